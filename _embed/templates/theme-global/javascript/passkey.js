@@ -0,0 +1,185 @@
+(function(){
+
+	// Converts an ArrayBuffer into an unpadded base64url string
+	var toBase64URL = function(buffer) {
+		var bytes = new Uint8Array(buffer);
+		var binary = "";
+
+		for (var index = 0; index < bytes.length; index++) {
+			binary += String.fromCharCode(bytes[index]);
+		}
+
+		return btoa(binary).replace(/\+/g, "-").replace(/\//g, "_").replace(/=+$/, "");
+	}
+
+	// Converts an unpadded base64url string into an ArrayBuffer
+	var fromBase64URL = function(value) {
+		var binary = atob(value.replace(/-/g, "+").replace(/_/g, "/"));
+		var bytes = new Uint8Array(binary.length);
+
+		for (var index = 0; index < binary.length; index++) {
+			bytes[index] = binary.charCodeAt(index);
+		}
+
+		return bytes.buffer;
+	}
+
+	var descriptors = function(list) {
+		return (list || []).map(function(descriptor) {
+			return Object.assign({}, descriptor, {id: fromBase64URL(descriptor.id)});
+		});
+	}
+
+	// Decodes the JSON options from the server, for browsers without parseCreationOptionsFromJSON()
+	var creationOptions = function(options) {
+
+		if (PublicKeyCredential.parseCreationOptionsFromJSON) {
+			return PublicKeyCredential.parseCreationOptionsFromJSON(options);
+		}
+
+		return Object.assign({}, options, {
+			challenge: fromBase64URL(options.challenge),
+			user: Object.assign({}, options.user, {id: fromBase64URL(options.user.id)}),
+			excludeCredentials: descriptors(options.excludeCredentials)
+		});
+	}
+
+	// Decodes the JSON options from the server, for browsers without parseRequestOptionsFromJSON()
+	var requestOptions = function(options) {
+
+		if (PublicKeyCredential.parseRequestOptionsFromJSON) {
+			return PublicKeyCredential.parseRequestOptionsFromJSON(options);
+		}
+
+		return Object.assign({}, options, {
+			challenge: fromBase64URL(options.challenge),
+			allowCredentials: descriptors(options.allowCredentials)
+		});
+	}
+
+	// Encodes a new or existing credential as JSON, for browsers without PublicKeyCredential.toJSON()
+	var credentialJSON = function(credential) {
+
+		if (credential.toJSON) {
+			return JSON.stringify(credential.toJSON());
+		}
+
+		var response = {
+			clientDataJSON: toBase64URL(credential.response.clientDataJSON)
+		};
+
+		if (credential.response.attestationObject) {
+			response.attestationObject = toBase64URL(credential.response.attestationObject);
+			response.transports = credential.response.getTransports ? credential.response.getTransports() : [];
+		}
+
+		if (credential.response.authenticatorData) {
+			response.authenticatorData = toBase64URL(credential.response.authenticatorData);
+			response.signature = toBase64URL(credential.response.signature);
+
+			if (credential.response.userHandle) {
+				response.userHandle = toBase64URL(credential.response.userHandle);
+			}
+		}
+
+		return JSON.stringify({
+			id: credential.id,
+			rawId: toBase64URL(credential.rawId),
+			type: credential.type,
+			response: response
+		});
+	}
+
+	// Returns a human-friendly message for errors raised by the browser
+	var errorMessage = function(error) {
+
+		if (error && error.name == "NotAllowedError") {
+			return "The passkey request was cancelled or timed out.";
+		}
+
+		if (error && error.message) {
+			return error.message;
+		}
+
+		return "Your passkey could not be used. Please try again.";
+	}
+
+	window.Passkey = {
+
+		// signin asks the browser for a passkey, then POSTs it to the server.  The server
+		// responds with the same HX-Trigger and HX-Redirect headers as the password form.
+		signin: function(source, optionsURL, postURL) {
+
+			if (!window.PublicKeyCredential) {
+				htmx.trigger(source, "SigninError", {value: "This browser does not support passkeys."});
+				return;
+			}
+
+			fetch(optionsURL, {credentials: "same-origin"})
+				.then(function(response) {
+					if (!response.ok) {
+						return response.text().then(function(message) { throw new Error(message); });
+					}
+					return response.json();
+				})
+				.then(function(options) {
+					return navigator.credentials.get({publicKey: requestOptions(options.publicKey)}).then(function(credential) {
+
+						var values = {
+							token: options.token,
+							credential: credentialJSON(credential)
+						};
+
+						var remember = document.querySelector("input[name=remember]");
+
+						if (remember && remember.checked) {
+							values.remember = "true";
+						}
+
+						return htmx.ajax("POST", postURL, {source: source, swap: "none", values: values});
+					});
+				})
+				.catch(function(error) {
+					htmx.trigger(source, "SigninError", {value: errorMessage(error)});
+				});
+		},
+
+		// register asks the browser to create a new passkey using the options in the
+		// #passkey-options script, then POSTs it to the URL in the button's data-url.
+		register: function(button) {
+
+			var message = document.getElementById("htmx-response-message");
+
+			var showError = function(error) {
+				var span = document.createElement("span");
+				span.className = "text-red";
+				span.textContent = errorMessage(error);
+				message.replaceChildren(span);
+			}
+
+			if (!window.PublicKeyCredential) {
+				showError(new Error("This browser does not support passkeys."));
+				return;
+			}
+
+			message.replaceChildren();
+
+			var options = JSON.parse(document.getElementById("passkey-options").textContent);
+
+			navigator.credentials.create({publicKey: creationOptions(options)})
+				.then(function(credential) {
+					return htmx.ajax("POST", button.dataset.url, {
+						source: button,
+						swap: "none",
+						values: {
+							token: button.dataset.token,
+							label: document.getElementById("idPasskeyLabel").value,
+							credential: credentialJSON(credential)
+						}
+					});
+				})
+				.catch(showError);
+		}
+	}
+
+})();
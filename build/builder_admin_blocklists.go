@@ -0,0 +1,187 @@
+package build
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/schema"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Blocklist is a builder for the admin/blocklists page
+// It can only be accessed by a Domain Owner
+type Blocklist struct {
+	_blocklist *model.Blocklist
+	CommonWithTemplate
+}
+
+// NewBlocklist returns a fully initialized `Blocklist` builder.
+func NewBlocklist(factory Factory, session data.Session, request *http.Request, response http.ResponseWriter, template model.Template, blocklist *model.Blocklist, actionID string) (Blocklist, error) {
+
+	const location = "build.NewBlocklist"
+
+	// Create the underlying Common builder
+	common, err := NewCommonWithTemplate(factory, session, request, response, template, blocklist, actionID)
+
+	if err != nil {
+		return Blocklist{}, derp.Wrap(err, location, "Creating common builder")
+	}
+
+	// Verify that the user is a Domain Owner
+	if !common._authorization.DomainOwner {
+		return Blocklist{}, derp.Forbidden(location, "Must be domain owner to continue")
+	}
+
+	// Return the Blocklist builder
+	return Blocklist{
+		_blocklist:         blocklist,
+		CommonWithTemplate: common,
+	}, nil
+}
+
+/******************************************
+ * Renderer Interface
+ ******************************************/
+
+// Render generates the string value for this Blocklist
+func (w Blocklist) Render() (template.HTML, error) {
+
+	var buffer bytes.Buffer
+
+	// Execute step (write HTML to buffer, update context)
+	status := Pipeline(w._action.Steps).Get(w._factory, &w, &buffer)
+
+	if status.Error != nil {
+		err := derp.Wrap(status.Error, "build.Blocklist.Render", "Generating HTML")
+		derp.Report(err)
+		return "", err
+	}
+
+	// Success!
+	status.Apply(w._response)
+	return template.HTML(buffer.String()), nil
+}
+
+// View executes a separate view for this Blocklist
+func (w Blocklist) View(actionID string) (template.HTML, error) {
+
+	builder, err := NewBlocklist(w._factory, w._session, w._request, w._response, w._template, w._blocklist, actionID)
+
+	if err != nil {
+		return template.HTML(""), derp.Wrap(err, "build.Blocklist.View", "Creating builder")
+	}
+
+	return builder.Render()
+}
+
+func (w Blocklist) NavigationID() string {
+	return "admin"
+}
+
+func (w Blocklist) Token() string {
+	return "blocklists"
+}
+
+func (w Blocklist) PageTitle() string {
+	return "Settings"
+}
+
+func (w Blocklist) Permalink() string {
+	return w.Host() + "/admin/blocklists/" + w.BlocklistID()
+}
+
+func (w Blocklist) BasePath() string {
+	return "/admin/blocklists/" + w.BlocklistID()
+}
+
+func (w Blocklist) object() data.Object {
+	return w._blocklist
+}
+
+func (w Blocklist) objectID() primitive.ObjectID {
+	return w._blocklist.BlocklistID
+}
+
+func (w Blocklist) objectType() string {
+	return "Blocklist"
+}
+
+func (w Blocklist) schema() schema.Schema {
+	return schema.New(model.BlocklistSchema())
+}
+
+func (w Blocklist) service() service.ModelService {
+	return w._factory.Blocklist()
+}
+
+func (w Blocklist) clone(action string) (Builder, error) {
+	return NewBlocklist(w._factory, w._session, w._request, w._response, w._template, w._blocklist, action)
+}
+
+/******************************************
+ * Blocklist Data
+ ******************************************/
+
+func (w Blocklist) BlocklistID() string {
+	if w._blocklist == nil {
+		return ""
+	}
+	return w._blocklist.BlocklistID.Hex()
+}
+
+func (w Blocklist) Blocklist() *model.Blocklist {
+	return w._blocklist
+}
+
+// RuleCount returns the number of server-wide Rules that the current Blocklist manages
+func (w Blocklist) RuleCount() int64 {
+
+	criteria := exp.Equal("userId", primitive.NilObjectID).
+		AndEqual("blocklistId", w._blocklist.BlocklistID)
+
+	result, err := w._factory.Rule().Count(w._session, criteria)
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, "build.Blocklist.RuleCount", "Counting Rules"))
+	}
+
+	return result
+}
+
+/******************************************
+ * Other Data Accessors
+ ******************************************/
+
+// IsAdminBuilder returns TRUE because Blocklist is an admin route.
+func (w Blocklist) IsAdminBuilder() bool {
+	return true
+}
+
+/******************************************
+ * Query Builders
+ ******************************************/
+
+// Blocklists returns every subscription, and every upload that is waiting to be applied
+func (w Blocklist) Blocklists() *QueryBuilder[model.Blocklist] {
+
+	criteria := exp.Equal("deleteDate", 0)
+
+	result := NewQueryBuilder[model.Blocklist](w._factory.Blocklist(), w._session, criteria)
+
+	return &result
+}
+
+/******************************************
+ * Debugging Methods
+ ******************************************/
+
+func (w Blocklist) debug() {
+	log.Debug().Interface("object", w.object()).Msg("builder_admin_blocklists")
+}
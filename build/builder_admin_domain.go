@@ -161,6 +161,14 @@ func (w Domain) MLSGroupIDs() sliceof.String {
 	return w._domain.MLSGroupIDs
 }
 
+func (w Domain) TwoFactorMode() string {
+	return w._domain.TwoFactorMode
+}
+
+func (w Domain) TwoFactorGroupIDs() sliceof.String {
+	return w._domain.TwoFactorGroupIDs
+}
+
 func (w Domain) Data(key string) string {
 	return w._domain.Data.GetString(key)
 }
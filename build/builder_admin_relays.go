@@ -0,0 +1,191 @@
+package build
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/schema"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Relay is a builder for the admin/relays page
+// It can only be accessed by a Domain Owner
+type Relay struct {
+	_relay *model.Relay
+	CommonWithTemplate
+}
+
+// NewRelay returns a fully initialized `Relay` builder.
+func NewRelay(factory Factory, session data.Session, request *http.Request, response http.ResponseWriter, template model.Template, relay *model.Relay, actionID string) (Relay, error) {
+
+	const location = "build.NewRelay"
+
+	// Create the underlying Common builder
+	common, err := NewCommonWithTemplate(factory, session, request, response, template, relay, actionID)
+
+	if err != nil {
+		return Relay{}, derp.Wrap(err, location, "Creating common builder")
+	}
+
+	// Verify that the user is a Domain Owner
+	if !common._authorization.DomainOwner {
+		return Relay{}, derp.Forbidden(location, "Must be domain owner to continue")
+	}
+
+	// Return the Relay builder
+	return Relay{
+		_relay:             relay,
+		CommonWithTemplate: common,
+	}, nil
+}
+
+/******************************************
+ * Renderer Interface
+ ******************************************/
+
+// Render generates the string value for this Relay
+func (w Relay) Render() (template.HTML, error) {
+
+	var buffer bytes.Buffer
+
+	// Execute step (write HTML to buffer, update context)
+	status := Pipeline(w._action.Steps).Get(w._factory, &w, &buffer)
+
+	if status.Error != nil {
+		err := derp.Wrap(status.Error, "build.Relay.Render", "Generating HTML")
+		derp.Report(err)
+		return "", err
+	}
+
+	// Success!
+	status.Apply(w._response)
+	return template.HTML(buffer.String()), nil
+}
+
+// View executes a separate view for this Relay
+func (w Relay) View(actionID string) (template.HTML, error) {
+
+	builder, err := NewRelay(w._factory, w._session, w._request, w._response, w._template, w._relay, actionID)
+
+	if err != nil {
+		return template.HTML(""), derp.Wrap(err, "build.Relay.View", "Creating builder")
+	}
+
+	return builder.Render()
+}
+
+func (w Relay) NavigationID() string {
+	return "admin"
+}
+
+func (w Relay) Token() string {
+	return "relays"
+}
+
+func (w Relay) PageTitle() string {
+	return "Settings"
+}
+
+func (w Relay) Permalink() string {
+	return w.Host() + "/admin/relays/" + w.RelayID()
+}
+
+func (w Relay) BasePath() string {
+	return "/admin/relays/" + w.RelayID()
+}
+
+func (w Relay) object() data.Object {
+	return w._relay
+}
+
+func (w Relay) objectID() primitive.ObjectID {
+	return w._relay.RelayID
+}
+
+func (w Relay) objectType() string {
+	return "Relay"
+}
+
+func (w Relay) schema() schema.Schema {
+	return schema.New(model.RelaySchema())
+}
+
+func (w Relay) service() service.ModelService {
+	return w._factory.Relay()
+}
+
+func (w Relay) clone(action string) (Builder, error) {
+	return NewRelay(w._factory, w._session, w._request, w._response, w._template, w._relay, action)
+}
+
+/******************************************
+ * Relay Data
+ ******************************************/
+
+func (w Relay) RelayID() string {
+	if w._relay == nil {
+		return ""
+	}
+	return w._relay.RelayID.Hex()
+}
+
+func (w Relay) Relay() *model.Relay {
+	return w._relay
+}
+
+// ResultCount returns the number of SearchResults that the provided Relay has forwarded
+func (w Relay) ResultCount(relayID primitive.ObjectID) int64 {
+
+	criteria := exp.Equal("relayId", relayID).AndEqual("local", false)
+
+	result, err := w._factory.SearchResult().Count(w._session, criteria)
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, "build.Relay.ResultCount", "Counting SearchResults"))
+	}
+
+	return result
+}
+
+// SearchActorURL returns the URL of the domain actor that subscribes to relays
+func (w Relay) SearchActorURL() string {
+	return w._factory.SearchDomain().ActivityPubURL()
+}
+
+/******************************************
+ * Other Data Accessors
+ ******************************************/
+
+// IsAdminBuilder returns TRUE because Relay is an admin route.
+func (w Relay) IsAdminBuilder() bool {
+	return true
+}
+
+/******************************************
+ * Query Builders
+ ******************************************/
+
+// Relays returns every Relay that this domain subscribes to
+func (w Relay) Relays() *QueryBuilder[model.Relay] {
+
+	criteria := exp.Equal("deleteDate", 0)
+
+	result := NewQueryBuilder[model.Relay](w._factory.Relay(), w._session, criteria)
+
+	return &result
+}
+
+/******************************************
+ * Debugging Methods
+ ******************************************/
+
+func (w Relay) debug() {
+	log.Debug().Interface("object", w.object()).Msg("builder_admin_relays")
+}
@@ -0,0 +1,225 @@
+package build
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/first"
+	"github.com/benpate/rosetta/schema"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Report is a builder for the admin/reports page (the moderation queue)
+// It can only be accessed by a Domain Owner
+type Report struct {
+	_report *model.Report
+	CommonWithTemplate
+}
+
+// NewReport returns a fully initialized `Report` builder.
+func NewReport(factory Factory, session data.Session, request *http.Request, response http.ResponseWriter, template model.Template, report *model.Report, actionID string) (Report, error) {
+
+	const location = "build.NewReport"
+
+	// Create the underlying Common builder
+	common, err := NewCommonWithTemplate(factory, session, request, response, template, report, actionID)
+
+	if err != nil {
+		return Report{}, derp.Wrap(err, location, "Creating common builder")
+	}
+
+	// Verify that the user is a Domain Owner
+	if !common._authorization.DomainOwner {
+		return Report{}, derp.Forbidden(location, "Must be domain owner to continue")
+	}
+
+	// Return the Report builder
+	return Report{
+		_report:            report,
+		CommonWithTemplate: common,
+	}, nil
+}
+
+/******************************************
+ * Renderer Interface
+ ******************************************/
+
+// Render generates the string value for this Report
+func (w Report) Render() (template.HTML, error) {
+
+	var buffer bytes.Buffer
+
+	// Execute step (write HTML to buffer, update context)
+	status := Pipeline(w._action.Steps).Get(w._factory, &w, &buffer)
+
+	if status.Error != nil {
+		err := derp.Wrap(status.Error, "build.Report.Render", "Generating HTML")
+		derp.Report(err)
+		return "", err
+	}
+
+	// Success!
+	status.Apply(w._response)
+	return template.HTML(buffer.String()), nil
+}
+
+// View executes a separate view for this Report
+func (w Report) View(actionID string) (template.HTML, error) {
+
+	builder, err := NewReport(w._factory, w._session, w._request, w._response, w._template, w._report, actionID)
+
+	if err != nil {
+		return template.HTML(""), derp.Wrap(err, "build.Report.View", "Creating builder")
+	}
+
+	return builder.Render()
+}
+
+func (w Report) NavigationID() string {
+	return "admin"
+}
+
+func (w Report) Token() string {
+	return "reports"
+}
+
+func (w Report) PageTitle() string {
+	return "Settings"
+}
+
+func (w Report) Permalink() string {
+	return w.Host() + "/admin/reports/" + w.ReportID()
+}
+
+func (w Report) BasePath() string {
+	return "/admin/reports/" + w.ReportID()
+}
+
+func (w Report) object() data.Object {
+	return w._report
+}
+
+func (w Report) objectID() primitive.ObjectID {
+	return w._report.ReportID
+}
+
+func (w Report) objectType() string {
+	return "Report"
+}
+
+func (w Report) schema() schema.Schema {
+	return schema.New(model.ReportSchema())
+}
+
+func (w Report) service() service.ModelService {
+	return w._factory.Report()
+}
+
+func (w Report) clone(action string) (Builder, error) {
+	return NewReport(w._factory, w._session, w._request, w._response, w._template, w._report, action)
+}
+
+/******************************************
+ * Report Data
+ ******************************************/
+
+func (w Report) ReportID() string {
+	if w._report == nil {
+		return ""
+	}
+	return w._report.ReportID.Hex()
+}
+
+func (w Report) Report() *model.Report {
+	return w._report
+}
+
+// Reporter returns the local User who filed this Report (if any)
+func (w Report) Reporter() (model.User, error) {
+
+	result := model.NewUser()
+
+	if w._report.ReporterID.IsZero() {
+		return result, nil
+	}
+
+	err := w._factory.User().LoadByID(w._session, w._report.ReporterID, &result)
+	return result, err
+}
+
+/******************************************
+ * Other Data Accessors
+ ******************************************/
+
+// IsAdminBuilder returns TRUE because Report is an admin route.
+func (w Report) IsAdminBuilder() bool {
+	return true
+}
+
+/******************************************
+ * Query Builders
+ ******************************************/
+
+// Reports returns the moderation queue, filtered by the "status" query parameter (OPEN by default)
+func (w Report) Reports() *QueryBuilder[model.Report] {
+
+	criteria := exp.Equal("status", w.StatusFilter()).
+		AndEqual("deleteDate", 0)
+
+	result := NewQueryBuilder[model.Report](w._factory.Report(), w._session, criteria)
+
+	return &result
+}
+
+// StatusFilter returns the Report status being displayed in the moderation queue
+func (w Report) StatusFilter() string {
+
+	switch status := w.QueryParam("status"); status {
+	case model.ReportStatusResolved, model.ReportStatusDismissed:
+		return status
+	}
+
+	return model.ReportStatusOpen
+}
+
+// OpenReportCount returns the number of Reports that are waiting for a moderator
+func (w Report) OpenReportCount() int64 {
+
+	result, err := w._factory.Report().CountOpen(w._session)
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, "build.Report.OpenReportCount", "Counting open Reports"))
+	}
+
+	return result
+}
+
+// ResolutionLabel returns a human-friendly description of the current Report's resolution
+func (w Report) ResolutionLabel() string {
+
+	switch w._report.Resolution {
+
+	case model.ReportResolutionBlockActor:
+		return "Blocked " + w._report.TargetURL
+
+	case model.ReportResolutionBlockDomain:
+		return "Blocked " + first.String(w._report.TargetHostname(), w._report.TargetURL)
+	}
+
+	return "No action taken"
+}
+
+/******************************************
+ * Debugging Methods
+ ******************************************/
+
+func (w Report) debug() {
+	log.Debug().Interface("object", w.object()).Msg("builder_admin_reports")
+}
@@ -177,6 +177,13 @@ func (w User) MapIDs() map[string]string {
 	return w._user.MapIDs
 }
 
+func (w User) IsTwoFactorActive() bool {
+	if w._user == nil {
+		return false
+	}
+	return w._user.TwoFactor.IsActive()
+}
+
 /******************************************
  * Query Builders
  ******************************************/
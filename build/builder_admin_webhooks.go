@@ -12,6 +12,7 @@ import (
 	"github.com/benpate/exp"
 	builder "github.com/benpate/exp-builder"
 	"github.com/benpate/rosetta/schema"
+	"github.com/benpate/rosetta/sliceof"
 	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -149,6 +150,10 @@ func (w Webhook) TargetURL() string {
 	return w._webhook.TargetURL
 }
 
+func (w Webhook) Secret() string {
+	return w._webhook.Secret
+}
+
 /******************************************
  * Other Data Accessors
  ******************************************/
@@ -179,6 +184,19 @@ func (w Webhook) Webhooks() *QueryBuilder[model.Webhook] {
 	return &result
 }
 
+// Deliveries returns the most recent entries in this Webhook's delivery log, newest first
+func (w Webhook) Deliveries() (sliceof.Object[model.WebhookDelivery], error) {
+	return w._factory.WebhookDelivery().QueryByWebhook(w._session, w._webhook.WebhookID, 60)
+}
+
+// Delivery returns the single delivery log entry identified by the "deliveryId" query parameter
+func (w Webhook) Delivery() (model.WebhookDelivery, error) {
+
+	result := model.NewWebhookDelivery()
+	err := w._factory.WebhookDelivery().LoadByToken(w._session, w._webhook.WebhookID, w.QueryParam("deliveryId"), &result)
+	return result, err
+}
+
 /******************************************
  * Debugging Methods
  ******************************************/
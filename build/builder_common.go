@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service"
 	"github.com/EmissarySocial/emissary/tools/ascache"
 	"github.com/EmissarySocial/emissary/tools/asrules"
 	"github.com/benpate/data"
@@ -28,13 +29,14 @@ import (
 
 // Common provides common building functions that are needed by ALL builders
 type Common struct {
-	_factory       Factory             // Factory interface is required for locating other services.
-	_session       data.Session        // Database session for all db requests
-	_request       *http.Request       // Pointer to the HTTP request we are serving
-	_response      http.ResponseWriter // ResponseWriter for this request
-	_authorization model.Authorization // Authorization information for the current website visitor
-	_user          *model.User         // User information for the current website User (if any)
-	_identity      *model.Identity     // Identity information for the current website visitor (if any)
+	_factory       Factory                  // Factory interface is required for locating other services.
+	_session       data.Session             // Database session for all db requests
+	_request       *http.Request            // Pointer to the HTTP request we are serving
+	_response      http.ResponseWriter      // ResponseWriter for this request
+	_authorization model.Authorization      // Authorization information for the current website visitor
+	_user          *model.User              // User information for the current website User (if any)
+	_identity      *model.Identity          // Identity information for the current website visitor (if any)
+	_groups        *service.GroupMembership // Group lookups cached for the duration of this request
 
 	arguments mapof.String // Temporary data scope for this request
 }
@@ -52,6 +54,7 @@ func NewCommon(factory Factory, session data.Session, request *http.Request, res
 		_request:       request,
 		_response:      response,
 		_authorization: authorization,
+		_groups:        service.NewGroupMembership(),
 		arguments:      make(mapof.String),
 	}
 }
@@ -564,7 +567,11 @@ func (w Common) IsFollower(url string) model.Follower {
 	followerService := w._factory.Follower()
 	follower := model.NewFollower()
 
-	_ = followerService.LoadByActor(w._session, w.AuthenticatedID(), url, &follower)
+	// Follow requests (and other inactive Followers) do not count
+	if err := followerService.LoadByActor(w._session, w.AuthenticatedID(), url, &follower); err != nil || !follower.IsActive() {
+		return model.NewFollower()
+	}
+
 	return follower
 }
 
@@ -90,7 +90,7 @@ func (builder CommonWithTemplate) AuthorInGroup(accessLister model.AccessLister,
 
 	// Use the Permission service to check if the user has the specified role
 	permissionService := builder._factory.Permission()
-	inGroup, err := permissionService.AuthorInGroup(accessLister, groupToken)
+	inGroup, err := permissionService.AuthorInGroup(builder._session, builder._groups, accessLister, groupToken)
 
 	if err != nil {
 		derp.Report(derp.Wrap(err, location, "Checking user roles"))
@@ -107,7 +107,7 @@ func (builder CommonWithTemplate) UserInGroup(groupToken string) bool {
 
 	// Use the Permission service to check if the user has the specified role
 	permissionService := builder._factory.Permission()
-	inGroup, err := permissionService.UserInGroup(&builder._authorization, groupToken)
+	inGroup, err := permissionService.UserInGroup(builder._session, builder._groups, &builder._authorization, groupToken)
 
 	if err != nil {
 		derp.Report(derp.Wrap(err, location, "Checking user roles"))
@@ -124,7 +124,7 @@ func (builder CommonWithTemplate) UserHasRole(role string) bool {
 
 	// Use the Permission service to check if the user has the specified role
 	permissionService := builder._factory.Permission()
-	hasRole, err := permissionService.UserHasRole(builder._session, &builder._authorization, builder._accessLister, role)
+	hasRole, err := permissionService.UserHasRole(builder._session, builder._groups, &builder._authorization, builder._accessLister, role)
 
 	if err != nil {
 		derp.Report(derp.Wrap(err, location, "Checking user roles"))
@@ -140,7 +140,7 @@ func (builder CommonWithTemplate) UserCan(actionID string) bool {
 	const location = "builder.CommonWithTemplate.UserCan"
 
 	permissionService := builder._factory.Permission()
-	result, err := permissionService.UserCan(builder._session, &builder._authorization, &builder._template, builder._accessLister, actionID)
+	result, err := permissionService.UserCan(builder._session, builder._groups, &builder._authorization, &builder._template, builder._accessLister, actionID)
 
 	if err != nil {
 		derp.Report(derp.Wrap(err, location, "Checking permissions"))
@@ -153,5 +153,5 @@ func (builder CommonWithTemplate) UserCan(actionID string) bool {
 // UserCan returns TRUE if this action is permitted on a stream (using the provided authorization)
 func (builder CommonWithTemplate) TraceUserCan(actionID string) []string {
 	permissionService := builder._factory.Permission()
-	return permissionService.TraceUserCan(builder._session, &builder._authorization, &builder._template, builder._accessLister, actionID)
+	return permissionService.TraceUserCan(builder._session, builder._groups, &builder._authorization, &builder._template, builder._accessLister, actionID)
 }
@@ -98,6 +98,12 @@ func (w Follower) StateID() string {
 }
 
 // Method returns the Method property of this Follow
+// IsRequested returns TRUE if this Follower is a follow request
+// that is waiting for the User to approve or reject it.
+func (w Follower) IsRequested() bool {
+	return w._follower.IsRequested()
+}
+
 func (w Follower) Method() string {
 	return w._follower.Method
 }
@@ -114,6 +114,9 @@ func (w Model) Label() string {
 	case *model.Follower:
 		return typed.Actor.Name
 
+	case *model.FollowedTag:
+		return "#" + typed.Name
+
 	case *model.Following:
 		return typed.Label
 
@@ -614,6 +614,7 @@ func (w Stream) RepliesAfter(dateString string, maxRows int) sliceof.Object[stre
 	}
 
 	w._factory.Rule().LabelDocuments(w._session, w.AuthenticatedID(), result)
+	w._factory.Rule().FilterDocuments(w._session, w.AuthenticatedID(), model.FilterContextThread, result)
 
 	return result
 }
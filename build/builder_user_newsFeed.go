@@ -564,6 +564,23 @@ func (w Inbox) Message() model.NewsItem {
 	return newsItem
 }
 
+// MessageDocument returns the ActivityStream document for a message in the inbox list, stamped
+// with the viewer's "home" Filters.  A filtered message renders as a placeholder in the list, and
+// opening it shows the full message, just like a message hidden by the viewer's rules.
+func (w Inbox) MessageDocument(url string) streams.Document {
+
+	result := w.ActivityStream(url)
+
+	if result.ID() == "" {
+		return result
+	}
+
+	documents := []streams.Document{result}
+	w._factory.Rule().FilterDocuments(w._session, w.AuthenticatedID(), model.FilterContextHome, documents)
+
+	return documents[0]
+}
+
 func (w Inbox) QueryByContext(contextID string, afterDate int64, maxRows int) (sliceof.Object[streams.Document], error) {
 
 	activityService := w._factory.ActivityStream()
@@ -574,7 +591,9 @@ func (w Inbox) QueryByContext(contextID string, afterDate int64, maxRows int) (s
 	}
 
 	// Stamp each document with the viewer's rule verdict (D2 placeholders + label chips)
+	// and with their "thread" Filters
 	w._factory.Rule().LabelDocuments(w._session, w.AuthenticatedID(), result)
+	w._factory.Rule().FilterDocuments(w._session, w.AuthenticatedID(), model.FilterContextThread, result)
 
 	return result, nil
 }
@@ -619,6 +638,7 @@ func (w Inbox) RepliesAfter(url string, dateString string, maxRows int) sliceof.
 	}
 
 	w._factory.Rule().LabelDocuments(w._session, w.AuthenticatedID(), result)
+	w._factory.Rule().FilterDocuments(w._session, w.AuthenticatedID(), model.FilterContextThread, result)
 
 	return result
 }
@@ -645,6 +665,42 @@ func (w Inbox) AmFollowing(url string) model.Following {
 	return following
 }
 
+// Poll returns the Poll (if any) that is published at the specified URL.
+// If the document is not a Poll, then an empty Poll is returned.
+func (w Inbox) Poll(url string) model.Poll {
+
+	// Null check
+	if w._user == nil {
+		return model.NewPoll()
+	}
+
+	poll, _, err := w._factory.PollVote().LoadPoll(w._session, w._user, url)
+
+	if err != nil {
+		return model.NewPoll()
+	}
+
+	return poll
+}
+
+// PollChoices returns the indexes of the options that the current user
+// selected in the Poll at the specified URL (nil if they have not voted).
+func (w Inbox) PollChoices(url string) []int {
+
+	// Null check
+	if w._user == nil {
+		return nil
+	}
+
+	_, choices, err := w._factory.PollVote().LoadPoll(w._session, w._user, url)
+
+	if err != nil {
+		return nil
+	}
+
+	return choices
+}
+
 // HasRule returns a rule that matches the current user, rule type, and trigger.
 // If no rule is found, then an empty rule is returned.
 func (w Inbox) HasRule(ruleType string, trigger string) model.Rule {
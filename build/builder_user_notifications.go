@@ -176,10 +176,12 @@ func (w Notifications) Notifications() (QueryBuilder[model.Notification], error)
 }
 
 // LabelNotifications stamps each Notification's transient Labels field with the viewer's rule
-// verdict for its snapshotted Actor, and returns the same slice for template chaining. Verdicts
-// are derived fresh on every render (R8), so a deleted rule stops labeling immediately.
+// verdict for its snapshotted Actor and with their "notifications" Filters, and returns the same
+// slice for template chaining. Verdicts are derived fresh on every render (R8), so a deleted rule
+// stops labeling immediately.
 func (w Notifications) LabelNotifications(notifications sliceof.Object[model.Notification]) sliceof.Object[model.Notification] {
 	w._factory.Rule().LabelNotifications(w._session, w.AuthenticatedID(), notifications)
+	w._factory.Rule().FilterNotifications(w._session, w.AuthenticatedID(), notifications)
 	return notifications
 }
 
@@ -206,8 +208,8 @@ func (w Notifications) UnreadNotificationCount(notificationType string) int64 {
 // notification types that section displays.  Grouped sections cast wide nets: MENTION includes
 // replies and direct messages (the classification ladder is DIRECT > REPLY > MENTION, so without
 // the expansion those would be invisible — there is no Replies section and no Messages section),
-// and LIKE includes dislikes (too rare for a section of their own).  ANNOUNCE passes through
-// unexpanded as the "Shares" section.  An empty section name returns nil, meaning "all types".
+// LIKE includes dislikes (too rare for a section of their own), and FOLLOW includes follow
+// requests.  ANNOUNCE passes through unexpanded as the "Shares" section.  An empty section name returns nil, meaning "all types".
 //
 // This helper (and notificationTypeFilter below) is package-level because both the Notifications
 // builder and the mark-notifications-read / with-notification steps expand `type` the same way.
@@ -223,6 +225,9 @@ func notificationTabTypes(notificationType string) []string {
 
 	case model.NotificationTypeLike:
 		return []string{model.NotificationTypeLike, model.NotificationTypeDislike}
+
+	case model.NotificationTypeFollow:
+		return []string{model.NotificationTypeFollow, model.NotificationTypeFollowRequest}
 	}
 
 	return []string{notificationType}
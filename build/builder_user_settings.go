@@ -230,16 +230,27 @@ func (w Settings) Followers() QueryBuilder[model.FollowerSummary] {
 		String("search", builder.WithAlias("actor.name"), builder.WithDefaultOpContains()).
 		String("name", builder.WithAlias("actor.name"))
 
-	// Calculate criteria
+	// Calculate criteria (follow requests are listed separately)
 	criteria := exp.And(
 		expressionBuilder.Evaluate(w._request.URL.Query()),
 		exp.Equal("parentId", w.AuthenticatedID()),
+		exp.NotEqual("stateId", model.FollowerStateRequested),
 	)
 
 	// Return the query builder
 	return NewQueryBuilder[model.FollowerSummary](w._factory.Follower(), w._session, criteria)
 }
 
+// FollowRequests returns a QueryBuilder for the follow requests that are waiting
+// for the User to approve or reject them.
+func (w Settings) FollowRequests() QueryBuilder[model.FollowerSummary] {
+
+	criteria := exp.Equal("parentId", w.AuthenticatedID()).
+		AndEqual("stateId", model.FollowerStateRequested)
+
+	return NewQueryBuilder[model.FollowerSummary](w._factory.Follower(), w._session, criteria)
+}
+
 func (w Settings) Following() QueryBuilder[model.FollowingSummary] {
 
 	expressionBuilder := builder.NewBuilder().
@@ -254,6 +265,11 @@ func (w Settings) Following() QueryBuilder[model.FollowingSummary] {
 	return NewQueryBuilder[model.FollowingSummary](w._factory.Following(), w._session, criteria)
 }
 
+// FollowedTags returns all of the #hashtags that the current user follows
+func (w Settings) FollowedTags() ([]model.FollowedTag, error) {
+	return w._factory.FollowedTag().QueryByUserID(w._session, w.AuthenticatedID())
+}
+
 func (w Settings) FollowingByFolder(token string) ([]model.FollowingSummary, error) {
 
 	// Get the UserID from the authentication scope
@@ -309,6 +325,26 @@ func (w Settings) KeyPackages() (sliceof.Object[model.KeyPackage], error) {
 	return w._factory.KeyPackage().QueryByUser(w._session, userID)
 }
 
+// TwoFactor returns the current user's second factor settings
+func (w Settings) TwoFactor() model.TwoFactor {
+	return w._user.TwoFactor
+}
+
+// TwoFactorRequired returns TRUE if this domain requires the current user to use a second factor
+func (w Settings) TwoFactorRequired() bool {
+	return w._factory.Domain().Get().UserRequiresTwoFactor(w._user)
+}
+
+// Passkeys returns all Passkeys for the current user, oldest first
+func (w Settings) Passkeys() ([]model.Passkey, error) {
+	return w._factory.Passkey().QueryByUserID(w._session, w._user.UserID)
+}
+
+// SigninHistory returns the most recent signin failures and second factor events for the current user
+func (w Settings) SigninHistory() ([]model.SigninAttempt, error) {
+	return w._factory.SterankoSigninService(w._session).QueryHistory(w._user.Username, 20)
+}
+
 // OAuthUserTokensForExports returns all OAuthUserTokens for the current user that have the ActivityPubPortability scope
 func (w Settings) OAuthUserTokensForExports() (sliceof.MapOfAny, error) {
 	userID := w.AuthenticatedID()
@@ -23,6 +23,7 @@ type Factory interface {
 	ActivityStream() *service.ActivityStream
 	Annotation() *service.Annotation
 	Attachment() *service.Attachment
+	Blocklist() *service.Blocklist
 	Circle() *service.Circle
 	Connection() *service.Connection
 	Collection() *service.Collection
@@ -31,6 +32,7 @@ type Factory interface {
 	Folder() *service.Folder
 	Following() *service.Following
 	Follower() *service.Follower
+	FollowedTag() *service.FollowedTag
 	GeocodeAddress() service.GeocodeAddress
 	GeocodeAutocomplete() service.GeocodeAutocomplete
 	GeocodeNetwork() service.GeocodeNetwork
@@ -46,12 +48,17 @@ type Factory interface {
 	Notification() *service.Notification
 	Outbox() *service.Outbox
 	Permission() *service.Permission
+	PollVote() *service.PollVote
 	Product() *service.Product
 	Provider() *service.Provider
+	Passkey() *service.Passkey
 	PushSubscription() *service.PushSubscription
 	Registration() *service.Registration
+	Relay() *service.Relay
+	Report() *service.Report
 	Response() *service.Response
 	Rule() *service.Rule
+	SearchDomain() *service.SearchDomain
 	SearchResult() *service.SearchResult
 	SearchTag() *service.SearchTag
 	Stream() *service.Stream
@@ -62,6 +69,7 @@ type Factory interface {
 	Theme() *service.Theme
 	User() *service.User
 	Webhook() *service.Webhook
+	WebhookDelivery() *service.WebhookDelivery
 	WebPush() *service.WebPush
 	Widget() *service.Widget
 
@@ -82,5 +90,6 @@ type Factory interface {
 	OAuthUserToken() *service.OAuthUserToken
 	Queue() *queue.Queue
 	Steranko(data.Session) *steranko.Steranko
+	SterankoSigninService(data.Session) service.SterankoSigninService
 	SSEUpdateChannel() chan realtime.Message
 }
@@ -209,6 +209,7 @@ func (builder SearchBuilder) Slice() (sliceof.Object[model.SearchResult], error)
 	// RULE: results hidden by the viewer's rules are dropped, not placeheld. A search listing is
 	// discovery, not thread structure, so a hole needs no explanation (R17 leaks nothing this way).
 	builder.ruleService.LabelSearchResults(builder.session, builder.userID, result)
+	builder.ruleService.FilterSearchResults(builder.session, builder.userID, result)
 	result = slice.Filter(result, func(searchResult model.SearchResult) bool {
 		return !searchResult.Labels.IsHidden()
 	})
@@ -233,6 +234,7 @@ func (builder SearchBuilder) Range() (iter.Seq[model.SearchResult], error) {
 
 			single := []model.SearchResult{searchResult}
 			builder.ruleService.LabelSearchResults(builder.session, builder.userID, single)
+			builder.ruleService.FilterSearchResults(builder.session, builder.userID, single)
 
 			if single[0].Labels.IsHidden() {
 				continue
@@ -29,6 +29,9 @@ func ExecutableStep(stepInfo step.Step) Step {
 	case step.AddStream:
 		return StepAddStream(s)
 
+	case step.ApplyBlocklist:
+		return StepApplyBlocklist(s)
+
 	case step.AsConfirmation:
 		return StepAsConfirmation(s)
 
@@ -71,6 +74,9 @@ func ExecutableStep(stepInfo step.Step) Step {
 	case step.EditWidget:
 		return StepEditWidget(s)
 
+	case step.FollowRequest:
+		return StepFollowRequest(s)
+
 	case step.ForwardTo:
 		return StepForwardTo(s)
 
@@ -104,6 +110,15 @@ func ExecutableStep(stepInfo step.Step) Step {
 	case step.MarkNotificationsRead:
 		return StepMarkNotificationsRead(s)
 
+	case step.Passkey:
+		return StepPasskey(s)
+
+	case step.PollVote:
+		return StepPollVote(s)
+
+	case step.PreviewBlocklist:
+		return StepPreviewBlocklist(s)
+
 	case step.ProcessContent:
 		return StepProcessContent(s)
 
@@ -122,6 +137,15 @@ func ExecutableStep(stepInfo step.Step) Step {
 	case step.RemoveEvent:
 		return StepRemoveEvent(s)
 
+	case step.ReplayWebhook:
+		return StepReplayWebhook(s)
+
+	case step.ResolveReport:
+		return StepResolveReport(s)
+
+	case step.RotateKey:
+		return StepRotateKey(s)
+
 	case step.RequirePassword:
 		return StepRequirePassword(s)
 
@@ -188,12 +212,18 @@ func ExecutableStep(stepInfo step.Step) Step {
 	case step.StreamPromoteDraft:
 		return StepStreamPromoteDraft(s)
 
+	case step.SubscribeRelay:
+		return StepSubscribeRelay(s)
+
 	case step.TableEditor:
 		return StepTableEditor(s)
 
 	case step.TriggerEvent:
 		return StepTriggerEvent(s)
 
+	case step.TwoFactor:
+		return StepTwoFactor(s)
+
 	case step.UnPublish:
 		return StepUnPublish(s)
 
@@ -236,6 +266,9 @@ func ExecutableStep(stepInfo step.Step) Step {
 	case step.WithFollower:
 		return StepWithFollower(s)
 
+	case step.WithFollowedTag:
+		return StepWithFollowedTag(s)
+
 	case step.WithFollowing:
 		return StepWithFollowing(s)
 
@@ -272,6 +305,9 @@ func ExecutableStep(stepInfo step.Step) Step {
 	case step.WithResponse:
 		return StepWithResponse(s)
 
+	case step.WithReport:
+		return StepWithReport(s)
+
 	case step.WithRule:
 		return StepWithRule(s)
 
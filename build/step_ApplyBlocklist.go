@@ -0,0 +1,37 @@
+package build
+
+import (
+	"io"
+
+	"github.com/benpate/derp"
+)
+
+// StepApplyBlocklist is a Step that applies every pending change in the current Blocklist.
+type StepApplyBlocklist struct{}
+
+func (step StepApplyBlocklist) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post applies the pending changes to the server-wide Rules.
+func (step StepApplyBlocklist) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepApplyBlocklist.Post"
+
+	blocklistBuilder, isBlocklistBuilder := builder.(Blocklist)
+
+	if !isBlocklistBuilder {
+		return Halt().WithError(derp.Internal(location, "StepApplyBlocklist can only be used in a Blocklist context"))
+	}
+
+	// RULE: Only Domain Owners can apply blocklists
+	if !blocklistBuilder.IsOwner() {
+		return Halt().WithError(derp.Forbidden(location, "Must be domain owner to apply blocklists"))
+	}
+
+	if err := builder.factory().Blocklist().Apply(builder.session(), blocklistBuilder._blocklist); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Applying Blocklist", blocklistBuilder._blocklist.BlocklistID))
+	}
+
+	return Continue()
+}
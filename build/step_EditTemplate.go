@@ -98,6 +98,15 @@ func (step StepEditTemplate) fieldLabel(value string) string {
 
 	case "outboxTemplate":
 		return "Outbox"
+
+	case "noteTemplate":
+		return "Notes from Client Apps"
+
+	case "articleTemplate":
+		return "Articles from Client Apps"
+
+	case "outboxFolderId":
+		return "Folder for Client Apps"
 	}
 
 	return ""
@@ -136,6 +145,21 @@ func (step StepEditTemplate) listTemplates(builder Builder, path string) []form.
 
 	case "outboxTemplate":
 		return builder.factory().Template().ListByTemplateRole("user-outbox")
+
+	// Posts from ActivityPub C2S clients are published into the User's outbox
+	case "noteTemplate", "articleTemplate":
+		return builder.factory().Template().ListByContainer("outbox")
+
+	// ...either at the top, or inside one of its folders.  Folders are Streams, not Templates,
+	// but they are chosen alongside the Templates that they will contain.
+	case "outboxFolderId":
+		folders, err := builder.factory().Stream().ListOutboxFolders(builder.session(), builder.objectID())
+
+		if err != nil {
+			derp.Report(derp.Wrap(err, "build.StepEditTemplate.listTemplates", "Listing outbox folders"))
+		}
+
+		return folders
 	}
 
 	return make([]form.LookupCode, 0)
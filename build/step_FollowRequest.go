@@ -0,0 +1,47 @@
+package build
+
+import (
+	"io"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+)
+
+// StepFollowRequest is a Step that approves or rejects a pending follow request
+type StepFollowRequest struct {
+	Accept bool
+}
+
+func (step StepFollowRequest) Get(builder Builder, buffer io.Writer) PipelineBehavior {
+	return nil
+}
+
+func (step StepFollowRequest) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepFollowRequest.Post"
+
+	// Require that we're working with a Follower
+	follower, ok := builder.object().(*model.Follower)
+
+	if !ok {
+		return Halt().WithError(derp.Internal(location, "step: FollowRequest can only be used on a Follower"))
+	}
+
+	followerService := builder.factory().Follower()
+
+	// Approve the request
+	if step.Accept {
+		if err := followerService.AcceptFollowRequest(builder.session(), follower); err != nil {
+			return Halt().WithError(derp.Wrap(err, location, "Accepting follow request"))
+		}
+
+		return Continue()
+	}
+
+	// Otherwise, reject the request
+	if err := followerService.RejectFollowRequest(builder.session(), follower); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Rejecting follow request"))
+	}
+
+	return Continue()
+}
@@ -0,0 +1,234 @@
+package build
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/EmissarySocial/emissary/tools/formdata"
+	"github.com/EmissarySocial/emissary/tools/webauthn"
+	"github.com/benpate/derp"
+	"github.com/benpate/html"
+)
+
+// StepPasskey is a Step that registers, renames, or revokes a User's passkeys.
+// Registration happens in two parts: "begin" displays a modal with the options for
+// navigator.credentials.create(), and "register" saves the credential that the browser returns.
+type StepPasskey struct {
+	Action string
+}
+
+// Get does nothing.
+func (step StepPasskey) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post registers, renames, or revokes a passkey
+func (step StepPasskey) Post(builder Builder, buffer io.Writer) PipelineBehavior {
+
+	const location = "build.StepPasskey.Post"
+
+	user, ok := builder.object().(*model.User)
+
+	if !ok {
+		return Halt().WithError(derp.Internal(location, "step: Passkey can only be used on a User"))
+	}
+
+	switch step.Action {
+
+	case "begin":
+		return step.begin(builder, buffer, user)
+
+	case "register":
+		return step.register(builder, user)
+
+	case "rename":
+		return step.rename(builder, user)
+
+	case "delete":
+		return step.delete(builder, user)
+	}
+
+	return Halt().WithError(derp.Internal(location, "Invalid action", step.Action))
+}
+
+// begin displays a modal that creates a new passkey in the User's browser
+func (step StepPasskey) begin(builder Builder, buffer io.Writer, user *model.User) PipelineBehavior {
+
+	const location = "build.StepPasskey.begin"
+
+	passkeyService := builder.factory().Passkey()
+	session := builder.session()
+
+	challenge, token, err := passkeyService.NewChallenge(service.PasskeyCeremonyRegister, user.UserID)
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Creating challenge"))
+	}
+
+	options, err := passkeyService.CreationOptions(session, user, challenge)
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Creating options"))
+	}
+
+	// json.Marshal escapes <, >, and & so the options are safe inside a <script> tag
+	optionsJSON, err := json.Marshal(options)
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Encoding options"))
+	}
+
+	// Modal Content
+	b := html.New()
+	b.H1().InnerText("Add a Passkey").Close()
+	b.Div().Class("margin-bottom").InnerText("Your browser will ask you to create a passkey using your fingerprint, face, screen lock, or security key.").Close()
+
+	b.Div().Class("layout-vertical")
+	b.Div().Class("layout-elements")
+	b.Div().Class("layout-element")
+	b.Label("idPasskeyLabel").InnerText("Name").Close()
+	b.Input("text", "label").ID("idPasskeyLabel").Attr("maxlength", "64").Attr("placeholder", "My Laptop").Close()
+	b.Div().Class("text-sm", "text-gray").InnerText("A name to help you recognize this passkey later.").Close()
+	b.Close()
+	b.Close()
+	b.Close()
+
+	b.WriteString(`<script type="application/json" id="passkey-options">` + string(optionsJSON) + `</script>`)
+
+	b.Button().Class("primary").
+		Data("token", token).
+		Data("url", "/@me/settings/passkey-register").
+		Script("on click call Passkey.register(me)").
+		InnerText("Create Passkey").
+		Close()
+
+	b.Button().Script("on click trigger closeModal").InnerText("Cancel").Close()
+	b.Span().ID("htmx-response-message").Class("margin-left").Close()
+	b.CloseAll()
+
+	modalHTML := WrapModal(builder.response(), b.String())
+
+	if _, err := io.WriteString(buffer, modalHTML); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Writing modal HTML to buffer"))
+	}
+
+	return Halt().AsFullPage()
+}
+
+// register saves a new passkey that the User's browser created in response to "begin"
+func (step StepPasskey) register(builder Builder, user *model.User) PipelineBehavior {
+
+	const location = "build.StepPasskey.register"
+
+	factory := builder.factory()
+	session := builder.session()
+	response := builder.response()
+	passkeyService := factory.Passkey()
+
+	transaction, err := formdata.Parse(builder.request())
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Parsing form data"))
+	}
+
+	// RULE: The challenge must have been issued to this User
+	userID, challenge, err := passkeyService.ParseChallenge(service.PasskeyCeremonyRegister, transaction.Get("token"))
+
+	if (err != nil) || (userID != user.UserID) {
+		return Halt().WithError(WrapInlineError(response, derp.Validation("This request has expired. Please try again.")))
+	}
+
+	registration, err := webauthn.ParseRegistration(json.RawMessage(transaction.Get("credential")))
+
+	if err != nil {
+		return Halt().WithError(WrapInlineError(response, derp.Wrap(err, location, "Invalid passkey response")))
+	}
+
+	label := strings.TrimSpace(transaction.Get("label"))
+
+	if _, err := passkeyService.Register(session, user, challenge, label, registration.ClientDataJSON, registration.AttestationObject, registration.Transports); err != nil {
+		return Halt().WithError(WrapInlineError(response, derp.Wrap(err, location, "Unable to add passkey")))
+	}
+
+	step.recordEvent(builder, user, model.SigninEventPasskeyAdd)
+	return Continue()
+}
+
+// rename updates the label of one of the User's passkeys.  The new label is
+// sent in the HX-Prompt header.
+func (step StepPasskey) rename(builder Builder, user *model.User) PipelineBehavior {
+
+	const location = "build.StepPasskey.rename"
+
+	passkeyService := builder.factory().Passkey()
+	session := builder.session()
+	passkey := model.NewPasskey()
+
+	if err := passkeyService.LoadByToken(session, user.UserID, builder.QueryParam("passkeyId"), &passkey); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Loading passkey"))
+	}
+
+	label := strings.TrimSpace(builder.request().Header.Get("HX-Prompt"))
+
+	// Empty labels (including a cancelled prompt) leave the passkey unchanged
+	if label == "" {
+		return Continue()
+	}
+
+	passkey.Label = label
+
+	if err := passkeyService.Save(session, &passkey, "Renamed"); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Saving passkey"))
+	}
+
+	return Continue()
+}
+
+// delete revokes one of the User's passkeys
+func (step StepPasskey) delete(builder Builder, user *model.User) PipelineBehavior {
+
+	const location = "build.StepPasskey.delete"
+
+	factory := builder.factory()
+	passkeyService := factory.Passkey()
+	session := builder.session()
+	passkey := model.NewPasskey()
+
+	if err := passkeyService.LoadByToken(session, user.UserID, builder.QueryParam("passkeyId"), &passkey); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Loading passkey"))
+	}
+
+	// RULE: Users cannot remove the last second factor that the domain requires
+	if factory.Domain().Get().UserRequiresTwoFactor(user) && !user.TwoFactor.IsActive() {
+
+		count, err := passkeyService.CountByUserID(session, user.UserID)
+
+		if err != nil {
+			return Halt().WithError(derp.Wrap(err, location, "Counting passkeys"))
+		}
+
+		if count <= 1 {
+			return Halt().WithError(WrapInlineError(builder.response(), derp.Validation("Two-factor authentication is required on this server. Add another passkey or an authenticator app first.")))
+		}
+	}
+
+	if err := passkeyService.Delete(session, &passkey, "Revoked by User"); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Deleting passkey"))
+	}
+
+	step.recordEvent(builder, user, model.SigninEventPasskeyRemove)
+	return Continue()
+}
+
+// recordEvent adds a change to the User's signin history
+func (step StepPasskey) recordEvent(builder Builder, user *model.User, event string) {
+
+	signinService := builder.factory().SterankoSigninService(builder.session())
+
+	if err := signinService.RecordEvent(builder.request(), user.Username, event); err != nil {
+		derp.Report(derp.Wrap(err, "build.StepPasskey.recordEvent", "Recording signin history", user.Username))
+	}
+}
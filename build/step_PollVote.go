@@ -0,0 +1,80 @@
+package build
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/tools/formdata"
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/convert"
+)
+
+// StepPollVote is a Step that casts the current user's vote in a (local or remote) Poll
+type StepPollVote struct{}
+
+func (step StepPollVote) Get(builder Builder, buffer io.Writer) PipelineBehavior {
+	return nil
+}
+
+func (step StepPollVote) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepPollVote.Post"
+
+	// Receive the transaction data
+	transaction := txnStepPollVote{}
+
+	if err := transaction.Bind(builder.request()); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Binding transaction"))
+	}
+
+	// Retrieve the currently authenticated user
+	user, err := builder.getUser()
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Getting user"))
+	}
+
+	// Cast the vote
+	pollVoteService := builder.factory().PollVote()
+
+	if _, _, err := pollVoteService.Vote(builder.session(), user, transaction.URL, transaction.Choices); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Voting in poll"))
+	}
+
+	// Carry on, carry onnnnn...
+	return Continue()
+}
+
+type txnStepPollVote struct {
+	URL     string // The URL of the Poll (Question) being voted on
+	Choices []int  // The indexes of the options being voted for
+}
+
+func (txn *txnStepPollVote) Bind(request *http.Request) error {
+
+	const location = "build.txnStepPollVote.Bind"
+
+	// Parse values from Form
+	values, err := formdata.Parse(request)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Parsing form values")
+	}
+
+	// Populate data
+	if url := values.Get("url"); url == "" {
+		return derp.Validation("The 'url' field cannot be empty.")
+	} else {
+		txn.URL = url
+	}
+
+	for _, choice := range values["choice"] {
+		txn.Choices = append(txn.Choices, convert.Int(choice))
+	}
+
+	if len(txn.Choices) == 0 {
+		return derp.Validation("Please select at least one choice.")
+	}
+
+	return nil
+}
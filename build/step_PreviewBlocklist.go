@@ -0,0 +1,38 @@
+package build
+
+import (
+	"io"
+
+	"github.com/benpate/derp"
+)
+
+// StepPreviewBlocklist is a Step that reads the latest copy of the current Blocklist, and stores
+// the changes it would make so that they can be reviewed.
+type StepPreviewBlocklist struct{}
+
+func (step StepPreviewBlocklist) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post downloads (or parses) the Blocklist and calculates its pending changes.
+func (step StepPreviewBlocklist) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepPreviewBlocklist.Post"
+
+	blocklistBuilder, isBlocklistBuilder := builder.(Blocklist)
+
+	if !isBlocklistBuilder {
+		return Halt().WithError(derp.Internal(location, "StepPreviewBlocklist can only be used in a Blocklist context"))
+	}
+
+	// RULE: Only Domain Owners can preview blocklists
+	if !blocklistBuilder.IsOwner() {
+		return Halt().WithError(derp.Forbidden(location, "Must be domain owner to preview blocklists"))
+	}
+
+	if err := builder.factory().Blocklist().Preview(builder.session(), blocklistBuilder._blocklist); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Previewing Blocklist", blocklistBuilder._blocklist.BlocklistID))
+	}
+
+	return Continue()
+}
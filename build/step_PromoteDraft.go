@@ -2,13 +2,16 @@ package build
 
 import (
 	"io"
+	"time"
 
+	"github.com/EmissarySocial/emissary/model"
 	"github.com/benpate/derp"
 )
 
 // StepStreamPromoteDraft is a Step that can copy the Container from a StreamDraft into its corresponding Stream
 type StepStreamPromoteDraft struct {
-	StateID string
+	StateID  string
+	Schedule string
 }
 
 func (step StepStreamPromoteDraft) Get(builder Builder, _ io.Writer) PipelineBehavior {
@@ -18,15 +21,39 @@ func (step StepStreamPromoteDraft) Get(builder Builder, _ io.Writer) PipelineBeh
 // Post copies relevant information from the draft into the primary stream, then deletes the draft
 func (step StepStreamPromoteDraft) Post(builder Builder, _ io.Writer) PipelineBehavior {
 
+	const location = "builder.StepStreamPromoteDraft.Post"
+
 	streamBuilder := builder.(Stream)
 
 	factory := builder.factory()
 
+	// "Publish later" is only possible for Streams that are not yet live
+	publishDate := requestedPublishDate(builder, step.Schedule)
+	publishLater := publishDate > time.Now().Unix()
+
+	if publishLater && streamBuilder._stream.IsPublished() {
+		return Halt().WithError(derp.Validation("Published streams cannot be scheduled"))
+	}
+
 	// Try to load the draft from the database, overwriting the stream already in the builder
 	stream, err := factory.StreamDraft().Promote(builder.session(), builder.objectID(), step.StateID)
 
 	if err != nil {
-		return Halt().WithError(derp.Wrap(err, "builder.StepStreamPromoteDraft.Post", "Publishing draft"))
+		return Halt().WithError(derp.Wrap(err, location, "Publishing draft"))
+	}
+
+	// Schedule the promoted Stream to publish at the requested date
+	if publishLater {
+
+		user := model.NewUser()
+
+		if err := factory.User().LoadByID(builder.session(), builder.AuthenticatedID(), &user); err != nil {
+			return Halt().WithError(derp.Wrap(err, location, "Loading user", builder.AuthenticatedID()))
+		}
+
+		if err := factory.Stream().SchedulePublish(builder.session(), &user, &stream, step.StateID, false, false, publishDate); err != nil {
+			return Halt().WithError(derp.Wrap(err, location, "Scheduling draft"))
+		}
 	}
 
 	// Push the newly updated stream back to the builder so that subsequent
@@ -0,0 +1,49 @@
+package build
+
+import (
+	"io"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+)
+
+// StepReplayWebhook is a Step that re-sends a logged WebhookDelivery (identified by the
+// "deliveryId" query parameter) to the current Webhook.
+type StepReplayWebhook struct{}
+
+func (step StepReplayWebhook) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post creates a new WebhookDelivery that replays the requested delivery.
+func (step StepReplayWebhook) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepReplayWebhook.Post"
+
+	webhookBuilder, isWebhookBuilder := builder.(Webhook)
+
+	if !isWebhookBuilder {
+		return Halt().WithError(derp.Internal(location, "StepReplayWebhook can only be used in a Webhook context"))
+	}
+
+	// RULE: Only Domain Owners can replay webhooks
+	if !webhookBuilder.IsOwner() {
+		return Halt().WithError(derp.Forbidden(location, "Must be domain owner to replay webhooks"))
+	}
+
+	// Load the original delivery (scoped to the current Webhook)
+	factory := builder.factory()
+	original := model.NewWebhookDelivery()
+	token := builder.QueryParam("deliveryId")
+
+	if err := factory.WebhookDelivery().LoadByToken(builder.session(), webhookBuilder._webhook.WebhookID, token, &original); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Loading WebhookDelivery", token))
+	}
+
+	// Queue a new delivery with the same payload
+	if _, err := factory.Webhook().Replay(builder.session(), &original); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Replaying WebhookDelivery", token))
+	}
+
+	return Continue()
+}
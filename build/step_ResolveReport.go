@@ -0,0 +1,40 @@
+package build
+
+import (
+	"io"
+
+	"github.com/benpate/derp"
+)
+
+// StepResolveReport is a Step that closes the current Report, using the
+// resolution (and note) that the moderator entered in a previous "edit" step.
+type StepResolveReport struct {
+	Status string
+}
+
+func (step StepResolveReport) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post resolves or dismisses the Report, creating any server-wide Rule that it requires.
+func (step StepResolveReport) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepResolveReport.Post"
+
+	reportBuilder, isReportBuilder := builder.(Report)
+
+	if !isReportBuilder {
+		return Halt().WithError(derp.Internal(location, "StepResolveReport can only be used in a Report context"))
+	}
+
+	// RULE: Only Domain Owners can resolve reports
+	if !reportBuilder.IsOwner() {
+		return Halt().WithError(derp.Forbidden(location, "Must be domain owner to resolve reports"))
+	}
+
+	if err := builder.factory().Report().Resolve(builder.session(), reportBuilder._report, step.Status, builder.AuthenticatedID()); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Resolving Report", reportBuilder._report.ReportID))
+	}
+
+	return Continue()
+}
@@ -0,0 +1,39 @@
+package build
+
+import (
+	"io"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+)
+
+// StepRotateKey is a Step that replaces a User's ActivityPub signing key with a newly
+// generated key, and federates the new public key via an Update of the User's actor.
+type StepRotateKey struct{}
+
+func (step StepRotateKey) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post rotates the User's signing key
+func (step StepRotateKey) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepRotateKey.Post"
+
+	user, ok := builder.object().(*model.User)
+
+	if !ok {
+		return Halt().WithError(derp.Internal(location, "step: RotateKey can only be used on a User"))
+	}
+
+	// RULE: Only Domain Owners can rotate keys
+	if !builder.IsOwner() {
+		return Halt().WithError(derp.Forbidden(location, "Must be domain owner to rotate keys"))
+	}
+
+	if err := builder.factory().User().RotateKey(builder.session(), user); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Rotating encryption key", user.UserID))
+	}
+
+	return Continue()
+}
@@ -12,6 +12,7 @@ type StepSaveAndPublish struct {
 	StateID   string
 	Outbox    bool
 	Republish bool
+	Schedule  string
 }
 
 func (step StepSaveAndPublish) Get(builder Builder, _ io.Writer) PipelineBehavior {
@@ -49,23 +50,26 @@ func (step StepSaveAndPublish) Post(builder Builder, _ io.Writer) PipelineBehavi
 		return Halt().WithError(derp.Wrap(err, location, "Loading user", streamBuilder.AuthenticatedID()))
 	}
 
-	// Additional rules if this Stream is headed for the user's outbox...
-	if step.Outbox {
-		// Guarantee this Stream has a context collection.
-		if err := streamService.CalcContext(session, stream); err != nil {
-			return Halt().WithError(derp.Wrap(err, location, "Calculating context for stream", stream))
+	// "Publish later" when the request includes a publish date
+	if publishDate := requestedPublishDate(builder, step.Schedule); publishDate > 0 {
+		if err := streamService.SchedulePublish(session, &user, stream, step.StateID, step.Outbox, step.Republish, publishDate); err != nil {
+			return Halt().WithError(derp.Wrap(err, location, "Scheduling Stream", stream))
 		}
 
-		// If this Stream is a reply, record it in the local parent's Replies collection.
-		if err := streamService.AddReply(session, stream.InReplyTo, stream.ActivityPubURL()); err != nil {
-			return Halt().WithError(derp.Wrap(err, location, "Adding reply to parent's collection", stream))
-		}
+		return nil
 	}
 
-	// Try to Publish the Stream to ActivityPub
+	// Streams headed for the user's outbox get a context collection and reply links, too.
+	if step.Outbox {
+		if err := streamService.PublishOutboxPost(session, &user, stream, step.StateID, step.Republish); err != nil {
+			return Halt().WithError(derp.Wrap(err, location, "Publishing Stream to outbox", stream))
+		}
+
+		return nil
+	}
 
-	// Publish the Stream to the ActivityPub Outbox
-	if err := streamService.Publish(session, &user, stream, step.StateID, step.Outbox, step.Republish); err != nil {
+	// Publish the Stream without sending it to the ActivityPub Outbox
+	if err := streamService.Publish(session, &user, stream, step.StateID, false, step.Republish); err != nil {
 		return Halt().WithError(derp.Wrap(err, location, "Publishing Stream", stream))
 	}
 
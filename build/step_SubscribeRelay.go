@@ -0,0 +1,37 @@
+package build
+
+import (
+	"io"
+
+	"github.com/benpate/derp"
+)
+
+// StepSubscribeRelay is a Step that sends a Follow from the domain's search actor to the current Relay
+type StepSubscribeRelay struct{}
+
+func (step StepSubscribeRelay) Get(builder Builder, _ io.Writer) PipelineBehavior {
+	return nil
+}
+
+// Post resolves the Relay's address, and sends it a Follow
+func (step StepSubscribeRelay) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepSubscribeRelay.Post"
+
+	relayBuilder, isRelayBuilder := builder.(Relay)
+
+	if !isRelayBuilder {
+		return Halt().WithError(derp.Internal(location, "StepSubscribeRelay can only be used in a Relay context"))
+	}
+
+	// RULE: Only Domain Owners can subscribe to relays
+	if !relayBuilder.IsOwner() {
+		return Halt().WithError(derp.Forbidden(location, "Must be domain owner to subscribe to relays"))
+	}
+
+	if err := builder.factory().Relay().Subscribe(builder.session(), relayBuilder._relay); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Subscribing to Relay", relayBuilder._relay.RelayID))
+	}
+
+	return Continue()
+}
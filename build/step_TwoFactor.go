@@ -0,0 +1,125 @@
+package build
+
+import (
+	"io"
+	"strings"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/formdata"
+	"github.com/benpate/derp"
+)
+
+// StepTwoFactor is a Step that enrolls, resets, or removes a User's second factor.
+// New recovery codes are stored in the "recoveryCodes" argument (one per line) so that
+// the following steps can display them exactly once.
+type StepTwoFactor struct {
+	Action string
+}
+
+// Get begins a new enrollment, so that the enrollment form can display a QR code
+func (step StepTwoFactor) Get(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepTwoFactor.Get"
+
+	if step.Action != "enroll" {
+		return nil
+	}
+
+	user, ok := builder.object().(*model.User)
+
+	if !ok {
+		return Halt().WithError(derp.Internal(location, "step: TwoFactor can only be used on a User"))
+	}
+
+	// RULE: Users cannot enroll twice
+	if user.TwoFactor.IsActive() {
+		return Halt().WithError(derp.BadRequest(location, "Two-factor authentication is already active"))
+	}
+
+	if err := builder.factory().User().BeginTwoFactor(builder.session(), user); err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Beginning two-factor enrollment"))
+	}
+
+	return nil
+}
+
+// Post confirms an enrollment, replaces the recovery codes, or removes the second factor
+func (step StepTwoFactor) Post(builder Builder, _ io.Writer) PipelineBehavior {
+
+	const location = "build.StepTwoFactor.Post"
+
+	user, ok := builder.object().(*model.User)
+
+	if !ok {
+		return Halt().WithError(derp.Internal(location, "step: TwoFactor can only be used on a User"))
+	}
+
+	factory := builder.factory()
+	session := builder.session()
+	response := builder.response()
+	userService := factory.User()
+
+	var event string
+
+	switch step.Action {
+
+	case "enroll":
+
+		transaction, err := formdata.Parse(builder.request())
+
+		if err != nil {
+			return Halt().WithError(derp.Wrap(err, location, "Parsing form data"))
+		}
+
+		recoveryCodes, err := userService.EnrollTwoFactor(session, user, transaction.Get("code"))
+
+		if err != nil {
+			return Halt().WithError(WrapInlineError(response, err))
+		}
+
+		builder.setString("recoveryCodes", strings.Join(recoveryCodes, "\n"))
+		event = model.SigninEventTwoFactorEnroll
+
+	case "recovery-codes":
+
+		recoveryCodes, err := userService.ResetRecoveryCodes(session, user)
+
+		if err != nil {
+			return Halt().WithError(WrapInlineError(response, err))
+		}
+
+		builder.setString("recoveryCodes", strings.Join(recoveryCodes, "\n"))
+		event = model.SigninEventRecoveryCodeReset
+
+	case "reset":
+
+		// RULE: Users cannot remove a second factor that the domain requires, unless they
+		// have a passkey to use instead.  Domain owners can still reset another User's second
+		// factor (e.g. after a lost device), and that User will enroll again the next time they sign in.
+		if builder.authorization().UserID == user.UserID && factory.Domain().Get().UserRequiresTwoFactor(user) {
+
+			passkeyCount, err := factory.Passkey().CountByUserID(session, user.UserID)
+
+			if err != nil {
+				return Halt().WithError(derp.Wrap(err, location, "Counting passkeys"))
+			}
+
+			if passkeyCount == 0 {
+				return Halt().WithError(WrapInlineError(response, derp.Validation("Two-factor authentication is required on this server")))
+			}
+		}
+
+		if err := userService.RemoveTwoFactor(session, user); err != nil {
+			return Halt().WithError(derp.Wrap(err, location, "Removing two-factor authentication"))
+		}
+
+		event = model.SigninEventTwoFactorReset
+	}
+
+	// Record the change in the User's signin history
+	if err := factory.SterankoSigninService(session).RecordEvent(builder.request(), user.Username, event); err != nil {
+		derp.Report(derp.Wrap(err, location, "Recording signin history", user.Username))
+	}
+
+	return Continue()
+}
@@ -0,0 +1,69 @@
+package build
+
+import (
+	"io"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/model/step"
+	"github.com/benpate/derp"
+)
+
+// StepWithFollowedTag is a Step that runs its sub-steps on one of the User's FollowedTags
+type StepWithFollowedTag struct {
+	SubSteps []step.Step
+}
+
+func (step StepWithFollowedTag) Get(builder Builder, buffer io.Writer) PipelineBehavior {
+	return step.execute(builder, buffer, ActionMethodGet)
+}
+
+// Post updates the stream with approved data from the request body.
+func (step StepWithFollowedTag) Post(builder Builder, buffer io.Writer) PipelineBehavior {
+	return step.execute(builder, buffer, ActionMethodPost)
+}
+
+func (step StepWithFollowedTag) execute(builder Builder, buffer io.Writer, actionMethod ActionMethod) PipelineBehavior {
+
+	const location = "build.StepWithFollowedTag.execute"
+
+	// RULE: User MUST be authenticated to use this step
+	if !builder.IsAuthenticated() {
+		return Halt().WithError(derp.Unauthorized(location, "Anonymous user is not authorized to perform this action"))
+	}
+
+	// Try to find the Template for this builder.
+	// This *should* work for all builders that use CommonWithTemplate
+	template, exists := getTemplate(builder)
+
+	if !exists {
+		return Halt().WithError(derp.Internal(location, "This step cannot be used in this Renderer."))
+	}
+
+	// Collect required services and values
+	factory := builder.factory()
+	followedTag := model.NewFollowedTag()
+	followedTag.UserID = builder.AuthenticatedID()
+
+	// If we have a real ID, then try to load the FollowedTag from the database
+	if token := builder.QueryParam("followedTagId"); notNewOrEmpty(token) {
+		if err := factory.FollowedTag().LoadByToken(builder.session(), builder.AuthenticatedID(), token, &followedTag); err != nil {
+			if actionMethod == ActionMethodGet {
+				return Halt().WithError(derp.Wrap(err, location, "Loading FollowedTag", token))
+			}
+			// Fall through for POSTS..  we're just creating a new FollowedTag.
+		}
+	}
+
+	// Create a new builder tied to the FollowedTag record
+	subBuilder, err := NewModel(factory, builder.session(), builder.request(), builder.response(), template, &followedTag, builder.actionID())
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Creating sub-builder"))
+	}
+
+	// Execute the POST build pipeline on the child
+	result := Pipeline(step.SubSteps).Execute(factory, subBuilder, buffer, actionMethod)
+	result.Error = derp.WrapIF(result.Error, location, "Executing steps for child")
+
+	return UseResult(result)
+}
@@ -0,0 +1,70 @@
+package build
+
+import (
+	"io"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/model/step"
+	"github.com/benpate/derp"
+)
+
+// StepWithReport is a Step that lets the signed-in User file a new moderation Report
+// about the actor in the "url" query parameter (and, optionally, the document in the "object" parameter)
+type StepWithReport struct {
+	SubSteps []step.Step
+}
+
+func (step StepWithReport) Get(builder Builder, buffer io.Writer) PipelineBehavior {
+	return step.execute(builder, buffer, ActionMethodGet)
+}
+
+// Post files the Report with data from the request body.
+func (step StepWithReport) Post(builder Builder, buffer io.Writer) PipelineBehavior {
+	return step.execute(builder, buffer, ActionMethodPost)
+}
+
+func (step StepWithReport) execute(builder Builder, buffer io.Writer, actionMethod ActionMethod) PipelineBehavior {
+
+	const location = "build.StepWithReport.execute"
+
+	if !builder.IsAuthenticated() {
+		return Halt().WithError(derp.Unauthorized(location, "Anonymous user is not authorized to perform this action"))
+	}
+
+	// Try to find the Template for this builder.
+	// This *should* work for all builders that use CommonWithTemplate
+	template, exists := getTemplate(builder)
+
+	if !exists {
+		return Halt().WithError(derp.Internal(location, "This step cannot be used in this Renderer."))
+	}
+
+	targetURL := builder.QueryParam("url")
+
+	if targetURL == "" {
+		return Halt().WithError(derp.BadRequest(location, "Actor URL is required"))
+	}
+
+	// Reports are always new.  Users cannot view or edit Reports once they are filed.
+	report := model.NewReport()
+	report.ReporterID = builder.AuthenticatedID()
+	report.TargetURL = targetURL
+
+	if objectURL := builder.QueryParam("object"); objectURL != "" {
+		report.ObjectURLs = append(report.ObjectURLs, objectURL)
+	}
+
+	// Create a new builder tied to the Report record
+	factory := builder.factory()
+	subBuilder, err := NewModel(factory, builder.session(), builder.request(), builder.response(), template, &report, builder.actionID())
+
+	if err != nil {
+		return Halt().WithError(derp.Wrap(err, location, "Creating sub-builder"))
+	}
+
+	// Execute the build pipeline on the child
+	result := Pipeline(step.SubSteps).Execute(factory, subBuilder, buffer, actionMethod)
+	result.Error = derp.WrapIF(result.Error, location, "Executing steps for child")
+
+	return UseResult(result)
+}
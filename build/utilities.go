@@ -463,3 +463,14 @@ func oEmbedURL(host string, permalink string, format string) string {
 	// concatenation truncates the "url" parameter at the permalink's first "&" or "#".
 	return host + "/.oembed?url=" + url.QueryEscape(permalink) + "&format=" + format
 }
+
+// requestedPublishDate returns the "publish later" date from the named form field,
+// or zero if the field is not configured or empty.
+func requestedPublishDate(builder Builder, field string) int64 {
+
+	if field == "" {
+		return 0
+	}
+
+	return model.ParsePublishDate(builder.request().FormValue(field))
+}
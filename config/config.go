@@ -41,6 +41,7 @@ type Config struct {
 	ClientIPHeader       string                       `json:"clientIpHeader"`       // When using the "SINGLE-IP-HEADER" strategy, header to inspect to determine the client's IP address.
 	TrustForwardedHost   bool                         `json:"trustForwardedHost"`   // If true, then the server will trust the hostname provided by the client.  This is useful when running behind a reverse proxy that does not provide the original hostname.
 	AllowPrivateIPs      bool                         `json:"allowPrivateIPs"`      // If true, then outbound ActivityPub delivery may connect to non-public (private/loopback) addresses. Leave FALSE in production; enable only for local/dev federation between machines on a private network.
+	RealtimeTransport    string                       `json:"realtimeTransport"`    // Method used to share realtime updates between server nodes (AUTO, CHANGE-STREAM, CAPPED-COLLECTION, MEMORY)
 }
 
 // NewConfig returns a fully initialized (but empty) Config data structure.
@@ -80,6 +81,7 @@ func DefaultConfig() Config {
 		HTTPSPort:           443,
 		MasterKey:           hex.EncodeToString(masterKey),
 		ClientIPStrategy:    "REMOTE-ADDR",
+		RealtimeTransport:   "AUTO",
 	}
 }
 
@@ -29,6 +29,7 @@ func Schema() schema.Schema {
 				"clientIPHeader":       schema.String{Default: "X-Real-IP"},
 				"trustForwardedHost":   schema.Boolean{},
 				"allowPrivateIPs":      schema.Boolean{},
+				"realtimeTransport":    schema.String{Enum: []string{"AUTO", "CHANGE-STREAM", "CAPPED-COLLECTION", "MEMORY"}, Default: "AUTO"},
 			},
 		},
 	}
@@ -89,6 +90,9 @@ func (config *Config) GetPointer(name string) (any, bool) {
 
 	case "allowPrivateIPs":
 		return &config.AllowPrivateIPs, true
+
+	case "realtimeTransport":
+		return &config.RealtimeTransport, true
 	}
 
 	return nil, false
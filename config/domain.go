@@ -19,6 +19,10 @@ type Domain struct {
 	Owner          Owner          `json:"owner"          bson:"owner"`         // Information about the owner of this domain
 	MasterKey      string         `json:"masterKey"      bson:"masterKey"`     // Key used to encrypt/decrypt JWT keys stored in the database
 	CreateOwner    bool           `json:"createOwner"    bson:"createOwner"`   // TRUE if the owner should be created when the domain is created
+
+	// PendingMasterKey holds a new master key while a rotation is in progress.  If a rotation
+	// is interrupted, then the database may be encrypted with this key instead of MasterKey.
+	PendingMasterKey string `json:"pendingMasterKey,omitempty" bson:"pendingMasterKey,omitempty"`
 }
 
 // NewDomain returns a fully initialized Domain object.
@@ -0,0 +1,45 @@
+package consumer
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/turbine/queue"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ClosePoll is a scheduled job that ends voting in a Stream's Poll once its end date has passed.
+func ClosePoll(factory *service.Factory, session data.Session, args mapof.Any) queue.Result {
+
+	const location = "consumer.ClosePoll"
+
+	// Locate the StreamID parameter
+	token := args.GetString("streamId")
+	streamID, err := primitive.ObjectIDFromHex(token)
+
+	if err != nil {
+		return queue.Failure(derp.Wrap(err, location, "Invalid StreamID", token))
+	}
+
+	// Try to load the Stream from the database
+	stream := model.NewStream()
+
+	if err := factory.Stream().LoadByID(session, streamID, &stream); err != nil {
+
+		// Deleted Streams have nothing left to close
+		if derp.IsNotFound(err) {
+			return queue.Success()
+		}
+
+		return queue.Error(derp.Wrap(err, location, "Loading stream", token))
+	}
+
+	// Close the Poll and publish the final results
+	if err := factory.PollVote().ClosePoll(session, &stream); err != nil {
+		return queue.Error(derp.Wrap(err, location, "Closing poll", stream.StreamID))
+	}
+
+	return queue.Success()
+}
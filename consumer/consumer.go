@@ -26,6 +26,9 @@ func (consumer Consumer) Run(name string, args map[string]any) queue.Result {
 	case "AddToCollection":
 		return WithSession(consumer.serverFactory, args, AddToCollection)
 
+	case "ClosePoll":
+		return WithSession(consumer.serverFactory, args, ClosePoll)
+
 	case "ConnectPushService":
 		return WithFollowing(consumer.serverFactory, args, ConnectPushService)
 
@@ -63,7 +66,7 @@ func (consumer Consumer) Run(name string, args map[string]any) queue.Result {
 		return WithSender(consumer.serverFactory, args, SendToAllRecipients)
 
 	case sender.OutboxSendToSingleRecipient:
-		return WithSender(consumer.serverFactory, args, SendToSingleRecipient)
+		return WithSession(consumer.serverFactory, args, SendToSingleRecipient)
 
 	case "Outbox-Publish":
 		return WithSession(consumer.serverFactory, args, OutboxPublish)
@@ -74,6 +77,15 @@ func (consumer Consumer) Run(name string, args map[string]any) queue.Result {
 	case "PollFollowing-Record":
 		return WithFollowing(consumer.serverFactory, args, PollFollowing_Record)
 
+	case "ProcessMedia":
+		return WithSession(consumer.serverFactory, args, ProcessMedia)
+
+	case "PublishScheduledStream":
+		return WithSession(consumer.serverFactory, args, PublishScheduledStream)
+
+	case "PublishScheduledStreams":
+		return WithSession(consumer.serverFactory, args, PublishScheduledStreams)
+
 	case "PublishRealtimeMessage":
 		return WithFactory(consumer.serverFactory, args, PublishRealtimeMessage)
 
@@ -92,6 +104,9 @@ func (consumer Consumer) Run(name string, args map[string]any) queue.Result {
 	case "PurgeNotifications":
 		return WithSession(consumer.serverFactory, args, PurgeNotifications)
 
+	case "PurgeWebhookDeliveries":
+		return WithSession(consumer.serverFactory, args, PurgeWebhookDeliveries)
+
 	case "Rule-Cleanup":
 		return WithSession(consumer.serverFactory, args, RuleCleanup)
 
@@ -125,15 +140,24 @@ func (consumer Consumer) Run(name string, args map[string]any) queue.Result {
 	case "SendSearchResult":
 		return WithSession(consumer.serverFactory, args, SendSearchResult)
 
+	case "SendSearchResult-FollowedTags":
+		return WithSession(consumer.serverFactory, args, SendSearchResult_FollowedTags)
+
 	case "SendSearchResult-SearchQuery":
 		return WithSession(consumer.serverFactory, args, SendSearchResult_SearchQuery)
 
+	case "SendWebhook":
+		return WithSession(consumer.serverFactory, args, SendWebhook)
+
 	case "SendWebPushNotification":
 		return WithSession(consumer.serverFactory, args, SendWebPushNotification)
 
 	case "Shuffle":
 		return WithSession(consumer.serverFactory, args, Shuffle)
 
+	case "SyncBlocklists":
+		return WithSession(consumer.serverFactory, args, SyncBlocklists)
+
 	case "syndication.create", "syndication.update", "syndication.delete":
 		return StreamSyndicate(name, args)
 	}
@@ -60,8 +60,8 @@ func MoveUser(factory *service.Factory, session data.Session, user *model.User,
 	 * 3. Send a `Move` to all Followers
 	 ******************************************/
 
-	// Send `Move` message to all followers
-	followers := factory.Follower().RangeByUserID(session, user.UserID)
+	// Send `Move` message to all active followers
+	followers := factory.Follower().RangeActiveByUserID(session, user.UserID)
 
 	// NOTE: this previously called queue.NewTask (the turbine CONSTRUCTOR) and discarded
 	// the result, so follower `Move` messages were never actually published.  Fixed as
@@ -91,6 +91,21 @@ func MoveUser(factory *service.Factory, session data.Session, user *model.User,
 		return queue.Error(derp.Wrap(err, location, "Deleting related Annotations"))
 	}
 
+	// Delete related Bookmarks
+	if err := factory.Bookmark().DeleteByUserID(session, user.UserID, "moved"); err != nil {
+		return queue.Error(derp.Wrap(err, location, "Deleting related Bookmarks"))
+	}
+
+	// Delete related Filters
+	if err := factory.Filter().DeleteByUserID(session, user.UserID, "moved"); err != nil {
+		return queue.Error(derp.Wrap(err, location, "Deleting related Filters"))
+	}
+
+	// Delete related PollVotes
+	if err := factory.PollVote().DeleteByUserID(session, user.UserID, "moved"); err != nil {
+		return queue.Error(derp.Wrap(err, location, "Deleting related PollVotes"))
+	}
+
 	// Delete related Outbox Messages
 	if err := factory.Outbox().DeleteByParentID(session, model.ActorTypeUser, user.UserID); err != nil {
 		return queue.Error(derp.Wrap(err, location, "Deleting related Outbox messages"))
@@ -35,6 +35,9 @@ func PreProcessor(task *queue.Task) error {
 	case "SendSearchResult":
 		task.Priority = 16
 
+	case "SendSearchResult-FollowedTags":
+		task.Priority = 16
+
 	case "SendSearchResult-SearchQuery":
 		task.Priority = 16
 
@@ -45,6 +48,8 @@ func PreProcessor(task *queue.Task) error {
 		task.Priority = 16
 
 	// (32) User-Affecting Tasks That Should Complete Very Quickly
+	case "ProcessMedia":
+		task.Priority = 32
 
 	///////////////////////////////////////////////////
 	// Tasks below this line are ALWAYS written to the
@@ -57,6 +62,9 @@ func PreProcessor(task *queue.Task) error {
 	case "ImportItem":
 		task.Priority = 64
 
+	case "PublishScheduledStream":
+		task.Priority = 64
+
 	case "ReceiveActivityPub-Add":
 		task.Priority = 64
 
@@ -80,6 +88,9 @@ func PreProcessor(task *queue.Task) error {
 	case "PollFollowing-Index":
 		task.Priority = 512
 
+	case "PublishScheduledStreams":
+		task.Priority = 512
+
 	case "PollFollowing-Record":
 		task.Priority = 512
 
@@ -122,6 +133,9 @@ func PreProcessor(task *queue.Task) error {
 
 	case "RecycleDomain":
 		task.Priority = 1024
+
+	case "SyncBlocklists":
+		task.Priority = 1024
 	}
 
 	return nil
@@ -0,0 +1,53 @@
+package consumer
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/turbine/queue"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProcessMedia calculates the dimensions and BlurHash of a file uploaded through the Mastodon API
+func ProcessMedia(factory *service.Factory, session data.Session, args mapof.Any) queue.Result {
+
+	const location = "consumer.ProcessMedia"
+
+	// Locate the UserID parameter
+	token := args.GetString("userId")
+	userID, err := primitive.ObjectIDFromHex(token)
+
+	if err != nil {
+		return queue.Failure(derp.Wrap(err, location, "Invalid UserID", token))
+	}
+
+	// Locate the AttachmentID parameter
+	token = args.GetString("attachmentId")
+	attachmentID, err := primitive.ObjectIDFromHex(token)
+
+	if err != nil {
+		return queue.Failure(derp.Wrap(err, location, "Invalid AttachmentID", token))
+	}
+
+	// Try to load the Attachment from the database
+	attachmentService := factory.Attachment()
+	attachment := model.NewAttachment(model.AttachmentObjectTypeUser, userID)
+
+	if err := attachmentService.LoadMedia(session, userID, attachmentID, &attachment); err != nil {
+
+		// Deleted media has nothing left to process
+		if derp.IsNotFound(err) {
+			return queue.Success()
+		}
+
+		return queue.Error(derp.Wrap(err, location, "Loading media", token))
+	}
+
+	if err := attachmentService.ProcessMedia(session, &attachment); err != nil {
+		return queue.Error(derp.Wrap(err, location, "Processing media", attachmentID))
+	}
+
+	return queue.Success()
+}
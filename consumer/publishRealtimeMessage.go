@@ -28,21 +28,30 @@ func PublishRealtimeMessage(factory *service.Factory, args mapof.Any) queue.Resu
 	}
 
 	// Build the realtime message for this topic
-	message, err := messageForTopic(args.GetInt("topic"), objectID, args.GetString("data"))
+	message, err := messageForTopic(args.GetInt("topic"), objectID, args)
 
 	if err != nil {
 		return queue.Failure(derp.Wrap(err, location, "Invalid 'topic' argument", args))
 	}
 
+	// Database change messages are only sent when the database watchers can't see them
+	if args.GetBool("change") {
+		factory.RealtimeBroker().SendChange(message)
+		return queue.Success()
+	}
+
 	// Deliver directly to this process's broker (synchronous, mutex-safe)
 	factory.RealtimeBroker().Send(message)
 	return queue.Success()
 }
 
 // messageForTopic builds the realtime.Message for a topic, honoring each topic's
-// event-name and payload conventions (see realtime/message.go).  The data argument
-// is only used by the inbox-activity topics; other topics carry a fixed payload.
-func messageForTopic(topic int, objectID primitive.ObjectID, data string) (realtime.Message, error) {
+// event-name and payload conventions (see realtime/message.go).  The "data" argument
+// is only used by the inbox-activity and streaming topics; other topics carry a fixed
+// payload.  Streaming messages also use the "stream" and "event" arguments.
+func messageForTopic(topic int, objectID primitive.ObjectID, args mapof.Any) (realtime.Message, error) {
+
+	data := args.GetString("data")
 
 	switch topic {
 
@@ -72,6 +81,9 @@ func messageForTopic(topic int, objectID primitive.ObjectID, data string) (realt
 
 	case realtime.TopicNotification:
 		return realtime.NewMessage_Notification(objectID), nil
+
+	case realtime.TopicStreaming:
+		return realtime.NewMessage_Streaming(objectID, args.GetSliceOfString("stream"), args.GetString("event"), data), nil
 	}
 
 	return realtime.Message{}, derp.Internal("consumer.messageForTopic", "Unrecognized realtime topic", topic)
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/EmissarySocial/emissary/realtime"
+	"github.com/benpate/rosetta/mapof"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -20,7 +21,7 @@ func TestMessageForTopic(t *testing.T) {
 	test := func(topic int, wantEvent string, wantData string) {
 		t.Helper()
 
-		message, err := messageForTopic(topic, objectID, "payload")
+		message, err := messageForTopic(topic, objectID, mapof.Any{"data": "payload"})
 		require.NoError(t, err, "topic %d", topic)
 		require.Equal(t, objectID, message.ObjectID, "topic %d", topic)
 		require.Equal(t, topic, message.Topic, "topic %d", topic)
@@ -44,13 +45,29 @@ func TestMessageForTopic(t *testing.T) {
 	test(realtime.TopicNotification, "", "notification")
 }
 
+func TestMessageForTopic_Streaming(t *testing.T) {
+
+	message, err := messageForTopic(realtime.TopicStreaming, primitive.NilObjectID, mapof.Any{
+		"stream": []string{"hashtag", "cats"},
+		"event":  "update",
+		"data":   `{"id":"1"}`,
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, primitive.NilObjectID, message.ObjectID)
+	require.Equal(t, realtime.TopicStreaming, message.Topic)
+	require.Equal(t, []string{"hashtag", "cats"}, message.Stream)
+	require.Equal(t, "update", message.Event)
+	require.Equal(t, `{"id":"1"}`, message.Data)
+}
+
 func TestMessageForTopic_Unrecognized(t *testing.T) {
 
 	// TopicAll is a subscription filter, not a publishable topic — and any
 	// unknown value must error rather than deliver a zero message.
-	_, err := messageForTopic(realtime.TopicAll, primitive.NewObjectID(), "")
+	_, err := messageForTopic(realtime.TopicAll, primitive.NewObjectID(), mapof.Any{})
 	require.Error(t, err)
 
-	_, err = messageForTopic(999, primitive.NewObjectID(), "")
+	_, err = messageForTopic(999, primitive.NewObjectID(), mapof.Any{})
 	require.Error(t, err)
 }
@@ -0,0 +1,68 @@
+package consumer
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/turbine/queue"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PublishScheduledStream is a scheduled job that publishes a Stream once its PublishDate arrives.
+func PublishScheduledStream(factory *service.Factory, session data.Session, args mapof.Any) queue.Result {
+
+	const location = "consumer.PublishScheduledStream"
+
+	// Locate the StreamID parameter
+	token := args.GetString("streamId")
+	streamID, err := primitive.ObjectIDFromHex(token)
+
+	if err != nil {
+		return queue.Failure(derp.Wrap(err, location, "Invalid StreamID", token))
+	}
+
+	// Try to load the Stream from the database
+	streamService := factory.Stream()
+	stream := model.NewStream()
+
+	if err := streamService.LoadByID(session, streamID, &stream); err != nil {
+
+		// Deleted Streams have nothing left to publish
+		if derp.IsNotFound(err) {
+			return queue.Success()
+		}
+
+		return queue.Error(derp.Wrap(err, location, "Loading stream", token))
+	}
+
+	// Publish the Stream (including ActivityPub delivery and webhooks)
+	if err := streamService.PublishScheduled(session, &stream); err != nil {
+		return queue.Error(derp.Wrap(err, location, "Publishing stream", stream.StreamID))
+	}
+
+	return queue.Success()
+}
+
+// PublishScheduledStreams publishes every scheduled Stream that is past due.  This runs
+// hourly as a safety net for "PublishScheduledStream" tasks that were lost or failed.
+func PublishScheduledStreams(factory *service.Factory, session data.Session, _ mapof.Any) queue.Result {
+
+	const location = "consumer.PublishScheduledStreams"
+
+	streamService := factory.Stream()
+	streams, err := streamService.RangeScheduledDue(session)
+
+	if err != nil {
+		return queue.Error(derp.Wrap(err, location, "Querying scheduled streams"))
+	}
+
+	for stream := range streams {
+		if err := streamService.PublishScheduled(session, &stream); err != nil {
+			derp.Report(derp.Wrap(err, location, "Publishing stream", stream.StreamID))
+		}
+	}
+
+	return queue.Success()
+}
@@ -0,0 +1,32 @@
+package consumer
+
+import (
+	"time"
+
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/turbine/queue"
+	"github.com/rs/zerolog/log"
+)
+
+// webhookDeliveryRetentionDays is the number of days that a webhook delivery log is kept before it is purged.
+const webhookDeliveryRetentionDays = 30
+
+// PurgeWebhookDeliveries removes webhook delivery logs older than webhookDeliveryRetentionDays
+func PurgeWebhookDeliveries(factory *service.Factory, session data.Session, _ mapof.Any) queue.Result {
+
+	const location = "consumer.PurgeWebhookDeliveries"
+
+	log.Trace().Msg("Task: PurgeWebhookDeliveries")
+
+	// journal.createDate is stored in Unix MILLISECONDS, so compute the cutoff in millis.
+	cutoffMillis := time.Now().AddDate(0, 0, -webhookDeliveryRetentionDays).UnixMilli()
+
+	if err := factory.WebhookDelivery().PurgeBefore(session, cutoffMillis); err != nil {
+		return queue.Error(derp.Wrap(err, location, "Purging old webhook deliveries"))
+	}
+
+	return queue.Success()
+}
@@ -142,17 +142,22 @@ func ruleCleanup_newsfeed(factory *service.Factory, session data.Session, userID
 		return derp.Wrap(err, location, "Ranging NewsItems by UserID", userID)
 	}
 
-	isTagRule := args.GetString("type") == model.RuleTypeTag
+	ruleType := args.GetString("type")
+	isDocumentRule := (ruleType == model.RuleTypeTag) || (ruleType == model.RuleTypeContent)
+
+	// CONTENT rules compile once, from the rule's own MatchKey
+	contentMatcher := model.NewContentMatcher([]model.RuleSummary{{Type: ruleType, MatchKey: matchKey}})
 
 	for newsItem := range rangeFunc {
 
 		keys := append(model.ActorMatchKeys(newsItem.Origin.URL), model.DomainMatchKeys(newsItem.URL)...)
 
-		// TAG rules can only match through the document's hashtags, which the NewsItem does not
-		// store -- so read the locally-cached document (a database read, never a fetch)
-		if !slices.Contains(keys, matchKey) && isTagRule {
+		// TAG and CONTENT rules can only match through the document's hashtags and text, which the
+		// NewsItem does not store -- so read the locally-cached document (a database read, never a fetch)
+		if !slices.Contains(keys, matchKey) && isDocumentRule {
 			if document, exists := ruleCleanup_cachedDocument(factory, newsItem.URL); exists {
 				keys = append(keys, model.DocumentMatchKeys(document)...)
+				keys = append(keys, contentMatcher.MatchKeys(document)...)
 			}
 		}
 
@@ -46,6 +46,12 @@ func ScheduleDaily(serverFactory ServerFactory) queue.Result {
 
 		// Add "PurgeNotifications" tasks to the queue
 		q.NewTask("PurgeNotifications", mapof.Any{"hostname": factory.Hostname()})
+
+		// Add "PurgeWebhookDeliveries" tasks to the queue
+		q.NewTask("PurgeWebhookDeliveries", mapof.Any{"hostname": factory.Hostname()})
+
+		// Add "SyncBlocklists" tasks to the queue
+		q.NewTask("SyncBlocklists", mapof.Any{"hostname": factory.Hostname()})
 	}
 
 	// Stupendous.
@@ -16,6 +16,9 @@ func ScheduleHourly(serverFactory ServerFactory) queue.Result {
 	// Hourly tasks for each domain
 	for factory := range serverFactory.RangeDomains() {
 
+		// Publish any scheduled Streams that were missed by their own tasks
+		q.NewTask("PublishScheduledStreams", mapof.Any{"hostname": factory.Hostname()})
+
 		// Schedule "PollFollowing-Index" tasks every four hours, starting at 1am.
 		if isHour(4, 1) {
 			q.NewTask("PollFollowing-Index", mapof.Any{"hostname": factory.Hostname()})
@@ -8,6 +8,7 @@ import (
 	"github.com/EmissarySocial/emissary/tools/postcommit"
 	"github.com/benpate/data"
 	"github.com/benpate/derp"
+	"github.com/benpate/exp"
 	"github.com/benpate/hannibal/sender"
 	"github.com/benpate/hannibal/vocab"
 	"github.com/benpate/rosetta/mapof"
@@ -84,31 +85,67 @@ func SendSearchResult(factory *service.Factory, session data.Session, args mapof
 	}
 
 	// PART 2:
-	// Send SearchResult to all Global Search Followers
+	// Send local SearchResults to all Global Search Followers, and to Relays that re-publish them.
+	// Only local SearchResults are syndicated, so that relayed posts never loop back out.
 	//
 
-	// Get all Followers from the database
-	searchDomainService := factory.SearchDomain()
-	followerService := factory.Follower()
-	followers := followerService.RangeByGlobalSearch(session)
-
-	// Send ActivityPub messages to each follower. The activity carries its recipient in `to`;
-	// hannibal/sender resolves the inbox and SendLocator.Actor signs as the global @search actor
-	// (F1). Tenant routing uses the activity's `actor` host. See F5.
-	for follower := range followers {
-
-		postcommit.Publish(
-			session,
-			queueService,
-			sender.OutboxSendToAllRecipients,
-			mapof.Any{
-				vocab.AtContext:      vocab.ContextTypeActivityStreams,
-				vocab.PropertyTo:     []string{follower.Actor.ProfileURL},
-				vocab.PropertyActor:  searchDomainService.ActivityPubURL(),
-				vocab.PropertyType:   vocab.ActivityTypeAnnounce,
-				vocab.PropertyObject: searchResult.URL,
-			},
-		)
+	if searchResult.Local {
+
+		// Get all Followers from the database
+		searchDomainService := factory.SearchDomain()
+		followerService := factory.Follower()
+		followers := followerService.RangeByGlobalSearch(session)
+
+		// Send ActivityPub messages to each follower. The activity carries its recipient in `to`;
+		// hannibal/sender resolves the inbox and SendLocator.Actor signs as the global @search actor
+		// (F1). Tenant routing uses the activity's `actor` host. See F5.
+		for follower := range followers {
+
+			postcommit.Publish(
+				session,
+				queueService,
+				sender.OutboxSendToAllRecipients,
+				mapof.Any{
+					vocab.AtContext:      vocab.ContextTypeActivityStreams,
+					vocab.PropertyTo:     []string{follower.Actor.ProfileURL},
+					vocab.PropertyActor:  searchDomainService.ActivityPubURL(),
+					vocab.PropertyType:   vocab.ActivityTypeAnnounce,
+					vocab.PropertyObject: searchResult.URL,
+				},
+			)
+		}
+
+		// Send an Announce to every Relay that re-publishes our posts
+		if err := factory.Relay().Publish(session, &searchResult); err != nil {
+			return queue.Error(derp.Wrap(err, location, "Publishing to Relays", searchResult.URL))
+		}
+	}
+
+	// PART 3:
+	// Send SearchResult to the inboxes of Users who follow its hashtags
+	//
+
+	if searchResult.Tags.NotEmpty() {
+
+		// Only load the document if someone is listening
+		count, err := factory.FollowedTag().Count(session, exp.In("value", searchResult.Tags))
+
+		if err != nil {
+			return queue.Error(derp.Wrap(err, location, "Counting followed hashtags", searchResult.Tags))
+		}
+
+		if count > 0 {
+			postcommit.Publish(
+				session,
+				queueService,
+				"SendSearchResult-FollowedTags",
+				mapof.Any{
+					"hostname": factory.Hostname(),
+					"url":      searchResult.URL,
+					"relayId":  searchResult.RelayID,
+				},
+			)
+		}
 	}
 
 	// SUCCESS!!!
@@ -0,0 +1,45 @@
+package consumer
+
+import (
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/turbine/queue"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SendSearchResult_FollowedTags delivers a SearchResult into the inbox of every
+// User who follows one of its hashtags.
+func SendSearchResult_FollowedTags(factory *service.Factory, session data.Session, args mapof.Any) queue.Result {
+
+	const location = "consumer.SendSearchResult_FollowedTags"
+
+	// Parse URL
+	url := args.GetString("url")
+
+	if url == "" {
+		return queue.Failure(derp.Internal(location, "'url' is required."))
+	}
+
+	// RelayID is optional, and is only present for documents that were forwarded by a Relay
+	relayID, err := primitive.ObjectIDFromHex(args.GetString("relayId"))
+
+	if err != nil {
+		relayID = primitive.NilObjectID
+	}
+
+	// Load the document that was indexed
+	document, err := factory.ActivityStream().AppClient().Load(url)
+
+	if err != nil {
+		return queue.Error(derp.Wrap(err, location, "Loading document", url))
+	}
+
+	// Deliver it to hashtag followers
+	if err := factory.Following().SaveHashtagNewsItems(session, document, relayID); err != nil {
+		return queue.Error(derp.Wrap(err, location, "Delivering to hashtag followers", url))
+	}
+
+	return queue.Success()
+}
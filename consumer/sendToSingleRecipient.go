@@ -1,11 +1,31 @@
 package consumer
 
 import (
-	"github.com/benpate/hannibal/sender"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
 	"github.com/benpate/rosetta/mapof"
 	"github.com/benpate/turbine/queue"
 )
 
-func SendToSingleRecipient(sender sender.Sender, args mapof.Any) queue.Result {
-	return sender.SendToSingleRecipient(args)
+// SendToSingleRecipient delivers an activity to a single inbox.  Emissary makes this request itself
+// (instead of through hannibal's sender) so that it can be signed with RFC 9421 and fall back to
+// draft-cavage signatures for servers that do not support them yet (see ActivityPubDelivery).
+func SendToSingleRecipient(factory *service.Factory, session data.Session, args mapof.Any) queue.Result {
+
+	const location = "consumer.SendToSingleRecipient"
+
+	actorURL := args.GetString("actor")
+	inboxURL := args.GetString("inbox")
+	activity := args.GetMap("activity")
+
+	if (actorURL == "") || (inboxURL == "") || (len(activity) == 0) {
+		return queue.Failure(derp.Internal(location, "Missing 'actor', 'inbox', or 'activity' argument", args))
+	}
+
+	if err := factory.ActivityPubDelivery().Send(session, actorURL, inboxURL, activity); err != nil {
+		return requeue(derp.Wrap(err, location, "Sending activity", actorURL, inboxURL))
+	}
+
+	return queue.Success()
 }
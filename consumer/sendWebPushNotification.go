@@ -181,6 +181,8 @@ func notificationTitle(notification *model.Notification) string {
 		return actor + " boosted your post"
 	case model.NotificationTypeFollow:
 		return actor + " followed you"
+	case model.NotificationTypeFollowRequest:
+		return actor + " asked to follow you"
 	default:
 		return actor
 	}
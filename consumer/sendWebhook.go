@@ -0,0 +1,58 @@
+package consumer
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/turbine/queue"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SendWebhook makes one signed attempt to deliver a WebhookDelivery to its TargetURL.  Failed
+// attempts are retried with exponential backoff until model.WebhookDeliveryMaxAttempts is reached.
+func SendWebhook(factory *service.Factory, session data.Session, args mapof.Any) queue.Result {
+
+	const location = "consumer.SendWebhook"
+
+	log.Trace().Msg("Task: SendWebhook")
+
+	// Locate the WebhookDeliveryID parameter
+	token := args.GetString("webhookDeliveryId")
+	deliveryID, err := primitive.ObjectIDFromHex(token)
+
+	if err != nil {
+		return queue.Failure(derp.Wrap(err, location, "Invalid WebhookDeliveryID", token))
+	}
+
+	// Load the pending WebhookDelivery
+	webhookService := factory.Webhook()
+	delivery := model.NewWebhookDelivery()
+
+	if err := factory.WebhookDelivery().LoadPending(session, deliveryID, &delivery); err != nil {
+
+		// A delivery that was already sent (or purged) has nothing left to do.
+		if derp.IsNotFound(err) {
+			return queue.Success()
+		}
+
+		return queue.Error(derp.Wrap(err, location, "Loading WebhookDelivery", token))
+	}
+
+	// Try to deliver the webhook.  Deliver records every attempt in the delivery log.
+	if err := webhookService.Deliver(session, &delivery); err != nil {
+
+		// RULE: Never return delivery errors to the queue.  This task runs inside a transaction,
+		// and returning an error would roll back the attempt that was just written to the log.
+		// Retries are scheduled here instead, so that we control the backoff.
+		derp.Report(derp.Wrap(err, location, "Delivering webhook", delivery.WebhookDeliveryID, delivery.AttemptCount()))
+
+		if delivery.IsPending() {
+			webhookService.QueueAttempt(session, &delivery, delivery.RetryDelaySeconds())
+		}
+	}
+
+	return queue.Success()
+}
@@ -0,0 +1,25 @@
+package consumer
+
+import (
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/turbine/queue"
+	"github.com/rs/zerolog/log"
+)
+
+// SyncBlocklists refreshes every blocklist subscription on this domain, previewing
+// the changes for review, or applying them directly when the subscription allows it.
+func SyncBlocklists(factory *service.Factory, session data.Session, _ mapof.Any) queue.Result {
+
+	const location = "consumer.SyncBlocklists"
+
+	log.Trace().Msg("Task: SyncBlocklists")
+
+	if err := factory.Blocklist().SyncAll(session); err != nil {
+		return queue.Error(derp.Wrap(err, location, "Synchronizing blocklist subscriptions"))
+	}
+
+	return queue.Success()
+}
@@ -84,10 +84,9 @@ func WithSender(serverFactory ServerFactory, args mapof.Any, handler func(sender
 
 	return WithSession(serverFactory, args, func(factory *service.Factory, session data.Session, args mapof.Any) queue.Result {
 
-		// RULE: Recipient resolution filters through the SENDING actor's block rules (R4). Both
-		// task shapes carry the sender under "actor" -- the raw activity's own property for
-		// SendToAllRecipients, the explicit argument for SendToSingleRecipient (where the locator
-		// only signs, so the binding is inert but harmless).
+		// RULE: Recipient resolution filters through the SENDING actor's block rules (R4). The
+		// sender is the raw activity's own "actor" property. (Single-recipient deliveries do not
+		// resolve recipients, and are sent by ActivityPubDelivery instead of this Sender.)
 		locator := factory.SendLocator(session).BoundToSender(args.GetString(vocab.PropertyActor))
 
 		// Create a new Sender. AllowPrivateIPs is normally FALSE (remote's SSRF guard
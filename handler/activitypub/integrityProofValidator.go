@@ -0,0 +1,165 @@
+package activitypub
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/tools/ascache"
+	"github.com/EmissarySocial/emissary/tools/integrity"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/validator"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/uri"
+	"github.com/rs/zerolog/log"
+)
+
+// IntegrityProofValidator is a hannibal router.Validator that accepts activities carrying a valid
+// Object Integrity Proof (FEP-8b32) from their actor's key.  Because the proof signs the activity
+// itself, it is trusted no matter which server delivered it -- so forwarded activities do not need
+// to be re-fetched from their origin.  Activities without a valid proof are deferred to the HTTP
+// signature validators.  It is built per-request, closing over the (already read) request body.
+type IntegrityProofValidator struct {
+	client streams.Client
+	body   []byte
+}
+
+// NewIntegrityProofValidator returns an IntegrityProofValidator that loads keys through the provided client.
+func NewIntegrityProofValidator(client streams.Client, body []byte) IntegrityProofValidator {
+	return IntegrityProofValidator{
+		client: client,
+		body:   body,
+	}
+}
+
+// Validate returns ResultValid if the activity has a valid proof from its actor, and ResultUnknown otherwise.
+func (v IntegrityProofValidator) Validate(request *http.Request, document *streams.Document) validator.Result {
+
+	const location = "handler.activitypub.IntegrityProofValidator.Validate"
+
+	// The proof signs the activity exactly as the actor sent it, so verify the raw body
+	// instead of the parsed document.
+	activity := make(map[string]any)
+
+	if err := json.Unmarshal(v.body, &activity); err != nil {
+		return validator.ResultUnknown
+	}
+
+	if !integrity.HasProof(activity) {
+		return validator.ResultUnknown
+	}
+
+	// An invalid proof is not fatal (intermediaries may have re-serialized the activity)
+	// so fall back to the HTTP signature instead of rejecting the request.
+	if err := VerifyIntegrityProof(v.client, activity, document.ActorID()); err != nil {
+		log.Debug().Str("location", location).Err(err).Str("activity", document.ID()).Msg("Ignoring invalid integrity proof")
+		return validator.ResultUnknown
+	}
+
+	return validator.ResultValid
+}
+
+// VerifyIntegrityProof validates the Object Integrity Proof (FEP-8b32) on a document, and confirms
+// that it was signed by a key that belongs to authorID.  Keys are read from the cache first, and
+// re-fetched only if the cached key does not verify (in case it has been rotated).
+func VerifyIntegrityProof(client streams.Client, document map[string]any, authorID string) error {
+
+	const location = "handler.activitypub.VerifyIntegrityProof"
+
+	if authorID == "" {
+		return derp.BadRequest(location, "Document must have an author")
+	}
+
+	// RULE: Authors can only vouch for documents on their own server.  Otherwise, a valid
+	// proof could place a document into the cache under an ID that belongs to someone else.
+	documentID, _ := document[vocab.PropertyID].(string)
+
+	if documentID == "" {
+		return derp.BadRequest(location, "Document must have an ID", authorID)
+	}
+
+	if uri.Hostname(documentID) != uri.Hostname(authorID) {
+		return derp.Forbidden(location, "Document must be on the author's server", documentID, authorID)
+	}
+
+	keyID, controller, err := verifyIntegrityProof(client, document)
+
+	if err != nil {
+		keyID, controller, err = verifyIntegrityProof(client, document, ascache.WithWriteOnly())
+	}
+
+	if err != nil {
+		return derp.Wrap(err, location, "Verifying proof", authorID)
+	}
+
+	// RULE: The key must belong to the document's author
+	if controller != authorID {
+		return derp.Forbidden(location, "Proof key does not belong to the author", keyID, controller, authorID)
+	}
+
+	// RULE: The key document must be published by the author's own server
+	if uri.Hostname(keyID) != uri.Hostname(authorID) {
+		return derp.Forbidden(location, "Proof key must be on the author's server", keyID, authorID)
+	}
+
+	return nil
+}
+
+// verifyIntegrityProof verifies a document's proof with keys loaded using the provided options,
+// and returns the key ID and its controller.
+func verifyIntegrityProof(client streams.Client, document map[string]any, options ...any) (string, string, error) {
+
+	const location = "handler.activitypub.verifyIntegrityProof"
+
+	controller := ""
+
+	finder := func(keyID string) (ed25519.PublicKey, error) {
+
+		publicKey, keyController, err := loadPublicKey(client, keyID, options...)
+
+		if err != nil {
+			return nil, err
+		}
+
+		ed25519Key, isEd25519 := publicKey.(ed25519.PublicKey)
+
+		if !isEd25519 {
+			return nil, derp.BadRequest(location, "Proof key must be an Ed25519 key", keyID)
+		}
+
+		controller = keyController
+		return ed25519Key, nil
+	}
+
+	keyID, err := integrity.Verify(document, finder)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	return keyID, controller, nil
+}
+
+// VerifiedObject returns an embedded object (or activity) when it carries a valid Object Integrity
+// Proof from its author, so that it can be used without re-fetching it from its origin.  It returns
+// FALSE for objects that are only linked, or that cannot be verified -- and these should be loaded.
+func VerifiedObject(client streams.Client, object streams.Document) (streams.Document, bool) {
+
+	if !object.IsMap() || !integrity.HasProof(object.Map()) {
+		return object, false
+	}
+
+	// Activities are authored by their actor, and other objects by their attributedTo
+	authorID := object.ActorID()
+
+	if authorID == "" {
+		authorID = object.AttributedTo().ID()
+	}
+
+	if err := VerifyIntegrityProof(client, object.Map(), authorID); err != nil {
+		return object, false
+	}
+
+	return object, true
+}
@@ -0,0 +1,167 @@
+package activitypub
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/EmissarySocial/emissary/tools/integrity"
+	"github.com/EmissarySocial/emissary/tools/multikey"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/validator"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrityProofValidator(t *testing.T) {
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+
+	const actorID = "https://remote.example/@alice"
+	const keyID = actorID + "#ed25519-key"
+
+	client := keyClient{documents: mapof.Any{
+		keyID: mapof.Any{
+			vocab.PropertyID:     keyID,
+			vocab.PropertyType:   multikey.Type,
+			"controller":         actorID,
+			"publicKeyMultibase": multikey.EncodeEd25519(publicKey),
+		},
+	}}
+
+	signed, err := integrity.Sign(map[string]any{
+		vocab.AtContext:      vocab.ContextTypeActivityStreams,
+		vocab.PropertyID:     actorID + "/like/1",
+		vocab.PropertyType:   vocab.ActivityTypeLike,
+		vocab.PropertyActor:  actorID,
+		vocab.PropertyObject: "https://local.example/@bob/post/1",
+	}, keyID, privateKey, time.Now())
+	require.Nil(t, err)
+
+	body, err := json.Marshal(signed)
+	require.Nil(t, err)
+
+	// The request itself is unsigned (e.g. forwarded by a relay or group)
+	request := httptest.NewRequest(http.MethodPost, "/@bob/inbox", bytes.NewReader(body))
+
+	// Valid proofs from the actor's own key are accepted
+	subject := NewIntegrityProofValidator(client, body)
+	result := subject.Validate(request, document(actorID, vocab.ActivityTypeLike))
+	require.Equal(t, validator.ResultValid, result)
+
+	// Proofs that do not belong to the actor are deferred to the HTTP signature validators
+	result = subject.Validate(request, document("https://remote.example/@mallory", vocab.ActivityTypeLike))
+	require.Equal(t, validator.ResultUnknown, result)
+
+	// Tampered activities are deferred to the HTTP signature validators
+	tampered := bytes.Replace(body, []byte("post/1"), []byte("post/2"), 1)
+	result = NewIntegrityProofValidator(client, tampered).Validate(request, document(actorID, vocab.ActivityTypeLike))
+	require.Equal(t, validator.ResultUnknown, result)
+
+	// Unsigned activities are deferred to the HTTP signature validators
+	result = NewIntegrityProofValidator(client, []byte(`{"type":"Like"}`)).Validate(request, document(actorID, vocab.ActivityTypeLike))
+	require.Equal(t, validator.ResultUnknown, result)
+}
+
+func TestVerifiedObject(t *testing.T) {
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+
+	const actorID = "https://local.example/@bob"
+	const keyID = actorID + "#ed25519-key"
+
+	client := keyClient{documents: mapof.Any{
+		keyID: mapof.Any{
+			vocab.PropertyID:     keyID,
+			vocab.PropertyType:   multikey.Type,
+			"controller":         actorID,
+			"publicKeyMultibase": multikey.EncodeEd25519(publicKey),
+		},
+	}}
+
+	signed, err := integrity.Sign(map[string]any{
+		vocab.AtContext:            vocab.ContextTypeActivityStreams,
+		vocab.PropertyID:           actorID + "/post/1",
+		vocab.PropertyType:         vocab.ObjectTypeNote,
+		vocab.PropertyAttributedTo: actorID,
+		vocab.PropertyContent:      "Hello world",
+	}, keyID, privateKey, time.Now())
+	require.Nil(t, err)
+
+	// Embedded objects with a valid proof from their author are verified
+	_, isVerified := VerifiedObject(client, streams.NewDocument(signed))
+	require.True(t, isVerified)
+
+	// Linked objects must be loaded
+	_, isVerified = VerifiedObject(client, streams.NewDocument(actorID+"/post/1"))
+	require.False(t, isVerified)
+
+	// Objects that do not belong to their author must be loaded
+	signed[vocab.PropertyAttributedTo] = "https://local.example/@mallory"
+	_, isVerified = VerifiedObject(client, streams.NewDocument(signed))
+	require.False(t, isVerified)
+}
+
+func TestVerifiedObject_ForeignID(t *testing.T) {
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+
+	const actorID = "https://remote.example/@mallory"
+	const keyID = actorID + "#ed25519-key"
+
+	client := keyClient{documents: mapof.Any{
+		keyID: mapof.Any{
+			vocab.PropertyID:     keyID,
+			vocab.PropertyType:   multikey.Type,
+			"controller":         actorID,
+			"publicKeyMultibase": multikey.EncodeEd25519(publicKey),
+		},
+	}}
+
+	// A valid proof cannot vouch for a document that claims an ID on another server
+	signed, err := integrity.Sign(map[string]any{
+		vocab.AtContext:            vocab.ContextTypeActivityStreams,
+		vocab.PropertyID:           "https://victim.example/@bob/post/1",
+		vocab.PropertyType:         vocab.ObjectTypeNote,
+		vocab.PropertyAttributedTo: actorID,
+		vocab.PropertyContent:      "Not what Bob said",
+	}, keyID, privateKey, time.Now())
+	require.Nil(t, err)
+
+	_, isVerified := VerifiedObject(client, streams.NewDocument(signed))
+	require.False(t, isVerified)
+
+	// Nor can it vouch for a document without an ID
+	unidentified, err := integrity.Sign(map[string]any{
+		vocab.AtContext:            vocab.ContextTypeActivityStreams,
+		vocab.PropertyType:         vocab.ObjectTypeNote,
+		vocab.PropertyAttributedTo: actorID,
+		vocab.PropertyContent:      "Anonymous",
+	}, keyID, privateKey, time.Now())
+	require.Nil(t, err)
+
+	_, isVerified = VerifiedObject(client, streams.NewDocument(unidentified))
+	require.False(t, isVerified)
+
+	// The same proof on the author's own server is accepted
+	own, err := integrity.Sign(map[string]any{
+		vocab.AtContext:            vocab.ContextTypeActivityStreams,
+		vocab.PropertyID:           actorID + "/post/1",
+		vocab.PropertyType:         vocab.ObjectTypeNote,
+		vocab.PropertyAttributedTo: actorID,
+		vocab.PropertyContent:      "Hello world",
+	}, keyID, privateKey, time.Now())
+	require.Nil(t, err)
+
+	_, isVerified = VerifiedObject(client, streams.NewDocument(own))
+	require.True(t, isVerified)
+}
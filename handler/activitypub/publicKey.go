@@ -0,0 +1,77 @@
+package activitypub
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+
+	"github.com/EmissarySocial/emissary/tools/multikey"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+)
+
+// loadPublicKey loads a public key (either a FEP-521a Multikey or a PEM-encoded publicKey)
+// and returns it along with the ID of the actor that controls it.
+func loadPublicKey(client streams.Client, keyID string, options ...any) (crypto.PublicKey, string, error) {
+
+	const location = "handler.activitypub.loadPublicKey"
+
+	key, err := client.Load(keyID, options...)
+
+	if err != nil {
+		return nil, "", derp.Wrap(err, location, "Loading public key", keyID)
+	}
+
+	controller := keyController(keyID, key)
+
+	// Multikeys (FEP-521a) carry Ed25519 keys
+	if multibase := key.Get("publicKeyMultibase").String(); multibase != "" {
+
+		publicKey, err := multikey.DecodeEd25519(multibase)
+
+		if err != nil {
+			return nil, "", derp.Wrap(err, location, "Decoding Multikey", keyID)
+		}
+
+		return publicKey, controller, nil
+	}
+
+	// Fall back to PEM-encoded (RSA) keys
+	block, _ := pem.Decode([]byte(key.PublicKeyPEM()))
+
+	if block == nil {
+		return nil, "", derp.BadRequest(location, "Key document does not include a public key", keyID)
+	}
+
+	if publicKey, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return publicKey, controller, nil
+	}
+
+	publicKey, err := x509.ParsePKCS1PublicKey(block.Bytes)
+
+	if err != nil {
+		return nil, "", derp.Wrap(err, location, "Parsing PEM public key", keyID)
+	}
+
+	return publicKey, controller, nil
+}
+
+// keyController returns the actor that controls a key: the Multikey "controller",
+// the publicKey "owner", or (if neither is present) the document that the key is embedded in.
+func keyController(keyID string, key streams.Document) string {
+
+	if controller := key.Get("controller").String(); controller != "" {
+		return controller
+	}
+
+	if owner := key.Get("owner").String(); owner != "" {
+		return owner
+	}
+
+	if base, _, found := strings.Cut(keyID, "#"); found {
+		return base
+	}
+
+	return ""
+}
@@ -1,6 +1,8 @@
 package activitypub
 
 import (
+	"bytes"
+	"io"
 	"net/http"
 
 	"github.com/EmissarySocial/emissary/model"
@@ -21,9 +23,19 @@ func ReceiveRequest(request *http.Request, client streams.Client, checker RuleCh
 
 	const location = "handler.activitypub.ReceiveRequest"
 
+	// Read the body up front so that RFC 9421 signatures can verify its Content-Digest, then
+	// restore it for the router to parse.
+	body, err := io.ReadAll(request.Body)
+
+	if err != nil {
+		return streams.NilDocument(), derp.Wrap(err, location, "Reading request body")
+	}
+
+	request.Body = io.NopCloser(bytes.NewReader(body))
+
 	// The validator chain goes FIRST: caller options like WithPublicKeyFinder patch the HTTPSig
 	// entry in place, and would be discarded if a later option replaced the chain.
-	options = append([]router.Option{InboxValidators(checker, session, userID)}, options...)
+	options = append([]router.Option{InboxValidators(checker, session, userID, client, body)}, options...)
 
 	// Receive and parse the activity
 	activity, err := router.ReceiveRequest(request, client, options...)
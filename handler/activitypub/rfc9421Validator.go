@@ -0,0 +1,76 @@
+package activitypub
+
+import (
+	"crypto"
+	"net/http"
+	"time"
+
+	"github.com/EmissarySocial/emissary/tools/ascache"
+	"github.com/EmissarySocial/emissary/tools/httpsig"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/validator"
+	"github.com/benpate/uri"
+	"github.com/rs/zerolog/log"
+)
+
+// RFC9421Validator is a hannibal router.Validator that verifies RFC 9421 HTTP Message Signatures.
+// Requests signed with draft-cavage signatures are deferred to the standard HTTPSig validator.
+// It is built per-request, closing over the (already read) request body.
+type RFC9421Validator struct {
+	client streams.Client
+	body   []byte
+}
+
+// NewRFC9421Validator returns an RFC9421Validator that loads keys through the provided client.
+func NewRFC9421Validator(client streams.Client, body []byte) RFC9421Validator {
+	return RFC9421Validator{
+		client: client,
+		body:   body,
+	}
+}
+
+// Validate verifies the request's RFC 9421 signature, and confirms that the signing key belongs
+// to the activity's actor.  Requests without an RFC 9421 signature return ResultUnknown.
+func (v RFC9421Validator) Validate(request *http.Request, document *streams.Document) validator.Result {
+
+	const location = "handler.activitypub.RFC9421Validator.Validate"
+
+	if !httpsig.IsRFC9421(request) {
+		return validator.ResultUnknown
+	}
+
+	// The controller of the key that signed the request, as reported by the finder
+	controller := ""
+
+	finder := func(keyID string) (crypto.PublicKey, error) {
+
+		// WithWriteOnly forces a cache revalidation so that rotated keys are picked up
+		publicKey, keyController, err := loadPublicKey(v.client, keyID, ascache.WithWriteOnly())
+		controller = keyController
+		return publicKey, err
+	}
+
+	host := uri.GuessProtocolForHostname(request.Host) + request.Host
+	keyID, err := httpsig.Verify(request, v.body, host, finder, time.Now())
+
+	if err != nil {
+		log.Debug().Str("location", location).Err(err).Str("keyId", httpsig.KeyID(request)).Msg("Rejecting invalid RFC 9421 signature")
+		return validator.ResultInvalid
+	}
+
+	// RULE: The key must belong to the actor who sent the activity
+	actorID := document.ActorID()
+
+	if (controller == "") || (controller != actorID) {
+		log.Debug().Str("location", location).Str("keyId", keyID).Str("controller", controller).Str("actor", actorID).Msg("Rejecting signing key that does not belong to the actor")
+		return validator.ResultInvalid
+	}
+
+	// RULE: The key document must be published by the actor's own server
+	if uri.Hostname(keyID) != uri.Hostname(actorID) {
+		log.Debug().Str("location", location).Str("keyId", keyID).Str("actor", actorID).Msg("Rejecting signing key that is not on the actor's server")
+		return validator.ResultInvalid
+	}
+
+	return validator.ResultValid
+}
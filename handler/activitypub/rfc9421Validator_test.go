@@ -0,0 +1,101 @@
+package activitypub
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/EmissarySocial/emissary/tools/httpsig"
+	"github.com/EmissarySocial/emissary/tools/multikey"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/validator"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+// keyClient is a streams.Client that serves key documents from an in-memory map.
+type keyClient struct {
+	documents mapof.Any
+}
+
+func (client keyClient) SetRootClient(streams.Client) {}
+
+func (client keyClient) Load(uri string, _ ...any) (streams.Document, error) {
+
+	if value, ok := client.documents[uri]; ok {
+		return streams.NewDocument(value), nil
+	}
+
+	return streams.NilDocument(), derp.NotFound("keyClient.Load", "Unknown URI", uri)
+}
+
+func (client keyClient) Save(streams.Document) error { return nil }
+
+func (client keyClient) Delete(string) error { return nil }
+
+// signedRequest returns an inbound request (as the receiving server sees it) that
+// was signed by the sender with an RFC 9421 signature.
+func signedRequest(t *testing.T, body []byte, keyID string, privateKey ed25519.PrivateKey) *http.Request {
+
+	outbound, err := http.NewRequest(http.MethodPost, "https://local.example/@bob/inbox", bytes.NewReader(body))
+	require.Nil(t, err)
+	require.Nil(t, httpsig.Sign(outbound, body, keyID, privateKey, time.Now()))
+
+	inbound := httptest.NewRequest(http.MethodPost, "/@bob/inbox", bytes.NewReader(body))
+	inbound.Host = "local.example"
+	inbound.Header = outbound.Header.Clone()
+	return inbound
+}
+
+func TestRFC9421Validator(t *testing.T) {
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+
+	const actorID = "https://remote.example/@alice"
+	const keyID = actorID + "#ed25519-key"
+
+	client := keyClient{documents: mapof.Any{
+		keyID: mapof.Any{
+			vocab.PropertyID:     keyID,
+			vocab.PropertyType:   multikey.Type,
+			"controller":         actorID,
+			"publicKeyMultibase": multikey.EncodeEd25519(publicKey),
+		},
+	}}
+
+	body := []byte(`{"type":"Create"}`)
+
+	// Valid signatures from the actor's own key are accepted
+	subject := NewRFC9421Validator(client, body)
+	result := subject.Validate(signedRequest(t, body, keyID, privateKey), document(actorID, vocab.ActivityTypeCreate))
+	require.Equal(t, validator.ResultValid, result)
+
+	// Keys that belong to a different actor are rejected
+	result = subject.Validate(signedRequest(t, body, keyID, privateKey), document("https://remote.example/@mallory", vocab.ActivityTypeCreate))
+	require.Equal(t, validator.ResultInvalid, result)
+
+	// Bodies that do not match the signed digest are rejected
+	tampered := NewRFC9421Validator(client, []byte(`{"type":"Delete"}`))
+	result = tampered.Validate(signedRequest(t, body, keyID, privateKey), document(actorID, vocab.ActivityTypeCreate))
+	require.Equal(t, validator.ResultInvalid, result)
+
+	// Unknown keys are rejected
+	result = subject.Validate(signedRequest(t, body, actorID+"#other-key", privateKey), document(actorID, vocab.ActivityTypeCreate))
+	require.Equal(t, validator.ResultInvalid, result)
+}
+
+// Requests without an RFC 9421 signature are deferred to the draft-cavage validator
+func TestRFC9421Validator_Cavage(t *testing.T) {
+
+	subject := NewRFC9421Validator(keyClient{}, nil)
+	result := subject.Validate(request("https://remote.example/@alice#main-key"), document("https://remote.example/@alice", vocab.ActivityTypeCreate))
+
+	require.Equal(t, validator.ResultUnknown, result)
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/httpsig"
 	"github.com/benpate/data"
 	"github.com/benpate/hannibal/router"
 	"github.com/benpate/hannibal/sigs"
@@ -15,13 +16,17 @@ import (
 )
 
 // InboxValidators returns the router Option that installs the canonical inbox validator chain (Stage 1
-// of the block gate plus the standard validators). Pass NilObjectID as userID for admin-tier inboxes.
-func InboxValidators(checker RuleChecker, session data.Session, userID primitive.ObjectID) router.Option {
+// of the block gate, integrity proofs, RFC 9421 signatures, and the standard validators). Pass NilObjectID
+// as userID for admin-tier inboxes. The body is the already-read request body, which proofs and RFC 9421
+// digests are checked against.
+func InboxValidators(checker RuleChecker, session data.Session, userID primitive.ObjectID, client streams.Client, body []byte) router.Option {
 
 	// One definition so the chain cannot drift: WithValidators REPLACES it wholesale, so hand-assembling
 	// it per handler risks omitting NewHTTPSig and silently disabling signature verification there.
 	return router.WithValidators(
 		NewRuleValidator(checker, session, userID),
+		NewIntegrityProofValidator(client, body),
+		NewRFC9421Validator(client, body),
 		validator.NewHTTPSig(nil),
 		validator.NewDeletedObject(),
 	)
@@ -120,17 +125,36 @@ func keyIDDomainKeys(request *http.Request) []string {
 
 	// The keyId names the SERVER delivering this activity, so binding it lets a DOMAIN block refuse a
 	// blocked relay before its key is ever fetched.
+	signerID := SignerID(request)
+
+	if signerID == "" {
+		return nil
+	}
+
+	return model.DomainMatchKeys(signerID)
+}
+
+// SignerID returns the keyId of the request's HTTP signature (RFC 9421 or draft-cavage), which
+// identifies the server that delivered it.  This may differ from the activity's actor when the
+// activity was forwarded (for instance, by a relay).  It returns an empty string if the request
+// is unsigned or the signature will not parse.  The signature itself is NOT verified here.
+func SignerID(request *http.Request) string {
+
+	if httpsig.IsRFC9421(request) {
+		return httpsig.KeyID(request)
+	}
+
 	signature := sigs.GetSignature(request)
 
 	if signature == "" {
-		return nil
+		return ""
 	}
 
 	parsed, err := sigs.ParseSignature(signature)
 
 	if err != nil {
-		return nil
+		return ""
 	}
 
-	return model.DomainMatchKeys(parsed.ActorID())
+	return parsed.ActorID()
 }
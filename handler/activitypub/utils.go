@@ -3,7 +3,9 @@ package activitypub
 import (
 	"crypto/sha256"
 	"encoding/base64"
+	"net/http"
 
+	"github.com/benpate/derp"
 	"github.com/benpate/hannibal/streams"
 	"github.com/benpate/hannibal/vocab"
 	"github.com/benpate/uri"
@@ -49,3 +51,22 @@ func sha256base64(value string) string {
 
 	return "sha-" + base64.StdEncoding.EncodeToString(hash.Sum(nil))
 }
+
+// IsConfirmedDelete returns TRUE if the object of a "Delete" activity can be removed.
+// The object is re-fetched from its origin, and is confirmed if it no longer exists, has
+// been replaced by a Tombstone, or is attributed to the Actor who sent the activity.
+func IsConfirmedDelete(client streams.Client, activity streams.Document) bool {
+
+	document, err := client.Load(activity.Object().ID())
+
+	if err != nil {
+		errorCode := derp.ErrorCode(err)
+		return (errorCode == http.StatusNotFound) || (errorCode == http.StatusGone)
+	}
+
+	if document.Type() == vocab.ObjectTypeTombstone {
+		return true
+	}
+
+	return document.AttributedTo().ID() == activity.ActorID()
+}
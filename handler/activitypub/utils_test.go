@@ -3,6 +3,10 @@ package activitypub
 import (
 	"testing"
 
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
 	"github.com/stretchr/testify/require"
 )
 
@@ -44,3 +48,64 @@ func TestIsSameOrigin(t *testing.T) {
 		})
 	}
 }
+
+// TestIsConfirmedDelete pins that "Delete" activities only remove documents that are
+// gone, tombstoned, or owned by the Actor who sent them.
+func TestIsConfirmedDelete(t *testing.T) {
+
+	const actorID = "https://example.com/@sender"
+
+	client := deleteTestClient{
+		"https://example.com/notes/tombstone": mapof.Any{
+			vocab.PropertyType: vocab.ObjectTypeTombstone,
+		},
+		"https://example.com/notes/owned": mapof.Any{
+			vocab.PropertyType:         vocab.ObjectTypeNote,
+			vocab.PropertyAttributedTo: actorID,
+		},
+		"https://example.com/notes/other": mapof.Any{
+			vocab.PropertyType:         vocab.ObjectTypeNote,
+			vocab.PropertyAttributedTo: "https://example.com/@someone-else",
+		},
+		"https://example.com/notes/broken": derp.Internal("test", "Server error"),
+	}
+
+	isConfirmed := func(objectID string) bool {
+		activity := streams.NewDocument(mapof.Any{
+			vocab.PropertyType:   vocab.ActivityTypeDelete,
+			vocab.PropertyActor:  actorID,
+			vocab.PropertyObject: objectID,
+		})
+		return IsConfirmedDelete(client, activity)
+	}
+
+	require.True(t, isConfirmed("https://example.com/notes/missing"))
+	require.True(t, isConfirmed("https://example.com/notes/tombstone"))
+	require.True(t, isConfirmed("https://example.com/notes/owned"))
+	require.False(t, isConfirmed("https://example.com/notes/other"))
+	require.False(t, isConfirmed("https://example.com/notes/broken"))
+}
+
+// deleteTestClient is a streams.Client that returns documents (or errors) from a map,
+// and a NotFound error for everything else
+type deleteTestClient map[string]any
+
+func (client deleteTestClient) SetRootClient(streams.Client) {}
+
+func (client deleteTestClient) Load(uri string, _ ...any) (streams.Document, error) {
+
+	switch value := client[uri].(type) {
+
+	case mapof.Any:
+		return streams.NewDocument(value, streams.WithClient(client)), nil
+
+	case error:
+		return streams.NilDocument(), value
+	}
+
+	return streams.NilDocument(), derp.NotFound("deleteTestClient.Load", "Unknown URI", uri)
+}
+
+func (client deleteTestClient) Save(streams.Document) error { return nil }
+
+func (client deleteTestClient) Delete(string) error { return nil }
@@ -0,0 +1,75 @@
+package activitypub
+
+import (
+	"bytes"
+	"crypto"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/EmissarySocial/emissary/tools/ascache"
+	"github.com/EmissarySocial/emissary/tools/httpsig"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/sigs"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/uri"
+)
+
+// VerifiedSignerID returns the keyId of the request's HTTP signature (RFC 9421 or draft-cavage), but
+// only if that signature verifies.  Unlike SignerID, this can be trusted to identify the server that
+// delivered a forwarded activity -- even when the activity itself was accepted because of an integrity
+// proof.  It returns an empty string if the request is unsigned or the signature is invalid.  The
+// request body is restored afterwards, so that it can still be received normally.
+func VerifiedSignerID(request *http.Request, client streams.Client) string {
+
+	body, err := io.ReadAll(request.Body)
+
+	if err != nil {
+		return ""
+	}
+
+	request.Body = io.NopCloser(bytes.NewReader(body))
+
+	defer func() {
+		request.Body = io.NopCloser(bytes.NewReader(body))
+	}()
+
+	// RFC 9421 signatures
+	if httpsig.IsRFC9421(request) {
+
+		finder := func(keyID string) (crypto.PublicKey, error) {
+			publicKey, _, err := loadPublicKey(client, keyID, ascache.WithWriteOnly())
+			return publicKey, err
+		}
+
+		host := uri.GuessProtocolForHostname(request.Host) + request.Host
+		keyID, err := httpsig.Verify(request, body, host, finder, time.Now())
+
+		if err != nil {
+			return ""
+		}
+
+		return keyID
+	}
+
+	// draft-cavage signatures
+	finder := func(keyID string) (string, error) {
+
+		// WithWriteOnly forces a cache revalidation so that rotated keys are picked up
+		key, err := client.Load(keyID, ascache.WithWriteOnly())
+
+		if err != nil {
+			return "", derp.Wrap(err, "handler.activitypub.VerifiedSignerID", "Loading public key", keyID)
+		}
+
+		return key.PublicKeyPEM(), nil
+	}
+
+	signature, err := sigs.Verify(request, finder)
+
+	if err != nil {
+		return ""
+	}
+
+	return signature.ActorID()
+}
@@ -8,8 +8,9 @@ import (
 
 // Context includes all of the necessary objects to handle an ActivityPub request
 type Context struct {
-	factory *service.Factory
-	session data.Session
+	factory  *service.Factory
+	session  data.Session
+	signerID string // keyId of the (verified) HTTP signature, which identifies the server that delivered the activity.  Empty if the signature did not verify.
 }
 
 func (context Context) ActivityPubActor() (outbox.Actor, error) {
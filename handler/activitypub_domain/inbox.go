@@ -17,10 +17,12 @@ func PostInbox(ctx *steranko.Context, factory *service.Factory, session data.Ses
 
 	client := factory.ActivityStream().SearchDomainClient()
 
-	// Create a new request context for the ActivityPub router
+	// Create a new request context for the ActivityPub router.  Relays are identified by the
+	// server that signed the request, so only a verified signature is used here.
 	context := Context{
-		factory: factory,
-		session: session,
+		factory:  factory,
+		session:  session,
+		signerID: activitypub.VerifiedSignerID(ctx.Request(), client),
 	}
 
 	// Retrieve the activity through the canonical inbox receive funnel (Stage-1 validators + the
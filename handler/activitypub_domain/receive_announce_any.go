@@ -0,0 +1,47 @@
+package activitypub_domain
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+)
+
+func init() {
+	inboxRouter.Add(vocab.ActivityTypeAnnounce, vocab.Any, func(context Context, activity streams.Document) error {
+
+		const location = "handler.activitypub_domain.ReceiveAnnounce"
+
+		// LitePub relays forward posts as an "Announce" from the relay's own actor
+		relayService := context.factory.Relay()
+		relay := model.NewRelay()
+
+		if err := relayService.LoadByActorURL(context.session, activity.ActorID(), &relay); err != nil {
+
+			if derp.IsNotFound(err) {
+				return nil
+			}
+
+			return derp.Wrap(err, location, "Loading relay", activity.ActorID())
+		}
+
+		// RULE: Ignore relays that have not accepted our subscription
+		if !relay.IsActive() {
+			return nil
+		}
+
+		// Load the post that is being forwarded
+		document, err := loadRelayedObject(context, activity.Object())
+
+		if err != nil {
+			return derp.Wrap(err, location, "Loading relayed object", activity.Object().ID())
+		}
+
+		// Add it to the search index
+		if err := relayService.Receive(context.session, &relay, document); err != nil {
+			return derp.Wrap(err, location, "Receiving relayed object", document.ID())
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,44 @@
+package activitypub_domain
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+)
+
+func init() {
+	inboxRouter.Add(vocab.ActivityTypeCreate, vocab.Any, func(context Context, activity streams.Document) error {
+
+		const location = "handler.activitypub_domain.ReceiveCreate"
+
+		// Mastodon-style relays forward the original "Create" activity, so the relay is
+		// identified by the server that signed the request, not by the activity's actor.
+		relay := model.NewRelay()
+
+		isRelay, err := loadSigningRelay(context, &relay)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Loading relay")
+		}
+
+		// RULE: Ignore activities that were not forwarded by an active Relay
+		if !isRelay {
+			return nil
+		}
+
+		// Load the post that is being forwarded
+		document, err := loadRelayedObject(context, activity.Object())
+
+		if err != nil {
+			return derp.Wrap(err, location, "Loading relayed object", activity.Object().ID())
+		}
+
+		// Add it to the search index
+		if err := context.factory.Relay().Receive(context.session, &relay, document); err != nil {
+			return derp.Wrap(err, location, "Receiving relayed object", document.ID())
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,52 @@
+package activitypub_domain
+
+import (
+	"github.com/EmissarySocial/emissary/handler/activitypub"
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+)
+
+func init() {
+	inboxRouter.Add(vocab.ActivityTypeDelete, vocab.Any, func(context Context, activity streams.Document) error {
+
+		const location = "handler.activitypub_domain.ReceiveDelete"
+
+		// Mastodon-style relays forward "Delete" activities along with the posts themselves
+		relay := model.NewRelay()
+		isRelay, err := loadSigningRelay(context, &relay)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Loading relay")
+		}
+
+		// RULE: Ignore activities that were not forwarded by an active Relay
+		if !isRelay {
+			return nil
+		}
+
+		objectID := activity.Object().ID()
+
+		// RULE: Actors can only delete objects from their own origin
+		if !activitypub.IsSameOrigin(activity.ActorID(), objectID) {
+			return derp.Forbidden(location, "Actor and Object must share the same origin", activity.ActorID(), objectID)
+		}
+
+		// Relays do not sign the activities that they forward, so refresh the cached
+		// document and confirm with its origin that it is really gone.
+		client := context.factory.ActivityStream().SearchDomainClient()
+		_ = client.Delete(objectID)
+
+		if !activitypub.IsConfirmedDelete(client, activity) {
+			return nil
+		}
+
+		// Remove the document from the search index and from hashtag feeds
+		if err := context.factory.Relay().ReceiveDelete(context.session, objectID); err != nil {
+			return derp.Wrap(err, location, "Removing relayed object", objectID)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,23 @@
+package activitypub_domain
+
+import (
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func init() {
+	inboxRouter.Add(vocab.ActivityTypeFlag, vocab.Any, func(context Context, activity streams.Document) error {
+
+		const location = "handler.activityPub_domain.ReceiveFlag"
+
+		// Save the Flag as a Report about one of our Users.  The Flag names the User
+		// (or their content) in its objects, because it was not sent to their inbox.
+		if err := context.factory.Report().ReceiveFlag(context.session, activity, primitive.NilObjectID); err != nil {
+			return derp.Wrap(err, location, "Receiving Flag", activity.ID())
+		}
+
+		return nil
+	})
+}
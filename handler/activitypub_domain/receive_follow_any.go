@@ -36,6 +36,20 @@ func init() {
 			return derp.Forbidden(location, "Blocked by rule", activity.Object().ID())
 		}
 
+		// RULE: Relays that we subscribe to (LitePub) follow us back.  Accept them without adding a
+		// Follower, so that our posts only reach the relays that are configured to publish them.
+		relayService := context.factory.Relay()
+		relay := model.NewRelay()
+
+		if err := relayService.LoadByActorURL(context.session, activity.ActorID(), &relay); err == nil {
+			acceptID := relayService.FollowID(&relay) + "/accept"
+			context.factory.Outbox().SendAccept(context.session, searchDomainService.ActivityPubURL(), acceptID, activity)
+			return nil
+
+		} else if !derp.IsNotFound(err) {
+			return derp.Wrap(err, location, "Loading relay", activity.ActorID())
+		}
+
 		// Try to look up the complete actor record from the activity
 		document, err := activity.Actor().Load()
 
@@ -0,0 +1,36 @@
+package activitypub_domain
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+)
+
+func init() {
+	inboxRouter.Add(vocab.ActivityTypeReject, vocab.Any, func(context Context, activity streams.Document) error {
+
+		const location = "handler.activitypub_domain.ReceiveReject"
+
+		// The only thing that the search actor follows is a Relay, so the object
+		// should be the Follow that subscribed to one.
+		relayService := context.factory.Relay()
+		relay := model.NewRelay()
+
+		if err := relayService.LoadByFollowID(context.session, activity.Object().ID(), &relay); err != nil {
+
+			if derp.IsNotFound(err) {
+				return nil
+			}
+
+			return derp.Wrap(err, location, "Loading relay", activity.Object().ID())
+		}
+
+		// Mark the Relay as rejected
+		if err := relayService.Reject(context.session, &relay, activity.ActorID()); err != nil {
+			return derp.Wrap(err, location, "Rejecting relay subscription", relay.RelayID)
+		}
+
+		return nil
+	})
+}
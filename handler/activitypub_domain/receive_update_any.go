@@ -0,0 +1,81 @@
+package activitypub_domain
+
+import (
+	"github.com/EmissarySocial/emissary/handler/activitypub"
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/ascache"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+)
+
+func init() {
+	inboxRouter.Add(vocab.ActivityTypeUpdate, vocab.Any, func(context Context, activity streams.Document) error {
+
+		const location = "handler.activitypub_domain.ReceiveUpdate"
+
+		// Mastodon-style relays forward "Update" activities along with the posts themselves
+		relay := model.NewRelay()
+		isRelay, err := loadSigningRelay(context, &relay)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Loading relay")
+		}
+
+		// RULE: Ignore activities that were not forwarded by an active Relay
+		if !isRelay {
+			return nil
+		}
+
+		objectID := activity.Object().ID()
+
+		// RULE: Actors can only update objects from their own origin
+		if !activitypub.IsSameOrigin(activity.ActorID(), objectID) {
+			return derp.Forbidden(location, "Actor and Object must share the same origin", activity.ActorID(), objectID)
+		}
+
+		// Use the edited document if its author signed it.  Otherwise, revalidate
+		// the cached copy with its origin, so that newsItems display the new version, too.
+		document, err := loadUpdatedObject(context, activity.Object())
+
+		if err != nil {
+			return derp.Wrap(err, location, "Loading relayed object", objectID)
+		}
+
+		// Refresh the search index
+		if err := context.factory.Relay().ReceiveUpdate(context.session, &relay, document); err != nil {
+			return derp.Wrap(err, location, "Updating relayed object", objectID)
+		}
+
+		return nil
+	})
+}
+
+// loadUpdatedObject returns the new version of a document that a Relay forwarded in an "Update"
+// activity, and saves it into the cache.  Like loadRelayedObject, embedded documents are only used
+// if they carry a valid integrity proof.  All others are re-fetched from their origin.
+func loadUpdatedObject(context Context, object streams.Document) (streams.Document, error) {
+
+	const location = "handler.activitypub_domain.loadUpdatedObject"
+
+	activityService := context.factory.ActivityStream()
+	client := activityService.SearchDomainClient()
+
+	if verified, isVerified := activitypub.VerifiedObject(client, object); isVerified {
+
+		if err := activityService.Save(verified); err != nil {
+			return verified, derp.Wrap(err, location, "Saving verified document", verified.ID())
+		}
+
+		return verified, nil
+	}
+
+	// WithWriteOnly skips the cached copy, and saves the new version in its place
+	result, err := client.Load(object.ID(), ascache.WithWriteOnly())
+
+	if err != nil {
+		return result, derp.Wrap(err, location, "Loading document", object.ID())
+	}
+
+	return result, nil
+}
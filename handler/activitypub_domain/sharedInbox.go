@@ -0,0 +1,115 @@
+package activitypub_domain
+
+import (
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/handler/activitypub"
+	ap_stream "github.com/EmissarySocial/emissary/handler/activitypub_stream"
+	ap_user "github.com/EmissarySocial/emissary/handler/activitypub_user"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/router"
+	"github.com/benpate/steranko"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PostSharedInbox receives an inbound ActivityPub activity on behalf of every local actor.
+// The HTTP signature is verified ONCE, then the activity is fanned out to each local User who
+// follows the sender or is addressed by it, and to each addressed Stream.  Every User still
+// runs through their own de-duplication and Stage-2 rule gate (see ap_user.ReceiveActivity).
+func PostSharedInbox(ctx *steranko.Context, factory *service.Factory, session data.Session) error {
+
+	const location = "handler.activitypub_domain.PostSharedInbox"
+
+	activityService := factory.ActivityStream()
+
+	// Receive and parse the activity through the canonical inbox receive funnel, evaluated against
+	// admin-tier rules (NilObjectID) -- Stage 1 of the block gate (D5).  Per-user rules are applied
+	// individually, below.
+	activity, err := activitypub.ReceiveRequest(
+		ctx.Request(),
+		activityService.AppClient(),
+		factory.Rule(),
+		session,
+		primitive.NilObjectID,
+
+		// Injecting our own key finder that is aware of the ascache middleware.
+		router.WithPublicKeyFinder(activityService.PublicKeyFinder),
+	)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Receiving ActivityPub request")
+	}
+
+	// Find all local recipients for this activity
+	users, streams, err := factory.Inbox().SharedInboxRecipients(session, activity)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Locating recipients", activity.ID())
+	}
+
+	// Track server errors so that the sender can retry.  Recipients who have
+	// already succeeded are protected from duplicates by their own de-duplication.
+	failed := false
+
+	// Deliver the activity to each User
+	for index := range users {
+
+		user := &users[index]
+
+		if err := ap_user.ReceiveActivity(ctx, factory, session, user, activity); err != nil {
+
+			// Client errors (like blocked senders) are this recipient's decision.  Skip them
+			// silently so that one User's rules do not reveal anything or affect anyone else.
+			if derp.IsClientError(err) {
+				continue
+			}
+
+			derp.Report(derp.Wrap(err, location, "Delivering activity to User", user.UserID, activity.ID()))
+			failed = true
+		}
+	}
+
+	// Deliver the activity to each Stream that is an ActivityPub actor
+	templateService := factory.Template()
+
+	for index := range streams {
+
+		stream := &streams[index]
+		template, err := templateService.Load(stream.TemplateID)
+
+		if err != nil {
+			derp.Report(derp.Wrap(err, location, "Loading Template", stream.TemplateID))
+			failed = true
+			continue
+		}
+
+		// RULE: Only Streams with an ActivityPub actor can receive activities
+		if template.Actor.IsNil() {
+			continue
+		}
+
+		if err := ap_stream.ReceiveActivity(factory, session, &template, stream, activity); err != nil {
+
+			if derp.IsClientError(err) {
+				continue
+			}
+
+			derp.Report(derp.Wrap(err, location, "Delivering activity to Stream", stream.StreamID, activity.ID()))
+			failed = true
+		}
+	}
+
+	// Ask the sender to retry if any recipient failed unexpectedly
+	if failed {
+		return derp.Internal(location, "Unable to deliver activity to all recipients", activity.ID())
+	}
+
+	// Send the response to the client (unless a handler has already responded)
+	if ctx.Response().Committed {
+		return nil
+	}
+
+	return ctx.String(http.StatusOK, "")
+}
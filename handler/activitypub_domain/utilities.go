@@ -1,7 +1,12 @@
 package activitypub_domain
 
 import (
+	"github.com/EmissarySocial/emissary/handler/activitypub"
+	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/uri"
 	"github.com/labstack/echo/v4"
 )
 
@@ -9,3 +14,49 @@ import (
 func fullURL(factory *service.Factory, ctx echo.Context) string {
 	return factory.Host() + ctx.Request().URL.String()
 }
+
+// loadRelayedObject returns the post that a Relay forwarded, and adds it to the cache.  Relays sign
+// the requests that they forward, not the posts themselves, so embedded posts are only used directly
+// if they carry a valid integrity proof (FEP-8b32) from their author.  All others are loaded from
+// their origin.
+func loadRelayedObject(context Context, object streams.Document) (streams.Document, error) {
+
+	const location = "handler.activitypub_domain.loadRelayedObject"
+
+	activityService := context.factory.ActivityStream()
+
+	if verified, isVerified := activitypub.VerifiedObject(activityService.SearchDomainClient(), object); isVerified {
+
+		if err := activityService.Save(verified); err != nil {
+			return verified, derp.Wrap(err, location, "Saving verified document", verified.ID())
+		}
+
+		return verified, nil
+	}
+
+	return object.Load()
+}
+
+// loadSigningRelay loads the active Relay that signed this request.  Mastodon-style relays forward
+// activities from other servers unchanged, so the relay is identified by the server whose signature
+// was verified, and not by the activity's actor.  It returns FALSE if no active Relay signed the request.
+func loadSigningRelay(context Context, relay *model.Relay) (bool, error) {
+
+	const location = "handler.activitypub_domain.loadSigningRelay"
+
+	// RULE: Requests without a verified signature cannot come from a Relay
+	if context.signerID == "" {
+		return false, nil
+	}
+
+	if err := context.factory.Relay().LoadActiveByHostname(context.session, uri.Hostname(context.signerID), relay); err != nil {
+
+		if derp.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, derp.Wrap(err, location, "Loading relay", context.signerID)
+	}
+
+	return true, nil
+}
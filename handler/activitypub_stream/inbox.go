@@ -8,32 +8,24 @@ import (
 	"github.com/EmissarySocial/emissary/service"
 	"github.com/benpate/data"
 	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
 	"github.com/benpate/steranko"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// PostInbox receives an inbound ActivityPub activity addressed to a Stream actor.
 func PostInbox(ctx *steranko.Context, factory *service.Factory, session data.Session, template *model.Template, stream *model.Stream) error {
 
 	const location = "handler.activitypub_stream.PostInbox"
 
-	// Verify the stream is an ActivityPub actor (based on the Template)
-	actor := template.Actor
-
-	if actor.IsNil() {
+	// RULE: The Stream must be an ActivityPub actor (based on the Template)
+	if template.Actor.IsNil() {
 		return derp.NotFound(location, "Actor not found")
 	}
 
 	// Get an ActivityStream service for the Stream
 	client := factory.ActivityStream().StreamClient(stream.StreamID)
 
-	// Create a new request context for the ActivityPub router
-	context := Context{
-		factory: factory,
-		session: session,
-		stream:  stream,
-		actor:   &actor,
-	}
-
 	// Retrieve the activity through the canonical inbox receive funnel (Stage-1 validators + the
 	// reserved-namespace sanitizer), evaluated against admin-tier rules (NilObjectID) -- Stage 1 of
 	// the block gate (D5).
@@ -43,11 +35,42 @@ func PostInbox(ctx *steranko.Context, factory *service.Factory, session data.Ses
 		return derp.Wrap(err, location, "Receiving ActivityPub request")
 	}
 
+	// Process the activity on behalf of this Stream
+	if err := ReceiveActivity(factory, session, template, stream, activity); err != nil {
+		return derp.Wrap(err, location, "Receiving activity", activity.ID())
+	}
+
+	// Send the response to the client
+	return ctx.String(http.StatusOK, "")
+}
+
+// ReceiveActivity routes an activity that has already been received and verified to the
+// Stream's ActivityPub handlers.  It is shared by the Stream's own inbox and the domain-wide
+// shared inbox.
+func ReceiveActivity(factory *service.Factory, session data.Session, template *model.Template, stream *model.Stream, activity streams.Document) error {
+
+	const location = "handler.activitypub_stream.ReceiveActivity"
+
+	// Verify the stream is an ActivityPub actor (based on the Template)
+	actor := template.Actor
+
+	if actor.IsNil() {
+		return derp.NotFound(location, "Actor not found")
+	}
+
+	// Create a new request context for the ActivityPub router
+	context := Context{
+		factory: factory,
+		session: session,
+		stream:  stream,
+		actor:   &actor,
+	}
+
 	// Route the activity to the appropriate handler
 	if err := streamRouter.Handle(context, activity); err != nil {
 		return derp.Wrap(err, location, "Handling ActivityPub request")
 	}
 
-	// Send the response to the client
-	return ctx.String(http.StatusOK, "")
+	// Success
+	return nil
 }
@@ -5,11 +5,12 @@ import (
 
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/service"
+	"github.com/EmissarySocial/emissary/tools/multikey"
 	"github.com/benpate/data"
 	"github.com/benpate/derp"
 	"github.com/benpate/hannibal/vocab"
-	"github.com/benpate/rosetta/mapof"
 	"github.com/benpate/rosetta/slice"
+	"github.com/benpate/rosetta/sliceof"
 	"github.com/benpate/steranko"
 )
 
@@ -46,23 +47,26 @@ func GetJSONLD(ctx *steranko.Context, factory *service.Factory, session data.Ses
 		return ctx.JSON(http.StatusOK, jsonld)
 	}
 
-	// Try to locate the domain
-	// Try to load the Encryption Key for this Actor
-	keyService := factory.EncryptionKey()
-	key := model.NewEncryptionKey()
-	if err := keyService.LoadByParentID(session, model.EncryptionKeyTypeStream, stream.StreamID, &key); err != nil {
+	// Try to load the Public Key(s) for this Actor
+	publicKey, err := factory.EncryptionKey().PublicKeyJSONLD(session, model.EncryptionKeyTypeStream, stream.StreamID, stream.Permalink())
+
+	if err != nil {
 		return derp.Wrap(err, location, "Loading Public Key", stream.StreamID)
 	}
 
-	// Combine the Actor and the Public Key
-	result := template.Actor.JSONLD(stream)
-	result[vocab.PropertyPublicKey] = mapof.Any{
-		vocab.PropertyID:   stream.Permalink() + "#main-key",
-		vocab.PropertyType: "Key",
-		"owner":            stream.Permalink(),
-		"publicKeyPem":     key.PublicPEM,
+	// Try to load the Ed25519 key(s) for this Actor
+	assertionMethod, err := factory.EncryptionKey().AssertionMethodJSONLD(session, model.EncryptionKeyTypeStream, stream.StreamID, stream.Permalink())
+
+	if err != nil {
+		return derp.Wrap(err, location, "Loading Ed25519 Key", stream.StreamID)
 	}
 
+	// Combine the Actor and the Public Keys
+	result := template.Actor.JSONLD(stream)
+	result[vocab.AtContext] = sliceof.Any{vocab.ContextTypeActivityStreams, vocab.ContextTypeSecurity, multikey.Context}
+	result[vocab.PropertyPublicKey] = publicKey
+	result["assertionMethod"] = assertionMethod
+
 	// Return an ActivityPub response
 	ctx.Response().Header().Set("Content-Type", vocab.ContentTypeActivityPub)
 	return ctx.JSON(http.StatusOK, result)
@@ -69,14 +69,6 @@ func PostInbox(ctx *steranko.Context, factory *service.Factory, session data.Ses
 
 	const location = "handler.activitypub_user.PostInbox"
 
-	// Create a new Context
-	context := Context{
-		context: ctx,
-		factory: factory,
-		session: session,
-		user:    user,
-	}
-
 	// Get ActivityStream service for this User
 	activityService := factory.ActivityStream()
 	client := activityService.UserClient(user.UserID)
@@ -100,6 +92,34 @@ func PostInbox(ctx *steranko.Context, factory *service.Factory, session data.Ses
 		return derp.Wrap(err, location, "Receiving ActivityPub request")
 	}
 
+	// Process the activity on behalf of this User
+	if err := ReceiveActivity(ctx, factory, session, user, activity); err != nil {
+		return derp.Wrap(err, location, "Receiving activity", activity.ID())
+	}
+
+	// Send the response to the client (unless a handler has already responded)
+	if ctx.Response().Committed {
+		return nil
+	}
+
+	return ctx.String(http.StatusOK, "")
+}
+
+// ReceiveActivity processes an activity that has already been received and verified on behalf of
+// a single User: it drops duplicates, applies the Stage-2 rule gate, then saves, notifies, and
+// routes the activity.  It is shared by the User's own inbox and the domain-wide shared inbox.
+func ReceiveActivity(ctx *steranko.Context, factory *service.Factory, session data.Session, user *model.User, activity streams.Document) error {
+
+	const location = "handler.activitypub_user.ReceiveActivity"
+
+	// Create a new Context
+	context := Context{
+		context: ctx,
+		factory: factory,
+		session: session,
+		user:    user,
+	}
+
 	// Drop duplicate activities so retries and multiple deliveries are processed only once
 	if inbox_IsDuplicateActivity(context, activity) {
 		return nil
@@ -117,7 +137,22 @@ func PostInbox(ctx *steranko.Context, factory *service.Factory, session data.Ses
 	// invisible to the sender, gone for the viewer, and idempotent on redelivery (no row, no dedup
 	// hit). Returning here also skips notifications and routing, which follow storage.
 	if inbox_SuppressStorage(disposition, activity) {
-		return ctx.String(http.StatusOK, "")
+		return nil
+	}
+
+	// RULE: Votes in local Polls are tallied, not stored.  They are private Notes addressed to the
+	// Poll's author, so without this they would appear as direct messages (and reply notifications).
+	if activity.Type() == vocab.ActivityTypeCreate {
+
+		isVote, err := context.factory.PollVote().ReceiveVote(context.session, activity)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Receiving poll vote", activity.Value())
+		}
+
+		if isVote {
+			return nil
+		}
 	}
 
 	// Save the activity to the actor's Inbox, stamped with the sender's disposition
@@ -139,8 +174,8 @@ func PostInbox(ctx *steranko.Context, factory *service.Factory, session data.Ses
 		return derp.Wrap(err, location, "Handling ActivityPub request")
 	}
 
-	// Send the response to the client
-	return ctx.String(http.StatusOK, "")
+	// Success
+	return nil
 }
 
 // inbox_IsDuplicateActivity checks if this activity has already been received and processed in the inbox
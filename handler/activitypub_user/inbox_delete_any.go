@@ -12,11 +12,6 @@ func init() {
 
 		const location = "handler.activitypub_user.inbox_DeleteAny"
 
-		// RULE: No further processing required for non-public activities
-		if activity.NotPublic() {
-			return nil
-		}
-
 		// RULE: Actors can only delete objects from their own origin, not evict arbitrary cache entries (D19)
 		if !activitypub.IsSameOrigin(activity.ActorID(), activity.Object().ID()) {
 			return derp.Forbidden(location, "Actor and Object must share the same origin", activity.ActorID(), activity.Object().ID())
@@ -29,6 +24,17 @@ func init() {
 		// If the activity is gone, then it will be removed from the cache.
 		_ = client.Delete(activity.Object().ID())
 
+		// RULE: Only remove documents that are really gone (or that belong to the Actor)
+		if !activitypub.IsConfirmedDelete(client, activity) {
+			return nil
+		}
+
+		// Remove the deleted document from the User's newsfeed.  This includes
+		// non-public documents, such as direct messages and followers-only posts.
+		if err := context.factory.NewsFeed().DeleteByURL(context.session, context.user.UserID, activity.Object().ID(), "Deleted by author"); err != nil {
+			return derp.Wrap(err, location, "Removing deleted document from newsfeed", activity.Object().ID())
+		}
+
 		// Who let the dogs out?
 		return nil
 	})
@@ -0,0 +1,25 @@
+package activitypub_user
+
+import (
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+)
+
+func init() {
+	inboxRouter.Add(vocab.ActivityTypeFlag, vocab.Any, inbox_Flag)
+}
+
+// inbox_Flag handles moderation reports (Flag activities) about this User that are delivered
+// to their inbox.  Unlike other inbox activities, Flags are accepted from any sender -- remote
+// moderators do not follow the Users they report -- and are queued for the Domain Owner, not the User.
+func inbox_Flag(context Context, activity streams.Document) error {
+
+	const location = "handler.activitypub_user.inbox_Flag"
+
+	if err := context.factory.Report().ReceiveFlag(context.session, activity, context.user.UserID); err != nil {
+		return derp.Wrap(err, location, "Receiving Flag", activity.ID())
+	}
+
+	return nil
+}
@@ -52,9 +52,34 @@ func init() {
 			return derp.Wrap(err, location, "Parsing actor", activity)
 		}
 
-		// Try to create a new follower record
 		followerService := context.factory.Follower()
 		follower := model.NewFollower()
+
+		// If the User approves followers manually, then record a follow request and wait
+		// for the User to decide.  Existing (approved) followers are accepted again below.
+		if context.user.IsLocked && !followerService.IsActivityPubFollower(context.session, model.FollowerTypeUser, context.user.UserID, document.ID()) {
+
+			isNewRequest, err := followerService.NewActivityPubFollowRequest(context.session, context.user.UserID, document, activity, &follower)
+
+			if err != nil {
+				return derp.Wrap(err, location, "Creating follow request", context.user)
+			}
+
+			// Repeated Follows from an actor who is already waiting only refresh the request
+			if !isNewRequest {
+				return nil
+			}
+
+			// Create a FOLLOW-REQUEST notification so that the User can approve or reject it.
+			// A notification failure must not fail the request, so report-and-continue.
+			if err := context.factory.Notification().NotifyFollowRequest(context.session, context.user, activity); err != nil {
+				derp.Report(derp.Wrap(err, location, "Creating follow request notification", context.user.UserID))
+			}
+
+			return nil
+		}
+
+		// Try to create a new follower record
 		if err := followerService.NewActivityPubFollower(context.session, model.FollowerTypeUser, context.user.UserID, document, &follower); err != nil {
 			return derp.Wrap(err, location, "Creating new follower", context.user)
 		}
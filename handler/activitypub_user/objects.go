@@ -1,21 +1,65 @@
 package activitypub_user
 
 import (
+	"math"
 	"net/http"
+	"net/url"
 
+	"github.com/EmissarySocial/emissary/handler/activitypub"
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/service"
 	"github.com/benpate/data"
 	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/convert"
 	"github.com/benpate/steranko"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-func GetObjectsCollection(ctx *steranko.Context, factory *service.Factory, session data.Session, user *model.User) error {
+// GetObjectsCollection returns all of the Objects that a User has authored (through the C2S outbox) as
+// an ActivityPub OrderedCollection.  Each page only includes the Objects that the requester is allowed to
+// view.  Results can be filtered with the "type" parameter, and by date range with the "after" and "before"
+// parameters (unix timestamps, in seconds).
+func GetObjectsCollection(ctx *steranko.Context, factory *service.Factory, session data.Session, actorID *string, user *model.User) error {
 
 	const location = "handler.activitypub_user.GetObjectsCollection"
 
-	return derp.NotImplemented(location, "Not Implemented")
+	// Collect filters from the query string.  These are passed through to every page URL.
+	criteria, filters, err := objectsCollectionCriteria(ctx.QueryParams())
+
+	if err != nil {
+		return derp.Wrap(err, location, "Invalid query parameters")
+	}
+
+	collectionURL := user.ActivityPubURL() + "/pub/objects"
+	ctx.Response().Header().Set("Content-Type", "application/activity+json")
+
+	// If the request is for the collection itself, then return a summary and the URL of the first page
+	publishDateString := ctx.QueryParam("publishDate")
+
+	if publishDateString == "" {
+		result := activitypub.Collection(collectionURL)
+		result.First = withFilters(result.First, filters)
+		return ctx.JSON(http.StatusOK, result)
+	}
+
+	// Fall through means that we're looking for a specific page of the collection
+	objectService := factory.Object()
+	publishDate := convert.Int64Default(publishDateString, math.MaxInt64)
+	pageID := fullURL(factory, ctx)
+	pageSize := 60
+
+	// Retrieve a page of Objects that the requester is allowed to view
+	objects, err := objectService.QueryByUserAndDate(session, user.UserID, *actorID, criteria, publishDate, pageSize)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Loading objects")
+	}
+
+	// Return results as an OrderedCollectionPage
+	result := activitypub.CollectionPage(pageID, collectionURL, pageSize, objects)
+	result.Next = withFilters(result.Next, filters)
+	return ctx.JSON(http.StatusOK, result)
 }
 
 func GetObject(ctx *steranko.Context, factory *service.Factory, session data.Session, actorID *string) error {
@@ -58,3 +102,50 @@ func GetObject(ctx *steranko.Context, factory *service.Factory, session data.Ses
 	ctx.Response().Header().Set("Content-Type", "application/activity+json")
 	return ctx.JSON(http.StatusOK, object.Value)
 }
+
+// objectsCollectionCriteria converts the "type", "after" and "before" query parameters into
+// database criteria, and returns the filters that must be passed through to every page URL.
+// "after" and "before" are unix timestamps in SECONDS, but Objects record their createDate
+// in MILLISECONDS, so they are converted here.
+func objectsCollectionCriteria(query url.Values) (exp.Expression, url.Values, error) {
+
+	const location = "handler.activitypub_user.objectsCollectionCriteria"
+
+	var criteria exp.Expression = exp.All()
+	filters := url.Values{}
+
+	if objectType := query.Get("type"); objectType != "" {
+		criteria = criteria.AndEqual("value.type", objectType)
+		filters.Set("type", objectType)
+	}
+
+	if after := query.Get("after"); after != "" {
+		afterDate, ok := convert.Int64Ok(after, 0)
+		if !ok {
+			return nil, nil, derp.BadRequest(location, "Invalid 'after' parameter. Must be a unix timestamp", after)
+		}
+		criteria = criteria.AndGreaterThan("createDate", afterDate*1000)
+		filters.Set("after", after)
+	}
+
+	if before := query.Get("before"); before != "" {
+		beforeDate, ok := convert.Int64Ok(before, 0)
+		if !ok {
+			return nil, nil, derp.BadRequest(location, "Invalid 'before' parameter. Must be a unix timestamp", before)
+		}
+		criteria = criteria.AndLessThan("createDate", beforeDate*1000)
+		filters.Set("before", before)
+	}
+
+	return criteria, filters, nil
+}
+
+// withFilters appends query string filters to a collection page URL
+func withFilters(pageURL string, filters url.Values) string {
+
+	if (pageURL == "") || (len(filters) == 0) {
+		return pageURL
+	}
+
+	return pageURL + "&" + filters.Encode()
+}
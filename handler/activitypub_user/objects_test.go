@@ -0,0 +1,52 @@
+package activitypub_user
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/benpate/exp"
+	"github.com/stretchr/testify/require"
+)
+
+// TestObjectsCollectionCriteria pins the unit conversion for the objects collection: "after"
+// and "before" arrive in unix SECONDS, but Objects store createDate in MILLISECONDS.
+func TestObjectsCollectionCriteria(t *testing.T) {
+
+	query := url.Values{}
+	query.Set("type", "Note")
+	query.Set("after", "1700000000")
+	query.Set("before", "1800000000")
+
+	criteria, filters, err := objectsCollectionCriteria(query)
+	require.Nil(t, err)
+
+	// Collect every predicate in the criteria, by field and operator
+	predicates := make(map[string]any)
+
+	criteria.Match(func(predicate exp.Predicate) bool {
+		predicates[predicate.Field+" "+predicate.Operator] = predicate.Value
+		return true
+	})
+
+	require.Len(t, predicates, 3)
+	require.Equal(t, "Note", predicates["value.type "+exp.OperatorEqual])
+
+	// Both date bounds are converted to milliseconds
+	require.Equal(t, int64(1700000000000), predicates["createDate "+exp.OperatorGreaterThan])
+	require.Equal(t, int64(1800000000000), predicates["createDate "+exp.OperatorLessThan])
+
+	// Filters are passed through to page URLs unchanged (still in seconds)
+	require.Equal(t, "1700000000", filters.Get("after"))
+	require.Equal(t, "1800000000", filters.Get("before"))
+	require.Equal(t, "Note", filters.Get("type"))
+}
+
+// TestObjectsCollectionCriteria_Invalid pins that non-numeric dates are rejected
+func TestObjectsCollectionCriteria_Invalid(t *testing.T) {
+
+	query := url.Values{}
+	query.Set("after", "yesterday")
+
+	_, _, err := objectsCollectionCriteria(query)
+	require.NotNil(t, err)
+}
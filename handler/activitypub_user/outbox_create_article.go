@@ -17,6 +17,11 @@ func outbox_CreateArticle(context Context, activity streams.Document) error {
 
 	const location = "handler.activitypub_user.outbox_CreateArticle"
 
+	// Public posts are published as Streams in the User's outbox
+	if activity.IsPublic() {
+		return outbox_CreateStream(context, activity)
+	}
+
 	// Find the ActivityStream's Document
 	document := activity.Object()
 
@@ -0,0 +1,43 @@
+package activitypub_user
+
+import (
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+)
+
+func init() {
+	outboxRouter.Add(vocab.ActivityTypeDelete, vocab.Any, outbox_DeleteAny)
+}
+
+// outbox_DeleteAny deletes a Stream from the User's outbox.  Activities that do not
+// reference a local Stream are forwarded to the outbox without further processing.
+func outbox_DeleteAny(context Context, activity streams.Document) error {
+
+	const location = "handler.activitypub_user.outbox_DeleteAny"
+
+	objectID := activity.Object().ID()
+
+	// Try to load the Stream being deleted
+	stream := model.NewStream()
+
+	found, err := loadOutboxStream(context, objectID, &stream)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Loading stream", objectID)
+	}
+
+	if !found {
+		return outbox_Wildcard(context, activity)
+	}
+
+	// Delete the Stream (which sends "Delete" activities to all followers)
+	if err := context.factory.Stream().DeleteOutboxPost(context.session, context.user, &stream, "Deleted via ActivityPub Outbox"); err != nil {
+		return derp.Wrap(err, location, "Deleting stream", stream)
+	}
+
+	return context.context.NoContent(http.StatusAccepted)
+}
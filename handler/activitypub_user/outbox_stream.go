@@ -0,0 +1,93 @@
+package activitypub_user
+
+import (
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+)
+
+// outbox_CreateStream maps a public Note or Article onto a new Stream in the User's outbox,
+// using the Template that the User has chosen for posts from client apps.  The Stream service
+// sends the "Create" activity to the User's followers, just like the web editor and the
+// Mastodon API do.
+func outbox_CreateStream(context Context, activity streams.Document) error {
+
+	const location = "handler.activitypub_user.outbox_CreateStream"
+
+	document := activity.Object()
+	socialRole := vocab.ObjectTypeNote
+
+	if document.Type() == vocab.ObjectTypeArticle {
+		socialRole = vocab.ObjectTypeArticle
+	}
+
+	// Create a new Stream in the User's outbox
+	streamService := context.factory.Stream()
+	stream, err := streamService.NewOutboxPost(context.session, context.user, socialRole)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Creating stream")
+	}
+
+	streamService.SetOutboxPostDocument(&stream, document)
+
+	// Use the same State that the Template publishes into from the web editor
+	stateID, err := streamService.OutboxPostState(&stream)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Finding publish state", stream)
+	}
+
+	// Save and publish the Stream (which triggers delivery to all followers)
+	if err := streamService.PublishOutboxPost(context.session, context.user, &stream, stateID, false); err != nil {
+		return derp.Wrap(err, location, "Publishing stream", stream)
+	}
+
+	// Write the response to the client
+	context.context.Response().Header().Set("Location", stream.ActivityPubURL())
+	return context.context.NoContent(http.StatusCreated)
+}
+
+// outbox_UpdateStream applies an "Update" activity to an existing Stream, and republishes it
+// to the User's followers.
+func outbox_UpdateStream(context Context, activity streams.Document, stream *model.Stream) error {
+
+	const location = "handler.activitypub_user.outbox_UpdateStream"
+
+	streamService := context.factory.Stream()
+	streamService.SetOutboxPostDocument(stream, activity.Object())
+
+	// Save and republish the Stream (which sends "Update" activities to all followers)
+	if err := streamService.PublishOutboxPost(context.session, context.user, stream, stream.StateID, true); err != nil {
+		return derp.Wrap(err, location, "Publishing stream", stream)
+	}
+
+	return context.context.NoContent(http.StatusAccepted)
+}
+
+// loadOutboxStream tries to load a Stream owned by the current User.  It returns FALSE
+// (with no error) if the URL does not identify a local Stream, so that callers can fall
+// back to handling the document as a private Object.
+func loadOutboxStream(context Context, url string, stream *model.Stream) (bool, error) {
+
+	const location = "handler.activitypub_user.loadOutboxStream"
+
+	if err := context.factory.Stream().LoadByURL(context.session, url, stream); err != nil {
+
+		if derp.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, derp.Wrap(err, location, "Loading stream", url)
+	}
+
+	// RULE: The current User must be the author of the Stream
+	if !stream.IsAuthor(context.user.UserID) {
+		return false, derp.Forbidden(location, "Current User must own the Stream", "url: "+url)
+	}
+
+	return true, nil
+}
@@ -19,6 +19,15 @@ func outbox_UpdateArticle(context Context, activity streams.Document) error {
 
 	document := activity.Object()
 
+	// If the Document is a Stream in the User's outbox, then update it in place
+	stream := model.NewStream()
+
+	if found, err := loadOutboxStream(context, document.ID(), &stream); err != nil {
+		return derp.Wrap(err, location, "Loading stream", document.ID())
+	} else if found {
+		return outbox_UpdateStream(context, activity, &stream)
+	}
+
 	// Parse the UserID and ObjectID from the Document's URL
 	locatorService := context.factory.Locator()
 	userID, objectID, err := locatorService.ParseObject(document.ID())
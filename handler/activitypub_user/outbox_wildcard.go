@@ -35,10 +35,10 @@ func putActivityIntoOutbox(context Context, activity streams.Document) error {
 	// Calculate all recipients of this Activity
 	recipients := activity.Recipients()
 
-	// For now, we don't support public notes, so return an error
-	// In the future, we'll add more rules that map public-facing posts to Streams.
+	// RULE: Public Notes and Articles are mapped to Streams by their specific handlers.
+	// Any other public activity is not supported at this time.
 	if activity.IsPublic() {
-		return derp.NotImplemented(location, "Public notes are not supported at this time.")
+		return derp.NotImplemented(location, "Public activities are only supported for Notes and Articles.")
 	}
 
 	// Collect services
@@ -76,6 +76,17 @@ func buildAdmin_GetBuilder(ctx *steranko.Context, factory *service.Factory, sess
 	// Create the correct builder for this controller
 	switch template.Model {
 
+	case "Blocklist":
+		blocklist := model.NewBlocklist()
+
+		if !objectID.IsZero() {
+			if err := factory.Blocklist().LoadByID(session, objectID, &blocklist); err != nil {
+				return nil, derp.Wrap(err, location, "Loading Blocklist", objectID)
+			}
+		}
+
+		return build.NewBlocklist(factory, session, ctx.Request(), ctx.Response(), template, &blocklist, actionID)
+
 	case "Domain", "Search", "SSO", "Followers", "Following":
 		return build.NewDomain(factory, session, ctx.Request(), ctx.Response(), template, actionID)
 
@@ -94,6 +105,28 @@ func buildAdmin_GetBuilder(ctx *steranko.Context, factory *service.Factory, sess
 
 		return build.NewGroup(factory, session, ctx.Request(), ctx.Response(), template, &group, actionID)
 
+	case "Relay":
+		relay := model.NewRelay()
+
+		if !objectID.IsZero() {
+			if err := factory.Relay().LoadByID(session, objectID, &relay); err != nil {
+				return nil, derp.Wrap(err, location, "Loading Relay", objectID)
+			}
+		}
+
+		return build.NewRelay(factory, session, ctx.Request(), ctx.Response(), template, &relay, actionID)
+
+	case "Report":
+		report := model.NewReport()
+
+		if !objectID.IsZero() {
+			if err := factory.Report().LoadByID(session, objectID, &report); err != nil {
+				return nil, derp.Wrap(err, location, "Loading Report", objectID)
+			}
+		}
+
+		return build.NewReport(factory, session, ctx.Request(), ctx.Response(), template, &report, actionID)
+
 	case "Rule":
 
 		rule := model.NewRule()
@@ -152,6 +185,6 @@ func buildAdmin_GetBuilder(ctx *steranko.Context, factory *service.Factory, sess
 		return build.NewWebhook(factory, session, ctx.Request(), ctx.Response(), template, &webhook, actionID)
 
 	default:
-		return nil, derp.NotFound(location, "Template MODEL must be one of: 'Rule', 'Domain', 'Syndication', 'Group', 'Stream', 'Tag', or 'User'", template.Model)
+		return nil, derp.NotFound(location, "Template MODEL must be one of: 'Blocklist', 'Relay', 'Report', 'Rule', 'Domain', 'Syndication', 'Group', 'Stream', 'Tag', 'User', or 'Webhook'", template.Model)
 	}
 }
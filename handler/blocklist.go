@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/steranko"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetBlocklistExport downloads every server-wide DOMAIN and ACTOR Rule as a CSV file that
+// other Emissary and Mastodon servers can import (the `domain_blocks.csv` format).
+func GetBlocklistExport(ctx *steranko.Context, factory *service.Factory, session data.Session) error {
+
+	const location = "handler.GetBlocklistExport"
+
+	criteria := exp.Equal("userId", primitive.NilObjectID).
+		AndIn("type", []string{model.RuleTypeDomain, model.RuleTypeActor})
+
+	rules, err := factory.Rule().Query(session, criteria, option.SortAsc("trigger"))
+
+	if err != nil {
+		return derp.Wrap(err, location, "Querying server-wide Rules")
+	}
+
+	header := ctx.Response().Header()
+	header.Set("Content-Type", "text/csv; charset=utf-8")
+	header.Set("Content-Disposition", `attachment; filename="`+factory.Hostname()+`-blocklist.csv"`)
+	ctx.Response().WriteHeader(http.StatusOK)
+
+	if err := model.WriteBlocklistCSV(ctx.Response(), rules); err != nil {
+		return derp.Wrap(err, location, "Writing CSV file")
+	}
+
+	return nil
+}
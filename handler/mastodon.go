@@ -1,9 +1,15 @@
 package handler
 
 import (
+	"strings"
+
 	"github.com/EmissarySocial/emissary/handler/mastodon"
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/server"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/steranko"
 	"github.com/benpate/toot"
 )
 
@@ -135,7 +141,7 @@ func Mastodon(serverFactory *server.Factory) toot.API[model.Authorization] {
 		PostMarker: mastodon.PostMarker(serverFactory),
 
 		// https://docs.joinmastodon.org/methods/media/
-		PostMedia: mastodon.PostMedia(serverFactory),
+		// Media uploads are multipart requests, so they are routed directly (see handler.PostMastodonMedia)
 
 		// https://docs.joinmastodon.org/methods/mutes/
 		GetMutes: mastodon.GetMutes(serverFactory),
@@ -220,3 +226,46 @@ func Mastodon(serverFactory *server.Factory) toot.API[model.Authorization] {
 		GetTrends_Links:    mastodon.GetTrends_Links(serverFactory),
 	}
 }
+
+// mastodonAccessToken returns the access token sent with a Mastodon API request, either
+// in the Authorization header or in the "access_token" query parameter.
+func mastodonAccessToken(ctx *steranko.Context) string {
+
+	if tokenString := strings.TrimPrefix(ctx.Request().Header.Get("Authorization"), "Bearer "); tokenString != "" {
+		return tokenString
+	}
+
+	return ctx.QueryParam("access_token")
+}
+
+// mastodonAuthorization validates a Mastodon API access token.  Tokens must come from
+// an OAuth grant that has not been revoked.
+func mastodonAuthorization(factory *service.Factory, session data.Session, tokenString string) (model.Authorization, error) {
+
+	const location = "handler.mastodonAuthorization"
+
+	token, err := factory.JWT().ParseString(tokenString)
+
+	if err != nil {
+		return model.Authorization{}, derp.Wrap(err, location, "Invalid access token", derp.WithUnauthorized())
+	}
+
+	authorization, ok := token.Claims.(*model.Authorization)
+
+	if !token.Valid || !ok || authorization.NotAuthenticated() {
+		return model.Authorization{}, derp.Unauthorized(location, "Invalid access token")
+	}
+
+	// RULE: Access token must come from an OAuth grant that has not been revoked
+	if authorization.OAuthUserTokenID.IsZero() {
+		return model.Authorization{}, derp.Unauthorized(location, "Access token is not an OAuth grant")
+	}
+
+	oauthUserToken := model.NewOAuthUserToken()
+
+	if err := factory.OAuthUserToken().LoadByID(session, authorization.UserID, authorization.OAuthUserTokenID, &oauthUserToken); err != nil {
+		return model.Authorization{}, derp.Wrap(err, location, "Loading OAuthUserToken", derp.WithUnauthorized())
+	}
+
+	return *authorization, nil
+}
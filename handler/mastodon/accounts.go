@@ -1,12 +1,17 @@
 package mastodon
 
 import (
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/server"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
 	"github.com/benpate/data/option"
 	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/slice"
 	"github.com/benpate/toot"
 	"github.com/benpate/toot/object"
 	"github.com/benpate/toot/txn"
@@ -145,6 +150,7 @@ func PatchAccount_UpdateCredentials(serverFactory *server.Factory) func(model.Au
 		user.DisplayName = t.DisplayName
 		user.Note = t.Note
 		user.IsPublic = t.Discoverable
+		user.IsLocked = t.Locked
 
 		if err := userService.Save(session, &user, "Updated via Mastodon API"); err != nil {
 			return object.Account{}, derp.Wrap(err, location, "Saving user")
@@ -230,8 +236,14 @@ func GetAccount_Statuses(serverFactory *server.Factory) func(model.Authorization
 
 		// TODO: HIGH: Work out how to set response headers here for additional pagination
 
-		// Return posts as toot.Status(es)
-		return getSliceOfToots(streams), getPageInfo(streams), nil
+		// Apply the caller's "account" Filters, and return posts as toot.Status(es)
+		statuses, err := getFilteredStreams(factory, session, auth.UserID, model.FilterContextAccount, streams)
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Applying filters")
+		}
+
+		return statuses, getPageInfo(streams), nil
 	}
 }
 
@@ -502,7 +514,51 @@ func PostAccount_Pin(serverFactory *server.Factory) func(model.Authorization, tx
 	const location = "handler.mastodon_PostAccount_Pin"
 
 	return func(auth model.Authorization, t txn.PostAccount_Pin) (object.Relationship, error) {
-		return object.Relationship{}, derp.NotImplemented(location)
+
+		// Get the Domain factory for this request
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return object.Relationship{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.Relationship{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the User
+		userService := factory.User()
+		user := model.NewUser()
+
+		if err := userService.LoadByID(session, auth.UserID, &user); err != nil {
+			return object.Relationship{}, derp.Wrap(err, location, "Unrecognized User")
+		}
+
+		// RULE: Only accounts that the User follows can be endorsed
+		relationship, err := userService.Relationship(session, &user, t.ID)
+
+		if err != nil {
+			return object.Relationship{}, derp.Wrap(err, location, "Calculating relationship", t.ID)
+		}
+
+		if !relationship.Following {
+			return object.Relationship{}, derp.Validation("You must follow an account before featuring it on your profile", t.ID)
+		}
+
+		// Feature the account on the User's profile
+		user.AddEndorsement(t.ID)
+
+		if err := userService.Save(session, &user, "Endorsed via Mastodon API"); err != nil {
+			return object.Relationship{}, derp.Wrap(err, location, "Saving user")
+		}
+
+		relationship.Endorsed = true
+		return relationship.Toot(), nil
 	}
 }
 
@@ -511,7 +567,39 @@ func PostAccount_Unpin(serverFactory *server.Factory) func(model.Authorization,
 	const location = "handler.mastodon_PostAccount_Unpin"
 
 	return func(auth model.Authorization, t txn.PostAccount_Unpin) (object.Relationship, error) {
-		return object.Relationship{}, derp.NotImplemented(location)
+
+		// Get the Domain factory for this request
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return object.Relationship{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.Relationship{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the User
+		userService := factory.User()
+		user := model.NewUser()
+
+		if err := userService.LoadByID(session, auth.UserID, &user); err != nil {
+			return object.Relationship{}, derp.Wrap(err, location, "Unrecognized User")
+		}
+
+		// Remove the account from the User's profile
+		user.RemoveEndorsement(t.ID)
+
+		if err := userService.Save(session, &user, "Unendorsed via Mastodon API"); err != nil {
+			return object.Relationship{}, derp.Wrap(err, location, "Saving user")
+		}
+
+		return getRelationship(userService, session, &user, t.ID)
 	}
 }
 
@@ -520,7 +608,39 @@ func PostAccount_Note(serverFactory *server.Factory) func(model.Authorization, t
 	const location = "handler.mastodon_PostAccount_Note"
 
 	return func(auth model.Authorization, t txn.PostAccount_Note) (object.Relationship, error) {
-		return object.Relationship{}, derp.NotImplemented(location)
+
+		// Get the Domain factory for this request
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return object.Relationship{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.Relationship{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the User
+		userService := factory.User()
+		user := model.NewUser()
+
+		if err := userService.LoadByID(session, auth.UserID, &user); err != nil {
+			return object.Relationship{}, derp.Wrap(err, location, "Unrecognized User")
+		}
+
+		// Update (or remove) the private note.  Notes are only visible to the User who wrote them.
+		user.SetAccountNote(t.ID, strings.TrimSpace(t.Comment))
+
+		if err := userService.Save(session, &user, "Account note updated via Mastodon API"); err != nil {
+			return object.Relationship{}, derp.Wrap(err, location, "Saving user")
+		}
+
+		return getRelationship(userService, session, &user, t.ID)
 	}
 }
 
@@ -529,7 +649,46 @@ func GetAccount_Relationships(serverFactory *server.Factory) func(model.Authoriz
 	const location = "handler.mastodon_GetAccount_Relationships"
 
 	return func(auth model.Authorization, t txn.GetAccount_Relationships) ([]object.Relationship, error) {
-		return nil, derp.NotImplemented(location)
+
+		// Get the Domain factory for this request
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the User
+		userService := factory.User()
+		user := model.NewUser()
+
+		if err := userService.LoadByID(session, auth.UserID, &user); err != nil {
+			return nil, derp.Wrap(err, location, "Unrecognized User")
+		}
+
+		// Calculate a relationship for each requested account, in the order requested
+		result := make([]object.Relationship, 0, len(t.IDs))
+
+		for _, actorID := range t.IDs {
+
+			relationship, err := getRelationship(userService, session, &user, actorID)
+
+			if err != nil {
+				return nil, derp.Wrap(err, location, "Calculating relationship", actorID)
+			}
+
+			result = append(result, relationship)
+		}
+
+		return result, nil
 	}
 }
 
@@ -538,7 +697,49 @@ func GetAccount_FamiliarFollowers(serverFactory *server.Factory) func(model.Auth
 	const location = "handler.mastodon_GetAccount_FamiliarFollowers"
 
 	return func(auth model.Authorization, t txn.GetAccount_FamiliarFollowers) (object.FamiliarFollowers, error) {
-		return nil, derp.NotImplemented(location)
+
+		// Get the Domain factory for this request
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the User
+		userService := factory.User()
+		user := model.NewUser()
+
+		if err := userService.LoadByID(session, auth.UserID, &user); err != nil {
+			return nil, derp.Wrap(err, location, "Unrecognized User")
+		}
+
+		// Find familiar followers for each requested account, in the order requested
+		result := make(object.FamiliarFollowers, 0, len(t.IDs))
+
+		for _, actorID := range t.IDs {
+
+			followers, err := userService.FamiliarFollowers(session, &user, actorID)
+
+			if err != nil {
+				return nil, derp.Wrap(err, location, "Finding familiar followers", actorID)
+			}
+
+			result = append(result, object.FamiliarFollowers{{
+				ID:       actorID,
+				Accounts: slice.Map(followers, model.PersonLink.Toot),
+			}}...)
+		}
+
+		return result, nil
 	}
 }
 
@@ -548,7 +749,54 @@ func GetAccount_Search(serverFactory *server.Factory) func(model.Authorization,
 	const location = "handler.mastodon_GetAccount_Search"
 
 	return func(auth model.Authorization, t txn.GetAccount_Search) ([]object.Account, toot.PageInfo, error) {
-		return nil, toot.PageInfo{}, derp.NotImplemented(location)
+
+		// Get the Domain factory for this request
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Search cached actors.  Only "resolve" requests may load new actors from the Internet.
+		activityService := factory.ActivityStream()
+		search := activityService.QueryCachedActors
+
+		if t.Resolve {
+			search = activityService.QueryActors
+		}
+
+		actors, err := search(strings.TrimSpace(t.Q))
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Searching actors", t.Q)
+		}
+
+		// RULE: Limit results to accounts that the User follows, if requested
+		if t.Following {
+
+			session, cancel, err := factory.Session(time.Minute)
+
+			if err != nil {
+				return nil, toot.PageInfo{}, derp.Wrap(err, location, "Creating session")
+			}
+
+			defer cancel()
+
+			followingService := factory.Following()
+			actors = slices.DeleteFunc(actors, func(actor model.ActorSummary) bool {
+				following := model.NewFollowing()
+				return followingService.LoadByURL(session, auth.UserID, actor.ID, &following) != nil
+			})
+		}
+
+		// Apply offset and limit
+		actors = actors[min(max(t.Offset, 0), len(actors)):]
+
+		if (t.Limit > 0) && (len(actors) > t.Limit) {
+			actors = actors[:t.Limit]
+		}
+
+		return slice.Map(actors, model.ActorSummary.Toot), toot.PageInfo{}, nil
 	}
 }
 
@@ -574,16 +822,26 @@ func GetAccount_Lookup(serverFactory *server.Factory) func(model.Authorization,
 			return object.Account{}, derp.Wrap(err, location, "Loading document")
 		}
 
-		// Map the ActivityStream to a Mastodon Account
-		// TODO: LOW: This should probably be moved somewhere else
-		result := object.Account{
-			ID:          document.ID(),
-			Acct:        t.Acct,
-			DisplayName: document.Name(),
-			URL:         document.URL(),
+		// RULE: Only actors can be returned as Accounts
+		if !document.IsActor() {
+			return object.Account{}, derp.NotFound(location, "Account not found", t.Acct)
 		}
 
-		// Success.
-		return result, derp.NotImplemented(location)
+		// Map the ActivityStream to a Mastodon Account
+		return getAccountFromDocument(document), nil
 	}
 }
+
+// getRelationship calculates the relationship between a User and another account, as a Mastodon Relationship
+func getRelationship(userService *service.User, session data.Session, user *model.User, actorID string) (object.Relationship, error) {
+
+	const location = "handler.mastodon.getRelationship"
+
+	relationship, err := userService.Relationship(session, user, actorID)
+
+	if err != nil {
+		return object.Relationship{}, derp.Wrap(err, location, "Calculating relationship", actorID)
+	}
+
+	return relationship.Toot(), nil
+}
@@ -1,18 +1,56 @@
 package mastodon
 
 import (
+	"time"
+
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
 	"github.com/benpate/toot/object"
 	"github.com/benpate/toot/txn"
 )
 
+// bookmarksPageSize is the maximum number of Bookmarks returned in a single page
+const bookmarksPageSize = 40
+
 // https://docs.joinmastodon.org/methods/bookmarks/
 func GetBookmarks(serverFactory *server.Factory) func(model.Authorization, txn.GetBookmarks) ([]object.Status, error) {
 
-	// const location = "handler.mastodon_GetBookmarks"
+	const location = "handler.mastodon_GetBookmarks"
 
 	return func(auth model.Authorization, t txn.GetBookmarks) ([]object.Status, error) {
-		return []object.Status{}, nil
+
+		// Get the Domain factory for this request
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return []object.Status{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return []object.Status{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Query the database
+		bookmarks, err := factory.Bookmark().QueryByUser(session, auth.UserID, queryExpression(t), option.MaxRows(bookmarksPageSize))
+
+		if err != nil {
+			return []object.Status{}, derp.Wrap(err, location, "Querying bookmarks")
+		}
+
+		// Load each bookmarked document so that clients receive complete Statuses
+		result := make([]object.Status, len(bookmarks))
+
+		for index, bookmark := range bookmarks {
+			result[index] = getBookmarkStatus(factory, auth.UserID, bookmark, true)
+		}
+
+		return result, nil
 	}
 }
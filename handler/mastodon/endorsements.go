@@ -1,8 +1,11 @@
 package mastodon
 
 import (
+	"time"
+
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/derp"
 	"github.com/benpate/toot"
 	"github.com/benpate/toot/object"
 	"github.com/benpate/toot/txn"
@@ -11,7 +14,49 @@ import (
 // https://docs.joinmastodon.org/methods/endorsements/
 func GetEndorsements(serverFactory *server.Factory) func(model.Authorization, txn.GetEndorsements) ([]object.Account, toot.PageInfo, error) {
 
-	return func(model.Authorization, txn.GetEndorsements) ([]object.Account, toot.PageInfo, error) {
-		return []object.Account{}, toot.PageInfo{}, nil
+	const location = "handler.mastodon.GetEndorsements"
+
+	return func(auth model.Authorization, t txn.GetEndorsements) ([]object.Account, toot.PageInfo, error) {
+
+		// Get the Domain factory for this request
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the User
+		user := model.NewUser()
+
+		if err := factory.User().LoadByID(session, auth.UserID, &user); err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Unrecognized User")
+		}
+
+		// Load each endorsed account (usually from the ActivityStream cache)
+		client := factory.ActivityStream().UserClient(auth.UserID)
+		result := make([]object.Account, 0, len(user.Endorsements))
+
+		for _, actorID := range user.Endorsements {
+
+			document, err := client.Load(actorID)
+
+			if err != nil {
+				derp.Report(derp.Wrap(err, location, "Loading endorsed account", actorID))
+				continue
+			}
+
+			result = append(result, getAccountFromDocument(document))
+		}
+
+		return result, toot.PageInfo{}, nil
 	}
 }
@@ -1,144 +1,1010 @@
 package mastodon
 
 import (
+	"time"
+
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/server"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
 	"github.com/benpate/derp"
 	"github.com/benpate/toot"
 	"github.com/benpate/toot/object"
 	"github.com/benpate/toot/txn"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // https://docs.joinmastodon.org/methods/filters/
 func GetFilters(serverFactory *server.Factory) func(model.Authorization, txn.GetFilters) ([]object.Filter, error) {
 
-	return func(model.Authorization, txn.GetFilters) ([]object.Filter, error) {
-		return []object.Filter{}, nil
+	const location = "handler.mastodon.GetFilters"
+
+	return func(auth model.Authorization, t txn.GetFilters) ([]object.Filter, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return []object.Filter{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return []object.Filter{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Query all of the User's Filters
+		filters, err := factory.Filter().QueryByUser(session, auth.UserID)
+
+		if err != nil {
+			return []object.Filter{}, derp.Wrap(err, location, "Querying filters")
+		}
+
+		return getSliceOfToots(filters), nil
 	}
 }
 
+// https://docs.joinmastodon.org/methods/filters/#get-one
 func GetFilter(serverFactory *server.Factory) func(model.Authorization, txn.GetFilter) (object.Filter, error) {
 
-	return func(model.Authorization, txn.GetFilter) (object.Filter, error) {
-		return object.Filter{}, nil
+	const location = "handler.mastodon.GetFilter"
+
+	return func(auth model.Authorization, t txn.GetFilter) (object.Filter, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return object.Filter{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.Filter{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the requested Filter
+		filter := model.NewFilter()
+
+		if err := factory.Filter().LoadByToken(session, auth.UserID, t.ID, &filter); err != nil {
+			return object.Filter{}, derp.Wrap(err, location, "Loading filter")
+		}
+
+		return filter.Toot(), nil
 	}
 }
 
+// https://docs.joinmastodon.org/methods/filters/#create
 func PostFilter(serverFactory *server.Factory) func(model.Authorization, txn.PostFilter) (object.Filter, error) {
 
-	return func(model.Authorization, txn.PostFilter) (object.Filter, error) {
-		return object.Filter{}, derp.NotImplemented("handler.mastodon.PostFilter")
+	const location = "handler.mastodon.PostFilter"
+
+	return func(auth model.Authorization, t txn.PostFilter) (object.Filter, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return object.Filter{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.Filter{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Create the new Filter
+		filter := model.NewFilter()
+		filter.UserID = auth.UserID
+		filter.Title = t.Title
+		filter.Contexts = t.Context
+		filter.ExpireDate = filterExpireDate(int64(t.ExpiresIn))
+
+		if t.FilterAction != "" {
+			filter.Action = t.FilterAction
+		}
+
+		for _, attributes := range t.KeywordsAttributes {
+			keyword := model.NewFilterKeyword()
+			keyword.Keyword = attributes.Keyword
+			keyword.WholeWord = attributes.WholeWord
+			filter.SetKeyword(keyword)
+		}
+
+		// Save the Filter
+		if err := factory.Filter().Save(session, &filter, "Created via Mastodon API"); err != nil {
+			return object.Filter{}, derp.Wrap(err, location, "Saving filter")
+		}
+
+		return filter.Toot(), nil
 	}
 }
 
+// https://docs.joinmastodon.org/methods/filters/#update
 func PutFilter(serverFactory *server.Factory) func(model.Authorization, txn.PutFilter) (object.Filter, error) {
 
-	return func(model.Authorization, txn.PutFilter) (object.Filter, error) {
-		return object.Filter{}, derp.NotImplemented("handler.mastodon.PutFilter")
+	const location = "handler.mastodon.PutFilter"
+
+	return func(auth model.Authorization, t txn.PutFilter) (object.Filter, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return object.Filter{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.Filter{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the requested Filter
+		filterService := factory.Filter()
+		filter := model.NewFilter()
+
+		if err := filterService.LoadByToken(session, auth.UserID, t.ID, &filter); err != nil {
+			return object.Filter{}, derp.Wrap(err, location, "Loading filter")
+		}
+
+		// Update only the values that were provided
+		if t.Title != "" {
+			filter.Title = t.Title
+		}
+
+		if len(t.Context) > 0 {
+			filter.Contexts = t.Context
+		}
+
+		if t.FilterAction != "" {
+			filter.Action = t.FilterAction
+		}
+
+		if t.ExpiresIn > 0 {
+			filter.ExpireDate = filterExpireDate(int64(t.ExpiresIn))
+		}
+
+		// Add, update, or remove keywords
+		for _, attributes := range t.KeywordsAttributes {
+
+			// New keywords have no ID
+			if attributes.ID == "" {
+				keyword := model.NewFilterKeyword()
+				keyword.Keyword = attributes.Keyword
+				keyword.WholeWord = attributes.WholeWord
+				filter.SetKeyword(keyword)
+				continue
+			}
+
+			keywordID, err := primitive.ObjectIDFromHex(attributes.ID)
+
+			if err != nil {
+				return object.Filter{}, derp.BadRequest(location, "Invalid KeywordID", attributes.ID)
+			}
+
+			if attributes.Destroy {
+				filter.RemoveKeyword(keywordID)
+				continue
+			}
+
+			keyword, exists := filter.KeywordByID(keywordID)
+
+			if !exists {
+				return object.Filter{}, derp.NotFound(location, "Keyword not found", attributes.ID)
+			}
+
+			keyword.Keyword = attributes.Keyword
+			keyword.WholeWord = attributes.WholeWord
+			filter.SetKeyword(keyword)
+		}
+
+		// Save the Filter
+		if err := filterService.Save(session, &filter, "Updated via Mastodon API"); err != nil {
+			return object.Filter{}, derp.Wrap(err, location, "Saving filter")
+		}
+
+		return filter.Toot(), nil
 	}
 }
 
+// https://docs.joinmastodon.org/methods/filters/#delete
 func DeleteFilter(serverFactory *server.Factory) func(model.Authorization, txn.DeleteFilter) (struct{}, error) {
 
-	return func(model.Authorization, txn.DeleteFilter) (struct{}, error) {
-		return struct{}{}, derp.NotImplemented("handler.mastodon.DeleteFilter")
+	const location = "handler.mastodon.DeleteFilter"
+
+	return func(auth model.Authorization, t txn.DeleteFilter) (struct{}, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the requested Filter
+		filterService := factory.Filter()
+		filter := model.NewFilter()
+
+		if err := filterService.LoadByToken(session, auth.UserID, t.ID, &filter); err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Loading filter")
+		}
+
+		// Delete the Filter
+		if err := filterService.Delete(session, &filter, "Deleted via Mastodon API"); err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Deleting filter")
+		}
+
+		return struct{}{}, nil
 	}
 }
 
+// https://docs.joinmastodon.org/methods/filters/#keywords-get
 func GetFilter_Keywords(serverFactory *server.Factory) func(model.Authorization, txn.GetFilter_Keywords) ([]string, error) {
 
-	return func(model.Authorization, txn.GetFilter_Keywords) ([]string, error) {
-		return []string{}, nil
+	const location = "handler.mastodon.GetFilter_Keywords"
+
+	return func(auth model.Authorization, t txn.GetFilter_Keywords) ([]string, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return []string{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return []string{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the requested Filter
+		filter := model.NewFilter()
+
+		if err := factory.Filter().LoadByToken(session, auth.UserID, t.ID, &filter); err != nil {
+			return []string{}, derp.Wrap(err, location, "Loading filter")
+		}
+
+		// Return the keywords in this Filter
+		result := make([]string, len(filter.Keywords))
+
+		for index, keyword := range filter.Keywords {
+			result[index] = keyword.Keyword
+		}
+
+		return result, nil
 	}
 }
 
+// https://docs.joinmastodon.org/methods/filters/#keywords-create
 func PostFilter_Keyword(serverFactory *server.Factory) func(model.Authorization, txn.PostFilter_Keyword) (struct{}, error) {
 
-	return func(model.Authorization, txn.PostFilter_Keyword) (struct{}, error) {
-		return struct{}{}, derp.NotImplemented("handler.mastodon.PostFilter_Keyword")
+	const location = "handler.mastodon.PostFilter_Keyword"
+
+	return func(auth model.Authorization, t txn.PostFilter_Keyword) (struct{}, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the requested Filter
+		filterService := factory.Filter()
+		filter := model.NewFilter()
+
+		if err := filterService.LoadByToken(session, auth.UserID, t.ID, &filter); err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Loading filter")
+		}
+
+		// Add the new keyword
+		keyword := model.NewFilterKeyword()
+		keyword.Keyword = t.Keyword
+		keyword.WholeWord = t.WholeWord
+		filter.SetKeyword(keyword)
+
+		if err := filterService.Save(session, &filter, "Keyword added via Mastodon API"); err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Saving filter")
+		}
+
+		return struct{}{}, nil
 	}
 }
 
+// https://docs.joinmastodon.org/methods/filters/#keywords-get-one
 func GetFilter_Keyword(serverFactory *server.Factory) func(model.Authorization, txn.GetFilter_Keyword) (object.FilterKeyword, error) {
 
-	return func(model.Authorization, txn.GetFilter_Keyword) (object.FilterKeyword, error) {
-		return object.FilterKeyword{}, nil
+	const location = "handler.mastodon.GetFilter_Keyword"
+
+	return func(auth model.Authorization, t txn.GetFilter_Keyword) (object.FilterKeyword, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return object.FilterKeyword{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.FilterKeyword{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the Filter that contains this keyword
+		filter := model.NewFilter()
+		keyword, err := factory.Filter().LoadByKeywordToken(session, auth.UserID, t.ID, &filter)
+
+		if err != nil {
+			return object.FilterKeyword{}, derp.Wrap(err, location, "Loading filter keyword")
+		}
+
+		return keyword.Toot(), nil
 	}
 }
 
+// https://docs.joinmastodon.org/methods/filters/#keywords-update
 func PutFilter_Keyword(serverFactory *server.Factory) func(model.Authorization, txn.PutFilter_Keyword) (object.FilterKeyword, error) {
 
-	return func(model.Authorization, txn.PutFilter_Keyword) (object.FilterKeyword, error) {
-		return object.FilterKeyword{}, derp.NotImplemented("handler.mastodon.PutFilter_Keyword")
+	const location = "handler.mastodon.PutFilter_Keyword"
+
+	return func(auth model.Authorization, t txn.PutFilter_Keyword) (object.FilterKeyword, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return object.FilterKeyword{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.FilterKeyword{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the Filter that contains this keyword
+		filterService := factory.Filter()
+		filter := model.NewFilter()
+		keyword, err := filterService.LoadByKeywordToken(session, auth.UserID, t.ID, &filter)
+
+		if err != nil {
+			return object.FilterKeyword{}, derp.Wrap(err, location, "Loading filter keyword")
+		}
+
+		// Update the keyword
+		keyword.Keyword = t.Keyword
+		keyword.WholeWord = t.WholeWord
+		filter.SetKeyword(keyword)
+
+		if err := filterService.Save(session, &filter, "Keyword updated via Mastodon API"); err != nil {
+			return object.FilterKeyword{}, derp.Wrap(err, location, "Saving filter")
+		}
+
+		return keyword.Toot(), nil
 	}
 }
 
+// https://docs.joinmastodon.org/methods/filters/#keywords-delete
 func DeleteFilter_Keyword(serverFactory *server.Factory) func(model.Authorization, txn.DeleteFilter_Keyword) (struct{}, error) {
 
-	return func(model.Authorization, txn.DeleteFilter_Keyword) (struct{}, error) {
-		return struct{}{}, derp.NotImplemented("handler.mastodon.DeleteFilter_Keyword")
+	const location = "handler.mastodon.DeleteFilter_Keyword"
+
+	return func(auth model.Authorization, t txn.DeleteFilter_Keyword) (struct{}, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the Filter that contains this keyword
+		filterService := factory.Filter()
+		filter := model.NewFilter()
+		keyword, err := filterService.LoadByKeywordToken(session, auth.UserID, t.ID, &filter)
+
+		if err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Loading filter keyword")
+		}
+
+		// Remove the keyword
+		filter.RemoveKeyword(keyword.KeywordID)
+
+		if err := filterService.Save(session, &filter, "Keyword removed via Mastodon API"); err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Saving filter")
+		}
+
+		return struct{}{}, nil
 	}
 }
 
+// https://docs.joinmastodon.org/methods/filters/#statuses-get
 func GetFilter_Statuses(serverFactory *server.Factory) func(model.Authorization, txn.GetFilter_Statuses) ([]object.FilterStatus, error) {
 
-	return func(model.Authorization, txn.GetFilter_Statuses) ([]object.FilterStatus, error) {
-		return []object.FilterStatus{}, nil
+	const location = "handler.mastodon.GetFilter_Statuses"
+
+	return func(auth model.Authorization, t txn.GetFilter_Statuses) ([]object.FilterStatus, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return []object.FilterStatus{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return []object.FilterStatus{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the requested Filter
+		filter := model.NewFilter()
+
+		if err := factory.Filter().LoadByToken(session, auth.UserID, t.ID, &filter); err != nil {
+			return []object.FilterStatus{}, derp.Wrap(err, location, "Loading filter")
+		}
+
+		return filter.Toot().Statuses, nil
 	}
 }
 
+// https://docs.joinmastodon.org/methods/filters/#statuses-add
 func PostFilter_Status(serverFactory *server.Factory) func(model.Authorization, txn.PostFilter_Status) (object.FilterStatus, error) {
 
-	return func(model.Authorization, txn.PostFilter_Status) (object.FilterStatus, error) {
-		return object.FilterStatus{}, derp.NotImplemented("handler.mastodon.PostFilter_Status")
+	const location = "handler.mastodon.PostFilter_Status"
+
+	return func(auth model.Authorization, t txn.PostFilter_Status) (object.FilterStatus, error) {
+
+		// RULE: StatusID is required
+		if t.StatusID == "" {
+			return object.FilterStatus{}, derp.BadRequest(location, "StatusID is required")
+		}
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return object.FilterStatus{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.FilterStatus{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the requested Filter
+		filterService := factory.Filter()
+		filter := model.NewFilter()
+
+		if err := filterService.LoadByToken(session, auth.UserID, t.ID, &filter); err != nil {
+			return object.FilterStatus{}, derp.Wrap(err, location, "Loading filter")
+		}
+
+		// Add the status (Status IDs are URLs in this API)
+		status := filter.AddStatus(t.StatusID)
+
+		if err := filterService.Save(session, &filter, "Status added via Mastodon API"); err != nil {
+			return object.FilterStatus{}, derp.Wrap(err, location, "Saving filter")
+		}
+
+		return status.Toot(), nil
 	}
 }
 
+// https://docs.joinmastodon.org/methods/filters/#statuses-get-one
 func GetFilter_Status(serverFactory *server.Factory) func(model.Authorization, txn.GetFilter_Status) (object.FilterStatus, error) {
 
-	return func(model.Authorization, txn.GetFilter_Status) (object.FilterStatus, error) {
-		return object.FilterStatus{}, nil
+	const location = "handler.mastodon.GetFilter_Status"
+
+	return func(auth model.Authorization, t txn.GetFilter_Status) (object.FilterStatus, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return object.FilterStatus{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.FilterStatus{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the Filter that contains this status
+		filter := model.NewFilter()
+		status, err := factory.Filter().LoadByStatusToken(session, auth.UserID, t.ID, &filter)
+
+		if err != nil {
+			return object.FilterStatus{}, derp.Wrap(err, location, "Loading filter status")
+		}
+
+		return status.Toot(), nil
 	}
 }
 
+// https://docs.joinmastodon.org/methods/filters/#statuses-remove
 func DeleteFilter_Status(serverFactory *server.Factory) func(model.Authorization, txn.DeleteFilter_Status) (struct{}, error) {
 
-	return func(model.Authorization, txn.DeleteFilter_Status) (struct{}, error) {
-		return struct{}{}, derp.NotImplemented("handler.mastodon.DeleteFilter_Status")
+	const location = "handler.mastodon.DeleteFilter_Status"
+
+	return func(auth model.Authorization, t txn.DeleteFilter_Status) (struct{}, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the Filter that contains this status
+		filterService := factory.Filter()
+		filter := model.NewFilter()
+		status, err := filterService.LoadByStatusToken(session, auth.UserID, t.ID, &filter)
+
+		if err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Loading filter status")
+		}
+
+		// Remove the status
+		filter.RemoveStatus(status.StatusID)
+
+		if err := filterService.Save(session, &filter, "Status removed via Mastodon API"); err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Saving filter")
+		}
+
+		return struct{}{}, nil
 	}
 }
 
+/******************************************
+ * V1 Filters
+ *
+ * A V1 filter is a single phrase with its own context and expiration.
+ * These are stored as Filters that contain exactly one keyword, titled
+ * with that keyword, so both APIs read and write the same records.
+ ******************************************/
+
+// https://docs.joinmastodon.org/methods/filters/#get-v1
 func GetFilters_V1(serverFactory *server.Factory) func(model.Authorization, txn.GetFilters_V1) ([]object.Filter, toot.PageInfo, error) {
 
-	return func(model.Authorization, txn.GetFilters_V1) ([]object.Filter, toot.PageInfo, error) {
-		return []object.Filter{}, toot.PageInfo{}, nil
+	const location = "handler.mastodon.GetFilters_V1"
+
+	return func(auth model.Authorization, t txn.GetFilters_V1) ([]object.Filter, toot.PageInfo, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return []object.Filter{}, toot.PageInfo{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return []object.Filter{}, toot.PageInfo{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Query all of the User's Filters
+		filters, err := factory.Filter().QueryByUser(session, auth.UserID)
+
+		if err != nil {
+			return []object.Filter{}, toot.PageInfo{}, derp.Wrap(err, location, "Querying filters")
+		}
+
+		return getSliceOfToots(filters), getPageInfo(filters), nil
 	}
 }
 
+// https://docs.joinmastodon.org/methods/filters/#get-one-v1
 func GetFilter_V1(serverFactory *server.Factory) func(model.Authorization, txn.GetFilter_V1) (object.Filter, error) {
 
-	return func(model.Authorization, txn.GetFilter_V1) (object.Filter, error) {
-		return object.Filter{}, nil
+	const location = "handler.mastodon.GetFilter_V1"
+
+	return func(auth model.Authorization, t txn.GetFilter_V1) (object.Filter, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return object.Filter{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.Filter{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the requested Filter
+		filter := model.NewFilter()
+
+		if err := factory.Filter().LoadByToken(session, auth.UserID, t.ID, &filter); err != nil {
+			return object.Filter{}, derp.Wrap(err, location, "Loading filter")
+		}
+
+		return filter.Toot(), nil
 	}
 }
 
+// https://docs.joinmastodon.org/methods/filters/#create-v1
 func PostFilter_V1(serverFactory *server.Factory) func(model.Authorization, txn.PostFilter_V1) (object.Filter, error) {
 
-	return func(model.Authorization, txn.PostFilter_V1) (object.Filter, error) {
-		return object.Filter{}, derp.NotImplemented("handler.mastodon.PostFilter_V1")
+	const location = "handler.mastodon.PostFilter_V1"
+
+	return func(auth model.Authorization, t txn.PostFilter_V1) (object.Filter, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return object.Filter{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.Filter{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Create a new single-phrase Filter
+		filter := model.NewFilter()
+		filter.UserID = auth.UserID
+		setFilterPhrase_V1(&filter, t.Phrase, t.WholeWord)
+		filter.Contexts = t.Context
+		filter.Action = filterAction_V1(t.Irreversible)
+		filter.ExpireDate = filterExpireDate(int64(t.ExpiresIn))
+
+		if err := factory.Filter().Save(session, &filter, "Created via Mastodon API"); err != nil {
+			return object.Filter{}, derp.Wrap(err, location, "Saving filter")
+		}
+
+		return filter.Toot(), nil
 	}
 }
 
+// https://docs.joinmastodon.org/methods/filters/#update-v1
 func PutFilter_V1(serverFactory *server.Factory) func(model.Authorization, txn.PutFilter_V1) (object.Filter, error) {
 
-	return func(model.Authorization, txn.PutFilter_V1) (object.Filter, error) {
-		return object.Filter{}, derp.NotImplemented("handler.mastodon.PutFilter_V1")
+	const location = "handler.mastodon.PutFilter_V1"
+
+	return func(auth model.Authorization, t txn.PutFilter_V1) (object.Filter, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return object.Filter{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.Filter{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the requested Filter
+		filterService := factory.Filter()
+		filter := model.NewFilter()
+
+		if err := filterService.LoadByToken(session, auth.UserID, t.ID, &filter); err != nil {
+			return object.Filter{}, derp.Wrap(err, location, "Loading filter")
+		}
+
+		// V1 updates replace the entire filter
+		setFilterPhrase_V1(&filter, t.Phrase, t.WholeWord)
+		filter.Contexts = t.Context
+		filter.Action = filterAction_V1(t.Irreversible)
+		filter.ExpireDate = filterExpireDate(int64(t.ExpiresIn))
+
+		if err := filterService.Save(session, &filter, "Updated via Mastodon API"); err != nil {
+			return object.Filter{}, derp.Wrap(err, location, "Saving filter")
+		}
+
+		return filter.Toot(), nil
 	}
 }
 
+// https://docs.joinmastodon.org/methods/filters/#delete-v1
 func DeleteFilter_V1(serverFactory *server.Factory) func(model.Authorization, txn.DeleteFilter_V1) (struct{}, error) {
 
-	return func(model.Authorization, txn.DeleteFilter_V1) (struct{}, error) {
-		return struct{}{}, derp.NotImplemented("handler.mastodon.DeleteFilter_V1")
+	const location = "handler.mastodon.DeleteFilter_V1"
+
+	return func(auth model.Authorization, t txn.DeleteFilter_V1) (struct{}, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the requested Filter
+		filterService := factory.Filter()
+		filter := model.NewFilter()
+
+		if err := filterService.LoadByToken(session, auth.UserID, t.ID, &filter); err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Loading filter")
+		}
+
+		// Delete the Filter
+		if err := filterService.Delete(session, &filter, "Deleted via Mastodon API"); err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Deleting filter")
+		}
+
+		return struct{}{}, nil
+	}
+}
+
+/******************************************
+ * Helpers
+ ******************************************/
+
+// setFilterPhrase_V1 replaces all keywords in a Filter with the single phrase used by the V1 API
+func setFilterPhrase_V1(filter *model.Filter, phrase string, wholeWord bool) {
+
+	keyword := model.NewFilterKeyword()
+
+	// Keep the existing KeywordID so that V2 clients see a stable keyword
+	if len(filter.Keywords) > 0 {
+		keyword.KeywordID = filter.Keywords[0].KeywordID
+	}
+
+	keyword.Keyword = phrase
+	keyword.WholeWord = wholeWord
+
+	filter.Title = phrase
+	filter.Keywords = append(filter.Keywords[:0], keyword)
+}
+
+// filterAction_V1 converts the V1 "irreversible" flag into a Filter action
+func filterAction_V1(irreversible bool) string {
+
+	if irreversible {
+		return model.FilterActionHide
 	}
+
+	return model.FilterActionWarn
+}
+
+// filterExpireDate converts a Mastodon "expires_in" value (seconds from now) into an
+// absolute ExpireDate (Unix seconds).  Zero means the Filter never expires.
+func filterExpireDate(expiresIn int64) int64 {
+
+	if expiresIn <= 0 {
+		return 0
+	}
+
+	return time.Now().Unix() + expiresIn
+}
+
+// getFilteredStatuses converts a page of NewsItems into Mastodon Statuses, applying the User's
+// active Filters for the provided context: statuses matched by a "hide" Filter are removed, and
+// statuses matched by a "warn" Filter carry the matching Filters in their `filtered` field.
+// The page's PageInfo should still be calculated from the unfiltered NewsItems.
+func getFilteredStatuses(factory *service.Factory, session data.Session, userID primitive.ObjectID, context string, newsItems []model.NewsItem) ([]object.Status, error) {
+
+	const location = "handler.mastodon.getFilteredStatuses"
+
+	// Find the Filters that apply to this context
+	filters, err := factory.Filter().QueryActive(session, userID, context, time.Now().Unix())
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Querying active filters", userID, context)
+	}
+
+	// No filters means nothing to do
+	if len(filters) == 0 {
+		return getSliceOfToots(newsItems), nil
+	}
+
+	client := factory.ActivityStream().UserClient(userID)
+	result := make([]object.Status, 0, len(newsItems))
+
+	for _, newsItem := range newsItems {
+
+		// Keyword filters match the document's text.  A document that can't be loaded
+		// is matched by URL only, so an unreachable server can't break the timeline.
+		text := ""
+
+		if document, err := client.Load(newsItem.URL); err == nil {
+			text = model.ContentText(document)
+		}
+
+		matches := model.MatchFilters(filters, text, newsItem.URL)
+
+		// "hide" filters remove the status entirely
+		if matches.IsHidden() {
+			continue
+		}
+
+		status := newsItem.Toot()
+
+		// "warn" filters are reported to the client
+		if len(matches) > 0 {
+			status.Filtered = matches.Toot()
+		}
+
+		result = append(result, status)
+	}
+
+	return result, nil
+}
+
+// getFilteredStreams converts a page of local Streams into Mastodon Statuses, applying the
+// User's active Filters for the provided context (usually "account").
+func getFilteredStreams(factory *service.Factory, session data.Session, userID primitive.ObjectID, context string, posts []model.Stream) ([]object.Status, error) {
+
+	const location = "handler.mastodon.getFilteredStreams"
+
+	// Anonymous callers have no Filters
+	if userID.IsZero() {
+		return getSliceOfToots(posts), nil
+	}
+
+	// Find the Filters that apply to this context
+	filters, err := factory.Filter().QueryActive(session, userID, context, time.Now().Unix())
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Querying active filters", userID, context)
+	}
+
+	// No filters means nothing to do
+	if len(filters) == 0 {
+		return getSliceOfToots(posts), nil
+	}
+
+	result := make([]object.Status, 0, len(posts))
+
+	for _, stream := range posts {
+
+		text := model.FilterTextFromStrings(stream.Label, stream.Summary, stream.Content.HTML)
+		matches := model.MatchFilters(filters, text, stream.ActivityPubURL())
+
+		// "hide" filters remove the status entirely
+		if matches.IsHidden() {
+			continue
+		}
+
+		status := stream.Toot()
+
+		// "warn" filters are reported to the client
+		if len(matches) > 0 {
+			status.Filtered = matches.Toot()
+		}
+
+		result = append(result, status)
+	}
+
+	return result, nil
+}
+
+// getFilteredNotifications removes the Notifications that are hidden by the User's active
+// "notifications" Filters.  Notifications only carry a plain-text snapshot of the object
+// they refer to, so keywords are matched against that snapshot.
+func getFilteredNotifications(factory *service.Factory, session data.Session, userID primitive.ObjectID, notifications []model.Notification) ([]model.Notification, error) {
+
+	const location = "handler.mastodon.getFilteredNotifications"
+
+	// Find the Filters that apply to notifications
+	filters, err := factory.Filter().QueryActive(session, userID, model.FilterContextNotifications, time.Now().Unix())
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Querying active filters", userID)
+	}
+
+	// No filters means nothing to do
+	if len(filters) == 0 {
+		return notifications, nil
+	}
+
+	result := make([]model.Notification, 0, len(notifications))
+
+	for _, notification := range notifications {
+
+		text := model.FilterTextFromStrings(notification.ObjectSummary)
+
+		if model.MatchFilters(filters, text, notification.ObjectURL).IsHidden() {
+			continue
+		}
+
+		result = append(result, notification)
+	}
+
+	return result, nil
 }
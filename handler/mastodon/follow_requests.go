@@ -1,8 +1,11 @@
 package mastodon
 
 import (
+	"time"
+
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/data/option"
 	"github.com/benpate/derp"
 	"github.com/benpate/toot"
 	"github.com/benpate/toot/object"
@@ -12,21 +15,133 @@ import (
 // https://docs.joinmastodon.org/methods/follow_requests/
 func GetFollowRequests(serverFactory *server.Factory) func(model.Authorization, txn.GetFollowRequests) ([]object.Account, toot.PageInfo, error) {
 
-	return func(model.Authorization, txn.GetFollowRequests) ([]object.Account, toot.PageInfo, error) {
-		return []object.Account{}, toot.PageInfo{}, nil
+	const location = "handler.mastodon.GetFollowRequests"
+
+	return func(auth model.Authorization, t txn.GetFollowRequests) ([]object.Account, toot.PageInfo, error) {
+
+		// Get the Domain factory for this request
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Query all pending follow requests for this User
+		followers, err := factory.Follower().QueryFollowRequests(session, auth.UserID, option.SortAsc("createDate"))
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Querying follow requests")
+		}
+
+		result := make([]object.Account, 0, len(followers))
+
+		for _, follower := range followers {
+			result = append(result, follower.Actor.Toot())
+		}
+
+		return result, toot.PageInfo{}, nil
 	}
 }
 
+// https://docs.joinmastodon.org/methods/follow_requests/#accept
 func PostFollowRequest_Authorize(serverFactory *server.Factory) func(model.Authorization, txn.PostFollowRequest_Authorize) (object.Relationship, error) {
 
-	return func(model.Authorization, txn.PostFollowRequest_Authorize) (object.Relationship, error) {
-		return object.Relationship{}, derp.NotImplemented("handler.mastodon.PostFollowRequest_Authorize")
+	const location = "handler.mastodon.PostFollowRequest_Authorize"
+
+	return func(auth model.Authorization, t txn.PostFollowRequest_Authorize) (object.Relationship, error) {
+
+		// Get the Domain factory for this request
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return object.Relationship{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.Relationship{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the User
+		user := model.NewUser()
+
+		if err := factory.User().LoadByID(session, auth.UserID, &user); err != nil {
+			return object.Relationship{}, derp.Wrap(err, location, "Unrecognized User")
+		}
+
+		// Load the pending follow request.  Account IDs are the actor's profile URL.
+		followerService := factory.Follower()
+		follower := model.NewFollower()
+
+		if err := followerService.LoadFollowRequest(session, auth.UserID, t.ID, &follower); err != nil {
+			return object.Relationship{}, derp.Wrap(err, location, "Loading follow request", t.ID)
+		}
+
+		// Accept the request
+		if err := followerService.AcceptFollowRequest(session, &follower); err != nil {
+			return object.Relationship{}, derp.Wrap(err, location, "Accepting follow request", t.ID)
+		}
+
+		return getRelationship(factory.User(), session, &user, t.ID)
 	}
 }
 
+// https://docs.joinmastodon.org/methods/follow_requests/#reject
 func PostFollowRequest_Reject(serverFactory *server.Factory) func(model.Authorization, txn.PostFollowRequest_Reject) (object.Relationship, error) {
 
-	return func(model.Authorization, txn.PostFollowRequest_Reject) (object.Relationship, error) {
-		return object.Relationship{}, derp.NotImplemented("handler.mastodon.PostFollowRequest_Reject")
+	const location = "handler.mastodon.PostFollowRequest_Reject"
+
+	return func(auth model.Authorization, t txn.PostFollowRequest_Reject) (object.Relationship, error) {
+
+		// Get the Domain factory for this request
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return object.Relationship{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.Relationship{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the User
+		user := model.NewUser()
+
+		if err := factory.User().LoadByID(session, auth.UserID, &user); err != nil {
+			return object.Relationship{}, derp.Wrap(err, location, "Unrecognized User")
+		}
+
+		// Load the pending follow request.  Account IDs are the actor's profile URL.
+		followerService := factory.Follower()
+		follower := model.NewFollower()
+
+		if err := followerService.LoadFollowRequest(session, auth.UserID, t.ID, &follower); err != nil {
+			return object.Relationship{}, derp.Wrap(err, location, "Loading follow request", t.ID)
+		}
+
+		// Reject the request
+		if err := followerService.RejectFollowRequest(session, &follower); err != nil {
+			return object.Relationship{}, derp.Wrap(err, location, "Rejecting follow request", t.ID)
+		}
+
+		return getRelationship(factory.User(), session, &user, t.ID)
 	}
 }
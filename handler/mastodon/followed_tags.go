@@ -1,17 +1,66 @@
 package mastodon
 
 import (
+	"strconv"
+	"time"
+
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
 	"github.com/benpate/toot"
 	"github.com/benpate/toot/object"
 	"github.com/benpate/toot/txn"
 )
 
+// followedTagsPageSize is the maximum number of FollowedTags returned in a single page
+const followedTagsPageSize = 100
+
 // https://docs.joinmastodon.org/methods/followed_tags/
 func GetFollowedTags(serverFactory *server.Factory) func(model.Authorization, txn.GetFollowedTags) ([]object.Tag, toot.PageInfo, error) {
 
-	return func(model.Authorization, txn.GetFollowedTags) ([]object.Tag, toot.PageInfo, error) {
-		return []object.Tag{}, toot.PageInfo{}, nil
+	const location = "handler.mastodon.GetFollowedTags"
+
+	return func(auth model.Authorization, t txn.GetFollowedTags) ([]object.Tag, toot.PageInfo, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Invalid Domain Name", t.Host)
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Query the User's FollowedTags.  Pages are sorted
+		// by createDate, which is what queryExpression filters on.
+		followedTags, err := factory.FollowedTag().QueryByUser(session, auth.UserID, queryExpression(t), option.SortDesc("createDate"), option.MaxRows(followedTagsPageSize))
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Querying followed tags")
+		}
+
+		// Convert the results to Mastodon objects
+		host := factory.Host()
+		result := make([]object.Tag, len(followedTags))
+		pageInfo := toot.PageInfo{}
+
+		for index, followedTag := range followedTags {
+			result[index] = followedTag.Toot(host)
+		}
+
+		if length := len(followedTags); length > 0 {
+			pageInfo.MaxID = strconv.FormatInt(followedTags[length-1].CreateDate, 10)
+			pageInfo.MinID = strconv.FormatInt(followedTags[0].CreateDate, 10)
+		}
+
+		return result, pageInfo, nil
 	}
 }
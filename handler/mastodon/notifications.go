@@ -45,7 +45,14 @@ func GetNotifications(serverFactory *server.Factory) func(model.Authorization, t
 			return []object.Notification{}, toot.PageInfo{}, derp.Wrap(err, location, "Querying notifications")
 		}
 
-		return getSliceOfToots(notifications), getPageInfo(notifications), nil
+		// Apply the User's Filters, removing notifications that are hidden by them
+		filtered, err := getFilteredNotifications(factory, session, auth.UserID, notifications)
+
+		if err != nil {
+			return []object.Notification{}, toot.PageInfo{}, derp.Wrap(err, location, "Applying filters")
+		}
+
+		return getSliceOfToots(filtered), getPageInfo(notifications), nil
 	}
 }
 
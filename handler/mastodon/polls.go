@@ -1,6 +1,8 @@
 package mastodon
 
 import (
+	"time"
+
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/server"
 	"github.com/benpate/derp"
@@ -8,17 +10,84 @@ import (
 	"github.com/benpate/toot/txn"
 )
 
-// https://docs.joinmastodon.org/methods/polls/
+// https://docs.joinmastodon.org/methods/polls/#get
 func GetPoll(serverFactory *server.Factory) func(model.Authorization, txn.GetPoll) ([]object.Poll, error) {
 
-	return func(model.Authorization, txn.GetPoll) ([]object.Poll, error) {
-		return nil, derp.NotImplemented("handler.mastodon.GetPoll")
+	const location = "handler.mastodon.GetPoll"
+
+	return func(auth model.Authorization, t txn.GetPoll) ([]object.Poll, error) {
+
+		// Get the factory for this domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the User from the database
+		user := model.NewUser()
+
+		if err := factory.User().LoadByID(session, auth.UserID, &user); err != nil {
+			return nil, derp.Wrap(err, location, "Loading user")
+		}
+
+		// Load the Poll (and the User's own votes).  Poll IDs are the URL of the Question.
+		poll, ownVotes, err := factory.PollVote().LoadPoll(session, &user, t.ID)
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Loading poll", t.ID)
+		}
+
+		return []object.Poll{poll.Toot(t.ID, ownVotes)}, nil
 	}
 }
 
+// https://docs.joinmastodon.org/methods/polls/#vote
 func PostPoll_Votes(serverFactory *server.Factory) func(model.Authorization, txn.PostPoll_Votes) ([]object.Poll, error) {
 
-	return func(model.Authorization, txn.PostPoll_Votes) ([]object.Poll, error) {
-		return nil, derp.NotImplemented("handler.mastodon.PostPoll_Votes")
+	const location = "handler.mastodon.PostPoll_Votes"
+
+	return func(auth model.Authorization, t txn.PostPoll_Votes) ([]object.Poll, error) {
+
+		// Get the factory for this domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the User from the database
+		user := model.NewUser()
+
+		if err := factory.User().LoadByID(session, auth.UserID, &user); err != nil {
+			return nil, derp.Wrap(err, location, "Loading user")
+		}
+
+		// Cast the vote (locally, or by sending it to the Poll's author)
+		poll, ownVotes, err := factory.PollVote().Vote(session, &user, t.ID, t.Choices)
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Voting in poll", t.ID, t.Choices)
+		}
+
+		return []object.Poll{poll.Toot(t.ID, ownVotes)}, nil
 	}
 }
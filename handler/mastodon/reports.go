@@ -1,8 +1,12 @@
 package mastodon
 
 import (
+	"time"
+
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/first"
 	"github.com/benpate/toot/object"
 	"github.com/benpate/toot/txn"
 )
@@ -10,7 +14,39 @@ import (
 // https://docs.joinmastodon.org/methods/reports/
 func PostReport(serverFactory *server.Factory) func(model.Authorization, txn.PostReport) (object.Report, error) {
 
-	return func(model.Authorization, txn.PostReport) (object.Report, error) {
-		return object.Report{}, nil
+	const location = "handler.mastodon.PostReport"
+
+	return func(auth model.Authorization, t txn.PostReport) (object.Report, error) {
+
+		// Get the Domain factory for this request
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return object.Report{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.Report{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Create the Report.  Mastodon account and status IDs are ActivityPub URLs in Emissary.
+		report := model.NewReport()
+		report.ReporterID = auth.UserID
+		report.TargetURL = t.AccountID
+		report.ObjectURLs = t.StatusIDs
+		report.Category = first.String(t.Category, model.ReportCategoryOther)
+		report.Comment = t.Comment
+		report.Forward = t.Forward
+
+		if err := factory.Report().Save(session, &report, "Created via Mastodon API"); err != nil {
+			return object.Report{}, derp.Wrap(err, location, "Saving report")
+		}
+
+		return report.Toot(), nil
 	}
 }
@@ -1,40 +1,200 @@
 package mastodon
 
 import (
+	"strconv"
+	"time"
+
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/server"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
 	"github.com/benpate/toot"
 	"github.com/benpate/toot/object"
 	"github.com/benpate/toot/txn"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// https://docs.joinmastodon.org/methods/scheduled_statuses/
+// scheduledStatusesPageSize is the maximum number of ScheduledStatuses returned in a single page
+const scheduledStatusesPageSize = 20
+
+// https://docs.joinmastodon.org/methods/scheduled_statuses/#get
 func GetScheduledStatuses(serverFactory *server.Factory) func(model.Authorization, txn.GetScheduledStatuses) ([]object.ScheduledStatus, toot.PageInfo, error) {
 
-	return func(model.Authorization, txn.GetScheduledStatuses) ([]object.ScheduledStatus, toot.PageInfo, error) {
-		return []object.ScheduledStatus{}, toot.PageInfo{}, nil
+	const location = "handler.mastodon_GetScheduledStatuses"
+
+	return func(authorization model.Authorization, transaction txn.GetScheduledStatuses) ([]object.ScheduledStatus, toot.PageInfo, error) {
+
+		// Get the factory for this domain
+		factory, err := serverFactory.ByHostname(transaction.Host)
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Query the User's scheduled Streams
+		streams, err := factory.Stream().QueryScheduledByUser(session, authorization.UserID, queryExpression(transaction), option.MaxRows(scheduledStatusesPageSize))
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Querying scheduled streams")
+		}
+
+		// Convert the results to Mastodon objects.  Pages are sorted
+		// by createDate, which is what queryExpression filters on.
+		result := make([]object.ScheduledStatus, len(streams))
+		pageInfo := toot.PageInfo{}
+
+		for index, stream := range streams {
+			result[index] = stream.ScheduledToot()
+		}
+
+		if length := len(streams); length > 0 {
+			pageInfo.MaxID = strconv.FormatInt(streams[length-1].CreateDate, 10)
+			pageInfo.MinID = strconv.FormatInt(streams[0].CreateDate, 10)
+		}
+
+		return result, pageInfo, nil
 	}
 }
 
+// https://docs.joinmastodon.org/methods/scheduled_statuses/#get-one
 func GetScheduledStatus(serverFactory *server.Factory) func(model.Authorization, txn.GetScheduledStatus) (object.ScheduledStatus, error) {
 
-	return func(model.Authorization, txn.GetScheduledStatus) (object.ScheduledStatus, error) {
-		return object.ScheduledStatus{}, nil
+	const location = "handler.mastodon_GetScheduledStatus"
 
+	return func(authorization model.Authorization, transaction txn.GetScheduledStatus) (object.ScheduledStatus, error) {
+
+		_, _, stream, err := getScheduledStream(serverFactory, &authorization, transaction.Host, transaction.ID)
+
+		if err != nil {
+			return object.ScheduledStatus{}, derp.Wrap(err, location, "Loading scheduled stream")
+		}
+
+		return stream.ScheduledToot(), nil
 	}
 }
 
+// https://docs.joinmastodon.org/methods/scheduled_statuses/#update
 func PutScheduledStatus(serverFactory *server.Factory) func(model.Authorization, txn.PutScheduledStatus) (object.ScheduledStatus, error) {
 
-	return func(model.Authorization, txn.PutScheduledStatus) (object.ScheduledStatus, error) {
-		return object.ScheduledStatus{}, nil
+	const location = "handler.mastodon_PutScheduledStatus"
+
+	return func(authorization model.Authorization, transaction txn.PutScheduledStatus) (object.ScheduledStatus, error) {
+
+		factory, streamService, stream, err := getScheduledStream(serverFactory, &authorization, transaction.Host, transaction.ID)
+
+		if err != nil {
+			return object.ScheduledStatus{}, derp.Wrap(err, location, "Loading scheduled stream")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
 
+		if err != nil {
+			return object.ScheduledStatus{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Move the Stream to its new publish date
+		scheduledAt := model.ParsePublishDate(transaction.ScheduledAt)
+
+		if err := streamService.Reschedule(session, &stream, scheduledAt); err != nil {
+			return object.ScheduledStatus{}, derp.Wrap(err, location, "Rescheduling stream")
+		}
+
+		return stream.ScheduledToot(), nil
 	}
 }
 
+// https://docs.joinmastodon.org/methods/scheduled_statuses/#cancel
 func DeleteScheduledStatus(serverFactory *server.Factory) func(model.Authorization, txn.DeleteScheduledStatus) (struct{}, error) {
 
-	return func(model.Authorization, txn.DeleteScheduledStatus) (struct{}, error) {
+	const location = "handler.mastodon_DeleteScheduledStatus"
+
+	return func(authorization model.Authorization, transaction txn.DeleteScheduledStatus) (struct{}, error) {
+
+		factory, streamService, stream, err := getScheduledStream(serverFactory, &authorization, transaction.Host, transaction.ID)
+
+		if err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Loading scheduled stream")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Cancelling a scheduled status removes it entirely, matching the Mastodon API
+		if err := streamService.Unschedule(session, &stream, "Cancelled via Mastodon API"); err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Unscheduling stream")
+		}
+
+		if err := streamService.Delete(session, &stream, "Cancelled via Mastodon API"); err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Deleting stream")
+		}
+
 		return struct{}{}, nil
 	}
 }
+
+// getScheduledStream loads a scheduled Stream using the ID from its ScheduledStatus and verifies
+// that it belongs to the authorized User.  Streams that are not scheduled are NotFound.
+func getScheduledStream(serverFactory *server.Factory, authorization *model.Authorization, host string, scheduledStatusID string) (*service.Factory, *service.Stream, model.Stream, error) {
+
+	const location = "handler.mastodon.getScheduledStream"
+
+	// Get the factory for this domain
+	factory, err := serverFactory.ByHostname(host)
+
+	if err != nil {
+		return nil, nil, model.Stream{}, derp.Wrap(err, location, "Unrecognized Domain")
+	}
+
+	// ScheduledStatus IDs are the StreamID (see model.Stream.ScheduledToot)
+	streamID, err := primitive.ObjectIDFromHex(scheduledStatusID)
+
+	if err != nil {
+		return nil, nil, model.Stream{}, derp.Wrap(err, location, "Invalid ScheduledStatus ID", scheduledStatusID)
+	}
+
+	// Get a database session for this request
+	session, cancel, err := factory.Session(time.Minute)
+
+	if err != nil {
+		return nil, nil, model.Stream{}, derp.Wrap(err, location, "Creating session")
+	}
+
+	defer cancel()
+
+	// Load the Stream from the database
+	streamService := factory.Stream()
+	stream := model.NewStream()
+
+	if err := streamService.LoadByID(session, streamID, &stream); err != nil {
+		return nil, nil, model.Stream{}, derp.Wrap(err, location, "Loading stream", scheduledStatusID)
+	}
+
+	if !stream.IsScheduled() {
+		return nil, nil, model.Stream{}, derp.NotFound(location, "Stream is not scheduled", scheduledStatusID)
+	}
+
+	if err := userOwnsStream(authorization, &stream); err != nil {
+		return nil, nil, model.Stream{}, derp.Wrap(err, location, "Loading stream")
+	}
+
+	return factory, streamService, stream, nil
+}
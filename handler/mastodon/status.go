@@ -10,7 +10,6 @@ import (
 	"github.com/benpate/toot"
 	"github.com/benpate/toot/object"
 	"github.com/benpate/toot/txn"
-	"github.com/relvacode/iso8601"
 )
 
 // https://docs.joinmastodon.org/methods/statuses/#create
@@ -19,62 +18,140 @@ func PostStatus(serverFactory *server.Factory) func(model.Authorization, txn.Pos
 	const location = "handler.mastodon_PostStatus"
 	return func(authorization model.Authorization, transaction txn.PostStatus) (object.Status, error) {
 
-		// Get the factory for this domain
-		factory, err := serverFactory.ByHostname(transaction.Host)
+		// RULE: Scheduled statuses respond with a ScheduledStatus (see PostScheduledStatus)
+		if IsScheduledStatus(transaction) {
+			return object.Status{}, derp.Validation("Scheduled statuses must use PostScheduledStatus", transaction.ScheduledAt)
+		}
+
+		stream, err := postStatus(serverFactory, authorization, transaction)
 
 		if err != nil {
-			return object.Status{}, derp.Wrap(err, location, "Unrecognized Domain")
+			return object.Status{}, derp.Wrap(err, location, "Posting status")
 		}
 
-		// Get a database session for this request
-		session, cancel, err := factory.Session(time.Minute)
+		return stream.Toot(), nil
+	}
+}
+
+// PostScheduledStatus handles "create status" requests that include a future "scheduled_at" date.
+// The Mastodon API responds to these with a ScheduledStatus instead of a Status.
+// https://docs.joinmastodon.org/methods/statuses/#create
+func PostScheduledStatus(serverFactory *server.Factory) func(model.Authorization, txn.PostStatus) (object.ScheduledStatus, error) {
+
+	const location = "handler.mastodon_PostScheduledStatus"
+	return func(authorization model.Authorization, transaction txn.PostStatus) (object.ScheduledStatus, error) {
+
+		if !IsScheduledStatus(transaction) {
+			return object.ScheduledStatus{}, derp.Validation("Scheduled date must be in the future", transaction.ScheduledAt)
+		}
+
+		stream, err := postStatus(serverFactory, authorization, transaction)
 
 		if err != nil {
-			return object.Status{}, derp.Wrap(err, location, "Creating session")
+			return object.ScheduledStatus{}, derp.Wrap(err, location, "Posting status")
 		}
 
-		defer cancel()
+		return stream.ScheduledToot(), nil
+	}
+}
 
-		// Load the user from the database
-		userSerivce := factory.User()
-		user := model.NewUser()
+// IsScheduledStatus returns TRUE if a "create status" request should be published in the future
+func IsScheduledStatus(transaction txn.PostStatus) bool {
+	return model.ParsePublishDate(transaction.ScheduledAt) > time.Now().Unix()
+}
 
-		if err := userSerivce.LoadByID(session, authorization.UserID, &user); err != nil {
-			return object.Status{}, derp.Wrap(err, location, "Loading user")
-		}
+// postStatus creates a new Stream in the User's outbox, and either publishes it
+// immediately or schedules it for the date in "scheduled_at"
+func postStatus(serverFactory *server.Factory, authorization model.Authorization, transaction txn.PostStatus) (model.Stream, error) {
 
-		// Create the stream for the new mastodon "Status"
-		stream := model.NewStream()
-		// Hard-coded default Template, matching service.Stream.Import (service/stream_import.go).
-		// This will be updated when we make a registry of default templates in profiles.
-		stream.TemplateID = "outbox-message"
-		stream.ParentID = authorization.UserID
-		stream.AttributedTo = user.PersonLink()
-		stream.SocialRole = vocab.ObjectTypeNote
-		stream.InReplyTo = transaction.InReplyToID
-		stream.Label = transaction.SpoilerText
+	const location = "handler.mastodon.postStatus"
+
+	// Get the factory for this domain
+	factory, err := serverFactory.ByHostname(transaction.Host)
+
+	if err != nil {
+		return model.Stream{}, derp.Wrap(err, location, "Unrecognized Domain")
+	}
+
+	// Get a database session for this request
+	session, cancel, err := factory.Session(time.Minute)
+
+	if err != nil {
+		return model.Stream{}, derp.Wrap(err, location, "Creating session")
+	}
+
+	defer cancel()
 
-		if scheduledAt, err := iso8601.ParseString(transaction.ScheduledAt); err == nil {
-			stream.PublishDate = scheduledAt.Unix()
+	// Load the user from the database
+	userSerivce := factory.User()
+	user := model.NewUser()
+
+	if err := userSerivce.LoadByID(session, authorization.UserID, &user); err != nil {
+		return model.Stream{}, derp.Wrap(err, location, "Loading user")
+	}
+
+	// Create the stream for the new mastodon "Status" using the User's chosen Note template
+	streamService := factory.Stream()
+	stream, err := streamService.NewOutboxPost(session, &user, vocab.ObjectTypeNote)
+
+	if err != nil {
+		return model.Stream{}, derp.Wrap(err, location, "Creating stream")
+	}
+
+	stream.InReplyTo = transaction.InReplyToID
+	stream.Label = transaction.SpoilerText
+
+	// Add the content into the stream
+	contentService := factory.Content()
+	stream.Content = contentService.New(model.ContentFormatHTML, transaction.Status)
+
+	// Attach a Poll (if requested)
+	if len(transaction.Poll.Options) > 0 {
+
+		if len(transaction.Poll.Options) > model.PollMaxOptions {
+			return model.Stream{}, derp.Validation("Too many poll options", len(transaction.Poll.Options))
 		}
 
-		// Add the content into the stream
-		contentService := factory.Content()
-		stream.Content = contentService.New(model.ContentFormatHTML, transaction.Status)
+		stream.Poll = model.NewPollWithOptions(transaction.Poll.Options...)
+		stream.Poll.Multiple = transaction.Poll.Multiple
 
-		// Save the stream
-		streamService := factory.Stream()
-		if err := streamService.Save(session, &stream, "Created via Mastodon API"); err != nil {
-			return object.Status{}, derp.Wrap(err, location, "Saving stream")
+		if transaction.Poll.ExpiresIn > 0 {
+			stream.Poll.EndDate = time.Now().Unix() + int64(transaction.Poll.ExpiresIn)
 		}
+	}
 
-		// Publish the Stream to the User's outbox
-		if err := streamService.Publish(session, &user, &stream, "published", true, false); err != nil {
-			return object.Status{}, derp.Wrap(err, location, "Publishing stream")
+	// Attach uploaded media (if requested) before publishing, so that it federates with the Stream
+	if len(transaction.MediaIDs) > 0 {
+		if err := factory.Attachment().AttachMedia(session, user.UserID, stream.StreamID, transaction.MediaIDs); err != nil {
+			return model.Stream{}, derp.Wrap(err, location, "Attaching media")
 		}
+	}
 
-		return stream.Toot(), nil
+	// Use the same State that the Template publishes into from the web editor
+	stateID, err := streamService.OutboxPostState(&stream)
+
+	if err != nil {
+		return model.Stream{}, derp.Wrap(err, location, "Finding publish state")
+	}
+
+	// Schedule the Stream if the client requested a future date
+	if IsScheduledStatus(transaction) {
+
+		scheduledAt := model.ParsePublishDate(transaction.ScheduledAt)
+
+		if err := streamService.SchedulePublish(session, &user, &stream, stateID, true, false, scheduledAt); err != nil {
+			return model.Stream{}, derp.Wrap(err, location, "Scheduling stream")
+		}
+
+		return stream, nil
 	}
+
+	// Save and publish the Stream to the User's outbox
+	if err := streamService.PublishOutboxPost(session, &user, &stream, stateID, false); err != nil {
+		return model.Stream{}, derp.Wrap(err, location, "Publishing stream")
+	}
+
+	return stream, nil
 }
 
 // https://docs.joinmastodon.org/methods/statuses/#get
@@ -138,7 +215,14 @@ func DeleteStatus(serverFactory *server.Factory) func(model.Authorization, txn.D
 
 		defer cancel()
 
-		if err := streamService.Delete(session, &stream, "Deleted via Mastodon API"); err != nil {
+		// Load the author of the Stream, whose followers receive the "Delete" activity
+		user := model.NewUser()
+
+		if err := factory.User().LoadByID(session, stream.AttributedTo.UserID, &user); err != nil {
+			return struct{}{}, derp.Wrap(err, location, "Loading user")
+		}
+
+		if err := streamService.DeleteOutboxPost(session, &user, &stream, "Deleted via Mastodon API"); err != nil {
 			return struct{}{}, derp.Wrap(err, location, "Deleting stream")
 		}
 
@@ -312,40 +396,185 @@ func PostStatus_Unfavourite(serverFactory *server.Factory) func(model.Authorizat
 			return object.Status{}, derp.Wrap(err, location, "Deleting response")
 		}
 
-		// Return success
-		return response.Toot(), nil
+		// Return the original Status, which the User no longer reblogs
+		return getViewerStatus(factory, session, auth.UserID, t.ID), nil
 	}
 }
 
 // https://docs.joinmastodon.org/methods/statuses/#boost
 func PostStatus_Reblog(serverFactory *server.Factory) func(model.Authorization, txn.PostStatus_Reblog) (object.Status, error) {
 
+	const location = "handler.mastodon.PostStatus_Reblog"
+
 	return func(auth model.Authorization, t txn.PostStatus_Reblog) (object.Status, error) {
-		return object.Status{}, derp.NotImplemented("handler.mastodon.PostStatus_Reblog")
+
+		// Get the factory for this domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Load the User
+		userService := factory.User()
+		user := model.NewUser()
+
+		if err := userService.LoadByID(session, auth.UserID, &user); err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Loading user")
+		}
+
+		// Save the Announce via SetResponse, which validates the target, publishes the
+		// Announce through the User's outbox, and makes this endpoint idempotent.
+		responseService := factory.Response()
+
+		if err := responseService.SetResponse(session, &user, t.ID, vocab.ActivityTypeAnnounce, ""); err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Saving response")
+		}
+
+		// Read the active Response back, so the caller is returned the record that actually persisted
+		response := model.NewResponse()
+
+		if err := responseService.LoadByUserAndObject(session, auth.UserID, t.ID, vocab.ActivityTypeAnnounce, &response); err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Loading response")
+		}
+
+		// Return the reblog itself, wrapped around the original Status
+		original := getViewerStatus(factory, session, auth.UserID, t.ID)
+
+		result := response.Toot()
+		result.Account = user.Toot()
+		result.CreatedAt = time.Unix(response.CreateDateSeconds(), 0).UTC().Format(time.RFC3339)
+		result.Reblogged = original.Reblogged
+		result.Favourited = original.Favourited
+		result.Bookmarked = original.Bookmarked
+		result.Reblog = &original
+
+		return result, nil
 	}
 }
 
 // https://docs.joinmastodon.org/methods/statuses/#unreblog
 func PostStatus_Unreblog(serverFactory *server.Factory) func(model.Authorization, txn.PostStatus_Unreblog) (object.Status, error) {
 
+	const location = "handler.mastodon.PostStatus_Unreblog"
+
 	return func(auth model.Authorization, t txn.PostStatus_Unreblog) (object.Status, error) {
-		return object.Status{}, derp.NotImplemented("handler.mastodon.PostStatus_Unreblog")
+
+		// Get the factory for this domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Search for the Announce in the database
+		responseService := factory.Response()
+		response := model.NewResponse()
+
+		if err := responseService.LoadByUserAndObject(session, auth.UserID, t.ID, vocab.ActivityTypeAnnounce, &response); err != nil {
+
+			// If the response doesn't exist, then there is nothing to undo
+			if derp.IsNotFound(err) {
+				return getViewerStatus(factory, session, auth.UserID, t.ID), nil
+			}
+
+			// Otherwise, return a legitimate error
+			return object.Status{}, derp.Wrap(err, location, "Loading response")
+		}
+
+		// Fall through means a response exists.  Delete it (which publishes the Undo activity)
+		if err := responseService.Delete(session, &response, "Deleted via Mastodon API"); err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Deleting response")
+		}
+
+		// Return the original Status, which the User no longer reblogs
+		return getViewerStatus(factory, session, auth.UserID, t.ID), nil
 	}
 }
 
 // https://docs.joinmastodon.org/methods/statuses/#bookmark
 func PostStatus_Bookmark(serverFactory *server.Factory) func(model.Authorization, txn.PostStatus_Bookmark) (object.Status, error) {
 
+	const location = "handler.mastodon.PostStatus_Bookmark"
+
 	return func(auth model.Authorization, t txn.PostStatus_Bookmark) (object.Status, error) {
-		return object.Status{}, derp.NotImplemented("handler.mastodon.PostStatus_Bookmark")
+
+		// Get the factory for this domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Bookmarks are private to the User, so nothing is published to ActivityPub
+		bookmark, err := factory.Bookmark().SetBookmark(session, auth.UserID, t.ID)
+
+		if err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Saving bookmark")
+		}
+
+		return getBookmarkStatus(factory, auth.UserID, bookmark, true), nil
 	}
 }
 
 // https://docs.joinmastodon.org/methods/statuses/#unbookmark
 func PostStatus_Unbookmark(serverFactory *server.Factory) func(model.Authorization, txn.PostStatus_Unbookmark) (object.Status, error) {
 
+	const location = "handler.mastodon.PostStatus_Unbookmark"
+
 	return func(auth model.Authorization, t txn.PostStatus_Unbookmark) (object.Status, error) {
-		return object.Status{}, derp.NotImplemented("handler.mastodon.PostStatus_Unbookmark")
+
+		// Get the factory for this domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Unrecognized Domain")
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Remove the Bookmark (if it exists)
+		bookmark, err := factory.Bookmark().UnsetBookmark(session, auth.UserID, t.ID)
+
+		if err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Removing bookmark")
+		}
+
+		return getBookmarkStatus(factory, auth.UserID, bookmark, false), nil
 	}
 }
 
@@ -432,16 +661,95 @@ func PostStatus_Unmute(serverFactory *server.Factory) func(model.Authorization,
 // https://docs.joinmastodon.org/methods/statuses/#pin
 func PostStatus_Pin(serverFactory *server.Factory) func(model.Authorization, txn.PostStatus_Pin) (object.Status, error) {
 
+	const location = "handler.mastodon.PostStatus_Pin"
+
 	return func(auth model.Authorization, t txn.PostStatus_Pin) (object.Status, error) {
-		return object.Status{}, derp.NotImplemented("handler.mastodon.PostStatus_Pin")
+
+		// Get the Stream from the URL
+		factory, streamService, stream, err := getStreamFromURL(serverFactory, t.ID)
+
+		if err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Loading stream")
+		}
+
+		// Validate that this user is allowed to pin this Stream.  Pinning is an
+		// author-only operation, matching the Mastodon API contract.
+		if err := userOwnsStream(&auth, &stream); err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Pinning stream")
+		}
+
+		// RULE: Only posts in the User's outbox can be pinned to their profile
+		if stream.ParentID != stream.AttributedTo.UserID {
+			return object.Status{}, derp.BadRequest(location, "Only posts in your outbox can be pinned", stream.StreamID)
+		}
+
+		// Nothing to do if the Stream is already pinned
+		if stream.IsFeatured {
+			return stream.Toot(), nil
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Featured Streams are served in the User's ActivityPub "featured" collection
+		stream.IsFeatured = true
+
+		if err := streamService.Save(session, &stream, "Pinned via Mastodon API"); err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Saving stream")
+		}
+
+		return stream.Toot(), nil
 	}
 }
 
 // https://docs.joinmastodon.org/methods/statuses/#unpin
 func PostStatus_Unpin(serverFactory *server.Factory) func(model.Authorization, txn.PostStatus_Unpin) (object.Status, error) {
 
+	const location = "handler.mastodon.PostStatus_Unpin"
+
 	return func(auth model.Authorization, t txn.PostStatus_Unpin) (object.Status, error) {
-		return object.Status{}, derp.NotImplemented("handler.mastodon.PostStatus_Unpin")
+
+		// Get the Stream from the URL
+		factory, streamService, stream, err := getStreamFromURL(serverFactory, t.ID)
+
+		if err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Loading stream")
+		}
+
+		// Validate that this user is allowed to unpin this Stream.  Pinning is an
+		// author-only operation, matching the Mastodon API contract.
+		if err := userOwnsStream(&auth, &stream); err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Unpinning stream")
+		}
+
+		// Nothing to do if the Stream is already unpinned
+		if !stream.IsFeatured {
+			return stream.Toot(), nil
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Featured Streams are served in the User's ActivityPub "featured" collection
+		stream.IsFeatured = false
+
+		if err := streamService.Save(session, &stream, "Unpinned via Mastodon API"); err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Saving stream")
+		}
+
+		return stream.Toot(), nil
 	}
 }
 
@@ -491,9 +799,16 @@ func PutStatus(serverFactory *server.Factory) func(model.Authorization, txn.PutS
 		// t.MediaIDs
 		// t.Poll info...
 
-		// Save the stream to the database
-		if err := streamService.Save(session, &stream, "Edited via Mastodon API"); err != nil {
-			return object.Status{}, derp.Wrap(err, location, "Saving stream")
+		// Load the User who is editing the stream
+		user := model.NewUser()
+
+		if err := factory.User().LoadByID(session, auth.UserID, &user); err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Loading user")
+		}
+
+		// Save the stream and send an "Update" to the User's followers
+		if err := streamService.PublishOutboxPost(session, &user, &stream, stream.StateID, true); err != nil {
+			return object.Status{}, derp.Wrap(err, location, "Publishing stream")
 		}
 
 		return stream.Toot(), nil
@@ -1,8 +1,11 @@
 package mastodon
 
 import (
+	"time"
+
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/derp"
 	"github.com/benpate/toot/object"
 	"github.com/benpate/toot/txn"
 )
@@ -10,21 +13,113 @@ import (
 // https://docs.joinmastodon.org/methods/tags/
 func GetTag(serverFactory *server.Factory) func(model.Authorization, txn.GetTag) (object.Tag, error) {
 
-	return func(model.Authorization, txn.GetTag) (object.Tag, error) {
-		return object.Tag{}, nil
+	const location = "handler.mastodon.GetTag"
+
+	return func(auth model.Authorization, t txn.GetTag) (object.Tag, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return object.Tag{}, derp.Wrap(err, location, "Invalid Domain Name", t.Host)
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.Tag{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Look for a FollowedTag for this User
+		followedTag := model.NewFollowedTag()
+
+		if err := factory.FollowedTag().LoadByValue(session, auth.UserID, t.ID, &followedTag); err != nil {
+
+			if !derp.IsNotFound(err) {
+				return object.Tag{}, derp.Wrap(err, location, "Loading followed tag", t.ID)
+			}
+
+			// Tags that are not followed are still valid tags
+			followedTag.SetName(t.ID)
+			result := followedTag.Toot(factory.Host())
+			result.Following = false
+			return result, nil
+		}
+
+		return followedTag.Toot(factory.Host()), nil
 	}
 }
 
+// https://docs.joinmastodon.org/methods/tags/#follow
 func PostTag_Follow(serverFactory *server.Factory) func(model.Authorization, txn.PostTag_Follow) (object.Tag, error) {
 
-	return func(model.Authorization, txn.PostTag_Follow) (object.Tag, error) {
-		return object.Tag{}, nil
+	const location = "handler.mastodon.PostTag_Follow"
+
+	return func(auth model.Authorization, t txn.PostTag_Follow) (object.Tag, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return object.Tag{}, derp.Wrap(err, location, "Invalid Domain Name", t.Host)
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.Tag{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Follow the tag (following a tag twice is not an error)
+		followedTag, err := factory.FollowedTag().Follow(session, auth.UserID, t.ID)
+
+		if err != nil {
+			return object.Tag{}, derp.Wrap(err, location, "Following tag", t.ID)
+		}
+
+		return followedTag.Toot(factory.Host()), nil
 	}
 }
 
+// https://docs.joinmastodon.org/methods/tags/#unfollow
 func PostTag_Unfollow(serverFactory *server.Factory) func(model.Authorization, txn.PostTag_Unfollow) (object.Tag, error) {
 
-	return func(model.Authorization, txn.PostTag_Unfollow) (object.Tag, error) {
-		return object.Tag{}, nil
+	const location = "handler.mastodon.PostTag_Unfollow"
+
+	return func(auth model.Authorization, t txn.PostTag_Unfollow) (object.Tag, error) {
+
+		// Get the factory for this Domain
+		factory, err := serverFactory.ByHostname(t.Host)
+
+		if err != nil {
+			return object.Tag{}, derp.Wrap(err, location, "Invalid Domain Name", t.Host)
+		}
+
+		// Get a database session for this request
+		session, cancel, err := factory.Session(time.Minute)
+
+		if err != nil {
+			return object.Tag{}, derp.Wrap(err, location, "Creating session")
+		}
+
+		defer cancel()
+
+		// Unfollow the tag (unfollowing a tag that is not followed is not an error)
+		if err := factory.FollowedTag().Unfollow(session, auth.UserID, t.ID); err != nil {
+			return object.Tag{}, derp.Wrap(err, location, "Unfollowing tag", t.ID)
+		}
+
+		followedTag := model.NewFollowedTag()
+		followedTag.SetName(t.ID)
+
+		result := followedTag.Toot(factory.Host())
+		result.Following = false
+		return result, nil
 	}
 }
@@ -61,7 +61,14 @@ func GetTimeline_Home(serverFactory *server.Factory) func(model.Authorization, t
 			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Retrieving newsItems")
 		}
 
-		return getSliceOfToots(newsItems), getPageInfo(newsItems), nil
+		// Apply the User's Filters (lists share the "home" context, as in Mastodon)
+		statuses, err := getFilteredStatuses(factory, session, auth.UserID, model.FilterContextHome, newsItems)
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Applying filters")
+		}
+
+		return statuses, getPageInfo(newsItems), nil
 	}
 }
 
@@ -105,6 +112,13 @@ func GetTimeline_List(serverFactory *server.Factory) func(model.Authorization, t
 			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Retrieving newsItems")
 		}
 
-		return getSliceOfToots(newsItems), getPageInfo(newsItems), nil
+		// Apply the User's Filters (lists share the "home" context, as in Mastodon)
+		statuses, err := getFilteredStatuses(factory, session, auth.UserID, model.FilterContextHome, newsItems)
+
+		if err != nil {
+			return nil, toot.PageInfo{}, derp.Wrap(err, location, "Applying filters")
+		}
+
+		return statuses, getPageInfo(newsItems), nil
 	}
 }
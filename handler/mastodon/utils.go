@@ -11,8 +11,13 @@ import (
 	"github.com/benpate/data"
 	"github.com/benpate/derp"
 	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
 	"github.com/benpate/toot"
+	"github.com/benpate/toot/object"
 	"github.com/benpate/toot/txn"
+	"github.com/benpate/uri"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type tootGetter[Result any] interface {
@@ -146,7 +151,7 @@ func userCanStream(factory *service.Factory, session data.Session, authorization
 	}
 
 	// Check permissions for the requested action
-	allowed, err := factory.Permission().UserCan(session, authorization, &template, stream, actionID)
+	allowed, err := factory.Permission().UserCan(session, nil, authorization, &template, stream, actionID)
 
 	if err != nil {
 		return derp.Wrap(err, location, "Checking permissions")
@@ -180,3 +185,117 @@ func userOwnsStream(authorization *model.Authorization, stream *model.Stream) er
 
 	return derp.Forbidden(location, "User is not authorized to modify this stream")
 }
+
+// getAccountFromDocument maps an ActivityStreams actor into a Mastodon Account.
+func getAccountFromDocument(document streams.Document) object.Account {
+
+	result := object.Account{
+		ID:          document.ID(),
+		Username:    document.PreferredUsername(),
+		Acct:        document.ID(),
+		DisplayName: document.Name(),
+		Note:        document.Summary(),
+		URL:         document.URL(),
+		Avatar:      document.Icon().Href(),
+	}
+
+	if result.Username != "" {
+		result.Acct = result.Username + "@" + uri.Hostname(result.ID)
+	}
+
+	return result
+}
+
+// getStatusFromDocument maps an ActivityStreams object into a Mastodon Status.  The
+// object's author is loaded through the same client, so it is usually served from the
+// ActivityStream cache.
+func getStatusFromDocument(document streams.Document) object.Status {
+
+	result := object.Status{
+		ID:          document.ID(),
+		URI:         document.ID(),
+		URL:         document.URL(),
+		Content:     document.Content(),
+		SpoilerText: document.Summary(),
+		InReplyToID: document.InReplyTo().ID(),
+	}
+
+	if published := document.Published(); !published.IsZero() {
+		result.CreatedAt = published.UTC().Format(time.RFC3339)
+	}
+
+	if result.URL == "" {
+		result.URL = result.URI
+	}
+
+	// Load the author.  If that fails, the Status still identifies who wrote it.
+	attributedTo := document.AttributedTo()
+
+	if author, err := attributedTo.Load(); err == nil {
+		result.Account = getAccountFromDocument(author)
+	} else {
+		result.Account = object.Account{ID: attributedTo.ID()}
+	}
+
+	return result
+}
+
+// getBookmarkStatus returns a full Mastodon Status for a bookmarked document, loaded
+// from the ActivityStream cache.  If the document can't be loaded, the Bookmark's own
+// summary is returned so that one unreachable server can't break the whole list.
+func getBookmarkStatus(factory *service.Factory, userID primitive.ObjectID, bookmark model.Bookmark, bookmarked bool) object.Status {
+
+	const location = "handler.mastodon.getBookmarkStatus"
+
+	result := bookmark.Toot()
+
+	document, err := factory.ActivityStream().UserClient(userID).Load(bookmark.URL)
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, location, "Loading bookmarked document", bookmark.URL))
+	} else {
+		status := getStatusFromDocument(document)
+
+		if status.CreatedAt == "" {
+			status.CreatedAt = result.CreatedAt
+		}
+
+		result = status
+	}
+
+	result.Bookmarked = bookmarked
+	return result
+}
+
+// getViewerStatus returns a full Mastodon Status for a document, loaded from the ActivityStream
+// cache, along with the viewer's own state: whether they have reblogged, favourited, or
+// bookmarked it.  If the document can't be loaded, a Status that only identifies it is returned.
+func getViewerStatus(factory *service.Factory, session data.Session, userID primitive.ObjectID, documentURL string) object.Status {
+
+	const location = "handler.mastodon.getViewerStatus"
+
+	result := object.Status{
+		ID:  documentURL,
+		URI: documentURL,
+		URL: documentURL,
+	}
+
+	if document, err := factory.ActivityStream().UserClient(userID).Load(documentURL); err == nil {
+		result = getStatusFromDocument(document)
+	} else {
+		derp.Report(derp.Wrap(err, location, "Loading document", documentURL))
+	}
+
+	responseService := factory.Response()
+	response := model.NewResponse()
+
+	result.Reblogged = (responseService.LoadByUserAndObject(session, userID, documentURL, vocab.ActivityTypeAnnounce, &response) == nil)
+
+	response = model.NewResponse()
+	result.Favourited = (responseService.LoadByUserAndObject(session, userID, documentURL, vocab.ActivityTypeLike, &response) == nil)
+
+	bookmark := model.NewBookmark()
+	result.Bookmarked = (factory.Bookmark().LoadByURL(session, userID, documentURL, &bookmark) == nil)
+
+	return result
+}
@@ -0,0 +1,299 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/steranko"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// mastodonMediaScope is the OAuth scope required to upload and edit media
+const mastodonMediaScope = "write:media"
+
+// mastodonMediaMaxMemory is the amount of an upload that is held in memory
+// before the remainder is written to a temporary file
+const mastodonMediaMaxMemory = 32 << 20
+
+// mastodonMediaMaxSize limits the total size of a media upload
+const mastodonMediaMaxSize = 100 << 20
+
+//////////////////////////////////////////
+// Mastodon Media API Handlers
+// https://docs.joinmastodon.org/methods/media/
+//////////////////////////////////////////
+
+// PostMastodonMedia uploads a media file, and processes it before responding (API v1)
+func PostMastodonMedia(ctx *steranko.Context, factory *service.Factory, session data.Session) error {
+
+	const location = "handler.PostMastodonMedia"
+
+	attachment, err := uploadMastodonMedia(ctx, factory, session)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Uploading media")
+	}
+
+	if err := factory.Attachment().ProcessMedia(session, &attachment); err != nil {
+		return derp.Wrap(err, location, "Processing media", attachment.AttachmentID)
+	}
+
+	return ctx.JSON(http.StatusOK, newMastodonMediaAttachment(attachment))
+}
+
+// PostMastodonMediaV2 uploads a media file, and processes it in the background.
+// Clients poll GetMastodonMedia until the file is ready (API v2)
+func PostMastodonMediaV2(ctx *steranko.Context, factory *service.Factory, session data.Session) error {
+
+	const location = "handler.PostMastodonMediaV2"
+
+	attachment, err := uploadMastodonMedia(ctx, factory, session)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Uploading media")
+	}
+
+	factory.Attachment().ScheduleProcessMedia(session, &attachment)
+
+	return ctx.JSON(http.StatusAccepted, newMastodonMediaAttachment(attachment))
+}
+
+// GetMastodonMedia returns a media file that has not been attached to a status yet.
+// Files that are still being processed return 206 (Partial Content)
+func GetMastodonMedia(ctx *steranko.Context, factory *service.Factory, session data.Session) error {
+
+	const location = "handler.GetMastodonMedia"
+
+	attachment, err := loadMastodonMedia(ctx, factory, session)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Loading media")
+	}
+
+	if !attachment.IsReady() {
+		return ctx.JSON(http.StatusPartialContent, newMastodonMediaAttachment(attachment))
+	}
+
+	return ctx.JSON(http.StatusOK, newMastodonMediaAttachment(attachment))
+}
+
+// PutMastodonMedia updates the description (alt text) and focal point of a media
+// file that has not been attached to a status yet
+func PutMastodonMedia(ctx *steranko.Context, factory *service.Factory, session data.Session) error {
+
+	const location = "handler.PutMastodonMedia"
+
+	transaction := struct {
+		Description *string `form:"description" json:"description"`
+		Focus       string  `form:"focus"       json:"focus"`
+	}{}
+
+	if err := ctx.Bind(&transaction); err != nil {
+		return derp.Wrap(err, location, "Binding input", derp.WithBadRequest())
+	}
+
+	attachment, err := loadMastodonMedia(ctx, factory, session)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Loading media")
+	}
+
+	if transaction.Description != nil {
+		attachment.Description = *transaction.Description
+	}
+
+	if (transaction.Focus != "") && !attachment.SetFocus(transaction.Focus) {
+		return derp.Validation("Focal point must be two numbers between -1.0 and 1.0", transaction.Focus)
+	}
+
+	if err := factory.Attachment().Save(session, &attachment, "Updated media"); err != nil {
+		return derp.Wrap(err, location, "Saving media", attachment.AttachmentID)
+	}
+
+	return ctx.JSON(http.StatusOK, newMastodonMediaAttachment(attachment))
+}
+
+// uploadMastodonMedia authorizes a media upload, and saves the uploaded file
+func uploadMastodonMedia(ctx *steranko.Context, factory *service.Factory, session data.Session) (model.Attachment, error) {
+
+	const location = "handler.uploadMastodonMedia"
+
+	authorization, err := mastodonMediaAuthorization(ctx, factory, session)
+
+	if err != nil {
+		return model.Attachment{}, derp.Wrap(err, location, "Authorizing request")
+	}
+
+	request := ctx.Request()
+	request.Body = http.MaxBytesReader(ctx.Response(), request.Body, mastodonMediaMaxSize)
+
+	if err := request.ParseMultipartForm(mastodonMediaMaxMemory); err != nil {
+		return model.Attachment{}, derp.Wrap(err, location, "Parsing multipart form", derp.WithBadRequest())
+	}
+
+	source, fileHeader, err := request.FormFile("file")
+
+	if err != nil {
+		return model.Attachment{}, derp.Wrap(err, location, "Reading uploaded file", derp.WithBadRequest())
+	}
+
+	//nolint:errcheck
+	defer source.Close()
+
+	attachment, err := factory.Attachment().UploadMedia(
+		session,
+		authorization.UserID,
+		fileHeader.Filename,
+		source,
+		request.FormValue("description"),
+		request.FormValue("focus"),
+	)
+
+	if err != nil {
+		return model.Attachment{}, derp.Wrap(err, location, "Saving uploaded file")
+	}
+
+	return attachment, nil
+}
+
+// loadMastodonMedia authorizes a request, and loads the media file in the URL
+func loadMastodonMedia(ctx *steranko.Context, factory *service.Factory, session data.Session) (model.Attachment, error) {
+
+	const location = "handler.loadMastodonMedia"
+
+	authorization, err := mastodonMediaAuthorization(ctx, factory, session)
+
+	if err != nil {
+		return model.Attachment{}, derp.Wrap(err, location, "Authorizing request")
+	}
+
+	attachmentID, err := primitive.ObjectIDFromHex(ctx.Param("id"))
+
+	if err != nil {
+		return model.Attachment{}, derp.Wrap(err, location, "Invalid media ID", ctx.Param("id"), derp.WithNotFound())
+	}
+
+	attachment := model.NewAttachment(model.AttachmentObjectTypeUser, authorization.UserID)
+
+	if err := factory.Attachment().LoadMedia(session, authorization.UserID, attachmentID, &attachment); err != nil {
+		return model.Attachment{}, derp.Wrap(err, location, "Loading media", attachmentID)
+	}
+
+	return attachment, nil
+}
+
+// mastodonMediaAuthorization validates the access token for a media request
+func mastodonMediaAuthorization(ctx *steranko.Context, factory *service.Factory, session data.Session) (model.Authorization, error) {
+
+	const location = "handler.mastodonMediaAuthorization"
+
+	authorization, err := mastodonAuthorization(factory, session, mastodonAccessToken(ctx))
+
+	if err != nil {
+		return model.Authorization{}, derp.Wrap(err, location, "Invalid access token")
+	}
+
+	if !authorization.HasScope(mastodonMediaScope) {
+		return model.Authorization{}, derp.Forbidden(location, "Access token does not include the required scope", mastodonMediaScope)
+	}
+
+	return authorization, nil
+}
+
+//////////////////////////////////////////
+// Mastodon MediaAttachment Entity
+// https://docs.joinmastodon.org/entities/MediaAttachment/
+//////////////////////////////////////////
+
+// mastodonMediaAttachment is the JSON representation of an uploaded media file
+type mastodonMediaAttachment struct {
+	ID          string                      `json:"id"`
+	Type        string                      `json:"type"`
+	URL         *string                     `json:"url"`
+	PreviewURL  *string                     `json:"preview_url"`
+	RemoteURL   *string                     `json:"remote_url"`
+	Meta        mastodonMediaAttachmentMeta `json:"meta"`
+	Description *string                     `json:"description"`
+	BlurHash    *string                     `json:"blurhash"`
+}
+
+// mastodonMediaAttachmentMeta contains the dimensions and focal point of a media file
+type mastodonMediaAttachmentMeta struct {
+	Original *mastodonMediaAttachmentSize  `json:"original,omitempty"`
+	Focus    *mastodonMediaAttachmentFocus `json:"focus,omitempty"`
+}
+
+type mastodonMediaAttachmentSize struct {
+	Width  int     `json:"width"`
+	Height int     `json:"height"`
+	Size   string  `json:"size"`
+	Aspect float64 `json:"aspect"`
+}
+
+type mastodonMediaAttachmentFocus struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// newMastodonMediaAttachment converts an Attachment into a Mastodon MediaAttachment.
+// Like Mastodon, the URLs are null until the file has finished processing.
+func newMastodonMediaAttachment(attachment model.Attachment) mastodonMediaAttachment {
+
+	result := mastodonMediaAttachment{
+		ID:   attachment.AttachmentID.Hex(),
+		Type: mastodonMediaType(attachment),
+	}
+
+	if attachment.IsReady() {
+		result.URL = &attachment.URL
+		result.PreviewURL = &attachment.URL
+	}
+
+	if attachment.Description != "" {
+		result.Description = &attachment.Description
+	}
+
+	if attachment.BlurHash != "" {
+		result.BlurHash = &attachment.BlurHash
+	}
+
+	if attachment.HasDimensions() {
+		result.Meta.Original = &mastodonMediaAttachmentSize{
+			Width:  attachment.Width,
+			Height: attachment.Height,
+			Size:   fmt.Sprintf("%dx%d", attachment.Width, attachment.Height),
+			Aspect: float64(attachment.Width) / float64(attachment.Height),
+		}
+	}
+
+	if attachment.HasFocus() {
+		result.Meta.Focus = &mastodonMediaAttachmentFocus{
+			X: attachment.FocusX,
+			Y: attachment.FocusY,
+		}
+	}
+
+	return result
+}
+
+// mastodonMediaType returns the Mastodon media type (image, gifv, video, audio, unknown) of an Attachment
+func mastodonMediaType(attachment model.Attachment) string {
+
+	switch attachment.MimeCategory() {
+
+	case model.AttachmentMediaTypeImage:
+		return "image"
+
+	case model.AttachmentMediaTypeVideo:
+		return "video"
+
+	case model.AttachmentMediaTypeAudio:
+		return "audio"
+	}
+
+	return "unknown"
+}
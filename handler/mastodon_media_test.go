@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TestMastodonMediaAttachment_Working confirms that URLs are null until processing is complete
+func TestMastodonMediaAttachment_Working(t *testing.T) {
+
+	attachment := model.NewAttachment(model.AttachmentObjectTypeUser, primitive.NewObjectID())
+	attachment.ContentType = "video/mp4"
+	attachment.URL = "https://example.com/@123/attachments/456"
+	attachment.Status = model.AttachmentStatusWorking
+
+	result, err := json.Marshal(newMastodonMediaAttachment(attachment))
+	require.Nil(t, err)
+	require.JSONEq(t, `{
+		"id": "`+attachment.AttachmentID.Hex()+`",
+		"type": "video",
+		"url": null,
+		"preview_url": null,
+		"remote_url": null,
+		"meta": {},
+		"description": null,
+		"blurhash": null
+	}`, string(result))
+}
+
+func TestMastodonMediaAttachment_Ready(t *testing.T) {
+
+	attachment := model.NewAttachment(model.AttachmentObjectTypeUser, primitive.NewObjectID())
+	attachment.ContentType = "image/png"
+	attachment.URL = "https://example.com/@123/attachments/456"
+	attachment.Status = model.AttachmentStatusReady
+	attachment.Description = "A cat asleep on a keyboard"
+	attachment.BlurHash = "LEHV6nWB2yk8pyo0adR*.7kCMdnj"
+	attachment.Width = 200
+	attachment.Height = 100
+	attachment.SetFocus("0.5,-0.25")
+
+	result, err := json.Marshal(newMastodonMediaAttachment(attachment))
+	require.Nil(t, err)
+	require.JSONEq(t, `{
+		"id": "`+attachment.AttachmentID.Hex()+`",
+		"type": "image",
+		"url": "https://example.com/@123/attachments/456",
+		"preview_url": "https://example.com/@123/attachments/456",
+		"remote_url": null,
+		"meta": {
+			"original": {"width": 200, "height": 100, "size": "200x100", "aspect": 2},
+			"focus": {"x": 0.5, "y": -0.25}
+		},
+		"description": "A cat asleep on a keyboard",
+		"blurhash": "LEHV6nWB2yk8pyo0adR*.7kCMdnj"
+	}`, string(result))
+}
+
+func TestMastodonMediaType(t *testing.T) {
+
+	attachment := model.NewAttachment(model.AttachmentObjectTypeUser, primitive.NewObjectID())
+
+	attachment.ContentType = "audio/mpeg"
+	require.Equal(t, "audio", mastodonMediaType(attachment))
+
+	attachment.ContentType = "application/pdf"
+	require.Equal(t, "unknown", mastodonMediaType(attachment))
+}
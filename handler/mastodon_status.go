@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/handler/mastodon"
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/derp"
+	"github.com/benpate/toot/txn"
+	"github.com/labstack/echo/v4"
+)
+
+// mastodonStatusScope is the OAuth scope required to create statuses
+const mastodonStatusScope = "write:statuses"
+
+// PostMastodonStatus creates a new status (API v1).  This is routed outside of toot because
+// requests with a future "scheduled_at" date respond with a ScheduledStatus instead of a Status.
+// https://docs.joinmastodon.org/methods/statuses/#create
+func PostMastodonStatus(serverFactory *server.Factory) echo.HandlerFunc {
+
+	const location = "handler.PostMastodonStatus"
+
+	authorizer := mastodon.Authorizer(serverFactory)
+	postStatus := mastodon.PostStatus(serverFactory)
+	postScheduledStatus := mastodon.PostScheduledStatus(serverFactory)
+
+	return func(ctx echo.Context) error {
+
+		// Authenticate the request
+		authorization, err := authorizer(ctx.Request())
+
+		if err != nil {
+			return derp.Wrap(err, location, "Invalid access token")
+		}
+
+		if !authorization.HasScope(mastodonStatusScope) {
+			return derp.Forbidden(location, "Access token does not include the required scope", mastodonStatusScope)
+		}
+
+		// Collect the request body
+		transaction := txn.PostStatus{}
+
+		if err := ctx.Bind(&transaction); err != nil {
+			return derp.Wrap(err, location, "Invalid request body")
+		}
+
+		transaction.Host = serverFactory.Hostname(ctx.Request())
+
+		// Scheduled statuses respond with a ScheduledStatus
+		if mastodon.IsScheduledStatus(transaction) {
+
+			result, err := postScheduledStatus(authorization, transaction)
+
+			if err != nil {
+				return derp.Wrap(err, location, "Scheduling status")
+			}
+
+			return ctx.JSON(http.StatusOK, result)
+		}
+
+		// Everything else is published immediately
+		result, err := postStatus(authorization, transaction)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Posting status")
+		}
+
+		return ctx.JSON(http.StatusOK, result)
+	}
+}
@@ -0,0 +1,493 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/realtime"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/steranko"
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/net/websocket"
+)
+
+// mastodonStreamingHeartbeat is how often streaming connections are sent an SSE comment
+// or a WebSocket ping, so that proxies do not close them.
+const mastodonStreamingHeartbeat = 30 * time.Second
+
+// mastodonStreamingMaxSubscriptions caps the number of streams that a single WebSocket
+// connection can subscribe to.
+const mastodonStreamingMaxSubscriptions = 32
+
+//////////////////////////////////////////
+// Mastodon Streaming API Handlers
+// https://docs.joinmastodon.org/methods/streaming/
+//////////////////////////////////////////
+
+// GetMastodonStreamingHealth reports that the streaming API is available
+func GetMastodonStreamingHealth(ctx echo.Context) error {
+	return ctx.String(http.StatusOK, "OK")
+}
+
+// GetMastodonStreaming opens a WebSocket connection that can subscribe to any number of
+// streams.  Requests that are not WebSocket upgrades are served as SSE, using the stream
+// named in the "stream" query parameter.
+func GetMastodonStreaming(ctx *steranko.Context, factory *service.Factory, session data.Session) error {
+
+	const location = "handler.GetMastodonStreaming"
+
+	if !strings.EqualFold(ctx.Request().Header.Get("Upgrade"), "websocket") {
+		return mastodonStreamingSSE(ctx, factory, session, ctx.QueryParam("stream"))
+	}
+
+	client, err := newMastodonStreamingClient(ctx, factory, session)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Authorizing streaming client")
+	}
+
+	defer client.close()
+
+	// Subscribe to the stream in the URL, if present
+	if stream := ctx.QueryParam("stream"); stream != "" {
+		if err := client.subscribe(stream, ctx.QueryParam("tag"), ctx.QueryParam("list")); err != nil {
+			return derp.Wrap(err, location, "Subscribing to stream", stream)
+		}
+	}
+
+	server := websocket.Server{
+
+		// Clients may send their access token as the WebSocket subprotocol, which must be
+		// echoed back.  Origins are not checked because the access token is required.
+		Handshake: func(config *websocket.Config, _ *http.Request) error {
+			if len(config.Protocol) > 1 {
+				config.Protocol = config.Protocol[:1]
+			}
+			return nil
+		},
+
+		Handler: client.serveWebSocket,
+	}
+
+	server.ServeHTTP(ctx.Response(), ctx.Request())
+	return nil
+}
+
+// GetMastodonStreamingSSE returns a handler that serves a single stream as SSE
+func GetMastodonStreamingSSE(stream string) WithFunc0 {
+	return func(ctx *steranko.Context, factory *service.Factory, session data.Session) error {
+		return mastodonStreamingSSE(ctx, factory, session, stream)
+	}
+}
+
+// mastodonStreamingSSE serves a single stream as Server-Sent Events
+func mastodonStreamingSSE(ctx *steranko.Context, factory *service.Factory, session data.Session, stream string) error {
+
+	const location = "handler.mastodonStreamingSSE"
+
+	client, err := newMastodonStreamingClient(ctx, factory, session)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Authorizing streaming client")
+	}
+
+	defer client.close()
+
+	if err := client.subscribe(stream, ctx.QueryParam("tag"), ctx.QueryParam("list")); err != nil {
+		return derp.Wrap(err, location, "Subscribing to stream", stream)
+	}
+
+	// Cap the lifetime of a connection, just like handler.serverSentEvent
+	timeoutContext, cancel := context.WithTimeout(ctx.Request().Context(), 30*24*time.Hour)
+	defer cancel()
+
+	w := ctx.Response().Writer
+	f, ok := w.(http.Flusher)
+
+	if !ok {
+		return derp.Internal(location, "Streaming Not Supported")
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", model.MimeTypeEventStream)
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("Transfer-Encoding", "chunked")
+	f.Flush()
+
+	heartbeat := time.NewTicker(mastodonStreamingHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+
+		var message realtime.Message
+
+		select {
+
+		case <-timeoutContext.Done():
+			return nil
+
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ":thump\n\n"); err != nil {
+				return derp.Wrap(err, location, "Writing heartbeat to response")
+			}
+			f.Flush()
+			continue
+
+		case message = <-client.userChannel():
+		case message = <-client.domainChannel():
+		}
+
+		if !client.accepts(message) {
+			continue
+		}
+
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", message.Event, message.Data); err != nil {
+			return derp.Wrap(err, location, "Writing event to response")
+		}
+
+		f.Flush()
+	}
+}
+
+//////////////////////////////////////////
+// Streaming Client
+//////////////////////////////////////////
+
+// mastodonStreamingClient relays realtime messages to a single streaming API connection.
+// Messages for the signed-in User and for the whole domain are published under different
+// ObjectIDs, so each is received through its own realtime.Client, which is only registered
+// with the broker once a matching stream has been subscribed.
+type mastodonStreamingClient struct {
+	factory       *service.Factory
+	session       data.Session
+	request       *http.Request
+	authorization model.Authorization
+	streams       [][]string
+	userClient    *realtime.Client
+	domainClient  *realtime.Client
+}
+
+// mastodonStreamingCommand is a message sent by a WebSocket client to change its subscriptions
+type mastodonStreamingCommand struct {
+	Type   string `json:"type"`
+	Stream string `json:"stream"`
+	Tag    string `json:"tag"`
+	List   string `json:"list"`
+}
+
+// mastodonStreamingError is sent to a WebSocket client when a command fails
+type mastodonStreamingError struct {
+	Error string `json:"error"`
+}
+
+// mastodonStreamingEvent is a message sent to a WebSocket client.  The payload is
+// itself a JSON-encoded string, as in Mastodon.
+type mastodonStreamingEvent struct {
+	Stream  []string `json:"stream"`
+	Event   string   `json:"event"`
+	Payload string   `json:"payload"`
+}
+
+// newMastodonStreamingClient authorizes a streaming request.  Like Mastodon, the access
+// token can be sent in the Authorization header, the "access_token" query parameter, or
+// as the WebSocket subprotocol.  Tokens from revoked OAuth grants are rejected.
+func newMastodonStreamingClient(ctx *steranko.Context, factory *service.Factory, session data.Session) (*mastodonStreamingClient, error) {
+
+	const location = "handler.newMastodonStreamingClient"
+
+	request := ctx.Request()
+	tokenString := mastodonAccessToken(ctx)
+
+	if tokenString == "" {
+		tokenString, _, _ = strings.Cut(request.Header.Get("Sec-WebSocket-Protocol"), ",")
+		tokenString = strings.TrimSpace(tokenString)
+	}
+
+	authorization, err := mastodonAuthorization(factory, session, tokenString)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Authorizing request")
+	}
+
+	result := &mastodonStreamingClient{
+		factory:       factory,
+		session:       session,
+		request:       request,
+		authorization: authorization,
+		streams:       make([][]string, 0),
+	}
+
+	return result, nil
+}
+
+// resolve validates a stream name (plus its tag or list parameter) and returns
+// the stream identifier that realtime messages are published under
+func (client *mastodonStreamingClient) resolve(name string, tag string, list string) ([]string, error) {
+
+	const location = "handler.mastodonStreamingClient.resolve"
+
+	scope := "read:statuses"
+
+	if name == realtime.StreamUserNotification {
+		scope = "read:notifications"
+	}
+
+	if !client.authorization.HasScope(scope) {
+		return nil, derp.Forbidden(location, "Access token does not have the required scope", scope)
+	}
+
+	switch name {
+
+	case realtime.StreamUser, realtime.StreamUserNotification, realtime.StreamPublic:
+		return []string{name}, nil
+
+	case realtime.StreamHashtag:
+
+		if tag = strings.TrimPrefix(tag, "#"); tag == "" {
+			return nil, derp.BadRequest(location, "Hashtag stream requires a tag")
+		}
+
+		return realtime.HashtagStream(tag), nil
+
+	case realtime.StreamList:
+
+		folderID, err := primitive.ObjectIDFromHex(list)
+
+		if err != nil {
+			return nil, derp.BadRequest(location, "List stream requires a valid list ID", list)
+		}
+
+		// RULE: Lists are the User's own Folders
+		folder := model.NewFolder()
+
+		if err := client.factory.Folder().LoadByID(client.session, client.authorization.UserID, folderID, &folder); err != nil {
+			return nil, derp.Wrap(err, location, "Loading list", list)
+		}
+
+		return realtime.ListStream(folder.FolderID.Hex()), nil
+	}
+
+	return nil, derp.BadRequest(location, "Unknown stream", name)
+}
+
+// subscribe adds a stream to this client
+func (client *mastodonStreamingClient) subscribe(name string, tag string, list string) error {
+
+	const location = "handler.mastodonStreamingClient.subscribe"
+
+	stream, err := client.resolve(name, tag, list)
+
+	if err != nil {
+		return err
+	}
+
+	if client.isSubscribed(stream) {
+		return nil
+	}
+
+	if len(client.streams) >= mastodonStreamingMaxSubscriptions {
+		return derp.BadRequest(location, "Too many subscriptions")
+	}
+
+	client.streams = append(client.streams, stream)
+
+	// Register with the broker the first time each kind of stream is used
+	broker := client.factory.RealtimeBroker()
+
+	if isMastodonUserStream(stream) {
+		if client.userClient == nil {
+			client.userClient = realtime.NewClient(client.request, client.authorization.UserID, realtime.TopicStreaming)
+			broker.AddClient <- client.userClient
+		}
+	} else if client.domainClient == nil {
+		client.domainClient = realtime.NewClient(client.request, primitive.NilObjectID, realtime.TopicStreaming)
+		broker.AddClient <- client.domainClient
+	}
+
+	return nil
+}
+
+// unsubscribe removes a stream from this client
+func (client *mastodonStreamingClient) unsubscribe(name string, tag string, list string) error {
+
+	stream, err := client.resolve(name, tag, list)
+
+	if err != nil {
+		return err
+	}
+
+	client.streams = slices.DeleteFunc(client.streams, func(subscribed []string) bool {
+		return slices.Equal(subscribed, stream)
+	})
+
+	return nil
+}
+
+// isSubscribed returns TRUE if this client is subscribed to the provided stream
+func (client *mastodonStreamingClient) isSubscribed(stream []string) bool {
+	return slices.ContainsFunc(client.streams, func(subscribed []string) bool {
+		return slices.Equal(subscribed, stream)
+	})
+}
+
+// accepts returns TRUE if a realtime message belongs to one of this client's streams
+func (client *mastodonStreamingClient) accepts(message realtime.Message) bool {
+	return client.isSubscribed(message.Stream)
+}
+
+// userChannel returns the channel of messages published for the signed-in User.
+// It is nil (and so blocks forever) until a User stream has been subscribed.
+func (client *mastodonStreamingClient) userChannel() chan realtime.Message {
+	if client.userClient == nil {
+		return nil
+	}
+	return client.userClient.WriteChannel
+}
+
+// domainChannel returns the channel of messages published for the whole domain.
+// It is nil (and so blocks forever) until a domain stream has been subscribed.
+func (client *mastodonStreamingClient) domainChannel() chan realtime.Message {
+	if client.domainClient == nil {
+		return nil
+	}
+	return client.domainClient.WriteChannel
+}
+
+// close removes this client from the realtime broker
+func (client *mastodonStreamingClient) close() {
+
+	broker := client.factory.RealtimeBroker()
+
+	if client.userClient != nil {
+		broker.RemoveClient <- client.userClient
+	}
+
+	if client.domainClient != nil {
+		broker.RemoveClient <- client.domainClient
+	}
+}
+
+// serveWebSocket relays events to a WebSocket connection, and applies the subscribe and
+// unsubscribe commands that the client sends.  Subscriptions are only modified on this
+// goroutine, so they need no locking.
+func (client *mastodonStreamingClient) serveWebSocket(conn *websocket.Conn) {
+
+	const location = "handler.mastodonStreamingClient.serveWebSocket"
+
+	commands := make(chan mastodonStreamingCommand)
+	done := make(chan struct{})
+	stop := make(chan struct{})
+
+	defer close(stop)
+
+	// Read commands until the connection is closed
+	go func() {
+
+		defer close(done)
+
+		for {
+			command := mastodonStreamingCommand{}
+
+			if err := websocket.JSON.Receive(conn, &command); err != nil {
+				return
+			}
+
+			select {
+			case commands <- command:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(mastodonStreamingHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+
+		var message realtime.Message
+
+		select {
+
+		case <-done:
+			return
+
+		case <-heartbeat.C:
+			if err := mastodonStreamingPing.Send(conn, nil); err != nil {
+				return
+			}
+			continue
+
+		case command := <-commands:
+			if err := client.applyCommand(command); err != nil {
+				derp.Report(derp.Wrap(err, location, "Applying streaming command", command))
+
+				if err := websocket.JSON.Send(conn, mastodonStreamingError{Error: derp.Message(err)}); err != nil {
+					return
+				}
+			}
+			continue
+
+		case message = <-client.userChannel():
+		case message = <-client.domainChannel():
+		}
+
+		if !client.accepts(message) {
+			continue
+		}
+
+		event := mastodonStreamingEvent{
+			Stream:  message.Stream,
+			Event:   message.Event,
+			Payload: message.Data,
+		}
+
+		if err := websocket.JSON.Send(conn, event); err != nil {
+			return
+		}
+	}
+}
+
+// applyCommand subscribes or unsubscribes a WebSocket client
+func (client *mastodonStreamingClient) applyCommand(command mastodonStreamingCommand) error {
+
+	const location = "handler.mastodonStreamingClient.applyCommand"
+
+	switch command.Type {
+
+	case "subscribe":
+		return client.subscribe(command.Stream, command.Tag, command.List)
+
+	case "unsubscribe":
+		return client.unsubscribe(command.Stream, command.Tag, command.List)
+	}
+
+	return derp.BadRequest(location, "Unknown command type", command.Type)
+}
+
+// isMastodonUserStream returns TRUE if a stream is published under the signed-in User's ID
+func isMastodonUserStream(stream []string) bool {
+
+	switch stream[0] {
+	case realtime.StreamUser, realtime.StreamUserNotification, realtime.StreamList:
+		return true
+	}
+
+	return false
+}
+
+// mastodonStreamingPing sends an empty WebSocket ping frame
+var mastodonStreamingPing = websocket.Codec{
+	Marshal: func(any) ([]byte, byte, error) {
+		return nil, websocket.PingFrame, nil
+	},
+}
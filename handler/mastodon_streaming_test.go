@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/realtime"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMastodonStreaming_Resolve(t *testing.T) {
+
+	client := mastodonStreamingClient{authorization: model.NewAuthorization()}
+	client.authorization.Scope = "read:statuses"
+
+	stream, err := client.resolve(realtime.StreamUser, "", "")
+	require.Nil(t, err)
+	require.Equal(t, []string{"user"}, stream)
+
+	stream, err = client.resolve(realtime.StreamHashtag, "#Cats", "")
+	require.Nil(t, err)
+	require.Equal(t, []string{"hashtag", "cats"}, stream)
+
+	// Hashtag streams require a tag
+	_, err = client.resolve(realtime.StreamHashtag, "", "")
+	require.NotNil(t, err)
+
+	// Notifications require their own scope
+	_, err = client.resolve(realtime.StreamUserNotification, "", "")
+	require.NotNil(t, err)
+
+	client.authorization.Scope = "read"
+	stream, err = client.resolve(realtime.StreamUserNotification, "", "")
+	require.Nil(t, err)
+	require.Equal(t, []string{"user:notification"}, stream)
+
+	// Unknown streams are rejected
+	_, err = client.resolve("direct", "", "")
+	require.NotNil(t, err)
+}
+
+func TestMastodonStreaming_Accepts(t *testing.T) {
+
+	client := mastodonStreamingClient{}
+	client.streams = append(client.streams, []string{"user"}, realtime.HashtagStream("cats"))
+
+	require.True(t, client.accepts(realtime.NewMessage_Streaming(primitive.NilObjectID, []string{"user"}, "update", "{}")))
+	require.True(t, client.accepts(realtime.NewMessage_Streaming(primitive.NilObjectID, []string{"hashtag", "cats"}, "update", "{}")))
+	require.False(t, client.accepts(realtime.NewMessage_Streaming(primitive.NilObjectID, []string{"hashtag", "dogs"}, "update", "{}")))
+	require.False(t, client.accepts(realtime.NewMessage_Streaming(primitive.NilObjectID, []string{"user:notification"}, "notification", "{}")))
+}
+
+func TestMastodonStreaming_IsUserStream(t *testing.T) {
+	require.True(t, isMastodonUserStream([]string{"user"}))
+	require.True(t, isMastodonUserStream([]string{"user:notification"}))
+	require.True(t, isMastodonUserStream(realtime.ListStream("abc")))
+	require.False(t, isMastodonUserStream([]string{"public"}))
+	require.False(t, isMastodonUserStream(realtime.HashtagStream("cats")))
+}
+
+// TestMastodonStreaming_Event confirms that the payload is sent as a JSON-encoded string
+func TestMastodonStreaming_Event(t *testing.T) {
+
+	event := mastodonStreamingEvent{
+		Stream:  []string{"user"},
+		Event:   "delete",
+		Payload: "123",
+	}
+
+	result, err := json.Marshal(event)
+	require.Nil(t, err)
+	require.JSONEq(t, `{"stream":["user"],"event":"delete","payload":"123"}`, string(result))
+}
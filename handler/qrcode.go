@@ -18,6 +18,11 @@ func GetQRCode_User(ctx *steranko.Context, factory *service.Factory, _ data.Sess
 	return getQRCode(ctx, user.ActivityPubURL())
 }
 
+// GetQRCode_TwoFactor generates a QR Code for the signed-in User's pending two-factor enrollment
+func GetQRCode_TwoFactor(ctx *steranko.Context, factory *service.Factory, _ data.Session, user *model.User) error {
+	return getTwoFactorQRCode(ctx, factory, user)
+}
+
 // getQRCode generates a QR Code for the provided URL
 func getQRCode(ctx *steranko.Context, url string) error {
 
@@ -26,8 +31,14 @@ func getQRCode(ctx *steranko.Context, url string) error {
 		url += "?" + rawQuery
 	}
 
+	return writeQRCode(ctx, url)
+}
+
+// writeQRCode writes a QR Code image containing the provided value to the response
+func writeQRCode(ctx *steranko.Context, value string) error {
+
 	// Create a new QR code generator
-	qrc, err := qrcode.New(url)
+	qrc, err := qrcode.New(value)
 
 	if err != nil {
 		return derp.Wrap(err, "build.StepQRCode.Get", "Generating QR Code")
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/build"
+	"github.com/EmissarySocial/emissary/server"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/labstack/echo/v4"
+)
+
+// SetupDomainMasterKeyPost generates a new master key for a domain, and re-encrypts everything
+// that the old master key protected: every actor's private key, and the vaults in every
+// Connection and MerchantAccount.  All records are re-encrypted inside a single transaction.
+// The configuration cannot be part of that transaction, so the new key is saved as "pending"
+// first, and only replaces the master key after the transaction commits.
+func SetupDomainMasterKeyPost(serverFactory *server.SetupFactory) echo.HandlerFunc {
+
+	const location = "handler.SetupDomainMasterKeyPost"
+
+	return func(ctx echo.Context) error {
+
+		// Get the domain configuration
+		domainID := ctx.Param("domain")
+		domainConfig, factory, err := serverFactory.ByDomainID(domainID)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Loading factory")
+		}
+
+		// Generate a new random master key
+		masterKey := make([]byte, 32)
+
+		if _, err := rand.Read(masterKey); err != nil {
+			return derp.Wrap(err, location, "Generating master key")
+		}
+
+		newMasterKey := hex.EncodeToString(masterKey)
+
+		// Save the new key BEFORE re-encrypting anything, so that it cannot be lost if the
+		// server stops after the transaction commits.
+		domainConfig.PendingMasterKey = newMasterKey
+
+		if err := serverFactory.PutDomain(domainConfig); err != nil {
+			return build.WrapInlineError(ctx.Response(), derp.Wrap(err, location, "Saving pending master key", domainID))
+		}
+
+		// Re-encrypt everything
+		_, err = factory.WithTransaction(ctx.Request().Context(), func(session data.Session) (any, error) {
+
+			if err := factory.EncryptionKey().ReEncrypt(session, newMasterKey); err != nil {
+				return nil, derp.Wrap(err, location, "Re-encrypting EncryptionKeys")
+			}
+
+			if err := factory.Connection().ReEncrypt(session, newMasterKey); err != nil {
+				return nil, derp.Wrap(err, location, "Re-encrypting Connections")
+			}
+
+			if err := factory.MerchantAccount().ReEncrypt(session, newMasterKey); err != nil {
+				return nil, derp.Wrap(err, location, "Re-encrypting MerchantAccounts")
+			}
+
+			return nil, nil
+		})
+
+		// If the transaction failed, then the old master key is still in use.  Forget the pending key.
+		if err != nil {
+
+			domainConfig.PendingMasterKey = ""
+
+			if putErr := serverFactory.PutDomain(domainConfig); putErr != nil {
+				derp.Report(derp.Wrap(putErr, location, "Clearing pending master key", domainID))
+			}
+
+			return build.WrapInlineError(ctx.Response(), derp.Wrap(err, location, "Rotating master key", domainID))
+		}
+
+		// Otherwise, the database now uses the new master key.
+		domainConfig.MasterKey = newMasterKey
+		domainConfig.PendingMasterKey = ""
+
+		if err := serverFactory.PutDomain(domainConfig); err != nil {
+			err = derp.Wrap(err, location, "Records were re-encrypted, but the new master key could not be saved. It is still stored in the configuration as the pending master key.", domainID)
+			return build.WrapInlineError(ctx.Response(), err)
+		}
+
+		build.RefreshPage(ctx)
+		return ctx.NoContent(http.StatusOK)
+	}
+}
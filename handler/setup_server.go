@@ -175,6 +175,12 @@ func getSetupForm(name string) (form.Element, bool, error) {
 						{Value: "X-Real-IP"},
 					}}},
 					{Type: "text", Label: "Trusted Proxy Count", Path: "clientIPTrustedCount", Description: "Number of trusted proxies to consider when determining the client's IP address.", Options: mapof.Any{"show-if": "clientIPStrategy is RIGHTMOST-TRUSTED-COUNT"}},
+					{Type: "select", Label: "Realtime Updates", Path: "realtimeTransport", Description: "Shares live updates between every node that runs this server.", Options: mapof.Any{"enum": []form.LookupCode{
+						{Value: "AUTO", Label: "Automatic (use change streams when available)"},
+						{Value: "CHANGE-STREAM", Label: "Change Streams (requires a MongoDB replica set)"},
+						{Value: "CAPPED-COLLECTION", Label: "Capped Collection (works with standalone MongoDB)"},
+						{Value: "MEMORY", Label: "In Memory (single node only)"},
+					}}},
 				}},
 				{Type: "layout-vertical", Label: "Server Ports", Children: []form.Element{
 					{Type: "text", Label: "HTTP", Description: "Port to use for HTTP connections (standard: 80, disabled: 0)", Path: "httpPort", Options: mapof.Any{"format": "number", "min": 0, "max:": 65535}},
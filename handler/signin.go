@@ -43,8 +43,10 @@ func GetSignIn(ctx *steranko.Context, factory *service.Factory, session data.Ses
 // PostSignIn generates an echo.HandlerFunc that handles POST /signin requests
 func PostSignIn(ctx *steranko.Context, factory *service.Factory, session data.Session) error {
 
-	// Try to sign in using Steranko
-	user, err := factory.Steranko(session).SigninFormPost(ctx)
+	const location = "handler.PostSignIn"
+
+	// Verify the password WITHOUT signing in yet
+	user, err := authenticatePassword(ctx, factory, session)
 
 	if err != nil {
 		messageJSON, _ := json.Marshal(map[string]string{"SigninError": derp.Message(err)})
@@ -54,18 +56,88 @@ func PostSignIn(ctx *steranko.Context, factory *service.Factory, session data.Se
 
 	// If there is a "next" parameter, then redirect to that URL.  Otherwise, redirect to the user's profile.
 	next := calcNextURL(ctx.QueryParam("next"))
-	ctx.Response().Header().Add("Hx-Redirect", next)
 
-	// Add user's Activity Intent data to the response.
-	if user, isAlwaysOK := user.(*model.User); isAlwaysOK {
+	// RULE: Users with a second factor must enter it before they are signed in
+	if needsTwoFactor(ctx, factory, session, &user) {
+		return beginTwoFactor(ctx, factory, &user, next)
+	}
+
+	if err := factory.Steranko(session).SigninUser(ctx, &user); err != nil {
+		return derp.Wrap(err, location, "Signing in user", user.Username)
+	}
+
+	return writeSigninRedirect(ctx, &user, next)
+}
+
+// authenticatePassword verifies the username and password from the signin form, but does
+// not sign the User in.  This lets PostSignIn issue the authentication cookie only after
+// any second factor has been verified.  Lockouts and failed attempts are tracked the same
+// way as every other signin.
+func authenticatePassword(ctx *steranko.Context, factory *service.Factory, session data.Session) (model.User, error) {
+
+	const location = "handler.authenticatePassword"
+
+	var txn struct {
+		Username string `form:"username"`
+		Password string `form:"password"`
+	}
+
+	if err := ctx.Bind(&txn); err != nil {
+		return model.User{}, derp.Wrap(err, location, "Reading form data")
+	}
+
+	request := ctx.Request()
+	signinService := factory.SterankoSigninService(session)
 
-		message := mapof.Any{"signin-account": user.ActivityIntentProfile()}
+	// RULE: Locked accounts cannot sign in, even with the correct password
+	if signinService.IsSigninLocked(request, txn.Username) {
+		return model.User{}, derp.Forbidden(location, "Too many signin attempts. Please try again later.")
+	}
+
+	// Load the User and compare passwords.  Unknown usernames and wrong passwords
+	// return the same error, so that usernames cannot be discovered this way.
+	user := model.NewUser()
+
+	if err := factory.SterankoUserService(session).Load(txn.Username, &user); err != nil {
+		signinService.SigninFailure(request, txn.Username)
+		return model.User{}, derp.Unauthorized(location, "Invalid username or password")
+	}
+
+	sterankoService := factory.Steranko(session)
+	matches, rehash := sterankoService.ComparePassword(txn.Password, user.Password)
+
+	if !matches {
+		signinService.SigninFailure(request, txn.Username)
+		return model.User{}, derp.Unauthorized(location, "Invalid username or password")
+	}
+
+	signinService.SigninSuccess(request, txn.Username)
 
-		if messageJSON, err := json.Marshal(message); err == nil {
-			ctx.Response().Header().Add("Hx-Trigger", string(messageJSON))
+	// Upgrade passwords that were stored with an older hashing policy
+	if rehash {
+		if err := sterankoService.SetPassword(&user, txn.Password); err != nil {
+			derp.Report(derp.Wrap(err, location, "Re-hashing password", user.Username))
+		} else if err := factory.User().Save(session, &user, "Re-hashed password"); err != nil {
+			derp.Report(derp.Wrap(err, location, "Saving re-hashed password", user.Username))
 		}
 	}
 
+	return user, nil
+}
+
+// writeSigninRedirect forwards a newly signed-in User to the "next" URL, along with
+// their Activity Intent data.
+func writeSigninRedirect(ctx *steranko.Context, user *model.User, next string) error {
+
+	// Add user's Activity Intent data to the response.
+	message := mapof.Any{"signin-account": user.ActivityIntentProfile()}
+
+	if messageJSON, err := json.Marshal(message); err == nil {
+		ctx.Response().Header().Add("Hx-Trigger", string(messageJSON))
+	}
+
+	ctx.Response().Header().Add("Hx-Redirect", next)
+
 	/// 3..2..1.. Go!
 	return ctx.NoContent(http.StatusNoContent)
 }
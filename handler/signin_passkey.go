@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/EmissarySocial/emissary/tools/formdata"
+	"github.com/EmissarySocial/emissary/tools/webauthn"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/steranko"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxPasskeyRequestSize limits the size of a passkey response from the browser
+const maxPasskeyRequestSize = 64 * 1024
+
+// passkeyRequest is the form that the browser POSTs after the User responds to a passkey prompt
+type passkeyRequest struct {
+	Token      string          // Challenge token from the matching GET request
+	Credential json.RawMessage // PublicKeyCredential.toJSON()
+	Remember   bool            // TRUE if the User wants to skip the second factor on this device
+}
+
+// GetSignInPasskey returns the options for a passwordless signin with a passkey
+func GetSignInPasskey(ctx *steranko.Context, factory *service.Factory, session data.Session) error {
+
+	const location = "handler.GetSignInPasskey"
+
+	passkeyService := factory.Passkey()
+	challenge, token, err := passkeyService.NewChallenge(service.PasskeyCeremonySignin, primitive.NilObjectID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Creating challenge")
+	}
+
+	options, err := passkeyService.RequestOptions(session, primitive.NilObjectID, challenge)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Creating request options")
+	}
+
+	return writePasskeyOptions(ctx, token, options)
+}
+
+// PostSignInPasskey signs in the User who owns the passkey that the browser returned.
+// Passkeys verify the User (with a PIN or biometric) on their device, so no other factor is needed.
+func PostSignInPasskey(ctx *steranko.Context, factory *service.Factory, session data.Session) error {
+
+	const location = "handler.PostSignInPasskey"
+
+	transaction, err := readPasskeyRequest(ctx)
+
+	if err != nil {
+		return twoFactorError(ctx, err)
+	}
+
+	passkeyService := factory.Passkey()
+	_, challenge, err := passkeyService.ParseChallenge(service.PasskeyCeremonySignin, transaction.Token)
+
+	if err != nil {
+		return twoFactorError(ctx, derp.Unauthorized(location, "Your signin has expired. Please try again."))
+	}
+
+	assertion, err := webauthn.ParseAssertion(transaction.Credential)
+
+	if err != nil {
+		return twoFactorError(ctx, derp.Wrap(err, location, "Invalid passkey response"))
+	}
+
+	// Find the passkey, and the User who owns it
+	passkey := model.NewPasskey()
+
+	if err := passkeyService.LoadByCredentialID(session, webauthn.EncodeID(assertion.CredentialID), &passkey); err != nil {
+
+		if derp.IsNotFound(err) {
+			return twoFactorError(ctx, derp.Unauthorized(location, "This passkey is not registered on this server."))
+		}
+
+		return derp.Wrap(err, location, "Loading passkey")
+	}
+
+	user := model.NewUser()
+
+	if err := factory.User().LoadByID(session, passkey.UserID, &user); err != nil {
+		return derp.Wrap(err, location, "Loading user", passkey.UserID)
+	}
+
+	// RULE: Users who have moved to another server cannot sign in here
+	if user.MovedTo != "" {
+		return twoFactorError(ctx, derp.Forbidden(location, "This account has moved to a new server."))
+	}
+
+	// RULE: Passkeys share the same lockout as passwords
+	signinService := factory.SterankoSigninService(session)
+
+	if signinService.IsSigninLocked(ctx.Request(), user.Username) {
+		return twoFactorError(ctx, derp.Forbidden(location, "Too many signin attempts. Please try again later."))
+	}
+
+	if err := passkeyService.Authenticate(session, &passkey, challenge, assertion, true); err != nil {
+		derp.Report(derp.Wrap(err, location, "Verifying passkey", user.Username))
+		signinService.PasskeyFailure(ctx.Request(), user.Username)
+		return twoFactorError(ctx, derp.Unauthorized(location, "Your passkey could not be verified. Please try again."))
+	}
+
+	if err := signinService.RecordEvent(ctx.Request(), user.Username, model.SigninEventPasskeyUse); err != nil {
+		derp.Report(derp.Wrap(err, location, "Recording passkey use", user.Username))
+	}
+
+	if err := completeSignin(ctx, factory, session, &user); err != nil {
+		return derp.Wrap(err, location, "Completing signin")
+	}
+
+	return writeSigninRedirect(ctx, &user, calcNextURL(ctx.QueryParam("next")))
+}
+
+// GetSignInTwoFactorPasskey returns the options for using a passkey as a second factor
+func GetSignInTwoFactorPasskey(ctx *steranko.Context, factory *service.Factory, session data.Session) error {
+
+	const location = "handler.GetSignInTwoFactorPasskey"
+
+	user := model.NewUser()
+
+	if err := loadTwoFactorChallenge(ctx, factory, session, &user); err != nil {
+		return derp.Unauthorized(location, "Your signin has expired. Please sign in again.")
+	}
+
+	passkeyService := factory.Passkey()
+	challenge, token, err := passkeyService.NewChallenge(service.PasskeyCeremonyTwoFactor, user.UserID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Creating challenge")
+	}
+
+	options, err := passkeyService.RequestOptions(session, user.UserID, challenge)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Creating request options")
+	}
+
+	return writePasskeyOptions(ctx, token, options)
+}
+
+// PostSignInTwoFactorPasskey verifies a passkey that was used as a second factor, and completes the signin
+func PostSignInTwoFactorPasskey(ctx *steranko.Context, factory *service.Factory, session data.Session) error {
+
+	const location = "handler.PostSignInTwoFactorPasskey"
+
+	user := model.NewUser()
+
+	if err := loadTwoFactorChallenge(ctx, factory, session, &user); err != nil {
+		return twoFactorError(ctx, derp.Unauthorized(location, "Your signin has expired. Please sign in again."))
+	}
+
+	// RULE: Second factors share the same lockout as passwords
+	signinService := factory.SterankoSigninService(session)
+
+	if signinService.IsSigninLocked(ctx.Request(), user.Username) {
+		return twoFactorError(ctx, derp.Forbidden(location, "Too many signin attempts. Please try again later."))
+	}
+
+	transaction, err := readPasskeyRequest(ctx)
+
+	if err != nil {
+		return twoFactorError(ctx, err)
+	}
+
+	// RULE: The challenge must have been issued to this User
+	passkeyService := factory.Passkey()
+	userID, challenge, err := passkeyService.ParseChallenge(service.PasskeyCeremonyTwoFactor, transaction.Token)
+
+	if (err != nil) || (userID != user.UserID) {
+		return twoFactorError(ctx, derp.Unauthorized(location, "Your signin has expired. Please sign in again."))
+	}
+
+	assertion, err := webauthn.ParseAssertion(transaction.Credential)
+
+	if err != nil {
+		return twoFactorError(ctx, derp.Wrap(err, location, "Invalid passkey response"))
+	}
+
+	// RULE: The passkey must belong to this User
+	passkey := model.NewPasskey()
+
+	if err := passkeyService.LoadByCredentialID(session, webauthn.EncodeID(assertion.CredentialID), &passkey); err != nil {
+
+		if !derp.IsNotFound(err) {
+			return derp.Wrap(err, location, "Loading passkey")
+		}
+	}
+
+	if passkey.UserID != user.UserID {
+		signinService.TwoFactorFailure(ctx.Request(), user.Username)
+		return twoFactorError(ctx, derp.Unauthorized(location, "This passkey is not registered to your account."))
+	}
+
+	// The password has already been verified, so the passkey only needs to prove possession
+	if err := passkeyService.Authenticate(session, &passkey, challenge, assertion, false); err != nil {
+		derp.Report(derp.Wrap(err, location, "Verifying passkey", user.Username))
+		signinService.TwoFactorFailure(ctx.Request(), user.Username)
+		return twoFactorError(ctx, derp.Unauthorized(location, "Your passkey could not be verified. Please try again."))
+	}
+
+	if err := signinService.RecordEvent(ctx.Request(), user.Username, model.SigninEventPasskeyUse); err != nil {
+		derp.Report(derp.Wrap(err, location, "Recording passkey use", user.Username))
+	}
+
+	if err := completeSignin(ctx, factory, session, &user); err != nil {
+		return derp.Wrap(err, location, "Completing signin")
+	}
+
+	// Skip the second factor on this device in the future
+	if transaction.Remember {
+		if err := rememberDevice(ctx, factory, &user); err != nil {
+			derp.Report(derp.Wrap(err, location, "Remembering device", user.Username))
+		}
+	}
+
+	return writeSigninRedirect(ctx, &user, calcNextURL(ctx.QueryParam("next")))
+}
+
+// readPasskeyRequest reads the form values of a passkey POST
+func readPasskeyRequest(ctx *steranko.Context) (passkeyRequest, error) {
+
+	const location = "handler.readPasskeyRequest"
+
+	request := ctx.Request()
+	request.Body = http.MaxBytesReader(ctx.Response(), request.Body, maxPasskeyRequestSize)
+
+	transaction, err := formdata.Parse(request)
+
+	if err != nil {
+		return passkeyRequest{}, derp.Wrap(err, location, "Parsing form data", derp.WithBadRequest())
+	}
+
+	result := passkeyRequest{
+		Token:      transaction.Get("token"),
+		Credential: json.RawMessage(transaction.Get("credential")),
+		Remember:   transaction.Get("remember") == "true",
+	}
+
+	return result, nil
+}
+
+// writePasskeyOptions writes the options for a passkey prompt, along with the token
+// that the browser must return with the User's response.
+func writePasskeyOptions(ctx *steranko.Context, token string, options any) error {
+
+	ctx.Response().Header().Set("Cache-Control", "no-store")
+
+	return ctx.JSON(http.StatusOK, map[string]any{
+		"token":     token,
+		"publicKey": options,
+	})
+}
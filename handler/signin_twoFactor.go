@@ -0,0 +1,384 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/service"
+	"github.com/EmissarySocial/emissary/tools/totp"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/steranko"
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// twoFactorChallengeCookie holds the (short-lived) proof that a User has entered a valid password
+const twoFactorChallengeCookie = "two-factor-challenge"
+
+// twoFactorDeviceCookie holds the (long-lived) proof that a User has chosen to "remember this device"
+const twoFactorDeviceCookie = "two-factor-device"
+
+// twoFactorChallengeAudience identifies JWTs that are issued for the two-factor challenge
+const twoFactorChallengeAudience = "two-factor-challenge"
+
+// twoFactorDeviceAudience identifies JWTs that are issued for remembered devices
+const twoFactorDeviceAudience = "two-factor-device"
+
+// needsTwoFactor returns TRUE if the User must enter a second factor before they are signed in.
+// Users who are required to use a second factor but have not enrolled yet must do so now.
+func needsTwoFactor(ctx *steranko.Context, factory *service.Factory, session data.Session, user *model.User) bool {
+
+	if user.TwoFactor.IsActive() || (countPasskeys(factory, session, user) > 0) {
+		return !isRememberedDevice(ctx, factory, user)
+	}
+
+	return factory.Domain().Get().UserRequiresTwoFactor(user)
+}
+
+// countPasskeys returns the number of passkeys that the User can use as a second factor.
+// RULE: fail closed. If the passkeys cannot be counted, then assume that there is one, so
+// that the User is asked for a second factor instead of being signed in without one.
+func countPasskeys(factory *service.Factory, session data.Session, user *model.User) int64 {
+
+	count, err := factory.Passkey().CountByUserID(session, user.UserID)
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, "handler.countPasskeys", "Counting passkeys", user.UserID))
+		return 1
+	}
+
+	return count
+}
+
+// beginTwoFactor sets a short-lived challenge cookie after a successful password check, and
+// sends the User to the second step of the signin.  The authentication cookie is not set
+// until the second factor is verified (see completeSignin).
+func beginTwoFactor(ctx *steranko.Context, factory *service.Factory, user *model.User, next string) error {
+
+	const location = "handler.beginTwoFactor"
+
+	expires := time.Now().Add(model.TwoFactorChallengeDuration)
+	token, err := factory.JWT().NewToken(jwt.MapClaims{
+		"sub": user.UserID.Hex(),
+		"aud": twoFactorChallengeAudience,
+		"exp": expires.Unix(),
+	})
+
+	if err != nil {
+		return derp.Wrap(err, location, "Creating challenge token")
+	}
+
+	setTwoFactorCookie(ctx, twoFactorChallengeCookie, token, expires)
+
+	ctx.Response().Header().Add("Hx-Redirect", "/signin/2fa?next="+url.QueryEscape(next))
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// GetSignInTwoFactor displays the second step of the signin, where Users enter a code
+// from their authenticator app.  Users who must enroll are shown a QR code instead.
+func GetSignInTwoFactor(ctx *steranko.Context, factory *service.Factory, session data.Session) error {
+
+	const location = "handler.GetSignInTwoFactor"
+
+	user := model.NewUser()
+
+	if err := loadTwoFactorChallenge(ctx, factory, session, &user); err != nil {
+		return ctx.Redirect(http.StatusSeeOther, "/signin")
+	}
+
+	templateName := "user-signin-2fa"
+	hasPasskeys := countPasskeys(factory, session, &user) > 0
+
+	// If the User has not enrolled yet, then start the enrollment now.
+	if !user.TwoFactor.IsActive() && !hasPasskeys {
+
+		if err := factory.User().BeginTwoFactor(session, &user); err != nil {
+			return derp.Wrap(err, location, "Beginning two-factor enrollment")
+		}
+
+		templateName = "user-signin-2fa-enroll"
+	}
+
+	data := twoFactorTemplateData(ctx, factory)
+	data["Secret"] = user.TwoFactor.PendingSecret
+	data["HasAuthenticatorApp"] = user.TwoFactor.IsActive()
+	data["HasPasskeys"] = hasPasskeys
+
+	return executeTwoFactorTemplate(ctx, factory, templateName, data)
+}
+
+// PostSignInTwoFactor verifies the User's second factor (or confirms a new enrollment)
+// and completes the signin.
+func PostSignInTwoFactor(ctx *steranko.Context, factory *service.Factory, session data.Session) error {
+
+	const location = "handler.PostSignInTwoFactor"
+
+	user := model.NewUser()
+
+	if err := loadTwoFactorChallenge(ctx, factory, session, &user); err != nil {
+		return twoFactorError(ctx, derp.Unauthorized(location, "Your signin has expired. Please sign in again."))
+	}
+
+	// RULE: Second factors share the same lockout as passwords
+	signinService := factory.SterankoSigninService(session)
+
+	if signinService.IsSigninLocked(ctx.Request(), user.Username) {
+		return twoFactorError(ctx, derp.Forbidden(location, "Too many signin attempts. Please try again later."))
+	}
+
+	userService := factory.User()
+	code := ctx.FormValue("code")
+
+	// Users who are enrolling confirm their new secret, then see their recovery codes
+	if !user.TwoFactor.IsActive() {
+
+		recoveryCodes, err := userService.EnrollTwoFactor(session, &user, code)
+
+		if err != nil {
+			signinService.TwoFactorFailure(ctx.Request(), user.Username)
+			return twoFactorError(ctx, err)
+		}
+
+		if err := signinService.RecordEvent(ctx.Request(), user.Username, model.SigninEventTwoFactorEnroll); err != nil {
+			derp.Report(derp.Wrap(err, location, "Recording two-factor enrollment", user.Username))
+		}
+
+		if err := completeSignin(ctx, factory, session, &user); err != nil {
+			return derp.Wrap(err, location, "Completing signin")
+		}
+
+		data := twoFactorTemplateData(ctx, factory)
+		data["RecoveryCodes"] = recoveryCodes
+		data["NextURL"] = calcNextURL(ctx.QueryParam("next"))
+
+		return executeTwoFactorTemplate(ctx, factory, "user-signin-2fa-recovery", data)
+	}
+
+	// Otherwise, verify the code from the User's authenticator app (or a recovery code)
+	event, isValid, err := userService.VerifyTwoFactor(session, &user, code)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Verifying two-factor code")
+	}
+
+	if !isValid {
+		signinService.TwoFactorFailure(ctx.Request(), user.Username)
+		return twoFactorError(ctx, derp.Validation("Code is incorrect. Please try again."))
+	}
+
+	if err := signinService.RecordEvent(ctx.Request(), user.Username, event); err != nil {
+		derp.Report(derp.Wrap(err, location, "Recording two-factor use", user.Username))
+	}
+
+	if err := completeSignin(ctx, factory, session, &user); err != nil {
+		return derp.Wrap(err, location, "Completing signin")
+	}
+
+	// Skip the second factor on this device in the future
+	if ctx.FormValue("remember") == "true" {
+		if err := rememberDevice(ctx, factory, &user); err != nil {
+			derp.Report(derp.Wrap(err, location, "Remembering device", user.Username))
+		}
+	}
+
+	// Forward to the "next" URL, with the same Activity Intent data as a password-only signin
+	return writeSigninRedirect(ctx, &user, calcNextURL(ctx.QueryParam("next")))
+}
+
+// GetSignInTwoFactorQRCode displays the QR code for a User who is enrolling during signin
+func GetSignInTwoFactorQRCode(ctx *steranko.Context, factory *service.Factory, session data.Session) error {
+
+	const location = "handler.GetSignInTwoFactorQRCode"
+
+	user := model.NewUser()
+
+	if err := loadTwoFactorChallenge(ctx, factory, session, &user); err != nil {
+		return derp.Wrap(err, location, "Loading signin challenge")
+	}
+
+	return getTwoFactorQRCode(ctx, factory, &user)
+}
+
+// completeSignin signs in the User after their second factor (or passkey) has been verified
+func completeSignin(ctx *steranko.Context, factory *service.Factory, session data.Session, user *model.User) error {
+
+	const location = "handler.completeSignin"
+
+	// A verified second factor (or passkey) clears all of the failed attempts for this User
+	if err := factory.SterankoSigninService(session).ClearSigninAttempts(user.Username); err != nil {
+		derp.Report(derp.Wrap(err, location, "Clearing signin attempts", user.Username))
+	}
+
+	if err := factory.Steranko(session).SigninUser(ctx, user); err != nil {
+		return derp.Wrap(err, location, "Signing in user", user.Username)
+	}
+
+	setTwoFactorCookie(ctx, twoFactorChallengeCookie, "", time.Unix(0, 0))
+	return nil
+}
+
+// loadTwoFactorChallenge loads the User named in a valid challenge cookie
+func loadTwoFactorChallenge(ctx *steranko.Context, factory *service.Factory, session data.Session, user *model.User) error {
+
+	const location = "handler.loadTwoFactorChallenge"
+
+	claims, err := parseTwoFactorCookie(ctx, factory, twoFactorChallengeCookie, twoFactorChallengeAudience)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Parsing challenge cookie")
+	}
+
+	userID, err := primitive.ObjectIDFromHex(claims.GetString("sub"))
+
+	if err != nil {
+		return derp.Wrap(err, location, "Invalid User ID")
+	}
+
+	if err := factory.User().LoadByID(session, userID, user); err != nil {
+		return derp.Wrap(err, location, "Loading user", userID)
+	}
+
+	return nil
+}
+
+// rememberDevice sets a cookie that skips the second factor on this device
+func rememberDevice(ctx *steranko.Context, factory *service.Factory, user *model.User) error {
+
+	const location = "handler.rememberDevice"
+
+	expires := time.Now().Add(model.TwoFactorRememberDuration)
+	token, err := factory.JWT().NewToken(jwt.MapClaims{
+		"sub": user.UserID.Hex(),
+		"aud": twoFactorDeviceAudience,
+		"key": user.TwoFactor.DeviceKey,
+		"exp": expires.Unix(),
+	})
+
+	if err != nil {
+		return derp.Wrap(err, location, "Creating device token")
+	}
+
+	setTwoFactorCookie(ctx, twoFactorDeviceCookie, token, expires)
+	return nil
+}
+
+// isRememberedDevice returns TRUE if the request includes a valid "remember this device"
+// cookie for the User.  Cookies are invalidated whenever the User re-enrolls or removes
+// their second factor, because that changes the User's DeviceKey.
+func isRememberedDevice(ctx *steranko.Context, factory *service.Factory, user *model.User) bool {
+
+	claims, err := parseTwoFactorCookie(ctx, factory, twoFactorDeviceCookie, twoFactorDeviceAudience)
+
+	if err != nil {
+		return false
+	}
+
+	if claims.GetString("sub") != user.UserID.Hex() {
+		return false
+	}
+
+	return user.TwoFactor.DeviceKey != "" && claims.GetString("key") == user.TwoFactor.DeviceKey
+}
+
+// parseTwoFactorCookie parses and validates a JWT from the named cookie
+func parseTwoFactorCookie(ctx *steranko.Context, factory *service.Factory, name string, audience string) (mapof.Any, error) {
+
+	const location = "handler.parseTwoFactorCookie"
+
+	cookie, err := ctx.Cookie(name)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Reading cookie", name)
+	}
+
+	claims := jwt.MapClaims{}
+
+	if err := factory.JWT().ParseToken(cookie.Value, &claims); err != nil {
+		return nil, derp.Wrap(err, location, "Parsing token", name)
+	}
+
+	result := mapof.Any(claims)
+
+	if result.GetString("aud") != audience {
+		return nil, derp.Unauthorized(location, "Invalid token audience", name)
+	}
+
+	return result, nil
+}
+
+// setTwoFactorCookie writes a two-factor cookie to the response.  An empty value removes the cookie.
+func setTwoFactorCookie(ctx *steranko.Context, name string, value string, expires time.Time) {
+
+	cookie := http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   ctx.Scheme() == "https",
+		SameSite: http.SameSiteLaxMode,
+	}
+
+	if value == "" {
+		cookie.MaxAge = -1
+	}
+
+	ctx.SetCookie(&cookie)
+}
+
+// getTwoFactorQRCode writes a QR code for the User's pending secret
+func getTwoFactorQRCode(ctx *steranko.Context, factory *service.Factory, user *model.User) error {
+
+	const location = "handler.getTwoFactorQRCode"
+
+	if !user.TwoFactor.IsPending() {
+		return derp.NotFound(location, "Two-factor enrollment has not been started")
+	}
+
+	domain := factory.Domain().Get()
+	issuer := firstOf(domain.Label, factory.Hostname())
+
+	ctx.Response().Header().Set("Cache-Control", "no-store")
+	return writeQRCode(ctx, totp.URL(issuer, user.Username, user.TwoFactor.PendingSecret))
+}
+
+// twoFactorTemplateData returns the values shared by all of the two-factor signin pages
+func twoFactorTemplateData(ctx *steranko.Context, factory *service.Factory) mapof.Any {
+
+	domain := factory.Domain().Get()
+
+	return mapof.Any{
+		"DomainName":  domain.Label,
+		"DomainIcon":  domain.IconURL(),
+		"DomainImage": domain.ImageURL(),
+		"Next":        url.QueryEscape(ctx.QueryParam("next")),
+	}
+}
+
+// executeTwoFactorTemplate renders one of the two-factor signin pages from the domain's theme
+func executeTwoFactorTemplate(ctx *steranko.Context, factory *service.Factory, templateName string, data mapof.Any) error {
+
+	var buffer bytes.Buffer
+
+	template := factory.Domain().Theme().HTMLTemplate
+
+	if err := template.ExecuteTemplate(&buffer, templateName, data); err != nil {
+		return derp.Wrap(err, "handler.executeTwoFactorTemplate", "Executing template", templateName)
+	}
+
+	ctx.Response().Header().Set("Cache-Control", "no-store")
+	return ctx.HTML(http.StatusOK, buffer.String())
+}
+
+// twoFactorError reports an error to the two-factor signin form
+func twoFactorError(ctx *steranko.Context, err error) error {
+	messageJSON, _ := json.Marshal(map[string]string{"SigninError": derp.Message(err)})
+	ctx.Response().Header().Add("HX-Trigger", string(messageJSON))
+	return ctx.HTML(derp.ErrorCode(err), derp.Message(err))
+}
@@ -0,0 +1,16 @@
+package model
+
+// AccountNote is a private comment that a User has written about another account.
+// It is only ever visible to the User who wrote it (Mastodon "account notes").
+type AccountNote struct {
+	URL  string `bson:"url"`  // ActivityPub URL of the account that this note describes
+	Note string `bson:"note"` // Text of the note
+}
+
+// NewAccountNote returns a fully initialized AccountNote object
+func NewAccountNote(url string, note string) AccountNote {
+	return AccountNote{
+		URL:  url,
+		Note: note,
+	}
+}
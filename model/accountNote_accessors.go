@@ -0,0 +1,34 @@
+package model
+
+import (
+	"github.com/benpate/rosetta/schema"
+)
+
+// AccountNoteSchema returns a validating schema for AccountNote objects
+func AccountNoteSchema() schema.Element {
+
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"url":  schema.String{Format: "url", Required: true},
+			"note": schema.String{Format: "text", MaxLength: 2000},
+		},
+	}
+}
+
+/********************************
+ * Getter/Setter Interfaces
+ ********************************/
+
+func (note *AccountNote) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "url":
+		return &note.URL, true
+
+	case "note":
+		return &note.Note, true
+	}
+
+	return nil, false
+}
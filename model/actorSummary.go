@@ -1,6 +1,9 @@
 package model
 
 import (
+	"strings"
+
+	"github.com/benpate/toot/object"
 	"github.com/benpate/uri"
 )
 
@@ -27,3 +30,15 @@ func (actor ActorSummary) UsernameOrID() string {
 func (actor ActorSummary) Username() string {
 	return actor.PreferredUsername
 }
+
+// Toot returns this ActorSummary as a Mastodon-API Account object
+func (actor ActorSummary) Toot() object.Account {
+	return object.Account{
+		ID:          actor.ID,
+		Username:    actor.PreferredUsername,
+		Acct:        strings.TrimPrefix(actor.UsernameOrID(), "@"),
+		DisplayName: actor.Name,
+		URL:         actor.ID,
+		Avatar:      actor.Icon,
+	}
+}
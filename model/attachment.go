@@ -16,21 +16,24 @@ import (
 
 // Attachment represents a file that has been uploaded to the software
 type Attachment struct {
-	AttachmentID primitive.ObjectID `bson:"_id"`               // ID of this Attachment
-	ObjectID     primitive.ObjectID `bson:"objectId"`          // ID of the object that owns this Attachment
-	ObjectType   string             `bson:"objectType"`        // Type of object that owns this Attachment
-	Original     string             `bson:"original"`          // Original filename uploaded by user
-	ContentType  string             `bson:"contentType"`       // Media type sniffed from the file's own bytes.  Empty on records that predate content sniffing.
-	Category     string             `bson:"category"`          // Category of the file (defined by the Template)
-	Label        string             `bson:"label"`             // User-defined label for the attachment
-	Description  string             `bson:"description"`       // User-defined description for the attachment
-	URL          string             `bson:"url"`               // URL where the file is stored
-	Status       string             `bson:"status"`            // Status of the attachment (READY, WORKING)
-	Rules        AttachmentRules    `bson:"rules"`             // Rules for downloading this attachment
-	Height       int                `bson:"height,omitzero"`   // Height of the media file (if applicable)
-	Width        int                `bson:"width,omitzero"`    // Width of the media file (if applicable)
-	Duration     int                `bson:"duration,omitzero"` // Duration of the media file (if applicable)
-	Rank         int                `bson:"rank,omitzero"`     // The sort order to display the attachments in.
+	AttachmentID primitive.ObjectID `bson:"_id"`                // ID of this Attachment
+	ObjectID     primitive.ObjectID `bson:"objectId"`           // ID of the object that owns this Attachment
+	ObjectType   string             `bson:"objectType"`         // Type of object that owns this Attachment
+	Original     string             `bson:"original"`           // Original filename uploaded by user
+	ContentType  string             `bson:"contentType"`        // Media type sniffed from the file's own bytes.  Empty on records that predate content sniffing.
+	Category     string             `bson:"category"`           // Category of the file (defined by the Template)
+	Label        string             `bson:"label"`              // User-defined label for the attachment
+	Description  string             `bson:"description"`        // User-defined description for the attachment
+	URL          string             `bson:"url"`                // URL where the file is stored
+	Status       string             `bson:"status"`             // Status of the attachment (READY, WORKING)
+	Rules        AttachmentRules    `bson:"rules"`              // Rules for downloading this attachment
+	Height       int                `bson:"height,omitzero"`    // Height of the media file (if applicable)
+	Width        int                `bson:"width,omitzero"`     // Width of the media file (if applicable)
+	Duration     int                `bson:"duration,omitzero"`  // Duration of the media file (if applicable)
+	Rank         int                `bson:"rank,omitzero"`      // The sort order to display the attachments in.
+	BlurHash     string             `bson:"blurhash,omitempty"` // BlurHash placeholder for images (if applicable)
+	FocusX       float64            `bson:"focusX,omitzero"`    // Horizontal focal point, from -1.0 (left) to 1.0 (right)
+	FocusY       float64            `bson:"focusY,omitzero"`    // Vertical focal point, from -1.0 (bottom) to 1.0 (top)
 
 	journal.Journal `json:"-" bson:",inline"` // Journal entry for fetch compatability
 }
@@ -212,6 +215,16 @@ func (attachment Attachment) AspectRatio() string {
 	return strconv.Itoa(attachment.Width / attachment.Height)
 }
 
+// HasFocus returns TRUE if a focal point has been set for this Attachment
+func (attachment Attachment) HasFocus() bool {
+	return (attachment.FocusX != 0) || (attachment.FocusY != 0)
+}
+
+// IsReady returns TRUE if this Attachment has finished processing
+func (attachment Attachment) IsReady() bool {
+	return attachment.Status != AttachmentStatusWorking
+}
+
 func (attachment Attachment) HasDimensions() bool {
 	if attachment.Width == 0 {
 		return false
@@ -249,8 +262,16 @@ func (attachment Attachment) JSONLD() map[string]any {
 		result["height"] = attachment.Height
 	}
 
-	// TODO: Blurhash
-	// TODO: FocalPoint?? -> toot:focalPoint (http://joinmastodon.org/ns#focalPoint) https://docs.joinmastodon.org/spec/activitypub/
+	// https://docs.joinmastodon.org/spec/activitypub/#blurhash
+	if attachment.BlurHash != "" {
+		result[vocab.PropertyBlurHash] = attachment.BlurHash
+	}
+
+	// https://docs.joinmastodon.org/spec/activitypub/#focalPoint
+	if attachment.HasFocus() {
+		result["focalPoint"] = []float64{attachment.FocusX, attachment.FocusY}
+	}
+
 	// TODO: Icon (if available) -> icon: {type:"", mediaType:"", url:""}
 
 	return result
@@ -265,3 +286,30 @@ func (attachment *Attachment) SetRules(width int, height int, extensions []strin
 	attachment.Rules.Width = width
 	attachment.Rules.Height = height
 }
+
+// SetFocus parses a focal point in the Mastodon format ("x,y", each from -1.0 to 1.0).
+// It returns FALSE if the value cannot be parsed.
+func (attachment *Attachment) SetFocus(value string) bool {
+
+	xString, yString, found := strings.Cut(value, ",")
+
+	if !found {
+		return false
+	}
+
+	x, err := strconv.ParseFloat(strings.TrimSpace(xString), 64)
+
+	if (err != nil) || (x < -1) || (x > 1) {
+		return false
+	}
+
+	y, err := strconv.ParseFloat(strings.TrimSpace(yString), 64)
+
+	if (err != nil) || (y < -1) || (y > 1) {
+		return false
+	}
+
+	attachment.FocusX = x
+	attachment.FocusY = y
+	return true
+}
@@ -14,7 +14,7 @@ func AttachmentSchema() schema.Element {
 			"objectType":   schema.String{Enum: []string{AttachmentObjectTypeDomain, AttachmentObjectTypeSearchTag, AttachmentObjectTypeStream, AttachmentObjectTypeUser}},
 			"category":     schema.String{MaxLength: 64},
 			"label":        schema.String{MaxLength: 64},
-			"description":  schema.String{MaxLength: 1024},
+			"description":  schema.String{MaxLength: 1500},
 			"url":          schema.String{Format: "url"},
 			"original":     schema.String{MaxLength: 1024},
 			"contentType":  schema.String{MaxLength: 255},
@@ -23,6 +23,7 @@ func AttachmentSchema() schema.Element {
 			"width":        schema.Integer{},
 			"duration":     schema.Integer{},
 			"rank":         schema.Integer{},
+			"blurhash":     schema.String{MaxLength: 128},
 
 			"rules": AttachmentRulesSchema(),
 		},
@@ -75,6 +76,9 @@ func (attachment *Attachment) GetPointer(name string) (any, bool) {
 	case "duration":
 		return &attachment.Duration, true
 
+	case "blurhash":
+		return &attachment.BlurHash, true
+
 	case "rules":
 		return &attachment.Rules, true
 	}
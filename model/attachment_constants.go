@@ -12,6 +12,17 @@ const AttachmentObjectTypeStream = "Stream"
 // AttachmentObjectTypeUser represents an attachment that is owned by a User
 const AttachmentObjectTypeUser = "User"
 
+// AttachmentCategoryMedia represents media uploaded through the Mastodon API
+// that has not been attached to a status yet
+const AttachmentCategoryMedia = "mastodon-media"
+
+// AttachmentMaxMedia is the maximum number of media files that can be attached to a single status
+const AttachmentMaxMedia = 4
+
+// AttachmentMaxMediaPixels is the largest image (in pixels) that is decoded to calculate
+// its BlurHash.  This is the size of an 8K display.
+const AttachmentMaxMediaPixels = 7680 * 4320
+
 // AttachmentMediaTypeAudio represents an attachment that is audio
 const AttachmentMediaTypeAudio = "audio"
 
@@ -28,6 +28,7 @@ func TestAttachmentSchema(t *testing.T) {
 		{"width", "200", 200},
 		{"duration", "100", 100},
 		{"rank", "1", 1},
+		{"blurhash", "LEHV6nWB2yk8pyo0adR*.7kCMdnj", nil},
 	}
 
 	tableTest_Schema(t, &s, &attachment, table)
@@ -99,3 +100,43 @@ func TestAttachment_MimeType(t *testing.T) {
 	// ...but the claim the filename makes is still available to whoever needs it.
 	require.Equal(t, "text/html; charset=utf-8", attachment.OriginalMimeType())
 }
+
+func TestAttachment_SetFocus(t *testing.T) {
+
+	attachment := NewAttachment(AttachmentObjectTypeStream, primitive.NewObjectID())
+	require.False(t, attachment.HasFocus())
+
+	require.True(t, attachment.SetFocus("-0.5, 0.25"))
+	require.Equal(t, -0.5, attachment.FocusX)
+	require.Equal(t, 0.25, attachment.FocusY)
+	require.True(t, attachment.HasFocus())
+
+	// Invalid values leave the focal point unchanged
+	require.False(t, attachment.SetFocus("0.5"))
+	require.False(t, attachment.SetFocus("1.5,0"))
+	require.False(t, attachment.SetFocus("left,top"))
+	require.Equal(t, -0.5, attachment.FocusX)
+	require.Equal(t, 0.25, attachment.FocusY)
+}
+
+// TestAttachment_JSONLD confirms the Mastodon extensions that are federated with each attachment.
+func TestAttachment_JSONLD(t *testing.T) {
+
+	attachment := NewAttachment(AttachmentObjectTypeStream, primitive.NewObjectID())
+	attachment.Original = "photo.png"
+	attachment.Description = "A cat asleep on a keyboard"
+	attachment.URL = "https://example.com/123/attachments/456"
+
+	// Optional values are omitted until they are set
+	result := attachment.JSONLD()
+	require.Equal(t, "A cat asleep on a keyboard", result["name"])
+	require.NotContains(t, result, "blurhash")
+	require.NotContains(t, result, "focalPoint")
+
+	attachment.BlurHash = "LEHV6nWB2yk8pyo0adR*.7kCMdnj"
+	attachment.SetFocus("0.1,-0.2")
+
+	result = attachment.JSONLD()
+	require.Equal(t, "LEHV6nWB2yk8pyo0adR*.7kCMdnj", result["blurhash"])
+	require.Equal(t, []float64{0.1, -0.2}, result["focalPoint"])
+}
@@ -99,6 +99,21 @@ func (authorization Authorization) Scopes() []string {
 	return strings.Split(authorization.Scope, " ")
 }
 
+// HasScope returns TRUE if this Authorization grants the requested OAuth scope.  Like
+// Mastodon, a top-level scope (e.g. "read") also grants each of its children (e.g. "read:statuses").
+func (authorization Authorization) HasScope(scope string) bool {
+
+	parent, _, _ := strings.Cut(scope, ":")
+
+	for _, granted := range authorization.Scopes() {
+		if (granted == scope) || (granted == parent) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (authorization Authorization) Debug() mapof.Any {
 
 	return mapof.Any{
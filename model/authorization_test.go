@@ -69,3 +69,22 @@ func TestAuthorization_RevalidateRoundTrip(t *testing.T) {
 	require.True(t, ok, "Revalidate must survive the JSON round-trip")
 	require.Greater(t, time.Since(at), 10*time.Minute, "the round-tripped session must read as stale")
 }
+
+func TestAuthorization_HasScope(t *testing.T) {
+
+	auth := NewAuthorization()
+	auth.Scope = "read:notifications write"
+
+	require.True(t, auth.HasScope("read:notifications"))
+	require.True(t, auth.HasScope("write"))
+	require.True(t, auth.HasScope("write:statuses"))
+	require.False(t, auth.HasScope("read"))
+	require.False(t, auth.HasScope("read:statuses"))
+
+	auth.Scope = "read"
+	require.True(t, auth.HasScope("read:statuses"))
+	require.False(t, auth.HasScope("write"))
+
+	auth.Scope = ""
+	require.False(t, auth.HasScope("read"))
+}
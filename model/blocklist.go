@@ -0,0 +1,235 @@
+package model
+
+import (
+	"strconv"
+
+	"github.com/benpate/data/journal"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Blocklist is a CSV list of domains and actors (like Mastodon's domain_blocks.csv) that is
+// imported as server-wide Rules.  A Blocklist with a URL is a subscription that is refreshed
+// daily.  A Blocklist without a URL is a one-time upload that is removed once it is applied.
+type Blocklist struct {
+	BlocklistID     primitive.ObjectID `bson:"_id"`                 // Unique identifier of this Blocklist
+	Label           string             `bson:"label"`               // Human-friendly name, which is also used to attribute the Rules it creates
+	URL             string             `bson:"url"`                 // Location of a remote CSV file to subscribe to.  Empty for one-time uploads.
+	Content         string             `bson:"content,omitempty"`   // Uploaded CSV content, which is kept only until it is applied
+	AutoApply       bool               `bson:"autoApply"`           // If TRUE, daily refreshes are applied without waiting for review
+	Pending         []BlocklistChange  `bson:"pending"`             // Changes that have been previewed, but not yet applied
+	EntryCount      int                `bson:"entryCount"`          // Number of usable entries in the most recent copy of the list
+	FetchDate       int64              `bson:"fetchDate"`           // Unix epoch seconds when this list was last downloaded/parsed
+	ApplyDate       int64              `bson:"applyDate"`           // Unix epoch seconds when changes were last applied
+	LastError       string             `bson:"lastError,omitempty"` // Human-friendly description of the last problem refreshing or applying this list
+	journal.Journal `json:"-" bson:",inline"`
+}
+
+// NewBlocklist returns a fully initialized Blocklist object
+func NewBlocklist() Blocklist {
+	return Blocklist{
+		BlocklistID: primitive.NewObjectID(),
+		Pending:     make([]BlocklistChange, 0),
+	}
+}
+
+func BlocklistFields() []string {
+	return []string{"_id", "label", "url", "autoApply", "pending", "entryCount", "fetchDate", "applyDate", "lastError"}
+}
+
+func (blocklist Blocklist) Fields() []string {
+	return BlocklistFields()
+}
+
+// ID returns the unique identifier for this Blocklist, and is required to implement the data.Object interface
+func (blocklist Blocklist) ID() string {
+	return blocklist.BlocklistID.Hex()
+}
+
+// IsSubscription returns TRUE if this Blocklist is downloaded from a remote URL
+func (blocklist Blocklist) IsSubscription() bool {
+	return blocklist.URL != ""
+}
+
+// HasPending returns TRUE if this Blocklist has changes waiting to be applied
+func (blocklist Blocklist) HasPending() bool {
+	return len(blocklist.Pending) > 0
+}
+
+// PendingCount returns the number of pending changes with the provided operation (ADD, UPDATE, or REMOVE)
+func (blocklist Blocklist) PendingCount(operation string) int {
+
+	result := 0
+
+	for _, change := range blocklist.Pending {
+		if change.Operation == operation {
+			result++
+		}
+	}
+
+	return result
+}
+
+// RemoteID returns the identifier that tracks a subscribed entry (identified by its MatchKey)
+// through every refresh, so entries removed from the list can be retracted locally.
+// One-time uploads are not tracked, so they return an empty string.
+func (blocklist Blocklist) RemoteID(matchKey string) string {
+
+	if !blocklist.IsSubscription() || (matchKey == "") {
+		return ""
+	}
+
+	return blocklist.URL + "#" + matchKey
+}
+
+// SyncError returns a human-friendly reason why a subscription's pending changes look like a
+// broken copy of the list (one with no entries, or one that removes too many of the Rules that
+// it already manages), or an empty string if they are safe to apply.  ownedCount is the number
+// of Rules that this Blocklist currently manages.
+func (blocklist Blocklist) SyncError(ownedCount int) string {
+
+	if !blocklist.IsSubscription() {
+		return ""
+	}
+
+	if blocklist.EntryCount == 0 {
+		return "The latest copy of this list has no entries, so it was not applied."
+	}
+
+	removals := blocklist.PendingCount(BlocklistChangeRemove)
+
+	if (removals > BlocklistMinRemovals) && (removals*100 > ownedCount*BlocklistMaxRemovalPercent) {
+		return "The latest copy of this list removes " + strconv.Itoa(removals) + " of " + strconv.Itoa(ownedCount) + " entries, so it was not applied."
+	}
+
+	return ""
+}
+
+/******************************************
+ * AccessLister Interface
+ ******************************************/
+
+// State returns the current state of this Blocklist.
+// It is part of the AccessLister interface
+func (blocklist *Blocklist) State() string {
+	return "default"
+}
+
+// IsAuthor returns TRUE if the provided UserID the author of this Blocklist
+// It is part of the AccessLister interface
+func (blocklist *Blocklist) IsAuthor(authorID primitive.ObjectID) bool {
+	return false
+}
+
+// IsMyself returns TRUE if this object directly represents the provided UserID
+// It is part of the AccessLister interface
+func (blocklist *Blocklist) IsMyself(userID primitive.ObjectID) bool {
+	return false
+}
+
+// RolesToGroupIDs returns a slice of Group IDs that grant access to any of the requested roles.
+// It is part of the AccessLister interface
+func (blocklist *Blocklist) RolesToGroupIDs(roleIDs ...string) Permissions {
+	return defaultRolesToGroupIDs(primitive.NilObjectID, roleIDs...)
+}
+
+// RolesToPrivilegeIDs returns a slice of Privileges that grant access to any of the requested roles.
+// It is part of the AccessLister interface
+func (blocklist *Blocklist) RolesToPrivilegeIDs(roleIDs ...string) Permissions {
+	return NewPermissions()
+}
+
+/******************************************
+ * Diff Methods
+ ******************************************/
+
+// Diff compares the entries in the latest copy of this Blocklist against the existing
+// server-wide Rules, and returns the changes needed to bring them into agreement.
+//
+// Rules that this Blocklist created (identified by their RemoteID) are updated when their
+// entry changes, and (for subscriptions) removed when their entry disappears.  Entries that
+// are already covered by any other Rule are left alone, so a blocklist never overwrites an
+// administrator's own decisions, or those of another blocklist.
+func (blocklist Blocklist) Diff(entries []BlocklistEntry, rules []Rule) []BlocklistChange {
+
+	owned := make(map[string]Rule)
+	existing := make(map[string]bool)
+
+	for _, rule := range rules {
+
+		if blocklist.IsSubscription() && (rule.BlocklistID == blocklist.BlocklistID) && (rule.RemoteID != "") {
+			owned[rule.RemoteID] = rule
+			continue
+		}
+
+		existing[rule.MatchKey] = true
+	}
+
+	result := make([]BlocklistChange, 0)
+	seen := make(map[string]bool, len(entries))
+
+	for _, entry := range entries {
+
+		matchKey := entry.MatchKey()
+
+		// Only the first copy of a repeated entry counts
+		if seen[matchKey] {
+			continue
+		}
+
+		seen[matchKey] = true
+		remoteID := blocklist.RemoteID(matchKey)
+
+		change := BlocklistChange{
+			RemoteID:   remoteID,
+			Type:       entry.Type,
+			Trigger:    entry.Trigger,
+			Action:     entry.Action,
+			ReasonCode: entry.ReasonCode,
+			Summary:    entry.Summary,
+		}
+
+		// Update Rules that this Blocklist already manages
+		if rule, isOwned := owned[remoteID]; isOwned {
+
+			delete(owned, remoteID)
+
+			if (rule.Action != entry.Action) || (rule.ReasonCode != entry.ReasonCode) || (rule.Summary != entry.Summary) {
+				change.Operation = BlocklistChangeUpdate
+				change.RuleID = rule.RuleID
+				result = append(result, change)
+			}
+
+			continue
+		}
+
+		// Never override a Rule from somewhere else
+		if existing[matchKey] {
+			continue
+		}
+
+		change.Operation = BlocklistChangeAdd
+		result = append(result, change)
+	}
+
+	// Anything still "owned" has been removed from the list (one-time uploads never own Rules).
+	// Walk the original slice so that the preview lists removals in a stable order.
+	for _, rule := range rules {
+
+		if _, isOwned := owned[rule.RemoteID]; !isOwned || (rule.BlocklistID != blocklist.BlocklistID) {
+			continue
+		}
+
+		result = append(result, BlocklistChange{
+			Operation:  BlocklistChangeRemove,
+			RuleID:     rule.RuleID,
+			RemoteID:   rule.RemoteID,
+			Type:       rule.Type,
+			Trigger:    rule.Trigger,
+			Action:     rule.Action,
+			ReasonCode: rule.ReasonCode,
+			Summary:    rule.Summary,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,31 @@
+package model
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// BlocklistChange is a single difference between a Blocklist and the server-wide Rules that
+// it manages.  Changes are previewed by an administrator before they are applied.
+type BlocklistChange struct {
+	Operation  string             `bson:"operation"`            // ADD, UPDATE, or REMOVE
+	RuleID     primitive.ObjectID `bson:"ruleId,omitempty"`     // Existing Rule to UPDATE or REMOVE
+	RemoteID   string             `bson:"remoteId,omitempty"`   // Tracking ID for subscribed entries (see Blocklist.RemoteID)
+	Type       string             `bson:"type"`                 // DOMAIN or ACTOR
+	Trigger    string             `bson:"trigger"`              // Domain name or actor address
+	Action     string             `bson:"action"`               // BLOCK or MUTE
+	ReasonCode string             `bson:"reasonCode,omitempty"` // Optional reason code from the list
+	Summary    string             `bson:"summary,omitempty"`    // Public comment from the list
+}
+
+// IsAdd returns TRUE if this change creates a new Rule
+func (change BlocklistChange) IsAdd() bool {
+	return change.Operation == BlocklistChangeAdd
+}
+
+// IsUpdate returns TRUE if this change modifies an existing Rule
+func (change BlocklistChange) IsUpdate() bool {
+	return change.Operation == BlocklistChangeUpdate
+}
+
+// IsRemove returns TRUE if this change retracts an existing Rule
+func (change BlocklistChange) IsRemove() bool {
+	return change.Operation == BlocklistChangeRemove
+}
@@ -0,0 +1,235 @@
+package model
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/benpate/derp"
+)
+
+// BlocklistEntry is a single, usable row of a blocklist CSV file
+type BlocklistEntry struct {
+	Type       string // DOMAIN or ACTOR
+	Trigger    string // Domain name or actor address
+	Action     string // BLOCK or MUTE
+	ReasonCode string // Optional reason code
+	Summary    string // Public comment
+}
+
+// MatchKey returns the Rule MatchKey that this entry produces
+func (entry BlocklistEntry) MatchKey() string {
+	return RuleMatchKey(entry.Type, entry.Trigger)
+}
+
+// ParseBlocklistCSV reads a blocklist in the Mastodon `domain_blocks.csv` format, which is also
+// used by most community lists.  Columns are located by their header (`#domain`, `#severity`,
+// `#public_comment`, plus the `#reason` and `#type` columns that Emissary exports).  A file with
+// no header is read as one domain per line.  Obfuscated domains (containing "*") and severities
+// with no Emissary equivalent (noop) are skipped.
+func ParseBlocklistCSV(reader io.Reader) ([]BlocklistEntry, error) {
+
+	const location = "model.ParseBlocklistCSV"
+
+	csvReader := csv.NewReader(io.LimitReader(reader, BlocklistContentMaxLength))
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+	csvReader.LazyQuotes = true
+
+	// Without a header, the first column is the domain
+	columns := map[string]int{"domain": 0}
+	result := make([]BlocklistEntry, 0)
+	first := true
+
+	for {
+
+		record, err := csvReader.Read()
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Reading CSV file")
+		}
+
+		// Look for a header in the first row
+		if first {
+			first = false
+
+			if header := parseBlocklistHeader(record); header != nil {
+				columns = header
+				continue
+			}
+		}
+
+		entry, ok := parseBlocklistRecord(record, columns)
+
+		if !ok {
+			continue
+		}
+
+		if len(result) >= BlocklistMaxEntries {
+			return nil, derp.Validation("Blocklist has too many entries", BlocklistMaxEntries)
+		}
+
+		result = append(result, entry)
+	}
+
+	return result, nil
+}
+
+// parseBlocklistHeader returns the column positions in a header row,
+// or nil if the row is not a header
+func parseBlocklistHeader(record []string) map[string]int {
+
+	result := make(map[string]int, len(record))
+
+	for index, value := range record {
+		name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(value), "#")))
+		result[name] = index
+	}
+
+	if _, ok := result["domain"]; !ok {
+		return nil
+	}
+
+	return result
+}
+
+// parseBlocklistRecord converts a single CSV row into a BlocklistEntry.
+// It returns FALSE if the row should be skipped.
+func parseBlocklistRecord(record []string, columns map[string]int) (BlocklistEntry, bool) {
+
+	column := func(name string) string {
+		if index, ok := columns[name]; ok && (index < len(record)) {
+			return strings.TrimSpace(record[index])
+		}
+		return ""
+	}
+
+	trigger := column("domain")
+
+	// Skip blank lines, comments, and obfuscated domains
+	if (trigger == "") || strings.HasPrefix(trigger, "#") || strings.Contains(trigger, "*") {
+		return BlocklistEntry{}, false
+	}
+
+	entry := BlocklistEntry{
+		Type:       blocklistEntryType(trigger, column("type")),
+		Trigger:    trigger,
+		ReasonCode: truncateString(column("reason"), 64),
+		Summary:    truncateString(column("public_comment"), 256),
+	}
+
+	switch strings.ToLower(column("severity")) {
+
+	case "", BlocklistSeveritySuspend:
+		entry.Action = RuleActionBlock
+
+	case BlocklistSeveritySilence, "limit":
+		entry.Action = RuleActionMute
+
+	default:
+		return BlocklistEntry{}, false
+	}
+
+	// Skip anything that does not produce a usable Rule
+	if entry.MatchKey() == "" {
+		return BlocklistEntry{}, false
+	}
+
+	return entry, true
+}
+
+// blocklistEntryType returns the Rule Type for a blocklist entry.  An explicit `#type` column
+// wins; otherwise webfinger handles and profile URLs are actors, and everything else is a domain.
+func blocklistEntryType(trigger string, value string) string {
+
+	switch strings.ToUpper(value) {
+
+	case RuleTypeActor:
+		return RuleTypeActor
+
+	case RuleTypeDomain:
+		return RuleTypeDomain
+	}
+
+	if strings.HasPrefix(trigger, "@") {
+		return RuleTypeActor
+	}
+
+	if _, path, ok := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(trigger, "https://"), "http://"), "/"); ok && (path != "") {
+		return RuleTypeActor
+	}
+
+	return RuleTypeDomain
+}
+
+// WriteBlocklistCSV writes DOMAIN and ACTOR Rules in the Mastodon `domain_blocks.csv` format,
+// with additional `#type` and `#reason` columns.  Public comments are only exported for Rules
+// that are marked public.  Rules of other types are skipped.
+func WriteBlocklistCSV(writer io.Writer, rules []Rule) error {
+
+	const location = "model.WriteBlocklistCSV"
+
+	csvWriter := csv.NewWriter(writer)
+
+	header := []string{"#domain", "#severity", "#reject_media", "#reject_reports", "#public_comment", "#obfuscate", "#type", "#reason"}
+
+	if err := csvWriter.Write(header); err != nil {
+		return derp.Wrap(err, location, "Writing CSV header")
+	}
+
+	for _, rule := range rules {
+
+		if (rule.Type != RuleTypeDomain) && (rule.Type != RuleTypeActor) {
+			continue
+		}
+
+		comment := ""
+
+		if rule.IsPublic {
+			comment = rule.Summary
+		}
+
+		record := []string{
+			rule.Trigger,
+			blocklistSeverity(rule.Action),
+			"false",
+			"false",
+			comment,
+			"false",
+			strings.ToLower(rule.Type),
+			rule.ReasonCode,
+		}
+
+		if err := csvWriter.Write(record); err != nil {
+			return derp.Wrap(err, location, "Writing CSV record", rule.RuleID)
+		}
+	}
+
+	csvWriter.Flush()
+
+	if err := csvWriter.Error(); err != nil {
+		return derp.Wrap(err, location, "Flushing CSV file")
+	}
+
+	return nil
+}
+
+// blocklistSeverity returns the Mastodon severity that matches a Rule Action
+func blocklistSeverity(action string) string {
+
+	switch action {
+
+	case RuleActionBlock:
+		return BlocklistSeveritySuspend
+
+	case RuleActionMute:
+		return BlocklistSeveritySilence
+	}
+
+	return BlocklistSeverityNoop
+}
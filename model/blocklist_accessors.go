@@ -0,0 +1,63 @@
+package model
+
+import (
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func BlocklistSchema() schema.Element {
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"blocklistId": schema.String{Format: "objectId"},
+			"label":       schema.String{Format: "text", MaxLength: 64, Required: true},
+			"url":         schema.String{Format: "url"},
+			"content":     schema.String{MaxLength: BlocklistContentMaxLength},
+			"autoApply":   schema.Boolean{},
+		},
+	}
+}
+
+func (blocklist *Blocklist) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "label":
+		return &blocklist.Label, true
+
+	case "url":
+		return &blocklist.URL, true
+
+	case "content":
+		return &blocklist.Content, true
+
+	case "autoApply":
+		return &blocklist.AutoApply, true
+	}
+
+	return nil, false
+}
+
+func (blocklist Blocklist) GetStringOK(name string) (string, bool) {
+
+	switch name {
+
+	case "blocklistId":
+		return blocklist.BlocklistID.Hex(), true
+	}
+
+	return "", false
+}
+
+func (blocklist *Blocklist) SetString(name string, value string) bool {
+
+	switch name {
+
+	case "blocklistId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			blocklist.BlocklistID = objectID
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,34 @@
+package model
+
+// BlocklistChangeAdd creates a new server-wide Rule for a blocklist entry
+const BlocklistChangeAdd = "ADD"
+
+// BlocklistChangeUpdate changes the Action, reason, or comment of a Rule that a blocklist created
+const BlocklistChangeUpdate = "UPDATE"
+
+// BlocklistChangeRemove retracts a Rule whose entry is no longer in the blocklist
+const BlocklistChangeRemove = "REMOVE"
+
+// BlocklistSeveritySuspend is the Mastodon severity that maps to a BLOCK Rule
+const BlocklistSeveritySuspend = "suspend"
+
+// BlocklistSeveritySilence is the Mastodon severity that maps to a MUTE Rule
+const BlocklistSeveritySilence = "silence"
+
+// BlocklistSeverityNoop is the Mastodon severity for entries that only reject media or reports.
+// Emissary has no equivalent, so these entries are skipped on import, and LABEL Rules export as noop.
+const BlocklistSeverityNoop = "noop"
+
+// BlocklistContentMaxLength caps the size of an uploaded or downloaded blocklist (in bytes)
+const BlocklistContentMaxLength = 4 * 1024 * 1024
+
+// BlocklistMaxEntries caps the number of entries that a single blocklist can contain
+const BlocklistMaxEntries = 50000
+
+// BlocklistMaxRemovalPercent is the largest share of a subscription's existing Rules that a
+// single sync may remove.  Larger removals usually mean that the list was truncated or replaced.
+const BlocklistMaxRemovalPercent = 50
+
+// BlocklistMinRemovals is the number of removals that are always allowed, so that small lists
+// can still shrink normally.
+const BlocklistMinRemovals = 10
@@ -0,0 +1,189 @@
+package model
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBlocklistSchema(t *testing.T) {
+
+	s := schema.New(BlocklistSchema())
+	blocklist := NewBlocklist()
+
+	tests := []tableTestItem{
+		{"blocklistId", "000000000000000000000001", nil},
+		{"label", "LABEL", nil},
+		{"url", "https://example.com/blocklist.csv", nil},
+		{"content", "#domain,#severity\nexample.social,suspend", nil},
+		{"autoApply", true, nil},
+	}
+
+	tableTest_Schema(t, &s, &blocklist, tests)
+}
+
+func TestBlocklist_RemoteID(t *testing.T) {
+
+	blocklist := NewBlocklist()
+
+	// One-time uploads are not tracked
+	require.Equal(t, "", blocklist.RemoteID("DOMAIN:example.social"))
+
+	blocklist.URL = "https://example.com/blocklist.csv"
+	require.Equal(t, "https://example.com/blocklist.csv#DOMAIN:example.social", blocklist.RemoteID("DOMAIN:example.social"))
+	require.Equal(t, "", blocklist.RemoteID(""))
+}
+
+func TestParseBlocklistCSV_Mastodon(t *testing.T) {
+
+	content := strings.Join([]string{
+		"#domain,#severity,#reject_media,#reject_reports,#public_comment,#obfuscate",
+		"spam.example,suspend,false,false,Spam factory,false",
+		"loud.example,silence,true,false,,false",
+		"media.example,noop,true,false,Media only,false",
+		"hidden.ex*mple,suspend,false,false,,true",
+		"",
+	}, "\n")
+
+	entries, err := ParseBlocklistCSV(strings.NewReader(content))
+	require.Nil(t, err)
+	require.Equal(t, []BlocklistEntry{
+		{Type: RuleTypeDomain, Trigger: "spam.example", Action: RuleActionBlock, Summary: "Spam factory"},
+		{Type: RuleTypeDomain, Trigger: "loud.example", Action: RuleActionMute},
+	}, entries)
+}
+
+func TestParseBlocklistCSV_Headerless(t *testing.T) {
+
+	content := "# A plain list of domains\nspam.example\n\nhttps://bad.example/@troll\n@troll@other.example\n"
+
+	entries, err := ParseBlocklistCSV(strings.NewReader(content))
+	require.Nil(t, err)
+	require.Equal(t, []BlocklistEntry{
+		{Type: RuleTypeDomain, Trigger: "spam.example", Action: RuleActionBlock},
+		{Type: RuleTypeActor, Trigger: "https://bad.example/@troll", Action: RuleActionBlock},
+		{Type: RuleTypeActor, Trigger: "@troll@other.example", Action: RuleActionBlock},
+	}, entries)
+}
+
+func TestBlocklistCSV_RoundTrip(t *testing.T) {
+
+	rules := []Rule{
+		{Type: RuleTypeDomain, Trigger: "spam.example", Action: RuleActionBlock, Summary: "Spam factory", ReasonCode: "SPAM", IsPublic: true},
+		{Type: RuleTypeActor, Trigger: "https://bad.example/@troll", Action: RuleActionMute, Summary: "Private note"},
+		{Type: RuleTypeTag, Trigger: "spam", Action: RuleActionBlock},
+	}
+
+	var buffer bytes.Buffer
+	require.Nil(t, WriteBlocklistCSV(&buffer, rules))
+
+	entries, err := ParseBlocklistCSV(&buffer)
+	require.Nil(t, err)
+	require.Equal(t, []BlocklistEntry{
+		{Type: RuleTypeDomain, Trigger: "spam.example", Action: RuleActionBlock, ReasonCode: "SPAM", Summary: "Spam factory"},
+		{Type: RuleTypeActor, Trigger: "https://bad.example/@troll", Action: RuleActionMute},
+	}, entries)
+}
+
+func TestBlocklist_Diff(t *testing.T) {
+
+	blocklist := NewBlocklist()
+	blocklist.URL = "https://example.com/blocklist.csv"
+
+	unchangedID := primitive.NewObjectID()
+	changedID := primitive.NewObjectID()
+	removedID := primitive.NewObjectID()
+
+	rules := []Rule{
+		{RuleID: unchangedID, BlocklistID: blocklist.BlocklistID, RemoteID: blocklist.RemoteID("DOMAIN:unchanged.example"), Type: RuleTypeDomain, Trigger: "unchanged.example", MatchKey: "DOMAIN:unchanged.example", Action: RuleActionBlock},
+		{RuleID: changedID, BlocklistID: blocklist.BlocklistID, RemoteID: blocklist.RemoteID("DOMAIN:changed.example"), Type: RuleTypeDomain, Trigger: "changed.example", MatchKey: "DOMAIN:changed.example", Action: RuleActionMute},
+		{RuleID: removedID, BlocklistID: blocklist.BlocklistID, RemoteID: blocklist.RemoteID("DOMAIN:removed.example"), Type: RuleTypeDomain, Trigger: "removed.example", MatchKey: "DOMAIN:removed.example", Action: RuleActionBlock},
+		{RuleID: primitive.NewObjectID(), Type: RuleTypeDomain, Trigger: "local.example", MatchKey: "DOMAIN:local.example", Action: RuleActionMute},
+	}
+
+	entries := []BlocklistEntry{
+		{Type: RuleTypeDomain, Trigger: "unchanged.example", Action: RuleActionBlock},
+		{Type: RuleTypeDomain, Trigger: "changed.example", Action: RuleActionBlock},
+		{Type: RuleTypeDomain, Trigger: "local.example", Action: RuleActionBlock},
+		{Type: RuleTypeDomain, Trigger: "new.example", Action: RuleActionBlock},
+		{Type: RuleTypeDomain, Trigger: "NEW.example", Action: RuleActionMute},
+	}
+
+	changes := blocklist.Diff(entries, rules)
+	require.Equal(t, 3, len(changes))
+
+	// Owned Rules are updated in place
+	require.Equal(t, BlocklistChangeUpdate, changes[0].Operation)
+	require.Equal(t, changedID, changes[0].RuleID)
+	require.Equal(t, RuleActionBlock, changes[0].Action)
+
+	// New entries are added (once), but local Rules are never overridden
+	require.Equal(t, BlocklistChangeAdd, changes[1].Operation)
+	require.Equal(t, "new.example", changes[1].Trigger)
+	require.Equal(t, RuleActionBlock, changes[1].Action)
+	require.Equal(t, blocklist.RemoteID("DOMAIN:new.example"), changes[1].RemoteID)
+
+	// Entries that disappear from a subscription are retracted
+	require.Equal(t, BlocklistChangeRemove, changes[2].Operation)
+	require.Equal(t, removedID, changes[2].RuleID)
+}
+
+func TestBlocklist_Diff_Upload(t *testing.T) {
+
+	// One-time uploads never own Rules, so they never remove anything
+	blocklist := NewBlocklist()
+
+	rules := []Rule{
+		{RuleID: primitive.NewObjectID(), RemoteID: "https://example.com/other.csv#DOMAIN:other.example", BlocklistID: primitive.NewObjectID(), Type: RuleTypeDomain, Trigger: "other.example", MatchKey: "DOMAIN:other.example", Action: RuleActionBlock},
+	}
+
+	entries := []BlocklistEntry{
+		{Type: RuleTypeDomain, Trigger: "other.example", Action: RuleActionMute},
+		{Type: RuleTypeDomain, Trigger: "new.example", Action: RuleActionBlock},
+	}
+
+	changes := blocklist.Diff(entries, rules)
+	require.Equal(t, 1, len(changes))
+	require.Equal(t, BlocklistChangeAdd, changes[0].Operation)
+	require.Equal(t, "", changes[0].RemoteID)
+}
+
+func TestBlocklist_SyncError(t *testing.T) {
+
+	blocklist := NewBlocklist()
+	blocklist.URL = "https://example.com/blocklist.csv"
+	blocklist.EntryCount = 100
+
+	removals := func(count int) []BlocklistChange {
+		result := make([]BlocklistChange, count)
+		for index := range result {
+			result[index] = BlocklistChange{Operation: BlocklistChangeRemove}
+		}
+		return result
+	}
+
+	// Ordinary refreshes are safe
+	blocklist.Pending = removals(5)
+	require.Empty(t, blocklist.SyncError(100))
+
+	// Small lists can always shrink a little
+	blocklist.Pending = removals(BlocklistMinRemovals)
+	require.Empty(t, blocklist.SyncError(BlocklistMinRemovals))
+
+	// Removing most of the existing entries is refused
+	blocklist.Pending = removals(60)
+	require.NotEmpty(t, blocklist.SyncError(100))
+
+	// An empty copy of the list is refused
+	blocklist.Pending = removals(0)
+	blocklist.EntryCount = 0
+	require.NotEmpty(t, blocklist.SyncError(100))
+
+	// One-time uploads never remove anything, so they are always safe
+	upload := NewBlocklist()
+	require.Empty(t, upload.SyncError(0))
+}
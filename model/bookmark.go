@@ -0,0 +1,58 @@
+package model
+
+import (
+	"time"
+
+	"github.com/benpate/data/journal"
+	"github.com/benpate/toot/object"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Bookmark is a private, per-User reference to a document that the User has saved for later.
+// Unlike a Response, a Bookmark is never published to ActivityPub.
+type Bookmark struct {
+	BookmarkID primitive.ObjectID `bson:"_id"`    // Unique identifier for this Bookmark
+	UserID     primitive.ObjectID `bson:"userId"` // ID of the User who saved this Bookmark
+	URL        string             `bson:"url"`    // ActivityPubURL of the document that was bookmarked
+
+	journal.Journal `json:"-" bson:",inline"`
+}
+
+// NewBookmark returns a fully initialized Bookmark object
+func NewBookmark() Bookmark {
+	return Bookmark{
+		BookmarkID: primitive.NewObjectID(),
+	}
+}
+
+/******************************************
+ * data.Object Interface
+ ******************************************/
+
+// ID returns the unique identifier for this Bookmark (in string format)
+func (bookmark Bookmark) ID() string {
+	return bookmark.BookmarkID.Hex()
+}
+
+/******************************************
+ * Mastodon API
+ ******************************************/
+
+// Toot returns a minimal Mastodon API Status that identifies the bookmarked document.
+// Handlers should prefer the full document from the ActivityStream cache, and only use
+// this when that document can't be loaded.
+func (bookmark Bookmark) Toot() object.Status {
+
+	return object.Status{
+		ID:         bookmark.URL,
+		URI:        bookmark.URL,
+		URL:        bookmark.URL,
+		CreatedAt:  time.UnixMilli(bookmark.CreateDate).UTC().Format(time.RFC3339), // CreateDate is milliseconds (journal UnixMilli)
+		Bookmarked: true,
+	}
+}
+
+// GetRank returns the value used to paginate Bookmarks in the Mastodon API
+func (bookmark Bookmark) GetRank() int64 {
+	return bookmark.CreateDate
+}
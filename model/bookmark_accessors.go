@@ -0,0 +1,63 @@
+package model
+
+import (
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BookmarkSchema returns a validating schema for Bookmark objects
+func BookmarkSchema() schema.Element {
+
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"bookmarkId": schema.String{Required: true, Format: "objectId"},
+			"userId":     schema.String{Required: true, Format: "objectId"},
+			"url":        schema.String{Required: true, Format: "url"},
+		},
+	}
+}
+
+func (bookmark *Bookmark) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "url":
+		return &bookmark.URL, true
+	}
+
+	return nil, false
+}
+
+func (bookmark Bookmark) GetStringOK(name string) (string, bool) {
+
+	switch name {
+
+	case "bookmarkId":
+		return bookmark.BookmarkID.Hex(), true
+
+	case "userId":
+		return bookmark.UserID.Hex(), true
+	}
+
+	return "", false
+}
+
+func (bookmark *Bookmark) SetString(name string, value string) bool {
+
+	switch name {
+
+	case "bookmarkId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			bookmark.BookmarkID = objectID
+			return true
+		}
+
+	case "userId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			bookmark.UserID = objectID
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/schema"
+)
+
+func TestBookmark(t *testing.T) {
+
+	s := schema.New(BookmarkSchema())
+	bookmark := NewBookmark()
+
+	tests := []tableTestItem{
+		{"bookmarkId", "000000000000000000000001", nil},
+		{"userId", "000000000000000000000002", nil},
+		{"url", "https://example.com/object", nil},
+	}
+
+	tableTest_Schema(t, &s, &bookmark, tests)
+}
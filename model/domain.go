@@ -27,6 +27,8 @@ type Domain struct {
 	ColorMode            string                          `bson:"colorMode"`            // Color mode for this domain (e.g. "LIGHT", "DARK", or "AUTO")
 	MLSMode              string                          `bson:"mlsMode"`              // MLS mode for this domain (e.g. "ALL", "GROUPS", or "NONE")
 	MLSGroupIDs          sliceof.String                  `bson:"mlsGroupIds"`          // List of GroupIDs that are allowed to use MLS features (only used if MLSMode is "GROUPS")
+	TwoFactorMode        string                          `bson:"twoFactorMode"`        // Which users must use two-factor authentication (e.g. "NONE", "OWNERS", or "GROUPS")
+	TwoFactorGroupIDs    sliceof.String                  `bson:"twoFactorGroupIds"`    // List of GroupIDs (i.e. group admins) that must use two-factor authentication (only used if TwoFactorMode is "GROUPS")
 	DefaultAnonymous     string                          `bson:"defaultAnonymous"`     // Default page for anonymous users (defaults to "/home")
 	DefaultAuthenticated string                          `bson:"defaultAuthenticated"` // Default page for authenticated users (defaults to "/@me")
 	DefaultOwner         string                          `bson:"defaultOwner"`         // Default page for owners (defaults to "/admin")
@@ -35,18 +37,21 @@ type Domain struct {
 	Syndication          sliceof.Object[form.LookupCode] `bson:"syndication"`          // List of external services that this domain can syndicate to
 	Connections          mapof.Matchable[Connection]     `bson:"connections"`          // Map of external connections for this domain
 	PrivateKey           string                          `bson:"privateKey"`           // Private key for this domain
+	Ed25519PrivateKey    string                          `bson:"ed25519PrivateKey"`    // Ed25519 private key (PKCS #8 PEM) that signs RFC 9421 requests for this domain
 	journal.Journal      `json:"-" bson:",inline"`
 }
 
 // NewDomain returns a fully initialized Domain object
 func NewDomain() Domain {
 	return Domain{
-		ThemeData:   mapof.NewAny(),
-		ColorMode:   DomainColorModeAuto,
-		MLSGroupIDs: sliceof.NewString(),
-		Data:        mapof.NewString(),
-		Syndication: sliceof.NewObject[form.LookupCode](),
-		Connections: mapof.NewMatchable[Connection](),
+		ThemeData:         mapof.NewAny(),
+		ColorMode:         DomainColorModeAuto,
+		MLSGroupIDs:       sliceof.NewString(),
+		TwoFactorMode:     DomainTwoFactorModeNone,
+		TwoFactorGroupIDs: sliceof.NewString(),
+		Data:              mapof.NewString(),
+		Syndication:       sliceof.NewObject[form.LookupCode](),
+		Connections:       mapof.NewMatchable[Connection](),
 	}
 }
 
@@ -189,6 +194,36 @@ func (domain *Domain) UserCanMLS(user *User) bool {
 	return false
 }
 
+// UserRequiresTwoFactor returns TRUE if the provided user must sign in with a second factor.
+func (domain *Domain) UserRequiresTwoFactor(user *User) bool {
+
+	if user == nil {
+		return false
+	}
+
+	switch domain.TwoFactorMode {
+
+	case DomainTwoFactorModeOwners:
+		return user.IsOwner
+
+	case DomainTwoFactorModeGroups:
+		if user.IsOwner {
+			return true
+		}
+
+		for _, groupID := range domain.TwoFactorGroupIDs {
+			if objectID, err := primitive.ObjectIDFromHex(groupID); err == nil {
+				if user.GroupIDs.Contains(objectID) {
+					return true
+				}
+			}
+		}
+	}
+
+	// Fallthrough includes DomainTwoFactorModeNone and any unrecognized values
+	return false
+}
+
 // UserCanBridgeToBluesky returns TRUE if the provided user is allowed to bridge to Bluesky.
 func (domain *Domain) UserCanBridgeToBluesky(user *User) bool {
 
@@ -26,10 +26,12 @@ func DomainSchema() schema.Element {
 			"data":                 schema.Object{Wildcard: schema.String{MaxLength: 4096}},
 			"colorMode":            schema.String{Enum: []string{DomainColorModeAuto, DomainColorModeLight, DomainColorModeDark}},
 			"mlsMode":              schema.String{Enum: []string{DomainMLSModeAll, DomainMLSModeGroups, DomainMLSModeNone}},
+			"twoFactorMode":        schema.String{Enum: []string{DomainTwoFactorModeNone, DomainTwoFactorModeOwners, DomainTwoFactorModeGroups}},
 			"defaultAnonymous":     schema.String{MaxLength: 128},
 			"defaultAuthenticated": schema.String{MaxLength: 128},
 			"defaultOwner":         schema.String{MaxLength: 128},
 			"mlsGroupIds":          schema.String{MaxLength: 2048},
+			"twoFactorGroupIds":    schema.String{MaxLength: 2048},
 			"syndication":          schema.Array{Items: form.LookupCodeSchema()},
 			"registrationData":     schema.Object{Wildcard: schema.String{MaxLength: 8192}},
 		},
@@ -74,6 +76,9 @@ func (domain *Domain) GetPointer(name string) (any, bool) {
 	case "mlsMode":
 		return &domain.MLSMode, true
 
+	case "twoFactorMode":
+		return &domain.TwoFactorMode, true
+
 	case "data":
 		return &domain.Data, true
 
@@ -114,6 +119,9 @@ func (domain Domain) GetStringOK(name string) (string, bool) {
 
 	case "mlsGroupIds":
 		return domain.MLSGroupIDs.Join(","), true
+
+	case "twoFactorGroupIds":
+		return domain.TwoFactorGroupIDs.Join(","), true
 	}
 
 	return "", false
@@ -159,6 +167,10 @@ func (domain *Domain) SetString(name string, value string) bool {
 		domain.MLSGroupIDs = strings.Split(value, ",")
 		return true
 
+	case "twoFactorGroupIds":
+		domain.TwoFactorGroupIDs = strings.Split(value, ",")
+		return true
+
 	case "iconUrl":
 		return true // Virtual fields can't be set, but don't return an error if someone tries
 
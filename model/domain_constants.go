@@ -17,3 +17,12 @@ const DomainMLSModeGroups = "GROUPS"
 
 // DomainMLSModeNone represents MLS mode where no users can use MLS features
 const DomainMLSModeNone = "NONE"
+
+// DomainTwoFactorModeNone represents two-factor mode where no users are required to use a second factor
+const DomainTwoFactorModeNone = "NONE"
+
+// DomainTwoFactorModeOwners represents two-factor mode where domain owners must use a second factor
+const DomainTwoFactorModeOwners = "OWNERS"
+
+// DomainTwoFactorModeGroups represents two-factor mode where domain owners and the members of specific (admin) groups must use a second factor
+const DomainTwoFactorModeGroups = "GROUPS"
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func TestDomainSchema(t *testing.T) {
@@ -46,7 +48,36 @@ func TestDomainSchema(t *testing.T) {
 		{"imageId", "aaa4bbb8ddd4ddd812345679", nil},
 		{"mlsGroupIds", "GROUP-IDS", nil},
 		{"mlsMode", DomainMLSModeGroups, nil},
+		{"twoFactorGroupIds", "GROUP-IDS", nil},
+		{"twoFactorMode", DomainTwoFactorModeOwners, nil},
 	}
 
 	tableTest_Schema(t, &s, &domain, table)
 }
+
+func TestDomain_UserRequiresTwoFactor(t *testing.T) {
+
+	groupID := primitive.NewObjectID()
+
+	owner := NewUser()
+	owner.IsOwner = true
+
+	admin := NewUser()
+	admin.AddGroup(groupID)
+
+	member := NewUser()
+
+	domain := NewDomain()
+	require.False(t, domain.UserRequiresTwoFactor(&owner))
+	require.False(t, domain.UserRequiresTwoFactor(nil))
+
+	domain.TwoFactorMode = DomainTwoFactorModeOwners
+	require.True(t, domain.UserRequiresTwoFactor(&owner))
+	require.False(t, domain.UserRequiresTwoFactor(&admin))
+
+	domain.TwoFactorMode = DomainTwoFactorModeGroups
+	domain.TwoFactorGroupIDs = []string{groupID.Hex()}
+	require.True(t, domain.UserRequiresTwoFactor(&owner))
+	require.True(t, domain.UserRequiresTwoFactor(&admin))
+	require.False(t, domain.UserRequiresTwoFactor(&member))
+}
@@ -1,13 +1,24 @@
 package model
 
 import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+
+	"github.com/EmissarySocial/emissary/tools/multikey"
 	"github.com/benpate/data/journal"
+	"github.com/benpate/derp"
 	"github.com/benpate/rosetta/schema"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-const EncryptionKeyEncodingPlaintext = "plaintext"
-
+// EncryptionKey is the public/private key pair that an ActivityPub actor uses to sign its requests.
+// Each record holds an RSA key (for draft-cavage signatures) and an Ed25519 key (for RFC 9421
+// signatures). Private keys are encrypted at rest with the domain's master key (see Encoding).
+// When a key is rotated, the previous key is retired (but still published) until its ExpireDate passes.
 type EncryptionKey struct {
 	EncryptionKeyID primitive.ObjectID `json:"encryptionKeyId" bson:"_id"`
 	ParentType      string             `json:"parentType"      bson:"parentType"`
@@ -15,6 +26,13 @@ type EncryptionKey struct {
 	Encoding        string             `json:"encoding"        bson:"encoding"`
 	PublicPEM       string             `json:"publicPEM"       bson:"publicPEM"`
 	PrivatePEM      string             `json:"privatePEM"      bson:"privatePEM"`
+	Nonce           string             `json:"nonce"           bson:"nonce"`           // Hex-encoded nonce used to encrypt the PrivatePEM
+	PublicMultibase string             `json:"publicMultibase" bson:"publicMultibase"` // Ed25519 public key, in Multikey format
+	PrivateEd25519  string             `json:"privateEd25519"  bson:"privateEd25519"`  // Hex-encoded, encrypted Ed25519 private key seed
+	NonceEd25519    string             `json:"nonceEd25519"    bson:"nonceEd25519"`    // Hex-encoded nonce used to encrypt the PrivateEd25519
+	RotatedDate     int64              `json:"rotatedDate"     bson:"rotatedDate"`     // Unix epoch (seconds) when this key was replaced by a newer key.  Zero for the current key.
+	ExpireDate      int64              `json:"expireDate"      bson:"expireDate"`      // Unix epoch (seconds) after which a retired key no longer verifies.  Zero for the current key.
+	Fragment        string             `json:"fragment"        bson:"fragment"`        // URL fragment that identifies this key in its owner's actor document.  Empty for the original "main-key".
 
 	journal.Journal `json:"-" bson:",inline"`
 }
@@ -34,6 +52,13 @@ func EncryptionKeySchema() schema.Element {
 			"encoding":        schema.String{Required: true},
 			"publicPEM":       schema.String{Required: true},
 			"privatePEM":      schema.String{Required: true},
+			"nonce":           schema.String{},
+			"publicMultibase": schema.String{},
+			"privateEd25519":  schema.String{},
+			"nonceEd25519":    schema.String{},
+			"rotatedDate":     schema.Integer{BitSize: 64},
+			"expireDate":      schema.Integer{BitSize: 64},
+			"fragment":        schema.String{},
 		},
 	}
 }
@@ -45,3 +70,273 @@ func EncryptionKeySchema() schema.Element {
 func (encryptionKey *EncryptionKey) ID() string {
 	return encryptionKey.EncryptionKeyID.Hex()
 }
+
+/******************************
+ * Key Lifecycle
+ ******************************/
+
+// IsCurrent returns TRUE if this is the key that its parent currently signs with
+func (encryptionKey EncryptionKey) IsCurrent() bool {
+	return encryptionKey.RotatedDate == 0
+}
+
+// IsVerifiable returns TRUE if this key should still be published so that
+// remote servers can verify signatures made with it.
+func (encryptionKey EncryptionKey) IsVerifiable(now int64) bool {
+	return encryptionKey.IsCurrent() || (encryptionKey.ExpireDate > now)
+}
+
+// Retire marks this key as replaced by a newer key. It will continue
+// to verify until `expireDate`
+func (encryptionKey *EncryptionKey) Retire(now int64, expireDate int64) {
+	encryptionKey.RotatedDate = now
+	encryptionKey.ExpireDate = expireDate
+}
+
+// NameFragment assigns this key a unique URL fragment, based on its EncryptionKeyID.
+// Keys created by rotation use this so that they never share an ID with an earlier key.
+func (encryptionKey *EncryptionKey) NameFragment() {
+	encryptionKey.Fragment = "key-" + encryptionKey.EncryptionKeyID.Hex()
+}
+
+// PublicKeyFragment returns the URL fragment for this key's RSA public key.
+// The original key for each parent is always "main-key".  The fragment never changes,
+// so signatures made with a retired key still resolve to it during the grace period.
+func (encryptionKey EncryptionKey) PublicKeyFragment() string {
+
+	if encryptionKey.Fragment == "" {
+		return "main-key"
+	}
+
+	return encryptionKey.Fragment
+}
+
+// Ed25519KeyFragment returns the URL fragment for this key's Ed25519 public key.
+func (encryptionKey EncryptionKey) Ed25519KeyFragment() string {
+
+	if encryptionKey.Fragment == "" {
+		return "ed25519-key"
+	}
+
+	return "ed25519-" + encryptionKey.Fragment
+}
+
+/******************************
+ * Encryption at Rest
+ ******************************/
+
+// IsEncrypted returns TRUE if the PrivatePEM is encrypted with a master key
+func (encryptionKey EncryptionKey) IsEncrypted() bool {
+	return encryptionKey.Encoding == EncryptionKeyEncodingAESGCM
+}
+
+// SetPrivatePEM sets a new (plaintext) private key and encrypts it with the provided master key.
+func (encryptionKey *EncryptionKey) SetPrivatePEM(privatePEM string, masterKey []byte) error {
+
+	// The EncryptionKeyID is authenticated as additional data so that
+	// ciphertext cannot be copied from one EncryptionKey record into another.
+	nonce, ciphertext, err := encryptionKeySeal(masterKey, []byte(privatePEM), encryptionKey.EncryptionKeyID[:])
+
+	if err != nil {
+		return derp.Wrap(err, "model.EncryptionKey.SetPrivatePEM", "Encrypting private key")
+	}
+
+	encryptionKey.Encoding = EncryptionKeyEncodingAESGCM
+	encryptionKey.Nonce = nonce
+	encryptionKey.PrivatePEM = ciphertext
+
+	return nil
+}
+
+// GetPrivatePEM returns the (plaintext) private key, decrypting it with the provided master key if necessary.
+func (encryptionKey EncryptionKey) GetPrivatePEM(masterKey []byte) (string, error) {
+
+	const location = "model.EncryptionKey.GetPrivatePEM"
+
+	switch encryptionKey.Encoding {
+
+	// Legacy keys that have not yet been encrypted
+	case EncryptionKeyEncodingPlaintext, "":
+		return encryptionKey.PrivatePEM, nil
+
+	case EncryptionKeyEncodingAESGCM:
+
+		plaintext, err := encryptionKeyOpen(masterKey, encryptionKey.Nonce, encryptionKey.PrivatePEM, encryptionKey.EncryptionKeyID[:])
+
+		if err != nil {
+			return "", derp.Wrap(err, location, "Decrypting private key", encryptionKey.EncryptionKeyID)
+		}
+
+		return string(plaintext), nil
+	}
+
+	return "", derp.Internal(location, "Unrecognized encoding", encryptionKey.EncryptionKeyID, encryptionKey.Encoding)
+}
+
+// ReEncrypt decrypts the private key with the `oldMasterKey` and encrypts it again with the `newMasterKey`.
+// Legacy plaintext keys are simply encrypted with the `newMasterKey`
+func (encryptionKey *EncryptionKey) ReEncrypt(oldMasterKey []byte, newMasterKey []byte) error {
+
+	const location = "model.EncryptionKey.ReEncrypt"
+
+	privatePEM, err := encryptionKey.GetPrivatePEM(oldMasterKey)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Decrypting private key")
+	}
+
+	if err := encryptionKey.SetPrivatePEM(privatePEM, newMasterKey); err != nil {
+		return derp.Wrap(err, location, "Encrypting private key")
+	}
+
+	// Re-encrypt the Ed25519 key, too (if it exists)
+	if encryptionKey.HasEd25519() {
+
+		privateKey, err := encryptionKey.GetEd25519(oldMasterKey)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Decrypting Ed25519 key")
+		}
+
+		if err := encryptionKey.SetEd25519(privateKey, newMasterKey); err != nil {
+			return derp.Wrap(err, location, "Encrypting Ed25519 key")
+		}
+	}
+
+	return nil
+}
+
+/******************************
+ * Ed25519 Keys
+ ******************************/
+
+// HasEd25519 returns TRUE if this record includes an Ed25519 key.  Keys created before
+// RFC 9421 support do not have one until it is generated.
+func (encryptionKey EncryptionKey) HasEd25519() bool {
+	return encryptionKey.PublicMultibase != ""
+}
+
+// SetEd25519 sets a new Ed25519 private key, encrypting it with the provided master key
+// and publishing its public half in Multikey format.
+func (encryptionKey *EncryptionKey) SetEd25519(privateKey ed25519.PrivateKey, masterKey []byte) error {
+
+	nonce, ciphertext, err := encryptionKeySeal(masterKey, privateKey.Seed(), encryptionKey.ed25519AdditionalData())
+
+	if err != nil {
+		return derp.Wrap(err, "model.EncryptionKey.SetEd25519", "Encrypting private key")
+	}
+
+	encryptionKey.PublicMultibase = multikey.EncodeEd25519(privateKey.Public().(ed25519.PublicKey))
+	encryptionKey.NonceEd25519 = nonce
+	encryptionKey.PrivateEd25519 = ciphertext
+
+	return nil
+}
+
+// GetEd25519 returns the (plaintext) Ed25519 private key, decrypting it with the provided master key.
+func (encryptionKey EncryptionKey) GetEd25519(masterKey []byte) (ed25519.PrivateKey, error) {
+
+	const location = "model.EncryptionKey.GetEd25519"
+
+	if !encryptionKey.HasEd25519() {
+		return nil, derp.NotFound(location, "Ed25519 key has not been generated", encryptionKey.EncryptionKeyID)
+	}
+
+	seed, err := encryptionKeyOpen(masterKey, encryptionKey.NonceEd25519, encryptionKey.PrivateEd25519, encryptionKey.ed25519AdditionalData())
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Decrypting private key", encryptionKey.EncryptionKeyID)
+	}
+
+	if len(seed) != ed25519.SeedSize {
+		return nil, derp.Internal(location, "Invalid seed size", encryptionKey.EncryptionKeyID)
+	}
+
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// ed25519AdditionalData binds the Ed25519 ciphertext to this record AND to its field,
+// so that it cannot be swapped with the RSA ciphertext of the same record.
+func (encryptionKey EncryptionKey) ed25519AdditionalData() []byte {
+	return append(encryptionKey.EncryptionKeyID[:], []byte("ed25519")...)
+}
+
+// encryptionKeyCipher returns an AES-GCM cipher for the provided master key
+func encryptionKeyCipher(masterKey []byte) (cipher.AEAD, error) {
+
+	const location = "model.encryptionKeyCipher"
+
+	block, err := aes.NewCipher(masterKey)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Creating AES block cipher")
+	}
+
+	aesgcm, err := cipher.NewGCM(block)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Generating GCM cipher")
+	}
+
+	return aesgcm, nil
+}
+
+// encryptionKeySeal encrypts a value with the master key and a new, random nonce.
+// It returns the hex-encoded nonce and ciphertext.
+func encryptionKeySeal(masterKey []byte, plaintext []byte, additionalData []byte) (string, string, error) {
+
+	const location = "model.encryptionKeySeal"
+
+	aesgcm, err := encryptionKeyCipher(masterKey)
+
+	if err != nil {
+		return "", "", derp.Wrap(err, location, "Creating cipher")
+	}
+
+	// RULE: Every encryption uses a new, random nonce
+	nonce := make([]byte, aesgcm.NonceSize())
+
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", "", derp.Wrap(err, location, "Generating nonce")
+	}
+
+	ciphertext := aesgcm.Seal(nil, nonce, plaintext, additionalData)
+
+	return hex.EncodeToString(nonce), hex.EncodeToString(ciphertext), nil
+}
+
+// encryptionKeyOpen decrypts a hex-encoded ciphertext that was created by encryptionKeySeal
+func encryptionKeyOpen(masterKey []byte, nonceHex string, ciphertextHex string, additionalData []byte) ([]byte, error) {
+
+	const location = "model.encryptionKeyOpen"
+
+	aesgcm, err := encryptionKeyCipher(masterKey)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Creating cipher")
+	}
+
+	nonce, err := hex.DecodeString(nonceHex)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Invalid nonce")
+	}
+
+	if len(nonce) != aesgcm.NonceSize() {
+		return nil, derp.Internal(location, "Invalid nonce size")
+	}
+
+	ciphertext, err := hex.DecodeString(ciphertextHex)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Invalid ciphertext")
+	}
+
+	plaintext, err := aesgcm.Open(nil, nonce, ciphertext, additionalData)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Decrypting value")
+	}
+
+	return plaintext, nil
+}
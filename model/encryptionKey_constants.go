@@ -5,3 +5,11 @@ const EncryptionKeyTypeUser = "User"
 
 // EncryptionKeyTypeStream identifies an EncryptionKey that is owned by a Stream/Actor
 const EncryptionKeyTypeStream = "Stream"
+
+// EncryptionKeyEncodingPlaintext identifies an EncryptionKey whose PrivatePEM is stored as-is.
+// This is only used by legacy records that have not yet been encrypted.
+const EncryptionKeyEncodingPlaintext = "plaintext"
+
+// EncryptionKeyEncodingAESGCM identifies an EncryptionKey whose PrivatePEM is encrypted with
+// the domain's master key using AES-GCM
+const EncryptionKeyEncodingAESGCM = "aes-gcm"
@@ -0,0 +1,194 @@
+package model
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/tools/multikey"
+	"github.com/stretchr/testify/require"
+)
+
+func testMasterKey(fill byte) []byte {
+	result := make([]byte, 32)
+
+	for index := range result {
+		result[index] = fill
+	}
+
+	return result
+}
+
+func TestEncryptionKey_Encrypt(t *testing.T) {
+
+	masterKey := testMasterKey(1)
+	key := NewEncryptionKey()
+
+	require.Nil(t, key.SetPrivatePEM("PRIVATE KEY", masterKey))
+	require.True(t, key.IsEncrypted())
+	require.NotEqual(t, "PRIVATE KEY", key.PrivatePEM)
+	require.NotEmpty(t, key.Nonce)
+
+	privatePEM, err := key.GetPrivatePEM(masterKey)
+	require.Nil(t, err)
+	require.Equal(t, "PRIVATE KEY", privatePEM)
+}
+
+func TestEncryptionKey_WrongMasterKey(t *testing.T) {
+
+	key := NewEncryptionKey()
+	require.Nil(t, key.SetPrivatePEM("PRIVATE KEY", testMasterKey(1)))
+
+	_, err := key.GetPrivatePEM(testMasterKey(2))
+	require.NotNil(t, err)
+}
+
+func TestEncryptionKey_CopiedCiphertext(t *testing.T) {
+
+	masterKey := testMasterKey(1)
+	original := NewEncryptionKey()
+	require.Nil(t, original.SetPrivatePEM("PRIVATE KEY", masterKey))
+
+	// Ciphertext is bound to its own record, so it cannot be replayed into another one
+	copied := NewEncryptionKey()
+	copied.Encoding = original.Encoding
+	copied.Nonce = original.Nonce
+	copied.PrivatePEM = original.PrivatePEM
+
+	_, err := copied.GetPrivatePEM(masterKey)
+	require.NotNil(t, err)
+}
+
+func TestEncryptionKey_Plaintext(t *testing.T) {
+
+	key := NewEncryptionKey()
+	key.Encoding = EncryptionKeyEncodingPlaintext
+	key.PrivatePEM = "PRIVATE KEY"
+
+	require.False(t, key.IsEncrypted())
+
+	privatePEM, err := key.GetPrivatePEM(testMasterKey(1))
+	require.Nil(t, err)
+	require.Equal(t, "PRIVATE KEY", privatePEM)
+}
+
+func TestEncryptionKey_ReEncrypt(t *testing.T) {
+
+	oldMasterKey := testMasterKey(1)
+	newMasterKey := testMasterKey(2)
+
+	key := NewEncryptionKey()
+	require.Nil(t, key.SetPrivatePEM("PRIVATE KEY", oldMasterKey))
+	require.Nil(t, key.ReEncrypt(oldMasterKey, newMasterKey))
+
+	_, err := key.GetPrivatePEM(oldMasterKey)
+	require.NotNil(t, err)
+
+	privatePEM, err := key.GetPrivatePEM(newMasterKey)
+	require.Nil(t, err)
+	require.Equal(t, "PRIVATE KEY", privatePEM)
+}
+
+func TestEncryptionKey_Retire(t *testing.T) {
+
+	key := NewEncryptionKey()
+	require.True(t, key.IsCurrent())
+	require.True(t, key.IsVerifiable(1000))
+
+	key.Retire(1000, 2000)
+	require.False(t, key.IsCurrent())
+	require.True(t, key.IsVerifiable(1999))
+	require.False(t, key.IsVerifiable(2000))
+}
+
+func TestEncryptionKey_Fragment(t *testing.T) {
+
+	// The original key keeps the fragments that remote servers already know
+	original := NewEncryptionKey()
+	require.Equal(t, "main-key", original.PublicKeyFragment())
+	require.Equal(t, "ed25519-key", original.Ed25519KeyFragment())
+
+	// Rotated keys are named individually
+	rotated := NewEncryptionKey()
+	rotated.NameFragment()
+	require.Equal(t, "key-"+rotated.EncryptionKeyID.Hex(), rotated.PublicKeyFragment())
+	require.Equal(t, "ed25519-key-"+rotated.EncryptionKeyID.Hex(), rotated.Ed25519KeyFragment())
+
+	// Retiring a key does not change its fragments
+	rotated.Retire(100, 200)
+	original.Retire(100, 200)
+	require.Equal(t, "key-"+rotated.EncryptionKeyID.Hex(), rotated.PublicKeyFragment())
+	require.Equal(t, "main-key", original.PublicKeyFragment())
+}
+
+func TestEncryptionKey_Ed25519(t *testing.T) {
+
+	masterKey := testMasterKey(1)
+	key := NewEncryptionKey()
+	require.False(t, key.HasEd25519())
+
+	_, err := key.GetEd25519(masterKey)
+	require.NotNil(t, err)
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+	require.Nil(t, key.SetEd25519(privateKey, masterKey))
+
+	// The public key is published in Multikey format
+	require.True(t, key.HasEd25519())
+	require.True(t, strings.HasPrefix(key.PublicMultibase, "z6Mk"))
+
+	decoded, err := multikey.DecodeEd25519(key.PublicMultibase)
+	require.Nil(t, err)
+	require.Equal(t, publicKey, decoded)
+
+	// The private key is encrypted at rest
+	require.NotContains(t, key.PrivateEd25519, string(privateKey.Seed()))
+
+	result, err := key.GetEd25519(masterKey)
+	require.Nil(t, err)
+	require.Equal(t, privateKey, result)
+
+	_, err = key.GetEd25519(testMasterKey(2))
+	require.NotNil(t, err)
+}
+
+func TestEncryptionKey_Ed25519_SwappedCiphertext(t *testing.T) {
+
+	masterKey := testMasterKey(1)
+	key := NewEncryptionKey()
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+	require.Nil(t, key.SetPrivatePEM(string(privateKey.Seed()), masterKey))
+
+	// RSA ciphertext from the same record cannot be used as the Ed25519 key
+	key.PublicMultibase = multikey.EncodeEd25519(privateKey.Public().(ed25519.PublicKey))
+	key.NonceEd25519 = key.Nonce
+	key.PrivateEd25519 = key.PrivatePEM
+
+	_, err = key.GetEd25519(masterKey)
+	require.NotNil(t, err)
+}
+
+func TestEncryptionKey_ReEncrypt_Ed25519(t *testing.T) {
+
+	oldMasterKey := testMasterKey(1)
+	newMasterKey := testMasterKey(2)
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+
+	key := NewEncryptionKey()
+	require.Nil(t, key.SetPrivatePEM("PRIVATE KEY", oldMasterKey))
+	require.Nil(t, key.SetEd25519(privateKey, oldMasterKey))
+	require.Nil(t, key.ReEncrypt(oldMasterKey, newMasterKey))
+
+	_, err = key.GetEd25519(oldMasterKey)
+	require.NotNil(t, err)
+
+	result, err := key.GetEd25519(newMasterKey)
+	require.Nil(t, err)
+	require.Equal(t, privateKey, result)
+}
@@ -0,0 +1,191 @@
+package model
+
+import (
+	"slices"
+	"time"
+
+	"github.com/benpate/data/journal"
+	"github.com/benpate/rosetta/sliceof"
+	"github.com/benpate/toot/object"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Filter is a User's named group of keyword and status filters, modeled on Mastodon's v2 filters.
+// Where a Rule matches WHO is talking (by an indexed match key), a Filter matches WHAT is said, so
+// it is evaluated at read time against each document's text.  Its two actions mirror the rule engine:
+// "hide" removes a document like a MUTE Rule, and "warn" annotates it like a LABEL Rule.
+type Filter struct {
+	FilterID   primitive.ObjectID            `bson:"_id"`        // Unique identifier of this Filter
+	UserID     primitive.ObjectID            `bson:"userId"`     // Unique identifier of the User who owns this Filter
+	Title      string                        `bson:"title"`      // Human-friendly name of this Filter, displayed when a status is "warned"
+	Contexts   sliceof.String                `bson:"contexts"`   // Places where this Filter applies (home, notifications, public, thread, account)
+	Action     string                        `bson:"action"`     // Action to take when this Filter matches (warn, hide)
+	ExpireDate int64                         `bson:"expireDate"` // Unix epoch SECONDS when this Filter expires and becomes inert (0 = never)
+	Keywords   sliceof.Object[FilterKeyword] `bson:"keywords"`   // Keywords that trigger this Filter
+	Statuses   sliceof.Object[FilterStatus]  `bson:"statuses"`   // Individual statuses that trigger this Filter
+
+	journal.Journal `json:"-" bson:",inline"`
+}
+
+// NewFilter returns a fully initialized Filter object
+func NewFilter() Filter {
+	return Filter{
+		FilterID: primitive.NewObjectID(),
+		Contexts: sliceof.NewString(),
+		Action:   FilterActionWarn,
+		Keywords: sliceof.NewObject[FilterKeyword](),
+		Statuses: sliceof.NewObject[FilterStatus](),
+	}
+}
+
+/******************************************
+ * data.Object Interface
+ ******************************************/
+
+// ID returns the unique identifier for this Filter (in string format)
+func (filter Filter) ID() string {
+	return filter.FilterID.Hex()
+}
+
+/******************************************
+ * Matching Methods
+ ******************************************/
+
+// IsActive returns TRUE if this Filter has not expired. `now` is the current Unix time in seconds.
+func (filter Filter) IsActive(now int64) bool {
+	return (filter.ExpireDate == 0) || (filter.ExpireDate > now)
+}
+
+// AppliesTo returns TRUE if this Filter is scoped to the provided context
+func (filter Filter) AppliesTo(context string) bool {
+	return slices.Contains(filter.Contexts, context)
+}
+
+// IsHide returns TRUE if this Filter removes matching statuses entirely
+func (filter Filter) IsHide() bool {
+	return filter.Action == FilterActionHide
+}
+
+// Match evaluates a document's searchable text (see ContentText) and URL against this Filter,
+// returning the result and TRUE if any keyword or status matched.
+func (filter Filter) Match(text string, url string) (FilterResult, bool) {
+
+	result := NewFilterResult(filter)
+	result.KeywordMatches = NewKeywordMatcher(filter.Keywords).MatchText(text)
+
+	if url != "" {
+		for _, status := range filter.Statuses {
+			if status.URL == url {
+				result.StatusMatches = append(result.StatusMatches, status.URL)
+			}
+		}
+	}
+
+	return result, result.IsMatch()
+}
+
+/******************************************
+ * Keyword and Status Methods
+ ******************************************/
+
+// KeywordByID returns the keyword with the provided ID, and TRUE if it exists
+func (filter Filter) KeywordByID(keywordID primitive.ObjectID) (FilterKeyword, bool) {
+
+	for _, keyword := range filter.Keywords {
+		if keyword.KeywordID == keywordID {
+			return keyword, true
+		}
+	}
+
+	return FilterKeyword{}, false
+}
+
+// SetKeyword adds or replaces a keyword in this Filter (matched by KeywordID)
+func (filter *Filter) SetKeyword(keyword FilterKeyword) {
+
+	for index := range filter.Keywords {
+		if filter.Keywords[index].KeywordID == keyword.KeywordID {
+			filter.Keywords[index] = keyword
+			return
+		}
+	}
+
+	filter.Keywords = append(filter.Keywords, keyword)
+}
+
+// RemoveKeyword removes a keyword from this Filter
+func (filter *Filter) RemoveKeyword(keywordID primitive.ObjectID) {
+	filter.Keywords = slices.DeleteFunc(filter.Keywords, func(keyword FilterKeyword) bool {
+		return keyword.KeywordID == keywordID
+	})
+}
+
+// StatusByID returns the status with the provided ID, and TRUE if it exists
+func (filter Filter) StatusByID(statusID primitive.ObjectID) (FilterStatus, bool) {
+
+	for _, status := range filter.Statuses {
+		if status.StatusID == statusID {
+			return status, true
+		}
+	}
+
+	return FilterStatus{}, false
+}
+
+// AddStatus adds a status to this Filter.  Adding the same URL twice returns the existing status.
+func (filter *Filter) AddStatus(url string) FilterStatus {
+
+	for _, status := range filter.Statuses {
+		if status.URL == url {
+			return status
+		}
+	}
+
+	status := NewFilterStatus()
+	status.URL = url
+	filter.Statuses = append(filter.Statuses, status)
+	return status
+}
+
+// RemoveStatus removes a status from this Filter
+func (filter *Filter) RemoveStatus(statusID primitive.ObjectID) {
+	filter.Statuses = slices.DeleteFunc(filter.Statuses, func(status FilterStatus) bool {
+		return status.StatusID == statusID
+	})
+}
+
+/******************************************
+ * Mastodon API
+ ******************************************/
+
+// Toot returns this Filter as a Mastodon API Filter
+func (filter Filter) Toot() object.Filter {
+
+	result := object.Filter{
+		ID:           filter.FilterID.Hex(),
+		Title:        filter.Title,
+		Context:      filter.Contexts,
+		FilterAction: filter.Action,
+		Keywords:     make([]object.FilterKeyword, len(filter.Keywords)),
+		Statuses:     make([]object.FilterStatus, len(filter.Statuses)),
+	}
+
+	if filter.ExpireDate > 0 {
+		result.ExpiresAt = time.Unix(filter.ExpireDate, 0).UTC().Format(time.RFC3339)
+	}
+
+	for index, keyword := range filter.Keywords {
+		result.Keywords[index] = keyword.Toot()
+	}
+
+	for index, status := range filter.Statuses {
+		result.Statuses[index] = status.Toot()
+	}
+
+	return result
+}
+
+// GetRank returns the value used to paginate Filters in the Mastodon API
+func (filter Filter) GetRank() int64 {
+	return filter.CreateDate
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"github.com/benpate/toot/object"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FilterKeyword is a single word or phrase that triggers a Filter
+type FilterKeyword struct {
+	KeywordID primitive.ObjectID `bson:"keywordId"` // Unique identifier of this keyword
+	Keyword   string             `bson:"keyword"`   // Word or phrase to match (case-insensitive)
+	WholeWord bool               `bson:"wholeWord"` // If TRUE, the keyword only matches at word boundaries
+}
+
+// NewFilterKeyword returns a fully initialized FilterKeyword
+func NewFilterKeyword() FilterKeyword {
+	return FilterKeyword{
+		KeywordID: primitive.NewObjectID(),
+		WholeWord: true,
+	}
+}
+
+// Toot returns this keyword as a Mastodon API FilterKeyword
+func (keyword FilterKeyword) Toot() object.FilterKeyword {
+	return object.FilterKeyword{
+		ID:        keyword.KeywordID.Hex(),
+		Keyword:   keyword.Keyword,
+		WholeWord: keyword.WholeWord,
+	}
+}
@@ -0,0 +1,145 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/benpate/hannibal/metadata"
+	"github.com/benpate/html"
+	"github.com/benpate/toot/object"
+)
+
+// FilterResult describes why a Filter matched a document: which of its keywords appeared in the
+// document's text, and whether the document itself was filtered by URL.
+type FilterResult struct {
+	Filter         Filter   // The Filter that matched
+	KeywordMatches []string // Keywords that appeared in the document
+	StatusMatches  []string // Status URLs that matched the document
+}
+
+// NewFilterResult returns an empty FilterResult for the provided Filter
+func NewFilterResult(filter Filter) FilterResult {
+	return FilterResult{
+		Filter:         filter,
+		KeywordMatches: make([]string, 0),
+		StatusMatches:  make([]string, 0),
+	}
+}
+
+// IsMatch returns TRUE if any keyword or status matched
+func (result FilterResult) IsMatch() bool {
+	return (len(result.KeywordMatches) > 0) || (len(result.StatusMatches) > 0)
+}
+
+// Toot returns this result as a Mastodon API FilterResult
+func (result FilterResult) Toot() object.FilterResult {
+	return object.FilterResult{
+		Filter:         result.Filter.Toot(),
+		KeywordMatches: result.KeywordMatches,
+		StatusMatches:  result.StatusMatches,
+	}
+}
+
+/******************************************
+ * FilterResults
+ ******************************************/
+
+// FilterResults is the set of Filters that matched a single document
+type FilterResults []FilterResult
+
+// MatchFilters evaluates a document's searchable text and URL against every provided Filter.
+// Callers are expected to pass only the Filters that are active in the current context.
+func MatchFilters(filters []Filter, text string, url string) FilterResults {
+
+	result := make(FilterResults, 0)
+
+	for _, filter := range filters {
+		if match, ok := filter.Match(text, url); ok {
+			result = append(result, match)
+		}
+	}
+
+	return result
+}
+
+// IsHidden returns TRUE if any matching Filter hides the document
+func (results FilterResults) IsHidden() bool {
+
+	for _, result := range results {
+		if result.Filter.IsHide() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AppendLabels adds these results to a document's per-viewer LabelSet, in the same shape that
+// the Rule engine uses: "hide" Filters add a hidden Label (rendered as a placeholder, just like a
+// MUTE Rule) and "warn" Filters add an annotation with the Filter's title (like a LABEL Rule).
+// Hidden labels go first, matching the LabelSet's display convention.
+func (results FilterResults) AppendLabels(labels metadata.LabelSet) metadata.LabelSet {
+
+	if len(results) == 0 {
+		return labels
+	}
+
+	hidden := make(metadata.LabelSet, 0, len(results))
+	annotations := make(metadata.LabelSet, 0, len(results))
+
+	for _, result := range results {
+
+		if result.Filter.IsHide() {
+			hidden = append(hidden, metadata.Label{
+				Value:    "Filtered: " + result.Filter.Title,
+				IsHidden: true,
+			})
+			continue
+		}
+
+		annotations = append(annotations, metadata.Label{
+			Value: result.Filter.Title,
+		})
+	}
+
+	combined := make(metadata.LabelSet, 0, len(hidden)+len(labels)+len(annotations))
+	combined = append(combined, hidden...)
+	combined = append(combined, labels...)
+	combined = append(combined, annotations...)
+
+	return combined
+}
+
+// Toot returns these results as a slice of Mastodon API FilterResults
+func (results FilterResults) Toot() []object.FilterResult {
+
+	toots := make([]object.FilterResult, len(results))
+
+	for index, result := range results {
+		toots[index] = result.Toot()
+	}
+
+	return toots
+}
+
+/******************************************
+ * Helpers
+ ******************************************/
+
+// FilterTextFromStrings returns the lower-cased, plain-text version of the provided (HTML) values,
+// separated by newlines so that a keyword cannot match across two of them.
+func FilterTextFromStrings(values ...string) string {
+
+	var buffer strings.Builder
+
+	for _, value := range values {
+
+		if value == "" {
+			continue
+		}
+
+		buffer.WriteString(strings.ToLower(html.ToText(value)))
+		buffer.WriteString("\n")
+	}
+
+	return buffer.String()
+}
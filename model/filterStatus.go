@@ -0,0 +1,27 @@
+package model
+
+import (
+	"github.com/benpate/toot/object"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FilterStatus is a single status that triggers a Filter, regardless of its content
+type FilterStatus struct {
+	StatusID primitive.ObjectID `bson:"statusId"` // Unique identifier of this status filter
+	URL      string             `bson:"url"`      // ActivityPub URL of the filtered status
+}
+
+// NewFilterStatus returns a fully initialized FilterStatus
+func NewFilterStatus() FilterStatus {
+	return FilterStatus{
+		StatusID: primitive.NewObjectID(),
+	}
+}
+
+// Toot returns this status filter as a Mastodon API FilterStatus
+func (status FilterStatus) Toot() object.FilterStatus {
+	return object.FilterStatus{
+		ID:       status.StatusID.Hex(),
+		StatusID: status.URL,
+	}
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FilterSchema returns a validating schema for Filter objects
+func FilterSchema() schema.Element {
+
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"filterId": schema.String{Format: "objectId"},
+			"userId":   schema.String{Required: true, Format: "objectId"},
+			"title":    schema.String{Required: true, MaxLength: 256},
+			"contexts": schema.Array{Items: schema.String{Enum: []string{
+				FilterContextHome,
+				FilterContextNotifications,
+				FilterContextPublic,
+				FilterContextThread,
+				FilterContextAccount,
+			}}},
+			"action":     schema.String{Required: true, Enum: []string{FilterActionWarn, FilterActionHide}},
+			"expireDate": schema.Integer{BitSize: 64},
+		},
+	}
+}
+
+func (filter *Filter) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "title":
+		return &filter.Title, true
+
+	case "contexts":
+		return &filter.Contexts, true
+
+	case "action":
+		return &filter.Action, true
+
+	case "expireDate":
+		return &filter.ExpireDate, true
+	}
+
+	return nil, false
+}
+
+func (filter Filter) GetStringOK(name string) (string, bool) {
+
+	switch name {
+
+	case "filterId":
+		return filter.FilterID.Hex(), true
+
+	case "userId":
+		return filter.UserID.Hex(), true
+	}
+
+	return "", false
+}
+
+func (filter *Filter) SetString(name string, value string) bool {
+
+	switch name {
+
+	case "filterId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			filter.FilterID = objectID
+			return true
+		}
+
+	case "userId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			filter.UserID = objectID
+			return true
+		}
+	}
+
+	return false
+}
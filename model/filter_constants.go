@@ -0,0 +1,22 @@
+package model
+
+// FilterActionWarn shows matching statuses behind a warning that names the Filter
+const FilterActionWarn = "warn"
+
+// FilterActionHide removes matching statuses from the results entirely
+const FilterActionHide = "hide"
+
+// FilterContextHome applies a Filter to the home timeline and lists
+const FilterContextHome = "home"
+
+// FilterContextNotifications applies a Filter to notifications
+const FilterContextNotifications = "notifications"
+
+// FilterContextPublic applies a Filter to public timelines
+const FilterContextPublic = "public"
+
+// FilterContextThread applies a Filter to expanded threads (status context)
+const FilterContextThread = "thread"
+
+// FilterContextAccount applies a Filter to account profiles
+const FilterContextAccount = "account"
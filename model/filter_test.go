@@ -0,0 +1,73 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterSchema(t *testing.T) {
+
+	s := schema.New(FilterSchema())
+	filter := NewFilter()
+
+	tests := []tableTestItem{
+		{"filterId", "000000000000000000000001", nil},
+		{"userId", "000000000000000000000002", nil},
+		{"title", "Spoilers", nil},
+		{"contexts.0", "home", nil},
+		{"contexts.1", "thread", nil},
+		{"action", "hide", nil},
+		{"expireDate", int64(1700000000), nil},
+	}
+
+	tableTest_Schema(t, &s, &filter, tests)
+}
+
+func TestFilter_Match(t *testing.T) {
+
+	filter := NewFilter()
+	filter.Keywords = append(filter.Keywords, FilterKeyword{Keyword: "spoiler", WholeWord: true})
+	filter.AddStatus("https://example.com/status/1")
+
+	result, ok := filter.Match(FilterTextFromStrings("<p>Major SPOILER ahead</p>"), "")
+	require.True(t, ok)
+	require.Equal(t, []string{"spoiler"}, result.KeywordMatches)
+
+	result, ok = filter.Match("nothing to see here", "https://example.com/status/1")
+	require.True(t, ok)
+	require.Empty(t, result.KeywordMatches)
+	require.Equal(t, []string{"https://example.com/status/1"}, result.StatusMatches)
+
+	_, ok = filter.Match("nothing to see here", "https://example.com/status/2")
+	require.False(t, ok)
+}
+
+func TestFilter_IsActive(t *testing.T) {
+
+	filter := NewFilter()
+	require.True(t, filter.IsActive(1000))
+
+	filter.ExpireDate = 1000
+	require.False(t, filter.IsActive(1000))
+	require.True(t, filter.IsActive(999))
+}
+
+func TestMatchFilters_IsHidden(t *testing.T) {
+
+	warn := NewFilter()
+	warn.Keywords = append(warn.Keywords, FilterKeyword{Keyword: "politics"})
+
+	hide := NewFilter()
+	hide.Action = FilterActionHide
+	hide.Keywords = append(hide.Keywords, FilterKeyword{Keyword: "election"})
+
+	results := MatchFilters([]Filter{warn, hide}, "politics today", "")
+	require.Len(t, results, 1)
+	require.False(t, results.IsHidden())
+
+	results = MatchFilters([]Filter{warn, hide}, "politics and the election", "")
+	require.Len(t, results, 2)
+	require.True(t, results.IsHidden())
+}
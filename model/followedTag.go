@@ -0,0 +1,110 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/benpate/data/journal"
+	"github.com/benpate/toot/object"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// followedTagSearchPath is the local search page that FollowedTag links point to.
+const followedTagSearchPath = "/search?q="
+
+// FollowedTag represents a #hashtag that a User follows.  Public posts that carry
+// this tag are delivered into the User's inbox, labeled with the tag that matched.
+type FollowedTag struct {
+	FollowedTagID primitive.ObjectID `bson:"_id"`      // Unique ID for this FollowedTag
+	UserID        primitive.ObjectID `bson:"userId"`   // ID of the User who follows this tag
+	FolderID      primitive.ObjectID `bson:"folderId"` // ID of the Folder where matching posts are delivered
+	Name          string             `bson:"name"`     // Human-friendly name of the tag (without the leading "#")
+	Value         string             `bson:"value"`    // Normalized version of the tag name, used for matching
+
+	journal.Journal `json:"-" bson:",inline"`
+}
+
+// NewFollowedTag returns a fully initialized FollowedTag object
+func NewFollowedTag() FollowedTag {
+	return FollowedTag{
+		FollowedTagID: primitive.NewObjectID(),
+	}
+}
+
+/******************************************
+ * data.Object Interface
+ ******************************************/
+
+func (followedTag FollowedTag) ID() string {
+	return followedTag.FollowedTagID.Hex()
+}
+
+/******************************************
+ * Other Data Accessors
+ ******************************************/
+
+// SetName updates the Name of this FollowedTag, along with its normalized Value
+func (followedTag *FollowedTag) SetName(name string) {
+	followedTag.Name = strings.TrimPrefix(strings.TrimSpace(name), "#")
+	followedTag.Value = ToToken(followedTag.Name)
+}
+
+// URL returns the local search page for this tag on the provided host
+func (followedTag FollowedTag) URL(host string) string {
+	return HashtagURL(host, followedTagSearchPath, followedTag.Name)
+}
+
+// Origin returns an OriginLink that labels inbox messages delivered because of this tag
+func (followedTag FollowedTag) Origin(host string) OriginLink {
+	return OriginLink{
+		Type:  OriginTypeHashtag,
+		Label: "#" + followedTag.Name,
+		URL:   followedTag.URL(host),
+	}
+}
+
+/******************************************
+ * AccessLister Interface
+ ******************************************/
+
+// State returns the current state of this FollowedTag.
+// It is part of the AccessLister interface
+func (followedTag *FollowedTag) State() string {
+	return "default"
+}
+
+// IsAuthor returns TRUE if the provided UserID the author of this FollowedTag
+// It is part of the AccessLister interface
+func (followedTag *FollowedTag) IsAuthor(authorID primitive.ObjectID) bool {
+	return false
+}
+
+// IsMyself returns TRUE if this object directly represents the provided UserID
+// It is part of the AccessLister interface
+func (followedTag *FollowedTag) IsMyself(userID primitive.ObjectID) bool {
+	return !userID.IsZero() && userID == followedTag.UserID
+}
+
+// RolesToGroupIDs returns a slice of Group IDs that grant access to any of the requested roles.
+// It is part of the AccessLister interface
+func (followedTag *FollowedTag) RolesToGroupIDs(roleIDs ...string) Permissions {
+	return defaultRolesToGroupIDs(followedTag.UserID, roleIDs...)
+}
+
+// RolesToPrivilegeIDs returns a slice of Privileges that grant access to any of the requested roles.
+// It is part of the AccessLister interface
+func (followedTag *FollowedTag) RolesToPrivilegeIDs(roleIDs ...string) Permissions {
+	return NewPermissions()
+}
+
+/******************************************
+ * Mastodon API
+ ******************************************/
+
+// Toot returns this FollowedTag as a Mastodon Tag that the User is following
+func (followedTag FollowedTag) Toot(host string) object.Tag {
+	return object.Tag{
+		Name:      followedTag.Name,
+		URL:       followedTag.URL(host),
+		Following: true,
+	}
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FollowedTagSchema returns a Rosetta Schema for the FollowedTag object
+func FollowedTagSchema() schema.Element {
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"followedTagId": schema.String{Format: "objectId"},
+			"userId":        schema.String{Format: "objectId"},
+			"folderId":      schema.String{Format: "objectId"},
+			"name":          schema.String{Format: "text", MaxLength: 128, Required: true},
+			"value":         schema.String{Format: "token", MaxLength: 128},
+		},
+	}
+}
+
+/******************************************
+ * Getter Interfaces
+ ******************************************/
+
+func (followedTag *FollowedTag) GetStringOK(name string) (string, bool) {
+	switch name {
+
+	case "followedTagId":
+		return followedTag.FollowedTagID.Hex(), true
+
+	case "userId":
+		return followedTag.UserID.Hex(), true
+
+	case "folderId":
+		return followedTag.FolderID.Hex(), true
+
+	case "name":
+		return followedTag.Name, true
+
+	case "value":
+		return followedTag.Value, true
+	}
+
+	return "", false
+}
+
+/******************************************
+ * Setter Interfaces
+ ******************************************/
+
+func (followedTag *FollowedTag) SetString(name string, value string) bool {
+	switch name {
+
+	case "followedTagId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			followedTag.FollowedTagID = objectID
+			return true
+		}
+
+	case "userId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			followedTag.UserID = objectID
+			return true
+		}
+
+	case "folderId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			followedTag.FolderID = objectID
+			return true
+		}
+
+	case "name":
+		followedTag.SetName(value)
+		return true
+	}
+
+	return false
+}
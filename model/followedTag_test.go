@@ -0,0 +1,45 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollowedTagSchema(t *testing.T) {
+
+	followedTag := NewFollowedTag()
+	s := schema.New(FollowedTagSchema())
+
+	table := []tableTestItem{
+		{"followedTagId", "123456781234567812345678", nil},
+		{"userId", "876543218765432187654321", nil},
+		{"folderId", "123456781234567812345678", nil},
+		{"name", "Travel", nil},
+	}
+
+	tableTest_Schema(t, &s, &followedTag, table)
+}
+
+func TestFollowedTag_SetName(t *testing.T) {
+
+	followedTag := NewFollowedTag()
+	followedTag.SetName(" #Slow Travel ")
+
+	require.Equal(t, "Slow Travel", followedTag.Name)
+	require.Equal(t, "slow-travel", followedTag.Value)
+}
+
+func TestFollowedTag_Origin(t *testing.T) {
+
+	followedTag := NewFollowedTag()
+	followedTag.SetName("travel")
+
+	origin := followedTag.Origin("https://example.com")
+
+	require.Equal(t, OriginTypeHashtag, origin.Type)
+	require.Equal(t, "#travel", origin.Label)
+	require.Equal(t, "https://example.com/search?q=%23travel", origin.URL)
+	require.Equal(t, "hashtag", origin.Icon())
+}
@@ -11,7 +11,7 @@ type Follower struct {
 	FollowerID primitive.ObjectID `bson:"_id"`        // Unique identifier for this Follower
 	ParentType string             `bson:"type"`       // Type of record being followed (e.g. "User", "Stream", or "Search")
 	ParentID   primitive.ObjectID `bson:"parentId"`   // Unique identifier for the Stream that is being followed (including user's outboxes)
-	StateID    string             `bson:"stateId"`    // Unique identifier for the State of this Follower ("ACTIVE", "PENDING")
+	StateID    string             `bson:"stateId"`    // Unique identifier for the State of this Follower ("ACTIVE", "PENDING", "REQUESTED")
 	Method     string             `bson:"method"`     // Method of follower (e.g. "POLL", "ACTIVITYPUB", "EMAIL")
 	Format     string             `bson:"format"`     // Format of the data being followed (e.g. "ATOM", "HTML", "JSON", "RSS", "XML")
 	Actor      PersonLink         `bson:"actor"`      // Person who is follower the User
@@ -79,6 +79,23 @@ func (follower *Follower) RolesToPrivilegeIDs(roleIDs ...string) Permissions {
  * Other Calculations
  ******************************************/
 
+// IsActive returns TRUE if this Follower is currently receiving updates
+func (follower Follower) IsActive() bool {
+	return follower.StateID == FollowerStateActive
+}
+
+// IsRequested returns TRUE if this Follower is waiting for the User
+// to approve (or reject) their follow request.
+func (follower Follower) IsRequested() bool {
+	return follower.StateID == FollowerStateRequested
+}
+
+// FollowActivityID returns the ID of the original "Follow" activity
+// that created this Follower, if it is known.
+func (follower Follower) FollowActivityID() string {
+	return follower.Data.GetString("followId")
+}
+
 // ParentURL returns the URL of the parent object that this Follower is following.
 func (follower Follower) ParentURL(host string) string {
 
@@ -115,3 +132,33 @@ func (follower Follower) GetJSONLD() mapof.Any {
 		vocab.PropertyName: follower.Actor.Name,
 	}
 }
+
+/******************************************
+ * Webhook Interface
+ ******************************************/
+
+// GetWebhookData returns the data for this Follower that will be sent to a webhook
+func (follower Follower) GetWebhookData() mapof.Any {
+	return mapof.Any{
+		"followerId": follower.FollowerID.Hex(),
+		"type":       follower.ParentType,
+		"parentId":   follower.ParentID.Hex(),
+		"stateId":    follower.StateID,
+		"method":     follower.Method,
+		"actor":      follower.Actor.MarshalMap(),
+		"createDate": follower.CreateDate,
+		"updateDate": follower.UpdateDate,
+		"deleteDate": follower.DeleteDate,
+	}
+}
+
+// WebhookUserID returns the User who is being followed.  Followers of
+// Streams and Searches are not owned by a single User, so they return a zero ID.
+func (follower Follower) WebhookUserID() primitive.ObjectID {
+
+	if follower.ParentType == FollowerTypeUser {
+		return follower.ParentID
+	}
+
+	return primitive.NilObjectID
+}
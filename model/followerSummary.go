@@ -9,11 +9,12 @@ type FollowerSummary struct {
 	ParentID   primitive.ObjectID `bson:"parentId"` // Unique identifier for the User that is being followed
 	Actor      PersonLink         `bson:"actor"`    // Person who is follower the User
 	Method     string             `bson:"method"`   // Method of follower (e.g. "POLL", "EMAIL", "ActivityPub".)
+	StateID    string             `bson:"stateId"`  // Unique identifier for the State of this Follower ("ACTIVE", "PENDING", "REQUESTED")
 }
 
 // FollowerSummaryFields returns a slice of all BSON field names for a FollowerSummary
 func FollowerSummaryFields() []string {
-	return []string{"_id", "parentId", "actor", "method"}
+	return []string{"_id", "parentId", "actor", "method", "stateId"}
 }
 
 func (summary FollowerSummary) Fields() []string {
@@ -35,3 +36,9 @@ func (summary FollowerSummary) Icon() string {
 
 	return ""
 }
+
+// IsRequested returns TRUE if this Follower is waiting for the User
+// to approve (or reject) their follow request.
+func (summary FollowerSummary) IsRequested() bool {
+	return summary.StateID == FollowerStateRequested
+}
@@ -14,7 +14,7 @@ func FollowerSchema() schema.Element {
 			"type":       schema.String{Enum: []string{FollowerTypeSearch, FollowerTypeSearchDomain, FollowerTypeStream, FollowerTypeUser}},
 			"method":     schema.String{Enum: []string{FollowerMethodActivityPub, FollowerMethodEmail}},
 			"format":     schema.String{Enum: []string{MimeTypeActivityPub, MimeTypeAtom, MimeTypeHTML, MimeTypeJSONFeed, MimeTypeRSS, MimeTypeXML}},
-			"stateId":    schema.String{Enum: []string{FollowerStateActive, FollowerStatePending}},
+			"stateId":    schema.String{Enum: []string{FollowerStateActive, FollowerStatePending, FollowerStateRequested}},
 			"actor":      PersonLinkSchema(),
 			"data":       schema.Object{Wildcard: schema.String{MaxLength: 256}},
 			"expireDate": schema.Integer{BitSize: 64},
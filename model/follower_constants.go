@@ -63,3 +63,8 @@ const FollowerStatePaused = "PAUSED"
 // FollowerStatePending represents an inactive Follower who has yet
 // to confirm their subscription status (e.g. via email confirmation)
 const FollowerStatePending = "PENDING"
+
+// FollowerStateRequested represents a Follower who has asked to follow a User
+// that approves followers manually.  Requested Followers do not receive updates
+// until the User accepts their request.
+const FollowerStateRequested = "REQUESTED"
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFollowerSchema(t *testing.T) {
@@ -18,6 +19,7 @@ func TestFollowerSchema(t *testing.T) {
 		{"method", FollowerMethodActivityPub, nil},
 		{"format", MimeTypeActivityPub, nil},
 		{"stateId", FollowerStateActive, nil},
+		{"stateId", FollowerStateRequested, nil},
 		{"actor.name", "ACTOR NAME", nil},
 		{"data.first", "DATA FIRST", nil},
 		{"expireDate", "1234", int64(1234)},
@@ -25,3 +27,29 @@ func TestFollowerSchema(t *testing.T) {
 
 	tableTest_Schema(t, &s, &follower, table)
 }
+
+func TestFollower_IsRequested(t *testing.T) {
+
+	follower := NewFollower()
+	require.False(t, follower.IsRequested())
+
+	follower.StateID = FollowerStateRequested
+	follower.Data["followId"] = "https://remote.example/follows/1"
+	require.True(t, follower.IsRequested())
+	require.Equal(t, "https://remote.example/follows/1", follower.FollowActivityID())
+
+	follower.StateID = FollowerStateActive
+	require.False(t, follower.IsRequested())
+}
+
+func TestFollower_IsActive(t *testing.T) {
+
+	follower := NewFollower()
+	require.False(t, follower.IsActive())
+
+	follower.StateID = FollowerStateRequested
+	require.False(t, follower.IsActive())
+
+	follower.StateID = FollowerStateActive
+	require.True(t, follower.IsActive())
+}
@@ -141,6 +141,17 @@ func (following Following) NotZero() bool {
 	return !following.IsZero()
 }
 
+// IsPending returns TRUE if this is an ActivityPub Following that the remote
+// actor has not yet accepted.
+func (following Following) IsPending() bool {
+
+	if following.Method != FollowingMethodActivityPub {
+		return false
+	}
+
+	return (following.Status == FollowingStatusNew) || (following.Status == FollowingStatusLoading)
+}
+
 func (following Following) UsernameOrID() string {
 	if following.Username != "" {
 		return following.Username
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFollowingSchema(t *testing.T) {
@@ -35,3 +36,21 @@ func TestFollowingSchema(t *testing.T) {
 
 	tableTest_Schema(t, &s, &following, table)
 }
+
+func TestFollowing_IsPending(t *testing.T) {
+
+	following := NewFollowing()
+	following.Method = FollowingMethodActivityPub
+	require.True(t, following.IsPending())
+
+	following.Status = FollowingStatusLoading
+	require.True(t, following.IsPending())
+
+	following.Status = FollowingStatusSuccess
+	require.False(t, following.IsPending())
+
+	// Non-ActivityPub feeds never wait for approval
+	following.Method = FollowingMethodPoll
+	following.Status = FollowingStatusNew
+	require.False(t, following.IsPending())
+}
@@ -2,7 +2,11 @@ package model
 
 import (
 	"net/url"
+	"slices"
 	"strings"
+
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
 )
 
 // HashtagURLPrefix returns the link prefix for #hashtags, built from the "tagUrl"
@@ -42,3 +46,37 @@ func HashtagURL(hostname string, tagURL string, tag string) string {
 
 	return prefix + "%23" + url.QueryEscape(tag)
 }
+
+// DocumentHashtags returns the normalized (ToToken) values of every Hashtag on the
+// provided document, without duplicates.  Mentions and Emoji are excluded, and
+// bare-string tags are skipped because reading their names would fetch them over
+// the network.
+func DocumentHashtags(document streams.Document) []string {
+
+	result := make([]string, 0)
+
+	for tag := document.Tag(); tag.NotNil(); tag = tag.Next() {
+
+		if tag.IsString() {
+			continue
+		}
+
+		if tag.Type() != vocab.LinkTypeHashtag {
+			continue
+		}
+
+		token := ToToken(tag.Name())
+
+		if token == "" {
+			continue
+		}
+
+		if slices.Contains(result, token) {
+			continue
+		}
+
+		result = append(result, token)
+	}
+
+	return result
+}
@@ -3,6 +3,9 @@ package model
 import (
 	"testing"
 
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
 	"github.com/stretchr/testify/require"
 )
 
@@ -43,3 +46,18 @@ func TestHashtagURL_Escapes(t *testing.T) {
 	require.Equal(t, "https://example.com/search?q=%23caf%C3%A9", HashtagURL("https://example.com", "/search?q=", "café"))
 	require.Equal(t, `https://example.com/search?q=%23%22%3E%3Cscript%3E`, HashtagURL("https://example.com", "/search?q=", `"><script>`))
 }
+
+// TestDocumentHashtags confirms that Hashtags are normalized and de-duplicated, and that Mentions are ignored.
+func TestDocumentHashtags(t *testing.T) {
+
+	document := streams.NewDocument(mapof.Any{
+		vocab.PropertyTag: []any{
+			mapof.Any{vocab.PropertyType: vocab.LinkTypeHashtag, vocab.PropertyName: "#Travel"},
+			mapof.Any{vocab.PropertyType: vocab.LinkTypeHashtag, vocab.PropertyName: "travel"},
+			mapof.Any{vocab.PropertyType: vocab.LinkTypeHashtag, vocab.PropertyName: "#SlowFood"},
+			mapof.Any{vocab.PropertyType: vocab.LinkTypeMention, vocab.PropertyName: "alice"},
+		},
+	})
+
+	require.Equal(t, []string{"travel", "slowfood"}, DocumentHashtags(document))
+}
@@ -94,3 +94,31 @@ func (inboxActivity InboxActivity) LabeledJSON() string {
 	// Ta-da!
 	return string(data)
 }
+
+/******************************************
+ * Webhook Interface
+ ******************************************/
+
+// GetWebhookData returns the data for this InboxActivity that will be sent to a webhook.
+// The RawActivity is NOT included, because it may contain private (or encrypted) content.
+func (inboxActivity InboxActivity) GetWebhookData() mapof.Any {
+	return mapof.Any{
+		"inboxActivityId": inboxActivity.InboxActivityID.Hex(),
+		"userId":          inboxActivity.UserID.Hex(),
+		"actorId":         inboxActivity.ActorID,
+		"activityId":      inboxActivity.ActivityID,
+		"activityType":    inboxActivity.ActivityType,
+		"context":         inboxActivity.Context,
+		"objectId":        inboxActivity.ObjectID,
+		"objectType":      inboxActivity.ObjectType,
+		"mediaType":       inboxActivity.MediaType,
+		"isPublic":        inboxActivity.IsPublic,
+		"publishedDate":   inboxActivity.PublishedDate,
+		"receivedDate":    inboxActivity.ReceivedDate,
+	}
+}
+
+// WebhookUserID returns the User who received this InboxActivity
+func (inboxActivity InboxActivity) WebhookUserID() primitive.ObjectID {
+	return inboxActivity.UserID
+}
@@ -35,6 +35,12 @@ type AccessLister interface {
 	RolesToPrivilegeIDs(...string) Permissions
 }
 
+// AuthorIDGetter wraps the AuthorID() method, which returns the User who created an object.
+// It is used to check the author's Group memberships.
+type AuthorIDGetter interface {
+	AuthorID() primitive.ObjectID
+}
+
 // ActivityPubURLGetter wraps the ActivityPubURL and Created methods. It is used
 // when creating ActivityPub Collections
 type ActivityPubURLGetter interface {
@@ -75,4 +81,8 @@ type JSONLDGetter interface {
 // return an arbitrary data structure to be sent as a webhook
 type WebhookDataGetter interface {
 	GetWebhookData() mapof.Any
+
+	// WebhookUserID returns the User that this event belongs to, so that
+	// webhooks can be filtered per-user.  Domain-wide events return a zero ID.
+	WebhookUserID() primitive.ObjectID
 }
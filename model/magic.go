@@ -1,6 +1,10 @@
 package model
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 // MagicGroupIDAnonymous refers to a user who has not been signed in.
 // Every user on the Internet is given this group, whether signed in or not.
@@ -30,8 +34,19 @@ const MagicRoleMyself = "self"
 // MagicRoleOwner grants full access to a user with database owner privileges
 const MagicRoleOwner = "owner"
 
+// MagicRoleGroupPrefix grants permissions to members of a named Group.  Templates use roles
+// like "group:editors", where "editors" is the token of a Group defined by the administrator.
+const MagicRoleGroupPrefix = "group:"
+
 func init() {
 	MagicGroupIDAnonymous = primitive.ObjectID{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
 	MagicGroupIDAuthenticated = primitive.ObjectID{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}
 	MagicGroupIDOwners = primitive.ObjectID{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255}
 }
+
+// GroupRoleToken returns the Group token named by a "group:" role, along with
+// TRUE if the role refers to a Group.
+func GroupRoleToken(roleID string) (string, bool) {
+	token, found := strings.CutPrefix(roleID, MagicRoleGroupPrefix)
+	return token, found && (token != "")
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupRoleToken(t *testing.T) {
+
+	token, ok := GroupRoleToken("group:editors")
+	require.True(t, ok)
+	require.Equal(t, "editors", token)
+
+	_, ok = GroupRoleToken("editors")
+	require.False(t, ok)
+
+	_, ok = GroupRoleToken("group:")
+	require.False(t, ok)
+}
+
+func TestTemplate_IsValidRole_Group(t *testing.T) {
+
+	template := NewTemplate("test-template", nil)
+
+	require.True(t, template.IsValidRole("group:moderators"))
+	require.True(t, template.IsValidRole(MagicRoleAuthor))
+	require.False(t, template.IsValidRole("moderators"))
+	require.False(t, template.IsValidRole("group:"))
+}
@@ -306,6 +306,43 @@ func (newsItem *NewsItem) AddReference(reference OriginLink) bool {
 	return true
 }
 
+// DetachRelay unlinks this message from the Relay that forwarded it.  If a Following has
+// also delivered this message, then that Following becomes the new Origin and DetachRelay
+// returns TRUE.  It returns FALSE if the Relay was the only source of this message.
+func (newsItem *NewsItem) DetachRelay() bool {
+
+	for _, reference := range newsItem.References {
+		if !reference.FollowingID.IsZero() {
+			newsItem.Origin = reference
+			return true
+		}
+	}
+
+	return false
+}
+
+// HashtagReferences returns the references that were added because this message
+// carries a #hashtag that the User follows (not including the Origin itself).
+func (newsItem NewsItem) HashtagReferences() sliceof.Object[OriginLink] {
+
+	result := sliceof.NewObject[OriginLink]()
+
+	for _, reference := range newsItem.References {
+
+		if reference.Type != OriginTypeHashtag {
+			continue
+		}
+
+		if newsItem.Origin.Equals(reference) {
+			continue
+		}
+
+		result = append(result, reference)
+	}
+
+	return result
+}
+
 /******************************************
  * Mastodon API
  ******************************************/
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func TestNewsItemSchema(t *testing.T) {
@@ -30,3 +32,47 @@ func TestNewsItemSchema(t *testing.T) {
 
 	tableTest_Schema(t, &s, &activity, table)
 }
+
+func TestNewsItem_HashtagReferences(t *testing.T) {
+
+	travel := OriginLink{Type: OriginTypeHashtag, Label: "#travel", URL: "https://example.com/search?q=%23travel"}
+	food := OriginLink{Type: OriginTypeHashtag, Label: "#food", URL: "https://example.com/search?q=%23food"}
+	person := OriginLink{Type: OriginTypePrimary, Label: "Alice", URL: "https://example.com/@alice"}
+
+	// Tags are listed alongside the Origin
+	newsItem := NewNewsItem()
+	newsItem.AddReference(person)
+	newsItem.AddReference(travel)
+	newsItem.AddReference(food)
+
+	require.Equal(t, []OriginLink{travel, food}, []OriginLink(newsItem.HashtagReferences()))
+
+	// A tag that is already the Origin is not repeated
+	newsItem = NewNewsItem()
+	newsItem.AddReference(travel)
+	newsItem.AddReference(food)
+
+	require.Equal(t, []OriginLink{food}, []OriginLink(newsItem.HashtagReferences()))
+}
+
+func TestNewsItem_DetachRelay(t *testing.T) {
+
+	relayID := primitive.NewObjectID()
+	travel := OriginLink{Type: OriginTypeHashtag, Label: "#travel", URL: "https://example.com/search?q=%23travel"}
+	following := OriginLink{Type: OriginTypePrimary, FollowingID: primitive.NewObjectID(), Label: "Alice", URL: "https://example.com/@alice"}
+
+	// Messages that only a Relay delivered cannot be detached
+	newsItem := NewNewsItem()
+	newsItem.AddReference(travel)
+	newsItem.Origin.RelayID = relayID
+
+	require.False(t, newsItem.DetachRelay())
+	require.Equal(t, relayID, newsItem.Origin.RelayID)
+
+	// Messages that a Following also delivered are re-linked to that Following
+	newsItem.AddReference(following)
+
+	require.True(t, newsItem.DetachRelay())
+	require.Equal(t, following, newsItem.Origin)
+	require.True(t, newsItem.Origin.RelayID.IsZero())
+}
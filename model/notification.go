@@ -164,7 +164,7 @@ func (notification Notification) Channels() []string {
 	case NotificationTypeLike, NotificationTypeDislike, NotificationTypeAnnounce:
 		return []string{NotificationChannelReaction}
 
-	case NotificationTypeFollow:
+	case NotificationTypeFollow, NotificationTypeFollowRequest:
 		return []string{NotificationChannelFollow}
 	}
 
@@ -238,6 +238,8 @@ func (notification Notification) MastodonType() string {
 		return "reblog"
 	case NotificationTypeFollow:
 		return "follow"
+	case NotificationTypeFollowRequest:
+		return "follow_request"
 	default:
 		return ""
 	}
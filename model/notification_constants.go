@@ -20,6 +20,10 @@ const NotificationTypeAnnounce = "ANNOUNCE"
 // NotificationTypeFollow identifies a Notification created because someone began following the recipient
 const NotificationTypeFollow = "FOLLOW"
 
+// NotificationTypeFollowRequest identifies a Notification created because someone asked to follow
+// a recipient who approves followers manually
+const NotificationTypeFollowRequest = "FOLLOW-REQUEST"
+
 // NotificationTypeDirect identifies a Notification created because someone sent the recipient a
 // PRIVATE message: a non-public activity addressed to them by name.  It outranks REPLY and MENTION
 // in the classification ladder (see service.Notification.notifyFromCreateOrUpdate), because a
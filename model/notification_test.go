@@ -53,6 +53,7 @@ func TestNotification_Channels(t *testing.T) {
 		{"dislike folds into reaction", NotificationTypeDislike, NotificationSubtypeNotFollowing, []string{NotificationChannelReaction}},
 		{"announce folds into reaction", NotificationTypeAnnounce, "", []string{NotificationChannelReaction}},
 		{"follow", NotificationTypeFollow, NotificationSubtypeNotFollowing, []string{NotificationChannelFollow}},
+		{"follow request", NotificationTypeFollowRequest, NotificationSubtypeNotFollowing, []string{NotificationChannelFollow}},
 		{"unknown type maps to nothing", "BOGUS", "", []string{}},
 	}
 
@@ -177,3 +178,11 @@ func TestNotification_MastodonType_Direct(t *testing.T) {
 	notification.Type = NotificationTypeDirect
 	require.Equal(t, "mention", notification.MastodonType())
 }
+
+// TestNotification_MastodonType_FollowRequest confirms that follow requests reach the Mastodon
+// API as "follow_request" notifications, so clients can show their approve/reject prompts.
+func TestNotification_MastodonType_FollowRequest(t *testing.T) {
+	notification := NewNotification()
+	notification.Type = NotificationTypeFollowRequest
+	require.Equal(t, "follow_request", notification.MastodonType())
+}
@@ -7,8 +7,9 @@ import (
 // OriginLink represents the original source of a stream that has been imported into Emissary.
 // This could be an external ActivityPub server, RSS Feed, or Tweet.
 type OriginLink struct {
-	Type        string             `bson:"type,omitempty"`        // The type of message that this document (DIRECT, LIKE, DISLIKE, REPLY, ANNOUNCE)
+	Type        string             `bson:"type,omitempty"`        // The type of message that this document (DIRECT, LIKE, DISLIKE, REPLY, ANNOUNCE, HASHTAG)
 	FollowingID primitive.ObjectID `bson:"followingId,omitempty"` // Unique ID of a document in this database
+	RelayID     primitive.ObjectID `bson:"relayId,omitempty"`     // Unique ID of the Relay that forwarded this document (if any)
 	Label       string             `bson:"label,omitempty"`       // Human-friendly label of the origin
 	URL         string             `bson:"url,omitempty"`         // Public URL of the origin
 	IconURL     string             `bson:"iconUrl,omitempty"`     // URL of the a avatar/icon image for this origin
@@ -52,6 +53,9 @@ func (origin OriginLink) Icon() string {
 
 	case OriginTypeAnnounce:
 		return "rocket"
+
+	case OriginTypeHashtag:
+		return "hashtag"
 	}
 
 	return "question-square"
@@ -10,8 +10,9 @@ func OriginLinkSchema() schema.Element {
 
 	return schema.Object{
 		Properties: schema.ElementMap{
-			"type":        schema.String{Enum: []string{OriginTypePrimary, OriginTypeLike, OriginTypeDislike, OriginTypeReply, OriginTypeAnnounce}},
+			"type":        schema.String{Enum: []string{OriginTypePrimary, OriginTypeLike, OriginTypeDislike, OriginTypeReply, OriginTypeAnnounce, OriginTypeHashtag}},
 			"followingId": schema.String{Format: "objectId"},
+			"relayId":     schema.String{Format: "objectId"},
 			"label":       schema.String{Format: "text", MaxLength: 128},
 			"url":         schema.String{Format: "url"},
 			"iconUrl":     schema.String{Format: "url"},
@@ -48,6 +49,9 @@ func (origin *OriginLink) GetStringOK(name string) (string, bool) {
 
 	case "followingId":
 		return origin.FollowingID.Hex(), true
+
+	case "relayId":
+		return origin.RelayID.Hex(), true
 	}
 
 	return "", false
@@ -65,6 +69,12 @@ func (origin *OriginLink) SetString(name string, value string) bool {
 			origin.FollowingID = objectID
 			return true
 		}
+
+	case "relayId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			origin.RelayID = objectID
+			return true
+		}
 	}
 
 	return false
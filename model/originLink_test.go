@@ -15,6 +15,7 @@ func TestOriginLink(t *testing.T) {
 	table := []tableTestItem{
 		{"type", "PRIMARY", nil},
 		{"followingId", "123412341234123412341234", nil},
+		{"relayId", "432143214321432143214321", nil},
 		{"label", "TEST-LABEL", nil},
 		{"url", "https://test.url", nil},
 		{"iconUrl", "https://test.image.url", nil},
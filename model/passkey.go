@@ -0,0 +1,75 @@
+package model
+
+import (
+	"time"
+
+	"github.com/EmissarySocial/emissary/tools/webauthn"
+	"github.com/benpate/data/journal"
+	"github.com/benpate/rosetta/sliceof"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PasskeyChallengeDuration is how long a User has to respond to a passkey prompt
+const PasskeyChallengeDuration = 5 * time.Minute
+
+// Passkey is a WebAuthn credential that a User has registered with one of their authenticators
+// (a phone, a security key, a password manager).  One User may register several passkeys, and
+// each can be used to sign in without a password, or as a second factor after a password.
+type Passkey struct {
+	PasskeyID      primitive.ObjectID `bson:"_id"`            // Unique ID for this passkey
+	UserID         primitive.ObjectID `bson:"userId"`         // Owner (local User)
+	CredentialID   string             `bson:"credentialId"`   // Credential ID chosen by the authenticator (base64url)
+	PublicKey      []byte             `bson:"publicKey"`      // COSE encoded public key
+	SignCount      uint32             `bson:"signCount"`      // Most recent signature counter, used to detect cloned authenticators
+	Transports     sliceof.String     `bson:"transports"`     // Hints for how the browser can reach the authenticator (usb, nfc, internal, etc)
+	BackupEligible bool               `bson:"backupEligible"` // TRUE if the credential can be synced between devices
+	Label          string             `bson:"label"`          // Human-friendly name chosen by the User
+	LastUsedDate   int64              `bson:"lastUsedDate"`   // Unix epoch milliseconds when this passkey was last used to sign in
+
+	journal.Journal `json:"-" bson:",inline"`
+}
+
+// NewPasskey returns a fully initialized Passkey object
+func NewPasskey() Passkey {
+	return Passkey{
+		PasskeyID:  primitive.NewObjectID(),
+		Transports: sliceof.NewString(),
+	}
+}
+
+/******************************************
+ * data.Object Interface
+ ******************************************/
+
+// ID returns a string representation of the Passkey's unique id.
+func (passkey Passkey) ID() string {
+	return passkey.PasskeyID.Hex()
+}
+
+/******************************************
+ * Other Methods
+ ******************************************/
+
+// Credential returns the WebAuthn credential that this Passkey stores
+func (passkey Passkey) Credential() webauthn.Credential {
+
+	// Credential IDs are stored as they were validated during registration, so decoding cannot fail
+	credentialID, _ := webauthn.DecodeID(passkey.CredentialID)
+
+	return webauthn.Credential{
+		ID:             credentialID,
+		PublicKey:      passkey.PublicKey,
+		SignCount:      passkey.SignCount,
+		Transports:     passkey.Transports,
+		BackupEligible: passkey.BackupEligible,
+	}
+}
+
+// SetCredential copies a newly registered WebAuthn credential into this Passkey
+func (passkey *Passkey) SetCredential(credential webauthn.Credential) {
+	passkey.CredentialID = webauthn.EncodeID(credential.ID)
+	passkey.PublicKey = credential.PublicKey
+	passkey.SignCount = credential.SignCount
+	passkey.Transports = credential.Transports
+	passkey.BackupEligible = credential.BackupEligible
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func PasskeySchema() schema.Element {
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"passkeyId":      schema.String{Format: "objectId"},
+			"userId":         schema.String{Format: "objectId"},
+			"credentialId":   schema.String{Required: true, MaxLength: 1400},
+			"transports":     schema.Array{Items: schema.String{MaxLength: 32}},
+			"backupEligible": schema.Boolean{},
+			"label":          schema.String{MaxLength: 64},
+			"lastUsedDate":   schema.Integer{BitSize: 64},
+		},
+	}
+}
+
+/******************************************
+ * Getter/Setter Interfaces
+ ******************************************/
+
+func (passkey *Passkey) GetPointer(name string) (any, bool) {
+	switch name {
+
+	case "credentialId":
+		return &passkey.CredentialID, true
+
+	case "transports":
+		return &passkey.Transports, true
+
+	case "backupEligible":
+		return &passkey.BackupEligible, true
+
+	case "label":
+		return &passkey.Label, true
+
+	case "lastUsedDate":
+		return &passkey.LastUsedDate, true
+	}
+
+	return nil, false
+}
+
+func (passkey *Passkey) GetStringOK(name string) (string, bool) {
+	switch name {
+
+	case "passkeyId":
+		return passkey.PasskeyID.Hex(), true
+
+	case "userId":
+		return passkey.UserID.Hex(), true
+	}
+
+	return "", false
+}
+
+func (passkey *Passkey) SetString(name string, value string) bool {
+	switch name {
+
+	case "passkeyId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			passkey.PasskeyID = objectID
+			return true
+		}
+
+	case "userId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			passkey.UserID = objectID
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/EmissarySocial/emissary/tools/webauthn"
+	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasskey(t *testing.T) {
+
+	passkey := NewPasskey()
+
+	s := schema.New(PasskeySchema())
+
+	table := []tableTestItem{
+		{"passkeyId", "123412341234123412341234", nil},
+		{"userId", "123456781234567812345678", nil},
+		{"credentialId", "Y3JlZGVudGlhbC1pZA", nil},
+		{"backupEligible", true, nil},
+		{"label", "My Phone", nil},
+		{"lastUsedDate", int64(1700000000), nil},
+	}
+
+	tableTest_Schema(t, &s, &passkey, table)
+}
+
+func TestPasskey_Credential(t *testing.T) {
+
+	credential := webauthn.Credential{
+		ID:             []byte("credential-id"),
+		PublicKey:      []byte{1, 2, 3},
+		SignCount:      42,
+		Transports:     []string{"usb", "nfc"},
+		BackupEligible: true,
+	}
+
+	passkey := NewPasskey()
+	passkey.SetCredential(credential)
+
+	require.Equal(t, "Y3JlZGVudGlhbC1pZA", passkey.CredentialID)
+	require.Equal(t, credential, passkey.Credential())
+}
@@ -0,0 +1,285 @@
+package model
+
+import (
+	"slices"
+	"time"
+
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/convert"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/rosetta/sliceof"
+	"github.com/benpate/toot/object"
+	"github.com/relvacode/iso8601"
+)
+
+// Poll is a set of choices that people can vote on.  Local Polls are embedded in a Stream
+// and published as an ActivityPub "Question".  Remote Polls are read from the Questions
+// in the ActivityStream cache (see PollFromDocument).
+type Poll struct {
+	Options       sliceof.Object[PollOption] `bson:"options"`                 // Choices that voters can select, in display order
+	Multiple      bool                       `bson:"multiple,omitempty"`      // If TRUE, voters may select more than one option (anyOf).  If FALSE, only one (oneOf)
+	EndDate       int64                      `bson:"endDate,omitempty"`       // Unix epoch SECONDS when voting ends (0 = never)
+	ClosedDate    int64                      `bson:"closedDate,omitempty"`    // Unix epoch SECONDS when this Poll was closed (0 = still open)
+	VoterCount    int                        `bson:"voterCount,omitempty"`    // Number of distinct people who have voted in this Poll
+	ScheduledDate int64                      `bson:"scheduledDate,omitempty"` // EndDate that the current ClosePoll task was scheduled for (Unix epoch SECONDS)
+}
+
+// NewPoll returns a fully initialized Poll object
+func NewPoll() Poll {
+	return Poll{
+		Options: sliceof.NewObject[PollOption](),
+	}
+}
+
+// NewPollWithOptions returns a Poll that offers the provided (non-empty) options
+func NewPollWithOptions(names ...string) Poll {
+
+	result := NewPoll()
+
+	for _, name := range names {
+		if name != "" {
+			result.Options = append(result.Options, NewPollOption(name))
+		}
+	}
+
+	return result
+}
+
+/******************************************
+ * Poll Methods
+ ******************************************/
+
+// IsZero returns TRUE if this Poll has no options.  This lets
+// bson "omitempty" skip Streams that do not include a Poll.
+func (poll Poll) IsZero() bool {
+	return len(poll.Options) == 0
+}
+
+// NotZero returns TRUE if this Poll has at least one option
+func (poll Poll) NotZero() bool {
+	return !poll.IsZero()
+}
+
+// IsClosed returns TRUE if voting has ended. `now` is the current Unix time in seconds.
+func (poll Poll) IsClosed(now int64) bool {
+
+	if poll.ClosedDate > 0 {
+		return true
+	}
+
+	return (poll.EndDate > 0) && (poll.EndDate <= now)
+}
+
+// IsOpen returns TRUE if this Poll is still accepting votes. `now` is the current Unix time in seconds.
+func (poll Poll) IsOpen(now int64) bool {
+	return poll.NotZero() && !poll.IsClosed(now)
+}
+
+// MarkScheduled records the current EndDate as the one that the ClosePoll task is scheduled
+// for, and returns TRUE if it has changed since the task was last scheduled.
+func (poll *Poll) MarkScheduled() bool {
+
+	if poll.ScheduledDate == poll.EndDate {
+		return false
+	}
+
+	poll.ScheduledDate = poll.EndDate
+	return true
+}
+
+// VoteCount returns the total number of votes cast for all options
+func (poll Poll) VoteCount() int {
+
+	result := 0
+
+	for _, option := range poll.Options {
+		result += option.VoteCount
+	}
+
+	return result
+}
+
+// OptionIndex returns the index of the option with the provided name, or -1 if it does not exist
+func (poll Poll) OptionIndex(name string) int {
+
+	for index, option := range poll.Options {
+		if option.Name == name {
+			return index
+		}
+	}
+
+	return -1
+}
+
+// OptionNames returns the names of the options at the provided indexes.
+// It returns FALSE if any index is out of range, or if a single-choice
+// Poll receives more than one choice.
+func (poll Poll) OptionNames(choices []int) (sliceof.String, bool) {
+
+	if len(choices) == 0 {
+		return nil, false
+	}
+
+	if !poll.Multiple && len(choices) > 1 {
+		return nil, false
+	}
+
+	result := make(sliceof.String, 0, len(choices))
+
+	for _, choice := range choices {
+
+		if (choice < 0) || (choice >= len(poll.Options)) {
+			return nil, false
+		}
+
+		name := poll.Options[choice].Name
+
+		if !slices.Contains(result, name) {
+			result = append(result, name)
+		}
+	}
+
+	return result, true
+}
+
+// Tally recalculates the vote counts of this Poll from the complete set of PollVotes cast in it.
+// Choices that do not match an option are ignored.
+func (poll *Poll) Tally(votes []PollVote) {
+
+	for index := range poll.Options {
+		poll.Options[index].VoteCount = 0
+	}
+
+	for _, vote := range votes {
+		for _, choice := range vote.Choices {
+			if index := poll.OptionIndex(choice); index >= 0 {
+				poll.Options[index].VoteCount++
+			}
+		}
+	}
+
+	poll.VoterCount = len(votes)
+}
+
+/******************************************
+ * ActivityPub Methods
+ ******************************************/
+
+// PropertyOptions returns the JSON-LD property that lists this Poll's options (oneOf or anyOf)
+func (poll Poll) PropertyOptions() string {
+
+	if poll.Multiple {
+		return PollPropertyAnyOf
+	}
+
+	return PollPropertyOneOf
+}
+
+// ApplyJSONLD adds this Poll's options and status to an ActivityStreams document,
+// making it a "Question"
+func (poll Poll) ApplyJSONLD(result mapof.Any) {
+
+	options := make([]mapof.Any, 0, len(poll.Options))
+
+	for _, option := range poll.Options {
+		options = append(options, option.JSONLD())
+	}
+
+	result[vocab.PropertyType] = PollObjectType
+	result[poll.PropertyOptions()] = options
+	result[PollPropertyVotersCount] = poll.VoterCount
+
+	if poll.EndDate > 0 {
+		result[PollPropertyEndTime] = time.Unix(poll.EndDate, 0).UTC().Format(time.RFC3339)
+	}
+
+	if poll.ClosedDate > 0 {
+		result[PollPropertyClosed] = time.Unix(poll.ClosedDate, 0).UTC().Format(time.RFC3339)
+	}
+}
+
+// PollFromDocument reads a Poll from an ActivityStreams "Question".  Documents
+// that are not polls return an empty Poll.
+func PollFromDocument(document streams.Document) Poll {
+
+	result := NewPoll()
+	options := document.Get(PollPropertyOneOf)
+
+	if !options.NotNil() {
+		options = document.Get(PollPropertyAnyOf)
+		result.Multiple = options.NotNil()
+	}
+
+	for option := range options.Range() {
+
+		if name := option.Name(); name != "" {
+			pollOption := NewPollOption(name)
+			pollOption.VoteCount = convert.Int(option.Get(vocab.PropertyReplies).Get(vocab.PropertyTotalItems).Value())
+			result.Options = append(result.Options, pollOption)
+		}
+	}
+
+	result.VoterCount = convert.Int(document.Get(PollPropertyVotersCount).Value())
+
+	if endTime, err := iso8601.ParseString(document.Get(PollPropertyEndTime).String()); err == nil {
+		result.EndDate = endTime.Unix()
+	}
+
+	if closed, err := iso8601.ParseString(document.Get(PollPropertyClosed).String()); err == nil {
+		result.ClosedDate = closed.Unix()
+	}
+
+	return result
+}
+
+// IsPollVote returns TRUE if the document is a vote in a Poll.  Votes are
+// Notes that reply to the Question, with the chosen option as their name
+// and no content of their own.
+func IsPollVote(document streams.Document) bool {
+
+	if document.Type() != vocab.ObjectTypeNote {
+		return false
+	}
+
+	if document.Name() == "" {
+		return false
+	}
+
+	if document.Content() != "" {
+		return false
+	}
+
+	return document.InReplyTo().ID() != ""
+}
+
+/******************************************
+ * Mastodon API
+ ******************************************/
+
+// Toot returns this Poll as a Mastodon API Poll.  The ID is the URL of the Poll's
+// Stream/Question, and ownVotes lists the indexes that the viewer has selected
+// (nil if they have not voted).
+func (poll Poll) Toot(id string, ownVotes []int) object.Poll {
+
+	result := object.Poll{
+		ID:          id,
+		Expired:     poll.IsClosed(time.Now().Unix()),
+		Multiple:    poll.Multiple,
+		VotesCount:  poll.VoteCount(),
+		VotersCount: poll.VoterCount,
+		Options:     make([]object.PollOption, len(poll.Options)),
+		Voted:       ownVotes != nil,
+		OwnVotes:    ownVotes,
+	}
+
+	if poll.EndDate > 0 {
+		result.ExpiresAt = time.Unix(poll.EndDate, 0).UTC().Format(time.RFC3339)
+	}
+
+	for index, option := range poll.Options {
+		result.Options[index] = option.Toot()
+	}
+
+	return result
+}
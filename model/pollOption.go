@@ -0,0 +1,40 @@
+package model
+
+import (
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/toot/object"
+)
+
+// PollOption is a single choice in a Poll
+type PollOption struct {
+	Name      string `bson:"name"`      // Text of this option, which voters send back as the "name" of their vote
+	VoteCount int    `bson:"voteCount"` // Number of votes cast for this option
+}
+
+// NewPollOption returns a fully initialized PollOption
+func NewPollOption(name string) PollOption {
+	return PollOption{
+		Name: name,
+	}
+}
+
+// JSONLD returns this option as an entry in a Question's oneOf/anyOf list
+func (option PollOption) JSONLD() mapof.Any {
+	return mapof.Any{
+		vocab.PropertyType: vocab.ObjectTypeNote,
+		vocab.PropertyName: option.Name,
+		vocab.PropertyReplies: mapof.Any{
+			vocab.PropertyType:       vocab.CoreTypeCollection,
+			vocab.PropertyTotalItems: option.VoteCount,
+		},
+	}
+}
+
+// Toot returns this option as a Mastodon API PollOption
+func (option PollOption) Toot() object.PollOption {
+	return object.PollOption{
+		Title:      option.Name,
+		VotesCount: option.VoteCount,
+	}
+}
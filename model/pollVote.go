@@ -0,0 +1,57 @@
+package model
+
+import (
+	"github.com/benpate/data/journal"
+	"github.com/benpate/rosetta/sliceof"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PollVote records the choices that one person made in one Poll.  It covers both
+// directions: votes that our Users cast (in local or remote Polls), and votes that
+// arrive from the Fediverse for Polls that our Users have published.
+type PollVote struct {
+	PollVoteID primitive.ObjectID `bson:"_id"`                // Unique identifier for this PollVote
+	StreamID   primitive.ObjectID `bson:"streamId,omitempty"` // ID of the local Stream that contains the Poll (zero for remote Polls)
+	UserID     primitive.ObjectID `bson:"userId,omitempty"`   // ID of the local User who voted (zero for remote voters)
+	PollURL    string             `bson:"pollUrl"`            // ActivityPub URL of the Question being voted on
+	VoterURL   string             `bson:"voterUrl"`           // ActivityPub URL of the actor who voted
+	Choices    sliceof.String     `bson:"choices"`            // Names of the options that the voter selected
+
+	journal.Journal `json:"-" bson:",inline"`
+}
+
+// NewPollVote returns a fully initialized PollVote object
+func NewPollVote() PollVote {
+	return PollVote{
+		PollVoteID: primitive.NewObjectID(),
+		Choices:    sliceof.NewString(),
+	}
+}
+
+/******************************************
+ * data.Object Interface
+ ******************************************/
+
+// ID returns the unique identifier for this PollVote (in string format)
+func (vote PollVote) ID() string {
+	return vote.PollVoteID.Hex()
+}
+
+/******************************************
+ * Other Methods
+ ******************************************/
+
+// ChoiceIndexes returns the position of each choice within the Poll's options,
+// skipping choices that the Poll no longer offers.
+func (vote PollVote) ChoiceIndexes(poll Poll) []int {
+
+	result := make([]int, 0, len(vote.Choices))
+
+	for _, choice := range vote.Choices {
+		if index := poll.OptionIndex(choice); index >= 0 {
+			result = append(result, index)
+		}
+	}
+
+	return result
+}
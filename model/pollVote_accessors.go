@@ -0,0 +1,81 @@
+package model
+
+import (
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PollVoteSchema returns a validating schema for PollVote objects
+func PollVoteSchema() schema.Element {
+
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"pollVoteId": schema.String{Required: true, Format: "objectId"},
+			"streamId":   schema.String{Format: "objectId"},
+			"userId":     schema.String{Format: "objectId"},
+			"pollUrl":    schema.String{Required: true, Format: "url"},
+			"voterUrl":   schema.String{Required: true, Format: "url"},
+			"choices":    schema.Array{Items: schema.String{MaxLength: 256}, MaxLength: PollMaxOptions},
+		},
+	}
+}
+
+func (vote *PollVote) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "pollUrl":
+		return &vote.PollURL, true
+
+	case "voterUrl":
+		return &vote.VoterURL, true
+
+	case "choices":
+		return &vote.Choices, true
+	}
+
+	return nil, false
+}
+
+func (vote PollVote) GetStringOK(name string) (string, bool) {
+
+	switch name {
+
+	case "pollVoteId":
+		return vote.PollVoteID.Hex(), true
+
+	case "streamId":
+		return vote.StreamID.Hex(), true
+
+	case "userId":
+		return vote.UserID.Hex(), true
+	}
+
+	return "", false
+}
+
+func (vote *PollVote) SetString(name string, value string) bool {
+
+	switch name {
+
+	case "pollVoteId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			vote.PollVoteID = objectID
+			return true
+		}
+
+	case "streamId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			vote.StreamID = objectID
+			return true
+		}
+
+	case "userId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			vote.UserID = objectID
+			return true
+		}
+	}
+
+	return false
+}
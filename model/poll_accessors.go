@@ -0,0 +1,72 @@
+package model
+
+import (
+	"github.com/benpate/rosetta/null"
+	"github.com/benpate/rosetta/schema"
+)
+
+// PollSchema returns a validating schema for Poll objects
+func PollSchema() schema.Element {
+
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"options":    schema.Array{Items: PollOptionSchema(), MaxLength: PollMaxOptions},
+			"multiple":   schema.Boolean{},
+			"endDate":    schema.Integer{BitSize: 64},
+			"closedDate": schema.Integer{BitSize: 64},
+			"voterCount": schema.Integer{Minimum: null.NewInt64(0)},
+		},
+	}
+}
+
+// PollOptionSchema returns a validating schema for PollOption objects
+func PollOptionSchema() schema.Element {
+
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"name":      schema.String{Format: "text", Required: true, MaxLength: 256},
+			"voteCount": schema.Integer{Minimum: null.NewInt64(0)},
+		},
+	}
+}
+
+/********************************
+ * Getter/Setter Interfaces
+ ********************************/
+
+func (poll *Poll) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "options":
+		return &poll.Options, true
+
+	case "multiple":
+		return &poll.Multiple, true
+
+	case "endDate":
+		return &poll.EndDate, true
+
+	case "closedDate":
+		return &poll.ClosedDate, true
+
+	case "voterCount":
+		return &poll.VoterCount, true
+	}
+
+	return nil, false
+}
+
+func (option *PollOption) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "name":
+		return &option.Name, true
+
+	case "voteCount":
+		return &option.VoteCount, true
+	}
+
+	return nil, false
+}
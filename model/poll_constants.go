@@ -0,0 +1,22 @@
+package model
+
+// PollObjectType is the ActivityStreams type used to publish a Stream that contains a Poll
+const PollObjectType = "Question"
+
+// PollPropertyOneOf is the JSON-LD property that lists the options of a single-choice Poll
+const PollPropertyOneOf = "oneOf"
+
+// PollPropertyAnyOf is the JSON-LD property that lists the options of a multiple-choice Poll
+const PollPropertyAnyOf = "anyOf"
+
+// PollPropertyEndTime is the JSON-LD property that holds the date when voting ends
+const PollPropertyEndTime = "endTime"
+
+// PollPropertyClosed is the JSON-LD property that holds the date when a Poll was closed
+const PollPropertyClosed = "closed"
+
+// PollPropertyVotersCount is the (Mastodon-specific) JSON-LD property that counts the people who voted
+const PollPropertyVotersCount = "votersCount"
+
+// PollMaxOptions is the largest number of options that a single Poll can offer
+const PollMaxOptions = 10
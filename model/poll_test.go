@@ -0,0 +1,136 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPollSchema(t *testing.T) {
+
+	s := schema.New(PollSchema())
+	poll := NewPoll()
+
+	tests := []tableTestItem{
+		{"multiple", true, nil},
+		{"endDate", int64(1700000000), nil},
+		{"closedDate", int64(1700000100), nil},
+		{"voterCount", 7, nil},
+	}
+
+	tableTest_Schema(t, &s, &poll, tests)
+}
+
+func TestPollVoteSchema(t *testing.T) {
+
+	s := schema.New(PollVoteSchema())
+	vote := NewPollVote()
+
+	tests := []tableTestItem{
+		{"pollVoteId", "000000000000000000000001", nil},
+		{"streamId", "000000000000000000000002", nil},
+		{"userId", "000000000000000000000003", nil},
+		{"pollUrl", "https://example.com/polls/1", nil},
+		{"voterUrl", "https://example.com/@voter", nil},
+		{"choices.0", "Yes", nil},
+	}
+
+	tableTest_Schema(t, &s, &vote, tests)
+}
+
+func TestPoll_OptionNames(t *testing.T) {
+
+	poll := NewPollWithOptions("Red", "", "Green", "Blue")
+	require.Equal(t, 3, len(poll.Options))
+
+	names, ok := poll.OptionNames([]int{1})
+	require.True(t, ok)
+	require.Equal(t, []string{"Green"}, []string(names))
+
+	_, ok = poll.OptionNames([]int{0, 1})
+	require.False(t, ok)
+
+	_, ok = poll.OptionNames([]int{3})
+	require.False(t, ok)
+
+	_, ok = poll.OptionNames([]int{})
+	require.False(t, ok)
+
+	poll.Multiple = true
+	names, ok = poll.OptionNames([]int{2, 0, 2})
+	require.True(t, ok)
+	require.Equal(t, []string{"Blue", "Red"}, []string(names))
+}
+
+func TestPoll_Tally(t *testing.T) {
+
+	poll := NewPollWithOptions("Yes", "No")
+	poll.Options[0].VoteCount = 99
+
+	votes := []PollVote{
+		{Choices: []string{"Yes"}},
+		{Choices: []string{"No"}},
+		{Choices: []string{"Yes", "Maybe"}},
+	}
+
+	poll.Tally(votes)
+	require.Equal(t, 2, poll.Options[0].VoteCount)
+	require.Equal(t, 1, poll.Options[1].VoteCount)
+	require.Equal(t, 3, poll.VoteCount())
+	require.Equal(t, 3, poll.VoterCount)
+}
+
+func TestPoll_IsClosed(t *testing.T) {
+
+	poll := NewPollWithOptions("Yes", "No")
+	require.True(t, poll.IsOpen(1000))
+
+	poll.EndDate = 1000
+	require.True(t, poll.IsOpen(999))
+	require.True(t, poll.IsClosed(1000))
+
+	poll.EndDate = 0
+	poll.ClosedDate = 500
+	require.True(t, poll.IsClosed(1))
+
+	require.False(t, NewPoll().IsOpen(1))
+}
+
+func TestPoll_MarkScheduled(t *testing.T) {
+
+	poll := NewPollWithOptions("Yes", "No")
+
+	// A Poll without an end date never needs a close task
+	require.False(t, poll.MarkScheduled())
+
+	// Setting an end date schedules the task once
+	poll.EndDate = 1000
+	require.True(t, poll.MarkScheduled())
+	require.False(t, poll.MarkScheduled())
+
+	// Changing the end date reschedules it
+	poll.EndDate = 2000
+	require.True(t, poll.MarkScheduled())
+	require.Equal(t, int64(2000), poll.ScheduledDate)
+
+	// Removing the end date also reschedules, so the old task is removed
+	poll.EndDate = 0
+	require.True(t, poll.MarkScheduled())
+}
+
+func TestPoll_ApplyJSONLD(t *testing.T) {
+
+	poll := NewPollWithOptions("Yes", "No")
+	poll.EndDate = 1700000000
+
+	result := mapof.Any{"type": "Note"}
+	poll.ApplyJSONLD(result)
+
+	require.Equal(t, PollObjectType, result["type"])
+	require.Contains(t, result, PollPropertyOneOf)
+	require.NotContains(t, result, PollPropertyAnyOf)
+	require.Contains(t, result, PollPropertyEndTime)
+	require.NotContains(t, result, PollPropertyClosed)
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"github.com/benpate/data/journal"
+	"github.com/benpate/rosetta/mapof"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -160,3 +161,33 @@ func (privilege *Privilege) SetCircleInfo(circle *Circle) bool {
 
 	return changed
 }
+
+/******************************************
+ * Webhook Interface
+ ******************************************/
+
+// GetWebhookData returns the data for this Privilege that will be sent to a webhook
+func (privilege Privilege) GetWebhookData() mapof.Any {
+	return mapof.Any{
+		"privilegeId":       privilege.PrivilegeID.Hex(),
+		"userId":            privilege.UserID.Hex(),
+		"identityId":        privilege.IdentityID.Hex(),
+		"circleId":          privilege.CircleID.Hex(),
+		"merchantAccountId": privilege.MerchantAccountID.Hex(),
+		"productId":         privilege.ProductID.Hex(),
+		"name":              privilege.Name,
+		"priceDescription":  privilege.PriceDescription,
+		"recurringType":     privilege.RecurringType,
+		"identifierType":    privilege.IdentifierType,
+		"identifierValue":   privilege.IdentifierValue,
+		"remotePurchaseId":  privilege.RemotePurchaseID,
+		"isPurchase":        privilege.IsPurchase(),
+		"createDate":        privilege.CreateDate,
+		"deleteDate":        privilege.DeleteDate,
+	}
+}
+
+// WebhookUserID returns the User who owns the Circle or MerchantAccount that granted this Privilege
+func (privilege Privilege) WebhookUserID() primitive.ObjectID {
+	return privilege.UserID
+}
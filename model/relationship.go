@@ -0,0 +1,46 @@
+package model
+
+import "github.com/benpate/toot/object"
+
+// Relationship summarizes how a User is connected to another account.  It is
+// calculated from the User's Following, Follower, and Rule records, and is not
+// stored in the database.
+type Relationship struct {
+	ActorID        string // ActivityPub URL of the other account
+	Following      bool   // TRUE if the User follows the account
+	Requested      bool   // TRUE if the User has asked to follow the account, but has not been accepted yet
+	FollowedBy     bool   // TRUE if the account follows the User
+	RequestedBy    bool   // TRUE if the account has asked to follow the User, but has not been approved yet
+	Blocking       bool   // TRUE if the User blocks the account
+	Muting         bool   // TRUE if the User mutes the account
+	DomainBlocking bool   // TRUE if the User blocks the account's whole domain
+	Endorsed       bool   // TRUE if the User features the account on their profile
+	Note           string // Private note that the User has written about the account
+}
+
+// NewRelationship returns a fully initialized Relationship object
+func NewRelationship(actorID string) Relationship {
+	return Relationship{
+		ActorID: actorID,
+	}
+}
+
+/******************************************
+ * Mastodon API
+ ******************************************/
+
+// Toot returns this Relationship as a Mastodon-API Relationship object
+func (relationship Relationship) Toot() object.Relationship {
+	return object.Relationship{
+		ID:             relationship.ActorID,
+		Following:      relationship.Following,
+		Requested:      relationship.Requested,
+		FollowedBy:     relationship.FollowedBy,
+		RequestedBy:    relationship.RequestedBy,
+		Blocking:       relationship.Blocking,
+		Muting:         relationship.Muting,
+		DomainBlocking: relationship.DomainBlocking,
+		Endorsed:       relationship.Endorsed,
+		Note:           relationship.Note,
+	}
+}
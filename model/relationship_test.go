@@ -0,0 +1,27 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelationship_Toot(t *testing.T) {
+
+	relationship := NewRelationship("https://example.com/@friend")
+	relationship.Following = true
+	relationship.FollowedBy = true
+	relationship.DomainBlocking = true
+	relationship.Note = "NOTE"
+
+	result := relationship.Toot()
+	require.Equal(t, "https://example.com/@friend", result.ID)
+	require.True(t, result.Following)
+	require.True(t, result.FollowedBy)
+	require.False(t, result.Requested)
+	require.False(t, result.RequestedBy)
+	require.False(t, result.Blocking)
+	require.False(t, result.Muting)
+	require.True(t, result.DomainBlocking)
+	require.Equal(t, "NOTE", result.Note)
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"github.com/benpate/data/journal"
+	"github.com/benpate/hannibal/vocab"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Relay is an ActivityPub relay that the domain's search actor (@search) subscribes to.  Relays
+// forward public posts from every server that subscribes to them, which gives small domains a
+// federated search index (and hashtag feeds) without needing to follow everyone individually.
+type Relay struct {
+	RelayID         primitive.ObjectID `bson:"_id"`                 // Unique identifier of this Relay
+	Label           string             `bson:"label"`               // Human-friendly name of this Relay
+	URL             string             `bson:"url"`                 // Address that the administrator subscribed to (the relay's actor, or its inbox)
+	Type            string             `bson:"type"`                // Protocol that this relay speaks (LITEPUB or MASTODON)
+	ActorURL        string             `bson:"actorUrl"`            // ActivityPub actor that forwards activities for this relay.  Mastodon-style relays are identified when they Accept.
+	InboxURL        string             `bson:"inboxUrl"`            // Inbox that receives our Follow (and our published posts)
+	Hostname        string             `bson:"hostname"`            // Hostname of the relay server, which identifies the activities that it forwards
+	StateID         string             `bson:"stateId"`             // Subscription state (PENDING, ACTIVE, or REJECTED)
+	Publish         bool               `bson:"publish"`             // If TRUE, public posts from this domain are sent to the relay to re-publish
+	ReceiveDate     int64              `bson:"receiveDate"`         // Unix epoch seconds when this relay last forwarded a document
+	LastError       string             `bson:"lastError,omitempty"` // Human-friendly description of the last problem subscribing to this relay
+	journal.Journal `json:"-" bson:",inline"`
+}
+
+// NewRelay returns a fully initialized Relay object
+func NewRelay() Relay {
+	return Relay{
+		RelayID: primitive.NewObjectID(),
+		StateID: RelayStatePending,
+	}
+}
+
+func RelayFields() []string {
+	return []string{"_id", "label", "url", "type", "actorUrl", "inboxUrl", "hostname", "stateId", "publish", "receiveDate", "lastError"}
+}
+
+func (relay Relay) Fields() []string {
+	return RelayFields()
+}
+
+// ID returns the unique identifier for this Relay, and is required to implement the data.Object interface
+func (relay Relay) ID() string {
+	return relay.RelayID.Hex()
+}
+
+// IsActive returns TRUE if the relay has accepted our subscription
+func (relay Relay) IsActive() bool {
+	return relay.StateID == RelayStateActive
+}
+
+// IsPending returns TRUE if the relay has not yet responded to our subscription
+func (relay Relay) IsPending() bool {
+	return relay.StateID == RelayStatePending
+}
+
+// IsRejected returns TRUE if the relay has declined our subscription
+func (relay Relay) IsRejected() bool {
+	return relay.StateID == RelayStateRejected
+}
+
+// IsPublishing returns TRUE if our public posts should be sent to this relay
+func (relay Relay) IsPublishing() bool {
+	return relay.Publish && relay.IsActive()
+}
+
+// FollowObject returns the "object" of the Follow that subscribes to this relay.  LitePub relays
+// are followed like any other actor, while Mastodon-style relays expect a Follow of the Public
+// collection, delivered to their inbox.
+func (relay Relay) FollowObject() string {
+
+	if relay.Type == RelayTypeLitePub {
+		return relay.ActorURL
+	}
+
+	return vocab.NamespacePublic
+}
+
+/******************************************
+ * AccessLister Interface
+ ******************************************/
+
+// State returns the current state of this Relay.
+// It is part of the AccessLister interface
+func (relay *Relay) State() string {
+	return "default"
+}
+
+// IsAuthor returns TRUE if the provided UserID the author of this Relay
+// It is part of the AccessLister interface
+func (relay *Relay) IsAuthor(authorID primitive.ObjectID) bool {
+	return false
+}
+
+// IsMyself returns TRUE if this object directly represents the provided UserID
+// It is part of the AccessLister interface
+func (relay *Relay) IsMyself(userID primitive.ObjectID) bool {
+	return false
+}
+
+// RolesToGroupIDs returns a slice of Group IDs that grant access to any of the requested roles.
+// It is part of the AccessLister interface
+func (relay *Relay) RolesToGroupIDs(roleIDs ...string) Permissions {
+	return defaultRolesToGroupIDs(primitive.NilObjectID, roleIDs...)
+}
+
+// RolesToPrivilegeIDs returns a slice of Privileges that grant access to any of the requested roles.
+// It is part of the AccessLister interface
+func (relay *Relay) RolesToPrivilegeIDs(roleIDs ...string) Permissions {
+	return NewPermissions()
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func RelaySchema() schema.Element {
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"relayId":  schema.String{Format: "objectId"},
+			"label":    schema.String{Format: "text", MaxLength: 64, Required: true},
+			"url":      schema.String{Format: "url", Required: true},
+			"type":     schema.String{Enum: []string{RelayTypeLitePub, RelayTypeMastodon}},
+			"actorUrl": schema.String{Format: "url"},
+			"inboxUrl": schema.String{Format: "url"},
+			"stateId":  schema.String{Enum: []string{RelayStatePending, RelayStateActive, RelayStateRejected}},
+			"publish":  schema.Boolean{},
+		},
+	}
+}
+
+func (relay *Relay) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "label":
+		return &relay.Label, true
+
+	case "url":
+		return &relay.URL, true
+
+	case "type":
+		return &relay.Type, true
+
+	case "actorUrl":
+		return &relay.ActorURL, true
+
+	case "inboxUrl":
+		return &relay.InboxURL, true
+
+	case "stateId":
+		return &relay.StateID, true
+
+	case "publish":
+		return &relay.Publish, true
+	}
+
+	return nil, false
+}
+
+func (relay Relay) GetStringOK(name string) (string, bool) {
+
+	switch name {
+
+	case "relayId":
+		return relay.RelayID.Hex(), true
+	}
+
+	return "", false
+}
+
+func (relay *Relay) SetString(name string, value string) bool {
+
+	switch name {
+
+	case "relayId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			relay.RelayID = objectID
+			return true
+		}
+	}
+
+	return false
+}
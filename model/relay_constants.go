@@ -0,0 +1,18 @@
+package model
+
+// RelayStatePending means that our Follow has been sent, but the relay has not responded yet
+const RelayStatePending = "PENDING"
+
+// RelayStateActive means that the relay has accepted our Follow, and is forwarding posts to us
+const RelayStateActive = "ACTIVE"
+
+// RelayStateRejected means that the relay has declined our Follow
+const RelayStateRejected = "REJECTED"
+
+// RelayTypeLitePub identifies relays (like Pleroma's) that are followed like any other actor,
+// and that forward posts as "Announce" activities from the relay's own actor
+const RelayTypeLitePub = "LITEPUB"
+
+// RelayTypeMastodon identifies relays that are subscribed to by sending a Follow of the Public
+// collection to the relay's inbox, and that forward the original "Create" activities
+const RelayTypeMastodon = "MASTODON"
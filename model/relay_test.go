@@ -0,0 +1,58 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelaySchema(t *testing.T) {
+
+	s := schema.New(RelaySchema())
+	relay := NewRelay()
+
+	tests := []tableTestItem{
+		{"relayId", "000000000000000000000001", nil},
+		{"label", "LABEL", nil},
+		{"url", "https://relay.example/actor", nil},
+		{"type", RelayTypeLitePub, nil},
+		{"actorUrl", "https://relay.example/actor", nil},
+		{"inboxUrl", "https://relay.example/inbox", nil},
+		{"stateId", RelayStateActive, nil},
+		{"publish", true, nil},
+	}
+
+	tableTest_Schema(t, &s, &relay, tests)
+}
+
+func TestRelay_FollowObject(t *testing.T) {
+
+	relay := NewRelay()
+	relay.ActorURL = "https://relay.example/actor"
+
+	// Mastodon-style relays are subscribed to by following the Public collection
+	relay.Type = RelayTypeMastodon
+	require.Equal(t, vocab.NamespacePublic, relay.FollowObject())
+
+	// LitePub relays are followed like any other actor
+	relay.Type = RelayTypeLitePub
+	require.Equal(t, "https://relay.example/actor", relay.FollowObject())
+}
+
+func TestRelay_IsPublishing(t *testing.T) {
+
+	relay := NewRelay()
+	require.True(t, relay.IsPending())
+
+	// Posts are not sent until the relay accepts our subscription
+	relay.Publish = true
+	require.False(t, relay.IsPublishing())
+
+	relay.StateID = RelayStateActive
+	require.True(t, relay.IsPublishing())
+
+	relay.Publish = false
+	require.False(t, relay.IsPublishing())
+}
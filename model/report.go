@@ -0,0 +1,165 @@
+package model
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/benpate/data/journal"
+	"github.com/benpate/rosetta/sliceof"
+	"github.com/benpate/toot/object"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Report is a moderation report about an actor (and optionally some of their content).
+// Reports are filed by local Users, or received from other servers as ActivityPub "Flag"
+// activities, and are triaged by the Domain Owner in the admin console.
+type Report struct {
+	ReportID        primitive.ObjectID `bson:"_id"`            // Unique identifier of this Report
+	Origin          string             `bson:"origin"`         // Where this Report came from (LOCAL, REMOTE)
+	Status          string             `bson:"status"`         // Current moderation status (OPEN, RESOLVED, DISMISSED)
+	ReporterID      primitive.ObjectID `bson:"reporterId"`     // Unique identifier of the local User who filed this Report.  Zero for REMOTE reports.
+	ReporterURL     string             `bson:"reporterUrl"`    // ActivityPub URL of the actor who filed this Report
+	TargetURL       string             `bson:"targetUrl"`      // ActivityPub URL of the actor being reported
+	TargetUserID    primitive.ObjectID `bson:"targetUserId"`   // Unique identifier of the reported User, if they are on this server
+	ObjectURLs      sliceof.String     `bson:"objectUrls"`     // URLs of the specific documents being reported
+	Category        string             `bson:"category"`       // Reason for this Report (spam, legal, violation, other)
+	Comment         string             `bson:"comment"`        // Additional information from the reporter
+	Forward         bool               `bson:"forward"`        // If TRUE, then a copy of this Report is sent to the reported actor's server
+	ForwardDate     int64              `bson:"forwardDate"`    // Unix epoch SECONDS when this Report was forwarded (0 = not forwarded)
+	ActivityID      string             `bson:"activityId"`     // ID of the "Flag" activity that was received (REMOTE) or sent (LOCAL)
+	Resolution      string             `bson:"resolution"`     // Action taken when this Report was resolved (NONE, BLOCK-ACTOR, BLOCK-DOMAIN)
+	ResolutionNote  string             `bson:"resolutionNote"` // Private note from the moderator who closed this Report
+	ResolvedBy      primitive.ObjectID `bson:"resolvedBy"`     // Unique identifier of the User who closed this Report
+	ResolveDate     int64              `bson:"resolveDate"`    // Unix epoch SECONDS when this Report was closed (0 = still open)
+	RuleID          primitive.ObjectID `bson:"ruleId"`         // Unique identifier of the Rule created when this Report was resolved
+	journal.Journal `json:"-" bson:",inline"`
+}
+
+// NewReport returns a fully initialized Report object
+func NewReport() Report {
+	return Report{
+		ReportID:   primitive.NewObjectID(),
+		Origin:     ReportOriginLocal,
+		Status:     ReportStatusOpen,
+		ObjectURLs: sliceof.NewString(),
+		Category:   ReportCategoryOther,
+		Resolution: ReportResolutionNone,
+	}
+}
+
+func ReportFields() []string {
+	return []string{"_id", "origin", "status", "reporterId", "reporterUrl", "targetUrl", "targetUserId", "objectUrls", "category", "comment", "forwardDate", "resolution", "resolveDate", "createDate"}
+}
+
+func (report Report) Fields() []string {
+	return ReportFields()
+}
+
+// ID returns the unique identifier for this Report, and is required to implement the data.Object interface
+func (report Report) ID() string {
+	return report.ReportID.Hex()
+}
+
+// IsOpen returns TRUE if this Report is still waiting for a moderator
+func (report Report) IsOpen() bool {
+	return report.Status == ReportStatusOpen
+}
+
+// IsLocal returns TRUE if this Report was filed by a User on this server
+func (report Report) IsLocal() bool {
+	return report.Origin == ReportOriginLocal
+}
+
+// IsRemote returns TRUE if this Report was received from another server
+func (report Report) IsRemote() bool {
+	return report.Origin == ReportOriginRemote
+}
+
+// IsTargetLocal returns TRUE if the reported actor is a User on this server
+func (report Report) IsTargetLocal() bool {
+	return !report.TargetUserID.IsZero()
+}
+
+// IsForwarded returns TRUE if a copy of this Report has been sent to the reported actor's server
+func (report Report) IsForwarded() bool {
+	return report.ForwardDate > 0
+}
+
+// ShouldForward returns TRUE if this Report still needs to be sent to the reported actor's server.
+// Only local Reports about remote actors are forwarded, and only once.
+func (report Report) ShouldForward() bool {
+	return report.Forward &&
+		report.IsLocal() &&
+		!report.IsTargetLocal() &&
+		!report.IsForwarded() &&
+		(report.TargetURL != "")
+}
+
+// TargetHostname returns the hostname of the reported actor
+func (report Report) TargetHostname() string {
+
+	targetURL, err := url.Parse(report.TargetURL)
+
+	if err != nil {
+		return ""
+	}
+
+	return targetURL.Hostname()
+}
+
+/******************************************
+ * AccessLister Interface
+ ******************************************/
+
+// State returns the current state of this Report.
+// It is part of the AccessLister interface
+func (report *Report) State() string {
+	return "default"
+}
+
+// IsAuthor returns TRUE if the provided UserID the author of this Report
+// It is part of the AccessLister interface
+func (report *Report) IsAuthor(authorID primitive.ObjectID) bool {
+	return false
+}
+
+// IsMyself returns TRUE if the provided UserID filed this Report
+// It is part of the AccessLister interface
+func (report *Report) IsMyself(userID primitive.ObjectID) bool {
+	return !userID.IsZero() && userID == report.ReporterID
+}
+
+// RolesToGroupIDs returns a slice of Group IDs that grant access to any of the requested roles.
+// It is part of the AccessLister interface
+func (report *Report) RolesToGroupIDs(roleIDs ...string) Permissions {
+	return defaultRolesToGroupIDs(primitive.NilObjectID, roleIDs...)
+}
+
+// RolesToPrivilegeIDs returns a slice of Privileges that grant access to any of the requested roles.
+// It is part of the AccessLister interface
+func (report *Report) RolesToPrivilegeIDs(roleIDs ...string) Permissions {
+	return NewPermissions()
+}
+
+/******************************************
+ * Mastodon API Methods
+ ******************************************/
+
+// Toot returns this Report as a Mastodon Report entity
+// https://docs.joinmastodon.org/entities/Report/
+func (report Report) Toot() object.Report {
+	return object.Report{
+		ID:          report.ReportID.Hex(),
+		ActionTaken: report.Status == ReportStatusResolved,
+		Category:    report.Category,
+		Comment:     report.Comment,
+		Forwarded:   report.IsForwarded(),
+		CreatedAt:   time.UnixMilli(report.CreateDate).UTC().Format(time.RFC3339), // CreateDate is milliseconds (journal UnixMilli)
+		StatusIDs:   report.ObjectURLs,
+		TargetAccount: object.Account{
+			ID:   report.TargetURL,
+			Acct: report.TargetURL,
+			URL:  report.TargetURL,
+		},
+	}
+}
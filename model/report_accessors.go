@@ -0,0 +1,107 @@
+package model
+
+import (
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func ReportSchema() schema.Element {
+	return schema.Object{
+		Properties: schema.ElementMap{
+			"reportId":       schema.String{Format: "objectId"},
+			"origin":         schema.String{Enum: []string{ReportOriginLocal, ReportOriginRemote}, Required: true},
+			"status":         schema.String{Enum: []string{ReportStatusOpen, ReportStatusResolved, ReportStatusDismissed}, Required: true},
+			"reporterId":     schema.String{Format: "objectId"},
+			"reporterUrl":    schema.String{Format: "url"},
+			"targetUrl":      schema.String{Format: "url", Required: true},
+			"targetUserId":   schema.String{Format: "objectId"},
+			"objectUrls":     schema.Array{Items: schema.String{Format: "url"}},
+			"category":       schema.String{Enum: []string{ReportCategorySpam, ReportCategoryLegal, ReportCategoryViolation, ReportCategoryOther}, Required: true},
+			"comment":        schema.String{Format: "text", MaxLength: ReportCommentMaxLength},
+			"forward":        schema.Boolean{},
+			"resolution":     schema.String{Enum: []string{ReportResolutionNone, ReportResolutionBlockActor, ReportResolutionBlockDomain}},
+			"resolutionNote": schema.String{Format: "text", MaxLength: 2048},
+		},
+	}
+}
+
+func (report *Report) GetPointer(name string) (any, bool) {
+
+	switch name {
+
+	case "origin":
+		return &report.Origin, true
+
+	case "status":
+		return &report.Status, true
+
+	case "reporterUrl":
+		return &report.ReporterURL, true
+
+	case "targetUrl":
+		return &report.TargetURL, true
+
+	case "objectUrls":
+		return &report.ObjectURLs, true
+
+	case "category":
+		return &report.Category, true
+
+	case "comment":
+		return &report.Comment, true
+
+	case "forward":
+		return &report.Forward, true
+
+	case "resolution":
+		return &report.Resolution, true
+
+	case "resolutionNote":
+		return &report.ResolutionNote, true
+	}
+
+	return nil, false
+}
+
+func (report Report) GetStringOK(name string) (string, bool) {
+
+	switch name {
+
+	case "reportId":
+		return report.ReportID.Hex(), true
+
+	case "reporterId":
+		return report.ReporterID.Hex(), true
+
+	case "targetUserId":
+		return report.TargetUserID.Hex(), true
+	}
+
+	return "", false
+}
+
+func (report *Report) SetString(name string, value string) bool {
+
+	switch name {
+
+	case "reportId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			report.ReportID = objectID
+			return true
+		}
+
+	case "reporterId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			report.ReporterID = objectID
+			return true
+		}
+
+	case "targetUserId":
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			report.TargetUserID = objectID
+			return true
+		}
+	}
+
+	return false
+}
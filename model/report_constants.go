@@ -0,0 +1,40 @@
+package model
+
+// ReportOriginLocal describes a Report that was filed by a User on this server
+const ReportOriginLocal = "LOCAL"
+
+// ReportOriginRemote describes a Report that was sent to this server as an ActivityPub "Flag" activity
+const ReportOriginRemote = "REMOTE"
+
+// ReportStatusOpen describes a Report that is waiting for a moderator to review it
+const ReportStatusOpen = "OPEN"
+
+// ReportStatusResolved describes a Report that a moderator has acted upon
+const ReportStatusResolved = "RESOLVED"
+
+// ReportStatusDismissed describes a Report that a moderator has closed without taking action
+const ReportStatusDismissed = "DISMISSED"
+
+// ReportCategorySpam describes a Report about spam or other unwanted advertising
+const ReportCategorySpam = "spam"
+
+// ReportCategoryLegal describes a Report about content that is illegal
+const ReportCategoryLegal = "legal"
+
+// ReportCategoryViolation describes a Report about content that violates the server rules
+const ReportCategoryViolation = "violation"
+
+// ReportCategoryOther describes a Report that does not fit any other category
+const ReportCategoryOther = "other"
+
+// ReportResolutionNone means that resolving the Report does not create any Rules
+const ReportResolutionNone = "NONE"
+
+// ReportResolutionBlockActor means that resolving the Report blocks the reported actor server-wide
+const ReportResolutionBlockActor = "BLOCK-ACTOR"
+
+// ReportResolutionBlockDomain means that resolving the Report blocks the reported actor's domain server-wide
+const ReportResolutionBlockDomain = "BLOCK-DOMAIN"
+
+// ReportCommentMaxLength is the longest comment that can be included in a Report (same as Mastodon)
+const ReportCommentMaxLength = 1000
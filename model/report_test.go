@@ -0,0 +1,82 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestReportSchema(t *testing.T) {
+
+	s := schema.New(ReportSchema())
+	report := NewReport()
+
+	tests := []tableTestItem{
+		{"reportId", "000000000000000000000001", nil},
+		{"origin", "REMOTE", nil},
+		{"status", "RESOLVED", nil},
+		{"reporterId", "000000000000000000000002", nil},
+		{"reporterUrl", "https://example.com/@alice", nil},
+		{"targetUrl", "https://example.social/users/bob", nil},
+		{"targetUserId", "000000000000000000000003", nil},
+		{"objectUrls.0", "https://example.social/users/bob/statuses/1", nil},
+		{"category", "spam", nil},
+		{"comment", "REPORT-COMMENT", nil},
+		{"forward", true, nil},
+		{"resolution", "BLOCK-DOMAIN", nil},
+		{"resolutionNote", "RESOLUTION-NOTE", nil},
+	}
+
+	tableTest_Schema(t, &s, &report, tests)
+}
+
+func TestReport_ShouldForward(t *testing.T) {
+
+	report := NewReport()
+	report.TargetURL = "https://example.social/users/bob"
+
+	// Reports are not forwarded unless requested
+	require.False(t, report.ShouldForward())
+
+	report.Forward = true
+	require.True(t, report.ShouldForward())
+
+	// Reports are only forwarded once
+	report.ForwardDate = 1700000000
+	require.False(t, report.ShouldForward())
+
+	// Reports about local Users stay on this server
+	report.ForwardDate = 0
+	report.TargetUserID = primitive.NewObjectID()
+	require.False(t, report.ShouldForward())
+
+	// Reports from other servers are never sent back out
+	report.TargetUserID = primitive.NilObjectID
+	report.Origin = ReportOriginRemote
+	require.False(t, report.ShouldForward())
+}
+
+func TestReport_TargetHostname(t *testing.T) {
+
+	report := NewReport()
+	report.TargetURL = "https://Example.social:8443/users/bob"
+	require.Equal(t, "Example.social", report.TargetHostname())
+
+	report.TargetURL = ""
+	require.Equal(t, "", report.TargetHostname())
+}
+
+func TestReport_IsMyself(t *testing.T) {
+
+	userID := primitive.NewObjectID()
+
+	report := NewReport()
+	require.False(t, report.IsMyself(userID))
+	require.False(t, report.IsMyself(primitive.NilObjectID))
+
+	report.ReporterID = userID
+	require.True(t, report.IsMyself(userID))
+	require.False(t, report.IsMyself(primitive.NewObjectID()))
+}
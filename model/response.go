@@ -181,6 +181,12 @@ func (response *Response) IsAuthor(authorID primitive.ObjectID) bool {
 	return false
 }
 
+// AuthorID returns the UserID of the User who made this Response
+// It is part of the AuthorIDGetter interface
+func (response *Response) AuthorID() primitive.ObjectID {
+	return response.UserID
+}
+
 // IsMyself returns TRUE if this object directly represents the provided UserID
 // It is part of the AccessLister interface
 func (response *Response) IsMyself(userID primitive.ObjectID) bool {
@@ -213,3 +219,28 @@ func (response Response) Toot() object.Status {
 		},
 	}
 }
+
+/******************************************
+ * Webhook Interface
+ ******************************************/
+
+// GetWebhookData returns the data for this Response that will be sent to a webhook
+func (response Response) GetWebhookData() mapof.Any {
+	return mapof.Any{
+		"responseId": response.ResponseID.Hex(),
+		"userId":     response.UserID.Hex(),
+		"url":        response.ActivityPubURL(),
+		"actor":      response.Actor,
+		"object":     response.Object,
+		"type":       response.Type,
+		"summary":    response.Summary,
+		"content":    response.Content,
+		"createDate": response.CreateDate,
+		"deleteDate": response.DeleteDate,
+	}
+}
+
+// WebhookUserID returns the User who made this Response
+func (response Response) WebhookUserID() primitive.ObjectID {
+	return response.UserID
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"github.com/benpate/data/journal"
+	"github.com/benpate/rosetta/mapof"
 	"github.com/benpate/toot/object"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -12,12 +13,13 @@ type Rule struct {
 	UserID          primitive.ObjectID `bson:"userId"`                    // Unique identifier of the User who owns this Rule
 	FollowingID     primitive.ObjectID `bson:"followingId"`               // Unique identifier of the Following record that created this Rule.  If Zero, then this rule was created by the user.
 	FollowingLabel  string             `bson:"followingLabel"`            // Label of the Following record that created this Rule.
-	Type            string             `bson:"type"`                      // Type of Rule (e.g. "ACTOR", "DOMAIN", "TAG")
+	Type            string             `bson:"type"`                      // Type of Rule (e.g. "ACTOR", "DOMAIN", "TAG", "CONTENT")
 	Action          string             `bson:"action"`                    // Action to take when this rule is triggered (e.g. "BLOCK", "MUTE", "LABEL")
 	Label           string             `bson:"label"`                     // Human-friendly label to add to messages
 	Trigger         string             `bson:"trigger"`                   // Parameter for this rule type)
 	MatchKey        string             `bson:"matchKey"`                  // Derived "<TYPE>:<normalized trigger>" -- the indexed key that a matching document also produces. Computed in Save from Type+Trigger; NEVER set from a form (see RuleSchema).
 	RemoteID        string             `bson:"remoteId,omitempty"`        // Canonical id (URL) of the remote moderation entry this Rule was imported from (P7-3). "" = created locally. Server-set only -- NEVER in RuleSchema.
+	BlocklistID     primitive.ObjectID `bson:"blocklistId,omitempty"`     // Unique identifier of the Blocklist subscription that manages this Rule.  Server-set only -- NEVER in RuleSchema.
 	ReasonCode      string             `bson:"reasonCode"`                // Optional code to identify the reason for this rule (e.g. "SPAM", "NSFW", "SENSITIVE")
 	Summary         string             `bson:"summary"`                   // Optional comment describing why this rule exists
 	IsPublic        bool               `bson:"isPublic"`                  // If TRUE, this record is visible publicly
@@ -138,3 +140,34 @@ func (rule Rule) OriginRemote() bool {
 func (rule Rule) OriginUser() bool {
 	return rule.FollowingID.IsZero()
 }
+
+/******************************************
+ * Webhook Interface
+ ******************************************/
+
+// GetWebhookData returns the data for this Rule that will be sent to a webhook
+func (rule Rule) GetWebhookData() mapof.Any {
+	return mapof.Any{
+		"ruleId":      rule.RuleID.Hex(),
+		"userId":      rule.UserID.Hex(),
+		"followingId": rule.FollowingID.Hex(),
+		"origin":      rule.Origin(),
+		"type":        rule.Type,
+		"action":      rule.Action,
+		"label":       rule.Label,
+		"trigger":     rule.Trigger,
+		"reasonCode":  rule.ReasonCode,
+		"summary":     rule.Summary,
+		"isPublic":    rule.IsPublic,
+		"expireDate":  rule.ExpireDate,
+		"createDate":  rule.CreateDate,
+		"updateDate":  rule.UpdateDate,
+		"deleteDate":  rule.DeleteDate,
+	}
+}
+
+// WebhookUserID returns the User who owns this Rule.
+// Domain-level Rules (created by an administrator) return a zero ID.
+func (rule Rule) WebhookUserID() primitive.ObjectID {
+	return rule.UserID
+}
@@ -0,0 +1,280 @@
+package model
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+)
+
+// contentRegexMaxInstructions caps the compiled size of a CONTENT rule's regular expression.
+// Go's RE2 engine already guarantees linear-time matching (no catastrophic backtracking), so this
+// only bounds the per-character cost of a pathological pattern such as `\w{1,900}\d{1,900}`.
+const contentRegexMaxInstructions = 2000
+
+// ContentMatcher is a precompiled set of CONTENT Rules. It is built once per rule set (and cached
+// by the Rule service per owner), so that inbox processing does not re-parse a single pattern.
+type ContentMatcher struct {
+	patterns []contentPattern
+}
+
+// contentPattern is a single compiled CONTENT Rule (or Filter keyword), keyed by the MatchKey
+// that it produces.  It matches either a word or phrase, or a regular expression.
+type contentPattern struct {
+	matchKey  string
+	phrase    string // lower-cased word or phrase, used when regex is nil
+	wholeWord bool   // if TRUE, the phrase only matches at word boundaries
+	regex     *regexp.Regexp
+}
+
+// NewContentMatcher compiles every CONTENT Rule in the provided set. Rules of other types are
+// ignored, and a Rule whose pattern no longer compiles is skipped (it matches nothing) rather than
+// breaking every other Rule in the set.
+func NewContentMatcher(rules []RuleSummary) ContentMatcher {
+
+	result := ContentMatcher{
+		patterns: make([]contentPattern, 0, len(rules)),
+	}
+
+	for _, rule := range rules {
+
+		if rule.Type != RuleTypeContent {
+			continue
+		}
+
+		// Compile from the MatchKey (not the Trigger) so that the matcher and the
+		// disposition engine can never disagree about which key a match produces.
+		trigger, ok := strings.CutPrefix(rule.MatchKey, RuleTypeContent+":")
+
+		if !ok {
+			continue
+		}
+
+		if pattern, err := newContentPattern(trigger); err == nil {
+			pattern.matchKey = rule.MatchKey
+			result.patterns = append(result.patterns, pattern)
+		}
+	}
+
+	return result
+}
+
+// NewKeywordMatcher compiles the keywords of a Mastodon Filter, so that Filters and CONTENT
+// Rules share one matching engine.  Each keyword matches under its own text (not a MatchKey),
+// and keywords are never treated as regular expressions.
+func NewKeywordMatcher(keywords []FilterKeyword) ContentMatcher {
+
+	result := ContentMatcher{
+		patterns: make([]contentPattern, 0, len(keywords)),
+	}
+
+	for _, keyword := range keywords {
+
+		phrase := strings.ToLower(strings.TrimSpace(keyword.Keyword))
+
+		if phrase == "" {
+			continue
+		}
+
+		result.patterns = append(result.patterns, contentPattern{
+			matchKey:  keyword.Keyword,
+			phrase:    phrase,
+			wholeWord: keyword.WholeWord,
+		})
+	}
+
+	return result
+}
+
+// IsEmpty returns TRUE if this matcher contains no CONTENT Rules.
+func (matcher ContentMatcher) IsEmpty() bool {
+	return len(matcher.patterns) == 0
+}
+
+// MatchKeys returns the MatchKey of every CONTENT Rule that matches the provided document's text
+// (see ContentText). Appending these to DocumentMatchKeys lets the disposition engine rank CONTENT
+// Rules exactly like every other type.
+func (matcher ContentMatcher) MatchKeys(document streams.Document) []string {
+
+	if matcher.IsEmpty() {
+		return make([]string, 0)
+	}
+
+	return matcher.MatchText(ContentText(document))
+}
+
+// MatchText returns the MatchKey of every CONTENT Rule that matches the provided text, which must
+// already be lower-cased plain text (see ContentText).
+func (matcher ContentMatcher) MatchText(text string) []string {
+
+	result := make([]string, 0)
+
+	if text == "" {
+		return result
+	}
+
+	for _, pattern := range matcher.patterns {
+		if pattern.matches(text) {
+			result = append(result, pattern.matchKey)
+		}
+	}
+
+	return result
+}
+
+// matches returns TRUE if this pattern appears in the provided (lower-cased) text.
+// Whole-word phrases only match when they are not part of a larger word, so "cat" matches
+// "a cat!" but not "concatenate".  Like Mastodon, a boundary is only required at an edge of
+// the phrase that is a word character, so a phrase that begins with punctuation (like "#cat")
+// may follow any character.
+func (pattern contentPattern) matches(text string) bool {
+
+	if pattern.regex != nil {
+		return pattern.regex.MatchString(text)
+	}
+
+	if !pattern.wholeWord {
+		return strings.Contains(text, pattern.phrase)
+	}
+
+	// Boundaries are only enforced at the edges of the phrase that are word characters
+	first, _ := utf8.DecodeRuneInString(pattern.phrase)
+	last, _ := utf8.DecodeLastRuneInString(pattern.phrase)
+	checkStart := isWordRune(first)
+	checkEnd := isWordRune(last)
+
+	// Scan every occurrence until one sits on a word boundary
+	for offset := 0; offset < len(text); {
+
+		index := strings.Index(text[offset:], pattern.phrase)
+
+		if index < 0 {
+			return false
+		}
+
+		start := offset + index
+		end := start + len(pattern.phrase)
+
+		if checkStart && (start > 0) {
+			if before, _ := utf8.DecodeLastRuneInString(text[:start]); isWordRune(before) {
+				offset = start + 1
+				continue
+			}
+		}
+
+		if checkEnd && (end < len(text)) {
+			if after, _ := utf8.DecodeRuneInString(text[end:]); isWordRune(after) {
+				offset = start + 1
+				continue
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// ContentText returns the lower-cased, plain-text version of everything a CONTENT Rule can match
+// in a document: its name, summary, and content, plus the name (alt text) of every attachment.
+func ContentText(document streams.Document) string {
+
+	values := []string{document.Name(), document.Summary(), document.Content()}
+
+	for attachment := document.Attachment(); attachment.NotNil(); attachment = attachment.Next() {
+
+		// Bare-string attachments carry no alt text, and reading them would fetch them
+		if attachment.IsString() {
+			continue
+		}
+
+		values = append(values, attachment.Name())
+	}
+
+	return FilterTextFromStrings(values...)
+}
+
+// isWordRune returns TRUE if the rune is part of a word (a letter, digit, or underscore)
+func isWordRune(value rune) bool {
+	return unicode.IsLetter(value) || unicode.IsDigit(value) || (value == '_')
+}
+
+// ValidateContentTrigger returns an error if the provided Trigger cannot be used in a CONTENT Rule.
+// A Trigger is either a word or phrase (matched case-insensitively on word boundaries) or a regular
+// expression wrapped in slashes, like `/buy(ing)? followers/`.
+func ValidateContentTrigger(trigger string) error {
+
+	_, err := newContentPattern(normalizeContentTrigger(trigger))
+	return err
+}
+
+// isContentRegex returns TRUE if the provided (normalized) Trigger is a regular expression
+func isContentRegex(trigger string) bool {
+	return (len(trigger) > 2) && strings.HasPrefix(trigger, "/") && strings.HasSuffix(trigger, "/")
+}
+
+// normalizeContentTrigger trims a CONTENT Trigger. Words and phrases are also lower-cased, with
+// their inner whitespace collapsed, so that "Buy  Followers" and "buy followers" share a MatchKey.
+// Regular expressions are left as written, because case can be meaningful inside a pattern.
+func normalizeContentTrigger(trigger string) string {
+
+	trigger = strings.TrimSpace(trigger)
+
+	if isContentRegex(trigger) {
+		return trigger
+	}
+
+	return strings.Join(strings.Fields(strings.ToLower(trigger)), " ")
+}
+
+// newContentPattern compiles a normalized CONTENT Trigger into a contentPattern.
+func newContentPattern(trigger string) (contentPattern, error) {
+
+	if trigger == "" {
+		return contentPattern{}, derp.Validation("Trigger cannot be empty")
+	}
+
+	// Words and phrases always match whole words
+	if !isContentRegex(trigger) {
+		return contentPattern{
+			phrase:    trigger,
+			wholeWord: true,
+		}, nil
+	}
+
+	// Regular expressions always match case-insensitively, because the text is lower-cased
+	expression := "(?i)" + trigger[1:len(trigger)-1]
+
+	parsed, err := syntax.Parse(expression, syntax.Perl)
+
+	if err != nil {
+		return contentPattern{}, derp.Validation("Regular expression does not compile", err.Error())
+	}
+
+	program, err := syntax.Compile(parsed.Simplify())
+
+	if err != nil {
+		return contentPattern{}, derp.Validation("Regular expression does not compile", err.Error())
+	}
+
+	if len(program.Inst) > contentRegexMaxInstructions {
+		return contentPattern{}, derp.Validation("Regular expression is too complex")
+	}
+
+	regex, err := regexp.Compile(expression)
+
+	if err != nil {
+		return contentPattern{}, derp.Validation("Regular expression does not compile", err.Error())
+	}
+
+	// RULE: A pattern that matches empty text matches EVERY document, which is never what was meant
+	if regex.MatchString("") {
+		return contentPattern{}, derp.Validation("Regular expression matches everything")
+	}
+
+	return contentPattern{regex: regex}, nil
+}
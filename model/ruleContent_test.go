@@ -0,0 +1,111 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+// contentRule builds a CONTENT RuleSummary with its MatchKey derived exactly as Save would.
+func contentRule(trigger string) RuleSummary {
+	return RuleSummary{
+		Type:     RuleTypeContent,
+		Trigger:  trigger,
+		MatchKey: RuleMatchKey(RuleTypeContent, trigger),
+	}
+}
+
+// TestRuleMatchKey_Content pins CONTENT normalization: words and phrases are lower-cased with their
+// whitespace collapsed, while regular expressions keep their case.
+func TestRuleMatchKey_Content(t *testing.T) {
+	require.Equal(t, "CONTENT:buy followers", RuleMatchKey(RuleTypeContent, "  Buy   Followers "))
+	require.Equal(t, "CONTENT:/Buy\\s+Followers/", RuleMatchKey(RuleTypeContent, "/Buy\\s+Followers/"))
+	require.Equal(t, "", RuleMatchKey(RuleTypeContent, "   "))
+}
+
+// Words and phrases match whole words only, case-insensitively
+func TestContentMatcher_Words(t *testing.T) {
+
+	rule := contentRule("Crypto Giveaway")
+	matcher := NewContentMatcher([]RuleSummary{rule})
+
+	require.Equal(t, []string{rule.MatchKey}, matcher.MatchText("a crypto giveaway!"))
+	require.Empty(t, matcher.MatchText("cryptogiveaway"))
+	require.Empty(t, matcher.MatchText("crypto giveaways"))
+}
+
+// Regular expressions match anywhere, case-insensitively
+func TestContentMatcher_Regex(t *testing.T) {
+
+	rule := contentRule(`/bit\.ly\/[a-z0-9]+/`)
+	matcher := NewContentMatcher([]RuleSummary{rule})
+
+	require.Equal(t, []string{rule.MatchKey}, matcher.MatchText("click https://bit.ly/abc123 now"))
+	require.Empty(t, matcher.MatchText("bitly is a company"))
+}
+
+// Filter keywords use the same engine, matching whole words or (optionally) any substring
+func TestNewKeywordMatcher(t *testing.T) {
+
+	matcher := NewKeywordMatcher([]FilterKeyword{{Keyword: "Cat", WholeWord: true}})
+	require.Equal(t, []string{"Cat"}, matcher.MatchText("a cat!"))
+	require.Equal(t, []string{"Cat"}, matcher.MatchText("cat"))
+	require.Equal(t, []string{"Cat"}, matcher.MatchText("concatenate the cat"))
+	require.Empty(t, matcher.MatchText("concatenate"))
+	require.Empty(t, matcher.MatchText("cats"))
+
+	matcher = NewKeywordMatcher([]FilterKeyword{{Keyword: "cat", WholeWord: false}})
+	require.Equal(t, []string{"cat"}, matcher.MatchText("concatenate"))
+	require.Empty(t, matcher.MatchText("dog"))
+
+	matcher = NewKeywordMatcher([]FilterKeyword{{Keyword: "#cat", WholeWord: true}})
+	require.Equal(t, []string{"#cat"}, matcher.MatchText("x#cat"))
+	require.Empty(t, matcher.MatchText("#cats"))
+
+	// Keywords are never regular expressions, and blank keywords are skipped
+	matcher = NewKeywordMatcher([]FilterKeyword{{Keyword: "/c.t/", WholeWord: false}, {Keyword: " ", WholeWord: true}})
+	require.Empty(t, matcher.MatchText("cat"))
+	require.Equal(t, []string{"/c.t/"}, matcher.MatchText("see /c.t/ here"))
+}
+
+// Other rule types, and rules that no longer compile, contribute nothing
+func TestContentMatcher_Ignored(t *testing.T) {
+
+	matcher := NewContentMatcher([]RuleSummary{
+		{Type: RuleTypeTag, MatchKey: RuleMatchKey(RuleTypeTag, "crypto")},
+		{Type: RuleTypeContent, MatchKey: "CONTENT:/(/"},
+	})
+
+	require.True(t, matcher.IsEmpty())
+	require.Empty(t, matcher.MatchText("crypto"))
+}
+
+// ContentText reads the name, summary, content, and attachment alt text of a document
+func TestContentMatcher_Document(t *testing.T) {
+
+	rule := contentRule("sunset")
+	matcher := NewContentMatcher([]RuleSummary{rule})
+
+	document := streams.NewDocument(mapof.Any{
+		vocab.PropertyContent: "<p>Look at this</p>",
+		vocab.PropertyAttachment: []mapof.Any{{
+			vocab.PropertyType: vocab.ObjectTypeImage,
+			vocab.PropertyName: "A SUNSET over the bay",
+		}},
+	})
+
+	require.Equal(t, []string{rule.MatchKey}, matcher.MatchKeys(document))
+}
+
+// Triggers that would never match (or would match everything) are refused
+func TestValidateContentTrigger(t *testing.T) {
+	require.NoError(t, ValidateContentTrigger("buy followers"))
+	require.NoError(t, ValidateContentTrigger("/buy(ing)? followers/"))
+	require.Error(t, ValidateContentTrigger(""))
+	require.Error(t, ValidateContentTrigger("/(unclosed/"))
+	require.Error(t, ValidateContentTrigger("/.*/"))
+	require.Error(t, ValidateContentTrigger(`/\w{1,900}\d{1,900}/`))
+}
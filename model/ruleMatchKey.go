@@ -5,7 +5,6 @@ import (
 	"strings"
 
 	"github.com/benpate/hannibal/streams"
-	"github.com/benpate/hannibal/vocab"
 	"github.com/benpate/uri"
 	"golang.org/x/net/idna"
 )
@@ -42,6 +41,13 @@ func RuleMatchKey(ruleType string, trigger string) string {
 		if tag := ToToken(trigger); tag != "" {
 			return RuleTypeTag + ":" + tag
 		}
+
+	// CONTENT keys are never produced by DocumentMatchKeys. A ContentMatcher (compiled from the
+	// key itself) adds them to a document's key set when the document's text matches.
+	case RuleTypeContent:
+		if content := normalizeContentTrigger(trigger); content != "" {
+			return RuleTypeContent + ":" + content
+		}
 	}
 
 	// Unknown type or empty trigger federates as nothing.
@@ -73,19 +79,8 @@ func DocumentMatchKeys(document streams.Document) []string {
 
 	// A TAG key for each Hashtag on the document. Mentions and Emoji are deliberately excluded
 	// (D12) so a TAG rule for "alice" cannot match a post that merely mentions @alice.
-	for tag := document.Tag(); tag.NotNil(); tag = tag.Next() {
-
-		if tag.IsString() {
-			continue
-		}
-
-		if tag.Type() != vocab.LinkTypeHashtag {
-			continue
-		}
-
-		if token := ToToken(tag.Name()); token != "" {
-			result = append(result, RuleTypeTag+":"+token)
-		}
+	for _, token := range DocumentHashtags(document) {
+		result = append(result, RuleTypeTag+":"+token)
 	}
 
 	return result
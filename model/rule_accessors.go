@@ -14,7 +14,7 @@ func RuleSchema() schema.Element {
 			"ruleId":         schema.String{Required: true, Format: "objectId"},
 			"userId":         schema.String{Required: true, Format: "objectId"},
 			"followingLabel": schema.String{Format: "text", MaxLength: 64},
-			"type":           schema.String{Required: true, Enum: []string{RuleTypeDomain, RuleTypeActor, RuleTypeTag}},
+			"type":           schema.String{Required: true, Enum: []string{RuleTypeDomain, RuleTypeActor, RuleTypeTag, RuleTypeContent}},
 			"action":         schema.String{Required: true, Enum: []string{RuleActionBlock, RuleActionMute, RuleActionLabel}},
 			"label":          schema.String{Format: "text", MaxLength: 64},
 			"trigger":        schema.String{MaxLength: 256, Required: true},
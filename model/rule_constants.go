@@ -9,6 +9,9 @@ const RuleTypeActor = "ACTOR"
 // RuleTypeTag rules all messages carrying a specific hashtag
 const RuleTypeTag = "TAG"
 
+// RuleTypeContent rules all messages whose text contains a specific word, phrase, or /regular expression/
+const RuleTypeContent = "CONTENT"
+
 // RuleActionBlock rules all contact with a particular user or domain
 const RuleActionBlock = "BLOCK"
 
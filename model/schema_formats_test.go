@@ -44,6 +44,7 @@ func modelSchemas() map[string]schema.Element {
 		"OriginLinkSchema":       OriginLinkSchema(),
 		"OutboxItemSchema":       OutboxItemSchema(),
 		"OutboxMessageSchema":    OutboxMessageSchema(),
+		"PasskeySchema":          PasskeySchema(),
 		"PasswordResetSchema":    PasswordResetSchema(),
 		"PersonLinkSchema":       PersonLinkSchema(),
 		"PrivilegeSchema":        PrivilegeSchema(),
@@ -0,0 +1,12 @@
+package model
+
+import "github.com/benpate/uri"
+
+// SharedInboxPath is the path (relative to the server root) of the domain-wide shared inbox
+const SharedInboxPath = "/@inbox"
+
+// SharedInboxURL returns the domain-wide shared inbox that serves the provided local actor.
+// This is advertised as the "sharedInbox" endpoint on every User and Stream actor.
+func SharedInboxURL(actorURL string) string {
+	return uri.Host(actorURL) + SharedInboxPath
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharedInboxURL(t *testing.T) {
+	require.Equal(t, "https://example.com/@inbox", SharedInboxURL("https://example.com/@6543210fedcba9876543210f"))
+	require.Equal(t, "https://example.com/@inbox", SharedInboxURL("https://example.com/my-stream"))
+	require.Equal(t, "http://localhost:8080/@inbox", SharedInboxURL("http://localhost:8080/@alice"))
+}
+
+func TestStreamActor_SharedInbox(t *testing.T) {
+
+	actor := StreamActor{SocialRole: vocab.ActorTypePerson}
+	stream := NewStream()
+	stream.URL = "https://example.com/my-stream"
+
+	endpoints, ok := actor.JSONLD(&stream)[vocab.PropertyEndpoints].(mapof.String)
+	require.True(t, ok)
+	require.Equal(t, "https://example.com/@inbox", endpoints["sharedInbox"])
+}
@@ -5,10 +5,12 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// SigninAttempt logs a failed signin attempt for a specific username.
+// SigninAttempt logs a failed signin attempt for a specific username.  It also records
+// the history of each User's second factors (enrollments, uses, and resets).
 type SigninAttempt struct {
 	SigninAttemptID primitive.ObjectID `bson:"_id"`
 	Username        string             `bson:"username"`  // Username that was used in the signin attempt
+	Event           string             `bson:"event"`     // Type of event (see model.SigninEvent* constants).  Older records without an event are password failures.
 	IPAddress       string             `bson:"ipAddress"` // Resolved client IP that made the attempt (forensics; the lockout window counts by username across all IPs)
 	UserAgent       string             `bson:"userAgent"` // User-Agent header of the attempt (forensics only)
 	journal.Journal `bson:",inline"`   // Embedded journal fields for tracking creation and updates
@@ -20,6 +22,7 @@ func NewSigninAttempt(username string, ipAddress string, userAgent string) Signi
 	return SigninAttempt{
 		SigninAttemptID: primitive.NewObjectID(),
 		Username:        username,
+		Event:           SigninEventPasswordFailure,
 		IPAddress:       ipAddress,
 		UserAgent:       userAgent,
 	}
@@ -0,0 +1,49 @@
+package model
+
+// SigninEventPasswordFailure records a signin attempt with an incorrect password
+const SigninEventPasswordFailure = "PASSWORD-FAILURE"
+
+// SigninEventTwoFactorFailure records a signin attempt with an incorrect second factor
+const SigninEventTwoFactorFailure = "TWO-FACTOR-FAILURE"
+
+// SigninEventPasskeyFailure records a signin attempt with a passkey that could not be verified
+const SigninEventPasskeyFailure = "PASSKEY-FAILURE"
+
+// SigninEventTwoFactorEnroll records that a User has confirmed a new second factor
+const SigninEventTwoFactorEnroll = "TWO-FACTOR-ENROLL"
+
+// SigninEventTwoFactorUse records a successful signin with a TOTP code
+const SigninEventTwoFactorUse = "TWO-FACTOR-USE"
+
+// SigninEventRecoveryCodeUse records a successful signin with a single-use recovery code
+const SigninEventRecoveryCodeUse = "RECOVERY-CODE-USE"
+
+// SigninEventRecoveryCodeReset records that a User has replaced their recovery codes
+const SigninEventRecoveryCodeReset = "RECOVERY-CODE-RESET"
+
+// SigninEventTwoFactorReset records that a User's second factor has been removed
+const SigninEventTwoFactorReset = "TWO-FACTOR-RESET"
+
+// SigninEventPasskeyAdd records that a User has registered a new passkey
+const SigninEventPasskeyAdd = "PASSKEY-ADD"
+
+// SigninEventPasskeyUse records a successful signin (or second factor) with a passkey
+const SigninEventPasskeyUse = "PASSKEY-USE"
+
+// SigninEventPasskeyRemove records that a User's passkey has been revoked
+const SigninEventPasskeyRemove = "PASSKEY-REMOVE"
+
+// SigninHistoryEvents returns the events that are kept as a User's history.  These never
+// count toward the signin lockout, and are not removed when failures are cleared.
+func SigninHistoryEvents() []string {
+	return []string{
+		SigninEventTwoFactorEnroll,
+		SigninEventTwoFactorUse,
+		SigninEventRecoveryCodeUse,
+		SigninEventRecoveryCodeReset,
+		SigninEventTwoFactorReset,
+		SigninEventPasskeyAdd,
+		SigninEventPasskeyUse,
+		SigninEventPasskeyRemove,
+	}
+}
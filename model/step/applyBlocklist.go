@@ -0,0 +1,34 @@
+package step
+
+import (
+	"github.com/benpate/rosetta/mapof"
+)
+
+// ApplyBlocklist is a Step that applies every pending change in the current Blocklist to the server-wide Rules.
+type ApplyBlocklist struct{}
+
+// NewApplyBlocklist returns a fully initialized ApplyBlocklist object
+func NewApplyBlocklist(stepInfo mapof.Any) (ApplyBlocklist, error) {
+	return ApplyBlocklist{}, nil
+}
+
+// Name returns the name of the step, which is used in debugging.
+func (step ApplyBlocklist) Name() string {
+	return "apply-blocklist"
+}
+
+// RequiredModel returns the name of the model object that MUST be present in the Template.
+// If this value is not empty, then the Template MUST use this model object.
+func (step ApplyBlocklist) RequiredModel() string {
+	return "Blocklist"
+}
+
+// RequiredStates returns a slice of states that must be defined any Template that uses this Step
+func (step ApplyBlocklist) RequiredStates() []string {
+	return []string{}
+}
+
+// RequiredRoles returns a slice of roles that must be defined any Template that uses this Step
+func (step ApplyBlocklist) RequiredRoles() []string {
+	return []string{}
+}
@@ -0,0 +1,17 @@
+package step
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyBlocklist(t *testing.T) {
+	step, err := NewApplyBlocklist(mapof.Any{})
+	require.Nil(t, err)
+	require.Equal(t, "apply-blocklist", step.Name())
+	require.Equal(t, "Blocklist", step.RequiredModel())
+	require.Equal(t, []string{}, step.RequiredStates())
+	require.Equal(t, []string{}, step.RequiredRoles())
+}
@@ -27,13 +27,13 @@ func NewEditTemplate(stepInfo mapof.Any) (EditTemplate, error) {
 		case "do", "title":
 			// NO OP
 
-		case "templateId", "inboxTemplate", "outboxTemplate":
+		case "templateId", "inboxTemplate", "outboxTemplate", "noteTemplate", "articleTemplate", "outboxFolderId":
 			if stepInfo.GetBool(key) {
 				result.Paths = append(result.Paths, key)
 			}
 
 		default:
-			return EditTemplate{}, derp.BadRequest(location, "Invalid value.  Only 'templateId', 'inboxTemplate', and 'outboxTemplate' are allowed", key)
+			return EditTemplate{}, derp.BadRequest(location, "Invalid value.  Only 'templateId', 'inboxTemplate', 'outboxTemplate', 'noteTemplate', 'articleTemplate', and 'outboxFolderId' are allowed", key)
 		}
 	}
 
@@ -27,8 +27,19 @@ func TestEditTemplate(t *testing.T) {
 	require.Equal(t, []string{}, step.RequiredRoles())
 }
 
+func TestEditTemplate_PostingTemplates(t *testing.T) {
+
+	step, err := NewEditTemplate(mapof.Any{
+		"noteTemplate":    true,
+		"articleTemplate": true,
+		"outboxFolderId":  true,
+	})
+	require.Nil(t, err)
+	require.ElementsMatch(t, []string{"articleTemplate", "noteTemplate", "outboxFolderId"}, step.Paths)
+}
+
 func TestEditTemplate_InvalidKey(t *testing.T) {
-	// Any key other than do/title/templateId/inboxTemplate/outboxTemplate is rejected.
+	// Any key other than do/title/templateId/inboxTemplate/outboxTemplate/noteTemplate/articleTemplate/outboxFolderId is rejected.
 	_, err := NewEditTemplate(mapof.Any{"unexpectedKey": true})
 	require.NotNil(t, err)
 }
@@ -0,0 +1,37 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// FollowRequest is a Step that approves or rejects a pending follow request
+type FollowRequest struct {
+	Accept bool // If TRUE, then the request is approved.  Otherwise, it is rejected.
+}
+
+// NewFollowRequest returns a fully initialized FollowRequest object
+func NewFollowRequest(stepInfo mapof.Any) (FollowRequest, error) {
+
+	return FollowRequest{
+		Accept: stepInfo.GetBool("accept"),
+	}, nil
+}
+
+// Name returns the name of the step, which is used in debugging.
+func (step FollowRequest) Name() string {
+	return "follow-request"
+}
+
+// RequiredModel returns the name of the model object that MUST be present in the Template.
+// If this value is not empty, then the Template MUST use this model object.
+func (step FollowRequest) RequiredModel() string {
+	return ""
+}
+
+// RequiredStates returns a slice of states that must be defined any Template that uses this Step
+func (step FollowRequest) RequiredStates() []string {
+	return []string{}
+}
+
+// RequiredRoles returns a slice of roles that must be defined any Template that uses this Step
+func (step FollowRequest) RequiredRoles() []string {
+	return []string{}
+}
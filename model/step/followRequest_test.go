@@ -0,0 +1,24 @@
+package step
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollowRequest(t *testing.T) {
+	step, err := NewFollowRequest(mapof.Any{"accept": true})
+	require.Nil(t, err)
+	require.Equal(t, "follow-request", step.Name())
+	require.True(t, step.Accept)
+	require.Equal(t, "", step.RequiredModel())
+	require.Equal(t, []string{}, step.RequiredStates())
+	require.Equal(t, []string{}, step.RequiredRoles())
+}
+
+func TestFollowRequest_Reject(t *testing.T) {
+	step, err := NewFollowRequest(mapof.Any{})
+	require.Nil(t, err)
+	require.False(t, step.Accept)
+}
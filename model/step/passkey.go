@@ -0,0 +1,45 @@
+package step
+
+import (
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/mapof"
+)
+
+// Passkey is a Step that registers, renames, or revokes a User's passkeys
+type Passkey struct {
+	Action string // One of "begin", "register", "rename", or "delete"
+}
+
+// NewPasskey returns a fully initialized Passkey object
+func NewPasskey(stepInfo mapof.Any) (Passkey, error) {
+
+	action := stepInfo.GetString("action")
+
+	switch action {
+	case "begin", "register", "rename", "delete":
+		return Passkey{Action: action}, nil
+	}
+
+	return Passkey{}, derp.Internal("model.step.NewPasskey", "Invalid 'action' parameter. Must be 'begin', 'register', 'rename', or 'delete'", action)
+}
+
+// Name returns the name of the step, which is used in debugging.
+func (step Passkey) Name() string {
+	return "passkey"
+}
+
+// RequiredModel returns the name of the model object that MUST be present in the Template.
+// If this value is not empty, then the Template MUST use this model object.
+func (step Passkey) RequiredModel() string {
+	return ""
+}
+
+// RequiredStates returns a slice of states that must be defined any Template that uses this Step
+func (step Passkey) RequiredStates() []string {
+	return []string{}
+}
+
+// RequiredRoles returns a slice of roles that must be defined any Template that uses this Step
+func (step Passkey) RequiredRoles() []string {
+	return []string{}
+}
@@ -0,0 +1,26 @@
+package step
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasskey(t *testing.T) {
+	step, err := NewPasskey(mapof.Any{"action": "register"})
+	require.Nil(t, err)
+	require.Equal(t, "passkey", step.Name())
+	require.Equal(t, "register", step.Action)
+	require.Equal(t, "", step.RequiredModel())
+	require.Equal(t, []string{}, step.RequiredStates())
+	require.Equal(t, []string{}, step.RequiredRoles())
+}
+
+func TestPasskey_InvalidAction(t *testing.T) {
+	_, err := NewPasskey(mapof.Any{"action": "enroll"})
+	require.NotNil(t, err)
+
+	_, err = NewPasskey(mapof.Any{})
+	require.NotNil(t, err)
+}
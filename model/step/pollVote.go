@@ -0,0 +1,33 @@
+package step
+
+import "github.com/benpate/rosetta/mapof"
+
+// PollVote is a Step that casts the current user's vote in a Poll
+type PollVote struct{}
+
+// NewPollVote returns a fully initialized PollVote object
+func NewPollVote(stepInfo mapof.Any) (PollVote, error) {
+
+	return PollVote{}, nil
+}
+
+// Name returns the name of the step, which is used in debugging.
+func (step PollVote) Name() string {
+	return "poll-vote"
+}
+
+// RequiredModel returns the name of the model object that MUST be present in the Template.
+// If this value is not empty, then the Template MUST use this model object.
+func (step PollVote) RequiredModel() string {
+	return ""
+}
+
+// RequiredStates returns a slice of states that must be defined any Template that uses this Step
+func (step PollVote) RequiredStates() []string {
+	return []string{}
+}
+
+// RequiredRoles returns a slice of roles that must be defined any Template that uses this Step
+func (step PollVote) RequiredRoles() []string {
+	return []string{}
+}
@@ -0,0 +1,17 @@
+package step
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPollVote(t *testing.T) {
+	step, err := NewPollVote(mapof.Any{})
+	require.Nil(t, err)
+	require.Equal(t, "poll-vote", step.Name())
+	require.Equal(t, "", step.RequiredModel())
+	require.Equal(t, []string{}, step.RequiredStates())
+	require.Equal(t, []string{}, step.RequiredRoles())
+}
@@ -0,0 +1,35 @@
+package step
+
+import (
+	"github.com/benpate/rosetta/mapof"
+)
+
+// PreviewBlocklist is a Step that reads the latest copy of the current Blocklist and stores the
+// changes it would make, so that they can be reviewed before they are applied.
+type PreviewBlocklist struct{}
+
+// NewPreviewBlocklist returns a fully initialized PreviewBlocklist object
+func NewPreviewBlocklist(stepInfo mapof.Any) (PreviewBlocklist, error) {
+	return PreviewBlocklist{}, nil
+}
+
+// Name returns the name of the step, which is used in debugging.
+func (step PreviewBlocklist) Name() string {
+	return "preview-blocklist"
+}
+
+// RequiredModel returns the name of the model object that MUST be present in the Template.
+// If this value is not empty, then the Template MUST use this model object.
+func (step PreviewBlocklist) RequiredModel() string {
+	return "Blocklist"
+}
+
+// RequiredStates returns a slice of states that must be defined any Template that uses this Step
+func (step PreviewBlocklist) RequiredStates() []string {
+	return []string{}
+}
+
+// RequiredRoles returns a slice of roles that must be defined any Template that uses this Step
+func (step PreviewBlocklist) RequiredRoles() []string {
+	return []string{}
+}
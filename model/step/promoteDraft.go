@@ -6,12 +6,14 @@ import (
 
 // StreamPromoteDraft represents a pipeline-step that can copy the Container from a StreamDraft into its corresponding Stream
 type StreamPromoteDraft struct {
-	StateID string
+	StateID  string // The ID of the state that the promoted Stream will use.
+	Schedule string // Name of the form field that can request a future ("publish later") date.
 }
 
 func NewStreamPromoteDraft(stepInfo mapof.Any) (StreamPromoteDraft, error) {
 	return StreamPromoteDraft{
-		StateID: first(stepInfo.GetString("state"), "published"),
+		StateID:  first(stepInfo.GetString("state"), "published"),
+		Schedule: stepInfo.GetString("schedule"),
 	}, nil
 }
 
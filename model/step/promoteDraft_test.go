@@ -13,6 +13,12 @@ func TestStreamPromoteDraft(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, "live", step.StateID)
 	require.Equal(t, []string{"live"}, step.RequiredStates())
+	require.Equal(t, "", step.Schedule)
+
+	// "schedule" names the form field with a "publish later" date
+	step, err = NewStreamPromoteDraft(mapof.Any{"schedule": "publishDate"})
+	require.Nil(t, err)
+	require.Equal(t, "publishDate", step.Schedule)
 
 	// "state" defaults to "published".
 	step, err = NewStreamPromoteDraft(mapof.Any{})
@@ -0,0 +1,35 @@
+package step
+
+import (
+	"github.com/benpate/rosetta/mapof"
+)
+
+// ReplayWebhook is a Step that re-sends a previously logged WebhookDelivery
+// (identified by the "deliveryId" query parameter) to its Webhook.
+type ReplayWebhook struct{}
+
+// NewReplayWebhook returns a fully initialized ReplayWebhook object
+func NewReplayWebhook(stepInfo mapof.Any) (ReplayWebhook, error) {
+	return ReplayWebhook{}, nil
+}
+
+// Name returns the name of the step, which is used in debugging.
+func (step ReplayWebhook) Name() string {
+	return "replay-webhook"
+}
+
+// RequiredModel returns the name of the model object that MUST be present in the Template.
+// If this value is not empty, then the Template MUST use this model object.
+func (step ReplayWebhook) RequiredModel() string {
+	return "Webhook"
+}
+
+// RequiredStates returns a slice of states that must be defined any Template that uses this Step
+func (step ReplayWebhook) RequiredStates() []string {
+	return []string{}
+}
+
+// RequiredRoles returns a slice of roles that must be defined any Template that uses this Step
+func (step ReplayWebhook) RequiredRoles() []string {
+	return []string{}
+}
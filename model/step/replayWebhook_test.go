@@ -0,0 +1,17 @@
+package step
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayWebhook(t *testing.T) {
+	step, err := NewReplayWebhook(mapof.Any{})
+	require.Nil(t, err)
+	require.Equal(t, "replay-webhook", step.Name())
+	require.Equal(t, "Webhook", step.RequiredModel())
+	require.Equal(t, []string{}, step.RequiredStates())
+	require.Equal(t, []string{}, step.RequiredRoles())
+}
@@ -0,0 +1,46 @@
+package step
+
+import (
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/mapof"
+)
+
+// ResolveReport is a Step that closes a moderation Report, creating
+// any server-wide Rule that the moderator chose in the Report's resolution.
+type ResolveReport struct {
+	Status string // One of "RESOLVED" or "DISMISSED"
+}
+
+// NewResolveReport returns a fully initialized ResolveReport object
+func NewResolveReport(stepInfo mapof.Any) (ResolveReport, error) {
+
+	status := stepInfo.GetString("status")
+
+	switch status {
+	case "RESOLVED", "DISMISSED":
+		return ResolveReport{Status: status}, nil
+	}
+
+	return ResolveReport{}, derp.Internal("model.step.NewResolveReport", "Invalid 'status' parameter. Must be 'RESOLVED' or 'DISMISSED'", status)
+}
+
+// Name returns the name of the step, which is used in debugging.
+func (step ResolveReport) Name() string {
+	return "resolve-report"
+}
+
+// RequiredModel returns the name of the model object that MUST be present in the Template.
+// If this value is not empty, then the Template MUST use this model object.
+func (step ResolveReport) RequiredModel() string {
+	return "Report"
+}
+
+// RequiredStates returns a slice of states that must be defined any Template that uses this Step
+func (step ResolveReport) RequiredStates() []string {
+	return []string{}
+}
+
+// RequiredRoles returns a slice of roles that must be defined any Template that uses this Step
+func (step ResolveReport) RequiredRoles() []string {
+	return []string{}
+}
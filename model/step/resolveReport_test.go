@@ -0,0 +1,26 @@
+package step
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveReport(t *testing.T) {
+	step, err := NewResolveReport(mapof.Any{"status": "DISMISSED"})
+	require.Nil(t, err)
+	require.Equal(t, "resolve-report", step.Name())
+	require.Equal(t, "DISMISSED", step.Status)
+	require.Equal(t, "Report", step.RequiredModel())
+	require.Equal(t, []string{}, step.RequiredStates())
+	require.Equal(t, []string{}, step.RequiredRoles())
+}
+
+func TestResolveReport_InvalidStatus(t *testing.T) {
+	_, err := NewResolveReport(mapof.Any{"status": "OPEN"})
+	require.NotNil(t, err)
+
+	_, err = NewResolveReport(mapof.Any{})
+	require.NotNil(t, err)
+}
@@ -0,0 +1,35 @@
+package step
+
+import (
+	"github.com/benpate/rosetta/mapof"
+)
+
+// RotateKey is a Step that replaces a User's ActivityPub signing key with a newly generated key,
+// and federates the new public key to the User's followers.
+type RotateKey struct{}
+
+// NewRotateKey returns a fully initialized RotateKey object
+func NewRotateKey(stepInfo mapof.Any) (RotateKey, error) {
+	return RotateKey{}, nil
+}
+
+// Name returns the name of the step, which is used in debugging.
+func (step RotateKey) Name() string {
+	return "rotate-key"
+}
+
+// RequiredModel returns the name of the model object that MUST be present in the Template.
+// If this value is not empty, then the Template MUST use this model object.
+func (step RotateKey) RequiredModel() string {
+	return "User"
+}
+
+// RequiredStates returns a slice of states that must be defined any Template that uses this Step
+func (step RotateKey) RequiredStates() []string {
+	return []string{}
+}
+
+// RequiredRoles returns a slice of roles that must be defined any Template that uses this Step
+func (step RotateKey) RequiredRoles() []string {
+	return []string{}
+}
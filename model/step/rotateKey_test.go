@@ -0,0 +1,17 @@
+package step
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotateKey(t *testing.T) {
+	step, err := NewRotateKey(mapof.Any{})
+	require.Nil(t, err)
+	require.Equal(t, "rotate-key", step.Name())
+	require.Equal(t, "User", step.RequiredModel())
+	require.Equal(t, []string{}, step.RequiredStates())
+	require.Equal(t, []string{}, step.RequiredRoles())
+}
@@ -9,6 +9,7 @@ type SaveAndPublish struct {
 	StateID   string // The ID of the state that this step will update.
 	Outbox    bool   // If TRUE, also send updates to this User's outbox.
 	Republish bool   // If TRUE, republishes this stream to syndication targets.
+	Schedule  string // Name of the form field that can request a future ("publish later") date.
 }
 
 // NewSaveAndPublish returns a fully initialized SaveAndPublish object
@@ -18,6 +19,7 @@ func NewSaveAndPublish(stepInfo mapof.Any) (SaveAndPublish, error) {
 		StateID:   first(stepInfo.GetString("state"), "published"),
 		Outbox:    stepInfo.GetBool("outbox"),
 		Republish: stepInfo.GetBool("republish"),
+		Schedule:  stepInfo.GetString("schedule"),
 	}
 
 	return result, nil
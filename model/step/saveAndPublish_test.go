@@ -15,6 +15,12 @@ func TestSaveAndPublish(t *testing.T) {
 	require.True(t, step.Outbox)
 	require.True(t, step.Republish)
 	require.Equal(t, []string{"live"}, step.RequiredStates())
+	require.Equal(t, "", step.Schedule)
+
+	// "schedule" names the form field with a "publish later" date
+	step, err = NewSaveAndPublish(mapof.Any{"schedule": "publishDate"})
+	require.Nil(t, err)
+	require.Equal(t, "publishDate", step.Schedule)
 
 	// "state" defaults to "published".
 	step, err = NewSaveAndPublish(mapof.Any{})
@@ -56,6 +56,9 @@ func New(stepInfo mapof.Any) (Step, error) {
 	case "add-stream":
 		return NewAddStream(stepInfo)
 
+	case "apply-blocklist":
+		return NewApplyBlocklist(stepInfo)
+
 	case "as-confirmation":
 		return NewAsConfirmation(stepInfo)
 
@@ -101,6 +104,9 @@ func New(stepInfo mapof.Any) (Step, error) {
 	case "edit-widget":
 		return NewEditWidget(stepInfo)
 
+	case "follow-request":
+		return NewFollowRequest(stepInfo)
+
 	case "forward-to":
 		return NewForwardTo(stepInfo)
 
@@ -134,6 +140,15 @@ func New(stepInfo mapof.Any) (Step, error) {
 	case "mark-notifications-read":
 		return NewMarkNotificationsRead(stepInfo)
 
+	case "passkey":
+		return NewPasskey(stepInfo)
+
+	case "poll-vote":
+		return NewPollVote(stepInfo)
+
+	case "preview-blocklist":
+		return NewPreviewBlocklist(stepInfo)
+
 	case "process-content":
 		return NewProcessContent(stepInfo)
 
@@ -155,9 +170,18 @@ func New(stepInfo mapof.Any) (Step, error) {
 	case "remove-event":
 		return NewRemoveEvent(stepInfo)
 
+	case "replay-webhook":
+		return NewReplayWebhook(stepInfo)
+
+	case "resolve-report":
+		return NewResolveReport(stepInfo)
+
 	case "require-password":
 		return NewRequirePassword(stepInfo)
 
+	case "rotate-key":
+		return NewRotateKey(stepInfo)
+
 	case "save":
 		return NewSave(stepInfo)
 
@@ -218,9 +242,15 @@ func New(stepInfo mapof.Any) (Step, error) {
 	case "sort-widgets":
 		return NewSortWidgets(stepInfo)
 
+	case "subscribe-relay":
+		return NewSubscribeRelay(stepInfo)
+
 	case "trigger-event":
 		return NewTriggerEvent(stepInfo)
 
+	case "two-factor":
+		return NewTwoFactor(stepInfo)
+
 	case "unpublish":
 		return NewUnPublish(stepInfo)
 
@@ -260,6 +290,9 @@ func New(stepInfo mapof.Any) (Step, error) {
 	case "with-folder":
 		return NewWithFolder(stepInfo)
 
+	case "with-followed-tag":
+		return NewWithFollowedTag(stepInfo)
+
 	case "with-following":
 		return NewWithFollowing(stepInfo)
 
@@ -299,6 +332,9 @@ func New(stepInfo mapof.Any) (Step, error) {
 	case "with-response":
 		return NewWithResponse(stepInfo)
 
+	case "with-report":
+		return NewWithReport(stepInfo)
+
 	case "with-rule":
 		return NewWithRule(stepInfo)
 
@@ -25,6 +25,7 @@ func TestNew_Dispatch(t *testing.T) {
 		{"add", mapof.Any{"form": map[string]any{"type": "layout-vertical"}}, "add"},
 		{"add-event", mapof.Any{}, "add-event"},
 		{"add-stream", mapof.Any{}, "add-stream"},
+		{"apply-blocklist", mapof.Any{}, "apply-blocklist"},
 		{"as-confirmation", mapof.Any{}, "as-confirmation"},
 		{"as-modal", mapof.Any{}, "as-modal"},
 		{"as-tooltip", mapof.Any{}, "as-tooltip"},
@@ -40,6 +41,7 @@ func TestNew_Dispatch(t *testing.T) {
 		{"edit-table", mapof.Any{"form": map[string]any{"type": "layout-vertical"}}, "edit-table"},
 		{"edit-template", mapof.Any{}, "edit-template"},
 		{"edit-widget", mapof.Any{}, "edit-widget"},
+		{"follow-request", mapof.Any{}, "follow-request"},
 		{"forward-to", mapof.Any{}, "forward-to"},
 		{"get-archive", mapof.Any{}, "get-archive"},
 		{"halt", mapof.Any{}, "halt"},
@@ -49,6 +51,9 @@ func TestNew_Dispatch(t *testing.T) {
 		{"inline-save-button", mapof.Any{}, "inline-save-button"},
 		{"inline-success", mapof.Any{}, "inline-success"},
 		{"make-archive", mapof.Any{}, "make-archive"},
+		{"passkey", mapof.Any{"action": "delete"}, "passkey"},
+		{"poll-vote", mapof.Any{}, "poll-vote"},
+		{"preview-blocklist", mapof.Any{}, "preview-blocklist"},
 		{"process-content", mapof.Any{}, "process-content"},
 		{"process-tags", mapof.Any{}, "process-tags"},
 		{"promote-draft", mapof.Any{}, "promote-draft"},
@@ -56,7 +61,10 @@ func TestNew_Dispatch(t *testing.T) {
 		{"refresh-page", mapof.Any{}, "refresh-page"},
 		{"reload-page", mapof.Any{}, "reload-page"},
 		{"remove-event", mapof.Any{}, "remove-event"},
+		{"replay-webhook", mapof.Any{}, "replay-webhook"},
 		{"require-password", mapof.Any{}, "requirePassword"},
+		{"resolve-report", mapof.Any{"status": "RESOLVED"}, "resolve-report"},
+		{"rotate-key", mapof.Any{}, "rotate-key"},
 		{"save", mapof.Any{}, "save"},
 		{"save-and-publish", mapof.Any{}, "save-and-publish"},
 		{"schedule-delete", mapof.Any{}, "schedule-delete"},
@@ -77,7 +85,9 @@ func TestNew_Dispatch(t *testing.T) {
 		{"sort", mapof.Any{}, "set-sort"},
 		{"sort-attachments", mapof.Any{}, "sort-attachments"},
 		{"sort-widgets", mapof.Any{}, "sort-widgets"},
+		{"subscribe-relay", mapof.Any{}, "subscribe-relay"},
 		{"trigger-event", mapof.Any{}, "trigger-event"},
+		{"two-factor", mapof.Any{"action": "reset"}, "two-factor"},
 		{"unpublish", mapof.Any{}, "unpublish"},
 		{"upload-attachments", mapof.Any{}, "upload-attachments"},
 		{"view-attachment", mapof.Any{"format": []string{"pdf"}}, "view-attachment"},
@@ -92,6 +102,7 @@ func TestNew_Dispatch(t *testing.T) {
 		{"with-draft", mapof.Any{}, "with-draft"},
 		{"with-folder", mapof.Any{}, "with-folder"},
 		{"with-follower", mapof.Any{}, "with-follower"},
+		{"with-followed-tag", mapof.Any{}, "with-followed-tag"},
 		{"with-following", mapof.Any{}, "with-following"},
 		{"with-import", mapof.Any{}, "with-import"},
 		{"with-keypackage", mapof.Any{}, "with-key-package"},
@@ -103,6 +114,7 @@ func TestNew_Dispatch(t *testing.T) {
 		{"with-prev-sibling", mapof.Any{}, "with-prev-sibling"},
 		{"with-privilege", mapof.Any{}, "with-privilege"},
 		{"with-response", mapof.Any{}, "with-response"},
+		{"with-report", mapof.Any{}, "with-report"},
 		{"with-rule", mapof.Any{}, "with-rule"},
 	}
 
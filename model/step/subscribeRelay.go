@@ -0,0 +1,34 @@
+package step
+
+import (
+	"github.com/benpate/rosetta/mapof"
+)
+
+// SubscribeRelay is a Step that sends a Follow from the domain's search actor to the current Relay
+type SubscribeRelay struct{}
+
+// NewSubscribeRelay returns a fully initialized SubscribeRelay object
+func NewSubscribeRelay(stepInfo mapof.Any) (SubscribeRelay, error) {
+	return SubscribeRelay{}, nil
+}
+
+// Name returns the name of the step, which is used in debugging.
+func (step SubscribeRelay) Name() string {
+	return "subscribe-relay"
+}
+
+// RequiredModel returns the name of the model object that MUST be present in the Template.
+// If this value is not empty, then the Template MUST use this model object.
+func (step SubscribeRelay) RequiredModel() string {
+	return "Relay"
+}
+
+// RequiredStates returns a slice of states that must be defined any Template that uses this Step
+func (step SubscribeRelay) RequiredStates() []string {
+	return []string{}
+}
+
+// RequiredRoles returns a slice of roles that must be defined any Template that uses this Step
+func (step SubscribeRelay) RequiredRoles() []string {
+	return []string{}
+}
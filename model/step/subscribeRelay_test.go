@@ -0,0 +1,17 @@
+package step
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribeRelay(t *testing.T) {
+	step, err := NewSubscribeRelay(mapof.Any{})
+	require.Nil(t, err)
+	require.Equal(t, "subscribe-relay", step.Name())
+	require.Equal(t, "Relay", step.RequiredModel())
+	require.Equal(t, []string{}, step.RequiredStates())
+	require.Equal(t, []string{}, step.RequiredRoles())
+}
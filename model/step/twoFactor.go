@@ -0,0 +1,45 @@
+package step
+
+import (
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/mapof"
+)
+
+// TwoFactor is a Step that enrolls, resets, or removes a User's second factor
+type TwoFactor struct {
+	Action string // One of "enroll", "recovery-codes", or "reset"
+}
+
+// NewTwoFactor returns a fully initialized TwoFactor object
+func NewTwoFactor(stepInfo mapof.Any) (TwoFactor, error) {
+
+	action := stepInfo.GetString("action")
+
+	switch action {
+	case "enroll", "recovery-codes", "reset":
+		return TwoFactor{Action: action}, nil
+	}
+
+	return TwoFactor{}, derp.Internal("model.step.NewTwoFactor", "Invalid 'action' parameter. Must be 'enroll', 'recovery-codes', or 'reset'", action)
+}
+
+// Name returns the name of the step, which is used in debugging.
+func (step TwoFactor) Name() string {
+	return "two-factor"
+}
+
+// RequiredModel returns the name of the model object that MUST be present in the Template.
+// If this value is not empty, then the Template MUST use this model object.
+func (step TwoFactor) RequiredModel() string {
+	return ""
+}
+
+// RequiredStates returns a slice of states that must be defined any Template that uses this Step
+func (step TwoFactor) RequiredStates() []string {
+	return []string{}
+}
+
+// RequiredRoles returns a slice of roles that must be defined any Template that uses this Step
+func (step TwoFactor) RequiredRoles() []string {
+	return []string{}
+}
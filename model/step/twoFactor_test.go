@@ -0,0 +1,26 @@
+package step
+
+import (
+	"testing"
+
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwoFactor(t *testing.T) {
+	step, err := NewTwoFactor(mapof.Any{"action": "enroll"})
+	require.Nil(t, err)
+	require.Equal(t, "two-factor", step.Name())
+	require.Equal(t, "enroll", step.Action)
+	require.Equal(t, "", step.RequiredModel())
+	require.Equal(t, []string{}, step.RequiredStates())
+	require.Equal(t, []string{}, step.RequiredRoles())
+}
+
+func TestTwoFactor_InvalidAction(t *testing.T) {
+	_, err := NewTwoFactor(mapof.Any{"action": "disable"})
+	require.NotNil(t, err)
+
+	_, err = NewTwoFactor(mapof.Any{})
+	require.NotNil(t, err)
+}
@@ -0,0 +1,49 @@
+package step
+
+import (
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/convert"
+	"github.com/benpate/rosetta/mapof"
+)
+
+// WithFollowedTag is a Step that returns a new FollowedTag builder
+type WithFollowedTag struct {
+	SubSteps []Step
+}
+
+// NewWithFollowedTag returns a fully initialized WithFollowedTag object
+func NewWithFollowedTag(stepInfo mapof.Any) (WithFollowedTag, error) {
+
+	const location = "NewWithFollowedTag"
+
+	subSteps, err := NewPipeline(convert.SliceOfMap(stepInfo["steps"]))
+
+	if err != nil {
+		return WithFollowedTag{}, derp.Wrap(err, location, "Invalid 'steps'", stepInfo)
+	}
+
+	return WithFollowedTag{
+		SubSteps: subSteps,
+	}, nil
+}
+
+// Name returns the name of the step, which is used in debugging.
+func (step WithFollowedTag) Name() string {
+	return "with-followed-tag"
+}
+
+// RequiredModel returns the name of the model object that MUST be present in the Template.
+// If this value is not empty, then the Template MUST use this model object.
+func (step WithFollowedTag) RequiredModel() string {
+	return ""
+}
+
+// RequiredStates returns a slice of states that must be defined any Template that uses this Step
+func (step WithFollowedTag) RequiredStates() []string {
+	return []string{} // removing this because states may be different in the child objects // requiredStates(step.SubSteps...)
+}
+
+// RequiredRoles returns a slice of roles that must be defined any Template that uses this Step
+func (step WithFollowedTag) RequiredRoles() []string {
+	return requiredRoles(step.SubSteps...)
+}
@@ -0,0 +1,49 @@
+package step
+
+import (
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/convert"
+	"github.com/benpate/rosetta/mapof"
+)
+
+// WithReport is a Step that returns a new Report Builder
+type WithReport struct {
+	SubSteps []Step
+}
+
+// NewWithReport returns a fully initialized WithReport object
+func NewWithReport(stepInfo mapof.Any) (WithReport, error) {
+
+	const location = "NewWithReport"
+
+	subSteps, err := NewPipeline(convert.SliceOfMap(stepInfo["steps"]))
+
+	if err != nil {
+		return WithReport{}, derp.Wrap(err, location, "Invalid 'steps'", stepInfo)
+	}
+
+	return WithReport{
+		SubSteps: subSteps,
+	}, nil
+}
+
+// Name returns the name of the step, which is used in debugging.
+func (step WithReport) Name() string {
+	return "with-report"
+}
+
+// RequiredModel returns the name of the model object that MUST be present in the Template.
+// If this value is not empty, then the Template MUST use this model object.
+func (step WithReport) RequiredModel() string {
+	return ""
+}
+
+// RequiredStates returns a slice of states that must be defined any Template that uses this Step
+func (step WithReport) RequiredStates() []string {
+	return []string{} // states may be different in the child objects
+}
+
+// RequiredRoles returns a slice of roles that must be defined any Template that uses this Step
+func (step WithReport) RequiredRoles() []string {
+	return requiredRoles(step.SubSteps...)
+}
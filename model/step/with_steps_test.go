@@ -28,6 +28,7 @@ func withStepCases() []withStepCase {
 		{"WithCircle", func(s mapof.Any) (Step, error) { return NewWithCircle(s) }, "with-circle", ""},
 		{"WithDraft", func(s mapof.Any) (Step, error) { return NewWithDraft(s) }, "with-draft", "Stream"},
 		{"WithFolder", func(s mapof.Any) (Step, error) { return NewWithFolder(s) }, "with-folder", ""},
+		{"WithFollowedTag", func(s mapof.Any) (Step, error) { return NewWithFollowedTag(s) }, "with-followed-tag", ""},
 		{"WithFollower", func(s mapof.Any) (Step, error) { return NewWithFollower(s) }, "with-follower", ""},
 		{"WithFollowing", func(s mapof.Any) (Step, error) { return NewWithFollowing(s) }, "with-following", ""},
 		{"WithImport", func(s mapof.Any) (Step, error) { return NewWithImport(s) }, "with-import", ""},
@@ -40,6 +41,7 @@ func withStepCases() []withStepCase {
 		{"WithPrevSibling", func(s mapof.Any) (Step, error) { return NewWithPrevSibling(s) }, "with-prev-sibling", "Stream"},
 		{"WithPrivilege", func(s mapof.Any) (Step, error) { return NewWithPrivilege(s) }, "with-privilege", ""},
 		{"WithResponse", func(s mapof.Any) (Step, error) { return NewWithResponse(s) }, "with-response", ""},
+		{"WithReport", func(s mapof.Any) (Step, error) { return NewWithReport(s) }, "with-report", ""},
 		{"WithRule", func(s mapof.Any) (Step, error) { return NewWithRule(s) }, "with-rule", ""},
 	}
 }
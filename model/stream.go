@@ -50,6 +50,7 @@ type Stream struct {
 	Widgets          set.Slice[StreamWidget] `bson:"widgets,omitempty"`      // Additional widgets to include when building this Stream.
 	Hashtags         sliceof.String          `bson:"hashtags,omitempty"`     // List of hashtags that are associated with this document
 	Location         geo.Address             `bson:"location,omitempty"`     // Location assigned to this stream
+	Poll             Poll                    `bson:"poll,omitempty"`         // Poll that people can vote on (published as an ActivityPub "Question")
 	Data             mapof.Any               `bson:"data,omitempty"`         // Set of data to populate into the Template.  This is validated by the JSON-Schema of the Template.
 	StartDate        datetime.DateTime       `bson:"startDate,omitempty"`    // Date/Time to publish as a "start date" for this Stream (semantics are dependent on the Template)
 	EndDate          datetime.DateTime       `bson:"endDate,omitempty"`      // Date/Time to publish as an "end date" for this Stream (semantics are dependent on the Template)
@@ -58,6 +59,7 @@ type Stream struct {
 	Shuffle          int64                   `bson:"shuffle"`                // Random number used to shuffle the order of Streams in a list.
 	PublishDate      int64                   `bson:"publishDate"`            // Unix epoch SECONDS when this document is/was/will be first available on the domain (0 = unpublished; math.MaxInt64 = not yet scheduled).
 	UnPublishDate    int64                   `bson:"unpublishDate"`          // Unix epoch SECONDS when this document will no longer be available on the domain (math.MaxInt64 = never).
+	Schedule         StreamSchedule          `bson:"schedule,omitempty"`     // Pending "publish later" request.  Empty unless this Stream is waiting to be published at its PublishDate.
 	IsFeatured       bool                    `bson:"isFeatured"`             // TRUE if this Stream is featured by its parent container.
 	IsSubscribable   bool                    `bson:"isSubscribable"`         // TRUE if this Stream uses the Products service to determine access rights.
 	ReplyCount       int                     `bson:"replyCount"`             // Denormalized count of replies to this Stream (maintained by the Stream service's reply funnel).
@@ -86,6 +88,7 @@ func NewStream() Stream {
 		Circles:       mapof.NewObject[id.Slice](),
 		Products:      mapof.NewObject[id.Slice](),
 		Location:      geo.NewAddress(),
+		Poll:          NewPoll(),
 		DefaultAllow:  NewPermissions(),
 		PrivilegeIDs:  NewPermissions(),
 		Widgets:       NewStreamWidgets(),
@@ -188,6 +191,12 @@ func (stream Stream) IsAuthor(authorID primitive.ObjectID) bool {
 	return !authorID.IsZero() && authorID == stream.AttributedTo.UserID
 }
 
+// AuthorID returns the UserID of the local User who created this Stream (if any)
+// It is part of the AuthorIDGetter interface
+func (stream Stream) AuthorID() primitive.ObjectID {
+	return stream.AttributedTo.UserID
+}
+
 // IsMyself returns TRUE if this object directly represents the provided UserID
 // It is part of the AccessLister interface
 func (stream Stream) IsMyself(_ primitive.ObjectID) bool {
@@ -370,6 +379,17 @@ func (stream Stream) IsPublished() bool {
 	return (stream.PublishDate <= now) && (stream.UnPublishDate > now)
 }
 
+// IsScheduled returns TRUE if this Stream is waiting to be published at its PublishDate
+func (stream Stream) IsScheduled() bool {
+
+	// RULE: Deleted streams are not scheduled
+	if stream.DeleteDate > 0 {
+		return false
+	}
+
+	return !stream.Schedule.IsZero()
+}
+
 /******************************************
  * Mastodon API Methods
  ******************************************/
@@ -389,6 +409,22 @@ func (stream Stream) Toot() object.Status {
 	}
 }
 
+// ScheduledToot returns this Stream as a Mastodon "ScheduledStatus"
+func (stream Stream) ScheduledToot() object.ScheduledStatus {
+
+	result := object.ScheduledStatus{
+		ID:          stream.StreamID.Hex(),
+		ScheduledAt: time.Unix(stream.PublishDate, 0).UTC().Format(time.RFC3339),
+	}
+
+	result.Params.Text = stream.Content.Raw
+	result.Params.SpoilerText = stream.Label
+	result.Params.InReplyToID = stream.InReplyTo
+	result.Params.Visibility = "public"
+
+	return result
+}
+
 func (stream Stream) GetRank() int64 {
 	return int64(stream.Rank)
 }
@@ -480,6 +516,11 @@ func (stream Stream) GetWebhookData() mapof.Any {
 	}
 }
 
+// WebhookUserID returns the User who is attributed as the author of this Stream
+func (stream Stream) WebhookUserID() primitive.ObjectID {
+	return stream.AttributedTo.UserID
+}
+
 // UpdateAttachmentURLs updates values in the Stream that match the remoteURL to the localURL
 func (stream *Stream) UpdateAttachmentURLs(remoteURL string, localURL string) {
 
@@ -532,6 +573,7 @@ func (stream *Stream) Update(other Stream) {
 	stream.Syndication = other.Syndication
 	stream.PublishDate = other.PublishDate
 	stream.UnPublishDate = other.UnPublishDate
+	stream.Schedule = other.Schedule
 	stream.IsFeatured = other.IsFeatured
 	stream.Journal = other.Journal
 }
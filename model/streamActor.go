@@ -39,6 +39,9 @@ func (actor StreamActor) JSONLD(stream *Stream) mapof.Any {
 		vocab.PropertyOutbox:            stream.ActivityPubOutboxURL(),
 		vocab.PropertyName:              stream.Label,
 		vocab.PropertyPreferredUsername: stream.Token,
+		vocab.PropertyEndpoints: mapof.String{
+			"sharedInbox": SharedInboxURL(stream.ActivityPubURL()),
+		},
 	}
 
 	if stream.Summary != "" {
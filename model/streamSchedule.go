@@ -0,0 +1,56 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/relvacode/iso8601"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StreamSchedule records a "publish later" request for a Stream.  Scheduled Streams keep
+// their future PublishDate until the "PublishScheduledStream" task publishes them, using
+// the same options that the author chose when scheduling.
+type StreamSchedule struct {
+	UserID    primitive.ObjectID `bson:"userId"`              // User who scheduled this Stream, and who will publish it
+	StateID   string             `bson:"stateId"`             // State that the Stream moves into when it is published
+	Outbox    bool               `bson:"outbox,omitempty"`    // If TRUE, the Stream is also sent to the User's outbox
+	Republish bool               `bson:"republish,omitempty"` // If TRUE, the Stream is republished to syndication targets
+}
+
+// NewStreamSchedule returns a fully initialized StreamSchedule object
+func NewStreamSchedule(userID primitive.ObjectID, stateID string, outbox bool, republish bool) StreamSchedule {
+	return StreamSchedule{
+		UserID:    userID,
+		StateID:   stateID,
+		Outbox:    outbox,
+		Republish: republish,
+	}
+}
+
+// IsZero returns TRUE if this StreamSchedule is empty (i.e. nothing is scheduled)
+func (schedule StreamSchedule) IsZero() bool {
+	return schedule.UserID.IsZero()
+}
+
+// ParsePublishDate converts a requested publish date into Unix epoch SECONDS.
+// It accepts ISO 8601 strings (as sent by the Mastodon API and HTML forms) or
+// raw epoch seconds.  Empty or invalid values return zero.
+func ParsePublishDate(value string) int64 {
+
+	value = strings.TrimSpace(value)
+
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return max(seconds, 0)
+	}
+
+	if publishDate, err := iso8601.ParseString(value); err == nil {
+		return max(publishDate.Unix(), 0)
+	}
+
+	return 0
+}
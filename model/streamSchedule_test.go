@@ -0,0 +1,71 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParsePublishDate(t *testing.T) {
+
+	// Empty and invalid values are zero
+	require.Zero(t, ParsePublishDate(""))
+	require.Zero(t, ParsePublishDate("   "))
+	require.Zero(t, ParsePublishDate("next tuesday"))
+	require.Zero(t, ParsePublishDate("-100"))
+
+	// Epoch seconds
+	require.Equal(t, int64(1700000000), ParsePublishDate("1700000000"))
+
+	// ISO 8601 (Mastodon API and HTML forms)
+	require.Equal(t, int64(1700000000), ParsePublishDate("2023-11-14T22:13:20Z"))
+	require.Equal(t, int64(1700000000), ParsePublishDate("2023-11-14T23:13:20+01:00"))
+	require.Equal(t, int64(1700000000), ParsePublishDate(" 2023-11-14T22:13:20.000Z "))
+}
+
+func TestStreamSchedule(t *testing.T) {
+
+	userID := primitive.NewObjectID()
+
+	require.True(t, StreamSchedule{}.IsZero())
+
+	schedule := NewStreamSchedule(userID, "published", true, false)
+	require.False(t, schedule.IsZero())
+	require.Equal(t, userID, schedule.UserID)
+	require.Equal(t, "published", schedule.StateID)
+	require.True(t, schedule.Outbox)
+	require.False(t, schedule.Republish)
+}
+
+func TestStream_IsScheduled(t *testing.T) {
+
+	stream := NewStream()
+	require.False(t, stream.IsScheduled())
+
+	stream.PublishDate = time.Now().Add(time.Hour).Unix()
+	stream.Schedule = NewStreamSchedule(primitive.NewObjectID(), "published", true, false)
+	require.True(t, stream.IsScheduled())
+	require.False(t, stream.IsPublished())
+
+	// Deleted Streams are never scheduled
+	stream.DeleteDate = time.Now().Unix()
+	require.False(t, stream.IsScheduled())
+}
+
+func TestStream_ScheduledToot(t *testing.T) {
+
+	stream := NewStream()
+	stream.PublishDate = 1700000000
+	stream.Label = "Content Warning"
+	stream.InReplyTo = "https://example.com/parent"
+	stream.Content = NewHTMLContent("<p>Hello</p>")
+
+	result := stream.ScheduledToot()
+	require.Equal(t, stream.StreamID.Hex(), result.ID)
+	require.Equal(t, "2023-11-14T22:13:20Z", result.ScheduledAt)
+	require.Equal(t, "<p>Hello</p>", result.Params.Text)
+	require.Equal(t, "Content Warning", result.Params.SpoilerText)
+	require.Equal(t, "https://example.com/parent", result.Params.InReplyToID)
+}
@@ -43,6 +43,7 @@ func StreamSchema() schema.Element {
 			"endDate":          datetime.Schema(),
 			"hashtags":         schema.Array{Items: schema.String{Format: "token", MaxLength: 32}},
 			"location":         geo.AddressSchema(),
+			"poll":             PollSchema(),
 			"data":             schema.Object{Wildcard: schema.Any{}},
 			"publishDate":      schema.Integer{BitSize: 64},
 			"unpublishDate":    schema.Integer{BitSize: 64},
@@ -123,6 +124,9 @@ func (stream *Stream) GetPointer(name string) (any, bool) {
 	case "location":
 		return &stream.Location, true
 
+	case "poll":
+		return &stream.Poll, true
+
 	case "data":
 		return &stream.Data, true
 
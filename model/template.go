@@ -6,6 +6,7 @@ import (
 	"slices"
 	"strings"
 
+	"github.com/EmissarySocial/emissary/model/step"
 	"github.com/EmissarySocial/emissary/tools/templatemap"
 	"github.com/benpate/data/option"
 	"github.com/benpate/derp"
@@ -146,6 +147,12 @@ func (template *Template) IsValidRole(roleID string) bool {
 
 	// Custom roles must be defined in the Template
 	default:
+
+		// Group roles are validated at runtime, against the Groups on this server
+		if _, isGroup := GroupRoleToken(roleID); isGroup {
+			return true
+		}
+
 		if _, ok := template.AccessRoles[roleID]; !ok {
 			return false
 		}
@@ -205,6 +212,21 @@ func (template *Template) Default() Action {
 	return template.Actions[template.DefaultAction]
 }
 
+// PublishState returns the State that this Template's "create" Action publishes new Streams
+// into (via a "save-and-publish" step).  Templates without one fall back to "published".
+func (template *Template) PublishState() string {
+
+	if action, ok := template.Action("create"); ok {
+		for _, actionStep := range action.Steps {
+			if saveAndPublish, ok := actionStep.(step.SaveAndPublish); ok {
+				return saveAndPublish.StateID
+			}
+		}
+	}
+
+	return "published"
+}
+
 func (template *Template) Inherit(parent *Template) {
 
 	// NILCHECK: Parent cannot be nil
@@ -52,3 +52,20 @@ func TestTemplate_Inherit_TagPathsNotInherited(t *testing.T) {
 
 	require.Empty(t, child.TagPaths)
 }
+
+// TestTemplate_PublishState confirms that new Streams are published into the State declared by
+// the "create" Action's "save-and-publish" step.
+func TestTemplate_PublishState(t *testing.T) {
+	template := NewTemplate("test", nil)
+
+	err := hjson.Unmarshal([]byte(`{actions:{create:{steps:[{do:"save"}, {do:"save-and-publish", state:"default"}]}}}`), &template)
+
+	require.Nil(t, err)
+	require.Equal(t, "default", template.PublishState())
+}
+
+// TestTemplate_PublishState_Missing confirms the fallback for Templates without a "save-and-publish" step.
+func TestTemplate_PublishState_Missing(t *testing.T) {
+	template := NewTemplate("test", nil)
+	require.Equal(t, "published", template.PublishState())
+}
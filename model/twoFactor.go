@@ -0,0 +1,126 @@
+package model
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/tools/totp"
+	"github.com/benpate/rosetta/sliceof"
+)
+
+// TwoFactorRecoveryCodeCount is the number of single-use recovery codes issued to a User
+const TwoFactorRecoveryCodeCount = 10
+
+// TwoFactorChallengeDuration is how long a User has to enter their second factor after
+// entering a valid password.
+const TwoFactorChallengeDuration = 5 * time.Minute
+
+// TwoFactorRememberDuration is how long a "remember this device" cookie skips the second factor
+const TwoFactorRememberDuration = 30 * 24 * time.Hour
+
+// TwoFactor stores a User's TOTP second factor and recovery codes.
+type TwoFactor struct {
+	Secret        string         `bson:"secret,omitempty"`        // Base32 encoded TOTP secret.  If empty, then two-factor authentication is not active.
+	PendingSecret string         `bson:"pendingSecret,omitempty"` // Secret that has been shown to the User but not yet confirmed with a valid code
+	RecoveryCodes sliceof.String `bson:"recoveryCodes,omitempty"` // SHA-256 hashes of the unused recovery codes.  Plaintext codes are only shown once.
+	LastCounter   int64          `bson:"lastCounter,omitempty"`   // Most recent TOTP time step that was used, so codes cannot be replayed
+	DeviceKey     string         `bson:"deviceKey,omitempty"`     // Random key embedded in "remember this device" cookies.  Changing it forgets all devices.
+	EnrollDate    int64          `bson:"enrollDate,omitempty"`    // Unix epoch seconds when the second factor was confirmed
+}
+
+// NewTwoFactor returns a fully initialized TwoFactor object
+func NewTwoFactor() TwoFactor {
+	return TwoFactor{
+		RecoveryCodes: sliceof.NewString(),
+	}
+}
+
+// IsActive returns TRUE if the User has confirmed a second factor
+func (twoFactor TwoFactor) IsActive() bool {
+	return twoFactor.Secret != ""
+}
+
+// IsPending returns TRUE if the User has started, but not confirmed, an enrollment
+func (twoFactor TwoFactor) IsPending() bool {
+	return twoFactor.PendingSecret != ""
+}
+
+// RecoveryCodesRemaining returns the number of unused recovery codes
+func (twoFactor TwoFactor) RecoveryCodesRemaining() int {
+	return len(twoFactor.RecoveryCodes)
+}
+
+// Activate confirms the pending secret, if the provided code is valid for it.
+func (twoFactor *TwoFactor) Activate(code string, now time.Time) bool {
+
+	counter, ok := totp.Validate(twoFactor.PendingSecret, code, now, 0)
+
+	if !ok {
+		return false
+	}
+
+	twoFactor.Secret = twoFactor.PendingSecret
+	twoFactor.PendingSecret = ""
+	twoFactor.LastCounter = counter
+	twoFactor.EnrollDate = now.Unix()
+
+	return true
+}
+
+// ValidateCode returns TRUE if the provided TOTP code is valid at the provided time.
+// Each code can only be used once.
+func (twoFactor *TwoFactor) ValidateCode(code string, now time.Time) bool {
+
+	if !twoFactor.IsActive() {
+		return false
+	}
+
+	counter, ok := totp.Validate(twoFactor.Secret, code, now, twoFactor.LastCounter)
+
+	if !ok {
+		return false
+	}
+
+	twoFactor.LastCounter = counter
+	return true
+}
+
+// SetRecoveryCodes replaces all recovery codes with hashes of the provided plaintext codes
+func (twoFactor *TwoFactor) SetRecoveryCodes(codes []string) {
+
+	twoFactor.RecoveryCodes = make(sliceof.String, len(codes))
+
+	for index, code := range codes {
+		twoFactor.RecoveryCodes[index] = hashRecoveryCode(code)
+	}
+}
+
+// UseRecoveryCode returns TRUE if the provided code matches an unused recovery code,
+// and removes it so that it cannot be used again.
+func (twoFactor *TwoFactor) UseRecoveryCode(code string) bool {
+
+	hash := hashRecoveryCode(code)
+
+	for index, existing := range twoFactor.RecoveryCodes {
+		if subtle.ConstantTimeCompare([]byte(existing), []byte(hash)) == 1 {
+			twoFactor.RecoveryCodes = append(twoFactor.RecoveryCodes[:index], twoFactor.RecoveryCodes[index+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// hashRecoveryCode returns the stored form of a recovery code.  Codes are compared
+// without regard to case, spaces, or dashes so that they are easy to type.
+func hashRecoveryCode(code string) string {
+
+	code = strings.ToLower(code)
+	code = strings.NewReplacer(" ", "", "-", "").Replace(code)
+
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EmissarySocial/emissary/tools/totp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwoFactor_Activate(t *testing.T) {
+
+	secret, err := totp.GenerateSecret()
+	require.Nil(t, err)
+
+	now := time.Now()
+	twoFactor := NewTwoFactor()
+	twoFactor.PendingSecret = secret
+	require.False(t, twoFactor.IsActive())
+	require.True(t, twoFactor.IsPending())
+
+	// Wrong codes do not activate the second factor
+	require.False(t, twoFactor.Activate("000000x", now))
+	require.False(t, twoFactor.IsActive())
+
+	code, err := totp.Code(secret, totp.Counter(now))
+	require.Nil(t, err)
+
+	require.True(t, twoFactor.Activate(code, now))
+	require.True(t, twoFactor.IsActive())
+	require.False(t, twoFactor.IsPending())
+	require.Equal(t, secret, twoFactor.Secret)
+
+	// The enrollment code cannot be replayed at signin
+	require.False(t, twoFactor.ValidateCode(code, now))
+}
+
+func TestTwoFactor_ValidateCode(t *testing.T) {
+
+	secret, err := totp.GenerateSecret()
+	require.Nil(t, err)
+
+	now := time.Now()
+	twoFactor := NewTwoFactor()
+	twoFactor.Secret = secret
+
+	code, err := totp.Code(secret, totp.Counter(now))
+	require.Nil(t, err)
+
+	require.True(t, twoFactor.ValidateCode(code, now))
+	require.False(t, twoFactor.ValidateCode(code, now))
+}
+
+func TestTwoFactor_RecoveryCodes(t *testing.T) {
+
+	twoFactor := NewTwoFactor()
+	twoFactor.SetRecoveryCodes([]string{"abcde-fghij", "klmno-pqrst"})
+	require.Equal(t, 2, twoFactor.RecoveryCodesRemaining())
+
+	// Stored codes are hashed
+	require.NotContains(t, twoFactor.RecoveryCodes, "abcde-fghij")
+
+	// Codes ignore case, spaces, and dashes
+	require.True(t, twoFactor.UseRecoveryCode("ABCDE FGHIJ"))
+	require.Equal(t, 1, twoFactor.RecoveryCodesRemaining())
+
+	// Codes are single-use
+	require.False(t, twoFactor.UseRecoveryCode("abcde-fghij"))
+	require.False(t, twoFactor.UseRecoveryCode("wrong-code"))
+
+	require.True(t, twoFactor.UseRecoveryCode("klmnopqrst"))
+	require.Equal(t, 0, twoFactor.RecoveryCodesRemaining())
+}
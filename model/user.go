@@ -5,9 +5,11 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/EmissarySocial/emissary/tools/id"
+	"github.com/EmissarySocial/emissary/tools/multikey"
 	"github.com/EmissarySocial/emissary/tools/replace"
 	"github.com/benpate/data/journal"
 	"github.com/benpate/delta"
@@ -23,39 +25,45 @@ import (
 
 // User represents a person or machine account that can own pages and sections.
 type User struct {
-	UserID               primitive.ObjectID         `bson:"_id"`                  // Unique identifier for this user.
-	MapIDs               mapof.String               `bson:"mapIds"`               // Map of IDs for this user on other web services.
-	GroupIDs             id.Slice                   `bson:"groupIds"`             // Slice of IDs for the groups that this user belongs to.
-	IconID               primitive.ObjectID         `bson:"iconId"`               // AttachmentID of this user's avatar/icon image.
-	ImageID              primitive.ObjectID         `bson:"imageId"`              // AttachmentID of this user's banner image.
-	DisplayName          string                     `bson:"displayName"`          // Name to be displayed for this user
-	StatusMessage        string                     `bson:"statusMessage"`        // Status summary for this user
-	Location             string                     `bson:"location"`             // Human-friendly description of this user's physical location.
-	ProfileURL           string                     `bson:"profileUrl"`           // Fully Qualified profile URL for this user (including domain name)
-	EmailAddress         string                     `bson:"emailAddress"`         // Email address for this user
-	Username             string                     `bson:"username"`             // This is the primary public identifier for the user.
-	Password             string                     `bson:"password"`             // Hashed password. Only ever written via a PasswordHasher (see steranko.SetPassword); never contains plaintext.
-	Locale               string                     `bson:"locale"`               // Language code for this user's preferred language.
-	SignupNote           string                     `bson:"signupNote,omitempty"` // Note that was included when this user signed up.
-	StateID              string                     `bson:"stateId"`              // State ID for this user
-	InboxTemplate        string                     `bson:"inboxTemplate"`        // Template for the user's inbox
-	OutboxTemplate       string                     `bson:"outboxTemplate"`       // Template for the user's outbox
-	NoteTemplate         string                     `bson:"noteTemplate"`         // Template for generically created notes
-	Hashtags             sliceof.String             `bson:"hashtags"`             // Slice of tags that can be used to categorize this user.
-	TagURL               string                     `bson:"tagUrl"`               // URL prefix for hashtag links, denormalized from the outbox Template ("%23" + tag is appended).
-	Links                sliceof.Object[PersonLink] `bson:"links"`                // Slice of links to profiles on other web services.
-	NotificationChannels sliceof.String             `bson:"notificationChannels"` // Slice of ENABLED notification channel keys (see model.NotificationChannel* constants). Empty = all notifications off.
-	PasswordReset        PasswordReset              `bson:"passwordReset"`        // Most recent password reset information.
-	Data                 mapof.String               `bson:"data"`                 // Custom profile data that can be stored with this User.
-	ProfileFingerprint   string                     `bson:"profileFingerprint"`   // Hash of the last-saved actor document (GetJSONLD). User.Save compares it to detect profile changes that must federate as an ActivityPub Update.
-	MovedTo              string                     `bson:"movedTo,omitempty"`    // If present, this user has been moved to a new URL, and cannot sign in to this profile anymore.
-	FollowerCount        int                        `bson:"followerCount"`        // Number of followers for this user
-	FollowingCount       int                        `bson:"followingCount"`       // Number of actors that this user is following
-	RuleCount            int                        `bson:"ruleCount"`            // Number of rules (blocks) that this user has implemented
-	IsOwner              bool                       `bson:"isOwner"`              // If TRUE, then this user is a website owner with FULL privileges.
-	IsPublic             bool                       `bson:"isPublic"`             // If TRUE, then this user's profile is publicly visible
-	IsBridgeBluesky      delta.Bool                 `bson:"isBridgeBluesky"`      // If TRUE, then allow this user to be bridged to Bluesky
-	IsIndexable          bool                       `bson:"isIndexable"`          // If TRUE, then this user's profile can be indexed by search engines.
+	UserID               primitive.ObjectID          `bson:"_id"`                    // Unique identifier for this user.
+	MapIDs               mapof.String                `bson:"mapIds"`                 // Map of IDs for this user on other web services.
+	GroupIDs             id.Slice                    `bson:"groupIds"`               // Slice of IDs for the groups that this user belongs to.
+	IconID               primitive.ObjectID          `bson:"iconId"`                 // AttachmentID of this user's avatar/icon image.
+	ImageID              primitive.ObjectID          `bson:"imageId"`                // AttachmentID of this user's banner image.
+	DisplayName          string                      `bson:"displayName"`            // Name to be displayed for this user
+	StatusMessage        string                      `bson:"statusMessage"`          // Status summary for this user
+	Location             string                      `bson:"location"`               // Human-friendly description of this user's physical location.
+	ProfileURL           string                      `bson:"profileUrl"`             // Fully Qualified profile URL for this user (including domain name)
+	EmailAddress         string                      `bson:"emailAddress"`           // Email address for this user
+	Username             string                      `bson:"username"`               // This is the primary public identifier for the user.
+	Password             string                      `bson:"password"`               // Hashed password. Only ever written via a PasswordHasher (see steranko.SetPassword); never contains plaintext.
+	Locale               string                      `bson:"locale"`                 // Language code for this user's preferred language.
+	SignupNote           string                      `bson:"signupNote,omitempty"`   // Note that was included when this user signed up.
+	StateID              string                      `bson:"stateId"`                // State ID for this user
+	InboxTemplate        string                      `bson:"inboxTemplate"`          // Template for the user's inbox
+	OutboxTemplate       string                      `bson:"outboxTemplate"`         // Template for the user's outbox
+	NoteTemplate         string                      `bson:"noteTemplate"`           // Template for generically created notes
+	ArticleTemplate      string                      `bson:"articleTemplate"`        // Template for articles created by client apps (ActivityPub C2S)
+	OutboxFolderID       primitive.ObjectID          `bson:"outboxFolderId"`         // Outbox Stream (folder) that holds posts created by client apps.  Zero = the top of the outbox
+	Hashtags             sliceof.String              `bson:"hashtags"`               // Slice of tags that can be used to categorize this user.
+	TagURL               string                      `bson:"tagUrl"`                 // URL prefix for hashtag links, denormalized from the outbox Template ("%23" + tag is appended).
+	Links                sliceof.Object[PersonLink]  `bson:"links"`                  // Slice of links to profiles on other web services.
+	NotificationChannels sliceof.String              `bson:"notificationChannels"`   // Slice of ENABLED notification channel keys (see model.NotificationChannel* constants). Empty = all notifications off.
+	AccountNotes         sliceof.Object[AccountNote] `bson:"accountNotes,omitempty"` // Private notes that this user has written about other accounts.
+	Endorsements         sliceof.String              `bson:"endorsements,omitempty"` // URLs of accounts that this user features on their profile.
+	PasswordReset        PasswordReset               `bson:"passwordReset"`          // Most recent password reset information.
+	TwoFactor            TwoFactor                   `bson:"twoFactor"`              // TOTP second factor and recovery codes for this user.
+	Data                 mapof.String                `bson:"data"`                   // Custom profile data that can be stored with this User.
+	ProfileFingerprint   string                      `bson:"profileFingerprint"`     // Hash of the last-saved actor document (GetJSONLD). User.Save compares it to detect profile changes that must federate as an ActivityPub Update.
+	MovedTo              string                      `bson:"movedTo,omitempty"`      // If present, this user has been moved to a new URL, and cannot sign in to this profile anymore.
+	FollowerCount        int                         `bson:"followerCount"`          // Number of followers for this user
+	FollowingCount       int                         `bson:"followingCount"`         // Number of actors that this user is following
+	RuleCount            int                         `bson:"ruleCount"`              // Number of rules (blocks) that this user has implemented
+	IsOwner              bool                        `bson:"isOwner"`                // If TRUE, then this user is a website owner with FULL privileges.
+	IsPublic             bool                        `bson:"isPublic"`               // If TRUE, then this user's profile is publicly visible
+	IsBridgeBluesky      delta.Bool                  `bson:"isBridgeBluesky"`        // If TRUE, then allow this user to be bridged to Bluesky
+	IsIndexable          bool                        `bson:"isIndexable"`            // If TRUE, then this user's profile can be indexed by search engines.
+	IsLocked             bool                        `bson:"isLocked"`               // If TRUE, then this user approves new followers manually.
 
 	journal.Journal `json:"-" bson:",inline"`
 }
@@ -69,6 +77,7 @@ func NewUser() User {
 		Links:                sliceof.NewObject[PersonLink](),
 		Data:                 mapof.NewString(),
 		NotificationChannels: DefaultNotificationChannels(),
+		TwoFactor:            NewTwoFactor(),
 	}
 }
 
@@ -163,6 +172,65 @@ func (user *User) RemoveGroup(groupID primitive.ObjectID) {
 	}
 }
 
+/******************************************
+ * Account Notes & Endorsements
+ ******************************************/
+
+// AccountNote returns the private note that this user has written about the account at the provided URL
+func (user User) AccountNote(url string) string {
+
+	for _, accountNote := range user.AccountNotes {
+		if accountNote.URL == url {
+			return accountNote.Note
+		}
+	}
+
+	return ""
+}
+
+// SetAccountNote adds, updates, or (if the note is empty) removes this user's private note about an account
+func (user *User) SetAccountNote(url string, note string) {
+
+	for index, accountNote := range user.AccountNotes {
+		if accountNote.URL == url {
+
+			if note == "" {
+				user.AccountNotes = append(user.AccountNotes[:index], user.AccountNotes[index+1:]...)
+				return
+			}
+
+			user.AccountNotes[index].Note = note
+			return
+		}
+	}
+
+	if note != "" {
+		user.AccountNotes = append(user.AccountNotes, NewAccountNote(url, note))
+	}
+}
+
+// IsEndorsed returns TRUE if this user features the account at the provided URL on their profile
+func (user User) IsEndorsed(url string) bool {
+	return slices.Contains(user.Endorsements, url)
+}
+
+// AddEndorsement features an account on this user's profile, avoiding duplicates
+func (user *User) AddEndorsement(url string) {
+
+	if user.IsEndorsed(url) {
+		return
+	}
+
+	user.Endorsements = append(user.Endorsements, url)
+}
+
+// RemoveEndorsement removes an account from this user's featured accounts
+func (user *User) RemoveEndorsement(url string) {
+	user.Endorsements = slices.DeleteFunc(user.Endorsements, func(value string) bool {
+		return value == url
+	})
+}
+
 /******************************************
  * Steranko Interfaces
  ******************************************/
@@ -262,6 +330,7 @@ func (user User) GetJSONLD() mapof.Any {
 		vocab.ContextTypeSecurity,
 		vocab.ContextTypeToot,
 		vocab.ContextTypeSocialWebMLS,
+		multikey.Context,
 	}
 
 	exportURL := user.ActivityPubURL() + "/export"
@@ -276,6 +345,7 @@ func (user User) GetJSONLD() mapof.Any {
 		vocab.PropertyPreferredUsername: user.Username,
 		vocab.PropertyTootDiscoverable:  true,
 		vocab.PropertyTootIndexable:     user.IsIndexable,
+		"manuallyApprovesFollowers":     user.IsLocked,
 		vocab.PropertyInbox:             user.ActivityPubInboxURL(),
 		vocab.PropertyOutbox:            user.ActivityPubOutboxURL(),
 		vocab.PropertyFollowing:         user.ActivityPubFollowingURL(),
@@ -295,6 +365,7 @@ func (user User) GetJSONLD() mapof.Any {
 			vocab.EndpointStartMigration:     serverURL + "/@" + user.UserID.Hex() + "/export/start",
 			vocab.EndpointFinishMigration:    serverURL + "/@me/settings/export",
 			vocab.EndpointProxyURL:           serverURL + "/.proxy",
+			"sharedInbox":                    SharedInboxURL(user.ProfileURL),
 		},
 
 		vocab.PropertyMigration: mapof.String{
@@ -494,7 +565,8 @@ func (user *User) ActivityPubOutboxURL() string {
 	return user.ProfileURL + "/pub/outbox"
 }
 
-// ActivityPubPublicKeyURL returns the key ID ("#main-key" fragment URL) for this User's public key
+// ActivityPubPublicKeyURL returns the key ID ("#main-key" fragment URL) for this User's original public key.
+// Keys created by rotation have their own IDs, so signatures use service.User.SigningKey instead.
 func (user *User) ActivityPubPublicKeyURL() string {
 	if user.ProfileURL == "" {
 		return ""
@@ -566,6 +638,7 @@ func (user User) Toot() object.Account {
 		Avatar:       user.ActivityPubIconURL(),
 		Header:       user.ActivityPubImageURL(),
 		Discoverable: user.IsPublic,
+		Locked:       user.IsLocked,
 		CreatedAt:    time.UnixMilli(user.CreateDate).UTC().Format(time.RFC3339), // CreateDate is milliseconds (journal UnixMilli)
 	}
 }
@@ -595,6 +668,11 @@ func (user User) GetWebhookData() mapof.Any {
 	}
 }
 
+// WebhookUserID returns the ID of this User
+func (user User) WebhookUserID() primitive.ObjectID {
+	return user.UserID
+}
+
 /******************************************
  * Activity Intent Data
  ******************************************/
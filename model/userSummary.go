@@ -1,6 +1,7 @@
 package model
 
 import (
+	"github.com/benpate/form"
 	"github.com/benpate/rosetta/sliceof"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -46,3 +47,13 @@ func (userSummary UserSummary) IconURL() string {
 	}
 	return "/@" + userSummary.UserID.Hex() + "/attachments/" + userSummary.IconID.Hex()
 }
+
+// LookupCode returns a form.LookupCode that represents this User
+func (userSummary UserSummary) LookupCode() form.LookupCode {
+	return form.LookupCode{
+		Value:       userSummary.UserID.Hex(),
+		Label:       userSummary.DisplayName,
+		Description: "@" + userSummary.Username,
+		Icon:        "person",
+	}
+}
@@ -10,26 +10,29 @@ func UserSchema() schema.Element {
 
 	return schema.Object{
 		Properties: schema.ElementMap{
-			"userId":         schema.String{Format: "objectId"},
-			"mapIds":         schema.Object{Wildcard: schema.String{MaxLength: 256}},
-			"groupIds":       id.SliceSchema(),
-			"iconId":         schema.String{Format: "objectId"},
-			"imageId":        schema.String{Format: "objectId"},
-			"iconUrl":        schema.String{Format: "url"}, // This is my first attempt at a "virtual field"
-			"imageUrl":       schema.String{Format: "url"}, // This is my first attempt at a "virtual field"
-			"displayName":    schema.String{MaxLength: 64, Format: "no-html", Required: true},
-			"statusMessage":  schema.String{Format: "text", MaxLength: 2048},
-			"location":       schema.String{Format: "text", MaxLength: 64},
-			"links":          schema.Array{Items: PersonLinkSchema(), MaxLength: 6},
-			"profileUrl":     schema.String{Format: "url"},
-			"emailAddress":   schema.String{Format: "email", Required: true},
-			"username":       schema.String{MaxLength: 32, Format: "username", Required: true},
-			"locale":         schema.String{},
-			"signupNote":     schema.String{MaxLength: 256},
-			"stateId":        schema.String{},
-			"inboxTemplate":  schema.String{MaxLength: 128},
-			"outboxTemplate": schema.String{MaxLength: 128},
-			"hashtags":       schema.Array{Items: schema.String{Format: "token"}},
+			"userId":          schema.String{Format: "objectId"},
+			"mapIds":          schema.Object{Wildcard: schema.String{MaxLength: 256}},
+			"groupIds":        id.SliceSchema(),
+			"iconId":          schema.String{Format: "objectId"},
+			"imageId":         schema.String{Format: "objectId"},
+			"iconUrl":         schema.String{Format: "url"}, // This is my first attempt at a "virtual field"
+			"imageUrl":        schema.String{Format: "url"}, // This is my first attempt at a "virtual field"
+			"displayName":     schema.String{MaxLength: 64, Format: "no-html", Required: true},
+			"statusMessage":   schema.String{Format: "text", MaxLength: 2048},
+			"location":        schema.String{Format: "text", MaxLength: 64},
+			"links":           schema.Array{Items: PersonLinkSchema(), MaxLength: 6},
+			"profileUrl":      schema.String{Format: "url"},
+			"emailAddress":    schema.String{Format: "email", Required: true},
+			"username":        schema.String{MaxLength: 32, Format: "username", Required: true},
+			"locale":          schema.String{},
+			"signupNote":      schema.String{MaxLength: 256},
+			"stateId":         schema.String{},
+			"inboxTemplate":   schema.String{MaxLength: 128},
+			"outboxTemplate":  schema.String{MaxLength: 128},
+			"noteTemplate":    schema.String{MaxLength: 128},
+			"articleTemplate": schema.String{MaxLength: 128},
+			"outboxFolderId":  schema.String{Format: "objectId"},
+			"hashtags":        schema.Array{Items: schema.String{Format: "token"}},
 			"notificationChannels": schema.Array{Items: schema.String{Enum: []string{
 				NotificationChannelDirectMessage,
 				NotificationChannelMentionFollowing,
@@ -38,6 +41,8 @@ func UserSchema() schema.Element {
 				NotificationChannelFollow,
 				NotificationChannelReaction,
 			}}},
+			"accountNotes":    schema.Array{Items: AccountNoteSchema()},
+			"endorsements":    schema.Array{Items: schema.String{Format: "url"}},
 			"data":            schema.Object{Wildcard: schema.String{MaxLength: 4096}},
 			"movedTo":         schema.String{Format: "url"},
 			"followerCount":   schema.Integer{},
@@ -47,6 +52,7 @@ func UserSchema() schema.Element {
 			"isBridgeBluesky": schema.Boolean{},
 			"isOwner":         schema.Boolean{},
 			"isIndexable":     schema.Boolean{},
+			"isLocked":        schema.Boolean{},
 		},
 	}
 }
@@ -77,6 +83,9 @@ func (user *User) GetPointer(name string) (any, bool) {
 	case "isIndexable":
 		return &user.IsIndexable, true
 
+	case "isLocked":
+		return &user.IsLocked, true
+
 	case "followerCount":
 		return &user.FollowerCount, true
 
@@ -119,6 +128,12 @@ func (user *User) GetPointer(name string) (any, bool) {
 	case "outboxTemplate":
 		return &user.OutboxTemplate, true
 
+	case "noteTemplate":
+		return &user.NoteTemplate, true
+
+	case "articleTemplate":
+		return &user.ArticleTemplate, true
+
 	case "data":
 		return &user.Data, true
 
@@ -131,6 +146,12 @@ func (user *User) GetPointer(name string) (any, bool) {
 	case "notificationChannels":
 		return &user.NotificationChannels, true
 
+	case "accountNotes":
+		return &user.AccountNotes, true
+
+	case "endorsements":
+		return &user.Endorsements, true
+
 	default:
 		return nil, false
 	}
@@ -154,6 +175,12 @@ func (user *User) GetStringOK(name string) (string, bool) {
 	case "imageUrl":
 		return user.ActivityPubImageURL(), true
 
+	case "outboxFolderId":
+		if user.OutboxFolderID.IsZero() {
+			return "", true
+		}
+		return user.OutboxFolderID.Hex(), true
+
 	default:
 		return "", false
 	}
@@ -205,6 +232,18 @@ func (user *User) SetString(name string, value string) bool {
 			return true
 		}
 
+	case "outboxFolderId":
+
+		if value == "" {
+			user.OutboxFolderID = primitive.NilObjectID
+			return true
+		}
+
+		if objectID, err := primitive.ObjectIDFromHex(value); err == nil {
+			user.OutboxFolderID = objectID
+			return true
+		}
+
 	case "iconUrl":
 		return true // Fail silently, but do not set iconUrl from this string
 
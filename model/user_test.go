@@ -41,14 +41,21 @@ func TestUserSchema(t *testing.T) {
 		{"isBridgeBluesky", "true", true},
 		{"isOwner", "true", true},
 		{"isIndexable", "true", true},
+		{"isLocked", "true", true},
 		{"inboxTemplate", "INBOX", nil},
 		{"outboxTemplate", "OUTBOX", nil},
+		{"noteTemplate", "NOTE", nil},
+		{"articleTemplate", "ARTICLE", nil},
+		{"outboxFolderId", "000000000000000000000003", nil},
 		{"hashtags.0", "HEy", nil},
 		{"hashtags.1", "ThErE", nil},
 		{"hashtags.2", "bItChEs", nil},
 		{"notificationChannels.0", NotificationChannelFollow, nil},
 		{"notificationChannels.1", NotificationChannelReaction, nil},
 		{"movedTo", "https://moved.example/newhome", nil},
+		{"accountNotes.0.url", "https://example.com/@friend", nil},
+		{"accountNotes.0.note", "Met at the conference", nil},
+		{"endorsements.0", "https://example.com/@friend", nil},
 		{"data.ABC", "DATA-ABC", nil},
 		{"data.XYZ", "DATA-XYZ", nil},
 		{"mapIds.federated", "fed-id-123", nil},
@@ -106,6 +113,17 @@ func TestUserJSONLD(t *testing.T) {
 	require.NotNil(t, getter.GetJSONLD())
 }
 
+// TestUser_ManuallyApprovesFollowers confirms that locked accounts are advertised in the actor document
+func TestUser_ManuallyApprovesFollowers(t *testing.T) {
+	user := NewUser()
+	require.Equal(t, false, user.GetJSONLD()["manuallyApprovesFollowers"])
+	require.False(t, user.Toot().Locked)
+
+	user.IsLocked = true
+	require.Equal(t, true, user.GetJSONLD()["manuallyApprovesFollowers"])
+	require.True(t, user.Toot().Locked)
+}
+
 // TestUser_CalcProfileFingerprint pins the change-detection contract that drives profile
 // federation (PROFILE-UPDATE-FEDERATION.md D-2): identical profiles hash identically, every
 // field visible in GetJSONLD flips the fingerprint, and fields OUTSIDE the actor document
@@ -167,6 +185,7 @@ func TestUser_CalcProfileFingerprint(t *testing.T) {
 		u.Links = append(u.Links, PersonLink{Name: "Blog", ProfileURL: "https://blog.example.com"})
 	})
 	changes("isIndexable", func(u *User) { u.IsIndexable = true })
+	changes("isLocked", func(u *User) { u.IsLocked = true })
 
 	// Fields peers can NOT see -> fingerprint unchanged -> no Update on login-adjacent saves.
 	// (location and isPublic are deliberate: neither appears in GetJSONLD today. isPublic only
@@ -182,6 +201,7 @@ func TestUser_CalcProfileFingerprint(t *testing.T) {
 	same("location", func(u *User) { u.Location = "Underground Bunker" })
 	same("isPublic", func(u *User) { u.IsPublic = true })
 	same("profileFingerprint", func(u *User) { u.ProfileFingerprint = "feedface" })
+	same("accountNotes", func(u *User) { u.SetAccountNote("https://example.com/@friend", "private") })
 }
 
 // TestUser_HashedPasswordAccessors pins the steranko.User contract: these are dumb
@@ -195,3 +215,34 @@ func TestUser_HashedPasswordAccessors(t *testing.T) {
 	require.Equal(t, "$2a$12$not-really-a-hash-but-stored-verbatim", user.Password)
 	require.Equal(t, "$2a$12$not-really-a-hash-but-stored-verbatim", user.GetHashedPassword())
 }
+
+// TestUser_AccountNotes confirms that private notes are added, updated, and removed by URL.
+func TestUser_AccountNotes(t *testing.T) {
+
+	user := NewUser()
+	require.Equal(t, "", user.AccountNote("https://example.com/@friend"))
+
+	user.SetAccountNote("https://example.com/@friend", "first")
+	user.SetAccountNote("https://example.com/@other", "other")
+	user.SetAccountNote("https://example.com/@friend", "second")
+	require.Equal(t, 2, len(user.AccountNotes))
+	require.Equal(t, "second", user.AccountNote("https://example.com/@friend"))
+
+	user.SetAccountNote("https://example.com/@friend", "")
+	require.Equal(t, 1, len(user.AccountNotes))
+	require.Equal(t, "", user.AccountNote("https://example.com/@friend"))
+	require.Equal(t, "other", user.AccountNote("https://example.com/@other"))
+}
+
+// TestUser_Endorsements confirms that endorsements are de-duplicated and removable.
+func TestUser_Endorsements(t *testing.T) {
+
+	user := NewUser()
+	user.AddEndorsement("https://example.com/@friend")
+	user.AddEndorsement("https://example.com/@friend")
+	require.True(t, user.IsEndorsed("https://example.com/@friend"))
+	require.Equal(t, 1, len(user.Endorsements))
+
+	user.RemoveEndorsement("https://example.com/@friend")
+	require.False(t, user.IsEndorsed("https://example.com/@friend"))
+}
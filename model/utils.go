@@ -136,3 +136,15 @@ func notOneOf[T comparable](value T, options ...T) bool {
 func must[T any](value T, err error) T {
 	return value
 }
+
+// truncateString shortens a string to (at most) the provided number of runes
+func truncateString(value string, length int) string {
+
+	runes := []rune(value)
+
+	if len(runes) <= length {
+		return value
+	}
+
+	return string(runes[:length])
+}
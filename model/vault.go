@@ -206,6 +206,38 @@ func (vault Vault) Decrypt(encryptionKey []byte, values ...string) (mapof.String
 	return result, nil
 }
 
+// ReEncrypt decrypts all values in the vault with the `oldKey` and encrypts them again with
+// the `newKey`, using a new n-once.
+func (vault *Vault) ReEncrypt(oldKey []byte, newKey []byte) error {
+
+	const location = "model.vault.ReEncrypt"
+
+	// Nothing to do if the vault is empty
+	if (len(vault.Encrypted) == 0) && !vault.hasEncryptableValues() {
+		return nil
+	}
+
+	// Decrypt all existing values with the old key
+	values, err := vault.Decrypt(oldKey)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Decrypting vault")
+	}
+
+	// Encrypt everything again with the new key (and a new n-once)
+	vault.Encrypted = mapof.NewString()
+	vault.Nonce = ""
+	vault.plaintext = values
+
+	if err := vault.Encrypt(newKey); err != nil {
+		return derp.Wrap(err, location, "Encrypting vault")
+	}
+
+	// Remove plaintext values so that they are not encrypted again with the old key
+	vault.plaintext = mapof.NewString()
+	return nil
+}
+
 // hasEncryptableValue returns TRUE if there are any non-empty/non-obscured values in the vault
 // that should be encrypted
 func (vault Vault) hasEncryptableValues() bool {
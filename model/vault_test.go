@@ -51,3 +51,31 @@ func TestVault(t *testing.T) {
 	require.Equal(t, "ABCDEFGHIJKLMNOPQRSTUVWXYZ", decrypted["letters"])
 	require.Equal(t, "!@#$%^&*()", decrypted["symbols"])
 }
+
+func TestVault_ReEncrypt(t *testing.T) {
+
+	oldKey, _ := hex.DecodeString("6368616e676520746869732070617373776f726420746f206120736563726574")
+	newKey, _ := hex.DecodeString("6e6577206d6173746572206b657920666f722074686973207661756c74212121")
+
+	vault := NewVault()
+	vault.SetString("numbers", "1234567890")
+	require.Nil(t, vault.Encrypt(oldKey))
+
+	// Re-encrypt with the new key
+	require.Nil(t, vault.ReEncrypt(oldKey, newKey))
+
+	// Old key no longer works
+	_, err := vault.Decrypt(oldKey)
+	require.NotNil(t, err)
+
+	// New key does
+	values, err := vault.Decrypt(newKey)
+	require.Nil(t, err)
+	require.Equal(t, "1234567890", values["numbers"])
+
+	// Encrypting again (as Save does) with the old key changes nothing
+	require.Nil(t, vault.Encrypt(oldKey))
+	values, err = vault.Decrypt(newKey)
+	require.Nil(t, err)
+	require.Equal(t, "1234567890", values["numbers"])
+}
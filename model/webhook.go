@@ -1,6 +1,7 @@
 package model
 
 import (
+	"github.com/EmissarySocial/emissary/tools/id"
 	"github.com/benpate/data/journal"
 	"github.com/benpate/rosetta/sliceof"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -12,6 +13,8 @@ type Webhook struct {
 	Events          sliceof.String     `bson:"events"`
 	Label           string             `bson:"label"`
 	TargetURL       string             `bson:"targetUrl"`
+	Secret          string             `bson:"secret"`  // Shared secret used to sign (HMAC-SHA256) every payload sent to the TargetURL
+	UserIDs         id.Slice           `bson:"userIds"` // If present, only events that belong to these Users are sent.  If empty, events for the entire domain are sent.
 	journal.Journal `json:"-" bson:",inline"`
 }
 
@@ -20,11 +23,12 @@ func NewWebhook() Webhook {
 	return Webhook{
 		WebhookID: primitive.NewObjectID(),
 		Events:    sliceof.NewString(),
+		UserIDs:   id.NewSlice(),
 	}
 }
 
 func WebhookFields() []string {
-	return []string{"_id", "events", "label", "targetUrl"}
+	return []string{"_id", "events", "label", "targetUrl", "userIds"}
 }
 
 func (userSummary Webhook) Fields() []string {
@@ -36,6 +40,27 @@ func (webhook Webhook) ID() string {
 	return webhook.WebhookID.Hex()
 }
 
+// IsDomainWide returns TRUE if this Webhook receives events for every User on the domain
+func (webhook Webhook) IsDomainWide() bool {
+	return webhook.UserIDs.IsEmpty()
+}
+
+// MatchesUser returns TRUE if this Webhook should receive events that belong to the provided User.
+// Domain-wide webhooks match every event.  Per-user webhooks only match events for their selected
+// Users, so events that belong to the whole domain (a zero UserID) are never sent to them.
+func (webhook Webhook) MatchesUser(userID primitive.ObjectID) bool {
+
+	if webhook.IsDomainWide() {
+		return true
+	}
+
+	if userID.IsZero() {
+		return false
+	}
+
+	return webhook.UserIDs.Contains(userID)
+}
+
 /******************************************
  * AccessLister Interface
  ******************************************/
@@ -0,0 +1,121 @@
+package model
+
+import (
+	"github.com/benpate/data/journal"
+	"github.com/benpate/rosetta/sliceof"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WebhookDelivery is the delivery log for a single event sent to a single Webhook.  It stores the
+// exact payload that was signed and sent, along with a record of every attempt to deliver it, so
+// that Domain Owners can audit (and replay) what was sent to their external services.
+type WebhookDelivery struct {
+	WebhookDeliveryID primitive.ObjectID             `bson:"_id"`                // Unique ID for this delivery
+	WebhookID         primitive.ObjectID             `bson:"webhookId"`          // Webhook that this payload is delivered to
+	Event             string                         `bson:"event"`              // Event that triggered this delivery (e.g. "stream:publish")
+	TargetURL         string                         `bson:"targetUrl"`          // URL that receives the payload (copied from the Webhook when each attempt is made)
+	Body              string                         `bson:"body"`               // Exact JSON body that is signed and sent to the TargetURL
+	StateID           string                         `bson:"stateId"`            // Current state of this delivery (PENDING, SUCCESS, FAILURE)
+	Attempts          sliceof.Object[WebhookAttempt] `bson:"attempts"`           // Every attempt made to deliver this payload, oldest first
+	ReplayOf          primitive.ObjectID             `bson:"replayOf,omitempty"` // If this delivery was replayed by an admin, the ID of the original delivery
+
+	journal.Journal `json:"-" bson:",inline"`
+}
+
+// WebhookAttempt records the outcome of a single HTTP request made for a WebhookDelivery
+type WebhookAttempt struct {
+	AttemptDate int64  `bson:"attemptDate"`     // Unix epoch SECONDS when this attempt was made
+	StatusCode  int    `bson:"statusCode"`      // HTTP status code returned by the TargetURL (zero if the request never completed)
+	Duration    int64  `bson:"duration"`        // Time (in milliseconds) that the request took to complete
+	Error       string `bson:"error,omitempty"` // Human-readable error message (empty on success)
+}
+
+// NewWebhookDelivery returns a fully initialized WebhookDelivery object
+func NewWebhookDelivery() WebhookDelivery {
+	return WebhookDelivery{
+		WebhookDeliveryID: primitive.NewObjectID(),
+		StateID:           WebhookDeliveryStatePending,
+		Attempts:          sliceof.NewObject[WebhookAttempt](),
+	}
+}
+
+// ID returns the unique identifier for this WebhookDelivery, and is required to implement the data.Object interface
+func (delivery WebhookDelivery) ID() string {
+	return delivery.WebhookDeliveryID.Hex()
+}
+
+/******************************************
+ * Delivery State
+ ******************************************/
+
+// IsPending returns TRUE if this delivery has not yet succeeded, and may still be retried
+func (delivery WebhookDelivery) IsPending() bool {
+	return delivery.StateID == WebhookDeliveryStatePending
+}
+
+// IsSuccess returns TRUE if this delivery was accepted by the TargetURL
+func (delivery WebhookDelivery) IsSuccess() bool {
+	return delivery.StateID == WebhookDeliveryStateSuccess
+}
+
+// IsFailure returns TRUE if every attempt to deliver this payload has failed
+func (delivery WebhookDelivery) IsFailure() bool {
+	return delivery.StateID == WebhookDeliveryStateFailure
+}
+
+// AttemptCount returns the number of attempts made to deliver this payload
+func (delivery WebhookDelivery) AttemptCount() int {
+	return len(delivery.Attempts)
+}
+
+// LastAttempt returns the most recent attempt to deliver this payload (or an empty attempt if none have been made)
+func (delivery WebhookDelivery) LastAttempt() WebhookAttempt {
+
+	if len(delivery.Attempts) == 0 {
+		return WebhookAttempt{}
+	}
+
+	return delivery.Attempts[len(delivery.Attempts)-1]
+}
+
+// AddAttempt appends an attempt to the delivery log and recalculates the state of this delivery.
+// Successful attempts mark the delivery as SUCCESS.  Failed attempts leave the delivery PENDING
+// until WebhookDeliveryMaxAttempts have been made, after which it is marked as FAILURE.
+func (delivery *WebhookDelivery) AddAttempt(attempt WebhookAttempt) {
+
+	delivery.Attempts = append(delivery.Attempts, attempt)
+
+	switch {
+
+	case attempt.IsSuccess():
+		delivery.StateID = WebhookDeliveryStateSuccess
+
+	case delivery.AttemptCount() >= WebhookDeliveryMaxAttempts:
+		delivery.StateID = WebhookDeliveryStateFailure
+
+	default:
+		delivery.StateID = WebhookDeliveryStatePending
+	}
+}
+
+// RetryDelaySeconds returns the number of seconds to wait before the next attempt.
+// Delays grow exponentially (1m, 2m, 4m, 8m...) with each failed attempt.
+func (delivery WebhookDelivery) RetryDelaySeconds() int {
+
+	attempts := delivery.AttemptCount()
+
+	if attempts == 0 {
+		return 0
+	}
+
+	return WebhookDeliveryRetryBaseSeconds << (attempts - 1)
+}
+
+/******************************************
+ * Attempt Methods
+ ******************************************/
+
+// IsSuccess returns TRUE if the TargetURL accepted this attempt with a 2xx status code
+func (attempt WebhookAttempt) IsSuccess() bool {
+	return (attempt.StatusCode >= 200) && (attempt.StatusCode <= 299)
+}
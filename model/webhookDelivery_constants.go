@@ -0,0 +1,16 @@
+package model
+
+// WebhookDeliveryStatePending means that this payload has not yet been delivered, and will be (re)tried
+const WebhookDeliveryStatePending = "PENDING"
+
+// WebhookDeliveryStateSuccess means that this payload was accepted by the Webhook's TargetURL
+const WebhookDeliveryStateSuccess = "SUCCESS"
+
+// WebhookDeliveryStateFailure means that every attempt to deliver this payload has failed
+const WebhookDeliveryStateFailure = "FAILURE"
+
+// WebhookDeliveryMaxAttempts is the maximum number of times that a payload will be sent before giving up
+const WebhookDeliveryMaxAttempts = 8
+
+// WebhookDeliveryRetryBaseSeconds is the delay before the first retry.  Each subsequent retry doubles this delay.
+const WebhookDeliveryRetryBaseSeconds = 60
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDelivery_AddAttempt_Success(t *testing.T) {
+
+	delivery := NewWebhookDelivery()
+	require.True(t, delivery.IsPending())
+
+	delivery.AddAttempt(WebhookAttempt{StatusCode: 500, Error: "Internal Server Error"})
+	require.True(t, delivery.IsPending())
+	require.Equal(t, 1, delivery.AttemptCount())
+
+	delivery.AddAttempt(WebhookAttempt{StatusCode: 204})
+	require.True(t, delivery.IsSuccess())
+	require.Equal(t, 2, delivery.AttemptCount())
+	require.Equal(t, 204, delivery.LastAttempt().StatusCode)
+}
+
+func TestWebhookDelivery_AddAttempt_Failure(t *testing.T) {
+
+	delivery := NewWebhookDelivery()
+
+	for index := 1; index < WebhookDeliveryMaxAttempts; index++ {
+		delivery.AddAttempt(WebhookAttempt{StatusCode: 503})
+		require.True(t, delivery.IsPending())
+	}
+
+	// The final attempt exhausts all retries
+	delivery.AddAttempt(WebhookAttempt{Error: "connection refused"})
+	require.True(t, delivery.IsFailure())
+}
+
+func TestWebhookDelivery_RetryDelaySeconds(t *testing.T) {
+
+	delivery := NewWebhookDelivery()
+	require.Equal(t, 0, delivery.RetryDelaySeconds())
+
+	delivery.AddAttempt(WebhookAttempt{StatusCode: 500})
+	require.Equal(t, WebhookDeliveryRetryBaseSeconds, delivery.RetryDelaySeconds())
+
+	delivery.AddAttempt(WebhookAttempt{StatusCode: 500})
+	require.Equal(t, WebhookDeliveryRetryBaseSeconds*2, delivery.RetryDelaySeconds())
+
+	delivery.AddAttempt(WebhookAttempt{StatusCode: 500})
+	require.Equal(t, WebhookDeliveryRetryBaseSeconds*4, delivery.RetryDelaySeconds())
+}
+
+func TestWebhookAttempt_IsSuccess(t *testing.T) {
+	require.False(t, WebhookAttempt{}.IsSuccess())
+	require.True(t, WebhookAttempt{StatusCode: 200}.IsSuccess())
+	require.True(t, WebhookAttempt{StatusCode: 299}.IsSuccess())
+	require.False(t, WebhookAttempt{StatusCode: 301}.IsSuccess())
+	require.False(t, WebhookAttempt{StatusCode: 404}.IsSuccess())
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"github.com/EmissarySocial/emissary/tools/id"
 	"github.com/benpate/rosetta/schema"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -11,6 +12,8 @@ func WebhookSchema() schema.Element {
 			"webhookId": schema.String{Format: "objectId"},
 			"label":     schema.String{Format: "text", MaxLength: 64},
 			"targetUrl": schema.String{Format: "url"},
+			"secret":    schema.String{MaxLength: 128},
+			"userIds":   id.SliceSchema(),
 			"events": schema.Array{Items: schema.String{Enum: []string{
 				WebhookEventStreamCreate,
 				WebhookEventStreamUpdate,
@@ -22,6 +25,16 @@ func WebhookSchema() schema.Element {
 				WebhookEventStreamPublishUndo,
 				WebhookEventStreamSyndicate,
 				WebhookEventStreamSyndicateUndo,
+				WebhookEventFollowerCreate,
+				WebhookEventFollowerDelete,
+				WebhookEventResponseCreate,
+				WebhookEventResponseDelete,
+				WebhookEventPrivilegeCreate,
+				WebhookEventPrivilegeDelete,
+				WebhookEventMessageCreate,
+				WebhookEventRuleCreate,
+				WebhookEventRuleUpdate,
+				WebhookEventRuleDelete,
 			}}},
 		},
 	}
@@ -39,6 +52,12 @@ func (webhook *Webhook) GetPointer(name string) (any, bool) {
 
 	case "targetUrl":
 		return &webhook.TargetURL, true
+
+	case "secret":
+		return &webhook.Secret, true
+
+	case "userIds":
+		return &webhook.UserIDs, true
 	}
 
 	return nil, false
@@ -29,3 +29,33 @@ const WebhookEventStreamSyndicate = "stream:syndicate"
 
 // WebhookEventStreamSyndicateUndo is triggered when a Stream's syndication is undone
 const WebhookEventStreamSyndicateUndo = "stream:syndicate:undo"
+
+// WebhookEventFollowerCreate is triggered when a new Follower is added to a User or Stream
+const WebhookEventFollowerCreate = "follower:create"
+
+// WebhookEventFollowerDelete is triggered when a Follower is removed from a User or Stream
+const WebhookEventFollowerDelete = "follower:delete"
+
+// WebhookEventResponseCreate is triggered when a User likes, dislikes, or shares a document
+const WebhookEventResponseCreate = "response:create"
+
+// WebhookEventResponseDelete is triggered when a User's like, dislike, or share is undone
+const WebhookEventResponseDelete = "response:delete"
+
+// WebhookEventPrivilegeCreate is triggered when a purchase or Circle membership grants a new Privilege
+const WebhookEventPrivilegeCreate = "privilege:create"
+
+// WebhookEventPrivilegeDelete is triggered when a Privilege is cancelled or removed
+const WebhookEventPrivilegeDelete = "privilege:delete"
+
+// WebhookEventMessageCreate is triggered when a new message is received in a User's inbox
+const WebhookEventMessageCreate = "message:create"
+
+// WebhookEventRuleCreate is triggered when a new block, mute, or label Rule is created
+const WebhookEventRuleCreate = "rule:create"
+
+// WebhookEventRuleUpdate is triggered when an existing Rule is updated
+const WebhookEventRuleUpdate = "rule:update"
+
+// WebhookEventRuleDelete is triggered when an existing Rule is deleted
+const WebhookEventRuleDelete = "rule:delete"
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/benpate/rosetta/schema"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func TestWebhookSchema(t *testing.T) {
@@ -16,8 +18,31 @@ func TestWebhookSchema(t *testing.T) {
 		{"label", "WEBHOOK-LABEL", nil},
 		{"events.0", "user:create", nil},
 		{"events.1", "user:update", nil},
+		{"events.2", "follower:create", nil},
+		{"events.3", "rule:delete", nil},
 		{"targetUrl", "https://example.com/webhook", nil},
+		{"secret", "WEBHOOK-SECRET", nil},
+		{"userIds.0", "000000000000000000000002", nil},
 	}
 
 	tableTest_Schema(t, &s, &webhook, tests)
 }
+
+func TestWebhook_MatchesUser(t *testing.T) {
+
+	userID := primitive.NewObjectID()
+	otherID := primitive.NewObjectID()
+
+	// Domain-wide webhooks receive every event
+	webhook := NewWebhook()
+	require.True(t, webhook.IsDomainWide())
+	require.True(t, webhook.MatchesUser(userID))
+	require.True(t, webhook.MatchesUser(primitive.NilObjectID))
+
+	// Per-user webhooks only receive events for their selected Users
+	webhook.UserIDs.Append(userID)
+	require.False(t, webhook.IsDomainWide())
+	require.True(t, webhook.MatchesUser(userID))
+	require.False(t, webhook.MatchesUser(otherID))
+	require.False(t, webhook.MatchesUser(primitive.NilObjectID))
+}
@@ -3,6 +3,7 @@ package queries
 import (
 	"context"
 
+	"github.com/EmissarySocial/emissary/model"
 	"github.com/benpate/data"
 	"github.com/benpate/derp"
 	"github.com/benpate/exp"
@@ -13,7 +14,11 @@ import (
 // SetFollowersCount counts the number of Followers for a specific User and updates the User record.
 func SetFollowersCount(userCollection data.Collection, followersCollection data.Collection, userID primitive.ObjectID) error {
 
-	criteria := exp.Equal("parentId", userID).AndEqual("deleteDate", 0)
+	// Follow requests that have not been approved yet are not counted
+	criteria := exp.Equal("parentId", userID).
+		AndNotEqual("stateId", model.FollowerStateRequested).
+		AndEqual("deleteDate", 0)
+
 	followerCount, err := followersCollection.Count(criteria)
 
 	if err != nil {
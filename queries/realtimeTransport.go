@@ -0,0 +1,380 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/EmissarySocial/emissary/realtime"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// realtimeCollection is the capped collection that carries realtime messages between nodes
+const realtimeCollection = "Realtime"
+
+// realtimeCollectionSize is the maximum size (in bytes) of the realtime collection.
+// Messages are only read as they arrive, so older messages can be discarded freely.
+const realtimeCollectionSize = 16 * 1024 * 1024
+
+// realtimeRetryDelay is how long to wait before reconnecting after an error, or
+// before re-opening a tailable cursor that has been closed.
+const realtimeRetryDelay = time.Second
+
+// RealtimeTransport is a realtime.Transport that shares messages between nodes through a
+// capped MongoDB collection.  Every node inserts the messages it publishes, and receives
+// every node's messages through a change stream (on a replica set) or by tailing the
+// capped collection (on a standalone server).
+type RealtimeTransport struct {
+	server     data.Server
+	mode       string
+	mutex      sync.Mutex
+	collection *mongo.Collection // cached once the capped collection is known to exist
+}
+
+// realtimeDocument is the database representation of a realtime.Message
+type realtimeDocument struct {
+	DocumentID primitive.ObjectID `bson:"_id"`
+	ObjectID   primitive.ObjectID `bson:"objectId"`
+	Topic      int                `bson:"topic"`
+	Event      string             `bson:"event,omitempty"`
+	Data       string             `bson:"data,omitempty"`
+	Stream     []string           `bson:"stream,omitempty"`
+}
+
+// NewRealtimeTransport returns a RealtimeTransport that uses the provided database.  The mode
+// is one of realtime.TransportAuto, realtime.TransportChangeStream, or realtime.TransportCappedCollection.
+func NewRealtimeTransport(server data.Server, mode string) *RealtimeTransport {
+	return &RealtimeTransport{
+		server: server,
+		mode:   mode,
+	}
+}
+
+// Publish inserts a message into the realtime collection, which delivers it to every node
+func (transport *RealtimeTransport) Publish(message realtime.Message) error {
+
+	const location = "queries.RealtimeTransport.Publish"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection, err := transport.getCollection(ctx)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Connecting to realtime collection")
+	}
+
+	document := realtimeDocument{
+		DocumentID: primitive.NewObjectID(),
+		ObjectID:   message.ObjectID,
+		Topic:      message.Topic,
+		Event:      message.Event,
+		Data:       message.Data,
+		Stream:     message.Stream,
+	}
+
+	if _, err := collection.InsertOne(ctx, document); err != nil {
+		return derp.Wrap(err, location, "Inserting realtime message")
+	}
+
+	return nil
+}
+
+// Listen delivers every message that is inserted into the realtime collection until the
+// context is cancelled.  Errors are reported, and the connection is retried.
+func (transport *RealtimeTransport) Listen(ctx context.Context, deliver func(realtime.Message)) {
+
+	const location = "queries.RealtimeTransport.Listen"
+
+	mode := transport.mode
+
+	for ctx.Err() == nil {
+
+		collection, err := transport.getCollection(ctx)
+
+		if err != nil {
+			derp.Report(derp.Wrap(err, location, "Connecting to realtime collection"))
+			sleepContext(ctx, realtimeRetryDelay)
+			continue
+		}
+
+		if mode == realtime.TransportCappedCollection {
+			transport.tail(ctx, collection, deliver)
+			continue
+		}
+
+		err = transport.watch(ctx, collection, deliver)
+
+		// Fall back to a capped collection if the database is not a replica set
+		if (mode == realtime.TransportAuto) && isNotReplicaSet(err) {
+			log.Info().Str("loc", location).Msg("MongoDB is not a replica set. Tailing capped collection for realtime updates.")
+			mode = realtime.TransportCappedCollection
+			continue
+		}
+
+		if (err != nil) && (ctx.Err() == nil) {
+			derp.Report(derp.Wrap(err, location, "Watching realtime collection"))
+		}
+
+		sleepContext(ctx, realtimeRetryDelay)
+	}
+}
+
+// watch delivers messages from a change stream, which requires a replica set
+func (transport *RealtimeTransport) watch(ctx context.Context, collection *mongo.Collection, deliver func(realtime.Message)) error {
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"operationType": "insert"}}},
+	}
+
+	cs, err := collection.Watch(ctx, pipeline)
+
+	if err != nil {
+		return err
+	}
+
+	defer cs.Close(context.Background())
+
+	for cs.Next(ctx) {
+
+		var event struct {
+			Document realtimeDocument `bson:"fullDocument"`
+		}
+
+		if err := cs.Decode(&event); err != nil {
+			derp.Report(derp.Wrap(err, "queries.RealtimeTransport.watch", "Decoding realtime message"))
+			continue
+		}
+
+		deliver(event.Document.message())
+	}
+
+	return cs.Err()
+}
+
+// tail delivers messages from a tailable cursor on the capped collection, which works on
+// a standalone server.  The cursor reads the collection in natural (insertion) order, so it
+// does not depend on any node's clock.  It starts after the last message that existed when
+// we started listening, and tailable cursors that close are re-opened after the last message
+// received, until the context is cancelled.
+func (transport *RealtimeTransport) tail(ctx context.Context, collection *mongo.Collection, deliver func(realtime.Message)) {
+
+	const location = "queries.RealtimeTransport.tail"
+
+	// Only deliver messages that are inserted after we started listening
+	lastID, err := lastRealtimeDocumentID(ctx, collection)
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, location, "Finding last realtime message"))
+		sleepContext(ctx, realtimeRetryDelay)
+		return
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "$natural", Value: 1}}).
+		SetCursorType(options.TailableAwait).
+		SetMaxAwaitTime(realtimeRetryDelay)
+
+	for ctx.Err() == nil {
+
+		// Skip every message up to (and including) the last one received.  If that message
+		// has already been discarded from the capped collection, then every remaining
+		// message is newer, and nothing is skipped.
+		skipping, err := containsRealtimeDocument(ctx, collection, lastID)
+
+		if err != nil {
+			derp.Report(derp.Wrap(err, location, "Finding last realtime message", lastID))
+			sleepContext(ctx, realtimeRetryDelay)
+			continue
+		}
+
+		cursor, err := collection.Find(ctx, bson.M{}, opts)
+
+		if err != nil {
+			derp.Report(derp.Wrap(err, location, "Opening tailable cursor"))
+			sleepContext(ctx, realtimeRetryDelay)
+			continue
+		}
+
+		for cursor.Next(ctx) {
+
+			document := realtimeDocument{}
+
+			if err := cursor.Decode(&document); err != nil {
+				derp.Report(derp.Wrap(err, location, "Decoding realtime message"))
+				continue
+			}
+
+			if skipping {
+				skipping = (document.DocumentID != lastID)
+				continue
+			}
+
+			lastID = document.DocumentID
+			deliver(document.message())
+		}
+
+		if err := cursor.Err(); (err != nil) && (ctx.Err() == nil) {
+			derp.Report(derp.Wrap(err, location, "Reading tailable cursor"))
+		}
+
+		_ = cursor.Close(context.Background())
+		sleepContext(ctx, realtimeRetryDelay)
+	}
+}
+
+// lastRealtimeDocumentID returns the ID of the last message inserted into the capped
+// collection (in natural order), or a zero ID if the collection is empty.
+func lastRealtimeDocumentID(ctx context.Context, collection *mongo.Collection) (primitive.ObjectID, error) {
+
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "$natural", Value: -1}}).
+		SetProjection(bson.M{"_id": 1})
+
+	document := realtimeDocument{}
+
+	if err := collection.FindOne(ctx, bson.M{}, opts).Decode(&document); err != nil {
+
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return primitive.NilObjectID, nil
+		}
+
+		return primitive.NilObjectID, err
+	}
+
+	return document.DocumentID, nil
+}
+
+// containsRealtimeDocument returns TRUE if the capped collection still contains the message
+// with the provided ID.  A zero ID is never contained.
+func containsRealtimeDocument(ctx context.Context, collection *mongo.Collection, documentID primitive.ObjectID) (bool, error) {
+
+	if documentID.IsZero() {
+		return false, nil
+	}
+
+	count, err := collection.CountDocuments(ctx, bson.M{"_id": documentID}, options.Count().SetLimit(1))
+
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// getCollection returns the realtime collection, creating it as a capped collection if necessary.
+// The collection must exist before the first message is published, because inserting into a
+// missing collection would create it without a size limit.
+func (transport *RealtimeTransport) getCollection(ctx context.Context) (*mongo.Collection, error) {
+
+	const location = "queries.RealtimeTransport.getCollection"
+
+	transport.mutex.Lock()
+	defer transport.mutex.Unlock()
+
+	if transport.collection != nil {
+		return transport.collection, nil
+	}
+
+	session, err := transport.server.Session(ctx)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Opening database session")
+	}
+
+	collection := mongoCollection(session.Collection(realtimeCollection))
+
+	if collection == nil {
+		return nil, derp.Internal(location, "Database must be MongoDB")
+	}
+
+	opts := options.CreateCollection().
+		SetCapped(true).
+		SetSizeInBytes(realtimeCollectionSize)
+
+	// MongoDB error 48 indicates that the collection already exists
+	if err := collection.Database().CreateCollection(ctx, realtimeCollection, opts); err != nil {
+
+		var commandError mongo.CommandError
+
+		if !errors.As(err, &commandError) || (commandError.Code != 48) {
+			return nil, derp.Wrap(err, location, "Creating capped collection")
+		}
+	}
+
+	transport.collection = collection
+	return collection, nil
+}
+
+// message converts a realtimeDocument back into a realtime.Message
+func (document realtimeDocument) message() realtime.Message {
+	return realtime.Message{
+		ObjectID: document.ObjectID,
+		Topic:    document.Topic,
+		Event:    document.Event,
+		Data:     document.Data,
+		Stream:   document.Stream,
+	}
+}
+
+// IsReplicaSet returns TRUE if the database is a MongoDB replica set, which supports the
+// change streams used by the database watchers (WatchStreams, WatchUsers, and WatchImports).
+func IsReplicaSet(ctx context.Context, server data.Server) (bool, error) {
+
+	const location = "queries.IsReplicaSet"
+
+	session, err := server.Session(ctx)
+
+	if err != nil {
+		return false, derp.Wrap(err, location, "Opening database session")
+	}
+
+	collection := mongoCollection(session.Collection(realtimeCollection))
+
+	if collection == nil {
+		return false, derp.Internal(location, "Database must be MongoDB")
+	}
+
+	// Replica set members report the name of their set in the "hello" command
+	var result struct {
+		SetName string `bson:"setName"`
+	}
+
+	if err := collection.Database().RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&result); err != nil {
+		return false, derp.Wrap(err, location, "Running 'hello' command")
+	}
+
+	return result.SetName != "", nil
+}
+
+// isNotReplicaSet returns TRUE if the error is MongoDB error 40573, which
+// indicates that we're running on a single node, not a replica set.
+func isNotReplicaSet(err error) bool {
+
+	var commandError mongo.CommandError
+
+	if errors.As(err, &commandError) {
+		return commandError.Code == 40573
+	}
+
+	return false
+}
+
+// sleepContext waits for the provided duration, or until the context is cancelled
+func sleepContext(ctx context.Context, duration time.Duration) {
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
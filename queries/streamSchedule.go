@@ -0,0 +1,48 @@
+package queries
+
+import (
+	"context"
+	"math"
+
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ClaimScheduledStream atomically clears the schedule of a Stream that is due to be published.
+// It returns TRUE only for the one caller that removed the schedule, so that concurrent
+// publishers (the delayed task and the hourly sweep) never publish the same Stream twice.
+func ClaimScheduledStream(ctx context.Context, collection data.Collection, streamID primitive.ObjectID, publishDate int64) (bool, error) {
+
+	const location = "queries.ClaimScheduledStream"
+
+	// Guarantee that we're using MongoDB
+	mongo := mongoCollection(collection)
+
+	if mongo == nil {
+		return false, derp.Internal(location, "Database must be MongoDB")
+	}
+
+	// Only match the Stream if it is still scheduled for the same date
+	filter := bson.M{
+		"_id":             streamID,
+		"schedule.userId": bson.M{"$exists": true},
+		"publishDate":     publishDate,
+		"deleteDate":      0,
+	}
+
+	update := bson.M{
+		"$unset": bson.M{"schedule": ""},
+		"$set":   bson.M{"publishDate": int64(math.MaxInt64)},
+	}
+
+	// Execute the conditional update
+	result, err := mongo.UpdateOne(ctx, filter, update)
+
+	if err != nil {
+		return false, derp.Wrap(err, location, "Claiming scheduled stream", streamID)
+	}
+
+	return result.ModifiedCount == 1, nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/EmissarySocial/emissary/realtime"
 	"github.com/benpate/data"
 	"github.com/benpate/derp"
+	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -33,11 +34,12 @@ func WatchStreams(ctx context.Context, server data.Server, result chan<- realtim
 
 	if err != nil {
 
-		// MongoDB error 40573 indicates that we're running on a single node, not a replica set.
-		if commandError, ok := err.(mongo.CommandError); ok {
-			if commandError.Code == 40573 {
-				return
-			}
+		// Change streams require a replica set, so the factory only starts this watcher on one.
+		// On a standalone server, the Stream service publishes its changes through the
+		// realtime transport instead.
+		if isNotReplicaSet(err) {
+			log.Info().Str("loc", "queries.WatchStreams").Msg("MongoDB is not a replica set. Stream updates are published through the realtime transport.")
+			return
 		}
 
 		derp.Report(derp.Wrap(err, "queries.WatchStreams", "Opening Mongodb Change Stream"))
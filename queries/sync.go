@@ -74,6 +74,14 @@ func SyncDomainIndexes(connectionString string, databaseName string) error { //
 		derp.Report(err)
 	}
 
+	if err := sync.Blocklist(ctx, session); err != nil {
+		derp.Report(err)
+	}
+
+	if err := sync.Bookmark(ctx, session); err != nil {
+		derp.Report(err)
+	}
+
 	if err := sync.Circle(ctx, session); err != nil {
 		derp.Report(err)
 	}
@@ -102,10 +110,18 @@ func SyncDomainIndexes(connectionString string, databaseName string) error { //
 		derp.Report(err)
 	}
 
+	if err := sync.Filter(ctx, session); err != nil {
+		derp.Report(err)
+	}
+
 	if err := sync.Folder(ctx, session); err != nil {
 		derp.Report(err)
 	}
 
+	if err := sync.FollowedTag(ctx, session); err != nil {
+		derp.Report(err)
+	}
+
 	if err := sync.Follower(ctx, session); err != nil {
 		derp.Report(err)
 	}
@@ -158,10 +174,18 @@ func SyncDomainIndexes(connectionString string, databaseName string) error { //
 		derp.Report(err)
 	}
 
+	if err := sync.Passkey(ctx, session); err != nil {
+		derp.Report(err)
+	}
+
 	if err := sync.PushSubscription(ctx, session); err != nil {
 		derp.Report(err)
 	}
 
+	if err := sync.PollVote(ctx, session); err != nil {
+		derp.Report(err)
+	}
+
 	if err := sync.Privilege(ctx, session); err != nil {
 		derp.Report(err)
 	}
@@ -170,10 +194,18 @@ func SyncDomainIndexes(connectionString string, databaseName string) error { //
 		derp.Report(err)
 	}
 
+	if err := sync.Relay(ctx, session); err != nil {
+		derp.Report(err)
+	}
+
 	if err := sync.Response(ctx, session); err != nil {
 		derp.Report(err)
 	}
 
+	if err := sync.Report(ctx, session); err != nil {
+		derp.Report(err)
+	}
+
 	if err := sync.Rule(ctx, session); err != nil {
 		derp.Report(err)
 	}
@@ -210,6 +242,10 @@ func SyncDomainIndexes(connectionString string, databaseName string) error { //
 		derp.Report(err)
 	}
 
+	if err := sync.WebhookDelivery(ctx, session); err != nil {
+		derp.Report(err)
+	}
+
 	log.Debug().Msg("Finished syncing indexes for: " + databaseName)
 
 	return nil
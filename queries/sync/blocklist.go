@@ -0,0 +1,28 @@
+package sync
+
+import (
+	"context"
+
+	"github.com/EmissarySocial/emissary/tools/indexer"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func Blocklist(ctx context.Context, database *mongo.Database) error {
+
+	log.Trace().Str("database", database.Name()).Str("collection", "Blocklist").Msg("COLLECTION:")
+
+	return indexer.Sync(ctx, database.Collection("Blocklist"), indexer.IndexSet{
+
+		// idx_Blocklist_Recycle serves the nightly RecycleDomain purge (deleteDate > 0).
+		"idx_Blocklist_Recycle": recycleIndex(),
+
+		// idx_Blocklist_URL serves the daily refresh, which loads every subscription
+		"idx_Blocklist_URL": mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "url", Value: 1},
+			},
+		},
+	})
+}
@@ -0,0 +1,44 @@
+package sync
+
+import (
+	"context"
+
+	"github.com/EmissarySocial/emissary/tools/indexer"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func Filter(ctx context.Context, database *mongo.Database) error {
+
+	log.Trace().Str("database", database.Name()).Str("collection", "Filter").Msg("COLLECTION:")
+
+	return indexer.Sync(ctx, database.Collection("Filter"), indexer.IndexSet{
+
+		// idx_Filter_Recycle serves the nightly RecycleDomain purge (deleteDate > 0).
+		"idx_Filter_Recycle": recycleIndex(),
+
+		// Serves the read-time lookup of a User's active Filters for a single context (QueryActive)
+		"idx_Filter_User_Context": mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "contexts", Value: 1},
+			},
+		},
+
+		// Serves the Mastodon API's keyword and status endpoints, which address a Filter by a child ID
+		"idx_Filter_User_Keyword": mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "keywords.keywordId", Value: 1},
+			},
+		},
+
+		"idx_Filter_User_Status": mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "statuses.statusId", Value: 1},
+			},
+		},
+	})
+}
@@ -0,0 +1,36 @@
+package sync
+
+import (
+	"context"
+
+	"github.com/EmissarySocial/emissary/tools/indexer"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func FollowedTag(ctx context.Context, database *mongo.Database) error {
+
+	log.Trace().Str("database", database.Name()).Str("collection", "FollowedTag").Msg("COLLECTION:")
+
+	return indexer.Sync(ctx, database.Collection("FollowedTag"), indexer.IndexSet{
+
+		// idx_FollowedTag_Recycle serves the nightly RecycleDomain purge (deleteDate > 0).
+		"idx_FollowedTag_Recycle": recycleIndex(),
+
+		// idx_FollowedTag_User lists a User's tags, and finds duplicates when following
+		"idx_FollowedTag_User": mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "value", Value: 1},
+			},
+		},
+
+		// idx_FollowedTag_Value finds every User who follows the tags on an incoming post
+		"idx_FollowedTag_Value": mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "value", Value: 1},
+			},
+		},
+	})
+}
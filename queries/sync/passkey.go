@@ -0,0 +1,42 @@
+package sync
+
+import (
+	"context"
+
+	"github.com/EmissarySocial/emissary/tools/indexer"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func Passkey(ctx context.Context, database *mongo.Database) error {
+
+	log.Trace().Str("database", database.Name()).Str("collection", "Passkey").Msg("COLLECTION:")
+
+	return indexer.Sync(ctx, database.Collection("Passkey"), indexer.IndexSet{
+
+		// idx_Passkey_Recycle serves the nightly RecycleDomain purge (deleteDate > 0).
+		"idx_Passkey_Recycle": recycleIndex(),
+
+		"idx_Passkey_User": mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "createDate", Value: 1},
+			},
+			Options: options.Index().
+				SetPartialFilterExpression(bson.M{"deleteDate": 0}),
+		},
+
+		// RULE: A credential can only be registered to one User.  Passwordless signins look up
+		// the passkey by this value alone, so it must never be ambiguous.
+		"idx_Passkey_Credential": mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "credentialId", Value: 1},
+			},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"deleteDate": 0}),
+		},
+	})
+}
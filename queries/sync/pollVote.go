@@ -0,0 +1,43 @@
+package sync
+
+import (
+	"context"
+
+	"github.com/EmissarySocial/emissary/tools/indexer"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func PollVote(ctx context.Context, database *mongo.Database) error {
+
+	log.Trace().Str("database", database.Name()).Str("collection", "PollVote").Msg("COLLECTION:")
+
+	return indexer.Sync(ctx, database.Collection("PollVote"), indexer.IndexSet{
+
+		// idx_PollVote_Recycle serves the nightly RecycleDomain purge (deleteDate > 0).
+		"idx_PollVote_Recycle": recycleIndex(),
+
+		// Enforces one PollVote per (pollUrl, voterUrl), so that nobody can vote twice,
+		// and serves the tally query (by pollUrl).
+		"idx_PollVote_Poll_Voter": mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "pollUrl", Value: 1},
+				{Key: "voterUrl", Value: 1},
+			},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"deleteDate": 0}),
+		},
+
+		// Serves DeleteByUserID
+		"idx_PollVote_User": mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+			},
+			Options: options.Index().
+				SetPartialFilterExpression(bson.M{"userId": bson.M{"$exists": true}}),
+		},
+	})
+}
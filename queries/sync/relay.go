@@ -0,0 +1,35 @@
+package sync
+
+import (
+	"context"
+
+	"github.com/EmissarySocial/emissary/tools/indexer"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func Relay(ctx context.Context, database *mongo.Database) error {
+
+	log.Trace().Str("database", database.Name()).Str("collection", "Relay").Msg("COLLECTION:")
+
+	return indexer.Sync(ctx, database.Collection("Relay"), indexer.IndexSet{
+
+		// idx_Relay_Recycle serves the nightly RecycleDomain purge (deleteDate > 0).
+		"idx_Relay_Recycle": recycleIndex(),
+
+		// idx_Relay_ActorURL identifies the relay that sent an inbound Announce or Follow
+		"idx_Relay_ActorURL": mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "actorUrl", Value: 1},
+			},
+		},
+
+		// idx_Relay_Hostname identifies the relay that forwarded an inbound Create
+		"idx_Relay_Hostname": mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "hostname", Value: 1},
+			},
+		},
+	})
+}
@@ -0,0 +1,36 @@
+package sync
+
+import (
+	"context"
+
+	"github.com/EmissarySocial/emissary/tools/indexer"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func Report(ctx context.Context, database *mongo.Database) error {
+
+	log.Trace().Str("database", database.Name()).Str("collection", "Report").Msg("COLLECTION:")
+
+	return indexer.Sync(ctx, database.Collection("Report"), indexer.IndexSet{
+
+		// idx_Report_Recycle serves the nightly RecycleDomain purge (deleteDate > 0).
+		"idx_Report_Recycle": recycleIndex(),
+
+		// idx_Report_Status serves the admin moderation queue (newest first within each status)
+		"idx_Report_Status": mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "createDate", Value: -1},
+			},
+		},
+
+		// idx_Report_Activity de-duplicates retried deliveries of the same Flag activity
+		"idx_Report_Activity": mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "activityId", Value: 1},
+			},
+		},
+	})
+}
@@ -43,5 +43,14 @@ func Rule(ctx context.Context, database *mongo.Database) error {
 			},
 			Options: options.Index().SetUnique(true),
 		},
+
+		// Serves Blocklist diffs and unsubscribes, which load every Rule that a subscription manages.
+		// Sparse because most Rules were not created by a Blocklist.
+		"idx_Rule_Blocklist": mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "blocklistId", Value: 1},
+			},
+			Options: options.Index().SetSparse(true),
+		},
 	})
 }
@@ -26,6 +26,16 @@ func SearchResult(ctx context.Context, database *mongo.Database) error {
 			Options: options.Index().SetUnique(true),
 		},
 
+		// idx_SearchResult_Relay serves the cleanup when a Relay is unsubscribed
+		"idx_SearchResult_Relay": mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "relayId", Value: 1},
+			},
+			Options: options.Index().SetPartialFilterExpression(bson.M{
+				"relayId": bson.M{"$exists": true},
+			}),
+		},
+
 		"idx_SearchResult_Notified": mongo.IndexModel{
 			Keys: bson.D{
 				{Key: "notifiedDate", Value: 1},
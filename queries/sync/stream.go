@@ -52,6 +52,16 @@ func Stream(ctx context.Context, database *mongo.Database) error {
 				SetPartialFilterExpression(bson.M{"deleteDate": 0}),
 		},
 
+		// Serves the Mastodon "scheduled statuses" list and the hourly sweep for due
+		// scheduled Streams.  Sparse because most Streams are never scheduled.
+		"idx_Stream_Schedule": mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "schedule.userId", Value: 1},
+				{Key: "publishDate", Value: 1},
+			},
+			Options: options.Index().SetSparse(true),
+		},
+
 		"idx_Stream_Privileges": mongo.IndexModel{
 			Keys: bson.D{
 				{Key: "privilegeIds", Value: 1},
@@ -0,0 +1,33 @@
+package sync
+
+import (
+	"context"
+
+	"github.com/EmissarySocial/emissary/tools/indexer"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func WebhookDelivery(ctx context.Context, database *mongo.Database) error {
+
+	log.Trace().Str("database", database.Name()).Str("collection", "WebhookDelivery").Msg("COLLECTION:")
+
+	return indexer.Sync(ctx, database.Collection("WebhookDelivery"), indexer.IndexSet{
+
+		// idx_WebhookDelivery_Webhook serves the admin delivery log (newest first)
+		"idx_WebhookDelivery_Webhook": mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "webhookId", Value: 1},
+				{Key: "createDate", Value: -1},
+			},
+		},
+
+		// idx_WebhookDelivery_CreateDate serves the nightly PurgeWebhookDeliveries task
+		"idx_WebhookDelivery_CreateDate": mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "createDate", Value: 1},
+			},
+		},
+	})
+}
@@ -13,7 +13,9 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func UpgradeMongoDB(connectionString string, databaseName string, domain *model.Domain) error {
+// UpgradeMongoDB runs all pending database upgrades for a domain.  The domain's masterKey
+// is required by upgrades that encrypt data at rest.
+func UpgradeMongoDB(connectionString string, databaseName string, masterKey string, domain *model.Domain) error {
 
 	const location = "queries.UpgradeMongoDB"
 
@@ -48,6 +50,7 @@ func UpgradeMongoDB(connectionString string, databaseName string, domain *model.
 		upgrades.Version27,
 		upgrades.Version28,
 		upgrades.Version29,
+		upgrades.Version30(masterKey),
 	}
 
 	// If we're already at the target database version or higher, then skip any other work
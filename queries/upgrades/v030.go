@@ -0,0 +1,96 @@
+package upgrades
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/derp"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Version30 encrypts every EncryptionKey's private key with the domain's master key.  Until now,
+// private keys were stored in plaintext, so anyone with a copy of the database could sign as
+// every actor on the server.
+//
+// It is idempotent: keys that are already encrypted are left untouched, so a second pass never
+// double-encrypts (and never corrupts) an account's identity key.
+func Version30(masterKey string) func(context.Context, *mongo.Database) error {
+
+	return func(ctx context.Context, session *mongo.Database) error {
+
+		const location = "queries.upgrades.Version30"
+
+		fmt.Println("... Version 30")
+
+		encryptionKey, err := hex.DecodeString(masterKey)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Invalid master key")
+		}
+
+		keyCollection := session.Collection("EncryptionKey")
+		cursor, err := keyCollection.Find(ctx, bson.M{})
+
+		if err != nil {
+			return derp.Wrap(err, location, "Retrieving keys iterator")
+		}
+
+		defer cursor.Close(ctx)
+
+		for cursor.Next(ctx) {
+
+			key := model.NewEncryptionKey()
+
+			if err := cursor.Decode(&key); err != nil {
+				return derp.Wrap(err, location, "Decoding key record")
+			}
+
+			changed, err := encryptKeyRecord(&key, encryptionKey)
+
+			if err != nil {
+				return derp.Wrap(err, location, "Encrypting key", key.EncryptionKeyID)
+			}
+
+			if !changed {
+				continue
+			}
+
+			filter := bson.M{"_id": key.EncryptionKeyID}
+			update := bson.M{"$set": bson.M{
+				"encoding":   key.Encoding,
+				"nonce":      key.Nonce,
+				"privatePEM": key.PrivatePEM,
+				"updateDate": time.Now().UnixMilli(),
+			}}
+
+			if _, err := keyCollection.UpdateOne(ctx, filter, update); err != nil {
+				return derp.Wrap(err, location, "Updating key record", key.EncryptionKeyID)
+			}
+
+			fmt.Print(".")
+		}
+
+		return cursor.Err()
+	}
+}
+
+// encryptKeyRecord encrypts a single plaintext EncryptionKey with the master key. It returns
+// FALSE (and makes no changes) when the key is already encrypted. Pure (no database) so the
+// decision is unit-testable.
+func encryptKeyRecord(key *model.EncryptionKey, masterKey []byte) (bool, error) {
+
+	// IDEMPOTENT: Leave keys that are already encrypted exactly as they are
+	if key.IsEncrypted() {
+		return false, nil
+	}
+
+	if err := key.SetPrivatePEM(key.PrivatePEM, masterKey); err != nil {
+		return false, derp.Wrap(err, "queries.upgrades.encryptKeyRecord", "Encrypting private key", key.EncryptionKeyID)
+	}
+
+	return true, nil
+}
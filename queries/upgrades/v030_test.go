@@ -0,0 +1,57 @@
+package upgrades
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/stretchr/testify/require"
+)
+
+// A plaintext key is encrypted, and still decrypts to the original private key.
+func TestEncryptKeyRecord_Plaintext(t *testing.T) {
+
+	masterKey := bytes.Repeat([]byte{7}, 32)
+
+	key := model.NewEncryptionKey()
+	key.Encoding = model.EncryptionKeyEncodingPlaintext
+	key.PrivatePEM = "PRIVATE KEY"
+
+	changed, err := encryptKeyRecord(&key, masterKey)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.True(t, key.IsEncrypted())
+
+	privatePEM, err := key.GetPrivatePEM(masterKey)
+	require.NoError(t, err)
+	require.Equal(t, "PRIVATE KEY", privatePEM)
+}
+
+// Records that predate the "encoding" field are treated as plaintext.
+func TestEncryptKeyRecord_MissingEncoding(t *testing.T) {
+
+	masterKey := bytes.Repeat([]byte{7}, 32)
+
+	key := model.NewEncryptionKey()
+	key.PrivatePEM = "PRIVATE KEY"
+
+	changed, err := encryptKeyRecord(&key, masterKey)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.True(t, key.IsEncrypted())
+}
+
+// A second pass leaves an already-encrypted key byte-for-byte untouched.
+func TestEncryptKeyRecord_Idempotent(t *testing.T) {
+
+	masterKey := bytes.Repeat([]byte{7}, 32)
+
+	key := model.NewEncryptionKey()
+	require.NoError(t, key.SetPrivatePEM("PRIVATE KEY", masterKey))
+	before := key
+
+	changed, err := encryptKeyRecord(&key, masterKey)
+	require.NoError(t, err)
+	require.False(t, changed)
+	require.Equal(t, before, key)
+}
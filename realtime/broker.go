@@ -1,9 +1,12 @@
 package realtime
 
 import (
+	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/benpate/derp"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -29,6 +32,20 @@ type Broker struct {
 	// Channel that users/streams are pushed into when they change.
 	updateChannel chan Message
 
+	// Channel for messages that every node generates on its own (like database
+	// change watchers), which are delivered to this node's clients only.
+	localChannel chan Message
+
+	// transport carries messages between nodes.  A nil transport delivers every
+	// message to this node's clients only.  Guarded by transportMutex.
+	transport       Transport
+	cancelTransport context.CancelFunc
+	transportMutex  sync.Mutex
+
+	// databaseWatchers is TRUE when this node watches the database for changes to
+	// Streams, Users, and Imports directly (MongoDB change streams on a replica set).
+	databaseWatchers atomic.Bool
+
 	// Channel into which new clients can be pushed
 	AddClient chan *Client
 
@@ -47,6 +64,7 @@ func NewBroker(updateChannel chan Message) *Broker {
 		clients:       make(map[primitive.ObjectID]*Client),
 		objects:       make(map[primitive.ObjectID]map[primitive.ObjectID]*Client),
 		updateChannel: updateChannel,
+		localChannel:  make(chan Message, 256),
 
 		AddClient:    make(chan *Client),
 		RemoveClient: make(chan *Client),
@@ -68,9 +86,49 @@ func (b *Broker) Refresh() {
 
 // Stop closes the broker
 func (b *Broker) Close() {
+	b.SetTransport(nil)
 	close(b.close)
 }
 
+// SetTransport replaces the Transport that carries messages between nodes, and
+// starts listening to it.  A nil Transport delivers messages to this node only.
+func (b *Broker) SetTransport(transport Transport) {
+
+	b.transportMutex.Lock()
+	defer b.transportMutex.Unlock()
+
+	// Stop listening to the previous transport
+	if b.cancelTransport != nil {
+		b.cancelTransport()
+		b.cancelTransport = nil
+	}
+
+	b.transport = transport
+
+	if transport == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b.cancelTransport = cancel
+
+	go transport.Listen(ctx, b.deliver)
+}
+
+// LocalChannel returns a channel for messages that are delivered to this node's
+// clients only.  Use it for messages that every node generates independently, such
+// as database change watchers, so that they are not duplicated by the Transport.
+func (b *Broker) LocalChannel() chan<- Message {
+	return b.localChannel
+}
+
+// SetDatabaseWatchers records whether this node watches the database for changes directly.
+// When it does not (on a standalone MongoDB server), change messages sent with SendChange are
+// shared with every node through the Transport instead.
+func (b *Broker) SetDatabaseWatchers(active bool) {
+	b.databaseWatchers.Store(active)
+}
+
 /******************************************
  * Listen/Modify Methods
  ******************************************/
@@ -118,6 +176,12 @@ func (b *Broker) listen() {
 
 		case message := <-b.updateChannel:
 
+			// Publish in a separate goroutine so a slow transport never blocks
+			// this connect/disconnect loop.
+			go b.publish(message)
+
+		case message := <-b.localChannel:
+
 			// Deliver in a separate goroutine so a slow client (or the NewReplies delay
 			// inside notifySSE) never blocks this connect/disconnect loop.
 			go b.notifySSE(message)
@@ -128,12 +192,50 @@ func (b *Broker) listen() {
 	}
 }
 
-// Send delivers a message to every matching SSE client immediately, on the
+// Send publishes a message to every matching SSE client on every node, on the
 // caller's goroutine.  It is the direct entry point for post-commit publishers
 // (the "PublishRealtimeMessage" inline task); the updateChannel path remains
 // for services that still send mid-transaction.
 func (b *Broker) Send(message Message) {
-	b.notifySSE(message)
+	b.publish(message)
+}
+
+// SendChange publishes a message that a database record (Stream, User, or Import) has
+// changed.  When this node's database watchers are running, every node already sees the
+// change on its own, so the message is dropped to avoid delivering it twice.
+func (b *Broker) SendChange(message Message) {
+
+	if b.databaseWatchers.Load() {
+		return
+	}
+
+	b.publish(message)
+}
+
+// publish sends a message through the Transport, which delivers it back to every
+// node (including this one).  If there is no Transport, or it fails, the message is
+// delivered to this node's clients so that single-node installations keep working.
+func (b *Broker) publish(message Message) {
+
+	b.transportMutex.Lock()
+	transport := b.transport
+	b.transportMutex.Unlock()
+
+	if transport == nil {
+		b.notifySSE(message)
+		return
+	}
+
+	if err := transport.Publish(message); err != nil {
+		derp.Report(derp.Wrap(err, "realtime.Broker.publish", "Publishing message to transport. Delivering locally instead."))
+		b.notifySSE(message)
+	}
+}
+
+// deliver receives messages from the Transport.  Delivery runs in a separate
+// goroutine so that the NewReplies delay never blocks the Transport.
+func (b *Broker) deliver(message Message) {
+	go b.notifySSE(message)
 }
 
 // notifySSE sends a message to every SSE client watching the message's object on a matching topic.
@@ -153,7 +255,7 @@ func (b *Broker) notifySSE(message Message) {
 	recipients := make([]*Client, 0, len(b.objects[message.ObjectID]))
 
 	for _, client := range b.objects[message.ObjectID] {
-		if (client.Topic == TopicAll) || (client.Topic == message.Topic) {
+		if client.Accepts(message) {
 			recipients = append(recipients, client)
 		}
 	}
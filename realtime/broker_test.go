@@ -129,3 +129,72 @@ func TestBroker_TopicFilter(t *testing.T) {
 		// Success: nothing delivered.
 	}
 }
+
+// TestBroker_StreamingTopic confirms that Mastodon streaming API messages reach clients
+// that subscribe to TopicStreaming, but never browser clients that watch TopicAll.
+func TestBroker_StreamingTopic(t *testing.T) {
+
+	updateChannel := make(chan Message)
+	broker := NewBroker(updateChannel)
+	defer broker.Close()
+
+	userID := primitive.NewObjectID()
+	browser := NewClient(nil, userID, TopicAll)
+	streaming := NewClient(nil, userID, TopicStreaming)
+
+	broker.AddClient <- browser
+	broker.AddClient <- streaming
+	updateChannel <- NewMessage_Streaming(userID, []string{StreamUser}, StreamingEventUpdate, `{"id":"1"}`)
+
+	select {
+	case message := <-streaming.WriteChannel:
+		if message.Event != StreamingEventUpdate {
+			t.Fatalf("expected %q event, got %q", StreamingEventUpdate, message.Event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for streaming delivery")
+	}
+
+	select {
+	case message := <-browser.WriteChannel:
+		t.Fatalf("browser client received a streaming message: %+v", message)
+	case <-time.After(100 * time.Millisecond):
+		// Success: nothing delivered.
+	}
+}
+
+// TestBroker_SendChange confirms that change messages are delivered when this node does not
+// watch the database, and dropped when the database watchers already deliver them.
+func TestBroker_SendChange(t *testing.T) {
+
+	broker := NewBroker(make(chan Message))
+	defer broker.Close()
+
+	streamID := primitive.NewObjectID()
+	client := NewClient(nil, streamID, TopicAll)
+
+	broker.AddClient <- client
+
+	// Database watchers are running: the watcher delivers this change, not SendChange
+	broker.SetDatabaseWatchers(true)
+	broker.SendChange(NewMessage_Updated(streamID))
+
+	select {
+	case message := <-client.WriteChannel:
+		t.Fatalf("client received a change that the database watchers deliver: %+v", message)
+	default:
+	}
+
+	// Standalone database: SendChange delivers the change itself
+	broker.SetDatabaseWatchers(false)
+	broker.SendChange(NewMessage_Updated(streamID))
+
+	select {
+	case message := <-client.WriteChannel:
+		if message.ObjectID != streamID {
+			t.Fatalf("expected a message for %s, got %s", streamID.Hex(), message.ObjectID.Hex())
+		}
+	default:
+		t.Fatal("SendChange returned without delivering to the client's buffer")
+	}
+}
@@ -33,3 +33,14 @@ func NewClient(request *http.Request, streamID primitive.ObjectID, topic int) *C
 		WriteChannel: make(chan Message, writeChannelBuffer),
 	}
 }
+
+// Accepts returns TRUE if this Client is subscribed to the topic of the provided Message.
+// Browser clients that watch TopicAll do not receive Mastodon streaming API messages.
+func (client *Client) Accepts(message Message) bool {
+
+	if client.Topic == message.Topic {
+		return true
+	}
+
+	return (client.Topic == TopicAll) && (message.Topic != TopicStreaming)
+}
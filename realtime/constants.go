@@ -29,3 +29,8 @@ const TopicInboxActivity_DirectMessage_MLS = 7
 
 // TopicNotification is triggered when a User receives a new Notification (mention, reply, like, follow, etc.)
 const TopicNotification = 9
+
+// TopicStreaming is triggered for Mastodon streaming API events (update, notification, delete, status.update).
+// It is never included in TopicAll, because these messages carry full JSON payloads for API clients,
+// not "something changed" nudges for browsers.
+const TopicStreaming = 10
@@ -8,6 +8,7 @@ type Message struct {
 	Topic    int
 	Event    string
 	Data     string
+	Stream   []string // Mastodon streaming API stream (e.g. ["user"] or ["hashtag", "cats"]). Only used by TopicStreaming
 }
 
 // NewMessage_ChildUpdated creates a new SSE message sent when a Stream's child has been updated
@@ -90,6 +91,18 @@ func NewMessage_NewReplies(objectID primitive.ObjectID) Message {
 	}
 }
 
+// NewMessage_Streaming creates a new Mastodon streaming API message.  User streams are published
+// under the User's ID, and domain-wide streams (public, hashtag) are published under the zero ObjectID.
+func NewMessage_Streaming(objectID primitive.ObjectID, stream []string, event string, payload string) Message {
+	return Message{
+		ObjectID: objectID,
+		Topic:    TopicStreaming,
+		Event:    event,
+		Data:     payload,
+		Stream:   stream,
+	}
+}
+
 // NewMessage_Updated creates a new SSE message sent when a User or Stream that has been updated
 func NewMessage_Updated(objectID primitive.ObjectID) Message {
 	return Message{
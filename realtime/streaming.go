@@ -0,0 +1,45 @@
+package realtime
+
+import "strings"
+
+// https://docs.joinmastodon.org/methods/streaming/#streams
+
+// StreamUser is the Mastodon stream for a User's home timeline and notifications
+const StreamUser = "user"
+
+// StreamUserNotification is the Mastodon stream for a User's notifications
+const StreamUserNotification = "user:notification"
+
+// StreamPublic is the Mastodon stream for all public posts on this server
+const StreamPublic = "public"
+
+// StreamHashtag is the Mastodon stream for public posts with a particular hashtag
+const StreamHashtag = "hashtag"
+
+// StreamList is the Mastodon stream for a User's list (Folder)
+const StreamList = "list"
+
+// https://docs.joinmastodon.org/methods/streaming/#events
+
+// StreamingEventUpdate is sent when a new status appears in a timeline.  Its payload is the Status JSON.
+const StreamingEventUpdate = "update"
+
+// StreamingEventStatusUpdate is sent when a status has been edited.  Its payload is the Status JSON.
+const StreamingEventStatusUpdate = "status.update"
+
+// StreamingEventDelete is sent when a status has been removed.  Its payload is the Status ID.
+const StreamingEventDelete = "delete"
+
+// StreamingEventNotification is sent when a User receives a new notification.  Its payload is the Notification JSON.
+const StreamingEventNotification = "notification"
+
+// HashtagStream returns the stream identifier for a hashtag.  Hashtags are
+// case-insensitive, and may be provided with or without a leading "#".
+func HashtagStream(tag string) []string {
+	return []string{StreamHashtag, strings.ToLower(strings.TrimPrefix(tag, "#"))}
+}
+
+// ListStream returns the stream identifier for a list
+func ListStream(listID string) []string {
+	return []string{StreamList, listID}
+}
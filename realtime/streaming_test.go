@@ -0,0 +1,17 @@
+package realtime
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestHashtagStream confirms that hashtag streams are case-insensitive and ignore a leading "#",
+// so that subscribers and publishers always agree on the stream identifier.
+func TestHashtagStream(t *testing.T) {
+
+	for _, tag := range []string{"cats", "#cats", "Cats", "#CATS"} {
+		if stream := HashtagStream(tag); !slices.Equal(stream, []string{"hashtag", "cats"}) {
+			t.Fatalf("unexpected stream for %q: %v", tag, stream)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package realtime
+
+import "context"
+
+// TransportAuto uses MongoDB change streams when the database is a replica set,
+// and falls back to tailing a capped collection on a standalone server.
+const TransportAuto = "AUTO"
+
+// TransportChangeStream uses MongoDB change streams, which require a replica set.
+const TransportChangeStream = "CHANGE-STREAM"
+
+// TransportCappedCollection tails a MongoDB capped collection, which works on a standalone server.
+const TransportCappedCollection = "CAPPED-COLLECTION"
+
+// TransportMemory delivers messages within a single process.  It is only suitable for
+// single-node installations and for tests.
+const TransportMemory = "MEMORY"
+
+// Transport carries realtime Messages between every node that serves a domain, so that
+// a Message published on one node reaches the SSE clients on all of them.
+type Transport interface {
+
+	// Publish sends a Message to every node, including this one.
+	Publish(message Message) error
+
+	// Listen calls `deliver` for every Message published on any node.  It blocks until
+	// the context is cancelled.
+	Listen(ctx context.Context, deliver func(Message))
+}
@@ -0,0 +1,54 @@
+package realtime
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryTransport is a Transport that delivers Messages to every Broker in the same
+// process.  Brokers that share a MemoryTransport behave like nodes that share a database.
+type MemoryTransport struct {
+	mutex     sync.RWMutex
+	listeners map[*memoryListener]struct{}
+}
+
+// memoryListener is a single call to MemoryTransport.Listen
+type memoryListener struct {
+	deliver func(Message)
+}
+
+// NewMemoryTransport returns a fully initialized MemoryTransport
+func NewMemoryTransport() *MemoryTransport {
+	return &MemoryTransport{
+		listeners: make(map[*memoryListener]struct{}),
+	}
+}
+
+// Publish sends a Message to every current listener
+func (transport *MemoryTransport) Publish(message Message) error {
+
+	transport.mutex.RLock()
+	defer transport.mutex.RUnlock()
+
+	for listener := range transport.listeners {
+		listener.deliver(message)
+	}
+
+	return nil
+}
+
+// Listen calls `deliver` for every Message published until the context is cancelled
+func (transport *MemoryTransport) Listen(ctx context.Context, deliver func(Message)) {
+
+	listener := &memoryListener{deliver: deliver}
+
+	transport.mutex.Lock()
+	transport.listeners[listener] = struct{}{}
+	transport.mutex.Unlock()
+
+	<-ctx.Done()
+
+	transport.mutex.Lock()
+	delete(transport.listeners, listener)
+	transport.mutex.Unlock()
+}
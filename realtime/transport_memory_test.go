@@ -0,0 +1,171 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// waitForListeners blocks until the transport has the expected number of listeners,
+// because Broker.SetTransport starts listening in a separate goroutine.
+func waitForListeners(t *testing.T, transport *MemoryTransport, count int) {
+
+	deadline := time.Now().Add(time.Second)
+
+	for time.Now().Before(deadline) {
+
+		transport.mutex.RLock()
+		current := len(transport.listeners)
+		transport.mutex.RUnlock()
+
+		if current == count {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatalf("timed out waiting for %d transport listeners", count)
+}
+
+// addClient registers a client with a broker, and waits until it is ready to receive
+// messages.  AddClient only hands the client to the broker's listen() goroutine, and
+// messages from a Transport are delivered on other goroutines.
+func addClient(t *testing.T, broker *Broker, client *Client) {
+
+	broker.AddClient <- client
+
+	deadline := time.Now().Add(time.Second)
+
+	for time.Now().Before(deadline) {
+
+		broker.mutex.RLock()
+		_, ok := broker.clients[client.ClientID]
+		broker.mutex.RUnlock()
+
+		if ok {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatal("timed out waiting for client to register")
+}
+
+// TestMemoryTransport_MultiNode confirms that a message published on one node
+// reaches the clients that are attached to every other node.
+func TestMemoryTransport_MultiNode(t *testing.T) {
+
+	transport := NewMemoryTransport()
+
+	nodeA := NewBroker(make(chan Message))
+	defer nodeA.Close()
+	nodeA.SetTransport(transport)
+
+	nodeB := NewBroker(make(chan Message))
+	defer nodeB.Close()
+	nodeB.SetTransport(transport)
+
+	waitForListeners(t, transport, 2)
+
+	userID := primitive.NewObjectID()
+	clientA := NewClient(nil, userID, TopicNotification)
+	clientB := NewClient(nil, userID, TopicNotification)
+
+	addClient(t, nodeA, clientA)
+	addClient(t, nodeB, clientB)
+
+	nodeA.Send(NewMessage_Notification(userID))
+
+	for _, client := range []*Client{clientA, clientB} {
+		select {
+		case message := <-client.WriteChannel:
+			if message.Topic != TopicNotification {
+				t.Fatalf("expected TopicNotification (%d), got %d", TopicNotification, message.Topic)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for delivery on every node")
+		}
+	}
+
+	// Each node receives the message exactly once
+	select {
+	case message := <-clientA.WriteChannel:
+		t.Fatalf("publishing node received a duplicate message: %+v", message)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// TestMemoryTransport_LocalChannel confirms that messages sent to the local channel
+// are not shared with other nodes.
+func TestMemoryTransport_LocalChannel(t *testing.T) {
+
+	transport := NewMemoryTransport()
+
+	nodeA := NewBroker(make(chan Message))
+	defer nodeA.Close()
+	nodeA.SetTransport(transport)
+
+	nodeB := NewBroker(make(chan Message))
+	defer nodeB.Close()
+	nodeB.SetTransport(transport)
+
+	waitForListeners(t, transport, 2)
+
+	streamID := primitive.NewObjectID()
+	clientA := NewClient(nil, streamID, TopicUpdated)
+	clientB := NewClient(nil, streamID, TopicUpdated)
+
+	addClient(t, nodeA, clientA)
+	addClient(t, nodeB, clientB)
+
+	nodeA.LocalChannel() <- NewMessage_Updated(streamID)
+
+	select {
+	case <-clientA.WriteChannel:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for local delivery")
+	}
+
+	select {
+	case message := <-clientB.WriteChannel:
+		t.Fatalf("local message was delivered to another node: %+v", message)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// failingTransport is a Transport that can never publish
+type failingTransport struct{}
+
+func (failingTransport) Publish(Message) error {
+	return errors.New("transport unavailable")
+}
+
+func (failingTransport) Listen(ctx context.Context, _ func(Message)) {
+	<-ctx.Done()
+}
+
+// TestBroker_TransportFailure confirms that messages are still delivered to this
+// node's clients when the transport cannot publish them.
+func TestBroker_TransportFailure(t *testing.T) {
+
+	broker := NewBroker(make(chan Message))
+	defer broker.Close()
+	broker.SetTransport(failingTransport{})
+
+	userID := primitive.NewObjectID()
+	client := NewClient(nil, userID, TopicNotification)
+
+	addClient(t, broker, client)
+	broker.Send(NewMessage_Notification(userID))
+
+	select {
+	case <-client.WriteChannel:
+	default:
+		t.Fatal("Send returned without delivering to the client's buffer")
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/EmissarySocial/emissary/handler/unsplash"
 	mw "github.com/EmissarySocial/emissary/middleware"
 	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/realtime"
 	"github.com/EmissarySocial/emissary/server"
 	derpconsole "github.com/EmissarySocial/emissary/tools/derp-console"
 	"github.com/benpate/derp"
@@ -209,6 +210,7 @@ func makeSetupRoutes(factory *server.SetupFactory, e *echo.Echo) {
 	e.POST("/domains/:domain", handler.SetupDomainPost(factory))
 	e.DELETE("/domains/:domain", handler.SetupDomainDelete(factory))
 	e.POST("/domains/:domain/signin", handler.SetupDomainSigninPost(factory))
+	e.POST("/domains/:domain/master-key", handler.SetupDomainMasterKeyPost(factory))
 	e.GET("/domains/:domain/users", handler.SetupDomainUsersGet(factory, setupTemplates))
 	e.POST("/domains/:domain/users", handler.SetupDomainUserPost(factory, setupTemplates))
 	e.POST("/domains/:domain/users/:user/invite", handler.SetupDomainUserInvite(factory, setupTemplates))
@@ -329,6 +331,13 @@ func makeStandardRoutes(factory *server.Factory, e *echo.Echo) {
 	// Authentication Pages
 	e.GET("/signin", handler.WithFactory(factory, handler.GetSignIn))
 	e.POST("/signin", handler.WithFactory(factory, handler.PostSignIn))
+	e.GET("/signin/2fa", handler.WithFactory(factory, handler.GetSignInTwoFactor))
+	e.POST("/signin/2fa", handler.WithFactory(factory, handler.PostSignInTwoFactor))
+	e.GET("/signin/2fa/qrcode", handler.WithFactory(factory, handler.GetSignInTwoFactorQRCode))
+	e.GET("/signin/2fa/passkey", handler.WithFactory(factory, handler.GetSignInTwoFactorPasskey))
+	e.POST("/signin/2fa/passkey", handler.WithFactory(factory, handler.PostSignInTwoFactorPasskey))
+	e.GET("/signin/passkey", handler.WithFactory(factory, handler.GetSignInPasskey))
+	e.POST("/signin/passkey", handler.WithFactory(factory, handler.PostSignInPasskey))
 	e.GET("/signin/reset", handler.WithFactory(factory, handler.GetResetPassword))
 	e.POST("/signin/reset", handler.WithFactory(factory, handler.PostResetPassword))
 	e.GET("/signin/reset-code", handler.WithFactory(factory, handler.GetResetCode))
@@ -395,6 +404,7 @@ func makeStandardRoutes(factory *server.Factory, e *echo.Echo) {
 	// Profile Pages for "me" only routes
 	e.GET("/@me", handler.WithAuthenticatedUser(factory, handler.ForwardMeURLs))
 	e.POST("/@me/delete", handler.WithAuthenticatedUser(factory, handler.PostProfileDelete))
+	e.GET("/@me/two-factor/qrcode", handler.WithAuthenticatedUser(factory, handler.GetQRCode_TwoFactor))
 
 	e.GET("/@me/conversations", handler.WithAuthenticatedUser(factory, handler.GetConversations))
 	e.POST("/@me/conversations", handler.WithAuthenticatedUser(factory, handler.PostConversations))
@@ -448,6 +458,9 @@ func makeStandardRoutes(factory *server.Factory, e *echo.Echo) {
 	e.GET("/@guest/signin/:jwt", handler.WithFactory(factory, handler.GetIdentitySigninWithJWT))
 	e.POST("/@guest/identifier", handler.WithIdentity(factory, handler.PostIdentityIdentifier))
 
+	// Shared Inbox (ActivityPub)
+	e.POST(model.SharedInboxPath, handler.WithFactory(factory, ap_domain.PostSharedInbox))
+
 	// Global Search Actor (ActivityPub)
 	e.GET("/@search", handler.WithFactory(factory, ap_domain.GetJSONLD))
 	e.POST("/@search/pub/followers", handler.WithFactory(factory, handler.GetEmptyCollection))
@@ -509,7 +522,7 @@ func makeStandardRoutes(factory *server.Factory, e *echo.Echo) {
 	e.POST("/@:userId/pub/inbox", handler.WithUser(factory, ap_user.PostInbox))
 	e.GET("/@:userId/pub/keyPackages", handler.WithUser(factory, ap_user.GetKeyPackageCollection))
 	e.GET("/@:userId/pub/keyPackages/:keyPackageId", handler.WithUser(factory, ap_user.GetKeyPackageRecord))
-	e.GET("/@:userId/pub/objects", handler.WithUser(factory, ap_user.GetObjectsCollection))
+	e.GET("/@:userId/pub/objects", handler.WithActorAndUser(factory, ap_user.GetObjectsCollection))
 	e.GET("/@:userId/pub/objects/:objectId", handler.WithActor(factory, ap_user.GetObject))
 	e.GET("/@:userId/pub/outbox", handler.WithUser(factory, ap_user.GetOutboxCollection))
 	e.POST("/@:userId/pub/outbox", handler.WithAuthenticatedUser(factory, ap_user.PostOutbox))
@@ -524,6 +537,7 @@ func makeStandardRoutes(factory *server.Factory, e *echo.Echo) {
 
 	// Domain Admin Pages
 	e.GET("/admin", handler.RedirectTo("/admin/domain/index"))
+	e.GET("/admin/blocklists/export.csv", handler.WithOwner(factory, handler.GetBlocklistExport))
 	e.GET("/admin/:param1", handler.WithOwner(factory, handler.GetAdmin))
 	e.POST("/admin/:param1", handler.WithOwner(factory, handler.PostAdmin))
 	e.GET("/admin/:param1/:param2", handler.WithOwner(factory, handler.GetAdmin))
@@ -555,6 +569,24 @@ func makeStandardRoutes(factory *server.Factory, e *echo.Echo) {
 
 	// Mastodon API
 	// toot.Register(e, handler.Mastodon(factory))
+
+	// Mastodon Media API (multipart uploads are not handled by toot)
+	e.POST("/api/v1/media", handler.WithFactory(factory, handler.PostMastodonMedia))
+	e.POST("/api/v2/media", handler.WithFactory(factory, handler.PostMastodonMediaV2))
+	e.GET("/api/v1/media/:id", handler.WithFactory(factory, handler.GetMastodonMedia))
+	e.PUT("/api/v1/media/:id", handler.WithFactory(factory, handler.PutMastodonMedia))
+
+	// Mastodon Statuses API (scheduled statuses return a ScheduledStatus, which toot's PostStatus can not)
+	e.POST("/api/v1/statuses", handler.PostMastodonStatus(factory))
+
+	// Mastodon Streaming API
+	e.GET("/api/v1/streaming/health", handler.GetMastodonStreamingHealth)
+	e.GET("/api/v1/streaming", handler.WithFactory(factory, handler.GetMastodonStreaming))
+	e.GET("/api/v1/streaming/user", handler.WithFactory(factory, handler.GetMastodonStreamingSSE(realtime.StreamUser)))
+	e.GET("/api/v1/streaming/user/notification", handler.WithFactory(factory, handler.GetMastodonStreamingSSE(realtime.StreamUserNotification)))
+	e.GET("/api/v1/streaming/public", handler.WithFactory(factory, handler.GetMastodonStreamingSSE(realtime.StreamPublic)))
+	e.GET("/api/v1/streaming/hashtag", handler.WithFactory(factory, handler.GetMastodonStreamingSSE(realtime.StreamHashtag)))
+	e.GET("/api/v1/streaming/list", handler.WithFactory(factory, handler.GetMastodonStreamingSSE(realtime.StreamList)))
 }
 
 /******************************************
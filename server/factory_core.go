@@ -14,6 +14,7 @@ import (
 	"github.com/EmissarySocial/emissary/config"
 	"github.com/EmissarySocial/emissary/consumer"
 	"github.com/EmissarySocial/emissary/queries"
+	"github.com/EmissarySocial/emissary/realtime"
 	"github.com/EmissarySocial/emissary/service"
 	derpconsole "github.com/EmissarySocial/emissary/tools/derp-console"
 	derpmongo "github.com/EmissarySocial/emissary/tools/derp-mongo"
@@ -111,6 +112,17 @@ func (factory *factoryCore) AllowPrivateIPs() bool {
 	return factory.config.AllowPrivateIPs
 }
 
+// RealtimeTransport returns the method used to share realtime updates between server nodes.
+// Empty values (from older configurations) are treated as realtime.TransportAuto.
+func (factory *factoryCore) RealtimeTransport() string {
+
+	if factory.config.RealtimeTransport == "" {
+		return realtime.TransportAuto
+	}
+
+	return factory.config.RealtimeTransport
+}
+
 // UpdateConfig updates the configuration for the Factory
 func (factory *factoryCore) UpdateConfig(value config.Config) error {
 
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/EmissarySocial/emissary/tools/httpsig"
+	"github.com/EmissarySocial/emissary/tools/integrity"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/uri"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// activityPubDeliveryTimeout caps how long a single inbox delivery may run.
+const activityPubDeliveryTimeout = 30 * time.Second
+
+// activityPubSchemeTTL is how long we remember which signature scheme a remote host accepts.
+// After it passes, hosts that only accepted draft-cavage are offered RFC 9421 again.
+const activityPubSchemeTTL = 24 * time.Hour
+
+// activityPubResponseMaxLength caps how much of an inbox's error response is included in errors
+const activityPubResponseMaxLength = 256
+
+// ActivityPubDelivery service POSTs activities to remote ActivityPub inboxes.  Every activity
+// carries an Object Integrity Proof (FEP-8b32) from the actor's Ed25519 key.  Every request is
+// signed with RFC 9421 HTTP Message Signatures (using the actor's Ed25519 key) first, and is
+// re-sent with a draft-cavage signature (using the actor's RSA key) if the inbox responds 401.
+// The scheme that each host accepts is cached, so that the second "knock" is rarely needed.
+type ActivityPubDelivery struct {
+	locatorService *Locator
+	host           string
+	hostname       string
+	httpClient     *http.Client
+	schemes        *httpsig.SchemeCache
+}
+
+// NewActivityPubDelivery returns a new instance of the ActivityPubDelivery service
+func NewActivityPubDelivery() ActivityPubDelivery {
+	return ActivityPubDelivery{
+		schemes: httpsig.NewSchemeCache(activityPubSchemeTTL),
+	}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+func (service *ActivityPubDelivery) Refresh(factory *Factory) {
+	service.locatorService = factory.Locator()
+	service.host = factory.Host()
+	service.hostname = factory.Hostname()
+
+	// Deliveries follow the same network policy as document loading: a production
+	// instance may only reach public addresses, while a local/dev instance may talk to itself.
+	service.httpClient = webPushHTTPClient(uri.IsLocalHostname(service.hostname))
+	service.httpClient.Timeout = activityPubDeliveryTimeout
+}
+
+/******************************************
+ * Delivery Methods
+ ******************************************/
+
+// Send signs and POSTs an activity to a single inbox on behalf of a local actor.
+// Errors include the HTTP status code of the inbox's response (if there is one)
+// so that callers can tell temporary failures from permanent ones.
+func (service *ActivityPubDelivery) Send(session data.Session, actorURL string, inboxURL string, activity mapof.Any) error {
+
+	const location = "service.ActivityPubDelivery.Send"
+
+	keys, err := service.signingKeys(session, actorURL)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Loading signing keys", actorURL)
+	}
+
+	// Attach an Object Integrity Proof (FEP-8b32) so that the activity can be verified
+	// without re-fetching it, even after it has been forwarded by another server.
+	secured, err := keys.prove(activity, time.Now())
+
+	if err != nil {
+		return derp.Wrap(err, location, "Signing activity", actorURL)
+	}
+
+	body, err := json.Marshal(secured)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Encoding activity", actorURL)
+	}
+
+	target, err := url.Parse(inboxURL)
+
+	if err != nil || target.Host == "" {
+		return derp.BadRequest(location, "Invalid inbox URL", inboxURL)
+	}
+
+	now := time.Now()
+	scheme := service.schemes.Get(target.Host, now)
+	statusCode, err := service.post(inboxURL, body, keys, scheme)
+
+	// Double-knock: retry with draft-cavage if the inbox rejected our RFC 9421 signature
+	if (scheme == httpsig.SchemeRFC9421) && (statusCode == http.StatusUnauthorized) {
+		scheme = httpsig.SchemeCavage
+		statusCode, err = service.post(inboxURL, body, keys, scheme)
+	}
+
+	if err != nil {
+		return derp.Wrap(err, location, "Delivering activity", inboxURL)
+	}
+
+	// Remember which scheme this host accepted
+	service.schemes.Set(target.Host, scheme, now)
+
+	log.Trace().Str("scheme", string(scheme)).Int("status", statusCode).Msg("ActivityPub activity delivered to " + inboxURL)
+	return nil
+}
+
+// post makes a single signed request to an inbox, and returns the response status code.
+// Non-2xx responses return an error that carries the same status code.
+func (service *ActivityPubDelivery) post(inboxURL string, body []byte, keys activityPubSigningKeys, scheme httpsig.Scheme) (int, error) {
+
+	const location = "service.ActivityPubDelivery.post"
+
+	request, err := http.NewRequest(http.MethodPost, inboxURL, bytes.NewReader(body))
+
+	if err != nil {
+		return 0, derp.Wrap(err, location, "Creating request", inboxURL)
+	}
+
+	request.Header.Set("Content-Type", vocab.ContentTypeActivityPub)
+	request.Header.Set("Accept", vocab.ContentTypeActivityPub)
+	request.Header.Set("User-Agent", "Emissary (https://"+service.hostname+")")
+
+	// Sign the request
+	if err := keys.sign(request, body, scheme, time.Now()); err != nil {
+		return 0, derp.Wrap(err, location, "Signing request", inboxURL)
+	}
+
+	// Fall back to a guarded client if the service was never refreshed (fail closed, not open).
+	client := service.httpClient
+	if client == nil {
+		client = webPushHTTPClient(false)
+	}
+
+	response, err := client.Do(request)
+
+	if err != nil {
+		return 0, derp.Wrap(err, location, "Sending request", inboxURL, derp.WithCode(http.StatusBadGateway))
+	}
+
+	defer func() {
+		if err := response.Body.Close(); err != nil {
+			derp.Report(derp.Wrap(err, location, "Closing response body", inboxURL))
+		}
+	}()
+
+	if (response.StatusCode < 200) || (response.StatusCode > 299) {
+		excerpt, _ := io.ReadAll(io.LimitReader(response.Body, activityPubResponseMaxLength))
+		err := errors.New(response.Status + " " + string(excerpt))
+		return response.StatusCode, derp.Wrap(err, location, "Inbox rejected activity", inboxURL, string(scheme), derp.WithCode(response.StatusCode))
+	}
+
+	return response.StatusCode, nil
+}
+
+// signingKeys loads the RSA and Ed25519 keys for a local actor
+func (service *ActivityPubDelivery) signingKeys(session data.Session, actorURL string) (activityPubSigningKeys, error) {
+
+	const location = "service.ActivityPubDelivery.signingKeys"
+
+	actorType, token := locateObjectFromURL(service.host, actorURL)
+
+	if actorType == "" {
+		return activityPubSigningKeys{}, derp.NotFound(location, "Actor is not local", actorURL)
+	}
+
+	// Domain-level actors (@search, @application) carry no ID
+	actorID := primitive.NilObjectID
+
+	if token != "" {
+
+		parsed, err := primitive.ObjectIDFromHex(token)
+
+		if err != nil {
+			return activityPubSigningKeys{}, derp.NotFound(location, "Actor URL must use the actor's ID", actorURL)
+		}
+
+		actorID = parsed
+	}
+
+	result := activityPubSigningKeys{}
+
+	rsaKeyID, rsaKey, err := service.locatorService.GetPrivateKey(session, actorType, actorID)
+
+	if err != nil {
+		return activityPubSigningKeys{}, derp.Wrap(err, location, "Loading RSA key", actorURL)
+	}
+
+	signer, isSigner := rsaKey.(crypto.Signer)
+
+	if !isSigner {
+		return activityPubSigningKeys{}, derp.Internal(location, "RSA key cannot sign", actorURL)
+	}
+
+	result.rsaKeyID = rsaKeyID
+	result.rsaKey = signer
+
+	ed25519KeyID, ed25519Key, err := service.locatorService.GetEd25519Key(session, actorType, actorID)
+
+	if err != nil {
+		return activityPubSigningKeys{}, derp.Wrap(err, location, "Loading Ed25519 key", actorURL)
+	}
+
+	result.ed25519KeyID = ed25519KeyID
+	result.ed25519Key = ed25519Key
+
+	return result, nil
+}
+
+// activityPubSigningKeys are the keys that a local actor signs outbound requests with
+type activityPubSigningKeys struct {
+	rsaKeyID     string
+	rsaKey       crypto.Signer
+	ed25519KeyID string
+	ed25519Key   ed25519.PrivateKey
+}
+
+// prove returns a copy of the activity with an "eddsa-jcs-2022" integrity proof from the Ed25519 key.
+// Activities that already carry a proof are returned unchanged.
+func (keys activityPubSigningKeys) prove(activity mapof.Any, now time.Time) (map[string]any, error) {
+
+	if integrity.HasProof(activity) {
+		return activity, nil
+	}
+
+	// Proofs sign the @context, so make sure that the activity has one
+	if _, exists := activity[vocab.AtContext]; !exists {
+		activity = maps.Clone(activity)
+		activity[vocab.AtContext] = vocab.ContextTypeActivityStreams
+	}
+
+	return integrity.Sign(activity, keys.ed25519KeyID, keys.ed25519Key, now)
+}
+
+// sign adds an HTTP signature to the request using the designated scheme.  RFC 9421 signatures
+// use the Ed25519 key, and draft-cavage signatures use the RSA key that every server understands.
+func (keys activityPubSigningKeys) sign(request *http.Request, body []byte, scheme httpsig.Scheme, now time.Time) error {
+
+	if scheme == httpsig.SchemeRFC9421 {
+		return httpsig.Sign(request, body, keys.ed25519KeyID, keys.ed25519Key, now)
+	}
+
+	return httpsig.SignCavage(request, body, keys.rsaKeyID, keys.rsaKey, now)
+}
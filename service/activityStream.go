@@ -202,12 +202,20 @@ func (service *ActivityStream) ruleChecker(actorType string, actorID primitive.O
 
 		// Before the fetch, only the URL is known: ACTOR keys catch a blocked actor's own URL,
 		// DOMAIN keys catch every URL on a blocked host. Once loaded, the document contributes its
-		// own keys (author, tags) -- which is what lets a MUTE or LABEL on an author reach every
+		// own keys (author, tags, matching CONTENT rules) -- which is what lets a MUTE or LABEL on an author reach every
 		// reply and quote fetched through this stack.
 		keys := model.ActorMatchKeys(uri)
 
 		if document.NotNil() {
+
+			contentKeys, err := service.ruleService.ContentMatchKeys(session, userID, document)
+
+			if err != nil {
+				return nil, err
+			}
+
 			keys = append(keys, model.DocumentMatchKeys(document)...)
+			keys = append(keys, contentKeys...)
 		}
 
 		disposition, err := service.ruleService.DispositionForKeys(session, userID, keys, time.Now().Unix())
@@ -307,8 +315,6 @@ func (service *ActivityStream) QueryByContext(ctx context.Context, contextName s
 // QueryActors returns a slice of ActorSummary values that match the provided query string.
 func (service *ActivityStream) QueryActors(queryString string) ([]model.ActorSummary, error) {
 
-	const location = "service.ActivityStream.QueryActors"
-
 	// If we think this is a URI  we can use then try to retrieve it directly.
 	if service.looksLikeValidURI(queryString) {
 
@@ -333,6 +339,15 @@ func (service *ActivityStream) QueryActors(queryString string) ([]model.ActorSum
 	}
 
 	// Fall through means that we can't find a perfect match, so fall back to a full-text search
+	return service.QueryCachedActors(queryString)
+}
+
+// QueryCachedActors returns a slice of ActorSummary values from the ActivityStream cache
+// that match the provided query string.  It never loads new actors from the Internet.
+func (service *ActivityStream) QueryCachedActors(queryString string) ([]model.ActorSummary, error) {
+
+	const location = "service.ActivityStream.QueryCachedActors"
+
 	ctx, cancel := timeoutContext(2)
 	defer cancel()
 
@@ -11,6 +11,7 @@ import (
 	"github.com/benpate/mediaserver"
 	"github.com/benpate/rosetta/schema"
 	"github.com/benpate/rosetta/sliceof"
+	"github.com/benpate/turbine/queue"
 	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -20,6 +21,7 @@ type Attachment struct {
 	host              string
 	importItemService *ImportItem
 	mediaServer       mediaserver.MediaServer
+	queue             *queue.Queue
 }
 
 // NewAttachment returns a fully populated Attachment service
@@ -36,6 +38,7 @@ func (service *Attachment) Refresh(factory *Factory) {
 	service.host = factory.Host()
 	service.importItemService = factory.ImportItem()
 	service.mediaServer = factory.MediaServer()
+	service.queue = factory.Queue()
 }
 
 // Close stops any background processes controlled by this service
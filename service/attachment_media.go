@@ -0,0 +1,269 @@
+package service
+
+import (
+	"bytes"
+	"image"
+	_ "image/gif"  // Register GIF decoder for media processing
+	_ "image/jpeg" // Register JPEG decoder for media processing
+	_ "image/png"  // Register PNG decoder for media processing
+	"io"
+	"net/http"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/blurhash"
+	"github.com/EmissarySocial/emissary/tools/postcommit"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/list"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/uri"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	_ "golang.org/x/image/webp" // Register WEBP decoder for media processing
+)
+
+/******************************************
+ * Mastodon Media Methods
+ * https://docs.joinmastodon.org/methods/media/
+ ******************************************/
+
+// UploadMedia saves a file uploaded through the Mastodon API.  Media belongs to the User
+// (in the AttachmentCategoryMedia category) until it is attached to a status, and is marked
+// as WORKING until ProcessMedia has calculated its dimensions and BlurHash.
+func (service *Attachment) UploadMedia(session data.Session, userID primitive.ObjectID, filename string, source io.Reader, description string, focus string) (model.Attachment, error) {
+
+	const location = "service.Attachment.UploadMedia"
+
+	// Sniff the actual file contents (NOT the attacker-controlled filename or Content-Type header)
+	header := make([]byte, 512)
+	headerLength, err := io.ReadFull(source, header)
+
+	if (err != nil) && (err != io.EOF) && (err != io.ErrUnexpectedEOF) {
+		return model.Attachment{}, derp.Wrap(err, location, "Reading uploaded file")
+	}
+
+	header = header[:headerLength]
+	contentType := model.DetectContentType(header, filename)
+
+	// RULE: Mastodon media must be an image, audio, or video file
+	switch list.Slash(contentType).First() {
+	case model.AttachmentMediaTypeImage, model.AttachmentMediaTypeAudio, model.AttachmentMediaTypeVideo:
+	default:
+		return model.Attachment{}, derp.Validation("Uploaded file must be an image, audio, or video", contentType)
+	}
+
+	// Create the new Attachment
+	attachment := model.NewAttachment(model.AttachmentObjectTypeUser, userID)
+	attachment.Original = filename
+	attachment.ContentType = contentType
+	attachment.Category = model.AttachmentCategoryMedia
+	attachment.Description = description
+	attachment.Status = model.AttachmentStatusWorking
+
+	if (focus != "") && !attachment.SetFocus(focus) {
+		return model.Attachment{}, derp.Validation("Focal point must be two numbers between -1.0 and 1.0", focus)
+	}
+
+	// Save the original file to the mediaserver.  The reader replays the sniffed bytes first.
+	if err := service.mediaServer.Put(attachment.AttachmentID.Hex(), io.MultiReader(bytes.NewReader(header), source)); err != nil {
+		return model.Attachment{}, derp.Wrap(err, location, "Saving file to mediaserver", attachment.AttachmentID)
+	}
+
+	if err := service.Save(session, &attachment, "Uploaded media: "+filename); err != nil {
+		return model.Attachment{}, derp.Wrap(err, location, "Saving Attachment", attachment.AttachmentID)
+	}
+
+	return attachment, nil
+}
+
+// ScheduleProcessMedia queues a background task that processes an uploaded media file
+func (service *Attachment) ScheduleProcessMedia(session data.Session, attachment *model.Attachment) {
+
+	postcommit.Publish(
+		session,
+		service.queue,
+		"ProcessMedia",
+		mapof.Any{
+			"hostname":     uri.Hostname(service.host),
+			"userId":       attachment.ObjectID.Hex(),
+			"attachmentId": attachment.AttachmentID.Hex(),
+		},
+	)
+}
+
+// ProcessMedia calculates the dimensions and BlurHash of an uploaded image, and marks the
+// Attachment as READY.  Other media types are served (and transcoded) by the mediaserver
+// on demand, so there is nothing more to calculate for them.
+func (service *Attachment) ProcessMedia(session data.Session, attachment *model.Attachment) error {
+
+	const location = "service.Attachment.ProcessMedia"
+
+	if attachment.IsReady() {
+		return nil
+	}
+
+	if attachment.MimeCategory() == model.AttachmentMediaTypeImage {
+
+		original, err := service.readOriginal(attachment)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Reading original file", attachment.AttachmentID)
+		}
+
+		service.processImage(attachment, original)
+	}
+
+	attachment.Status = model.AttachmentStatusReady
+
+	if err := service.Save(session, attachment, "Processed media"); err != nil {
+		return derp.Wrap(err, location, "Saving Attachment", attachment.AttachmentID)
+	}
+
+	return nil
+}
+
+// processImage sets the dimensions and BlurHash of an uploaded image.  Images that cannot
+// be decoded are still served, just without a placeholder.
+func (service *Attachment) processImage(attachment *model.Attachment, original []byte) {
+
+	const location = "service.Attachment.processImage"
+
+	// Read the dimensions from the image header before decoding any pixels
+	config, _, err := image.DecodeConfig(bytes.NewReader(original))
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, location, "Decoding image header", attachment.AttachmentID, attachment.ContentType))
+		return
+	}
+
+	attachment.Width = config.Width
+	attachment.Height = config.Height
+
+	// RULE: Do not decode huge images.  A small file can claim enormous dimensions,
+	// and decoding it would allocate memory for every pixel.
+	if int64(config.Width)*int64(config.Height) > model.AttachmentMaxMediaPixels {
+		return
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(original))
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, location, "Decoding image", attachment.AttachmentID, attachment.ContentType))
+		return
+	}
+
+	// Mastodon uses 4 x 3 components for every BlurHash
+	if hash, err := blurhash.Encode(img, 4, 3); err == nil {
+		attachment.BlurHash = hash
+	} else {
+		derp.Report(derp.Wrap(err, location, "Calculating BlurHash", attachment.AttachmentID))
+	}
+}
+
+// LoadMedia loads a media file that a User has uploaded, but not yet attached to a status
+func (service *Attachment) LoadMedia(session data.Session, userID primitive.ObjectID, attachmentID primitive.ObjectID, result *model.Attachment) error {
+
+	const location = "service.Attachment.LoadMedia"
+
+	criteria := exp.Equal("_id", attachmentID).
+		AndEqual("objectType", model.AttachmentObjectTypeUser).
+		AndEqual("objectId", userID).
+		AndEqual("category", model.AttachmentCategoryMedia)
+
+	if err := service.Load(session, criteria, result); err != nil {
+		return derp.Wrap(err, location, "Loading media", attachmentID)
+	}
+
+	return nil
+}
+
+// AttachMedia moves media files that a User has uploaded onto a Stream, in the order
+// provided, so that they are displayed and federated with it.
+func (service *Attachment) AttachMedia(session data.Session, userID primitive.ObjectID, streamID primitive.ObjectID, mediaIDs []string) error {
+
+	const location = "service.Attachment.AttachMedia"
+
+	// RULE: Limit the number of attachments per status (same as Mastodon)
+	if len(mediaIDs) > model.AttachmentMaxMedia {
+		return derp.Validation("Too many media attachments", len(mediaIDs))
+	}
+
+	attachments := make([]model.Attachment, len(mediaIDs))
+
+	// Validate every file before attaching any of them
+	for index, mediaID := range mediaIDs {
+
+		attachmentID, err := primitive.ObjectIDFromHex(mediaID)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Invalid media ID", mediaID, derp.WithBadRequest())
+		}
+
+		if err := service.LoadMedia(session, userID, attachmentID, &attachments[index]); err != nil {
+			return derp.Wrap(err, location, "Loading media", mediaID)
+		}
+
+		// RULE: Media must finish processing before it can be attached
+		if !attachments[index].IsReady() {
+			return derp.Validation("Media is still being processed", mediaID)
+		}
+	}
+
+	for index := range attachments {
+
+		attachment := &attachments[index]
+		attachment.ObjectType = model.AttachmentObjectTypeStream
+		attachment.ObjectID = streamID
+		attachment.Category = ""
+		attachment.Rank = index
+
+		if err := service.Save(session, attachment, "Attached to stream"); err != nil {
+			return derp.Wrap(err, location, "Saving Attachment", attachment.AttachmentID)
+		}
+	}
+
+	return nil
+}
+
+// readOriginal returns the original file for an Attachment
+func (service *Attachment) readOriginal(attachment *model.Attachment) ([]byte, error) {
+
+	const location = "service.Attachment.readOriginal"
+
+	request, err := http.NewRequest(http.MethodGet, attachment.URL, nil)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Creating request", attachment.URL)
+	}
+
+	writer := newBufferResponseWriter()
+
+	if err := service.mediaServer.ServeOriginal(writer, request, attachment.AttachmentID.Hex()); err != nil {
+		return nil, derp.Wrap(err, location, "Reading original file", attachment.AttachmentID)
+	}
+
+	return writer.body.Bytes(), nil
+}
+
+// bufferResponseWriter is an http.ResponseWriter that collects the response body in memory,
+// so that files can be read back out of the mediaserver.
+type bufferResponseWriter struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+func newBufferResponseWriter() *bufferResponseWriter {
+	return &bufferResponseWriter{
+		header: make(http.Header),
+	}
+}
+
+func (writer *bufferResponseWriter) Header() http.Header {
+	return writer.header
+}
+
+func (writer *bufferResponseWriter) Write(value []byte) (int, error) {
+	return writer.body.Write(value)
+}
+
+func (writer *bufferResponseWriter) WriteHeader(_ int) {}
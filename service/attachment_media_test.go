@@ -0,0 +1,76 @@
+package service
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TestAttachment_ProcessImage pins that ordinary images get their dimensions and a BlurHash
+func TestAttachment_ProcessImage(t *testing.T) {
+
+	original := testPNG(t, 8, 6)
+	attachment := model.NewAttachment(model.AttachmentObjectTypeUser, primitive.NewObjectID())
+
+	service := Attachment{}
+	service.processImage(&attachment, original)
+
+	require.Equal(t, 8, attachment.Width)
+	require.Equal(t, 6, attachment.Height)
+	require.NotEmpty(t, attachment.BlurHash)
+}
+
+// TestAttachment_ProcessImage_TooLarge pins that images claiming enormous dimensions are
+// never decoded, but still report the dimensions from their header.
+func TestAttachment_ProcessImage_TooLarge(t *testing.T) {
+
+	original := testPNG(t, 1, 1)
+	setPNGDimensions(original, 100000, 100000)
+
+	attachment := model.NewAttachment(model.AttachmentObjectTypeUser, primitive.NewObjectID())
+
+	service := Attachment{}
+	service.processImage(&attachment, original)
+
+	require.Equal(t, 100000, attachment.Width)
+	require.Equal(t, 100000, attachment.Height)
+	require.Empty(t, attachment.BlurHash)
+}
+
+func testPNG(t *testing.T, width int, height int) []byte {
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for x := range width {
+		for y := range height {
+			img.Set(x, y, color.RGBA{R: uint8(x * 30), G: uint8(y * 40), B: 128, A: 255})
+		}
+	}
+
+	var buffer bytes.Buffer
+	require.Nil(t, png.Encode(&buffer, img))
+	return buffer.Bytes()
+}
+
+// setPNGDimensions rewrites the width and height in a PNG's IHDR chunk (and its checksum)
+// without changing any of the pixel data.
+func setPNGDimensions(original []byte, width uint32, height uint32) {
+
+	// 8-byte signature, 4-byte length, then the "IHDR" chunk type and its data
+	const chunkType = 12
+	const chunkData = 16
+
+	binary.BigEndian.PutUint32(original[chunkData:], width)
+	binary.BigEndian.PutUint32(original[chunkData+4:], height)
+
+	checksum := crc32.ChecksumIEEE(original[chunkType : chunkData+13])
+	binary.BigEndian.PutUint32(original[chunkData+13:], checksum)
+}
@@ -0,0 +1,544 @@
+package service
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/schema"
+	"github.com/benpate/uri"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// blocklistTimeout caps how long a single blocklist download may run.
+const blocklistTimeout = 60 * time.Second
+
+// Blocklist service imports CSV blocklists (uploaded once, or subscribed to by URL) as server-wide Rules
+type Blocklist struct {
+	ruleService            *Rule
+	ruleSuppressionService *RuleSuppression
+	hostname               string
+	httpClient             *http.Client
+}
+
+// NewBlocklist returns a new instance of the Blocklist service
+func NewBlocklist() Blocklist {
+	return Blocklist{}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+func (service *Blocklist) Refresh(factory *Factory) {
+	service.ruleService = factory.Rule()
+	service.ruleSuppressionService = factory.RuleSuppression()
+	service.hostname = factory.Hostname()
+
+	// Subscriptions follow the same network policy as webhooks: a production instance may only
+	// download from public addresses, while a local/dev instance may talk to itself.
+	service.httpClient = webPushHTTPClient(uri.IsLocalHostname(service.hostname))
+	service.httpClient.Timeout = blocklistTimeout
+}
+
+/******************************************
+ * Common Methods
+ ******************************************/
+
+func (service *Blocklist) collection(session data.Session) data.Collection {
+	return session.Collection("Blocklist")
+}
+
+// New returns a new, empty Blocklist
+func (service *Blocklist) New() model.Blocklist {
+	return model.NewBlocklist()
+}
+
+// Count returns the number of records that match the provided criteria
+func (service *Blocklist) Count(session data.Session, criteria exp.Expression) (int64, error) {
+	return service.collection(session).Count(notDeleted(criteria))
+}
+
+// Query returns an slice containing all of the Blocklists that match the provided criteria
+func (service *Blocklist) Query(session data.Session, criteria exp.Expression, options ...option.Option) ([]model.Blocklist, error) {
+	result := make([]model.Blocklist, 0)
+	err := service.collection(session).Query(&result, notDeleted(criteria), options...)
+	return result, err
+}
+
+// List returns an iterator containing all of the Blocklists that match the provided criteria
+func (service *Blocklist) List(session data.Session, criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.collection(session).Iterator(notDeleted(criteria), options...)
+}
+
+// Load retrieves a Blocklist from the database
+func (service *Blocklist) Load(session data.Session, criteria exp.Expression, blocklist *model.Blocklist) error {
+
+	if err := service.collection(session).Load(notDeleted(criteria), blocklist); err != nil {
+		return derp.Wrap(err, "service.Blocklist.Load", "Loading Blocklist", criteria)
+	}
+
+	return nil
+}
+
+// Save adds/updates a Blocklist in the database
+func (service *Blocklist) Save(session data.Session, blocklist *model.Blocklist, note string) error {
+
+	const location = "service.Blocklist.Save"
+
+	// Validate the value before saving
+	if _, err := service.Schema().Validate(blocklist); err != nil {
+		return derp.Wrap(err, location, "Validating Blocklist using BlocklistSchema", blocklist)
+	}
+
+	// RULE: Every Blocklist needs something to read
+	if (blocklist.URL == "") && (blocklist.Content == "") {
+		return derp.Validation("Blocklist must have a URL or uploaded content")
+	}
+
+	// Try to save the Blocklist to the database
+	if err := service.collection(session).Save(blocklist, note); err != nil {
+		return derp.Wrap(err, location, "Saving Blocklist", blocklist, note)
+	}
+
+	return nil
+}
+
+// Delete removes a Blocklist from the database (virtual delete), along with every
+// Rule that it manages.
+func (service *Blocklist) Delete(session data.Session, blocklist *model.Blocklist, note string) error {
+
+	const location = "service.Blocklist.Delete"
+
+	// Retract every Rule that this subscription created
+	rules, err := service.ruleService.Query(session, exp.Equal("userId", primitive.NilObjectID).AndEqual("blocklistId", blocklist.BlocklistID))
+
+	if err != nil {
+		return derp.Wrap(err, location, "Querying Rules managed by Blocklist", blocklist.BlocklistID)
+	}
+
+	for _, rule := range rules {
+
+		// Unsubscribing is not a local decision about the entry, so it is not suppressed
+		rule.RemoteID = ""
+
+		if err := service.ruleService.Delete(session, &rule, "Unsubscribed from blocklist: "+blocklist.Label); err != nil {
+			return derp.Wrap(err, location, "Deleting Rule", rule.RuleID)
+		}
+	}
+
+	// Delete this Blocklist
+	if err := service.collection(session).Delete(blocklist, note); err != nil {
+		return derp.Wrap(err, location, "Deleting Blocklist", blocklist, note)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Generic Data Methods
+ ******************************************/
+
+// ObjectType returns the type of object that this service manages
+func (service *Blocklist) ObjectType() string {
+	return "Blocklist"
+}
+
+// ObjectNew returns a fully initialized model.Blocklist as a data.Object.
+func (service *Blocklist) ObjectNew() data.Object {
+	result := model.NewBlocklist()
+	return &result
+}
+
+func (service *Blocklist) ObjectID(object data.Object) primitive.ObjectID {
+
+	if blocklist, ok := object.(*model.Blocklist); ok {
+		return blocklist.BlocklistID
+	}
+
+	return primitive.NilObjectID
+}
+
+func (service *Blocklist) ObjectQuery(session data.Session, result any, criteria exp.Expression, options ...option.Option) error {
+	return service.collection(session).Query(result, notDeleted(criteria), options...)
+}
+
+func (service *Blocklist) ObjectLoad(session data.Session, criteria exp.Expression) (data.Object, error) {
+	result := model.NewBlocklist()
+	err := service.Load(session, criteria, &result)
+	return &result, err
+}
+
+func (service *Blocklist) ObjectSave(session data.Session, object data.Object, note string) error {
+	if blocklist, ok := object.(*model.Blocklist); ok {
+		return service.Save(session, blocklist, note)
+	}
+	return derp.Internal("service.Blocklist.ObjectSave", "Invalid object type", object)
+}
+
+func (service *Blocklist) ObjectDelete(session data.Session, object data.Object, note string) error {
+	if blocklist, ok := object.(*model.Blocklist); ok {
+		return service.Delete(session, blocklist, note)
+	}
+	return derp.Internal("service.Blocklist.ObjectDelete", "Invalid object type", object)
+}
+
+func (service *Blocklist) ObjectUserCan(object data.Object, authorization model.Authorization, action string) error {
+	return derp.Unauthorized("service.Blocklist.ObjectUserCan", "Not Authorized")
+}
+
+func (service *Blocklist) Schema() schema.Schema {
+	return schema.New(model.BlocklistSchema())
+}
+
+/******************************************
+ * Common Queries
+ ******************************************/
+
+func (service *Blocklist) LoadByID(session data.Session, blocklistID primitive.ObjectID, result *model.Blocklist) error {
+	return service.Load(session, exp.Equal("_id", blocklistID), result)
+}
+
+// QuerySubscriptions returns every Blocklist that is downloaded from a remote URL
+func (service *Blocklist) QuerySubscriptions(session data.Session) ([]model.Blocklist, error) {
+	return service.Query(session, exp.NotEqual("url", ""), option.SortAsc("label"))
+}
+
+/******************************************
+ * Custom Behaviors
+ ******************************************/
+
+// Preview reads the latest copy of a Blocklist (downloading subscriptions, or parsing uploaded
+// content) and stores the changes it would make in Blocklist.Pending.  Nothing is applied until
+// an administrator (or AutoApply) calls Apply.
+func (service *Blocklist) Preview(session data.Session, blocklist *model.Blocklist) error {
+
+	const location = "service.Blocklist.Preview"
+
+	changes, entryCount, err := service.diff(session, blocklist)
+
+	blocklist.FetchDate = time.Now().Unix()
+
+	if err != nil {
+		blocklist.LastError = derp.Message(err)
+
+		if saveErr := service.Save(session, blocklist, "Preview failed"); saveErr != nil {
+			derp.Report(derp.Wrap(saveErr, location, "Saving Blocklist", blocklist.BlocklistID))
+		}
+
+		return derp.Wrap(err, location, "Reading Blocklist", blocklist.BlocklistID)
+	}
+
+	blocklist.Pending = changes
+	blocklist.EntryCount = entryCount
+	blocklist.LastError = ""
+
+	if err := service.Save(session, blocklist, "Previewed"); err != nil {
+		return derp.Wrap(err, location, "Saving Blocklist", blocklist.BlocklistID)
+	}
+
+	return nil
+}
+
+// Apply makes every pending change in a Blocklist.  Failures on individual entries do not stop
+// the rest of the list; they are summarized in Blocklist.LastError instead.  One-time uploads
+// are removed once they have been applied cleanly.  Subscriptions whose latest copy looks
+// broken (see Blocklist.SyncError) are not applied at all.
+func (service *Blocklist) Apply(session data.Session, blocklist *model.Blocklist) error {
+
+	const location = "service.Blocklist.Apply"
+
+	// RULE: Do not apply subscriptions that look truncated or replaced.  Keep the pending
+	// changes so that an administrator can review them.
+	if err := service.checkSync(session, blocklist); err != nil {
+		return derp.Wrap(err, location, "Checking Blocklist changes", blocklist.BlocklistID)
+	}
+
+	note := "Blocklist: " + blocklist.Label
+	failures := make([]string, 0)
+
+	for _, change := range blocklist.Pending {
+		if err := service.applyChange(session, blocklist, change, note); err != nil {
+			derp.Report(derp.Wrap(err, location, "Applying blocklist change", change))
+			failures = append(failures, change.Trigger+": "+derp.Message(err))
+		}
+	}
+
+	// One-time uploads have nothing left to do (unless something went wrong)
+	if !blocklist.IsSubscription() && (len(failures) == 0) {
+
+		if err := service.collection(session).Delete(blocklist, "Applied"); err != nil {
+			return derp.Wrap(err, location, "Deleting applied Blocklist", blocklist.BlocklistID)
+		}
+
+		return nil
+	}
+
+	blocklist.Pending = make([]model.BlocklistChange, 0)
+	blocklist.ApplyDate = time.Now().Unix()
+	blocklist.LastError = ""
+
+	if len(failures) > 0 {
+		blocklist.LastError = strconv.Itoa(len(failures)) + " change(s) could not be applied. " + failures[0]
+	}
+
+	if err := service.Save(session, blocklist, "Applied"); err != nil {
+		return derp.Wrap(err, location, "Saving Blocklist", blocklist.BlocklistID)
+	}
+
+	return nil
+}
+
+// SyncAll refreshes every subscription, applying the changes to those that allow it.
+// This is called daily by the "SyncBlocklists" task.
+func (service *Blocklist) SyncAll(session data.Session) error {
+
+	const location = "service.Blocklist.SyncAll"
+
+	blocklists, err := service.QuerySubscriptions(session)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Querying Blocklist subscriptions")
+	}
+
+	for _, blocklist := range blocklists {
+
+		// One bad list should not prevent the others from refreshing
+		if err := service.Preview(session, &blocklist); err != nil {
+			derp.Report(derp.Wrap(err, location, "Refreshing Blocklist", blocklist.URL))
+			continue
+		}
+
+		if !blocklist.AutoApply || !blocklist.HasPending() {
+			continue
+		}
+
+		if err := service.Apply(session, &blocklist); err != nil {
+			derp.Report(derp.Wrap(err, location, "Applying Blocklist", blocklist.URL))
+		}
+	}
+
+	log.Debug().Int("count", len(blocklists)).Msg("Synchronized blocklist subscriptions")
+	return nil
+}
+
+/******************************************
+ * Helper Methods
+ ******************************************/
+
+// checkSync refuses to apply a subscription whose latest copy has no entries, or removes too
+// many of the Rules that it already manages.  The reason is recorded in Blocklist.LastError.
+func (service *Blocklist) checkSync(session data.Session, blocklist *model.Blocklist) error {
+
+	const location = "service.Blocklist.checkSync"
+
+	if !blocklist.IsSubscription() {
+		return nil
+	}
+
+	ownedCount, err := service.ruleService.Count(session, exp.Equal("userId", primitive.NilObjectID).AndEqual("blocklistId", blocklist.BlocklistID))
+
+	if err != nil {
+		return derp.Wrap(err, location, "Counting Rules managed by Blocklist", blocklist.BlocklistID)
+	}
+
+	reason := blocklist.SyncError(int(ownedCount))
+
+	if reason == "" {
+		return nil
+	}
+
+	blocklist.LastError = reason
+
+	if err := service.Save(session, blocklist, "Sync refused"); err != nil {
+		return derp.Wrap(err, location, "Saving Blocklist", blocklist.BlocklistID)
+	}
+
+	return derp.Validation(reason, blocklist.BlocklistID)
+}
+
+// diff returns the changes needed to bring the server-wide Rules into agreement
+// with the Blocklist, along with the number of usable entries in the list
+func (service *Blocklist) diff(session data.Session, blocklist *model.Blocklist) ([]model.BlocklistChange, int, error) {
+
+	const location = "service.Blocklist.diff"
+
+	content, err := service.content(blocklist)
+
+	if err != nil {
+		return nil, 0, derp.Wrap(err, location, "Reading Blocklist content")
+	}
+
+	entries, err := model.ParseBlocklistCSV(content)
+
+	if err != nil {
+		return nil, 0, derp.Wrap(err, location, "Parsing Blocklist content")
+	}
+
+	rules, err := service.ruleService.Query(session, exp.Equal("userId", primitive.NilObjectID))
+
+	if err != nil {
+		return nil, 0, derp.Wrap(err, location, "Querying server-wide Rules")
+	}
+
+	changes := blocklist.Diff(entries, rules)
+	result := make([]model.BlocklistChange, 0, len(changes))
+
+	// RULE: Entries that an administrator deleted locally are not re-added
+	for _, change := range changes {
+
+		if change.IsAdd() && (change.RemoteID != "") {
+
+			suppressed, err := service.ruleSuppressionService.IsSuppressed(session, primitive.NilObjectID, change.RemoteID)
+
+			if err != nil {
+				return nil, 0, derp.Wrap(err, location, "Checking suppression", change.RemoteID)
+			}
+
+			if suppressed {
+				continue
+			}
+		}
+
+		result = append(result, change)
+	}
+
+	return result, len(entries), nil
+}
+
+// content returns a reader for the latest copy of the Blocklist
+func (service *Blocklist) content(blocklist *model.Blocklist) (io.Reader, error) {
+
+	const location = "service.Blocklist.content"
+
+	if !blocklist.IsSubscription() {
+		return strings.NewReader(blocklist.Content), nil
+	}
+
+	request, err := http.NewRequest(http.MethodGet, blocklist.URL, nil)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Creating request", blocklist.URL)
+	}
+
+	request.Header.Set("Accept", "text/csv, text/plain")
+	request.Header.Set("User-Agent", "Emissary Blocklists (https://"+service.hostname+")")
+
+	// Fall back to a guarded client if the service was never refreshed (fail closed, not open).
+	client := service.httpClient
+	if client == nil {
+		client = webPushHTTPClient(false)
+	}
+
+	response, err := client.Do(request)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Downloading Blocklist", blocklist.URL)
+	}
+
+	defer func() {
+		if err := response.Body.Close(); err != nil {
+			derp.Report(derp.Wrap(err, location, "Closing response body", blocklist.URL))
+		}
+	}()
+
+	if (response.StatusCode < 200) || (response.StatusCode > 299) {
+		return nil, derp.Validation("Blocklist could not be downloaded", blocklist.URL, response.Status)
+	}
+
+	// Read one byte past the limit, so that oversized lists are refused rather than truncated
+	body, err := io.ReadAll(io.LimitReader(response.Body, model.BlocklistContentMaxLength+1))
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Reading Blocklist", blocklist.URL)
+	}
+
+	if len(body) > model.BlocklistContentMaxLength {
+		return nil, derp.Validation("Blocklist is too large", blocklist.URL)
+	}
+
+	return bytes.NewReader(body), nil
+}
+
+// applyChange makes a single pending change to the server-wide Rules
+func (service *Blocklist) applyChange(session data.Session, blocklist *model.Blocklist, change model.BlocklistChange, note string) error {
+
+	const location = "service.Blocklist.applyChange"
+
+	// Create a new server-wide Rule
+	if change.IsAdd() {
+
+		rule := model.NewRule()
+		rule.UserID = primitive.NilObjectID
+		rule.FollowingLabel = blocklist.Label
+		rule.Type = change.Type
+		rule.Trigger = change.Trigger
+		rule.Action = change.Action
+		rule.ReasonCode = change.ReasonCode
+		rule.Summary = change.Summary
+		rule.RemoteID = change.RemoteID
+
+		// Only subscriptions keep managing the Rules they create
+		if blocklist.IsSubscription() {
+			rule.BlocklistID = blocklist.BlocklistID
+		}
+
+		if err := service.ruleService.Save(session, &rule, note); err != nil {
+			return derp.Wrap(err, location, "Adding Rule", change.Trigger)
+		}
+
+		return nil
+	}
+
+	// Load the existing Rule
+	rule := model.NewRule()
+
+	if err := service.ruleService.LoadServerWideByID(session, change.RuleID, &rule); err != nil {
+
+		// A Rule that is already gone has nothing left to change
+		if derp.IsNotFound(err) {
+			return nil
+		}
+
+		return derp.Wrap(err, location, "Loading Rule", change.RuleID)
+	}
+
+	// RULE: Blocklists only change the Rules that they manage
+	if rule.BlocklistID != blocklist.BlocklistID {
+		return nil
+	}
+
+	// Retract Rules that have been removed from the list
+	if change.IsRemove() {
+
+		// A provider's retraction is not a local decision about the entry, so it is not suppressed
+		rule.RemoteID = ""
+
+		if err := service.ruleService.Delete(session, &rule, note); err != nil {
+			return derp.Wrap(err, location, "Removing Rule", change.RuleID)
+		}
+
+		return nil
+	}
+
+	// Otherwise, update the Rule to match the list
+	rule.Action = change.Action
+	rule.ReasonCode = change.ReasonCode
+	rule.Summary = change.Summary
+	rule.FollowingLabel = blocklist.Label
+
+	if err := service.ruleService.Save(session, &rule, note); err != nil {
+		return derp.Wrap(err, location, "Updating Rule", change.RuleID)
+	}
+
+	return nil
+}
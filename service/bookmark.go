@@ -0,0 +1,184 @@
+package service
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Bookmark manages the private list of documents that each User has saved for later.
+type Bookmark struct{}
+
+// NewBookmark returns a fully initialized Bookmark service
+func NewBookmark() Bookmark {
+	return Bookmark{}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+// Refresh updates any stateful data that is cached inside this service.
+func (service *Bookmark) Refresh(factory *Factory) {
+	// Nothing to refresh.
+}
+
+// Close stops any background processes controlled by this service
+func (service *Bookmark) Close() {
+	// Nothin to do here.
+}
+
+/******************************************
+ * Common Data Methods
+ ******************************************/
+
+func (service *Bookmark) collection(session data.Session) data.Collection {
+	return session.Collection("Bookmark")
+}
+
+// Count returns the number of Bookmarks that match the provided criteria
+func (service *Bookmark) Count(session data.Session, criteria exp.Expression) (int64, error) {
+	return service.collection(session).Count(notDeleted(criteria))
+}
+
+// Query returns a slice of Bookmarks that match the provided criteria
+func (service *Bookmark) Query(session data.Session, criteria exp.Expression, options ...option.Option) ([]model.Bookmark, error) {
+	result := make([]model.Bookmark, 0)
+	err := service.collection(session).Query(&result, notDeleted(criteria), options...)
+	return result, err
+}
+
+// Load retrieves a Bookmark from the database
+func (service *Bookmark) Load(session data.Session, criteria exp.Expression, bookmark *model.Bookmark) error {
+
+	if err := service.collection(session).Load(notDeleted(criteria), bookmark); err != nil {
+		return derp.Wrap(err, "service.Bookmark.Load", "Loading Bookmark", criteria)
+	}
+
+	return nil
+}
+
+// Save adds/updates a Bookmark in the database
+func (service *Bookmark) Save(session data.Session, bookmark *model.Bookmark, note string) error {
+
+	const location = "service.Bookmark.Save"
+
+	if _, err := service.Schema().Validate(bookmark); err != nil {
+		return derp.Wrap(err, location, "Validating Bookmark", bookmark)
+	}
+
+	if err := service.collection(session).Save(bookmark, note); err != nil {
+		return derp.Wrap(err, location, "Saving Bookmark", bookmark, note)
+	}
+
+	return nil
+}
+
+// Delete removes a Bookmark from the database (hard delete)
+func (service *Bookmark) Delete(session data.Session, bookmark *model.Bookmark, note string) error {
+
+	const location = "service.Bookmark.Delete"
+
+	// Hard delete, never virtual: a Bookmark is private to its User, so there is nothing to
+	// federate, and a tombstone would only block the User from bookmarking the same document again.
+	if err := service.collection(session).HardDelete(exp.Equal("_id", bookmark.BookmarkID)); err != nil {
+		return derp.Wrap(err, location, "Deleting Bookmark", bookmark, note)
+	}
+
+	return nil
+}
+
+func (service *Bookmark) Schema() schema.Schema {
+	return schema.New(model.BookmarkSchema())
+}
+
+/******************************************
+ * Custom Queries + Behaviors
+ ******************************************/
+
+// QueryByUser returns the Bookmarks for a User that match the provided criteria, newest first.
+func (service *Bookmark) QueryByUser(session data.Session, userID primitive.ObjectID, criteria exp.Expression, options ...option.Option) ([]model.Bookmark, error) {
+	criteria = exp.Equal("userId", userID).And(criteria)
+	options = append(options, option.SortDesc("createDate"))
+	return service.Query(session, criteria, options...)
+}
+
+// LoadByURL loads the Bookmark that a User has saved for a specific document
+func (service *Bookmark) LoadByURL(session data.Session, userID primitive.ObjectID, url string, bookmark *model.Bookmark) error {
+	criteria := exp.Equal("userId", userID).AndEqual("url", url)
+	return service.Load(session, criteria, bookmark)
+}
+
+// SetBookmark bookmarks a document for a User.  Bookmarking the same document twice is a no-op.
+func (service *Bookmark) SetBookmark(session data.Session, userID primitive.ObjectID, url string) (model.Bookmark, error) {
+
+	const location = "service.Bookmark.SetBookmark"
+
+	// Look for an existing Bookmark for this document
+	bookmark := model.NewBookmark()
+
+	if err := service.LoadByURL(session, userID, url, &bookmark); err == nil {
+		return bookmark, nil
+
+	} else if !derp.IsNotFound(err) {
+		return bookmark, derp.Wrap(err, location, "Loading existing Bookmark", userID, url)
+	}
+
+	// Create a new Bookmark
+	bookmark.UserID = userID
+	bookmark.URL = url
+
+	if err := service.Save(session, &bookmark, "Bookmarked"); err != nil {
+
+		// A lost creation race trips the unique (userId, url) index.  The winner
+		// already saved the same Bookmark, so there is nothing left to do.
+		if derp.IsConflict(err) {
+			return bookmark, nil
+		}
+
+		return bookmark, derp.Wrap(err, location, "Saving Bookmark", bookmark)
+	}
+
+	return bookmark, nil
+}
+
+// UnsetBookmark removes a User's Bookmark for a document.  Removing a missing Bookmark is a no-op.
+func (service *Bookmark) UnsetBookmark(session data.Session, userID primitive.ObjectID, url string) (model.Bookmark, error) {
+
+	const location = "service.Bookmark.UnsetBookmark"
+
+	bookmark := model.NewBookmark()
+
+	if err := service.LoadByURL(session, userID, url, &bookmark); err != nil {
+
+		if derp.IsNotFound(err) {
+			bookmark.UserID = userID
+			bookmark.URL = url
+			return bookmark, nil
+		}
+
+		return bookmark, derp.Wrap(err, location, "Loading Bookmark", userID, url)
+	}
+
+	if err := service.Delete(session, &bookmark, "Unbookmarked"); err != nil {
+		return bookmark, derp.Wrap(err, location, "Deleting Bookmark", bookmark)
+	}
+
+	return bookmark, nil
+}
+
+// DeleteByUserID removes every Bookmark owned by the provided User
+func (service *Bookmark) DeleteByUserID(session data.Session, userID primitive.ObjectID, note string) error {
+
+	const location = "service.Bookmark.DeleteByUserID"
+
+	if err := service.collection(session).HardDelete(exp.Equal("userId", userID)); err != nil {
+		return derp.Wrap(err, location, "Deleting Bookmarks", userID, note)
+	}
+
+	return nil
+}
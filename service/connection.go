@@ -350,6 +350,45 @@ func (service *Connection) LoadOrCreateByProvider(session data.Session, provider
 	return result, nil
 }
 
+// ReEncrypt decrypts the vault in every Connection with the current master key, and encrypts it
+// again with the `newMasterKey` (hex-encoded). The provider lifecycle hooks are not called,
+// because the decrypted values do not change.
+func (service *Connection) ReEncrypt(session data.Session, newMasterKey string) error {
+
+	const location = "service.Connection.ReEncrypt"
+
+	oldKey, err := hex.DecodeString(service.masterKey)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Decoding current master key")
+	}
+
+	newKey, err := hex.DecodeString(newMasterKey)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Decoding new master key")
+	}
+
+	// Connections are stored in the Domain record, so update a copy of them all at once
+	domain := *service.domain
+	domain.Connections = make(mapof.Matchable[model.Connection], len(service.domain.Connections))
+
+	for providerID, connection := range service.domain.Connections {
+
+		if err := connection.Vault.ReEncrypt(oldKey, newKey); err != nil {
+			return derp.Wrap(err, location, "Re-encrypting vault", providerID)
+		}
+
+		domain.Connections[providerID] = connection
+	}
+
+	if err := service.domainService.Save(session, domain, "Re-encrypted connections with new master key"); err != nil {
+		return derp.Wrap(err, location, "Saving Connections")
+	}
+
+	return nil
+}
+
 func (service *Connection) DecryptVault(connection *model.Connection, values ...string) (mapof.String, error) {
 	const location = "service.Connection.DecryptVault"
 
@@ -117,7 +117,7 @@ func (service *Domain) Start() error {
 	go func() {
 
 		// Once we have the domain loaded, try to upgrade the database
-		if err := queries.UpgradeMongoDB(service.configuration.ConnectString, service.configuration.DatabaseName, &service.domain); err != nil {
+		if err := queries.UpgradeMongoDB(service.configuration.ConnectString, service.configuration.DatabaseName, service.configuration.MasterKey, &service.domain); err != nil {
 			derp.Report(derp.Wrap(err, location, "Domain Not Ready: Error upgrading domain record"))
 			return
 		}
@@ -1,11 +1,15 @@
 package service
 
 import (
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/EmissarySocial/emissary/tools/camper"
+	"github.com/EmissarySocial/emissary/tools/multikey"
 	"github.com/benpate/data"
 	"github.com/benpate/derp"
 	"github.com/benpate/digit"
@@ -13,6 +17,7 @@ import (
 	"github.com/benpate/hannibal/sigs"
 	"github.com/benpate/hannibal/vocab"
 	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/rosetta/sliceof"
 )
 
 /******************************************
@@ -42,11 +47,18 @@ func (service *Domain) GetJSONLD(session data.Session) (mapof.Any, error) {
 
 	actorID := service.ActorID()
 
+	// Load the Ed25519 key
+	assertionMethod, err := service.AssertionMethodJSONLD(session, actorID)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Loading Ed25519 key")
+	}
+
 	domain := service.Get()
 
 	// Return the result as a JSON-LD document
 	result := map[string]any{
-		vocab.AtContext:                 []any{vocab.ContextTypeActivityStreams, vocab.ContextTypeSecurity, vocab.ContextTypeToot},
+		vocab.AtContext:                 []any{vocab.ContextTypeActivityStreams, vocab.ContextTypeSecurity, vocab.ContextTypeToot, multikey.Context},
 		vocab.PropertyType:              vocab.ActorTypeApplication,
 		vocab.PropertyID:                actorID,
 		vocab.PropertyPreferredUsername: "application",
@@ -66,6 +78,8 @@ func (service *Domain) GetJSONLD(session data.Session) (mapof.Any, error) {
 			vocab.PropertyOwner:        actorID,
 			vocab.PropertyPublicKeyPEM: publicKeyPEM,
 		},
+
+		"assertionMethod": assertionMethod,
 	}
 
 	return result, nil
@@ -138,6 +152,75 @@ func (service *Domain) PrivateKey(session data.Session) (*rsa.PrivateKey, error)
 	return privateKey, nil
 }
 
+// Ed25519KeyID returns the URL for the Ed25519 key for this domain/actor
+func (service *Domain) Ed25519KeyID() string {
+	return service.ActorID() + "#ed25519-key"
+}
+
+// Ed25519PrivateKey returns the Ed25519 key that signs RFC 9421 requests for this domain/actor.
+// Like the RSA key, it is generated the first time that it is needed.
+func (service *Domain) Ed25519PrivateKey(session data.Session) (ed25519.PrivateKey, error) {
+
+	const location = "service.Domain.Ed25519PrivateKey"
+
+	// Get the Domain record
+	domain := *service.Get()
+
+	// Try to use the existing private key
+	if domain.Ed25519PrivateKey != "" {
+
+		if block, _ := pem.Decode([]byte(domain.Ed25519PrivateKey)); block != nil {
+
+			privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+
+			if err == nil {
+				if ed25519Key, ok := privateKey.(ed25519.PrivateKey); ok {
+					return ed25519Key, nil
+				}
+			}
+		}
+
+		// Fall through means that the saved value is not valid, so make a new one.
+		derp.Report(derp.Internal(location, "Unable to decode Ed25519 private key. Creating a new key"))
+	}
+
+	// Otherwise, create a new private key, save it, and return it to the caller.
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Generating Ed25519 key")
+	}
+
+	encoded, err := x509.MarshalPKCS8PrivateKey(privateKey)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Encoding Ed25519 key")
+	}
+
+	// Save the new private key into the Domain record
+	domain.Ed25519PrivateKey = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: encoded}))
+
+	if err := service.Save(session, domain, "Generated Ed25519 Key"); err != nil {
+		return nil, derp.Wrap(err, location, "Saving Ed25519 key")
+	}
+
+	return privateKey, nil
+}
+
+// AssertionMethodJSONLD returns the "assertionMethod" property (FEP-521a) for a domain-level
+// actor (the application and search actors), all of which sign with the domain's Ed25519 key.
+func (service *Domain) AssertionMethodJSONLD(session data.Session, actorID string) (sliceof.Any, error) {
+
+	privateKey, err := service.Ed25519PrivateKey(session)
+
+	if err != nil {
+		return nil, derp.Wrap(err, "service.Domain.AssertionMethodJSONLD", "Loading Ed25519 key")
+	}
+
+	publicKey := multikey.EncodeEd25519(privateKey.Public().(ed25519.PublicKey))
+	return sliceof.Any{multikeyJSONLD(actorID+"#ed25519-key", actorID, publicKey)}, nil
+}
+
 // ActivityPubActor returns an ActivityPub Actor object
 // ** WHICH INCLUDES ENCRYPTION KEYS ** for the provided User.
 func (service *Domain) ActivityPubActor(session data.Session) (outbox.Actor, error) {
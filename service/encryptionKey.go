@@ -1,27 +1,40 @@
 package service
 
 import (
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
+	"encoding/hex"
 	"encoding/pem"
 	"iter"
+	"time"
 
 	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/multikey"
 	"github.com/benpate/data"
 	"github.com/benpate/data/option"
 	"github.com/benpate/derp"
 	"github.com/benpate/exp"
 	"github.com/benpate/hannibal/sigs"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/rosetta/sliceof"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Require 2048-bit encryption keys
 const encryptionKeyBits = 2048
 
-// EncryptionKey defines a service that tracks the (possibly external) accounts an internal User is encryptionKey.
+// encryptionKeyGracePeriod is how long a rotated key continues to be published (and verify) after
+// it has been replaced, so that signatures already in flight are not rejected.
+const encryptionKeyGracePeriod = 7 * 24 * time.Hour
+
+// EncryptionKey defines a service that manages the signing keys for each local ActivityPub actor.
+// Private keys are encrypted at rest using the domain's master key.
 type EncryptionKey struct {
-	host string
+	host      string
+	masterKey string
 }
 
 // NewEncryptionKey returns a fully initialized EncryptionKey service
@@ -36,6 +49,7 @@ func NewEncryptionKey() EncryptionKey {
 // Refresh updates any stateful data that is cached inside this service.
 func (service *EncryptionKey) Refresh(factory *Factory) {
 	service.host = factory.Host()
+	service.masterKey = factory.MasterKey()
 }
 
 // Close stops any background processes controlled by this service
@@ -112,20 +126,63 @@ func (service *EncryptionKey) RangeByParentID(session data.Session, parentID pri
 	return service.Range(session, exp.Equal("parentId", parentID))
 }
 
-// LoadByID tries to load the EncryptionKey from the database.  If no key
-// exists for the designated user, then a new one is generated.
+// RangeVerifiable returns all keys for the designated parent that should still verify signatures:
+// the current key, plus any retired keys that are still inside their grace period.
+func (service *EncryptionKey) RangeVerifiable(session data.Session, parentType string, parentID primitive.ObjectID) (iter.Seq[model.EncryptionKey], error) {
+
+	criteria := exp.Equal("parentType", parentType).
+		AndEqual("parentId", parentID)
+
+	keys, err := service.Range(session, criteria, option.SortDesc("createDate"))
+
+	if err != nil {
+		return nil, derp.Wrap(err, "service.EncryptionKey.RangeVerifiable", "Loading keys", parentType, parentID)
+	}
+
+	now := time.Now().Unix()
+
+	return func(yield func(model.EncryptionKey) bool) {
+		for key := range keys {
+			if key.IsVerifiable(now) {
+				if !yield(key) {
+					return
+				}
+			}
+		}
+	}, nil
+}
+
+// LoadByParentID loads the current EncryptionKey for the designated parent.  If no key
+// exists for the designated parent, then a new one is generated.
 func (service *EncryptionKey) LoadByParentID(session data.Session, parentType string, parentID primitive.ObjectID, encryptionKey *model.EncryptionKey) error {
 
 	const location = "service.EncryptionKey.LoadByParentID"
 
-	// Try to load the encryption key from the database
-	if err := service.Load(session, exp.Equal("parentType", parentType).AndEqual("parentId", parentID), encryptionKey); err != nil {
+	// Load all of the keys for this parent.  Legacy records do not include a "rotatedDate"
+	// so we cannot query for it directly.  Instead, look for the newest key that is not retired.
+	criteria := exp.Equal("parentType", parentType).
+		AndEqual("parentId", parentID)
 
-		// Anything other than NotFound is a real failure; a NotFound falls through to make a key
-		if !derp.IsNotFound(err) {
-			return derp.Wrap(err, location, "Loading EncryptionKey", parentID)
+	keys, err := service.Range(session, criteria, option.SortDesc("createDate"))
+
+	if err != nil {
+		return derp.Wrap(err, location, "Loading EncryptionKeys", parentID)
+	}
+
+	for key := range keys {
+
+		if !key.IsCurrent() {
+			continue
 		}
-	} else {
+
+		// Keys created before RFC 9421 support receive their Ed25519 key the first time they are used
+		if !key.HasEd25519() {
+			if err := service.addEd25519(session, &key); err != nil {
+				return derp.Wrap(err, location, "Adding Ed25519 key", parentID)
+			}
+		}
+
+		*encryptionKey = key
 		return nil
 	}
 
@@ -145,13 +202,23 @@ func (service *EncryptionKey) LoadByParentID(session data.Session, parentType st
  * Custom Actions
  ******************************************/
 
+// Create generates and saves the original EncryptionKey for the designated parent
 func (service *EncryptionKey) Create(session data.Session, parentType string, parentID primitive.ObjectID) (model.EncryptionKey, error) {
+	return service.create(session, parentType, parentID, false)
+}
+
+// create generates and saves a new EncryptionKey.  Keys created by rotation
+// are given their own URL fragment, so that they never reuse an earlier key's ID.
+func (service *EncryptionKey) create(session data.Session, parentType string, parentID primitive.ObjectID, rotated bool) (model.EncryptionKey, error) {
 
 	// Create new model object
 	encryptionKey := model.NewEncryptionKey()
 	encryptionKey.ParentType = parentType
 	encryptionKey.ParentID = parentID
-	encryptionKey.Encoding = model.EncryptionKeyEncodingPlaintext // TODO: MEDIUM: add key encryption encoding
+
+	if rotated {
+		encryptionKey.NameFragment()
+	}
 
 	// Create an actual encryption key
 	privateKey, err := rsa.GenerateKey(rand.Reader, encryptionKeyBits)
@@ -160,9 +227,30 @@ func (service *EncryptionKey) Create(session data.Session, parentType string, pa
 		return model.EncryptionKey{}, derp.Wrap(err, "model.CreateEncryptionKey", "Generating RSA key", parentType, parentID)
 	}
 
-	encryptionKey.PrivatePEM = sigs.EncodePrivatePEM(privateKey)
+	// Encrypt the private key before it is saved
+	masterKey, err := service.getMasterKey()
+
+	if err != nil {
+		return model.EncryptionKey{}, derp.Wrap(err, "model.CreateEncryptionKey", "Reading master key")
+	}
+
+	if err := encryptionKey.SetPrivatePEM(sigs.EncodePrivatePEM(privateKey), masterKey); err != nil {
+		return model.EncryptionKey{}, derp.Wrap(err, "model.CreateEncryptionKey", "Encrypting private key", parentType, parentID)
+	}
+
 	encryptionKey.PublicPEM = sigs.EncodePublicPEM(privateKey)
 
+	// Create the Ed25519 key that signs RFC 9421 requests
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+
+	if err != nil {
+		return model.EncryptionKey{}, derp.Wrap(err, "model.CreateEncryptionKey", "Generating Ed25519 key", parentType, parentID)
+	}
+
+	if err := encryptionKey.SetEd25519(ed25519Key, masterKey); err != nil {
+		return model.EncryptionKey{}, derp.Wrap(err, "model.CreateEncryptionKey", "Encrypting Ed25519 key", parentType, parentID)
+	}
+
 	if err := service.Save(session, &encryptionKey, "Created"); err != nil {
 		return model.EncryptionKey{}, derp.Wrap(err, "model.CreateEncryptionKey", "Saving new EncryptionKey", parentType, parentID)
 	}
@@ -189,6 +277,122 @@ func (service *EncryptionKey) DeleteByParentID(session data.Session, parentID pr
 	return nil
 }
 
+// addEd25519 generates an Ed25519 key for an existing EncryptionKey, and saves it.
+func (service *EncryptionKey) addEd25519(session data.Session, encryptionKey *model.EncryptionKey) error {
+
+	const location = "service.EncryptionKey.addEd25519"
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Generating Ed25519 key", encryptionKey.EncryptionKeyID)
+	}
+
+	masterKey, err := service.getMasterKey()
+
+	if err != nil {
+		return derp.Wrap(err, location, "Reading master key")
+	}
+
+	if err := encryptionKey.SetEd25519(privateKey, masterKey); err != nil {
+		return derp.Wrap(err, location, "Encrypting Ed25519 key", encryptionKey.EncryptionKeyID)
+	}
+
+	if err := service.Save(session, encryptionKey, "Added Ed25519 key"); err != nil {
+		return derp.Wrap(err, location, "Saving EncryptionKey", encryptionKey.EncryptionKeyID)
+	}
+
+	return nil
+}
+
+// Rotate replaces the current key for the designated parent with a newly generated key.
+// The previous key is retired, and continues to verify until the grace period has passed.
+// Retired keys that have already expired are removed.
+func (service *EncryptionKey) Rotate(session data.Session, parentType string, parentID primitive.ObjectID) (model.EncryptionKey, error) {
+
+	const location = "service.EncryptionKey.Rotate"
+
+	now := time.Now()
+	expireDate := now.Add(encryptionKeyGracePeriod).Unix()
+
+	// Retire all previous keys for this parent
+	criteria := exp.Equal("parentType", parentType).
+		AndEqual("parentId", parentID)
+
+	keys, err := service.Range(session, criteria)
+
+	if err != nil {
+		return model.EncryptionKey{}, derp.Wrap(err, location, "Loading keys", parentType, parentID)
+	}
+
+	for key := range keys {
+
+		// Remove retired keys that have passed their grace period
+		if !key.IsVerifiable(now.Unix()) {
+			if err := service.Delete(session, &key, "Expired"); err != nil {
+				return model.EncryptionKey{}, derp.Wrap(err, location, "Deleting expired key", key.EncryptionKeyID)
+			}
+			continue
+		}
+
+		// Retire the current key
+		if key.IsCurrent() {
+			key.Retire(now.Unix(), expireDate)
+
+			if err := service.Save(session, &key, "Rotated"); err != nil {
+				return model.EncryptionKey{}, derp.Wrap(err, location, "Retiring key", key.EncryptionKeyID)
+			}
+		}
+	}
+
+	// Create the new key
+	result, err := service.create(session, parentType, parentID, true)
+
+	if err != nil {
+		return model.EncryptionKey{}, derp.Wrap(err, location, "Creating new key", parentType, parentID)
+	}
+
+	return result, nil
+}
+
+// ReEncrypt decrypts every EncryptionKey with the current master key, and encrypts it again
+// with the `newMasterKey` (hex-encoded). Legacy plaintext keys are encrypted for the first time.
+func (service *EncryptionKey) ReEncrypt(session data.Session, newMasterKey string) error {
+
+	const location = "service.EncryptionKey.ReEncrypt"
+
+	oldKey, err := service.getMasterKey()
+
+	if err != nil {
+		return derp.Wrap(err, location, "Reading current master key")
+	}
+
+	newKey, err := hex.DecodeString(newMasterKey)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Reading new master key")
+	}
+
+	keys, err := service.Range(session, exp.All())
+
+	if err != nil {
+		return derp.Wrap(err, location, "Loading keys")
+	}
+
+	for key := range keys {
+
+		if err := key.ReEncrypt(oldKey, newKey); err != nil {
+			return derp.Wrap(err, location, "Re-encrypting key", key.EncryptionKeyID)
+		}
+
+		if err := service.Save(session, &key, "Re-encrypted with new master key"); err != nil {
+			return derp.Wrap(err, location, "Saving key", key.EncryptionKeyID)
+		}
+	}
+
+	return nil
+}
+
 /******************************************
  * Data Accessors
  ******************************************/
@@ -214,8 +418,21 @@ func (service *EncryptionKey) GetPrivateKey(encryptionKey *model.EncryptionKey)
 
 	const location = "service.EncryptionKey.GetPrivateKey"
 
+	// Decrypt the private key
+	masterKey, err := service.getMasterKey()
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Reading master key")
+	}
+
+	privatePEM, err := encryptionKey.GetPrivatePEM(masterKey)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Decrypting private key", encryptionKey.EncryptionKeyID)
+	}
+
 	// Decode PEM block
-	block, _ := pem.Decode([]byte(encryptionKey.PrivatePEM))
+	block, _ := pem.Decode([]byte(privatePEM))
 
 	if block == nil {
 		return nil, derp.Internal(location, "Unable to decode private key PEM", encryptionKey.EncryptionKeyID)
@@ -235,6 +452,26 @@ func (service *EncryptionKey) GetPrivateKey(encryptionKey *model.EncryptionKey)
 	return privateKey, nil
 }
 
+// GetEd25519PrivateKey returns the decrypted Ed25519 key that signs RFC 9421 requests
+func (service *EncryptionKey) GetEd25519PrivateKey(encryptionKey *model.EncryptionKey) (ed25519.PrivateKey, error) {
+
+	const location = "service.EncryptionKey.GetEd25519PrivateKey"
+
+	masterKey, err := service.getMasterKey()
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Reading master key")
+	}
+
+	privateKey, err := encryptionKey.GetEd25519(masterKey)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Decrypting Ed25519 key", encryptionKey.EncryptionKeyID)
+	}
+
+	return privateKey, nil
+}
+
 func (service *EncryptionKey) Sign(message []byte, encryptionKey *model.EncryptionKey) ([]byte, error) {
 
 	privateKey, err := service.GetPrivateKey(encryptionKey)
@@ -273,5 +510,127 @@ func (service *EncryptionKey) OwnerID(encryptionKey *model.EncryptionKey) string
 
 // KeyID returns the publicly accessible URL of this EncryptionKey
 func (service *EncryptionKey) KeyID(encryptionKey *model.EncryptionKey) string {
-	return service.OwnerID(encryptionKey) + "#main-key"
+	return encryptionKeyID(service.OwnerID(encryptionKey), encryptionKey)
+}
+
+// Ed25519KeyID returns the publicly accessible URL of this EncryptionKey's Ed25519 key
+func (service *EncryptionKey) Ed25519KeyID(encryptionKey *model.EncryptionKey) string {
+	return ed25519KeyID(service.OwnerID(encryptionKey), encryptionKey)
+}
+
+// PublicKeyJSONLD returns the "publicKey" property for an actor document.  This is usually
+// a single key, but it also includes retired keys that are still inside their grace period.
+// The current key is always listed first, because many servers only read the first value.
+func (service *EncryptionKey) PublicKeyJSONLD(session data.Session, parentType string, parentID primitive.ObjectID, ownerID string) (any, error) {
+
+	const location = "service.EncryptionKey.PublicKeyJSONLD"
+
+	// Make sure that the current key exists (creating it if necessary)
+	current := model.NewEncryptionKey()
+
+	if err := service.LoadByParentID(session, parentType, parentID, &current); err != nil {
+		return nil, derp.Wrap(err, location, "Loading current key", parentType, parentID)
+	}
+
+	result := sliceof.Any{encryptionKeyJSONLD(ownerID, &current)}
+
+	// Append any retired keys that are still verifiable
+	keys, err := service.RangeVerifiable(session, parentType, parentID)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Loading retired keys", parentType, parentID)
+	}
+
+	for key := range keys {
+		if !key.IsCurrent() {
+			result = append(result, encryptionKeyJSONLD(ownerID, &key))
+		}
+	}
+
+	// Most of the time, there is only one key
+	if len(result) == 1 {
+		return result[0], nil
+	}
+
+	return result, nil
+}
+
+// AssertionMethodJSONLD returns the "assertionMethod" property for an actor document (FEP-521a),
+// which publishes each verifiable Ed25519 key as a Multikey.  The current key is listed first.
+func (service *EncryptionKey) AssertionMethodJSONLD(session data.Session, parentType string, parentID primitive.ObjectID, ownerID string) (sliceof.Any, error) {
+
+	const location = "service.EncryptionKey.AssertionMethodJSONLD"
+
+	// Make sure that the current key exists (creating it, or its Ed25519 key, if necessary)
+	current := model.NewEncryptionKey()
+
+	if err := service.LoadByParentID(session, parentType, parentID, &current); err != nil {
+		return nil, derp.Wrap(err, location, "Loading current key", parentType, parentID)
+	}
+
+	result := sliceof.Any{ed25519KeyJSONLD(ownerID, &current)}
+
+	// Append any retired keys that are still verifiable
+	keys, err := service.RangeVerifiable(session, parentType, parentID)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Loading retired keys", parentType, parentID)
+	}
+
+	for key := range keys {
+		if !key.IsCurrent() && key.HasEd25519() {
+			result = append(result, ed25519KeyJSONLD(ownerID, &key))
+		}
+	}
+
+	return result, nil
+}
+
+// getMasterKey returns the decoded master key for this domain
+func (service *EncryptionKey) getMasterKey() ([]byte, error) {
+
+	masterKey, err := hex.DecodeString(service.masterKey)
+
+	if err != nil {
+		return nil, derp.Wrap(err, "service.EncryptionKey.getMasterKey", "Invalid master key")
+	}
+
+	return masterKey, nil
+}
+
+// encryptionKeyID returns the public ID of an EncryptionKey.  Each key keeps the same ID for
+// its entire life, so signatures made before a rotation still resolve to the key that made them.
+func encryptionKeyID(ownerID string, encryptionKey *model.EncryptionKey) string {
+	return ownerID + "#" + encryptionKey.PublicKeyFragment()
+}
+
+// encryptionKeyJSONLD returns the JSON-LD representation of a single public key
+func encryptionKeyJSONLD(ownerID string, encryptionKey *model.EncryptionKey) mapof.Any {
+	return mapof.Any{
+		vocab.PropertyID:           encryptionKeyID(ownerID, encryptionKey),
+		vocab.PropertyType:         "Key",
+		vocab.PropertyOwner:        ownerID,
+		vocab.PropertyPublicKeyPEM: encryptionKey.PublicPEM,
+	}
+}
+
+// ed25519KeyID returns the public ID of an EncryptionKey's Ed25519 key.  Like encryptionKeyID,
+// this never changes when the key is retired.
+func ed25519KeyID(ownerID string, encryptionKey *model.EncryptionKey) string {
+	return ownerID + "#" + encryptionKey.Ed25519KeyFragment()
+}
+
+// ed25519KeyJSONLD returns the JSON-LD representation of a single Ed25519 key, as a Multikey
+func ed25519KeyJSONLD(ownerID string, encryptionKey *model.EncryptionKey) mapof.Any {
+	return multikeyJSONLD(ed25519KeyID(ownerID, encryptionKey), ownerID, encryptionKey.PublicMultibase)
+}
+
+// multikeyJSONLD returns the JSON-LD representation of a Multikey verification method
+func multikeyJSONLD(keyID string, controller string, publicKeyMultibase string) mapof.Any {
+	return mapof.Any{
+		vocab.PropertyID:     keyID,
+		vocab.PropertyType:   multikey.Type,
+		"controller":         controller,
+		"publicKeyMultibase": publicKeyMultibase,
+	}
 }
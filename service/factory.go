@@ -60,8 +60,11 @@ type Factory struct {
 
 	// services (within this domain/factory)
 	activityStream          ActivityStream
+	activityPubDelivery     ActivityPubDelivery
 	annotationService       Annotation
 	attachmentService       Attachment
+	blocklistService        Blocklist
+	bookmarkService         Bookmark
 	circleService           Circle
 	collectionService       Collection
 	collectionItemService   CollectionItem
@@ -69,7 +72,9 @@ type Factory struct {
 	domainService           Domain
 	emailService            DomainEmail
 	encryptionKeyService    EncryptionKey
+	filterService           Filter
 	folderService           Folder
+	followedTagService      FollowedTag
 	followerService         Follower
 	followingService        Following
 	groupService            Group
@@ -87,12 +92,16 @@ type Factory struct {
 	objectService           Object
 	outboxService           Outbox
 	outbox2Service          Outbox2
+	passkeyService          Passkey
 	permissionService       Permission
+	pollVoteService         PollVote
 	productService          Product
 	providerService         Provider
 	pushSubscriptionService PushSubscription
+	relayService            Relay
 	responseService         Response
 	webPushService          WebPush
+	reportService           Report
 	ruleService             Rule
 	ruleSuppressionService  RuleSuppression
 	searchDomainService     SearchDomain
@@ -106,6 +115,7 @@ type Factory struct {
 	realtimeBroker          *realtime.Broker
 	userService             User
 	webhookService          Webhook
+	webhookDeliveryService  WebhookDelivery
 
 	// real-time watchers
 	refreshContext   context.CancelFunc
@@ -150,8 +160,11 @@ func NewFactory(serverFactory ServerFactory, domain config.Domain, port string,
 	// 2. reload service configurations separate from the services themselves.
 
 	factory.activityStream = NewActivityStream()
+	factory.activityPubDelivery = NewActivityPubDelivery()
 	factory.annotationService = NewAnnotation()
 	factory.attachmentService = NewAttachment()
+	factory.blocklistService = NewBlocklist()
+	factory.bookmarkService = NewBookmark()
 	factory.circleService = NewCircle()
 	factory.collectionService = NewCollection()
 	factory.collectionItemService = NewCollectionItem()
@@ -159,7 +172,9 @@ func NewFactory(serverFactory ServerFactory, domain config.Domain, port string,
 	factory.domainService = NewDomain()
 	factory.emailService = NewDomainEmail()
 	factory.encryptionKeyService = NewEncryptionKey()
+	factory.filterService = NewFilter()
 	factory.folderService = NewFolder()
+	factory.followedTagService = NewFollowedTag()
 	factory.followerService = NewFollower()
 	factory.followingService = NewFollowing()
 	factory.groupService = NewGroup()
@@ -177,14 +192,18 @@ func NewFactory(serverFactory ServerFactory, domain config.Domain, port string,
 	factory.objectService = NewObject()
 	factory.outboxService = NewOutbox()
 	factory.outbox2Service = NewOutbox2()
+	factory.passkeyService = NewPasskey()
 	factory.permissionService = NewPermission()
+	factory.pollVoteService = NewPollVote()
 	factory.productService = NewProduct()
 	factory.providerService = NewProvider()
 	factory.pushSubscriptionService = NewPushSubscription()
 	factory.webPushService = NewWebPush()
 	factory.collectionItemService = NewCollectionItem()
+	factory.relayService = NewRelay()
 	factory.responseService = NewResponse()
 	factory.realtimeBroker = realtime.NewBroker(factory.SSEUpdateChannel())
+	factory.reportService = NewReport()
 	factory.ruleService = NewRule()
 	factory.ruleSuppressionService = NewRuleSuppression()
 	factory.searchDomainService = NewSearchDomain()
@@ -197,6 +216,7 @@ func NewFactory(serverFactory ServerFactory, domain config.Domain, port string,
 	factory.privilegeService = NewPrivilege()
 	factory.userService = NewUser()
 	factory.webhookService = NewWebhook()
+	factory.webhookDeliveryService = NewWebhookDelivery()
 
 	// Refresh the configuration with values that (may) change during the lifetime of the factory
 	if err := factory.Refresh(domain, attachmentOriginals, attachmentCache); err != nil {
@@ -225,8 +245,11 @@ func (factory *Factory) Refresh(newConfig config.Domain, attachmentOriginals afe
 
 	// Refresh all services
 	factory.activityStream.Refresh(factory)
+	factory.activityPubDelivery.Refresh(factory)
 	factory.annotationService.Refresh(factory)
 	factory.attachmentService.Refresh(factory)
+	factory.blocklistService.Refresh(factory)
+	factory.bookmarkService.Refresh(factory)
 	factory.circleService.Refresh(factory)
 	factory.collectionService.Refresh(factory)
 	factory.collectionItemService.Refresh(factory)
@@ -234,7 +257,9 @@ func (factory *Factory) Refresh(newConfig config.Domain, attachmentOriginals afe
 	factory.domainService.Refresh(factory)
 	factory.emailService.Refresh(factory)
 	factory.encryptionKeyService.Refresh(factory)
+	factory.filterService.Refresh(factory)
 	factory.folderService.Refresh(factory)
+	factory.followedTagService.Refresh(factory)
 	factory.followerService.Refresh(factory)
 	factory.followingService.Refresh(factory)
 	factory.groupService.Refresh(factory)
@@ -252,14 +277,18 @@ func (factory *Factory) Refresh(newConfig config.Domain, attachmentOriginals afe
 	factory.objectService.Refresh(factory)
 	factory.outboxService.Refresh(factory)
 	factory.outbox2Service.Refresh(factory)
+	factory.passkeyService.Refresh(factory)
 	factory.permissionService.Refresh(factory)
+	factory.pollVoteService.Refresh(factory)
 	factory.productService.Refresh(factory)
 	factory.providerService.Refresh(factory)
 	factory.pushSubscriptionService.Refresh(factory)
 	factory.webPushService.Refresh(factory)
 	factory.collectionItemService.Refresh(factory)
 	factory.realtimeBroker.Refresh()
+	factory.relayService.Refresh(factory)
 	factory.responseService.Refresh(factory)
+	factory.reportService.Refresh(factory)
 	factory.ruleService.Refresh(factory)
 	factory.ruleSuppressionService.Refresh(factory)
 	factory.searchDomainService.Refresh(factory)
@@ -272,6 +301,7 @@ func (factory *Factory) Refresh(newConfig config.Domain, attachmentOriginals afe
 	factory.privilegeService.Refresh(factory)
 	factory.userService.Refresh(factory)
 	factory.webhookService.Refresh(factory)
+	factory.webhookDeliveryService.Refresh(factory)
 
 	// If the database connect string has changed,
 	// then reconnect to the new database
@@ -295,16 +325,36 @@ func (factory *Factory) Refresh(newConfig config.Domain, attachmentOriginals afe
 			return derp.Wrap(err, location, "Starting domain service", newConfig)
 		}
 
+		// REALTIME TRANSPORT
+
+		// Share realtime messages with every other node that serves this domain
+		factory.realtimeBroker.SetTransport(factory.newRealtimeTransport())
+
 		// REALTIME WATCHERS
+		// On a replica set, every node watches the database directly, so these messages are only delivered locally.
+		// On a standalone server, the services publish their changes through the realtime transport instead (see realtime/README.md)
+		isReplicaSet, err := queries.IsReplicaSet(refreshContext, factory.server)
+
+		if err != nil {
+			derp.Report(derp.Wrap(err, location, "Checking for a MongoDB replica set. Publishing changes through the realtime transport."))
+		}
 
-		// Watch for updates to Import records
-		go queries.WatchImports(refreshContext, factory.server, factory.sseUpdateChannel)
+		factory.realtimeBroker.SetDatabaseWatchers(isReplicaSet)
 
-		// Watch for updates to Stream records
-		go queries.WatchStreams(refreshContext, factory.server, factory.sseUpdateChannel)
+		if isReplicaSet {
 
-		// Watch for updates to User records
-		go queries.WatchUsers(refreshContext, factory.server, factory.sseUpdateChannel)
+			// Watch for updates to Import records
+			go queries.WatchImports(refreshContext, factory.server, factory.realtimeBroker.LocalChannel())
+
+			// Watch for updates to Stream records
+			go queries.WatchStreams(refreshContext, factory.server, factory.realtimeBroker.LocalChannel())
+
+			// Watch for updates to User records
+			go queries.WatchUsers(refreshContext, factory.server, factory.realtimeBroker.LocalChannel())
+
+		} else {
+			log.Info().Str("loc", location).Msg("MongoDB is not a replica set. Stream, User, and Import updates are published through the realtime transport.")
+		}
 	}
 
 	return nil
@@ -409,6 +459,11 @@ func (factory *Factory) ActivityStream() *ActivityStream {
 	return &factory.activityStream
 }
 
+// ActivityPubDelivery returns a fully populated ActivityPubDelivery service
+func (factory *Factory) ActivityPubDelivery() *ActivityPubDelivery {
+	return &factory.activityPubDelivery
+}
+
 // Annotation returns a fully populated Annotation service
 func (factory *Factory) Annotation() *Annotation {
 	return &factory.annotationService
@@ -419,6 +474,16 @@ func (factory *Factory) Attachment() *Attachment {
 	return &factory.attachmentService
 }
 
+// Blocklist returns a fully populated Blocklist service
+func (factory *Factory) Blocklist() *Blocklist {
+	return &factory.blocklistService
+}
+
+// Bookmark returns a fully populated Bookmark service
+func (factory *Factory) Bookmark() *Bookmark {
+	return &factory.bookmarkService
+}
+
 // Circle returns a fully populated Circle service
 func (factory *Factory) Circle() *Circle {
 	return &factory.circleService
@@ -449,6 +514,11 @@ func (factory *Factory) EncryptionKey() *EncryptionKey {
 	return &factory.encryptionKeyService
 }
 
+// Filter returns a fully populated Filter service
+func (factory *Factory) Filter() *Filter {
+	return &factory.filterService
+}
+
 // Follower returns a fully populated Follower service
 func (factory *Factory) Follower() *Follower {
 	return &factory.followerService
@@ -464,6 +534,11 @@ func (factory *Factory) Folder() *Folder {
 	return &factory.folderService
 }
 
+// FollowedTag returns a fully populated FollowedTag service
+func (factory *Factory) FollowedTag() *FollowedTag {
+	return &factory.followedTagService
+}
+
 // GeocodeAddress returns a fully populated Geocode service
 func (factory *Factory) GeocodeAddress() GeocodeAddress {
 	return NewGeocodeAddress(factory.Hostname(), factory.Queue(), factory.Connection(), factory.GeocodeTimezone())
@@ -566,6 +641,11 @@ func (factory *Factory) Permission() *Permission {
 	return &factory.permissionService
 }
 
+// PollVote returns a fully populated PollVote service
+func (factory *Factory) PollVote() *PollVote {
+	return &factory.pollVoteService
+}
+
 // Privilege returns a fully populated Privilege service
 func (factory *Factory) Privilege() *Privilege {
 	return &factory.privilegeService
@@ -576,11 +656,21 @@ func (factory *Factory) Product() *Product {
 	return &factory.productService
 }
 
+// Relay returns a fully populated Relay service
+func (factory *Factory) Relay() *Relay {
+	return &factory.relayService
+}
+
 // Response returns a fully populated Response service
 func (factory *Factory) Response() *Response {
 	return &factory.responseService
 }
 
+// Report returns a fully populated Report service
+func (factory *Factory) Report() *Report {
+	return &factory.reportService
+}
+
 // Rule returns a fully populated Rule service
 func (factory *Factory) Rule() *Rule {
 	return &factory.ruleService
@@ -652,6 +742,11 @@ func (factory *Factory) Webhook() *Webhook {
 	return &factory.webhookService
 }
 
+// WebhookDelivery returns a fully populated WebhookDelivery service
+func (factory *Factory) WebhookDelivery() *WebhookDelivery {
+	return &factory.webhookDeliveryService
+}
+
 /******************************************
  * Render Objects
  ******************************************/
@@ -675,6 +770,19 @@ func (factory *Factory) RealtimeBroker() *realtime.Broker {
 	return factory.realtimeBroker
 }
 
+// newRealtimeTransport returns the realtime.Transport configured for this server.  The MEMORY
+// transport only reaches clients on this node, so it is only suitable for single-node servers and tests.
+func (factory *Factory) newRealtimeTransport() realtime.Transport {
+
+	mode := factory.serverFactory.RealtimeTransport()
+
+	if mode == realtime.TransportMemory {
+		return realtime.NewMemoryTransport()
+	}
+
+	return queries.NewRealtimeTransport(factory.server, mode)
+}
+
 // SSEUpdateChannel initializes a background watcher and returns a channel containing any streams that have changed.
 func (factory *Factory) SSEUpdateChannel() chan realtime.Message {
 	return factory.sseUpdateChannel
@@ -842,6 +950,11 @@ func (factory *Factory) Provider() *Provider {
 	return &factory.providerService
 }
 
+// Passkey returns a fully populated Passkey service
+func (factory *Factory) Passkey() *Passkey {
+	return &factory.passkeyService
+}
+
 // PushSubscription returns a fully populated PushSubscription service
 func (factory *Factory) PushSubscription() *PushSubscription {
 	return &factory.pushSubscriptionService
@@ -960,6 +1073,9 @@ func (factory *Factory) Model(name string) (ModelService, error) {
 	case "folder":
 		return factory.Folder(), nil
 
+	case "followedTag":
+		return factory.FollowedTag(), nil
+
 	case "follower":
 		return factory.Follower(), nil
 
@@ -1000,12 +1116,18 @@ func (factory *Factory) ModelService(object data.Object) ModelService {
 	case *model.Annotation:
 		return factory.Annotation()
 
+	case *model.Blocklist:
+		return factory.Blocklist()
+
 	case *model.Circle:
 		return factory.Circle()
 
 	case *model.Folder:
 		return factory.Folder()
 
+	case *model.FollowedTag:
+		return factory.FollowedTag()
+
 	case *model.Follower:
 		return factory.Follower()
 
@@ -1033,9 +1155,15 @@ func (factory *Factory) ModelService(object data.Object) ModelService {
 	case *model.OAuthUserToken:
 		return factory.OAuthUserToken()
 
+	case *model.Relay:
+		return factory.Relay()
+
 	case *model.Response:
 		return factory.Response()
 
+	case *model.Report:
+		return factory.Report()
+
 	case *model.Rule:
 		return factory.Rule()
 
@@ -1057,12 +1185,16 @@ func (factory *Factory) Collections() []string {
 	return []string{
 		"Annotation",
 		"Attachment",
+		"Blocklist",
+		"Bookmark",
 		"Circle",
 		"Connection",
 		"Collection",
 		"Domain",
 		"EncryptionKey",
+		"Filter",
 		"Folder",
+		"FollowedTag",
 		"Follower",
 		"Following",
 		"Group",
@@ -1075,9 +1207,13 @@ func (factory *Factory) Collections() []string {
 		"OAuthClient",
 		"OAuthUserToken",
 		"Outbox",
+		"Passkey",
+		"PollVote",
 		"Privilege",
 		"Product",
 		"PushSubscription",
+		"Relay",
+		"Report",
 		"Response",
 		"Rule",
 		"SearchQuery",
@@ -1088,6 +1224,7 @@ func (factory *Factory) Collections() []string {
 		"StreamOutbox",
 		"User",
 		"Webhook",
+		"WebhookDelivery",
 	}
 }
 
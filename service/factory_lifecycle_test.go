@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/EmissarySocial/emissary/realtime"
 	mongodb "github.com/benpate/data-mongo"
 	"github.com/benpate/derp"
 	"github.com/benpate/digital-dome/dome"
@@ -52,6 +53,10 @@ func (factory *lifecycleServerFactory) CommonDatabase() *mongo.Database {
 	return factory.database
 }
 
+func (factory *lifecycleServerFactory) RealtimeTransport() string {
+	return realtime.TransportMemory
+}
+
 // lazyLifecycleDatabase returns a *mongo.Database whose client never contacts a server
 // (mongo.Connect is lazy), so these tests run without a reachable Mongo.
 func lazyLifecycleDatabase(t *testing.T, database string) *mongo.Database {
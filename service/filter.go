@@ -0,0 +1,211 @@
+package service
+
+import (
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Filter manages the keyword and status Filters that each User applies to their timelines.
+type Filter struct{}
+
+// NewFilter returns a fully initialized Filter service
+func NewFilter() Filter {
+	return Filter{}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+// Refresh updates any stateful data that is cached inside this service.
+func (service *Filter) Refresh(factory *Factory) {
+	// Nothing to refresh.
+}
+
+// Close stops any background processes controlled by this service
+func (service *Filter) Close() {
+	// Nothin to do here.
+}
+
+/******************************************
+ * Common Data Methods
+ ******************************************/
+
+func (service *Filter) collection(session data.Session) data.Collection {
+	return session.Collection("Filter")
+}
+
+// Count returns the number of Filters that match the provided criteria
+func (service *Filter) Count(session data.Session, criteria exp.Expression) (int64, error) {
+	return service.collection(session).Count(notDeleted(criteria))
+}
+
+// Query returns a slice of Filters that match the provided criteria
+func (service *Filter) Query(session data.Session, criteria exp.Expression, options ...option.Option) ([]model.Filter, error) {
+	result := make([]model.Filter, 0)
+	err := service.collection(session).Query(&result, notDeleted(criteria), options...)
+	return result, err
+}
+
+// Load retrieves a Filter from the database
+func (service *Filter) Load(session data.Session, criteria exp.Expression, filter *model.Filter) error {
+
+	if err := service.collection(session).Load(notDeleted(criteria), filter); err != nil {
+		return derp.Wrap(err, "service.Filter.Load", "Loading Filter", criteria)
+	}
+
+	return nil
+}
+
+// Save adds/updates a Filter in the database
+func (service *Filter) Save(session data.Session, filter *model.Filter, note string) error {
+
+	const location = "service.Filter.Save"
+
+	if _, err := service.Schema().Validate(filter); err != nil {
+		return derp.Wrap(err, location, "Validating Filter", filter)
+	}
+
+	// RULE: A Filter must apply somewhere
+	if len(filter.Contexts) == 0 {
+		return derp.Validation("Filter must apply to at least one context", filter)
+	}
+
+	if err := service.collection(session).Save(filter, note); err != nil {
+		return derp.Wrap(err, location, "Saving Filter", filter, note)
+	}
+
+	return nil
+}
+
+// Delete removes a Filter from the database (hard delete)
+func (service *Filter) Delete(session data.Session, filter *model.Filter, note string) error {
+
+	const location = "service.Filter.Delete"
+
+	// Hard delete, never virtual: a Filter is private to its User, so there is nothing to restore
+	if err := service.collection(session).HardDelete(exp.Equal("_id", filter.FilterID)); err != nil {
+		return derp.Wrap(err, location, "Deleting Filter", filter, note)
+	}
+
+	return nil
+}
+
+func (service *Filter) Schema() schema.Schema {
+	return schema.New(model.FilterSchema())
+}
+
+/******************************************
+ * Custom Queries
+ ******************************************/
+
+// QueryByUser returns all Filters owned by a User, oldest first
+func (service *Filter) QueryByUser(session data.Session, userID primitive.ObjectID) ([]model.Filter, error) {
+	criteria := exp.Equal("userId", userID)
+	return service.Query(session, criteria, option.SortAsc("createDate"))
+}
+
+// QueryActive returns the Filters owned by a User that apply to the provided context and have not
+// yet expired. `now` is the current Unix time in seconds.
+func (service *Filter) QueryActive(session data.Session, userID primitive.ObjectID, context string, now int64) ([]model.Filter, error) {
+
+	criteria := exp.Equal("userId", userID).
+		AndEqual("contexts", context).
+		And(exp.Equal("expireDate", 0).OrGreaterThan("expireDate", now))
+
+	return service.Query(session, criteria)
+}
+
+// Match returns the User's active Filters for the provided context that match a
+// document's text or URL.
+func (service *Filter) Match(session data.Session, userID primitive.ObjectID, context string, text string, url string) (model.FilterResults, error) {
+
+	const location = "service.Filter.Match"
+
+	filters, err := service.QueryActive(session, userID, context, time.Now().Unix())
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Querying active filters", userID, context)
+	}
+
+	return model.MatchFilters(filters, text, url), nil
+}
+
+// LoadByID loads a Filter that belongs to the provided User
+func (service *Filter) LoadByID(session data.Session, userID primitive.ObjectID, filterID primitive.ObjectID, filter *model.Filter) error {
+	criteria := exp.Equal("_id", filterID).AndEqual("userId", userID)
+	return service.Load(session, criteria, filter)
+}
+
+// LoadByToken loads a Filter that belongs to the provided User, using a string representation of its ID
+func (service *Filter) LoadByToken(session data.Session, userID primitive.ObjectID, token string, filter *model.Filter) error {
+
+	filterID, err := primitive.ObjectIDFromHex(token)
+
+	if err != nil {
+		return derp.BadRequest("service.Filter.LoadByToken", "Invalid FilterID", token)
+	}
+
+	return service.LoadByID(session, userID, filterID, filter)
+}
+
+// LoadByKeywordToken loads the Filter that contains the provided keyword
+func (service *Filter) LoadByKeywordToken(session data.Session, userID primitive.ObjectID, token string, filter *model.Filter) (model.FilterKeyword, error) {
+
+	const location = "service.Filter.LoadByKeywordToken"
+
+	keywordID, err := primitive.ObjectIDFromHex(token)
+
+	if err != nil {
+		return model.FilterKeyword{}, derp.BadRequest(location, "Invalid KeywordID", token)
+	}
+
+	criteria := exp.Equal("userId", userID).AndEqual("keywords.keywordId", keywordID)
+
+	if err := service.Load(session, criteria, filter); err != nil {
+		return model.FilterKeyword{}, derp.Wrap(err, location, "Loading Filter", token)
+	}
+
+	keyword, _ := filter.KeywordByID(keywordID)
+	return keyword, nil
+}
+
+// LoadByStatusToken loads the Filter that contains the provided status filter
+func (service *Filter) LoadByStatusToken(session data.Session, userID primitive.ObjectID, token string, filter *model.Filter) (model.FilterStatus, error) {
+
+	const location = "service.Filter.LoadByStatusToken"
+
+	statusID, err := primitive.ObjectIDFromHex(token)
+
+	if err != nil {
+		return model.FilterStatus{}, derp.BadRequest(location, "Invalid StatusID", token)
+	}
+
+	criteria := exp.Equal("userId", userID).AndEqual("statuses.statusId", statusID)
+
+	if err := service.Load(session, criteria, filter); err != nil {
+		return model.FilterStatus{}, derp.Wrap(err, location, "Loading Filter", token)
+	}
+
+	status, _ := filter.StatusByID(statusID)
+	return status, nil
+}
+
+// DeleteByUserID removes every Filter owned by the provided User
+func (service *Filter) DeleteByUserID(session data.Session, userID primitive.ObjectID, note string) error {
+
+	const location = "service.Filter.DeleteByUserID"
+
+	if err := service.collection(session).HardDelete(exp.Equal("userId", userID)); err != nil {
+		return derp.Wrap(err, location, "Deleting Filters", userID, note)
+	}
+
+	return nil
+}
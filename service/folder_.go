@@ -17,11 +17,12 @@ import (
 
 // Folder manages all interactions with a user's Folder
 type Folder struct {
-	domainService     *Domain
-	followingService  *Following
-	importItemService *ImportItem
-	newsFeedService   *NewsFeed
-	themeService      *Theme
+	domainService      *Domain
+	followedTagService *FollowedTag
+	followingService   *Following
+	importItemService  *ImportItem
+	newsFeedService    *NewsFeed
+	themeService       *Theme
 }
 
 // NewFolder returns a fully populated Folder service
@@ -36,6 +37,7 @@ func NewFolder() Folder {
 // Refresh updates any stateful data that is cached inside this service.
 func (service *Folder) Refresh(factory *Factory) {
 	service.domainService = factory.Domain()
+	service.followedTagService = factory.FollowedTag()
 	service.followingService = factory.Following()
 	service.importItemService = factory.ImportItem()
 	service.newsFeedService = factory.NewsFeed()
@@ -146,6 +148,11 @@ func (service *Folder) Delete(session data.Session, folder *model.Folder, commen
 		return derp.Wrap(err, location, "Deleting related `Following` records.")
 	}
 
+	// Delete any followed hashtags
+	if err := service.followedTagService.DeleteByFolder(session, folder.UserID, folder.FolderID, comment); err != nil {
+		return derp.Wrap(err, location, "Deleting related `FollowedTag` records.")
+	}
+
 	return nil
 }
 
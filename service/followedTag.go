@@ -0,0 +1,392 @@
+package service
+
+import (
+	"iter"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FollowedTag manages all interactions with the #hashtags that Users follow
+type FollowedTag struct {
+	folderService *Folder
+	host          string
+}
+
+// NewFollowedTag returns a fully populated FollowedTag service
+func NewFollowedTag() FollowedTag {
+	return FollowedTag{}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+// Refresh updates any stateful data that is cached inside this service.
+func (service *FollowedTag) Refresh(factory *Factory) {
+	service.folderService = factory.Folder()
+	service.host = factory.Host()
+}
+
+// Close stops any background processes controlled by this service
+func (service *FollowedTag) Close() {
+
+}
+
+/******************************************
+ * Common Data Methods
+ ******************************************/
+
+func (service *FollowedTag) collection(session data.Session) data.Collection {
+	return session.Collection("FollowedTag")
+}
+
+// New creates a newly initialized FollowedTag that is ready to use
+func (service *FollowedTag) New() model.FollowedTag {
+	return model.NewFollowedTag()
+}
+
+// Count returns the number of records that match the provided criteria
+func (service *FollowedTag) Count(session data.Session, criteria exp.Expression) (int64, error) {
+	return service.collection(session).Count(notDeleted(criteria))
+}
+
+// Query returns a slice of FollowedTags that match the provided criteria
+func (service *FollowedTag) Query(session data.Session, criteria exp.Expression, options ...option.Option) ([]model.FollowedTag, error) {
+	result := []model.FollowedTag{}
+	err := service.collection(session).Query(&result, notDeleted(criteria), options...)
+	return result, err
+}
+
+// Range returns an iterator containing all of the FollowedTags that match the provided criteria
+func (service *FollowedTag) Range(session data.Session, criteria exp.Expression, options ...option.Option) (iter.Seq[model.FollowedTag], error) {
+
+	const location = "service.FollowedTag.Range"
+
+	iter, err := service.List(session, criteria, options...)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Creating iterator", criteria)
+	}
+
+	return RangeFunc(iter, model.NewFollowedTag), nil
+}
+
+// List returns an iterator containing all of the FollowedTags that match the provided criteria
+func (service *FollowedTag) List(session data.Session, criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.collection(session).Iterator(notDeleted(criteria), options...)
+}
+
+// Load retrieves a FollowedTag from the database
+func (service *FollowedTag) Load(session data.Session, criteria exp.Expression, result *model.FollowedTag) error {
+
+	const location = "service.FollowedTag.Load"
+
+	if err := service.collection(session).Load(notDeleted(criteria), result); err != nil {
+		return derp.Wrap(err, location, "Loading FollowedTag", criteria)
+	}
+
+	return nil
+}
+
+// Save adds/updates a FollowedTag in the database
+func (service *FollowedTag) Save(session data.Session, followedTag *model.FollowedTag, comment string) error {
+
+	const location = "service.FollowedTag.Save"
+
+	// RULE: Recalculate the normalized Value from the Name
+	followedTag.SetName(followedTag.Name)
+
+	// RULE: Tag name is required
+	if followedTag.Value == "" {
+		return derp.Validation("Hashtag is required", followedTag)
+	}
+
+	// RULE: Deliver into the User's first Folder if none is selected
+	if followedTag.FolderID.IsZero() {
+
+		folderID, err := service.defaultFolderID(session, followedTag.UserID)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Finding default folder", followedTag)
+		}
+
+		followedTag.FolderID = folderID
+	}
+
+	// RULE: Each User can only follow a tag once
+	existing := model.NewFollowedTag()
+	if err := service.LoadByValue(session, followedTag.UserID, followedTag.Value, &existing); err == nil {
+		if existing.FollowedTagID != followedTag.FollowedTagID {
+			return derp.Validation("You already follow this hashtag", followedTag.Name)
+		}
+	} else if !derp.IsNotFound(err) {
+		return derp.Wrap(err, location, "Checking for duplicate hashtag", followedTag)
+	}
+
+	// Validate the value before saving
+	if _, err := service.Schema().Validate(followedTag); err != nil {
+		return derp.Wrap(err, location, "Invalid FollowedTag data", followedTag)
+	}
+
+	// Save the value to the database
+	if err := service.collection(session).Save(followedTag, comment); err != nil {
+		return derp.Wrap(err, location, "Saving FollowedTag", followedTag, comment)
+	}
+
+	return nil
+}
+
+// Delete removes a FollowedTag from the database (virtual delete)
+func (service *FollowedTag) Delete(session data.Session, followedTag *model.FollowedTag, comment string) error {
+
+	const location = "service.FollowedTag.Delete"
+
+	if err := service.collection(session).Delete(followedTag, comment); err != nil {
+		return derp.Wrap(err, location, "Deleting FollowedTag", followedTag, comment)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Model Service Methods
+ ******************************************/
+
+// ObjectType returns the type of object that this service manages
+func (service *FollowedTag) ObjectType() string {
+	return "FollowedTag"
+}
+
+// New returns a fully initialized model.FollowedTag as a data.Object.
+func (service *FollowedTag) ObjectNew() data.Object {
+	result := model.NewFollowedTag()
+	return &result
+}
+
+func (service *FollowedTag) ObjectID(object data.Object) primitive.ObjectID {
+
+	if followedTag, ok := object.(*model.FollowedTag); ok {
+		return followedTag.FollowedTagID
+	}
+
+	return primitive.NilObjectID
+}
+
+func (service *FollowedTag) ObjectQuery(session data.Session, result any, criteria exp.Expression, options ...option.Option) error {
+	return service.collection(session).Query(result, notDeleted(criteria), options...)
+}
+
+func (service *FollowedTag) ObjectLoad(session data.Session, criteria exp.Expression) (data.Object, error) {
+	result := model.NewFollowedTag()
+	err := service.Load(session, criteria, &result)
+	return &result, err
+}
+
+func (service *FollowedTag) ObjectSave(session data.Session, object data.Object, comment string) error {
+	if followedTag, ok := object.(*model.FollowedTag); ok {
+		return service.Save(session, followedTag, comment)
+	}
+	return derp.Internal("service.FollowedTag.ObjectSave", "Invalid object type", object)
+}
+
+func (service *FollowedTag) ObjectDelete(session data.Session, object data.Object, comment string) error {
+	if followedTag, ok := object.(*model.FollowedTag); ok {
+		return service.Delete(session, followedTag, comment)
+	}
+	return derp.Internal("service.FollowedTag.ObjectDelete", "Invalid object type", object)
+}
+
+func (service *FollowedTag) ObjectUserCan(object data.Object, authorization model.Authorization, action string) error {
+	return derp.Unauthorized("service.FollowedTag", "Not Authorized")
+}
+
+func (service *FollowedTag) Schema() schema.Schema {
+	return schema.New(model.FollowedTagSchema())
+}
+
+/******************************************
+ * Custom Queries
+ ******************************************/
+
+// QueryByUser returns the FollowedTags for a single User that match the provided criteria
+func (service *FollowedTag) QueryByUser(session data.Session, userID primitive.ObjectID, criteria exp.Expression, options ...option.Option) ([]model.FollowedTag, error) {
+	criteria = criteria.AndEqual("userId", userID)
+	return service.Query(session, criteria, options...)
+}
+
+// QueryByUserID returns all FollowedTags for a single User, sorted by tag
+func (service *FollowedTag) QueryByUserID(session data.Session, userID primitive.ObjectID) ([]model.FollowedTag, error) {
+	return service.QueryByUser(session, userID, exp.All(), option.SortAsc("value"))
+}
+
+// RangeByValues returns an iterator containing every FollowedTag (for ALL Users) that matches one of the provided tag values
+func (service *FollowedTag) RangeByValues(session data.Session, values []string) (iter.Seq[model.FollowedTag], error) {
+	return service.Range(session, exp.In("value", values))
+}
+
+// LoadByID loads a single FollowedTag owned by the provided User
+func (service *FollowedTag) LoadByID(session data.Session, userID primitive.ObjectID, followedTagID primitive.ObjectID, result *model.FollowedTag) error {
+
+	criteria := exp.
+		Equal("_id", followedTagID).
+		AndEqual("userId", userID)
+
+	return service.Load(session, criteria, result)
+}
+
+// LoadByToken loads a single FollowedTag that matches the provided token
+func (service *FollowedTag) LoadByToken(session data.Session, userID primitive.ObjectID, token string, result *model.FollowedTag) error {
+
+	followedTagID, err := primitive.ObjectIDFromHex(token)
+
+	if err != nil {
+		return derp.BadRequest("service.FollowedTag.LoadByToken", "Invalid token", token)
+	}
+
+	return service.LoadByID(session, userID, followedTagID, result)
+}
+
+// LoadByValue loads the FollowedTag that matches the provided (normalized) tag value
+func (service *FollowedTag) LoadByValue(session data.Session, userID primitive.ObjectID, value string, result *model.FollowedTag) error {
+
+	criteria := exp.
+		Equal("userId", userID).
+		AndEqual("value", model.ToToken(value))
+
+	return service.Load(session, criteria, result)
+}
+
+// DeleteByFolder removes all FollowedTags that deliver into the provided Folder
+func (service *FollowedTag) DeleteByFolder(session data.Session, userID primitive.ObjectID, folderID primitive.ObjectID, comment string) error {
+
+	const location = "service.FollowedTag.DeleteByFolder"
+
+	criteria := exp.Equal("userId", userID).AndEqual("folderId", folderID)
+
+	if err := service.collection(session).HardDelete(criteria); err != nil {
+		return derp.Wrap(err, location, "Deleting FollowedTags", userID, folderID, comment)
+	}
+
+	return nil
+}
+
+// DeleteByUserID removes every FollowedTag owned by the provided User
+func (service *FollowedTag) DeleteByUserID(session data.Session, userID primitive.ObjectID, comment string) error {
+
+	const location = "service.FollowedTag.DeleteByUserID"
+
+	if err := service.collection(session).HardDelete(exp.Equal("userId", userID)); err != nil {
+		return derp.Wrap(err, location, "Deleting FollowedTags", userID, comment)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Other Behaviors
+ ******************************************/
+
+// Follow starts following a #hashtag on behalf of the provided User.
+// Following a tag that is already followed returns the existing record.
+func (service *FollowedTag) Follow(session data.Session, userID primitive.ObjectID, name string) (model.FollowedTag, error) {
+
+	const location = "service.FollowedTag.Follow"
+
+	result := model.NewFollowedTag()
+
+	// If the User already follows this tag, then there's nothing more to do
+	if err := service.LoadByValue(session, userID, name, &result); err == nil {
+		return result, nil
+	} else if !derp.IsNotFound(err) {
+		return result, derp.Wrap(err, location, "Loading FollowedTag", userID, name)
+	}
+
+	// Otherwise, create a new FollowedTag
+	result = model.NewFollowedTag()
+	result.UserID = userID
+	result.SetName(name)
+
+	if err := service.Save(session, &result, "Followed"); err != nil {
+		return result, derp.Wrap(err, location, "Saving FollowedTag", userID, name)
+	}
+
+	return result, nil
+}
+
+// Unfollow stops following a #hashtag on behalf of the provided User.
+// Unfollowing a tag that is not followed is not an error.
+func (service *FollowedTag) Unfollow(session data.Session, userID primitive.ObjectID, name string) error {
+
+	const location = "service.FollowedTag.Unfollow"
+
+	followedTag := model.NewFollowedTag()
+
+	if err := service.LoadByValue(session, userID, name, &followedTag); err != nil {
+
+		if derp.IsNotFound(err) {
+			return nil
+		}
+
+		return derp.Wrap(err, location, "Loading FollowedTag", userID, name)
+	}
+
+	if err := service.Delete(session, &followedTag, "Unfollowed"); err != nil {
+		return derp.Wrap(err, location, "Deleting FollowedTag", userID, name)
+	}
+
+	return nil
+}
+
+// References returns an OriginLink for each of the User's FollowedTags that
+// appears on the provided document.  These label inbox messages with the tags
+// that they carry.  Errors are reported, and return an empty slice.
+func (service *FollowedTag) References(session data.Session, userID primitive.ObjectID, document streams.Document) []model.OriginLink {
+
+	const location = "service.FollowedTag.References"
+
+	values := model.DocumentHashtags(document)
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	followedTags, err := service.QueryByUser(session, userID, exp.In("value", values))
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, location, "Querying FollowedTags", userID, values))
+		return nil
+	}
+
+	result := make([]model.OriginLink, len(followedTags))
+
+	for index, followedTag := range followedTags {
+		result[index] = followedTag.Origin(service.host)
+	}
+
+	return result
+}
+
+// defaultFolderID returns the ID of the User's first Folder
+func (service *FollowedTag) defaultFolderID(session data.Session, userID primitive.ObjectID) (primitive.ObjectID, error) {
+
+	const location = "service.FollowedTag.defaultFolderID"
+
+	folders, err := service.folderService.QueryByUserID(session, userID)
+
+	if err != nil {
+		return primitive.NilObjectID, derp.Wrap(err, location, "Querying folders", userID)
+	}
+
+	if len(folders) == 0 {
+		return primitive.NilObjectID, derp.Validation("User must have at least one folder", userID)
+	}
+
+	return folders[0].FolderID, nil
+}
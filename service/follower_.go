@@ -23,14 +23,17 @@ import (
 // Follower defines a service that tracks the (possibly external) accounts that are followers of an internal User
 
 type Follower struct {
-	activityService   *ActivityStream
-	domainEmail       *DomainEmail
-	importItemService *ImportItem
-	ruleService       *Rule
-	streamService     *Stream
-	userService       *User
-	queue             *queue.Queue // The server-wide queue for background tasks
-	host              string       // The HOST for this domain (protocol + hostname)
+	activityService     *ActivityStream
+	domainEmail         *DomainEmail
+	importItemService   *ImportItem
+	notificationService *Notification
+	outboxService       *Outbox
+	ruleService         *Rule
+	streamService       *Stream
+	userService         *User
+	webhookService      *Webhook
+	queue               *queue.Queue // The server-wide queue for background tasks
+	host                string       // The HOST for this domain (protocol + hostname)
 }
 
 // NewFollower returns a fully initialized Follower service
@@ -47,9 +50,12 @@ func (service *Follower) Refresh(factory *Factory) {
 	service.activityService = factory.ActivityStream()
 	service.domainEmail = factory.Email()
 	service.importItemService = factory.ImportItem()
+	service.notificationService = factory.Notification()
+	service.outboxService = factory.Outbox()
 	service.ruleService = factory.Rule()
 	service.streamService = factory.Stream()
 	service.userService = factory.User()
+	service.webhookService = factory.Webhook()
 	service.queue = factory.Queue()
 	service.host = factory.Host()
 }
@@ -123,6 +129,13 @@ func (service *Follower) Save(session data.Session, follower *model.Follower, no
 		return derp.Wrap(err, location, "Invalid Follower record", follower)
 	}
 
+	// Find out if this save activates the Follower (pending and requested Followers do not count yet)
+	isActivated, err := service.isActivated(session, follower)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Loading previous Follower state", follower)
+	}
+
 	// Save the follower to the database
 	if err := service.collection(session).Save(follower, note); err != nil {
 		return derp.Wrap(err, location, "Saving Follower", follower, note)
@@ -133,9 +146,40 @@ func (service *Follower) Save(session data.Session, follower *model.Follower, no
 		return derp.Wrap(err, location, "Re-calculating follower count", follower)
 	}
 
+	// Send follower:create Webhooks
+	if isActivated {
+		service.webhookService.Send(session, follower, model.WebhookEventFollowerCreate)
+	}
+
 	return nil
 }
 
+// isActivated returns TRUE if saving this Follower moves it into the ACTIVE state, either
+// as a new record or from a previous (PENDING or REQUESTED) state.
+func (service *Follower) isActivated(session data.Session, follower *model.Follower) (bool, error) {
+
+	if !follower.IsActive() {
+		return false, nil
+	}
+
+	if follower.IsNew() {
+		return true, nil
+	}
+
+	previous := model.NewFollower()
+
+	if err := service.collection(session).Load(exp.Equal("_id", follower.FollowerID), &previous); err != nil {
+
+		if derp.IsNotFound(err) {
+			return true, nil
+		}
+
+		return false, derp.Wrap(err, "service.Follower.isActivated", "Loading Follower", follower.FollowerID)
+	}
+
+	return !previous.IsActive(), nil
+}
+
 // Delete removes an Follower from the database (virtual delete)
 func (service *Follower) Delete(session data.Session, follower *model.Follower, note string) error {
 
@@ -163,6 +207,9 @@ func (service *Follower) Delete(session data.Session, follower *model.Follower,
 		)
 	}
 
+	// Send follower:delete Webhooks
+	service.webhookService.Send(session, follower, model.WebhookEventFollowerDelete)
+
 	return nil
 }
 
@@ -289,8 +336,12 @@ func (service *Follower) Schema() schema.Schema {
  * Custom Queries
  ******************************************/
 
+// CountByParent returns the number of ACTIVE Followers of a specific parent
 func (service *Follower) CountByParent(session data.Session, parentType string, parentID primitive.ObjectID) (int64, error) {
-	criteria := exp.Equal("type", parentType).AndEqual("parentId", parentID)
+	criteria := exp.Equal("type", parentType).
+		AndEqual("parentId", parentID).
+		AndEqual("stateId", model.FollowerStateActive)
+
 	return service.Count(session, criteria)
 }
 
@@ -394,16 +445,28 @@ func (service *Follower) RangeByUserID(session data.Session, userID primitive.Ob
 	)
 }
 
+// RangeActiveByUserID returns an iterator containing the ACTIVE Followers of a specific User.
+// Pending, requested, and paused Followers are excluded.
+func (service *Follower) RangeActiveByUserID(session data.Session, userID primitive.ObjectID) iter.Seq[model.Follower] {
+	return service.Range(
+		session,
+		exp.Equal("parentId", userID).
+			AndEqual("type", model.FollowerTypeUser).
+			AndEqual("stateId", model.FollowerStateActive),
+	)
+}
+
 // RangeActivityPubByUserID returns an iterator containing all of the Followers of a specific User
 func (service *Follower) RangeActivityPubByType(session data.Session, followerType string, userID primitive.ObjectID) iter.Seq[model.Follower] {
 
 	// RULE: Followers paused by a block rule are excluded from delivery fan-out (R8)
+	// RULE: Follow requests that have not been approved yet are also excluded
 	return service.Range(
 		session,
 		exp.Equal("parentId", userID).
 			AndEqual("type", followerType).
 			AndEqual("method", model.FollowerMethodActivityPub).
-			AndNotEqual("stateId", model.FollowerStatePaused),
+			AndNotIn("stateId", []string{model.FollowerStatePaused, model.FollowerStateRequested}),
 	)
 }
 
@@ -445,13 +508,14 @@ func (service *Follower) DeleteByUserID(session data.Session, userID primitive.O
 // RangeFollowers returns a rangeFunc containing all of the Followers of specific parentID
 func (service *Follower) RangeFollowers(session data.Session, parentType string, parentID primitive.ObjectID) iter.Seq[model.Follower] {
 
-	// RULE: Followers paused by a block rule are excluded from delivery fan-out (R8). Only
-	// PAUSED is excluded -- other states keep their existing delivery behavior.
+	// RULE: Followers paused by a block rule are excluded from delivery fan-out (R8), as are
+	// follow requests that have not been approved yet. Other states keep their existing
+	// delivery behavior.
 	return service.Range(
 		session,
 		exp.Equal("parentId", parentID).
 			AndEqual("type", parentType).
-			AndNotEqual("stateId", model.FollowerStatePaused),
+			AndNotIn("stateId", []string{model.FollowerStatePaused, model.FollowerStateRequested}),
 	)
 }
 
@@ -509,11 +573,16 @@ func (service *Follower) LoadParentActor(session data.Session, follower *model.F
  * ActivityPub Queries
  ******************************************/
 
-// IsActivityPubFollower searches
+// IsActivityPubFollower returns TRUE if the provided URL follows the parent via ActivityPub.
+// Follow requests that have not been approved yet do not count.
 func (service *Follower) IsActivityPubFollower(session data.Session, parentType string, parentID primitive.ObjectID, followerURL string) bool {
 	result := model.NewFollower()
-	err := service.LoadByActivityPubFollower(session, parentType, parentID, followerURL, &result)
-	return err == nil
+
+	if err := service.LoadByActivityPubFollower(session, parentType, parentID, followerURL, &result); err != nil {
+		return false
+	}
+
+	return !result.IsRequested()
 }
 
 // ListActivityPub returns an iterator containing all of the Followers of specific parentID
@@ -521,7 +590,8 @@ func (service *Follower) ListActivityPub(session data.Session, parentID primitiv
 
 	criteria := exp.
 		Equal("parentId", parentID).
-		AndEqual("method", model.FollowerMethodActivityPub)
+		AndEqual("method", model.FollowerMethodActivityPub).
+		AndNotEqual("stateId", model.FollowerStateRequested)
 
 	return service.List(session, criteria, options...)
 }
@@ -537,11 +607,25 @@ func (service *Follower) NewActivityPubFollower(session data.Session, parentType
 		}
 	}
 
+	if err := service.saveActivityPubFollower(session, parentType, parentID, actor, model.FollowerStateActive, follower); err != nil {
+		return derp.Wrap(err, location, "Saving new follower", follower)
+	}
+
+	// Salút!
+	return nil
+}
+
+// saveActivityPubFollower populates a Follower record from a remote actor and saves it
+// to the database with the provided state.
+func (service *Follower) saveActivityPubFollower(session data.Session, parentType string, parentID primitive.ObjectID, actor streams.Document, stateID string, follower *model.Follower) error {
+
+	const location = "service.Follower.saveActivityPubFollower"
+
 	// Set/Update follower data from the activity
 	follower.Method = model.FollowerMethodActivityPub
 	follower.ParentType = parentType
 	follower.ParentID = parentID
-	follower.StateID = model.FollowerStateActive
+	follower.StateID = stateID
 
 	follower.Actor = model.PersonLink{
 		ProfileURL:   actor.ID(),
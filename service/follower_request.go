@@ -0,0 +1,175 @@
+package service
+
+import (
+	"iter"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/******************************************
+ * Follow Requests (Locked Accounts)
+ ******************************************/
+
+// NewActivityPubFollowRequest records a Follow from a remote actor to a User who approves
+// followers manually.  The Follower is saved in the REQUESTED state, and does not receive
+// any updates until the User accepts the request.  The original Follow activity ID is kept
+// so that the eventual Accept/Reject can reference it.  It returns TRUE if this is a new
+// request, and FALSE if the actor had already asked (or already follows) the User.
+func (service *Follower) NewActivityPubFollowRequest(session data.Session, userID primitive.ObjectID, actor streams.Document, activity streams.Document, follower *model.Follower) (bool, error) {
+
+	const location = "service.Follower.NewActivityPubFollowRequest"
+
+	// Try to find an existing follower record
+	if err := service.LoadByActor(session, userID, actor.ID(), follower); err != nil {
+		if !derp.IsNotFound(err) {
+			return false, derp.Wrap(err, location, "Loading existing follower", actor)
+		}
+	}
+
+	isNew := follower.IsNew()
+
+	// RULE: Do not downgrade an existing (approved) follower into a request
+	stateID := model.FollowerStateRequested
+
+	if !isNew && !follower.IsRequested() {
+		stateID = follower.StateID
+	}
+
+	follower.Data["followId"] = activity.ID()
+
+	if err := service.saveActivityPubFollower(session, model.FollowerTypeUser, userID, actor, stateID, follower); err != nil {
+		return false, derp.Wrap(err, location, "Saving follow request", follower)
+	}
+
+	return isNew, nil
+}
+
+// QueryFollowRequests returns all of the pending follow requests for a User
+func (service *Follower) QueryFollowRequests(session data.Session, userID primitive.ObjectID, options ...option.Option) ([]model.Follower, error) {
+	return service.Query(session, service.followRequestCriteria(userID), options...)
+}
+
+// RangeFollowRequests returns an iterator containing all of the pending follow requests for a User
+func (service *Follower) RangeFollowRequests(session data.Session, userID primitive.ObjectID) iter.Seq[model.Follower] {
+	return service.Range(session, service.followRequestCriteria(userID))
+}
+
+// LoadFollowRequest loads a pending follow request for a User, identified by the remote actor's URL
+func (service *Follower) LoadFollowRequest(session data.Session, userID primitive.ObjectID, actorID string, follower *model.Follower) error {
+
+	const location = "service.Follower.LoadFollowRequest"
+
+	if err := service.LoadByActor(session, userID, actorID, follower); err != nil {
+		return derp.Wrap(err, location, "Loading follower", userID, actorID)
+	}
+
+	if !follower.IsRequested() {
+		return derp.NotFound(location, "Follower is not a pending follow request", userID, actorID)
+	}
+
+	return nil
+}
+
+// AcceptFollowRequest approves a pending follow request.  The Follower becomes ACTIVE
+// and an "Accept" activity is sent back to the remote actor.
+func (service *Follower) AcceptFollowRequest(session data.Session, follower *model.Follower) error {
+
+	const location = "service.Follower.AcceptFollowRequest"
+
+	// RULE: Only pending follow requests can be accepted
+	if !follower.IsRequested() {
+		return derp.Validation("Follower is not a pending follow request", follower.FollowerID, derp.WithLocation(location))
+	}
+
+	follower.StateID = model.FollowerStateActive
+
+	if err := service.Save(session, follower, "Follow request accepted"); err != nil {
+		return derp.Wrap(err, location, "Saving follower", follower)
+	}
+
+	// Send the "Accept" message to the remote actor as a post-commit queue task
+	actorURL := service.userService.ActivityPubURL(follower.ParentID)
+	service.outboxService.SendAccept(session, actorURL, service.ActivityPubID(follower), service.followRequestActivity(follower))
+
+	// Remove the FOLLOW-REQUEST notification, which is no longer actionable
+	if err := service.notificationService.DeleteFollowByActor(session, follower.ParentID, follower.Actor.ProfileURL, "Follow request accepted"); err != nil {
+		derp.Report(derp.Wrap(err, location, "Deleting follow request notification", follower))
+	}
+
+	return nil
+}
+
+// RejectFollowRequest declines a pending follow request.  A "Reject" activity is sent
+// back to the remote actor, and the Follower record is removed.
+func (service *Follower) RejectFollowRequest(session data.Session, follower *model.Follower) error {
+
+	const location = "service.Follower.RejectFollowRequest"
+
+	// RULE: Only pending follow requests can be rejected
+	if !follower.IsRequested() {
+		return derp.Validation("Follower is not a pending follow request", follower.FollowerID, derp.WithLocation(location))
+	}
+
+	// Send the "Reject" message to the remote actor as a post-commit queue task
+	actorURL := service.userService.ActivityPubURL(follower.ParentID)
+	service.outboxService.SendReject(session, actorURL, service.ActivityPubID(follower), service.followRequestActivity(follower))
+
+	if err := service.Delete(session, follower, "Follow request rejected"); err != nil {
+		return derp.Wrap(err, location, "Deleting follower", follower)
+	}
+
+	// Remove the FOLLOW-REQUEST notification, which is no longer actionable
+	if err := service.notificationService.DeleteFollowByActor(session, follower.ParentID, follower.Actor.ProfileURL, "Follow request rejected"); err != nil {
+		derp.Report(derp.Wrap(err, location, "Deleting follow request notification", follower))
+	}
+
+	return nil
+}
+
+// AcceptAllFollowRequests approves every pending follow request for a User.  This is called
+// when a User stops approving followers manually.
+func (service *Follower) AcceptAllFollowRequests(session data.Session, userID primitive.ObjectID) error {
+
+	const location = "service.Follower.AcceptAllFollowRequests"
+
+	for follower := range service.RangeFollowRequests(session, userID) {
+		if err := service.AcceptFollowRequest(session, &follower); err != nil {
+			return derp.Wrap(err, location, "Accepting follow request", follower)
+		}
+	}
+
+	return nil
+}
+
+// followRequestCriteria returns the criteria for all pending follow requests for a User
+func (service *Follower) followRequestCriteria(userID primitive.ObjectID) exp.Expression {
+	return exp.Equal("parentId", userID).
+		AndEqual("type", model.FollowerTypeUser).
+		AndEqual("method", model.FollowerMethodActivityPub).
+		AndEqual("stateId", model.FollowerStateRequested)
+}
+
+// followRequestActivity reconstructs the original "Follow" activity for a follow request,
+// which is embedded as the object of the Accept/Reject that answers it.
+func (service *Follower) followRequestActivity(follower *model.Follower) streams.Document {
+
+	follow := mapof.Any{
+		vocab.PropertyType:   vocab.ActivityTypeFollow,
+		vocab.PropertyActor:  follower.Actor.ProfileURL,
+		vocab.PropertyObject: service.userService.ActivityPubURL(follower.ParentID),
+	}
+
+	if followID := follower.FollowActivityID(); followID != "" {
+		follow[vocab.PropertyID] = followID
+	}
+
+	return streams.NewDocument(follow)
+}
@@ -25,19 +25,21 @@ const followingMimeStack = "application/activity+json; q=1.0, text/html; q=0.9,
 
 // Following manages all interactions with the Following collection
 type Following struct {
-	activityService   *ActivityStream
-	folderService     *Folder
-	host              string
-	hostname          string
-	importItemService *ImportItem
-	keyService        *EncryptionKey
-	newsFeedService   *NewsFeed
-	outboxService     *Outbox
-	ruleService       *Rule
-	sseUpdateChannel  chan<- realtime.Message
-	streamService     *Stream
-	userService       *User
-	queue             *queue.Queue
+	activityService    *ActivityStream
+	filterService      *Filter
+	folderService      *Folder
+	followedTagService *FollowedTag
+	host               string
+	hostname           string
+	importItemService  *ImportItem
+	keyService         *EncryptionKey
+	newsFeedService    *NewsFeed
+	outboxService      *Outbox
+	ruleService        *Rule
+	sseUpdateChannel   chan<- realtime.Message
+	streamService      *Stream
+	userService        *User
+	queue              *queue.Queue
 }
 
 // NewFollowing returns a fully populated Following service.
@@ -52,7 +54,9 @@ func NewFollowing() Following {
 // Refresh updates any stateful data that is cached inside this service.
 func (service *Following) Refresh(factory *Factory) {
 	service.activityService = factory.ActivityStream()
+	service.filterService = factory.Filter()
 	service.folderService = factory.Folder()
+	service.followedTagService = factory.FollowedTag()
 	service.host = factory.Host()
 	service.hostname = factory.Hostname()
 	service.importItemService = factory.ImportItem()
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/realtime"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SaveHashtagNewsItems delivers a public document into the inbox of every User who follows
+// one of its #hashtags.  This is used for documents that arrive from outside of a User's
+// Following list (the search index and relays).  Each User's block/mute rules are applied,
+// and the newsItem is labeled with every followed tag that it carries.  Documents forwarded
+// by a Relay carry its relayID, so that they can be removed when the Relay is unsubscribed.
+func (service *Following) SaveHashtagNewsItems(session data.Session, document streams.Document, relayID primitive.ObjectID) error {
+
+	const location = "service.Following.SaveHashtagNewsItems"
+
+	// RULE: Only public documents are delivered to hashtag followers
+	if !document.IsPublic() {
+		return nil
+	}
+
+	// RULE: Documents without hashtags have nowhere to go
+	values := model.DocumentHashtags(document)
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	// Find every User who follows one of these tags
+	followedTags, err := service.followedTagService.RangeByValues(session, values)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Finding followed hashtags", values)
+	}
+
+	// Group the FollowedTags by User, so that each User receives one newsItem
+	// that is labeled with all of the tags they follow.
+	byUser := make(map[primitive.ObjectID][]model.FollowedTag)
+	userIDs := make([]primitive.ObjectID, 0)
+
+	for followedTag := range followedTags {
+
+		if _, exists := byUser[followedTag.UserID]; !exists {
+			userIDs = append(userIDs, followedTag.UserID)
+		}
+
+		byUser[followedTag.UserID] = append(byUser[followedTag.UserID], followedTag)
+	}
+
+	now := time.Now().Unix()
+
+	for _, userID := range userIDs {
+		if err := service.saveHashtagNewsItem(session, userID, byUser[userID], document, relayID, now); err != nil {
+			derp.Report(derp.Wrap(err, location, "Delivering to hashtag follower", userID, document.ID()))
+		}
+	}
+
+	return nil
+}
+
+// saveHashtagNewsItem delivers a single document into one User's inbox, labeled with the provided FollowedTags
+func (service *Following) saveHashtagNewsItem(session data.Session, userID primitive.ObjectID, followedTags []model.FollowedTag, document streams.Document, relayID primitive.ObjectID, now int64) error {
+
+	const location = "service.Following.saveHashtagNewsItem"
+
+	// RULE: Apply the User's block/mute rules to the document (including its other hashtags)
+	disposition, err := service.ruleService.Disposition(session, userID, document, now)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Checking rules", userID, document.ID())
+	}
+
+	if disposition.IsFiltered() {
+		return nil
+	}
+
+	// Build a newsItem that is delivered into the first tag's Folder
+	newsItem := getNewsItem(userID, document)
+	newsItem.FolderID = followedTags[0].FolderID
+
+	for _, followedTag := range followedTags {
+		newsItem.AddReference(followedTag.Origin(service.host))
+	}
+
+	// Link relayed documents to their Relay (see NewsFeed.DeleteByRelay)
+	newsItem.Origin.RelayID = relayID
+
+	// Try to save a unique version of this newsItem.  Duplicates (for instance, posts
+	// that also arrived from a Following) are labeled with the tag instead.
+	newsItem, isNew, err := service.saveUniqueNewsItem(session, newsItem)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Saving newsItem", newsItem)
+	}
+
+	if isNew {
+		service.publishStreamingStatus(session, newsItem, document, realtime.StreamingEventUpdate)
+	}
+
+	return nil
+}
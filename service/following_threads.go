@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/realtime"
 	"github.com/EmissarySocial/emissary/tools/postcommit"
 	"github.com/benpate/data"
 	"github.com/benpate/derp"
@@ -35,7 +36,7 @@ func (service *Following) SaveNewsItem(session data.Session, following *model.Fo
 		seen:        make(map[string]bool),
 	}
 
-	original, originType, dropped, err := walk.primaryPost(document, originType, 0)
+	original, walkOriginType, dropped, err := walk.primaryPost(document, originType, 0)
 
 	if err != nil {
 		return derp.Wrap(err, location, "Walking provenance chain", document.ID())
@@ -57,13 +58,29 @@ func (service *Following) SaveNewsItem(session data.Session, following *model.Fo
 	newsItem.Context = original.Context()
 	newsItem.FollowingID = following.FollowingID
 	newsItem.FolderID = following.FolderID.Value()
-	newsItem.AddReference(following.Origin(originType))
+	newsItem.AddReference(following.Origin(walkOriginType))
+
+	// Label the newsItem with any hashtags that this User follows
+	for _, reference := range service.followedTagService.References(session, following.UserID, original) {
+		newsItem.AddReference(reference)
+	}
 
 	// Try to save a unique version of this newsItem to the database (always collapse duplicates)
-	if err := service.saveUniqueNewsItem(session, newsItem); err != nil {
+	newsItem, isNew, err := service.saveUniqueNewsItem(session, newsItem)
+
+	if err != nil {
 		return derp.Wrap(err, location, "Saving newsItem", newsItem)
 	}
 
+	// Send new (and edited) statuses to the User's Mastodon streaming clients.  Edits arrive
+	// unwrapped from their `Update` activity, so an edit is a primary document that is already
+	// in the newsfeed, and that carries an `updated` date.
+	if isNew {
+		service.publishStreamingStatus(session, newsItem, original, realtime.StreamingEventUpdate)
+	} else if isEdited(document, newsItem, originType) {
+		service.publishStreamingStatus(session, newsItem, original, realtime.StreamingEventStatusUpdate)
+	}
+
 	// Crawl the document's context/reply chain in the background (post-commit)
 	postcommit.Publish(
 		session,
@@ -78,8 +95,9 @@ func (service *Following) SaveNewsItem(session data.Session, following *model.Fo
 
 // saveUnique adds/updates a message in the database.  If the message.URL does not already
 // exist, then a new message is added to the Inbox.  Otherwise, the "references" data will
-// of the existing record be updated and the unique value will be re-saved.
-func (service *Following) saveUniqueNewsItem(session data.Session, message model.NewsItem) error {
+// of the existing record be updated and the unique value will be re-saved.  It returns the
+// saved NewsItem, and TRUE if it was newly added to the Inbox.
+func (service *Following) saveUniqueNewsItem(session data.Session, message model.NewsItem) (model.NewsItem, bool, error) {
 
 	const location = "service.Following.saveUnique"
 
@@ -88,7 +106,7 @@ func (service *Following) saveUniqueNewsItem(session data.Session, message model
 
 	if err := service.newsFeedService.LoadByURL(session, message.UserID, message.URL, &previousNewsItem); err != nil {
 		if !derp.IsNotFound(err) {
-			return derp.Wrap(err, location, "Searching for duplicate message", message)
+			return message, false, derp.Wrap(err, location, "Searching for duplicate message", message)
 		}
 	}
 
@@ -96,10 +114,10 @@ func (service *Following) saveUniqueNewsItem(session data.Session, message model
 	if previousNewsItem.IsNew() {
 
 		if err := service.newsFeedService.Save(session, &message, "Created"); err != nil {
-			return derp.Wrap(err, location, "Saving new message", message)
+			return message, false, derp.Wrap(err, location, "Saving new message", message)
 		}
 
-		return nil
+		return message, true, nil
 	}
 
 	// Fall through means that we have a duplicate message.
@@ -117,12 +135,51 @@ func (service *Following) saveUniqueNewsItem(session data.Session, message model
 	// if the message was updated (from AddReference or MarkNewReplies) then save it.
 	if isReferenceUpdated || isStatusUpdated {
 		if err := service.newsFeedService.Save(session, &previousNewsItem, "NewsItem Imported"); err != nil {
-			return derp.Wrap(err, location, "Updating previous message with new origin and status", previousNewsItem)
+			return previousNewsItem, false, derp.Wrap(err, location, "Updating previous message with new origin and status", previousNewsItem)
 		}
 	}
 
 	// Successfully updated the message, or not.  But still, it's good.
-	return nil
+	return previousNewsItem, false, nil
+}
+
+// publishStreamingStatus sends a NewsItem to the User's "user" stream, and to the "list" stream
+// of its Folder.  The User's "home" Filters are applied here, because streaming clients receive
+// the Status as-is: hidden statuses are not sent, and warnings are included in `filtered`.
+// Streaming is best-effort, so errors are reported but never returned.
+func (service *Following) publishStreamingStatus(session data.Session, newsItem model.NewsItem, document streams.Document, event string) {
+
+	const location = "service.Following.publishStreamingStatus"
+
+	matches, err := service.filterService.Match(session, newsItem.UserID, model.FilterContextHome, model.ContentText(document), newsItem.URL)
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, location, "Matching filters", newsItem.UserID))
+		return
+	}
+
+	if matches.IsHidden() {
+		return
+	}
+
+	status := newsItem.Toot()
+
+	if len(matches) > 0 {
+		status.Filtered = matches.Toot()
+	}
+
+	payload, err := streamingPayload(status)
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, location, "Encoding status", newsItem.NewsItemID))
+		return
+	}
+
+	publishStreamingEvent(session, service.queue, service.host, newsItem.UserID, []string{realtime.StreamUser}, event, payload)
+
+	if !newsItem.FolderID.IsZero() {
+		publishStreamingEvent(session, service.queue, service.host, newsItem.UserID, realtime.ListStream(newsItem.FolderID.Hex()), event, payload)
+	}
 }
 
 /******************************************
@@ -393,6 +450,22 @@ func (w *primaryPostWalk) hostFiltered(url string) (bool, error) {
  * Helper Functions
  ******************************************/
 
+// isEdited returns TRUE if a document received for an existing NewsItem is an edit of that
+// NewsItem's status: it is the status itself (not a reply, like, or boost of it), and it
+// carries an `updated` date.
+func isEdited(document streams.Document, newsItem model.NewsItem, originType string) bool {
+
+	if originType != model.OriginTypePrimary {
+		return false
+	}
+
+	if document.ID() != newsItem.URL {
+		return false
+	}
+
+	return document.Get("updated").String() != ""
+}
+
 // getNewsItem returns an inbox NewsItem object based on the provided arguments.
 func getNewsItem(userID primitive.ObjectID, document streams.Document) model.NewsItem {
 
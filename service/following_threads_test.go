@@ -348,3 +348,29 @@ func TestPrimaryPost_MentionTagNotDropped(t *testing.T) {
 	require.Nil(t, err)
 	require.False(t, dropped)
 }
+
+// Edits are primary documents that are already in the newsfeed, and that carry an `updated` date.
+func TestIsEdited(t *testing.T) {
+
+	newsItem := model.NewNewsItem()
+	newsItem.URL = "https://example.com/notes/1"
+
+	edited := streams.NewDocument(map[string]any{
+		vocab.PropertyID: "https://example.com/notes/1",
+		"updated":        "2026-01-02T03:04:05Z",
+	})
+
+	unedited := streams.NewDocument(map[string]any{
+		vocab.PropertyID: "https://example.com/notes/1",
+	})
+
+	reply := streams.NewDocument(map[string]any{
+		vocab.PropertyID: "https://example.com/notes/2",
+		"updated":        "2026-01-02T03:04:05Z",
+	})
+
+	require.True(t, isEdited(edited, newsItem, model.OriginTypePrimary))
+	require.False(t, isEdited(unedited, newsItem, model.OriginTypePrimary))
+	require.False(t, isEdited(reply, newsItem, model.OriginTypePrimary))
+	require.False(t, isEdited(edited, newsItem, model.OriginTypeLike))
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/realtime"
 	"github.com/EmissarySocial/emissary/tools/postcommit"
 	"github.com/EmissarySocial/emissary/tools/random"
 	"github.com/benpate/data"
@@ -131,6 +132,9 @@ func (service *Import) Save(session data.Session, record *model.Import, note str
 		return derp.Wrap(err, location, "Saving Import", record, note)
 	}
 
+	// Notify SSE clients of the Import's progress
+	publishChange(session, service.queue, service.host, realtime.TopicImportProgress, record.ImportID)
+
 	return nil
 }
 
@@ -22,6 +22,11 @@ import (
 // Inbox manages all Inbox records for a User.
 type Inbox struct {
 	activityService  *ActivityStream
+	followingService *Following
+	locatorService   *Locator
+	streamService    *Stream
+	userService      *User
+	webhookService   *Webhook
 	host             string
 	sseUpdateChannel chan<- realtime.Message
 }
@@ -38,6 +43,11 @@ func NewInbox() Inbox {
 // Refresh updates any stateful data that is cached inside this service.
 func (service *Inbox) Refresh(factory *Factory) {
 	service.activityService = factory.ActivityStream()
+	service.followingService = factory.Following()
+	service.locatorService = factory.Locator()
+	service.streamService = factory.Stream()
+	service.userService = factory.User()
+	service.webhookService = factory.Webhook()
 	service.host = factory.Host()
 	service.sseUpdateChannel = factory.SSEUpdateChannel()
 }
@@ -126,6 +136,8 @@ func (service *Inbox) Save(session data.Session, inboxActivity *model.InboxActiv
 	}
 
 	// Check to see if this is a new record
+	isNew := inboxActivity.IsNew()
+
 	if err := service.createOrUpdate(session, inboxActivity, note); err != nil {
 		return derp.Wrap(err, location, "Saving Inbox activity", inboxActivity, note)
 	}
@@ -137,6 +149,11 @@ func (service *Inbox) Save(session data.Session, inboxActivity *model.InboxActiv
 	// Send realtime SSE messages to any listeners
 	go service.sendSSEUpdate(inboxActivity)
 
+	// Send message:create Webhooks
+	if isNew {
+		service.webhookService.Send(session, inboxActivity, model.WebhookEventMessageCreate)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SharedInboxRecipients returns the local Users and Streams that should receive an activity
+// delivered to the domain-wide shared inbox: every local User or Stream that the activity
+// addresses directly, plus every User who follows the sender when the activity is public or
+// addressed to the sender's followers.  Each recipient is returned once.
+func (service *Inbox) SharedInboxRecipients(session data.Session, activity streams.Document) ([]model.User, []model.Stream, error) {
+
+	const location = "service.Inbox.SharedInboxRecipients"
+
+	users := make([]model.User, 0)
+	streamList := make([]model.Stream, 0)
+	userIDs := make(map[primitive.ObjectID]bool)
+	streamIDs := make(map[primitive.ObjectID]bool)
+
+	// addUser loads a User (once) into the list of recipients
+	addUser := func(userID primitive.ObjectID) error {
+
+		if userIDs[userID] {
+			return nil
+		}
+
+		userIDs[userID] = true
+		user := model.NewUser()
+
+		if err := service.userService.LoadByID(session, userID, &user); err != nil {
+
+			// Users may have been deleted since the Following was created
+			if derp.IsNotFound(err) {
+				return nil
+			}
+
+			return derp.Wrap(err, location, "Loading User", userID)
+		}
+
+		// RULE: Users who have moved away no longer receive activities here
+		if user.MovedTo != "" {
+			return nil
+		}
+
+		users = append(users, user)
+		return nil
+	}
+
+	// Find every local User who follows the sender.
+	// RULE: Direct messages are only delivered to the actors that they address.
+	if actorID := activity.ActorID(); (actorID != "") && isForFollowers(activity) {
+
+		followings, err := service.followingService.RangeByActorID(session, actorID)
+
+		if err != nil {
+			return nil, nil, derp.Wrap(err, location, "Loading Following records", actorID)
+		}
+
+		for following := range followings {
+			if err := addUser(following.UserID); err != nil {
+				return nil, nil, derp.Wrap(err, location, "Adding follower", following.UserID)
+			}
+		}
+	}
+
+	// Find every local User and Stream that is addressed directly
+	uniquer := streams.NewUniquer[string]()
+
+	for addressee := range uniquer.Range(activity.RangeAddressees()) {
+
+		// RULE: Only local URLs can be recipients (this also skips the Public collection)
+		path, isLocal := strings.CutPrefix(addressee, service.host+"/")
+
+		if !isLocal {
+			continue
+		}
+
+		// RULE: Only actors can be recipients, not the collections or routes beneath them
+		if strings.Contains(path, "/") {
+			continue
+		}
+
+		objectType, objectID, err := service.locatorService.GetObjectFromURL(session, addressee)
+
+		if err != nil {
+
+			// Addresses that do not match a local actor are not recipients
+			if derp.IsNotFound(err) || derp.IsBadRequest(err) {
+				continue
+			}
+
+			return nil, nil, derp.Wrap(err, location, "Locating addressee", addressee)
+		}
+
+		switch objectType {
+
+		case model.ActorTypeUser:
+
+			if err := addUser(objectID); err != nil {
+				return nil, nil, derp.Wrap(err, location, "Adding addressee", addressee)
+			}
+
+		case model.ActorTypeStream:
+
+			if streamIDs[objectID] {
+				continue
+			}
+
+			streamIDs[objectID] = true
+			stream := model.NewStream()
+
+			if err := service.streamService.LoadByID(session, objectID, &stream); err != nil {
+				return nil, nil, derp.Wrap(err, location, "Loading Stream", objectID)
+			}
+
+			streamList = append(streamList, stream)
+		}
+	}
+
+	// Success
+	return users, streamList, nil
+}
+
+// isForFollowers returns TRUE if the activity is public, or is addressed to the sender's
+// followers collection.  Fails closed: if the sender cannot be loaded, then only the
+// actors that the activity addresses directly will receive it.
+func isForFollowers(activity streams.Document) bool {
+
+	const location = "service.isForFollowers"
+
+	if activity.IsPublic() {
+		return true
+	}
+
+	actor, err := activity.Actor().Load()
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, location, "Loading actor", activity.ActorID()))
+		return false
+	}
+
+	followersID := actor.Followers().ID()
+
+	if followersID == "" {
+		return false
+	}
+
+	for addressee := range activity.RangeAddressees() {
+		if addressee == followersID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// These tests pin who receives an activity that arrives at the shared inbox.  Direct messages
+// must reach only the actors they address, never every local follower of the sender.
+
+const sharedInboxHost = "https://example.com"
+
+const sharedInboxSender = "https://remote.example/users/sender"
+
+// TestInbox_SharedInboxRecipients_DirectMessage pins that a direct message is delivered only
+// to the local User it addresses, even though two other local Users follow the sender.
+func TestInbox_SharedInboxRecipients_DirectMessage(t *testing.T) {
+
+	service, session, recipient, _, _ := newSharedInboxTest()
+
+	activity := newSharedInboxActivity(mapof.Any{
+		vocab.PropertyTo: []any{sharedInboxHost + "/@" + recipient.UserID.Hex()},
+	})
+
+	users, streamList, err := service.SharedInboxRecipients(session, activity)
+
+	require.Nil(t, err)
+	require.Empty(t, streamList)
+	require.Equal(t, []primitive.ObjectID{recipient.UserID}, sharedInboxUserIDs(users))
+}
+
+// TestInbox_SharedInboxRecipients_Followers pins that activities addressed to the sender's
+// followers collection are delivered to every local follower.
+func TestInbox_SharedInboxRecipients_Followers(t *testing.T) {
+
+	service, session, _, follower1, follower2 := newSharedInboxTest()
+
+	activity := newSharedInboxActivity(mapof.Any{
+		vocab.PropertyTo: []any{sharedInboxSender + "/followers"},
+	})
+
+	users, _, err := service.SharedInboxRecipients(session, activity)
+
+	require.Nil(t, err)
+	require.ElementsMatch(t, []primitive.ObjectID{follower1.UserID, follower2.UserID}, sharedInboxUserIDs(users))
+}
+
+// TestInbox_SharedInboxRecipients_Public pins that public activities are delivered to every
+// local follower, plus the local Users that they mention.
+func TestInbox_SharedInboxRecipients_Public(t *testing.T) {
+
+	service, session, recipient, follower1, follower2 := newSharedInboxTest()
+
+	activity := newSharedInboxActivity(mapof.Any{
+		vocab.PropertyTo: []any{vocab.NamespaceActivityStreamsPublic},
+		vocab.PropertyCC: []any{sharedInboxHost + "/@" + recipient.UserID.Hex()},
+	})
+
+	users, _, err := service.SharedInboxRecipients(session, activity)
+
+	require.Nil(t, err)
+	require.ElementsMatch(t, []primitive.ObjectID{recipient.UserID, follower1.UserID, follower2.UserID}, sharedInboxUserIDs(users))
+}
+
+// newSharedInboxTest returns an Inbox service and a session that holds three local Users.
+// The first is addressed by the tests, and the other two follow the sender.
+func newSharedInboxTest() (*Inbox, sharedInboxSession, model.User, model.User, model.User) {
+
+	recipient := newSharedInboxUser("recipient")
+	follower1 := newSharedInboxUser("follower1")
+	follower2 := newSharedInboxUser("follower2")
+
+	store := &sharedInboxStore{
+		users:      []model.User{recipient, follower1, follower2},
+		followings: []model.Following{newSharedInboxFollowing(follower1), newSharedInboxFollowing(follower2)},
+	}
+
+	userService := &User{host: sharedInboxHost}
+	streamService := &Stream{}
+
+	service := &Inbox{
+		followingService: &Following{},
+		locatorService:   &Locator{userService: userService, streamService: streamService, host: sharedInboxHost},
+		streamService:    streamService,
+		userService:      userService,
+		host:             sharedInboxHost,
+	}
+
+	return service, sharedInboxSession{store: store}, recipient, follower1, follower2
+}
+
+func newSharedInboxUser(username string) model.User {
+	user := model.NewUser()
+	user.Username = username
+	return user
+}
+
+func newSharedInboxFollowing(user model.User) model.Following {
+	following := model.NewFollowing()
+	following.UserID = user.UserID
+	following.ProfileURL = sharedInboxSender
+	return following
+}
+
+// newSharedInboxActivity returns a Create activity from the sender, with the provided addressing
+func newSharedInboxActivity(addressing mapof.Any) streams.Document {
+
+	client := sharedInboxClient{
+		documents: mapof.Any{
+			sharedInboxSender: mapof.Any{
+				vocab.PropertyID:        sharedInboxSender,
+				vocab.PropertyFollowers: sharedInboxSender + "/followers",
+			},
+		},
+	}
+
+	value := mapof.Any{
+		vocab.PropertyID:     sharedInboxSender + "/activities/1",
+		vocab.PropertyType:   vocab.ActivityTypeCreate,
+		vocab.PropertyActor:  sharedInboxSender,
+		vocab.PropertyObject: sharedInboxSender + "/notes/1",
+	}
+
+	for key, addresses := range addressing {
+		value[key] = addresses
+	}
+
+	return streams.NewDocument(value, streams.WithClient(client))
+}
+
+func sharedInboxUserIDs(users []model.User) []primitive.ObjectID {
+
+	result := make([]primitive.ObjectID, len(users))
+
+	for index, user := range users {
+		result[index] = user.UserID
+	}
+
+	return result
+}
+
+/******************************************
+ * In-Memory Fakes
+ ******************************************/
+
+// sharedInboxStore is an in-memory data.Collection that holds Users (loaded by ID) and
+// Followings (iterated by profileUrl), which is all that SharedInboxRecipients reads.
+type sharedInboxStore struct {
+	users      []model.User
+	followings []model.Following
+}
+
+func (c *sharedInboxStore) Context() context.Context { return context.Background() }
+
+func (c *sharedInboxStore) Count(exp.Expression, ...option.Option) (int64, error) {
+	return 0, derp.Internal("test", "unused")
+}
+
+func (c *sharedInboxStore) Query(any, exp.Expression, ...option.Option) error {
+	return derp.Internal("test", "unused")
+}
+
+// Iterator returns the Followings whose profileUrl matches the criteria
+func (c *sharedInboxStore) Iterator(criteria exp.Expression, _ ...option.Option) (data.Iterator, error) {
+
+	result := &sharedInboxIterator{}
+
+	for _, following := range c.followings {
+
+		matches := criteria.Match(func(predicate exp.Predicate) bool {
+			switch predicate.Field {
+			case "profileUrl":
+				return predicate.Value == following.ProfileURL
+			case "deleteDate":
+				return true
+			default:
+				return false
+			}
+		})
+
+		if matches {
+			result.records = append(result.records, following)
+		}
+	}
+
+	return result, nil
+}
+
+// Load copies the User whose _id matches the criteria into the target
+func (c *sharedInboxStore) Load(criteria exp.Expression, target data.Object, _ ...option.Option) error {
+
+	result, ok := target.(*model.User)
+
+	if !ok {
+		return derp.Internal("test", "unexpected target type")
+	}
+
+	for _, user := range c.users {
+
+		matches := criteria.Match(func(predicate exp.Predicate) bool {
+			switch predicate.Field {
+			case "_id":
+				return predicate.Value == user.UserID
+			case "deleteDate":
+				return true
+			default:
+				return false
+			}
+		})
+
+		if matches {
+			*result = user
+			return nil
+		}
+	}
+
+	return derp.NotFound("test", "not found")
+}
+
+func (c *sharedInboxStore) Save(data.Object, string) error   { return derp.Internal("test", "unused") }
+func (c *sharedInboxStore) Delete(data.Object, string) error { return derp.Internal("test", "unused") }
+func (c *sharedInboxStore) HardDelete(exp.Expression) error  { return derp.Internal("test", "unused") }
+
+// sharedInboxIterator is a data.Iterator over a fixed list of Followings
+type sharedInboxIterator struct {
+	records []model.Following
+}
+
+func (i *sharedInboxIterator) Next(target any) bool {
+
+	if len(i.records) == 0 {
+		return false
+	}
+
+	following, ok := target.(*model.Following)
+
+	if !ok {
+		return false
+	}
+
+	*following = i.records[0]
+	i.records = i.records[1:]
+	return true
+}
+
+func (i *sharedInboxIterator) Count() int   { return len(i.records) }
+func (i *sharedInboxIterator) Error() error { return nil }
+func (i *sharedInboxIterator) Close() error { return nil }
+
+// sharedInboxSession hands out the same store for every collection
+type sharedInboxSession struct {
+	store *sharedInboxStore
+}
+
+func (s sharedInboxSession) Collection(string) data.Collection { return s.store }
+func (s sharedInboxSession) Context() context.Context          { return context.Background() }
+func (s sharedInboxSession) Close()                            {}
+
+// sharedInboxClient is a streams.Client that resolves documents from an in-memory map
+type sharedInboxClient struct {
+	documents mapof.Any
+}
+
+func (client sharedInboxClient) SetRootClient(streams.Client) {}
+
+func (client sharedInboxClient) Load(uri string, _ ...any) (streams.Document, error) {
+
+	if value, ok := client.documents[uri]; ok {
+		return streams.NewDocument(value, streams.WithClient(client)), nil
+	}
+
+	return streams.NilDocument(), derp.NotFound("sharedInboxClient.Load", "Unknown URI", uri)
+}
+
+func (client sharedInboxClient) Save(streams.Document) error { return nil }
+
+func (client sharedInboxClient) Delete(string) error { return nil }
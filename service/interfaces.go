@@ -34,6 +34,10 @@ type ServerFactory interface {
 	// getter on every use -- never capture the value -- because a config reload can reconnect the
 	// database, and a captured handle then fails every call with "client is disconnected".
 	CommonDatabase() *mongo.Database
+
+	// RealtimeTransport returns the method used to share realtime updates between server nodes
+	// (one of the realtime.Transport* constants).
+	RealtimeTransport() string
 }
 
 // DomainFactory is the narrow slice of a domain Factory that cross-domain tools (like the
@@ -2,6 +2,7 @@ package service
 
 import (
 	"crypto"
+	"crypto/ed25519"
 	"strings"
 
 	"github.com/EmissarySocial/emissary/model"
@@ -159,14 +160,35 @@ func (service *Locator) GetPrivateKey(session data.Session, actorType string, ac
 		return publicKeyID, privateKey, err
 
 	case model.ActorTypeStream:
-		publicKeyID := service.streamService.PublicKeyID(actorID)
-		privateKey, err := service.streamService.PrivateKey(session, actorID)
-		return publicKeyID, privateKey, err
+		return service.streamService.SigningKey(session, actorID)
 
 	case model.ActorTypeUser:
-		publicKeyID := service.userService.PublicKeyID(actorID)
-		privateKey, err := service.userService.PrivateKey(session, actorID)
-		return publicKeyID, privateKey, err
+		return service.userService.SigningKey(session, actorID)
+	}
+
+	return "", nil, derp.BadRequest(location, "Invalid Actor Type", actorType)
+}
+
+// GetEd25519Key returns the Ed25519 key ID and private key that sign RFC 9421 requests for the designated actor
+func (service *Locator) GetEd25519Key(session data.Session, actorType string, actorID primitive.ObjectID) (keyID string, privateKey ed25519.PrivateKey, err error) {
+
+	const location = "service.locator.GetEd25519Key"
+
+	switch actorType {
+
+	case model.ActorTypeApplication,
+		model.ActorTypeSearchDomain,
+		model.ActorTypeSearchQuery:
+
+		keyID := service.domainService.Ed25519KeyID()
+		privateKey, err := service.domainService.Ed25519PrivateKey(session)
+		return keyID, privateKey, err
+
+	case model.ActorTypeStream:
+		return service.streamService.Ed25519SigningKey(session, actorID)
+
+	case model.ActorTypeUser:
+		return service.userService.Ed25519SigningKey(session, actorID)
 	}
 
 	return "", nil, derp.BadRequest(location, "Invalid Actor Type", actorType)
@@ -107,6 +107,21 @@ func (service LookupProvider) Group(path string) form.LookupGroup {
 			form.LookupCode{Value: "BLOCK", Icon: "ban", Label: "Block", Description: "This person's posts are hidden, and your posts will not appear in their newsfeed. (two-way block)"},
 		)
 
+	case "report-categories":
+		return form.NewReadOnlyLookupGroup(
+			form.LookupCode{Value: model.ReportCategorySpam, Label: "Spam", Description: "Malicious links, fake engagement, or repetitive replies"},
+			form.LookupCode{Value: model.ReportCategoryLegal, Label: "Illegal Content", Description: "This content is illegal where you or the server are located"},
+			form.LookupCode{Value: model.ReportCategoryViolation, Label: "Rule Violation", Description: "This breaks the rules of the sender's server"},
+			form.LookupCode{Value: model.ReportCategoryOther, Label: "Something Else", Description: "The issue does not fit into the other categories"},
+		)
+
+	case "report-resolutions":
+		return form.NewReadOnlyLookupGroup(
+			form.LookupCode{Value: model.ReportResolutionNone, Label: "Take no further action"},
+			form.LookupCode{Value: model.ReportResolutionBlockActor, Label: "BLOCK this person for everyone on this server"},
+			form.LookupCode{Value: model.ReportResolutionBlockDomain, Label: "BLOCK this person's entire server"},
+		)
+
 	case "rule-actions":
 		return form.NewReadOnlyLookupGroup(
 			form.LookupCode{Value: "LABEL", Label: "LABEL posts that match this rule"},
@@ -119,6 +134,7 @@ func (service LookupProvider) Group(path string) form.LookupGroup {
 			form.LookupCode{Label: "Filter by Person", Value: model.RuleTypeActor},
 			form.LookupCode{Label: "Filter by Domain", Value: model.RuleTypeDomain},
 			form.LookupCode{Label: "Filter by Tag", Value: model.RuleTypeTag},
+			form.LookupCode{Label: "Filter by Words or Phrases", Value: model.RuleTypeContent},
 		)
 
 	case "rule-reasons":
@@ -191,6 +207,9 @@ func (service LookupProvider) Group(path string) form.LookupGroup {
 	case "themes":
 		return NewThemeLookupProvider(service.factory.Theme())
 
+	case "users":
+		return NewUserLookupProvider(service.session, service.factory.User())
+
 	case "webhook-types":
 		return form.NewReadOnlyLookupGroup(
 			form.LookupCode{Label: "stream:create", Description: "Occurs when a Stream is first created", Value: "stream:create"},
@@ -201,6 +220,16 @@ func (service LookupProvider) Group(path string) form.LookupGroup {
 			form.LookupCode{Label: "user:create", Description: "Occurs when a User is first created", Value: "user:create"},
 			form.LookupCode{Label: "user:update", Description: "Occurs when a User is updated", Value: "user:update"},
 			form.LookupCode{Label: "user:delete", Description: "Occurs when a User is deleted", Value: "user:delete"},
+			form.LookupCode{Label: "follower:create", Description: "Occurs when someone follows a User or Stream", Value: "follower:create"},
+			form.LookupCode{Label: "follower:delete", Description: "Occurs when someone unfollows a User or Stream", Value: "follower:delete"},
+			form.LookupCode{Label: "response:create", Description: "Occurs when a User likes, dislikes, or shares a post", Value: "response:create"},
+			form.LookupCode{Label: "response:delete", Description: "Occurs when a User undoes a like, dislike, or share", Value: "response:delete"},
+			form.LookupCode{Label: "privilege:create", Description: "Occurs when a purchase or Circle membership grants a privilege", Value: "privilege:create"},
+			form.LookupCode{Label: "privilege:delete", Description: "Occurs when a privilege is cancelled or removed", Value: "privilege:delete"},
+			form.LookupCode{Label: "message:create", Description: "Occurs when a User receives a new inbox message", Value: "message:create"},
+			form.LookupCode{Label: "rule:create", Description: "Occurs when a block, mute, or label rule is created", Value: "rule:create"},
+			form.LookupCode{Label: "rule:update", Description: "Occurs when a block, mute, or label rule is updated", Value: "rule:update"},
+			form.LookupCode{Label: "rule:delete", Description: "Occurs when a block, mute, or label rule is deleted", Value: "rule:delete"},
 		)
 	}
 
@@ -335,6 +335,45 @@ func (service *MerchantAccount) LoadByUserAndToken(session data.Session, userID
 	return service.LoadByUserAndID(session, userID, merchantAccountID, merchantAccount)
 }
 
+// ReEncrypt decrypts the vault in every MerchantAccount with the current master key, and encrypts
+// it again with the `newMasterKey` (hex-encoded). Records are written directly, without refreshing
+// any OAuth connections, because their values do not change.
+func (service *MerchantAccount) ReEncrypt(session data.Session, newMasterKey string) error {
+
+	const location = "service.MerchantAccount.ReEncrypt"
+
+	oldKey, err := hex.DecodeString(service.encryptionKey)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Decoding current master key")
+	}
+
+	newKey, err := hex.DecodeString(newMasterKey)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Decoding new master key")
+	}
+
+	merchantAccounts, err := service.Range(session, exp.All())
+
+	if err != nil {
+		return derp.Wrap(err, location, "Loading MerchantAccounts")
+	}
+
+	for merchantAccount := range merchantAccounts {
+
+		if err := merchantAccount.Vault.ReEncrypt(oldKey, newKey); err != nil {
+			return derp.Wrap(err, location, "Re-encrypting vault", merchantAccount.MerchantAccountID)
+		}
+
+		if err := service.collection(session).Save(&merchantAccount, "Re-encrypted with new master key"); err != nil {
+			return derp.Wrap(err, location, "Saving MerchantAccount", merchantAccount.MerchantAccountID)
+		}
+	}
+
+	return nil
+}
+
 // RangeByUserID returns a RangeFunc that yields all MerchantAccounts owned by the provided UserID
 func (service *MerchantAccount) RangeByUserID(session data.Session, userID primitive.ObjectID) (iter.Seq[model.MerchantAccount], error) {
 	criteria := exp.Equal("userId", userID)
@@ -7,12 +7,14 @@ import (
 	"time"
 
 	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/realtime"
 	"github.com/benpate/data"
 	"github.com/benpate/data/option"
 	"github.com/benpate/derp"
 	"github.com/benpate/exp"
 	"github.com/benpate/rosetta/schema"
 	"github.com/benpate/rosetta/sliceof"
+	"github.com/benpate/turbine/queue"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -21,6 +23,7 @@ type NewsFeed struct {
 	importItemService *ImportItem
 	folderService     *Folder
 	ruleService       *Rule
+	queue             *queue.Queue
 	host              string
 	counter           int
 	mutex             *sync.Mutex
@@ -42,6 +45,7 @@ func (service *NewsFeed) Refresh(factory *Factory) {
 	service.importItemService = factory.ImportItem()
 	service.folderService = factory.Folder()
 	service.ruleService = factory.Rule()
+	service.queue = factory.Queue()
 	service.host = factory.Host()
 }
 
@@ -145,6 +149,39 @@ func (service *NewsFeed) Delete(session data.Session, message *model.NewsItem, n
 	return nil
 }
 
+// DeleteByURL removes a User's NewsItem for a document that has been deleted by its author, and
+// removes it from the User's Mastodon streaming clients.  It is not an error if there is no
+// matching NewsItem.
+func (service *NewsFeed) DeleteByURL(session data.Session, userID primitive.ObjectID, url string, note string) error {
+
+	const location = "service.NewsFeed.DeleteByURL"
+
+	newsItem := model.NewNewsItem()
+
+	if err := service.LoadByURL(session, userID, url, &newsItem); err != nil {
+
+		if derp.IsNotFound(err) {
+			return nil
+		}
+
+		return derp.Wrap(err, location, "Loading NewsItem", userID, url)
+	}
+
+	if err := service.Delete(session, &newsItem, note); err != nil {
+		return derp.Wrap(err, location, "Deleting NewsItem", newsItem)
+	}
+
+	// Statuses are removed from every stream they were sent to
+	newsItemID := newsItem.NewsItemID.Hex()
+	publishStreamingEvent(session, service.queue, service.host, userID, []string{realtime.StreamUser}, realtime.StreamingEventDelete, newsItemID)
+
+	if !newsItem.FolderID.IsZero() {
+		publishStreamingEvent(session, service.queue, service.host, userID, realtime.ListStream(newsItem.FolderID.Hex()), realtime.StreamingEventDelete, newsItemID)
+	}
+
+	return nil
+}
+
 // DeleteMany removes all child streams from the provided stream (virtual delete)
 func (service *NewsFeed) DeleteMany(session data.Session, criteria exp.Expression, note string) error {
 
@@ -599,6 +636,66 @@ func (service *NewsFeed) DeleteByOrigin(session data.Session, internalID primiti
 	return service.DeleteMany(session, exp.Equal("origin.followingId", internalID), note)
 }
 
+// DeleteByRelay removes all newsItems that were delivered ONLY by the provided Relay.
+// Items that a Following has also delivered are kept, and linked to that Following instead.
+func (service *NewsFeed) DeleteByRelay(session data.Session, relayID primitive.ObjectID, note string) error {
+
+	const location = "service.NewsFeed.DeleteByRelay"
+
+	rangeFunc, err := service.Range(session, exp.Equal("origin.relayId", relayID))
+
+	if err != nil {
+		return derp.Wrap(err, location, "Listing newsItems forwarded by Relay", relayID)
+	}
+
+	for newsItem := range rangeFunc {
+
+		if newsItem.DetachRelay() {
+			if err := service.Save(session, &newsItem, note); err != nil {
+				return derp.Wrap(err, location, "Saving newsItem", newsItem.NewsItemID)
+			}
+			continue
+		}
+
+		if err := service.Delete(session, &newsItem, note); err != nil {
+			return derp.Wrap(err, location, "Deleting newsItem", newsItem.NewsItemID)
+		}
+	}
+
+	return nil
+}
+
+// DeleteRelayedByURL removes the newsItems that Relays delivered for a document that has been
+// deleted by its author.  NewsItems that a Following also delivered are only detached from their Relay,
+// because the Following delivers its own "Delete" activity to each User.
+func (service *NewsFeed) DeleteRelayedByURL(session data.Session, url string, note string) error {
+
+	const location = "service.NewsFeed.DeleteRelayedByURL"
+
+	criteria := exp.Equal("url", url).AndGreaterThan("origin.relayId", primitive.NilObjectID)
+	rangeFunc, err := service.Range(session, criteria)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Listing newsItems forwarded by Relays", url)
+	}
+
+	for newsItem := range rangeFunc {
+
+		if newsItem.DetachRelay() {
+			if err := service.Save(session, &newsItem, note); err != nil {
+				return derp.Wrap(err, location, "Saving newsItem", newsItem.NewsItemID)
+			}
+			continue
+		}
+
+		if err := service.DeleteByURL(session, newsItem.UserID, url, note); err != nil {
+			return derp.Wrap(err, location, "Deleting newsItem", newsItem.NewsItemID)
+		}
+	}
+
+	return nil
+}
+
 func (service *NewsFeed) DeleteByFolder(session data.Session, userID primitive.ObjectID, folderID primitive.ObjectID) error {
 
 	rangeFunc, err := service.RangeByFolder(session, userID, folderID)
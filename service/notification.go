@@ -20,6 +20,7 @@ import (
 // Notifications are created on the inbound ActivityPub path (NotifyFromActivity) whenever
 // another actor mentions, replies to, reacts to, or follows a local User.
 type Notification struct {
+	filterService    *Filter
 	followingService *Following
 	ruleService      *Rule
 	streamService    *Stream
@@ -39,6 +40,7 @@ func NewNotification() Notification {
 
 // Refresh updates any stateful data that is cached inside this service.
 func (service *Notification) Refresh(factory *Factory) {
+	service.filterService = factory.Filter()
 	service.followingService = factory.Following()
 	service.ruleService = factory.Rule()
 	service.streamService = factory.Stream()
@@ -439,7 +441,7 @@ func (service *Notification) DeleteByActivityID(session data.Session, userID pri
 	return nil
 }
 
-// DeleteFollowByActor soft-deletes any FOLLOW notification that the given actor created for the
+// DeleteFollowByActor soft-deletes any FOLLOW (or FOLLOW-REQUEST) notification that the given actor created for the
 // provided User.  An unfollow is identified by WHO unfollowed (the actor) — NOT by the Follow
 // activity's id, which is frequently absent or synthetic (see inbox_SaveActivity) and so cannot
 // be matched reliably against the id referenced by a later Undo.  The actor, by contrast, is
@@ -453,7 +455,7 @@ func (service *Notification) DeleteFollowByActor(session data.Session, userID pr
 	}
 
 	criteria := exp.Equal("userId", userID).
-		AndEqual("type", model.NotificationTypeFollow).
+		AndIn("type", []string{model.NotificationTypeFollow, model.NotificationTypeFollowRequest}).
 		AndEqual("actor.profileUrl", actorURL)
 
 	rangeFunc, err := service.Range(session, criteria)
@@ -169,6 +169,17 @@ func (service *Notification) NotifyFollow(session data.Session, user *model.User
 	return service.notify(session, user, activity, &notification)
 }
 
+// NotifyFollowRequest creates a FOLLOW-REQUEST notification for a recipient User who approves
+// followers manually.  It is called from inbox_follow_any.go after the (requested) Follower
+// record is saved.  No Accept is sent until the User approves the request.
+func (service *Notification) NotifyFollowRequest(session data.Session, user *model.User, activity streams.Document) error {
+
+	notification := service.newNotification(user, model.NotificationTypeFollowRequest, activity)
+	notification.ObjectURL = user.ActivityPubURL()
+
+	return service.notify(session, user, activity, &notification)
+}
+
 /******************************************
  * Common notify() funnel
  ******************************************/
@@ -252,6 +263,9 @@ func (service *Notification) notify(session data.Session, user *model.User, acti
 	// Publish an in-app SSE nudge (best-effort, published post-commit).
 	service.publishSSE(session, notification.UserID)
 
+	// Send the notification to Mastodon streaming clients (best-effort, published post-commit).
+	service.publishStreaming(session, notification)
+
 	// Enqueue a Web Push delivery task (best-effort, published post-commit).
 	service.enqueueWebPush(session, notification)
 
@@ -377,6 +391,41 @@ func (service *Notification) publishSSE(session data.Session, userID primitive.O
 	}, queue.WithInline())
 }
 
+// publishStreaming sends a notification to the User's "user" and "user:notification" streams.
+// DISLIKE has no Mastodon equivalent, and notifications hidden by the User's "notifications"
+// Filters are not sent.  Errors are reported, never returned.
+func (service *Notification) publishStreaming(session data.Session, notification *model.Notification) {
+
+	const location = "service.Notification.publishStreaming"
+
+	if notification.MastodonType() == "" {
+		return
+	}
+
+	text := model.FilterTextFromStrings(notification.ObjectSummary)
+	matches, err := service.filterService.Match(session, notification.UserID, model.FilterContextNotifications, text, notification.ObjectURL)
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, location, "Matching filters", notification.UserID))
+		return
+	}
+
+	if matches.IsHidden() {
+		return
+	}
+
+	payload, err := streamingPayload(notification.Toot())
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, location, "Encoding notification", notification.NotificationID))
+		return
+	}
+
+	for _, stream := range []string{realtime.StreamUser, realtime.StreamUserNotification} {
+		publishStreamingEvent(session, service.queue, service.host, notification.UserID, []string{stream}, realtime.StreamingEventNotification, payload)
+	}
+}
+
 // enqueueWebPush enqueues a best-effort Web Push delivery task for a new notification.
 // Published post-commit: the task references this Notification record, so it must not run
 // until the enclosing transaction has committed.
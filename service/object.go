@@ -123,6 +123,32 @@ func (service *Object) LoadByToken(session data.Session, userID primitive.Object
 	return service.LoadByID(session, userID, objectID, object)
 }
 
+// QueryByUserAndDate returns a page of Objects created by the provided userID that are visible
+// to the requesting actor, sorted from newest to oldest.  Only Objects created before maxDate
+// are returned.  Additional criteria (such as type or date range filters) can be provided.
+func (service *Object) QueryByUserAndDate(session data.Session, userID primitive.ObjectID, actorID string, criteria exp.Expression, maxDate int64, maxRows int) ([]model.Object, error) {
+
+	const location = "service.Object.QueryByUserAndDate"
+
+	criteria = exp.Equal("userId", userID).
+		And(service.AllowedCriteria(actorID)).
+		And(exp.LessThan("createDate", maxDate)).
+		And(criteria)
+
+	options := []option.Option{
+		option.SortDesc("createDate"),
+		option.MaxRows(int64(maxRows)),
+	}
+
+	result := make([]model.Object, 0, maxRows)
+
+	if err := service.collection(session).Query(&result, notDeleted(criteria), options...); err != nil {
+		return nil, derp.Wrap(err, location, "Querying objects", userID, maxDate)
+	}
+
+	return result, nil
+}
+
 // RangeByUser returns an iterator containing all Objects created by the provided userID
 func (service *Object) RangeByUser(session data.Session, userID primitive.ObjectID, options ...option.Option) (iter.Seq[model.Object], error) {
 	criteria := exp.Equal("userId", userID)
@@ -141,10 +167,26 @@ func (service *Object) ActivityPubURL(userID primitive.ObjectID, objectID primit
  * Permissions
  ******************************************/
 
+// IsAllowed returns TRUE if the requesting actor may view the provided Object.
 func (service *Object) IsAllowed(object *model.Object, actorID string) bool {
+	return object.Permissions.ContainsAny(service.credentials(actorID)...)
+}
+
+func (service *Object) NotAllowed(object *model.Object, actorID string) bool {
+	return !service.IsAllowed(object, actorID)
+}
+
+// AllowedCriteria returns a query expression that matches all Objects that the requesting
+// actor may view.  It enforces the same rules as IsAllowed.
+func (service *Object) AllowedCriteria(actorID string) exp.Expression {
+	return exp.In("permissions", service.credentials(actorID))
+}
+
+// credentials returns all of the permission values that the requesting actor matches
+func (service *Object) credentials(actorID string) []string {
 
 	// RULE: All requests match "Public"/anonymous role
-	credentials := []string{
+	result := []string{
 		vocab.NamespacePublic,
 		vocab.NamespaceASPublic,
 		vocab.NamespaceActivityStreamsPublic,
@@ -152,12 +194,8 @@ func (service *Object) IsAllowed(object *model.Object, actorID string) bool {
 
 	// RULE: If we have a valid actorID, also try to match it in the permissions.
 	if actorID != "" {
-		credentials = append(credentials, actorID)
+		result = append(result, actorID)
 	}
 
-	return object.Permissions.ContainsAny(credentials...)
-}
-
-func (service *Object) NotAllowed(object *model.Object, actorID string) bool {
-	return !service.IsAllowed(object, actorID)
+	return result
 }
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObject_IsAllowed(t *testing.T) {
+
+	service := NewObject()
+
+	public := model.NewObject()
+	public.Permissions = append(public.Permissions, vocab.NamespaceActivityStreamsPublic)
+
+	direct := model.NewObject()
+	direct.Permissions = append(direct.Permissions, "https://remote.social/@alice")
+
+	// Public objects are visible to everyone
+	require.True(t, service.IsAllowed(&public, ""))
+	require.True(t, service.IsAllowed(&public, "https://remote.social/@bob"))
+
+	// Direct objects are only visible to their recipients
+	require.True(t, service.NotAllowed(&direct, ""))
+	require.True(t, service.NotAllowed(&direct, "https://remote.social/@bob"))
+	require.True(t, service.IsAllowed(&direct, "https://remote.social/@alice"))
+}
+
+// TestObject_AllowedCriteria verifies that collection queries use the same
+// rules as IsAllowed, so that paging never reveals a hidden Object
+func TestObject_AllowedCriteria(t *testing.T) {
+
+	service := NewObject()
+
+	anonymous, ok := service.AllowedCriteria("").(exp.Predicate)
+	require.True(t, ok)
+	require.Equal(t, "permissions", anonymous.Field)
+	require.Equal(t, service.credentials(""), anonymous.Value)
+	require.NotContains(t, anonymous.Value, "")
+
+	signed, ok := service.AllowedCriteria("https://remote.social/@alice").(exp.Predicate)
+	require.True(t, ok)
+	require.Contains(t, signed.Value, "https://remote.social/@alice")
+	require.Contains(t, signed.Value, vocab.NamespaceActivityStreamsPublic)
+}
@@ -404,11 +404,10 @@ func (service *Outbox) addresseesAsFollowers(addressees iter.Seq[string]) iter.S
 }
 
 // deliverActivityPub enqueues one independently retryable OutboxSendToSingleRecipient task
-// (hannibal/sender) to deliver the activity to a single ActivityPub follower. The inbox URL is
-// taken from the stored Follower when present, else resolved from the actor's profile. The
-// signing key is resolved later, in the sender consumer, via SendLocator.Actor(actorURL) — which
-// is why F1 taught that resolver every actor type. Published post-commit, so it is spooled until
-// the consumer's own transaction commits.
+// to deliver the activity to a single ActivityPub follower. The inbox URL is taken from the stored
+// Follower when present, else resolved from the actor's profile. The signing keys are resolved
+// later, in the consumer, by ActivityPubDelivery — which resolves every local actor type.
+// Published post-commit, so it is spooled until the consumer's own transaction commits.
 func (service *Outbox) deliverActivityPub(session data.Session, actorURL string, follower *model.Follower, activity mapof.Any) {
 
 	const location = "service.Outbox.deliverActivityPub"
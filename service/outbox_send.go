@@ -41,6 +41,23 @@ func (service *Outbox) SendAccept(session data.Session, actorURL string, acceptI
 	postcommit.Publish(session, service.queue, sender.OutboxSendToAllRecipients, accept)
 }
 
+// SendReject queues a "Reject" activity addressed to the rejected activity's actor (typically the
+// sender of a Follow request that the User declined). actorURL is the rejecting local actor's
+// canonical URL (actor.ActorID()).
+func (service *Outbox) SendReject(session data.Session, actorURL string, rejectID string, activity streams.Document) {
+
+	reject := mapof.Any{
+		vocab.AtContext:      vocab.ContextTypeActivityStreams,
+		vocab.PropertyID:     rejectID,
+		vocab.PropertyType:   vocab.ActivityTypeReject,
+		vocab.PropertyActor:  actorURL,
+		vocab.PropertyObject: activity.Map(),
+		vocab.PropertyTo:     slices.Collect(activity.Actor().RangeIDs()),
+	}
+
+	postcommit.Publish(session, service.queue, sender.OutboxSendToAllRecipients, reject)
+}
+
 // SendFollow queues a "Follow" activity addressed to remoteActorID (the actor being followed).
 // actorURL is the following local actor's canonical URL (actor.ActorID()).
 func (service *Outbox) SendFollow(session data.Session, actorURL string, followID string, remoteActorID string) {
@@ -112,3 +129,24 @@ func (service *Outbox) SendAnnounce(session data.Session, actorURL string, annou
 
 	postcommit.Publish(session, service.queue, sender.OutboxSendToAllRecipients, announce)
 }
+
+// SendFlag queues a "Flag" (moderation report) about the actor at targetURL, addressed to that
+// actor so the sender resolves their server's inbox. actorURL is the reporting local actor's
+// canonical URL. Like Mastodon, forwarded reports are sent by a server-level actor so that the
+// identity of the User who filed them stays private. objectURLs are the reported documents, and
+// content is the reporter's comment.
+func (service *Outbox) SendFlag(session data.Session, actorURL string, flagID string, targetURL string, objectURLs []string, content string) {
+
+	flag := mapof.Any{
+		vocab.AtContext:         vocab.ContextTypeActivityStreams,
+		vocab.PropertyID:        flagID,
+		vocab.PropertyType:      vocab.ActivityTypeFlag,
+		vocab.PropertyActor:     actorURL,
+		vocab.PropertyObject:    append([]string{targetURL}, objectURLs...),
+		vocab.PropertyContent:   content,
+		vocab.PropertyPublished: hannibal.TimeFormat(time.Now()),
+		vocab.PropertyTo:        []string{targetURL},
+	}
+
+	postcommit.Publish(session, service.queue, sender.OutboxSendToAllRecipients, flag)
+}
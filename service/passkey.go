@@ -0,0 +1,365 @@
+package service
+
+import (
+	"slices"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/webauthn"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/rosetta/schema"
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PasskeyCeremonyRegister identifies challenges that were issued to register a new passkey
+const PasskeyCeremonyRegister = "passkey-register"
+
+// PasskeyCeremonySignin identifies challenges that were issued for a passwordless signin
+const PasskeyCeremonySignin = "passkey-signin"
+
+// PasskeyCeremonyTwoFactor identifies challenges that were issued to use a passkey as a second factor
+const PasskeyCeremonyTwoFactor = "passkey-two-factor"
+
+// passkeyTransports lists the transport hints that browsers may report for an authenticator
+var passkeyTransports = []string{"ble", "hybrid", "internal", "nfc", "smart-card", "usb"}
+
+// Passkey manages the WebAuthn credentials (passkeys) that Users have registered with their authenticators
+type Passkey struct {
+	domainService *Domain
+	jwtService    *JWT
+	host          string
+	hostname      string
+}
+
+// NewPasskey returns a fully initialized Passkey service
+func NewPasskey() Passkey {
+	return Passkey{}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+// Refresh updates any stateful data that is cached inside this service.
+func (service *Passkey) Refresh(factory *Factory) {
+	service.domainService = factory.Domain()
+	service.jwtService = factory.JWT()
+	service.host = factory.Host()
+	service.hostname = factory.Hostname()
+}
+
+// Close stops any background processes controlled by this service
+func (service *Passkey) Close() {
+	// Nothin to do here.
+}
+
+/******************************************
+ * Common Data Methods
+ ******************************************/
+
+func (service *Passkey) collection(session data.Session) data.Collection {
+	return session.Collection("Passkey")
+}
+
+// Count returns the number of Passkeys that match the provided criteria
+func (service *Passkey) Count(session data.Session, criteria exp.Expression) (int64, error) {
+	return service.collection(session).Count(notDeleted(criteria))
+}
+
+// Query returns a slice of Passkeys that match the provided criteria
+func (service *Passkey) Query(session data.Session, criteria exp.Expression, options ...option.Option) ([]model.Passkey, error) {
+	result := make([]model.Passkey, 0)
+	err := service.collection(session).Query(&result, notDeleted(criteria), options...)
+	return result, err
+}
+
+// Load retrieves a Passkey from the database
+func (service *Passkey) Load(session data.Session, criteria exp.Expression, passkey *model.Passkey) error {
+
+	if err := service.collection(session).Load(notDeleted(criteria), passkey); err != nil {
+		return derp.Wrap(err, "service.Passkey.Load", "Loading Passkey", criteria)
+	}
+
+	return nil
+}
+
+// Save adds/updates a Passkey in the database
+func (service *Passkey) Save(session data.Session, passkey *model.Passkey, note string) error {
+
+	const location = "service.Passkey.Save"
+
+	if _, err := service.Schema().Validate(passkey); err != nil {
+		return derp.Wrap(err, location, "Validating Passkey", passkey)
+	}
+
+	if err := service.collection(session).Save(passkey, note); err != nil {
+		return derp.Wrap(err, location, "Saving Passkey", passkey, note)
+	}
+
+	return nil
+}
+
+// Delete removes a Passkey from the database (hard delete)
+func (service *Passkey) Delete(session data.Session, passkey *model.Passkey, note string) error {
+
+	const location = "service.Passkey.Delete"
+
+	// Hard delete, never virtual: a revoked passkey must never be able to sign in again,
+	// and there is nothing in it worth restoring.
+	if err := service.collection(session).HardDelete(exp.Equal("_id", passkey.PasskeyID)); err != nil {
+		return derp.Wrap(err, location, "Deleting Passkey", passkey, note)
+	}
+
+	return nil
+}
+
+func (service *Passkey) Schema() schema.Schema {
+	return schema.New(model.PasskeySchema())
+}
+
+/******************************************
+ * Custom Queries
+ ******************************************/
+
+// QueryByUserID returns every Passkey owned by the provided User, oldest first
+func (service *Passkey) QueryByUserID(session data.Session, userID primitive.ObjectID) ([]model.Passkey, error) {
+	return service.Query(session, exp.Equal("userId", userID), option.SortAsc("createDate"))
+}
+
+// CountByUserID returns the number of Passkeys owned by the provided User
+func (service *Passkey) CountByUserID(session data.Session, userID primitive.ObjectID) (int64, error) {
+	return service.Count(session, exp.Equal("userId", userID))
+}
+
+// LoadByToken loads a Passkey owned by the provided User, using its string ID
+func (service *Passkey) LoadByToken(session data.Session, userID primitive.ObjectID, token string, passkey *model.Passkey) error {
+
+	passkeyID, err := primitive.ObjectIDFromHex(token)
+
+	if err != nil {
+		return derp.Wrap(err, "service.Passkey.LoadByToken", "Invalid Passkey ID", token, derp.WithNotFound())
+	}
+
+	return service.Load(session, exp.Equal("_id", passkeyID).AndEqual("userId", userID), passkey)
+}
+
+// LoadByCredentialID loads the Passkey with the provided (base64url encoded) credential ID
+func (service *Passkey) LoadByCredentialID(session data.Session, credentialID string, passkey *model.Passkey) error {
+	return service.Load(session, exp.Equal("credentialId", credentialID), passkey)
+}
+
+// DeleteByUserID removes every Passkey owned by the provided User (account teardown).
+func (service *Passkey) DeleteByUserID(session data.Session, userID primitive.ObjectID, note string) error {
+
+	const location = "service.Passkey.DeleteByUserID"
+
+	if err := service.collection(session).HardDelete(exp.Equal("userId", userID)); err != nil {
+		return derp.Wrap(err, location, "Deleting Passkeys", userID, note)
+	}
+
+	return nil
+}
+
+/******************************************
+ * WebAuthn Ceremonies
+ ******************************************/
+
+// RelyingParty returns the WebAuthn Relying Party for this domain
+func (service *Passkey) RelyingParty() webauthn.RelyingParty {
+
+	name := service.domainService.Get().Label
+
+	if name == "" {
+		name = service.hostname
+	}
+
+	return webauthn.RelyingParty{
+		ID:     service.hostname,
+		Name:   name,
+		Origin: service.host,
+	}
+}
+
+// NewChallenge returns a new WebAuthn challenge for the provided ceremony, along with a
+// signed token that proves (for a few minutes) that this server issued it.  The userID is
+// empty for passwordless signins, where the User is not known until they choose a passkey.
+func (service *Passkey) NewChallenge(ceremony string, userID primitive.ObjectID) (string, string, error) {
+
+	const location = "service.Passkey.NewChallenge"
+
+	challenge, err := webauthn.NewChallenge()
+
+	if err != nil {
+		return "", "", derp.Wrap(err, location, "Generating challenge")
+	}
+
+	token, err := service.jwtService.NewToken(jwt.MapClaims{
+		"sub": userID.Hex(),
+		"aud": ceremony,
+		"chl": challenge,
+		"exp": time.Now().Add(model.PasskeyChallengeDuration).Unix(),
+	})
+
+	if err != nil {
+		return "", "", derp.Wrap(err, location, "Creating challenge token")
+	}
+
+	return challenge, token, nil
+}
+
+// ParseChallenge validates a token created by NewChallenge, and returns the User and
+// challenge that it was issued for.
+func (service *Passkey) ParseChallenge(ceremony string, token string) (primitive.ObjectID, string, error) {
+
+	const location = "service.Passkey.ParseChallenge"
+
+	claims := jwt.MapClaims{}
+
+	if err := service.jwtService.ParseToken(token, &claims); err != nil {
+		return primitive.NilObjectID, "", derp.Wrap(err, location, "Parsing challenge token", derp.WithUnauthorized())
+	}
+
+	values := mapof.Any(claims)
+
+	if values.GetString("aud") != ceremony {
+		return primitive.NilObjectID, "", derp.Unauthorized(location, "Challenge was issued for a different ceremony")
+	}
+
+	userID, err := primitive.ObjectIDFromHex(values.GetString("sub"))
+
+	if err != nil {
+		return primitive.NilObjectID, "", derp.Wrap(err, location, "Invalid User ID", derp.WithUnauthorized())
+	}
+
+	return userID, values.GetString("chl"), nil
+}
+
+// CreationOptions returns the options that the browser needs to register a new passkey for the User
+func (service *Passkey) CreationOptions(session data.Session, user *model.User, challenge string) (webauthn.CreationOptions, error) {
+
+	const location = "service.Passkey.CreationOptions"
+
+	existing, err := service.credentials(session, user.UserID)
+
+	if err != nil {
+		return webauthn.CreationOptions{}, derp.Wrap(err, location, "Loading existing passkeys", user.UserID)
+	}
+
+	// The User ID is the "user handle" that authenticators return during passwordless signins
+	userHandle := user.UserID[:]
+
+	return service.RelyingParty().CreationOptions(challenge, userHandle, user.Username, user.DisplayName, existing), nil
+}
+
+// RequestOptions returns the options that the browser needs to sign in with a passkey.
+// Passwordless signins (with no User) let the browser offer every passkey it has for this site,
+// and require the authenticator to verify the User (with a PIN or biometric).  Second factors
+// are limited to the User's own passkeys, because the password has already been verified.
+func (service *Passkey) RequestOptions(session data.Session, userID primitive.ObjectID, challenge string) (webauthn.RequestOptions, error) {
+
+	const location = "service.Passkey.RequestOptions"
+
+	if userID.IsZero() {
+		return service.RelyingParty().RequestOptions(challenge, nil, "required"), nil
+	}
+
+	allowed, err := service.credentials(session, userID)
+
+	if err != nil {
+		return webauthn.RequestOptions{}, derp.Wrap(err, location, "Loading passkeys", userID)
+	}
+
+	return service.RelyingParty().RequestOptions(challenge, allowed, "preferred"), nil
+}
+
+// Register validates a new passkey that the User's browser created in response to the
+// provided challenge, and saves it.
+func (service *Passkey) Register(session data.Session, user *model.User, challenge string, label string, clientDataJSON []byte, attestationObject []byte, transports []string) (model.Passkey, error) {
+
+	const location = "service.Passkey.Register"
+
+	credential, err := service.RelyingParty().VerifyRegistration(challenge, clientDataJSON, attestationObject, true)
+
+	if err != nil {
+		return model.Passkey{}, derp.Wrap(err, location, "Verifying registration")
+	}
+
+	// Keep only the transport hints that browsers are allowed to report
+	credential.Transports = slices.DeleteFunc(slices.Clone(transports), func(transport string) bool {
+		return !slices.Contains(passkeyTransports, transport)
+	})
+
+	passkey := model.NewPasskey()
+	passkey.UserID = user.UserID
+	passkey.Label = label
+	passkey.SetCredential(credential)
+
+	// RULE: Each credential can only be registered once, to a single User
+	existing := model.NewPasskey()
+
+	if err := service.LoadByCredentialID(session, passkey.CredentialID, &existing); err == nil {
+		return model.Passkey{}, derp.Conflict(location, "This passkey has already been registered")
+	} else if !derp.IsNotFound(err) {
+		return model.Passkey{}, derp.Wrap(err, location, "Checking for duplicate passkey")
+	}
+
+	if passkey.Label == "" {
+		passkey.Label = "Passkey added " + time.Now().Format(time.DateOnly)
+	}
+
+	if err := service.Save(session, &passkey, "Registered"); err != nil {
+		return model.Passkey{}, derp.Wrap(err, location, "Saving passkey")
+	}
+
+	return passkey, nil
+}
+
+// Authenticate verifies an assertion from the Passkey's authenticator, and saves its new
+// signature counter.  User verification is required for passwordless signins.
+func (service *Passkey) Authenticate(session data.Session, passkey *model.Passkey, challenge string, assertion webauthn.Assertion, requireUserVerification bool) error {
+
+	const location = "service.Passkey.Authenticate"
+
+	// RULE: If the authenticator names a User, then it must be the Passkey's owner
+	if (len(assertion.UserHandle) > 0) && (string(assertion.UserHandle) != string(passkey.UserID[:])) {
+		return derp.Unauthorized(location, "User handle does not match passkey")
+	}
+
+	signCount, err := service.RelyingParty().VerifyAssertion(challenge, passkey.Credential(), assertion, requireUserVerification)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Verifying assertion")
+	}
+
+	passkey.SignCount = signCount
+	passkey.LastUsedDate = time.Now().UnixMilli()
+
+	if err := service.Save(session, passkey, "Used"); err != nil {
+		return derp.Wrap(err, location, "Saving passkey")
+	}
+
+	return nil
+}
+
+// credentials returns the WebAuthn credentials for every Passkey owned by the provided User
+func (service *Passkey) credentials(session data.Session, userID primitive.ObjectID) ([]webauthn.Credential, error) {
+
+	passkeys, err := service.QueryByUserID(session, userID)
+
+	if err != nil {
+		return nil, derp.Wrap(err, "service.Passkey.credentials", "Loading passkeys", userID)
+	}
+
+	result := make([]webauthn.Credential, len(passkeys))
+
+	for index, passkey := range passkeys {
+		result[index] = passkey.Credential()
+	}
+
+	return result, nil
+}
@@ -15,6 +15,7 @@ import (
 // Permission service manages user permissions and privileges
 type Permission struct {
 	activityService  *ActivityStream
+	groupService     *Group
 	identityService  *Identity
 	privilegeService *Privilege
 	userService      *User
@@ -28,13 +29,15 @@ func NewPermission() Permission {
 // Refresh updates links to additional services that may not have been initialized when this service was created.
 func (service *Permission) Refresh(factory *Factory) {
 	service.activityService = factory.ActivityStream()
+	service.groupService = factory.Group()
 	service.identityService = factory.Identity()
 	service.privilegeService = factory.Privilege()
 	service.userService = factory.User()
 }
 
-// UserCan returns TRUE if this action is permitted on a stream (using the provided authorization)
-func (service *Permission) UserCan(session data.Session, authorization *model.Authorization, template *model.Template, accessLister model.AccessLister, actionID string) (bool, error) {
+// UserCan returns TRUE if this action is permitted on a stream (using the provided authorization).
+// Group lookups are cached in the (optional) GroupMembership for the current request.
+func (service *Permission) UserCan(session data.Session, groups *GroupMembership, authorization *model.Authorization, template *model.Template, accessLister model.AccessLister, actionID string) (bool, error) {
 
 	const location = "service.Permission.UserCan"
 
@@ -73,6 +76,17 @@ func (service *Permission) UserCan(session data.Session, authorization *model.Au
 		if authorization.IsGroupMember(permissions...) {
 			return true, nil
 		}
+
+		// Otherwise, check if the user is a member of a Group named in the access list
+		inGroup, err := service.userInGroupRoles(session, groups, authorization, accessList...)
+
+		if err != nil {
+			return false, derp.Wrap(err, location, "Checking group roles")
+		}
+
+		if inGroup {
+			return true, nil
+		}
 	}
 
 	// These checks are only valid if the request includes an IdentityID
@@ -94,7 +108,7 @@ func (service *Permission) UserCan(session data.Session, authorization *model.Au
 }
 
 // UserCan returns TRUE if this action is permitted on a stream (using the provided authorization)
-func (service *Permission) TraceUserCan(session data.Session, authorization *model.Authorization, template *model.Template, accessLister model.AccessLister, actionID string) []string {
+func (service *Permission) TraceUserCan(session data.Session, groups *GroupMembership, authorization *model.Authorization, template *model.Template, accessLister model.AccessLister, actionID string) []string {
 
 	result := []string{"service.Permission.UserCan"}
 
@@ -150,6 +164,20 @@ func (service *Permission) TraceUserCan(session data.Session, authorization *mod
 			result = append(result, "Allow Group Member", "SUCCESS")
 			return result
 		}
+
+		// Otherwise, check if the user is a member of a Group named in the access list
+		inGroup, err := service.userInGroupRoles(session, groups, authorization, accessList...)
+
+		if err != nil {
+			result = append(result, "Error reading Groups: "+err.Error())
+			result = append(result, "FAILURE")
+			return result
+		}
+
+		if inGroup {
+			result = append(result, "Allow Group Role", "SUCCESS")
+			return result
+		}
 	}
 
 	// These checks are only valid if the request includes an IdentityID
@@ -176,7 +204,7 @@ func (service *Permission) TraceUserCan(session data.Session, authorization *mod
 }
 
 // UserHasRole returns TRUE if the user has access to the specified role
-func (service *Permission) UserHasRole(session data.Session, authorization *model.Authorization, accessLister model.AccessLister, role string) (bool, error) {
+func (service *Permission) UserHasRole(session data.Session, groups *GroupMembership, authorization *model.Authorization, accessLister model.AccessLister, role string) (bool, error) {
 
 	const location = "service.Permission.UserHasRole"
 
@@ -198,6 +226,11 @@ func (service *Permission) UserHasRole(session data.Session, authorization *mode
 		return authorization.DomainOwner, nil
 	}
 
+	// Group roles ("group:editors") are checked against the User's Group memberships
+	if groupToken, isGroup := model.GroupRoleToken(role); isGroup {
+		return service.UserInGroup(session, groups, authorization, groupToken)
+	}
+
 	// If the authorization includes GroupIDs, then check those next
 	if authorization.GroupIDs.NotEmpty() {
 
@@ -254,16 +287,6 @@ func (service *Permission) hasPrivilege(session data.Session, authorization *mod
 	return identity.HasPrivilege(requiredPrivileges...), nil
 }
 
-// UserInGroup returns TRUE if the user is a member of the specified group
-func (service *Permission) UserInGroup(authorization *model.Authorization, groupToken string) (bool, error) {
-	return false, derp.NotImplemented("service.Permission.UserInGroup", "UserInGroup is not implemented")
-}
-
-// AuthorInGroup returns TRUE if the Author/AttributedTo is a member of the specified group
-func (service *Permission) AuthorInGroup(accessLister model.AccessLister, groupToken string) (bool, error) {
-	return false, derp.NotImplemented("service.Permission.AuthorInGroup", "AuthorInGroup is not implemented")
-}
-
 func (service *Permission) Permissions(authorization *model.Authorization, identity *model.Identity) model.Permissions {
 
 	result := model.NewAnonymousPermissions()
@@ -70,7 +70,7 @@ func TestUserCan_View_AuthorOnly_DeniesOtherUser(t *testing.T) {
 	authorAuth := model.NewAuthorization()
 	authorAuth.UserID = author
 
-	allowed, err := permissionService.UserCan(nil, &authorAuth, &template, &stream, "view")
+	allowed, err := permissionService.UserCan(nil, nil, &authorAuth, &template, &stream, "view")
 	require.Nil(t, err)
 	require.True(t, allowed, "the author must be allowed to view their own stream")
 
@@ -78,7 +78,7 @@ func TestUserCan_View_AuthorOnly_DeniesOtherUser(t *testing.T) {
 	otherAuth := model.NewAuthorization()
 	otherAuth.UserID = otherUser
 
-	allowed, err = permissionService.UserCan(nil, &otherAuth, &template, &stream, "view")
+	allowed, err = permissionService.UserCan(nil, nil, &otherAuth, &template, &stream, "view")
 	require.Nil(t, err)
 	require.False(t, allowed, "a non-author user must NOT be allowed to view an author-gated stream")
 }
@@ -107,11 +107,11 @@ func TestUserCan_AuthorGatedActions(t *testing.T) {
 		stream := authorStream(author)
 		template := actionTemplate(actionID, model.MagicRoleAuthor)
 
-		allowed, err := permissionService.UserCan(nil, &authorAuth, &template, &stream, actionID)
+		allowed, err := permissionService.UserCan(nil, nil, &authorAuth, &template, &stream, actionID)
 		require.Nil(t, err)
 		require.True(t, allowed, "the author must be allowed to "+actionID+" their own stream")
 
-		allowed, err = permissionService.UserCan(nil, &otherAuth, &template, &stream, actionID)
+		allowed, err = permissionService.UserCan(nil, nil, &otherAuth, &template, &stream, actionID)
 		require.Nil(t, err)
 		require.False(t, allowed, "a non-author user must NOT be allowed to "+actionID+" an author-gated stream")
 	}
@@ -129,7 +129,7 @@ func TestUserCan_View_AuthorOnly_DeniesAnonymous(t *testing.T) {
 	// An anonymous (unauthenticated) caller must be denied.
 	anonAuth := model.NewAuthorization()
 
-	allowed, err := permissionService.UserCan(nil, &anonAuth, &template, &stream, "view")
+	allowed, err := permissionService.UserCan(nil, nil, &anonAuth, &template, &stream, "view")
 	require.Nil(t, err)
 	require.False(t, allowed, "an anonymous caller must NOT be allowed to view an author-gated stream")
 }
@@ -148,14 +148,14 @@ func TestUserCan_View_Anonymous_AllowsEveryone(t *testing.T) {
 	otherAuth := model.NewAuthorization()
 	otherAuth.UserID = otherUser
 
-	allowed, err := permissionService.UserCan(nil, &otherAuth, &template, &stream, "view")
+	allowed, err := permissionService.UserCan(nil, nil, &otherAuth, &template, &stream, "view")
 	require.Nil(t, err)
 	require.True(t, allowed, "any user may view an anonymous-gated stream")
 
 	// ...and by an anonymous caller.
 	anonAuth := model.NewAuthorization()
 
-	allowed, err = permissionService.UserCan(nil, &anonAuth, &template, &stream, "view")
+	allowed, err = permissionService.UserCan(nil, nil, &anonAuth, &template, &stream, "view")
 	require.Nil(t, err)
 	require.True(t, allowed, "an anonymous caller may view an anonymous-gated stream")
 }
@@ -174,7 +174,7 @@ func TestUserCan_View_DomainOwner_AlwaysAllowed(t *testing.T) {
 	ownerAuth.UserID = primitive.NewObjectID()
 	ownerAuth.DomainOwner = true
 
-	allowed, err := permissionService.UserCan(nil, &ownerAuth, &template, &stream, "view")
+	allowed, err := permissionService.UserCan(nil, nil, &ownerAuth, &template, &stream, "view")
 	require.Nil(t, err)
 	require.True(t, allowed, "a domain owner may view any stream")
 }
@@ -194,7 +194,7 @@ func TestUserCan_View_MissingAction_Denies(t *testing.T) {
 	authorAuth := model.NewAuthorization()
 	authorAuth.UserID = author
 
-	allowed, err := permissionService.UserCan(nil, &authorAuth, &template, &stream, "view")
+	allowed, err := permissionService.UserCan(nil, nil, &authorAuth, &template, &stream, "view")
 	require.Nil(t, err)
 	require.False(t, allowed, "a missing view action must deny access")
 }
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"slices"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/id"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/******************************************
+ * Group Membership
+ ******************************************/
+
+// GroupMembership caches Group lookups for a single request, so that templates that
+// check the same Groups many times only query the database once.  A nil GroupMembership
+// is valid, and simply does not cache any values.
+type GroupMembership struct {
+	groupIDs   map[string]primitive.ObjectID   // Maps Group tokens to GroupIDs (NilObjectID if not found)
+	userGroups map[primitive.ObjectID]id.Slice // Maps UserIDs to the Groups they belong to
+}
+
+// NewGroupMembership returns a new, empty GroupMembership cache
+func NewGroupMembership() *GroupMembership {
+	return &GroupMembership{
+		groupIDs:   make(map[string]primitive.ObjectID),
+		userGroups: make(map[primitive.ObjectID]id.Slice),
+	}
+}
+
+// UserInGroup returns TRUE if the user is a member of the specified group
+func (service *Permission) UserInGroup(session data.Session, groups *GroupMembership, authorization *model.Authorization, groupToken string) (bool, error) {
+
+	const location = "service.Permission.UserInGroup"
+
+	// RULE: Anonymous visitors are not members of any Group
+	if !authorization.IsAuthenticated() {
+		return false, nil
+	}
+
+	result, err := service.isGroupMember(session, groups, authorization.UserID, groupToken)
+
+	if err != nil {
+		return false, derp.Wrap(err, location, "Checking group membership", groupToken)
+	}
+
+	return result, nil
+}
+
+// AuthorInGroup returns TRUE if the Author/AttributedTo is a member of the specified group
+func (service *Permission) AuthorInGroup(session data.Session, groups *GroupMembership, accessLister model.AccessLister, groupToken string) (bool, error) {
+
+	const location = "service.Permission.AuthorInGroup"
+
+	// RULE: Only objects that are authored by a local User can be checked
+	authorIDGetter, ok := accessLister.(model.AuthorIDGetter)
+
+	if !ok {
+		return false, nil
+	}
+
+	authorID := authorIDGetter.AuthorID()
+
+	if authorID.IsZero() {
+		return false, nil
+	}
+
+	result, err := service.isGroupMember(session, groups, authorID, groupToken)
+
+	if err != nil {
+		return false, derp.Wrap(err, location, "Checking group membership", groupToken)
+	}
+
+	return result, nil
+}
+
+// userInGroupRoles returns TRUE if the user is a member of any Group named by a "group:" role
+func (service *Permission) userInGroupRoles(session data.Session, groups *GroupMembership, authorization *model.Authorization, roles ...string) (bool, error) {
+
+	const location = "service.Permission.userInGroupRoles"
+
+	for _, role := range roles {
+
+		groupToken, isGroup := model.GroupRoleToken(role)
+
+		if !isGroup {
+			continue
+		}
+
+		inGroup, err := service.UserInGroup(session, groups, authorization, groupToken)
+
+		if err != nil {
+			return false, derp.Wrap(err, location, "Checking group role", role)
+		}
+
+		if inGroup {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// isGroupMember returns TRUE if the User is a member of the Group identified by the groupToken
+func (service *Permission) isGroupMember(session data.Session, groups *GroupMembership, userID primitive.ObjectID, groupToken string) (bool, error) {
+
+	const location = "service.Permission.isGroupMember"
+
+	groupID, err := service.groupID(session, groups, groupToken)
+
+	if err != nil {
+		return false, derp.Wrap(err, location, "Loading group", groupToken)
+	}
+
+	// Unknown Groups have no members
+	if groupID.IsZero() {
+		return false, nil
+	}
+
+	userGroups, err := service.userGroupIDs(session, groups, userID)
+
+	if err != nil {
+		return false, derp.Wrap(err, location, "Loading user groups", userID)
+	}
+
+	return slices.Contains(userGroups, groupID), nil
+}
+
+// groupID returns the GroupID that matches a Group token, or a NilObjectID if the Group does not exist
+func (service *Permission) groupID(session data.Session, groups *GroupMembership, groupToken string) (primitive.ObjectID, error) {
+
+	const location = "service.Permission.groupID"
+
+	if groups != nil {
+		if groupID, exists := groups.groupIDs[groupToken]; exists {
+			return groupID, nil
+		}
+	}
+
+	group := model.NewGroup()
+
+	if err := service.groupService.LoadByToken(session, groupToken, &group); err != nil {
+
+		if !derp.IsNotFound(err) {
+			return primitive.NilObjectID, derp.Wrap(err, location, "Loading group", groupToken)
+		}
+
+		group.GroupID = primitive.NilObjectID
+	}
+
+	if groups != nil {
+		groups.groupIDs[groupToken] = group.GroupID
+	}
+
+	return group.GroupID, nil
+}
+
+// userGroupIDs returns the GroupIDs that a User belongs to
+func (service *Permission) userGroupIDs(session data.Session, groups *GroupMembership, userID primitive.ObjectID) (id.Slice, error) {
+
+	const location = "service.Permission.userGroupIDs"
+
+	if groups != nil {
+		if groupIDs, exists := groups.userGroups[userID]; exists {
+			return groupIDs, nil
+		}
+	}
+
+	user := model.NewUser()
+
+	if err := service.userService.LoadByID(session, userID, &user); err != nil {
+
+		if !derp.IsNotFound(err) {
+			return nil, derp.Wrap(err, location, "Loading user", userID)
+		}
+
+		user.GroupIDs = id.NewSlice()
+	}
+
+	if groups != nil {
+		groups.userGroups[userID] = user.GroupIDs
+	}
+
+	return user.GroupIDs, nil
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/id"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// These tests pre-populate the GroupMembership cache, which is exactly what
+// lets a single request check the same Groups many times without returning
+// to the database.  A nil session is safe because every lookup is a cache hit.
+
+func groupMembershipFixture(userID primitive.ObjectID, editorsID primitive.ObjectID) *GroupMembership {
+	groups := NewGroupMembership()
+	groups.groupIDs["editors"] = editorsID
+	groups.groupIDs["moderators"] = primitive.NewObjectID()
+	groups.groupIDs["missing"] = primitive.NilObjectID
+	groups.userGroups[userID] = id.Slice{editorsID}
+	return groups
+}
+
+func TestPermission_UserInGroup(t *testing.T) {
+
+	userID := primitive.NewObjectID()
+	groups := groupMembershipFixture(userID, primitive.NewObjectID())
+	permissionService := NewPermission()
+
+	authorization := model.NewAuthorization()
+	authorization.UserID = userID
+
+	inGroup, err := permissionService.UserInGroup(nil, groups, &authorization, "editors")
+	require.Nil(t, err)
+	require.True(t, inGroup)
+
+	inGroup, err = permissionService.UserInGroup(nil, groups, &authorization, "moderators")
+	require.Nil(t, err)
+	require.False(t, inGroup)
+
+	inGroup, err = permissionService.UserInGroup(nil, groups, &authorization, "missing")
+	require.Nil(t, err)
+	require.False(t, inGroup)
+
+	// Anonymous visitors are never group members
+	anonymous := model.NewAuthorization()
+	inGroup, err = permissionService.UserInGroup(nil, groups, &anonymous, "editors")
+	require.Nil(t, err)
+	require.False(t, inGroup)
+}
+
+func TestPermission_AuthorInGroup(t *testing.T) {
+
+	authorID := primitive.NewObjectID()
+	groups := groupMembershipFixture(authorID, primitive.NewObjectID())
+	permissionService := NewPermission()
+
+	stream := authorStream(authorID)
+
+	inGroup, err := permissionService.AuthorInGroup(nil, groups, stream, "editors")
+	require.Nil(t, err)
+	require.True(t, inGroup)
+
+	inGroup, err = permissionService.AuthorInGroup(nil, groups, stream, "moderators")
+	require.Nil(t, err)
+	require.False(t, inGroup)
+
+	// Objects without a local author are never in a group
+	remote := authorStream(primitive.NilObjectID)
+	inGroup, err = permissionService.AuthorInGroup(nil, groups, remote, "editors")
+	require.Nil(t, err)
+	require.False(t, inGroup)
+}
+
+func TestUserCan_GroupRole(t *testing.T) {
+
+	editor := primitive.NewObjectID()
+	otherUser := primitive.NewObjectID()
+	groups := groupMembershipFixture(editor, primitive.NewObjectID())
+	groups.userGroups[otherUser] = id.NewSlice()
+
+	stream := authorStream(primitive.NewObjectID())
+	template := actionTemplate("publish", "group:editors")
+	permissionService := NewPermission()
+
+	// Members of the "editors" group may publish
+	editorAuth := model.NewAuthorization()
+	editorAuth.UserID = editor
+
+	allowed, err := permissionService.UserCan(nil, groups, &editorAuth, &template, &stream, "publish")
+	require.Nil(t, err)
+	require.True(t, allowed)
+
+	// Everyone else may not
+	otherAuth := model.NewAuthorization()
+	otherAuth.UserID = otherUser
+
+	allowed, err = permissionService.UserCan(nil, groups, &otherAuth, &template, &stream, "publish")
+	require.Nil(t, err)
+	require.False(t, allowed)
+
+	// UserHasRole understands group roles, too
+	hasRole, err := permissionService.UserHasRole(nil, groups, &editorAuth, &stream, "group:editors")
+	require.Nil(t, err)
+	require.True(t, hasRole)
+}
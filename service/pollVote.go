@@ -0,0 +1,560 @@
+package service
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/postcommit"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/rosetta/schema"
+	"github.com/benpate/turbine/queue"
+	"github.com/benpate/uri"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PollVote manages the votes cast in Polls: votes that our Users send to local
+// and remote Polls, and votes that arrive for Polls that our Users have published.
+type PollVote struct {
+	activityStreamService *ActivityStream
+	outboxService         *Outbox
+	ruleService           *Rule
+	streamService         *Stream
+	userService           *User
+	queue                 *queue.Queue
+	host                  string
+}
+
+// NewPollVote returns a fully initialized PollVote service
+func NewPollVote() PollVote {
+	return PollVote{}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+// Refresh updates any stateful data that is cached inside this service.
+func (service *PollVote) Refresh(factory *Factory) {
+	service.activityStreamService = factory.ActivityStream()
+	service.outboxService = factory.Outbox()
+	service.ruleService = factory.Rule()
+	service.streamService = factory.Stream()
+	service.userService = factory.User()
+	service.queue = factory.Queue()
+	service.host = factory.Host()
+}
+
+// Close stops any background processes controlled by this service
+func (service *PollVote) Close() {
+	// Nothin to do here.
+}
+
+/******************************************
+ * Common Data Methods
+ ******************************************/
+
+func (service *PollVote) collection(session data.Session) data.Collection {
+	return session.Collection("PollVote")
+}
+
+// Count returns the number of PollVotes that match the provided criteria
+func (service *PollVote) Count(session data.Session, criteria exp.Expression) (int64, error) {
+	return service.collection(session).Count(notDeleted(criteria))
+}
+
+// Query returns a slice of PollVotes that match the provided criteria
+func (service *PollVote) Query(session data.Session, criteria exp.Expression, options ...option.Option) ([]model.PollVote, error) {
+	result := make([]model.PollVote, 0)
+	err := service.collection(session).Query(&result, notDeleted(criteria), options...)
+	return result, err
+}
+
+// Load retrieves a PollVote from the database
+func (service *PollVote) Load(session data.Session, criteria exp.Expression, vote *model.PollVote) error {
+
+	if err := service.collection(session).Load(notDeleted(criteria), vote); err != nil {
+		return derp.Wrap(err, "service.PollVote.Load", "Loading PollVote", criteria)
+	}
+
+	return nil
+}
+
+// Save adds/updates a PollVote in the database
+func (service *PollVote) Save(session data.Session, vote *model.PollVote, note string) error {
+
+	const location = "service.PollVote.Save"
+
+	if _, err := service.Schema().Validate(vote); err != nil {
+		return derp.Wrap(err, location, "Validating PollVote", vote)
+	}
+
+	if err := service.collection(session).Save(vote, note); err != nil {
+		return derp.Wrap(err, location, "Saving PollVote", vote, note)
+	}
+
+	return nil
+}
+
+// Delete removes a PollVote from the database (hard delete)
+func (service *PollVote) Delete(session data.Session, vote *model.PollVote, note string) error {
+
+	const location = "service.PollVote.Delete"
+
+	if err := service.collection(session).HardDelete(exp.Equal("_id", vote.PollVoteID)); err != nil {
+		return derp.Wrap(err, location, "Deleting PollVote", vote, note)
+	}
+
+	return nil
+}
+
+func (service *PollVote) Schema() schema.Schema {
+	return schema.New(model.PollVoteSchema())
+}
+
+/******************************************
+ * Custom Queries
+ ******************************************/
+
+// QueryByPollURL returns every vote cast in the provided Poll
+func (service *PollVote) QueryByPollURL(session data.Session, pollURL string) ([]model.PollVote, error) {
+	return service.Query(session, exp.Equal("pollUrl", pollURL))
+}
+
+// LoadByVoter loads the vote that an actor cast in the provided Poll
+func (service *PollVote) LoadByVoter(session data.Session, pollURL string, voterURL string, vote *model.PollVote) error {
+	criteria := exp.Equal("pollUrl", pollURL).AndEqual("voterUrl", voterURL)
+	return service.Load(session, criteria, vote)
+}
+
+// DeleteByUserID removes every vote cast by the provided User
+func (service *PollVote) DeleteByUserID(session data.Session, userID primitive.ObjectID, note string) error {
+
+	const location = "service.PollVote.DeleteByUserID"
+
+	if err := service.collection(session).HardDelete(exp.Equal("userId", userID)); err != nil {
+		return derp.Wrap(err, location, "Deleting PollVotes", userID, note)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Poll Behaviors
+ ******************************************/
+
+// LoadPoll returns the Poll published at the provided URL, along with the indexes that the
+// User has already voted for (nil if they have not voted).  Local Polls are read from their
+// Stream, and remote Polls are read from the Question in the ActivityStream cache.
+func (service *PollVote) LoadPoll(session data.Session, user *model.User, pollURL string) (model.Poll, []int, error) {
+
+	const location = "service.PollVote.LoadPoll"
+
+	poll, stream, err := service.loadPoll(session, user.UserID, pollURL)
+
+	if err != nil {
+		return model.Poll{}, nil, derp.Wrap(err, location, "Loading Poll", pollURL)
+	}
+
+	// Votes in local Polls are recorded against the Stream's canonical URL
+	if stream != nil {
+		pollURL = stream.ActivityPubURL()
+	}
+
+	// Look for the User's own vote
+	vote := model.NewPollVote()
+
+	if err := service.LoadByVoter(session, pollURL, user.ActivityPubURL(), &vote); err != nil {
+
+		if derp.IsNotFound(err) {
+			return poll.Poll, nil, nil
+		}
+
+		return model.Poll{}, nil, derp.Wrap(err, location, "Loading PollVote", pollURL)
+	}
+
+	return poll.Poll, vote.ChoiceIndexes(poll.Poll), nil
+}
+
+// Vote casts a User's vote in a Poll.  Votes in local Polls are tallied immediately. Votes in
+// remote Polls are sent to the Poll's author as one Note per choice, with the option's name
+// as the Note's "name".  It returns the updated Poll and the indexes that the User selected.
+func (service *PollVote) Vote(session data.Session, user *model.User, pollURL string, choices []int) (model.Poll, []int, error) {
+
+	const location = "service.PollVote.Vote"
+
+	poll, stream, err := service.loadPoll(session, user.UserID, pollURL)
+
+	if err != nil {
+		return model.Poll{}, nil, derp.Wrap(err, location, "Loading Poll", pollURL)
+	}
+
+	// RULE: Poll must still be accepting votes
+	if !poll.IsOpen(time.Now().Unix()) {
+		return model.Poll{}, nil, derp.Validation("This poll has already ended", pollURL)
+	}
+
+	// RULE: Choices must match the options in the Poll
+	names, isValid := poll.OptionNames(choices)
+
+	if !isValid {
+		return model.Poll{}, nil, derp.Validation("Invalid choices for this poll", pollURL, choices)
+	}
+
+	// RULE: Cannot vote in a Poll written by a blocked actor
+	if stream == nil {
+		disposition, err := service.ruleService.DispositionForKeys(session, user.UserID, model.ActorMatchKeys(poll.AuthorURL), time.Now().Unix())
+
+		if err != nil {
+			return model.Poll{}, nil, derp.Wrap(err, location, "Checking rules before voting", poll.AuthorURL)
+		}
+
+		if disposition.IsBlocked() {
+			return model.Poll{}, nil, derp.Forbidden(location, "Cannot vote in a poll from a blocked account", poll.AuthorURL)
+		}
+	}
+
+	// Record the vote.  The unique (pollUrl, voterUrl) index rejects a second vote from the same User.
+	vote := model.NewPollVote()
+	vote.UserID = user.UserID
+	vote.PollURL = pollURL
+	vote.VoterURL = user.ActivityPubURL()
+	vote.Choices = names
+
+	if stream != nil {
+		vote.StreamID = stream.StreamID
+		vote.PollURL = stream.ActivityPubURL()
+	}
+
+	if err := service.Save(session, &vote, "Voted"); err != nil {
+
+		if derp.IsConflict(err) {
+			return model.Poll{}, nil, derp.Validation("You have already voted in this poll", pollURL)
+		}
+
+		return model.Poll{}, nil, derp.Wrap(err, location, "Saving PollVote", vote)
+	}
+
+	// Local Polls are tallied directly
+	if stream != nil {
+
+		if err := service.tally(session, stream); err != nil {
+			return model.Poll{}, nil, derp.Wrap(err, location, "Tallying votes", stream.StreamID)
+		}
+
+		return stream.Poll, vote.ChoiceIndexes(stream.Poll), nil
+	}
+
+	// Remote Polls receive one Note per choice
+	for index, name := range names {
+		if err := service.sendVote(session, user, &vote, poll.AuthorURL, index, name); err != nil {
+			return model.Poll{}, nil, derp.Wrap(err, location, "Sending vote", pollURL, name)
+		}
+	}
+
+	// Reflect the new vote in the (cached) results until the author publishes an update
+	for _, name := range names {
+		poll.Options[poll.OptionIndex(name)].VoteCount++
+	}
+
+	poll.VoterCount++
+
+	return poll.Poll, vote.ChoiceIndexes(poll.Poll), nil
+}
+
+// ReceiveVote tallies a vote that arrived for a Poll published by one of our Users. It
+// returns TRUE if the activity was a vote in a local Poll, meaning that it has been fully
+// handled and should not be treated as a reply or a direct message.
+func (service *PollVote) ReceiveVote(session data.Session, activity streams.Document) (bool, error) {
+
+	const location = "service.PollVote.ReceiveVote"
+
+	// RULE: Only Notes that look like votes
+	document := activity.UnwrapActivity()
+
+	if !model.IsPollVote(document) {
+		return false, nil
+	}
+
+	// RULE: Only votes for local Polls
+	pollURL := document.InReplyTo().ID()
+
+	if !strings.HasPrefix(pollURL, service.host+"/") {
+		return false, nil
+	}
+
+	stream := model.NewStream()
+
+	if err := service.streamService.LoadByURL(session, pollURL, &stream); err != nil {
+
+		if derp.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, derp.Wrap(err, location, "Loading Stream", pollURL)
+	}
+
+	if stream.Poll.IsZero() {
+		return false, nil
+	}
+
+	// From here on, the activity is definitely a vote. Invalid votes are dropped, not stored.
+	pollURL = stream.ActivityPubURL()
+	voterURL := activity.ActorID()
+
+	// RULE: Voters can only vote for themselves
+	if attributedTo := document.AttributedTo().ID(); (attributedTo != "") && (attributedTo != voterURL) {
+		log.Debug().Str("voter", voterURL).Str("attributedTo", attributedTo).Msg("PollVote: ignoring vote attributed to a different actor")
+		return true, nil
+	}
+
+	// RULE: Closed Polls do not accept new votes
+	if stream.Poll.IsClosed(time.Now().Unix()) {
+		return true, nil
+	}
+
+	// RULE: Choice must match an option in the Poll
+	choice := document.Name()
+
+	if stream.Poll.OptionIndex(choice) < 0 {
+		return true, nil
+	}
+
+	// Find (or create) this voter's PollVote.  Multiple-choice votes arrive as separate
+	// Notes, so each one adds a choice to the same record.
+	vote := model.NewPollVote()
+
+	if err := service.LoadByVoter(session, pollURL, voterURL, &vote); err == nil {
+
+		// RULE: Single-choice Polls accept only one vote per voter
+		if !stream.Poll.Multiple {
+			return true, nil
+		}
+
+		// RULE: Repeated choices are ignored
+		if slices.Contains(vote.Choices, choice) {
+			return true, nil
+		}
+
+	} else if derp.IsNotFound(err) {
+		vote.StreamID = stream.StreamID
+		vote.PollURL = pollURL
+		vote.VoterURL = voterURL
+
+	} else {
+		return false, derp.Wrap(err, location, "Loading PollVote", pollURL, voterURL)
+	}
+
+	vote.Choices = append(vote.Choices, choice)
+
+	if err := service.Save(session, &vote, "Vote received"); err != nil {
+
+		// A lost creation race against another Note from the same voter.  Let the sender retry.
+		return false, derp.Wrap(err, location, "Saving PollVote", vote)
+	}
+
+	if err := service.tally(session, &stream); err != nil {
+		return false, derp.Wrap(err, location, "Tallying votes", stream.StreamID)
+	}
+
+	return true, nil
+}
+
+// ClosePoll ends voting in a Poll whose end date has passed, and publishes the final results.
+// Polls that have not yet ended (because their end date moved) are left alone.
+func (service *PollVote) ClosePoll(session data.Session, stream *model.Stream) error {
+
+	const location = "service.PollVote.ClosePoll"
+
+	now := time.Now().Unix()
+
+	// RULE: Only open Polls whose end date has passed can be closed
+	if (stream.Poll.ClosedDate > 0) || (stream.Poll.EndDate == 0) || (stream.Poll.EndDate > now) {
+		return nil
+	}
+
+	// Recount the votes one last time, then close the Poll
+	votes, err := service.QueryByPollURL(session, stream.ActivityPubURL())
+
+	if err != nil {
+		return derp.Wrap(err, location, "Loading PollVotes", stream.StreamID)
+	}
+
+	stream.Poll.Tally(votes)
+	stream.Poll.ClosedDate = now
+
+	// Unpublished Streams only need to be saved
+	if !stream.IsPublished() {
+
+		if err := service.streamService.Save(session, stream, "Poll closed"); err != nil {
+			return derp.Wrap(err, location, "Saving Stream", stream.StreamID)
+		}
+
+		return nil
+	}
+
+	// Republish the Stream so that everyone sees the final results
+	user := model.NewUser()
+
+	if err := service.userService.LoadByID(session, stream.AttributedTo.UserID, &user); err != nil {
+		return derp.Wrap(err, location, "Loading author", stream.AttributedTo.UserID)
+	}
+
+	if err := service.streamService.Publish(session, &user, stream, stream.StateID, true, true); err != nil {
+		return derp.Wrap(err, location, "Republishing Stream", stream.StreamID)
+	}
+
+	return nil
+}
+
+// ScheduleClose queues a task to close the Stream's Poll when its end date arrives, replacing
+// any task that was scheduled for a previous end date.  Streams without an open, expiring Poll
+// only have their previous task removed.  A stale task that still runs is harmless, because
+// ClosePoll ignores Polls whose end date has not yet passed.
+func (service *PollVote) ScheduleClose(session data.Session, stream *model.Stream) {
+
+	signature := pollCloseSignature(stream)
+
+	// Remove the task for the previous end date (if any) once the transaction commits
+	postcommit.Delete(session, service.queue, signature)
+
+	now := time.Now().Unix()
+
+	if !stream.Poll.IsOpen(now) || (stream.Poll.EndDate == 0) {
+		return
+	}
+
+	postcommit.Publish(
+		session,
+		service.queue,
+		"ClosePoll",
+		mapof.Any{
+			"hostname": uri.Hostname(service.host),
+			"streamId": stream.StreamID.Hex(),
+		},
+		queue.WithSignature(signature),
+		queue.WithDelaySeconds(int(stream.Poll.EndDate-now)),
+	)
+}
+
+// pollCloseSignature returns the unique queue signature for a Stream's ClosePoll task
+func pollCloseSignature(stream *model.Stream) string {
+	return "POLL-CLOSE:" + stream.StreamID.Hex()
+}
+
+/******************************************
+ * Helper Methods
+ ******************************************/
+
+// remotePoll is a Poll read from the Fediverse, along with the actor who wrote it
+type remotePoll struct {
+	model.Poll
+	AuthorURL string
+}
+
+// loadPoll returns the Poll at the provided URL.  Local Polls also return their Stream,
+// which is nil for remote Polls.
+func (service *PollVote) loadPoll(session data.Session, userID primitive.ObjectID, pollURL string) (remotePoll, *model.Stream, error) {
+
+	const location = "service.PollVote.loadPoll"
+
+	// Local Polls are read directly from their Stream
+	if strings.HasPrefix(pollURL, service.host+"/") {
+
+		stream := model.NewStream()
+
+		if err := service.streamService.LoadByURL(session, pollURL, &stream); err != nil {
+			return remotePoll{}, nil, derp.Wrap(err, location, "Loading Stream", pollURL)
+		}
+
+		if stream.Poll.IsZero() {
+			return remotePoll{}, nil, derp.NotFound(location, "Stream does not contain a poll", pollURL)
+		}
+
+		return remotePoll{Poll: stream.Poll, AuthorURL: stream.AttributedTo.ProfileURL}, &stream, nil
+	}
+
+	// Remote Polls are read from the ActivityStream cache
+	document, err := service.activityStreamService.UserClient(userID).Load(pollURL)
+
+	if err != nil {
+		return remotePoll{}, nil, derp.Wrap(err, location, "Loading Question", pollURL)
+	}
+
+	poll := model.PollFromDocument(document)
+
+	if poll.IsZero() {
+		return remotePoll{}, nil, derp.NotFound(location, "Document does not contain a poll", pollURL)
+	}
+
+	authorURL := document.AttributedTo().ID()
+
+	if authorURL == "" {
+		authorURL = document.ActorID()
+	}
+
+	return remotePoll{Poll: poll, AuthorURL: authorURL}, nil, nil
+}
+
+// tally recounts the votes in a local Poll and saves the results.  The cached copy of the
+// Question is refreshed too, so remote servers that fetch it see the current totals.
+func (service *PollVote) tally(session data.Session, stream *model.Stream) error {
+
+	const location = "service.PollVote.tally"
+
+	votes, err := service.QueryByPollURL(session, stream.ActivityPubURL())
+
+	if err != nil {
+		return derp.Wrap(err, location, "Loading PollVotes", stream.StreamID)
+	}
+
+	stream.Poll.Tally(votes)
+
+	if err := service.streamService.Save(session, stream, "Poll votes tallied"); err != nil {
+		return derp.Wrap(err, location, "Saving Stream", stream.StreamID)
+	}
+
+	if stream.IsPublished() {
+		if err := service.activityStreamService.Save(service.streamService.Activity(session, stream)); err != nil {
+			derp.Report(derp.Wrap(err, location, "Refreshing cached Question", stream.StreamID))
+		}
+	}
+
+	return nil
+}
+
+// sendVote delivers one choice of a User's vote to the author of a remote Poll.  Votes are
+// private: they go only to the author, and are not visible in the User's public outbox.
+func (service *PollVote) sendVote(session data.Session, user *model.User, vote *model.PollVote, authorURL string, index int, name string) error {
+
+	const location = "service.PollVote.sendVote"
+
+	activity := mapof.Any{
+		vocab.AtContext:    vocab.ContextTypeActivityStreams,
+		vocab.PropertyType: vocab.ActivityTypeCreate,
+		vocab.PropertyTo:   []string{authorURL},
+		vocab.PropertyObject: mapof.Any{
+			vocab.PropertyID:           user.ActivityPubURL() + "#votes/" + vote.PollVoteID.Hex() + "/" + strconv.Itoa(index),
+			vocab.PropertyType:         vocab.ObjectTypeNote,
+			vocab.PropertyName:         name,
+			vocab.PropertyInReplyTo:    vote.PollURL,
+			vocab.PropertyAttributedTo: user.ActivityPubURL(),
+			vocab.PropertyTo:           []string{authorURL},
+		},
+	}
+
+	if err := service.outboxService.Publish(session, model.FollowerTypeUser, user.UserID, streams.NewDocument(activity), model.NewPermissions(), WithRecipients(authorURL)); err != nil {
+		return derp.Wrap(err, location, "Publishing vote", vote.PollURL)
+	}
+
+	return nil
+}
@@ -20,6 +20,7 @@ type Privilege struct {
 	identityService        *Identity
 	importItemService      *ImportItem
 	merchantAccountService *MerchantAccount
+	webhookService         *Webhook
 }
 
 // NewPrivilege returns a fully initialized Privilege service
@@ -37,6 +38,7 @@ func (service *Privilege) Refresh(factory *Factory) {
 	service.identityService = factory.Identity()
 	service.importItemService = factory.ImportItem()
 	service.merchantAccountService = factory.MerchantAccount()
+	service.webhookService = factory.Webhook()
 }
 
 // Close stops any background processes controlled by this service
@@ -108,6 +110,8 @@ func (service *Privilege) Save(session data.Session, privilege *model.Privilege,
 		return derp.Wrap(err, location, "Validating Privilege", privilege)
 	}
 
+	isNew := privilege.IsNew()
+
 	// If the Identity does not exists, then creat a new Identity for this Privilege
 	if err := service.maybeCreateIdentity(session, privilege); err != nil {
 		return derp.Wrap(err, location, "Creating related Identity")
@@ -140,6 +144,11 @@ func (service *Privilege) Save(session data.Session, privilege *model.Privilege,
 		}
 	}
 
+	// Send privilege:create Webhooks
+	if isNew {
+		service.webhookService.Send(session, privilege, model.WebhookEventPrivilegeCreate)
+	}
+
 	return nil
 }
 
@@ -165,6 +174,9 @@ func (service *Privilege) Delete(session data.Session, privilege *model.Privileg
 		}
 	}
 
+	// Send privilege:delete Webhooks
+	service.webhookService.Send(session, privilege, model.WebhookEventPrivilegeDelete)
+
 	return nil
 }
 
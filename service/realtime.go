@@ -0,0 +1,34 @@
+package service
+
+import (
+	"github.com/EmissarySocial/emissary/tools/postcommit"
+	"github.com/benpate/data"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/turbine/queue"
+	"github.com/benpate/uri"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/******************************************
+ * Realtime Change Messages
+ ******************************************/
+
+// publishChange sends a best-effort realtime nudge that a database record (Stream, User, or
+// Import) has changed.  On a replica set, every node's database watchers already see the change,
+// so the broker drops this message.  On a standalone server, it is shared with every node
+// through the realtime transport.  Like publishStreamingEvent, this rides the post-commit spool
+// so that browsers never refetch a record before it has been committed.
+func publishChange(session data.Session, q *queue.Queue, host string, topic int, objectID primitive.ObjectID) {
+
+	// Skip "zero" records, matching the database watchers
+	if objectID.IsZero() {
+		return
+	}
+
+	postcommit.Publish(session, q, "PublishRealtimeMessage", mapof.Any{
+		"hostname": uri.Hostname(host),
+		"objectId": objectID.Hex(),
+		"topic":    topic,
+		"change":   true,
+	}, queue.WithInline())
+}
@@ -0,0 +1,602 @@
+package service
+
+import (
+	"iter"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/postcommit"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal"
+	"github.com/benpate/hannibal/sender"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/first"
+	"github.com/benpate/rosetta/html"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/rosetta/schema"
+	"github.com/benpate/sherlock"
+	"github.com/benpate/turbine/queue"
+	"github.com/benpate/uri"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// relayReceiveInterval is how often (in seconds) a Relay's ReceiveDate is written to the database,
+// so that a busy relay does not rewrite its own record for every post it forwards.
+const relayReceiveInterval = 60
+
+// Relay service subscribes the domain's search actor (@search) to ActivityPub relays, indexes the
+// public posts that they forward, and (optionally) sends our own public posts to them.
+type Relay struct {
+	activityService     *ActivityStream
+	newsFeedService     *NewsFeed
+	ruleService         *Rule
+	searchDomainService *SearchDomain
+	searchResultService *SearchResult
+	hostname            string
+	queue               *queue.Queue
+}
+
+// NewRelay returns a new instance of the Relay service
+func NewRelay() Relay {
+	return Relay{}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+func (service *Relay) Refresh(factory *Factory) {
+	service.activityService = factory.ActivityStream()
+	service.newsFeedService = factory.NewsFeed()
+	service.ruleService = factory.Rule()
+	service.searchDomainService = factory.SearchDomain()
+	service.searchResultService = factory.SearchResult()
+	service.hostname = factory.Hostname()
+	service.queue = factory.Queue()
+}
+
+/******************************************
+ * Common Methods
+ ******************************************/
+
+func (service *Relay) collection(session data.Session) data.Collection {
+	return session.Collection("Relay")
+}
+
+// New returns a new, empty Relay
+func (service *Relay) New() model.Relay {
+	return model.NewRelay()
+}
+
+// Count returns the number of records that match the provided criteria
+func (service *Relay) Count(session data.Session, criteria exp.Expression) (int64, error) {
+	return service.collection(session).Count(notDeleted(criteria))
+}
+
+// Query returns an slice containing all of the Relays that match the provided criteria
+func (service *Relay) Query(session data.Session, criteria exp.Expression, options ...option.Option) ([]model.Relay, error) {
+	result := make([]model.Relay, 0)
+	err := service.collection(session).Query(&result, notDeleted(criteria), options...)
+	return result, err
+}
+
+// List returns an iterator containing all of the Relays that match the provided criteria
+func (service *Relay) List(session data.Session, criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.collection(session).Iterator(notDeleted(criteria), options...)
+}
+
+// Range returns a Go RangeFunc that iterates over the Relays that match the provided criteria
+func (service *Relay) Range(session data.Session, criteria exp.Expression, options ...option.Option) (iter.Seq[model.Relay], error) {
+
+	it, err := service.List(session, criteria, options...)
+
+	if err != nil {
+		return nil, derp.Wrap(err, "service.Relay.Range", "Creating iterator", criteria)
+	}
+
+	return RangeFunc(it, model.NewRelay), nil
+}
+
+// Load retrieves a Relay from the database
+func (service *Relay) Load(session data.Session, criteria exp.Expression, relay *model.Relay) error {
+
+	if err := service.collection(session).Load(notDeleted(criteria), relay); err != nil {
+		return derp.Wrap(err, "service.Relay.Load", "Loading Relay", criteria)
+	}
+
+	return nil
+}
+
+// Save adds/updates a Relay in the database
+func (service *Relay) Save(session data.Session, relay *model.Relay, note string) error {
+
+	const location = "service.Relay.Save"
+
+	// Validate the value before saving
+	if _, err := service.Schema().Validate(relay); err != nil {
+		return derp.Wrap(err, location, "Validating Relay using RelaySchema", relay)
+	}
+
+	// Try to save the Relay to the database
+	if err := service.collection(session).Save(relay, note); err != nil {
+		return derp.Wrap(err, location, "Saving Relay", relay, note)
+	}
+
+	return nil
+}
+
+// Delete unsubscribes from a Relay and removes it from the database (virtual delete), along
+// with every SearchResult that it forwarded, and every hashtag newsItem that it delivered.
+func (service *Relay) Delete(session data.Session, relay *model.Relay, note string) error {
+
+	const location = "service.Relay.Delete"
+
+	// Tell the relay to stop forwarding posts to us
+	if !relay.IsRejected() && (relay.InboxURL != "") {
+		service.deliver(session, relay, mapof.Any{
+			vocab.AtContext:         vocab.ContextTypeActivityStreams,
+			vocab.PropertyID:        service.FollowID(relay) + "/undo",
+			vocab.PropertyType:      vocab.ActivityTypeUndo,
+			vocab.PropertyActor:     service.searchDomainService.ActivityPubURL(),
+			vocab.PropertyObject:    service.follow(relay),
+			vocab.PropertyPublished: hannibal.TimeFormat(time.Now()),
+		})
+	}
+
+	// Remove everything that this relay contributed to the search index
+	if err := service.searchResultService.DeleteByRelay(session, relay.RelayID); err != nil {
+		return derp.Wrap(err, location, "Deleting SearchResults forwarded by Relay", relay.RelayID)
+	}
+
+	// ...and to the hashtag feeds of every User
+	if err := service.newsFeedService.DeleteByRelay(session, relay.RelayID, "Unsubscribed from relay: "+relay.Label); err != nil {
+		return derp.Wrap(err, location, "Deleting newsItems forwarded by Relay", relay.RelayID)
+	}
+
+	// Delete this Relay
+	if err := service.collection(session).Delete(relay, note); err != nil {
+		return derp.Wrap(err, location, "Deleting Relay", relay, note)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Generic Data Methods
+ ******************************************/
+
+// ObjectType returns the type of object that this service manages
+func (service *Relay) ObjectType() string {
+	return "Relay"
+}
+
+// ObjectNew returns a fully initialized model.Relay as a data.Object.
+func (service *Relay) ObjectNew() data.Object {
+	result := model.NewRelay()
+	return &result
+}
+
+func (service *Relay) ObjectID(object data.Object) primitive.ObjectID {
+
+	if relay, ok := object.(*model.Relay); ok {
+		return relay.RelayID
+	}
+
+	return primitive.NilObjectID
+}
+
+func (service *Relay) ObjectQuery(session data.Session, result any, criteria exp.Expression, options ...option.Option) error {
+	return service.collection(session).Query(result, notDeleted(criteria), options...)
+}
+
+func (service *Relay) ObjectLoad(session data.Session, criteria exp.Expression) (data.Object, error) {
+	result := model.NewRelay()
+	err := service.Load(session, criteria, &result)
+	return &result, err
+}
+
+func (service *Relay) ObjectSave(session data.Session, object data.Object, note string) error {
+	if relay, ok := object.(*model.Relay); ok {
+		return service.Save(session, relay, note)
+	}
+	return derp.Internal("service.Relay.ObjectSave", "Invalid object type", object)
+}
+
+func (service *Relay) ObjectDelete(session data.Session, object data.Object, note string) error {
+	if relay, ok := object.(*model.Relay); ok {
+		return service.Delete(session, relay, note)
+	}
+	return derp.Internal("service.Relay.ObjectDelete", "Invalid object type", object)
+}
+
+func (service *Relay) ObjectUserCan(object data.Object, authorization model.Authorization, action string) error {
+	return derp.Unauthorized("service.Relay.ObjectUserCan", "Not Authorized")
+}
+
+func (service *Relay) Schema() schema.Schema {
+	return schema.New(model.RelaySchema())
+}
+
+/******************************************
+ * Common Queries
+ ******************************************/
+
+func (service *Relay) LoadByID(session data.Session, relayID primitive.ObjectID, result *model.Relay) error {
+	return service.Load(session, exp.Equal("_id", relayID), result)
+}
+
+// LoadByFollowID returns the Relay that was subscribed to with the provided Follow activity ID
+func (service *Relay) LoadByFollowID(session data.Session, followID string, result *model.Relay) error {
+
+	const location = "service.Relay.LoadByFollowID"
+
+	relayID, isRelayFollow := strings.CutPrefix(followID, service.followPrefix())
+
+	if !isRelayFollow {
+		return derp.NotFound(location, "Not a Relay subscription", followID)
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(relayID)
+
+	if err != nil {
+		return derp.NotFound(location, "Invalid RelayID", followID)
+	}
+
+	return service.LoadByID(session, objectID, result)
+}
+
+// LoadByActorURL returns the Relay whose actor matches the provided URL
+func (service *Relay) LoadByActorURL(session data.Session, actorURL string, result *model.Relay) error {
+
+	if actorURL == "" {
+		return derp.NotFound("service.Relay.LoadByActorURL", "ActorURL is required")
+	}
+
+	return service.Load(session, exp.Equal("actorUrl", actorURL), result)
+}
+
+// LoadActiveByHostname returns the active Relay that runs on the provided hostname
+func (service *Relay) LoadActiveByHostname(session data.Session, hostname string, result *model.Relay) error {
+
+	if hostname == "" {
+		return derp.NotFound("service.Relay.LoadActiveByHostname", "Hostname is required")
+	}
+
+	return service.Load(session, exp.Equal("hostname", hostname).AndEqual("stateId", model.RelayStateActive), result)
+}
+
+// RangePublishing returns every active Relay that re-publishes our public posts
+func (service *Relay) RangePublishing(session data.Session) (iter.Seq[model.Relay], error) {
+	criteria := exp.Equal("stateId", model.RelayStateActive).AndEqual("publish", true)
+	return service.Range(session, criteria)
+}
+
+/******************************************
+ * Subscription Methods
+ ******************************************/
+
+// Subscribe resolves the relay's address and sends it a Follow from the domain's search actor.
+// Addresses that load as an ActivityPub actor are LitePub relays, which are followed directly.
+// Anything else is treated as the inbox of a Mastodon-style relay.
+func (service *Relay) Subscribe(session data.Session, relay *model.Relay) error {
+
+	const location = "service.Relay.Subscribe"
+
+	// RULE: Never subscribe to ourselves
+	if uri.Hostname(relay.URL) == service.hostname {
+		return derp.Validation("Relay must be on another server")
+	}
+
+	actor, err := service.activityService.SearchDomainClient().Load(relay.URL, sherlock.AsActor())
+
+	if (err == nil) && (actor.Inbox().ID() != "") {
+		relay.Type = model.RelayTypeLitePub
+		relay.ActorURL = actor.ID()
+		relay.InboxURL = actor.Inbox().ID()
+		relay.Label = first.String(relay.Label, actor.Name())
+	} else {
+		relay.Type = model.RelayTypeMastodon
+		relay.ActorURL = ""
+		relay.InboxURL = relay.URL
+	}
+
+	relay.Hostname = uri.Hostname(relay.InboxURL)
+	relay.StateID = model.RelayStatePending
+	relay.LastError = ""
+
+	if err := service.Save(session, relay, "Subscribed"); err != nil {
+		return derp.Wrap(err, location, "Saving Relay", relay.RelayID)
+	}
+
+	service.deliver(session, relay, service.follow(relay))
+	return nil
+}
+
+// Accept marks a Relay as active once it accepts our Follow.  Mastodon-style relays are
+// identified by their actor for the first time here.
+func (service *Relay) Accept(session data.Session, relay *model.Relay, actorURL string) error {
+
+	const location = "service.Relay.Accept"
+
+	// RULE: Only the relay's own server can accept our subscription
+	if uri.Hostname(actorURL) != relay.Hostname {
+		return derp.Forbidden(location, "Accept must come from the relay's server", actorURL, relay.Hostname)
+	}
+
+	relay.ActorURL = first.String(relay.ActorURL, actorURL)
+	relay.StateID = model.RelayStateActive
+	relay.LastError = ""
+
+	if err := service.Save(session, relay, "Accepted"); err != nil {
+		return derp.Wrap(err, location, "Saving Relay", relay.RelayID)
+	}
+
+	return nil
+}
+
+// Reject marks a Relay as rejected once it declines our Follow
+func (service *Relay) Reject(session data.Session, relay *model.Relay, actorURL string) error {
+
+	const location = "service.Relay.Reject"
+
+	// RULE: Only the relay's own server can reject our subscription
+	if uri.Hostname(actorURL) != relay.Hostname {
+		return derp.Forbidden(location, "Reject must come from the relay's server", actorURL, relay.Hostname)
+	}
+
+	relay.StateID = model.RelayStateRejected
+	relay.LastError = "The relay declined this subscription"
+
+	if err := service.Save(session, relay, "Rejected"); err != nil {
+		return derp.Wrap(err, location, "Saving Relay", relay.RelayID)
+	}
+
+	return nil
+}
+
+// FollowID returns the ID of the Follow activity that subscribes to a Relay
+func (service *Relay) FollowID(relay *model.Relay) string {
+	return service.followPrefix() + relay.RelayID.Hex()
+}
+
+func (service *Relay) followPrefix() string {
+	return service.searchDomainService.ActivityPubURL() + "/pub/relays/"
+}
+
+// follow returns the Follow activity that subscribes to a Relay
+func (service *Relay) follow(relay *model.Relay) mapof.Any {
+	return mapof.Any{
+		vocab.AtContext:      vocab.ContextTypeActivityStreams,
+		vocab.PropertyID:     service.FollowID(relay),
+		vocab.PropertyType:   vocab.ActivityTypeFollow,
+		vocab.PropertyActor:  service.searchDomainService.ActivityPubURL(),
+		vocab.PropertyObject: relay.FollowObject(),
+	}
+}
+
+// deliver queues an activity from the search actor to the Relay's inbox (post-commit).  Relays
+// are addressed by inbox, not by actor, so this uses a single-recipient delivery.
+func (service *Relay) deliver(session data.Session, relay *model.Relay, activity mapof.Any) {
+
+	postcommit.Publish(session, service.queue, sender.OutboxSendToSingleRecipient, mapof.Any{
+		"actor":    service.searchDomainService.ActivityPubURL(),
+		"inbox":    relay.InboxURL,
+		"activity": activity,
+	})
+}
+
+/******************************************
+ * Ingest Methods
+ ******************************************/
+
+// Receive adds a public document that was forwarded by a Relay to the search index.  From there,
+// SendSearchResult delivers it to matching search queries and hashtag followers.  Documents that
+// are already indexed (from this relay, another relay, or this server) are left unchanged.
+func (service *Relay) Receive(session data.Session, relay *model.Relay, document streams.Document) error {
+
+	const location = "service.Relay.Receive"
+
+	// RULE: Only public posts are indexed
+	if !document.IsPublic() || !isRelayedType(document.Type()) {
+		return nil
+	}
+
+	// RULE: Our own posts are already indexed
+	if uri.Hostname(document.ID()) == service.hostname {
+		return nil
+	}
+
+	// RULE: Skip documents that are already indexed
+	existing := model.NewSearchResult()
+
+	if err := service.searchResultService.LoadByURL(session, document.ID(), &existing); err == nil {
+		return nil
+	} else if !derp.IsNotFound(err) {
+		return derp.Wrap(err, location, "Loading SearchResult", document.ID())
+	}
+
+	// RULE: Skip documents that are blocked or muted by server-wide rules
+	now := time.Now().Unix()
+	disposition, err := service.ruleService.Disposition(session, primitive.NilObjectID, document, now)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Checking rules", document.ID())
+	}
+
+	if disposition.IsFiltered() {
+		return nil
+	}
+
+	// Add the document to the search index
+	searchResult := service.SearchResult(relay, document)
+
+	if err := service.searchResultService.Save(session, &searchResult, "Forwarded by relay: "+relay.Label); err != nil {
+		return derp.Wrap(err, location, "Saving SearchResult", document.ID())
+	}
+
+	// Record the relay's activity, at most once per interval
+	if now-relay.ReceiveDate >= relayReceiveInterval {
+
+		relay.ReceiveDate = now
+
+		if err := service.Save(session, relay, "Received"); err != nil {
+			return derp.Wrap(err, location, "Saving Relay", relay.RelayID)
+		}
+	}
+
+	return nil
+}
+
+// ReceiveUpdate refreshes the search index entry for a document that was edited by its author.
+// Documents that are no longer public are removed, and documents that were never indexed are
+// handled like any other post that the Relay forwards.
+func (service *Relay) ReceiveUpdate(session data.Session, relay *model.Relay, document streams.Document) error {
+
+	const location = "service.Relay.ReceiveUpdate"
+
+	// RULE: Our own posts are indexed when they are saved
+	if uri.Hostname(document.ID()) == service.hostname {
+		return nil
+	}
+
+	// RULE: Posts that are no longer public are removed from the index
+	if !document.IsPublic() || !isRelayedType(document.Type()) {
+		return service.ReceiveDelete(session, document.ID())
+	}
+
+	// Find the existing SearchResult.  If there isn't one, then index it now.
+	searchResult := model.NewSearchResult()
+
+	if err := service.searchResultService.LoadByURL(session, document.ID(), &searchResult); err != nil {
+
+		if derp.IsNotFound(err) {
+			return service.Receive(session, relay, document)
+		}
+
+		return derp.Wrap(err, location, "Loading SearchResult", document.ID())
+	}
+
+	// Copy the edited values into the existing SearchResult, so that
+	// its ranking and its original Relay are unchanged
+	updated := service.SearchResult(relay, document)
+
+	searchResult.Type = updated.Type
+	searchResult.AttributedTo = updated.AttributedTo
+	searchResult.Name = updated.Name
+	searchResult.Summary = updated.Summary
+	searchResult.IconURL = updated.IconURL
+	searchResult.Date = updated.Date
+	searchResult.Tags = updated.Tags
+	searchResult.Text = updated.Text
+
+	if err := service.searchResultService.Save(session, &searchResult, "Updated by relay: "+relay.Label); err != nil {
+		return derp.Wrap(err, location, "Saving SearchResult", document.ID())
+	}
+
+	return nil
+}
+
+// ReceiveDelete removes a document that was deleted by its author from the search index,
+// along with every newsItem that a Relay delivered for it.
+func (service *Relay) ReceiveDelete(session data.Session, url string) error {
+
+	const location = "service.Relay.ReceiveDelete"
+
+	// RULE: Our own posts are removed when they are deleted
+	if uri.Hostname(url) == service.hostname {
+		return nil
+	}
+
+	if err := service.searchResultService.DeleteByURL(session, url); err != nil {
+		return derp.Wrap(err, location, "Deleting SearchResult", url)
+	}
+
+	if err := service.newsFeedService.DeleteRelayedByURL(session, url, "Deleted by author"); err != nil {
+		return derp.Wrap(err, location, "Deleting relayed newsItems", url)
+	}
+
+	return nil
+}
+
+// SearchResult returns the search index entry for a document that was forwarded by a Relay
+func (service *Relay) SearchResult(relay *model.Relay, document streams.Document) model.SearchResult {
+
+	result := model.NewSearchResult()
+
+	result.Type = document.Type()
+	result.URL = document.ID()
+	result.AttributedTo = document.AttributedTo().ID()
+	result.Name = first.String(document.Name(), plainText(document.Summary()), plainText(document.Content()))
+	result.Summary = plainText(first.String(document.Summary(), document.Content()))
+	result.IconURL = document.IconOrImage().URL()
+	result.Date = document.Published()
+	result.Tags = model.DocumentHashtags(document)
+	result.Text = strings.Join([]string{document.Name(), html.ToSearchText(document.Summary()), html.ToSearchText(document.Content())}, " ")
+	result.RelayID = relay.RelayID
+	result.Local = false
+
+	return result
+}
+
+/******************************************
+ * Publishing Methods
+ ******************************************/
+
+// Publish queues an "Announce" of a local SearchResult to every Relay that re-publishes our posts.
+// The Announce uses the same ID as the search actor's outbox, so that relays can fetch it.
+func (service *Relay) Publish(session data.Session, searchResult *model.SearchResult) error {
+
+	const location = "service.Relay.Publish"
+
+	// RULE: Only local posts are published (this also prevents loops between relays)
+	if !searchResult.Local || !isRelayedType(searchResult.Type) {
+		return nil
+	}
+
+	relays, err := service.RangePublishing(session)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Loading publishing Relays")
+	}
+
+	actorURL := service.searchDomainService.ActivityPubURL()
+
+	for relay := range relays {
+		service.deliver(session, &relay, mapof.Any{
+			vocab.AtContext:         vocab.ContextTypeActivityStreams,
+			vocab.PropertyID:        service.searchDomainService.ActivityPubOutboxURL() + "/" + searchResult.SearchResultID.Hex(),
+			vocab.PropertyType:      vocab.ActivityTypeAnnounce,
+			vocab.PropertyActor:     actorURL,
+			vocab.PropertyObject:    searchResult.URL,
+			vocab.PropertyPublished: hannibal.TimeFormat(time.Now()),
+			vocab.PropertyTo:        []string{vocab.NamespacePublic},
+			vocab.PropertyCC:        []string{service.searchDomainService.ActivityPubFollowersURL()},
+		})
+	}
+
+	return nil
+}
+
+// isRelayedType returns TRUE if documents of this type are exchanged with relays.  Relays carry
+// posts, so actors (like the Person records in the search index) and Tombstones are skipped.
+func isRelayedType(objectType string) bool {
+
+	switch objectType {
+
+	case "",
+		vocab.ActorTypeApplication,
+		vocab.ActorTypeGroup,
+		vocab.ActorTypeOrganization,
+		vocab.ActorTypePerson,
+		vocab.ActorTypeService,
+		vocab.ObjectTypeTombstone:
+		return false
+	}
+
+	return true
+}
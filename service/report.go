@@ -0,0 +1,411 @@
+package service
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/rosetta/first"
+	"github.com/benpate/rosetta/schema"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Report service manages moderation reports that are filed by local Users
+// and received from other servers as ActivityPub "Flag" activities.
+type Report struct {
+	outboxService       *Outbox
+	ruleService         *Rule
+	searchDomainService *SearchDomain
+	streamService       *Stream
+	userService         *User
+	host                string
+	hostname            string
+}
+
+// NewReport returns a new instance of the Report service
+func NewReport() Report {
+	return Report{}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+func (service *Report) Refresh(factory *Factory) {
+	service.outboxService = factory.Outbox()
+	service.ruleService = factory.Rule()
+	service.searchDomainService = factory.SearchDomain()
+	service.streamService = factory.Stream()
+	service.userService = factory.User()
+	service.host = factory.Host()
+	service.hostname = factory.Hostname()
+}
+
+/******************************************
+ * Common Methods
+ ******************************************/
+
+func (service *Report) collection(session data.Session) data.Collection {
+	return session.Collection("Report")
+}
+
+// New returns a new Report
+func (service *Report) New() model.Report {
+	return model.NewReport()
+}
+
+// Count returns the number of records that match the provided criteria
+func (service *Report) Count(session data.Session, criteria exp.Expression) (int64, error) {
+	return service.collection(session).Count(notDeleted(criteria))
+}
+
+// Query returns an slice containing all of the Reports that match the provided criteria
+func (service *Report) Query(session data.Session, criteria exp.Expression, options ...option.Option) ([]model.Report, error) {
+	result := make([]model.Report, 0)
+	err := service.collection(session).Query(&result, notDeleted(criteria), options...)
+	return result, err
+}
+
+// List returns an iterator containing all of the Reports that match the provided criteria
+func (service *Report) List(session data.Session, criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.collection(session).Iterator(notDeleted(criteria), options...)
+}
+
+// Load retrieves a Report from the database
+func (service *Report) Load(session data.Session, criteria exp.Expression, report *model.Report) error {
+
+	if err := service.collection(session).Load(notDeleted(criteria), report); err != nil {
+		return derp.Wrap(err, "service.Report.Load", "Loading Report", criteria)
+	}
+
+	return nil
+}
+
+// Save adds/updates a Report in the database.  Local Reports about remote actors
+// are forwarded to the remote server (once) if the reporter asked for it.
+func (service *Report) Save(session data.Session, report *model.Report, note string) error {
+
+	const location = "service.Report.Save"
+
+	// RULE: Comments are limited to the same length as Mastodon, so that remote reports always fit
+	report.Comment = truncateReportComment(report.Comment)
+
+	// RULE: Local reporters are identified by their canonical actor URL
+	if (report.ReporterURL == "") && !report.ReporterID.IsZero() {
+		report.ReporterURL = service.host + "/@" + report.ReporterID.Hex()
+	}
+
+	// RULE: Identify reports about local Users so that they are never sent off-server
+	if report.TargetUserID.IsZero() {
+		if userID, err := ParseProfileURL_UserID(service.hostname, report.TargetURL); err == nil {
+			report.TargetUserID = userID
+		}
+	}
+
+	// Validate the value (using the global report schema) before saving
+	if _, err := service.Schema().Validate(report); err != nil {
+		return derp.Wrap(err, location, "Validating Report using ReportSchema", report)
+	}
+
+	// Forward the Report to the reported actor's server (post-commit)
+	if report.ShouldForward() {
+		report.ActivityID = service.ActivityPubURL(report)
+		report.ForwardDate = time.Now().Unix()
+		service.outboxService.SendFlag(session, service.searchDomainService.ActivityPubURL(), report.ActivityID, report.TargetURL, report.ObjectURLs, report.Comment)
+	}
+
+	// Try to save the Report to the database
+	if err := service.collection(session).Save(report, note); err != nil {
+		return derp.Wrap(err, location, "Saving Report", report, note)
+	}
+
+	return nil
+}
+
+// Delete removes a Report from the database (virtual delete)
+func (service *Report) Delete(session data.Session, report *model.Report, note string) error {
+
+	if err := service.collection(session).Delete(report, note); err != nil {
+		return derp.Wrap(err, "service.Report.Delete", "Deleting Report", report, note)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Generic Data Methods
+ ******************************************/
+
+// ObjectType returns the type of object that this service manages
+func (service *Report) ObjectType() string {
+	return "Report"
+}
+
+// ObjectNew returns a fully initialized model.Report as a data.Object.
+func (service *Report) ObjectNew() data.Object {
+	result := model.NewReport()
+	return &result
+}
+
+func (service *Report) ObjectID(object data.Object) primitive.ObjectID {
+
+	if report, ok := object.(*model.Report); ok {
+		return report.ReportID
+	}
+
+	return primitive.NilObjectID
+}
+
+func (service *Report) ObjectQuery(session data.Session, result any, criteria exp.Expression, options ...option.Option) error {
+	return service.collection(session).Query(result, notDeleted(criteria), options...)
+}
+
+func (service *Report) ObjectLoad(session data.Session, criteria exp.Expression) (data.Object, error) {
+	result := model.NewReport()
+	err := service.Load(session, criteria, &result)
+	return &result, err
+}
+
+func (service *Report) ObjectSave(session data.Session, object data.Object, note string) error {
+	if report, ok := object.(*model.Report); ok {
+		return service.Save(session, report, note)
+	}
+	return derp.Internal("service.Report.ObjectSave", "Invalid object type", object)
+}
+
+func (service *Report) ObjectDelete(session data.Session, object data.Object, note string) error {
+	if report, ok := object.(*model.Report); ok {
+		return service.Delete(session, report, note)
+	}
+	return derp.Internal("service.Report.ObjectDelete", "Invalid object type", object)
+}
+
+func (service *Report) ObjectUserCan(object data.Object, authorization model.Authorization, action string) error {
+	return derp.Unauthorized("service.Report.ObjectUserCan", "Not Authorized")
+}
+
+func (service *Report) Schema() schema.Schema {
+	return schema.New(model.ReportSchema())
+}
+
+/******************************************
+ * Common Queries
+ ******************************************/
+
+func (service *Report) LoadByID(session data.Session, reportID primitive.ObjectID, result *model.Report) error {
+	return service.Load(session, exp.Equal("_id", reportID), result)
+}
+
+func (service *Report) LoadByActivityID(session data.Session, activityID string, result *model.Report) error {
+	return service.Load(session, exp.Equal("activityId", activityID), result)
+}
+
+// CountOpen returns the number of Reports that are waiting for a moderator
+func (service *Report) CountOpen(session data.Session) (int64, error) {
+	return service.Count(session, exp.Equal("status", model.ReportStatusOpen))
+}
+
+// ActivityPubURL returns the ID of the "Flag" activity sent when a Report is forwarded.
+// Flags are not published anywhere, so this only needs to be unique on this server.
+func (service *Report) ActivityPubURL(report *model.Report) string {
+	return service.searchDomainService.ActivityPubURL() + "#report-" + report.ReportID.Hex()
+}
+
+/******************************************
+ * ActivityPub Methods
+ ******************************************/
+
+// ReceiveFlag saves a "Flag" activity that another server sent about one of our Users (or their content).
+// userID is the User whose inbox received the Flag, or zero if it was sent to the domain inbox.
+// Duplicate deliveries of the same Flag are ignored.
+func (service *Report) ReceiveFlag(session data.Session, activity streams.Document, userID primitive.ObjectID) error {
+
+	const location = "service.Report.ReceiveFlag"
+
+	// RULE: Flags must have an ID so that retries can be de-duplicated
+	if activity.ID() == "" {
+		return derp.BadRequest(location, "Flag must have an ID", activity.Value())
+	}
+
+	// RULE: Flags must have an Actor
+	if activity.ActorID() == "" {
+		return derp.BadRequest(location, "Flag must have an Actor", activity.Value())
+	}
+
+	// If we've already received this Flag, then there's nothing more to do.
+	existing := model.NewReport()
+
+	if err := service.LoadByActivityID(session, activity.ID(), &existing); err == nil {
+		return nil
+	} else if !derp.IsNotFound(err) {
+		return derp.Wrap(err, location, "Searching for existing Report", activity.ID())
+	}
+
+	// Build a new Report from the Flag
+	report := model.NewReport()
+	report.Origin = model.ReportOriginRemote
+	report.ReporterURL = activity.ActorID()
+	report.ActivityID = activity.ID()
+	report.Comment = activity.Content()
+	report.Category = model.ReportCategoryOther
+
+	objectURLs := slices.Collect(activity.Object().RangeIDs())
+
+	// Find the local User being reported
+	target := model.NewUser()
+
+	if err := service.findTarget(session, userID, objectURLs, &target); err != nil {
+		return derp.Wrap(err, location, "Flag does not reference a local User", activity.ID())
+	}
+
+	report.TargetUserID = target.UserID
+	report.TargetURL = target.ActivityPubURL()
+
+	// Every other object is a document that is being reported
+	for _, objectURL := range objectURLs {
+		if objectURL != report.TargetURL {
+			report.ObjectURLs = append(report.ObjectURLs, objectURL)
+		}
+	}
+
+	if err := service.Save(session, &report, "Received Flag"); err != nil {
+		return derp.Wrap(err, location, "Saving Report", activity.ID())
+	}
+
+	return nil
+}
+
+// findTarget locates the local User that a Flag is about.  Flags delivered to a User's inbox are
+// about that User.  Flags delivered to the domain inbox must name a local User, or one of their Streams.
+func (service *Report) findTarget(session data.Session, userID primitive.ObjectID, objectURLs []string, result *model.User) error {
+
+	const location = "service.Report.findTarget"
+
+	if !userID.IsZero() {
+		return service.userService.LoadByID(session, userID, result)
+	}
+
+	for _, objectURL := range objectURLs {
+
+		// Look for local actor URLs
+		if objectUserID, err := ParseProfileURL_UserID(service.hostname, objectURL); err == nil {
+			if err := service.userService.LoadByID(session, objectUserID, result); err == nil {
+				return nil
+			}
+			continue
+		}
+
+		// Look for local Streams (and report their author)
+		stream := model.NewStream()
+		if err := service.streamService.LoadByURL(session, objectURL, &stream); err == nil {
+			if err := service.userService.LoadByID(session, stream.AttributedTo.UserID, result); err == nil {
+				return nil
+			}
+		}
+	}
+
+	return derp.NotFound(location, "No local User found", objectURLs)
+}
+
+/******************************************
+ * Moderation Methods
+ ******************************************/
+
+// Resolve closes an open Report.  Reports that are RESOLVED with a blocking Resolution
+// also create a server-wide Rule that blocks the reported actor (or their domain).
+func (service *Report) Resolve(session data.Session, report *model.Report, status string, resolvedBy primitive.ObjectID) error {
+
+	const location = "service.Report.Resolve"
+
+	// RULE: Only open Reports can be resolved
+	if !report.IsOpen() {
+		return derp.Validation("Report has already been closed", report.ReportID, report.Status)
+	}
+
+	switch status {
+
+	case model.ReportStatusResolved:
+
+		if err := service.applyResolution(session, report); err != nil {
+			return derp.Wrap(err, location, "Applying resolution", report.ReportID, report.Resolution)
+		}
+
+	case model.ReportStatusDismissed:
+		report.Resolution = model.ReportResolutionNone
+
+	default:
+		return derp.Internal(location, "Invalid status", status)
+	}
+
+	report.Status = status
+	report.ResolvedBy = resolvedBy
+	report.ResolveDate = time.Now().Unix()
+
+	if err := service.Save(session, report, "Closed as "+status); err != nil {
+		return derp.Wrap(err, location, "Saving Report", report.ReportID)
+	}
+
+	return nil
+}
+
+// applyResolution creates the server-wide Rule (if any) that a moderator chose when resolving a Report
+func (service *Report) applyResolution(session data.Session, report *model.Report) error {
+
+	const location = "service.Report.applyResolution"
+
+	rule := model.NewRule()
+	rule.UserID = primitive.NilObjectID
+	rule.Action = model.RuleActionBlock
+	rule.Summary = first.String(report.ResolutionNote, report.Comment)
+
+	switch report.Resolution {
+
+	case "", model.ReportResolutionNone:
+		return nil
+
+	case model.ReportResolutionBlockActor:
+		rule.Type = model.RuleTypeActor
+		rule.Trigger = report.TargetURL
+
+	case model.ReportResolutionBlockDomain:
+		rule.Type = model.RuleTypeDomain
+		rule.Trigger = report.TargetHostname()
+
+	default:
+		return derp.Validation("Invalid resolution", report.Resolution)
+	}
+
+	// RULE: Local Users are moderated directly, not with server-wide Rules
+	if report.IsTargetLocal() {
+		return derp.Validation("Cannot create a blocking Rule for a local User", report.TargetURL)
+	}
+
+	if rule.Trigger == "" {
+		return derp.Validation("Reported actor is missing", report.ReportID)
+	}
+
+	if err := service.ruleService.Save(session, &rule, "Created from Report "+report.ReportID.Hex()); err != nil {
+		return derp.Wrap(err, location, "Saving Rule", rule)
+	}
+
+	report.RuleID = rule.RuleID
+	return nil
+}
+
+// truncateReportComment limits a Report comment to model.ReportCommentMaxLength characters
+func truncateReportComment(comment string) string {
+
+	comment = strings.TrimSpace(comment)
+
+	if runes := []rune(comment); len(runes) > model.ReportCommentMaxLength {
+		return string(runes[:model.ReportCommentMaxLength])
+	}
+
+	return comment
+}
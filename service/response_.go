@@ -29,6 +29,7 @@ type Response struct {
 	outboxService         *Outbox
 	ruleService           *Rule
 	userService           *User
+	webhookService        *Webhook
 	host                  string
 
 	// loadDocument resolves a URL to an ActivityStream Document via the App client (which caches).
@@ -54,6 +55,7 @@ func (service *Response) Refresh(factory *Factory) {
 	service.outboxService = factory.Outbox()
 	service.ruleService = factory.Rule()
 	service.userService = factory.User()
+	service.webhookService = factory.Webhook()
 	service.host = factory.Host()
 
 	// Resolve reaction targets through the App client, which caches the loaded document so the
@@ -125,6 +127,8 @@ func (service *Response) Save(session data.Session, response *model.Response, no
 		return derp.Wrap(err, location, "Validating Response", response)
 	}
 
+	isNew := response.IsNew()
+
 	// Save the value to the database
 	if err := service.collection(session).Save(response, note); err != nil {
 		return derp.Wrap(err, location, "Saving Response", response, note)
@@ -139,6 +143,11 @@ func (service *Response) Save(session data.Session, response *model.Response, no
 	// solely by the inbound funnel: SetResponse publishes the reaction to the author, and the
 	// resulting inbox delivery (including the self-loopback) projects it. See COLLECTIONS-REDESIGN.md D6.
 
+	// Send response:create Webhooks
+	if isNew {
+		service.webhookService.Send(session, response, model.WebhookEventResponseCreate)
+	}
+
 	return nil
 }
 
@@ -180,6 +189,9 @@ func (service *Response) Delete(session data.Session, response *model.Response,
 		derp.Report(derp.Wrap(err, location, "Sending Undo activity"))
 	}
 
+	// Send response:delete Webhooks
+	service.webhookService.Send(session, response, model.WebhookEventResponseDelete)
+
 	return nil
 }
 
@@ -28,10 +28,13 @@ type actorLoader interface {
 // Rule defines a service that manages all content rules created and imported by Users.
 type Rule struct {
 	activityStreamService  actorLoader
+	filterService          *Filter
 	importItemService      *ImportItem
 	outboxService          *Outbox
 	ruleSuppressionService *RuleSuppression
 	userService            *User
+	webhookService         *Webhook
+	contentCache           *ruleContentCache
 	host                   string
 	newSession             func(timeout time.Duration) (data.Session, context.CancelFunc, error)
 
@@ -40,7 +43,9 @@ type Rule struct {
 
 // NewRule returns a fully initialized Rule service
 func NewRule() Rule {
-	return Rule{}
+	return Rule{
+		contentCache: newRuleContentCache(),
+	}
 }
 
 /******************************************
@@ -50,10 +55,12 @@ func NewRule() Rule {
 // Refresh updates any stateful data that is cached inside this service.
 func (service *Rule) Refresh(factory *Factory) {
 	service.activityStreamService = factory.ActivityStream()
+	service.filterService = factory.Filter()
 	service.importItemService = factory.ImportItem()
 	service.outboxService = factory.Outbox()
 	service.ruleSuppressionService = factory.RuleSuppression()
 	service.userService = factory.User()
+	service.webhookService = factory.Webhook()
 	service.queue = factory.Queue()
 	service.host = factory.Host()
 	service.newSession = factory.Session
@@ -232,9 +239,16 @@ func (service *Rule) Save(session data.Session, rule *model.Rule, note string) e
 		return derp.Wrap(err, location, "Calculating rule count")
 	}
 
+	// Recompile CONTENT Rules on their next use
+	service.invalidateContentRules(rule.UserID, oldMatchKey, rule.MatchKey)
+
 	// Enqueue the retroactive cleanup task for action/trigger transitions (R8, post-commit)
 	service.enqueueCleanup(session, *rule, oldAction, oldMatchKey, rule.Action)
 
+	// Send rule:create and rule:update Webhooks
+	eventName := iif(previous.RuleID.IsZero(), model.WebhookEventRuleCreate, model.WebhookEventRuleUpdate)
+	service.webhookService.Send(session, rule, eventName)
+
 	return nil
 }
 
@@ -247,6 +261,11 @@ func (service *Rule) Save(session data.Session, rule *model.Rule, note string) e
 // persisting a rule whose MatchKey can never match.
 func (service *Rule) resolveMatchKeyTrigger(rule *model.Rule) (string, error) {
 
+	// RULE: CONTENT triggers must compile, or the Rule would silently never match.
+	if rule.Type == model.RuleTypeContent {
+		return rule.Trigger, model.ValidateContentTrigger(rule.Trigger)
+	}
+
 	// RULE: Only ACTOR triggers name an actor; DOMAIN (host) and TAG (token) resolve differently.
 	if rule.Type != model.RuleTypeActor {
 		return rule.Trigger, nil
@@ -323,9 +342,15 @@ func (service *Rule) Delete(session data.Session, rule *model.Rule, note string)
 		return derp.Wrap(err, location, "Calculating rule count")
 	}
 
+	// Recompile CONTENT Rules on their next use
+	service.invalidateContentRules(rule.UserID, rule.MatchKey)
+
 	// Enqueue the retroactive cleanup task -- deleting a BLOCK restores paused relationships (R8)
 	service.enqueueCleanup(session, *rule, rule.Action, rule.MatchKey, "")
 
+	// Send rule:delete Webhooks
+	service.webhookService.Send(session, rule, model.WebhookEventRuleDelete)
+
 	// The Rule is gone, and so is its shadow on the wire.
 	return nil
 }
@@ -575,9 +600,21 @@ func (service *Rule) QueryByMatchKeys(session data.Session, userID primitive.Obj
 // returns the resulting RuleDisposition. `now` is the current Unix time in seconds.
 func (service *Rule) Disposition(session data.Session, userID primitive.ObjectID, document streams.Document, now int64) (model.RuleDisposition, error) {
 
+	const location = "service.Rule.Disposition"
+
 	// One matcher, one place (D17): Stage 1, Stage 2, and the future queue worker all adapt over this.
 	// DocumentMatchKeys covers the actor AND the document's content tags.
-	return service.DispositionForKeys(session, userID, model.DocumentMatchKeys(document), now)
+	keys := model.DocumentMatchKeys(document)
+
+	// CONTENT Rules cannot be found by an indexed key lookup, so the precompiled matcher contributes
+	// the keys of whichever ones match the document's text. The engine then ranks them like any other.
+	contentKeys, err := service.ContentMatchKeys(session, userID, document)
+
+	if err != nil {
+		return model.RuleDisposition{}, derp.Wrap(err, location, "Matching content rules", userID)
+	}
+
+	return service.DispositionForKeys(session, userID, append(keys, contentKeys...), now)
 }
 
 // DispositionForKeys evaluates a pre-computed match-key set against this User's Rules (plus
@@ -714,6 +751,12 @@ func (service *Rule) shouldPublish(rule model.Rule) bool {
 		return false
 	}
 
+	// RULE: CONTENT Rules do not federate. There is no wire grammar for a word list (and a
+	// published regular expression would only teach spammers how to avoid it).
+	if rule.Type == model.RuleTypeContent {
+		return false
+	}
+
 	return true
 }
 
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/streams"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ruleContentCacheTTL is how long a compiled ContentMatcher is trusted. Saves and deletes on THIS
+// server invalidate it immediately; the TTL only bounds how long another node in a cluster can keep
+// matching against a rule set that has since changed.
+const ruleContentCacheTTL = 60 * time.Second
+
+// ruleContentCache holds each User's precompiled CONTENT Rules (their own plus the domain-wide
+// admin rules), so that inbox processing compiles a pattern once instead of once per document.
+type ruleContentCache struct {
+	mutex   sync.RWMutex
+	entries map[primitive.ObjectID]ruleContentCacheEntry
+}
+
+// ruleContentCacheEntry is a single User's compiled ContentMatcher, and when it stops being trusted
+type ruleContentCacheEntry struct {
+	matcher model.ContentMatcher
+	expires time.Time
+}
+
+// newRuleContentCache returns a fully initialized, empty ruleContentCache
+func newRuleContentCache() *ruleContentCache {
+	return &ruleContentCache{
+		entries: make(map[primitive.ObjectID]ruleContentCacheEntry),
+	}
+}
+
+// get returns the cached ContentMatcher for a User, if one exists and has not expired.
+// A nil cache never has anything in it.
+func (cache *ruleContentCache) get(userID primitive.ObjectID) (model.ContentMatcher, bool) {
+
+	if cache == nil {
+		return model.ContentMatcher{}, false
+	}
+
+	cache.mutex.RLock()
+	defer cache.mutex.RUnlock()
+
+	entry, exists := cache.entries[userID]
+
+	if !exists || time.Now().After(entry.expires) {
+		return model.ContentMatcher{}, false
+	}
+
+	return entry.matcher, true
+}
+
+// set stores the compiled ContentMatcher for a User
+func (cache *ruleContentCache) set(userID primitive.ObjectID, matcher model.ContentMatcher) {
+
+	if cache == nil {
+		return
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.entries[userID] = ruleContentCacheEntry{
+		matcher: matcher,
+		expires: time.Now().Add(ruleContentCacheTTL),
+	}
+}
+
+// invalidate removes a User's compiled ContentMatcher. Domain-wide (admin) rules are compiled
+// into EVERY User's matcher, so invalidating the zero UserID empties the whole cache.
+func (cache *ruleContentCache) invalidate(userID primitive.ObjectID) {
+
+	if cache == nil {
+		return
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if userID.IsZero() {
+		clear(cache.entries)
+		return
+	}
+
+	delete(cache.entries, userID)
+}
+
+/******************************************
+ * CONTENT Rule Methods
+ ******************************************/
+
+// ContentMatchKeys returns the MatchKeys of every CONTENT Rule (the User's own, plus domain-wide
+// admin rules) that matches the provided document's text. Passing NilObjectID as userID evaluates
+// admin-tier rules alone.
+func (service *Rule) ContentMatchKeys(session data.Session, userID primitive.ObjectID, document streams.Document) ([]string, error) {
+
+	const location = "service.Rule.ContentMatchKeys"
+
+	matcher, err := service.contentMatcher(session, userID)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Loading content rules", userID)
+	}
+
+	return matcher.MatchKeys(document), nil
+}
+
+// contentMatcher returns the compiled CONTENT Rules for a User, from the cache when possible.
+func (service *Rule) contentMatcher(session data.Session, userID primitive.ObjectID) (model.ContentMatcher, error) {
+
+	const location = "service.Rule.contentMatcher"
+
+	if matcher, exists := service.contentCache.get(userID); exists {
+		return matcher, nil
+	}
+
+	criteria := service.byUserID(userID).And(exp.Equal("type", model.RuleTypeContent))
+	rules, err := service.QuerySummary(session, criteria)
+
+	if err != nil {
+		return model.ContentMatcher{}, derp.Wrap(err, location, "Querying content rules", userID)
+	}
+
+	// Expired rules are compiled anyway; the disposition engine skips them by date.
+	matcher := model.NewContentMatcher(rules)
+	service.contentCache.set(userID, matcher)
+
+	return matcher, nil
+}
+
+// invalidateContentRules drops a User's compiled CONTENT Rules if any of the provided MatchKeys
+// (typically a Rule's key before and after a save) belongs to a CONTENT Rule.
+func (service *Rule) invalidateContentRules(userID primitive.ObjectID, matchKeys ...string) {
+
+	for _, matchKey := range matchKeys {
+		if strings.HasPrefix(matchKey, model.RuleTypeContent+":") {
+			service.contentCache.invalidate(userID)
+			return
+		}
+	}
+}
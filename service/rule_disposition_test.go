@@ -27,7 +27,8 @@ const dispositionNow = int64(1_000_000)
 
 /******************************************
  * ruleStore -- an in-memory data.Collection that matches RuleSummaries on the fields
- * QueryByMatchKeys uses: userId (IN), matchKey (IN), and the notDeleted() deleteDate guard.
+ * QueryByMatchKeys uses: userId (IN), matchKey (IN), and the notDeleted() deleteDate guard,
+ * plus the type (EQUAL) that CONTENT rules are loaded by.
  ******************************************/
 
 type ruleStore struct {
@@ -69,8 +70,8 @@ func (c *ruleStore) Save(data.Object, string) error   { return derp.NotFound("te
 func (c *ruleStore) Delete(data.Object, string) error { return derp.NotFound("test", "unused") }
 func (c *ruleStore) HardDelete(exp.Expression) error  { return derp.NotFound("test", "unused") }
 
-// matchesRule reports whether a RuleSummary satisfies the IN criteria on userId/matchKey plus the
-// notDeleted() deleteDate==0 guard. Any unsupported field or operator conservatively counts as "no".
+// matchesRule reports whether a RuleSummary satisfies the IN criteria on userId/matchKey, the type
+// equality, and the notDeleted() deleteDate==0 guard. Any unsupported field or operator conservatively counts as "no".
 func matchesRule(criteria exp.Expression, record model.RuleSummary) bool {
 
 	return criteria.Match(func(predicate exp.Predicate) bool {
@@ -85,6 +86,10 @@ func matchesRule(criteria exp.Expression, record model.RuleSummary) bool {
 			values, ok := predicate.Value.([]string)
 			return ok && (predicate.Operator == exp.OperatorIn) && slices.Contains(values, record.MatchKey)
 
+		case "type":
+			value, ok := predicate.Value.(string)
+			return ok && (predicate.Operator == exp.OperatorEqual) && (value == record.Type)
+
 		case "deleteDate":
 			// All test records are live; the notDeleted() guard always passes.
 			return predicate.Operator == exp.OperatorEqual
@@ -266,3 +271,44 @@ func TestRule_Disposition_TagOnDocument(t *testing.T) {
 	require.Nil(t, err)
 	require.True(t, disposition.IsMuted())
 }
+
+// The document path also matches a CONTENT rule against the document's text -- a keyword that is
+// neither an actor nor a hashtag, so no indexed key lookup alone could ever find it.
+func TestRule_Disposition_ContentOnDocument(t *testing.T) {
+
+	userID := primitive.NewObjectID()
+	adminBlock := summaryRule(primitive.NilObjectID, model.RuleTypeContent, model.RuleActionBlock, "/buy(ing)? followers/")
+	userMute := summaryRule(userID, model.RuleTypeContent, model.RuleActionMute, "crypto giveaway")
+	store := &ruleStore{records: []model.RuleSummary{adminBlock, userMute}}
+
+	service, session := newRuleService(store)
+
+	muted := streams.NewDocument(mapof.Any{
+		vocab.PropertyActor:   "https://good.example/@friend",
+		vocab.PropertyContent: "<p>Huge <b>Crypto Giveaway</b> today!</p>",
+	})
+
+	disposition, err := service.Disposition(session, userID, muted, dispositionNow)
+	require.Nil(t, err)
+	require.True(t, disposition.IsMuted())
+	require.Equal(t, userMute.RuleID, disposition.RuleID)
+
+	blocked := streams.NewDocument(mapof.Any{
+		vocab.PropertyActor:   "https://good.example/@friend",
+		vocab.PropertySummary: "Buying Followers",
+	})
+
+	disposition, err = service.Disposition(session, userID, blocked, dispositionNow)
+	require.Nil(t, err)
+	require.True(t, disposition.IsBlocked())
+	require.Equal(t, model.RuleOriginAdmin, disposition.Tier)
+
+	clean := streams.NewDocument(mapof.Any{
+		vocab.PropertyActor:   "https://good.example/@friend",
+		vocab.PropertyContent: "<p>Cryptography is neat</p>",
+	})
+
+	disposition, err = service.Disposition(session, userID, clean, dispositionNow)
+	require.Nil(t, err)
+	require.False(t, disposition.IsFiltered())
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/hannibal/streams"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FilterDocuments stamps the viewer's keyword and status Filters for the provided context
+// (home, thread, public, account) into each document's Metadata.Labels, alongside the rule
+// verdict written by LabelDocuments.  Filters are loaded once for the whole slice, and their
+// keywords are matched against the same text, by the same engine, as CONTENT Rules.
+func (service *Rule) FilterDocuments(session data.Session, userID primitive.ObjectID, filterContext string, documents []streams.Document) {
+
+	filters := service.activeFilters(session, userID, filterContext)
+
+	if len(filters) == 0 {
+		return
+	}
+
+	for index := range documents {
+		matches := model.MatchFilters(filters, model.ContentText(documents[index]), documents[index].ID())
+		documents[index].Metadata.Labels = matches.AppendLabels(documents[index].Metadata.Labels)
+	}
+}
+
+// FilterNotifications stamps the viewer's "notifications" Filters into each Notification's
+// transient Labels field.  Notifications only carry a plain-text snapshot of the object they
+// refer to, so keywords are matched against that snapshot.
+func (service *Rule) FilterNotifications(session data.Session, userID primitive.ObjectID, notifications []model.Notification) {
+
+	filters := service.activeFilters(session, userID, model.FilterContextNotifications)
+
+	if len(filters) == 0 {
+		return
+	}
+
+	for index := range notifications {
+		text := model.FilterTextFromStrings(notifications[index].ObjectSummary)
+		matches := model.MatchFilters(filters, text, notifications[index].ObjectURL)
+		notifications[index].Labels = matches.AppendLabels(notifications[index].Labels)
+	}
+}
+
+// FilterSearchResults stamps the viewer's "public" Filters into each SearchResult's transient
+// Labels field.  Search pages are this server's public timelines.
+func (service *Rule) FilterSearchResults(session data.Session, userID primitive.ObjectID, results []model.SearchResult) {
+
+	filters := service.activeFilters(session, userID, model.FilterContextPublic)
+
+	if len(filters) == 0 {
+		return
+	}
+
+	for index := range results {
+		text := model.FilterTextFromStrings(results[index].Name, results[index].Summary, results[index].Text)
+		matches := model.MatchFilters(filters, text, results[index].URL)
+		results[index].Labels = matches.AppendLabels(results[index].Labels)
+	}
+}
+
+// activeFilters returns the viewer's unexpired Filters for the provided context.  Anonymous
+// viewers have no Filters.
+func (service *Rule) activeFilters(session data.Session, userID primitive.ObjectID, filterContext string) []model.Filter {
+
+	const location = "service.Rule.activeFilters"
+
+	if userID.IsZero() {
+		return nil
+	}
+
+	filters, err := service.filterService.QueryActive(session, userID, filterContext, time.Now().Unix())
+
+	// RULE: display fails OPEN (same posture as LabelDocuments)
+	if err != nil {
+		derp.Report(derp.Wrap(err, location, "Querying filters for labels; serving unfiltered", userID, filterContext))
+		return nil
+	}
+
+	return filters
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/hannibal/streams"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TestRule_FilterDocuments pins the Filter stamp: "hide" Filters hide a document like a MUTE
+// Rule, "warn" Filters annotate it with their title, and Filters scoped to another context
+// (or owned by another User) are ignored.
+func TestRule_FilterDocuments(t *testing.T) {
+
+	userID := primitive.NewObjectID()
+
+	hide := testFilter(userID, model.FilterActionHide, "Spoilers", "spoiler", model.FilterContextThread)
+	warn := testFilter(userID, model.FilterActionWarn, "Politics", "election", model.FilterContextThread)
+	homeOnly := testFilter(userID, model.FilterActionHide, "Cats", "cat", model.FilterContextHome)
+	otherUser := testFilter(primitive.NewObjectID(), model.FilterActionHide, "Dogs", "dog", model.FilterContextThread)
+
+	service := NewRule()
+	service.filterService = &Filter{}
+	session := filterSession{store: &filterStore{records: []model.Filter{hide, warn, homeOnly, otherUser}}}
+
+	documents := []streams.Document{
+		streams.NewDocument(map[string]any{"id": "https://example.com/notes/1", "content": "<p>Major SPOILER ahead</p>"}),
+		streams.NewDocument(map[string]any{"id": "https://example.com/notes/2", "content": "Election day"}),
+		streams.NewDocument(map[string]any{"id": "https://example.com/notes/3", "content": "My cat and my dog"}),
+	}
+
+	service.FilterDocuments(session, userID, model.FilterContextThread, documents)
+
+	// The "hide" Filter hides its document
+	require.True(t, documents[0].Metadata.Labels.IsHidden())
+	require.Equal(t, "Filtered: Spoilers", documents[0].Metadata.Labels.Reason())
+
+	// The "warn" Filter annotates its document without hiding it
+	require.False(t, documents[1].Metadata.Labels.IsHidden())
+	require.Equal(t, "Politics", documents[1].Metadata.Labels.Annotations()[0].Value)
+
+	// Filters for other contexts and other Users do not apply
+	require.Empty(t, documents[2].Metadata.Labels)
+}
+
+// TestRule_FilterDocuments_Anonymous pins that anonymous viewers are never filtered
+func TestRule_FilterDocuments_Anonymous(t *testing.T) {
+
+	hide := testFilter(primitive.NilObjectID, model.FilterActionHide, "Spoilers", "spoiler", model.FilterContextHome)
+
+	service := NewRule()
+	service.filterService = &Filter{}
+	session := filterSession{store: &filterStore{records: []model.Filter{hide}}}
+
+	documents := []streams.Document{
+		streams.NewDocument(map[string]any{"id": "https://example.com/notes/1", "content": "spoiler"}),
+	}
+
+	service.FilterDocuments(session, primitive.NilObjectID, model.FilterContextHome, documents)
+	require.Empty(t, documents[0].Metadata.Labels)
+}
+
+func testFilter(userID primitive.ObjectID, action string, title string, keyword string, filterContext string) model.Filter {
+	filter := model.NewFilter()
+	filter.UserID = userID
+	filter.Action = action
+	filter.Title = title
+	filter.Contexts = append(filter.Contexts, filterContext)
+	filter.Keywords = append(filter.Keywords, model.FilterKeyword{Keyword: keyword, WholeWord: true})
+	return filter
+}
+
+/******************************************
+ * filterStore -- an in-memory data.Collection that matches Filters on the fields QueryActive
+ * uses: userId, contexts, expireDate, and the notDeleted() deleteDate guard.
+ ******************************************/
+
+type filterStore struct {
+	records []model.Filter
+}
+
+func (c *filterStore) Context() context.Context { return context.Background() }
+
+func (c *filterStore) Query(target any, criteria exp.Expression, _ ...option.Option) error {
+
+	result, ok := target.(*[]model.Filter)
+
+	if !ok {
+		return derp.Internal("test", "unexpected target type")
+	}
+
+	for _, record := range c.records {
+		if matchesFilter(criteria, record) {
+			*result = append(*result, record)
+		}
+	}
+
+	return nil
+}
+
+func (c *filterStore) Count(exp.Expression, ...option.Option) (int64, error) {
+	return 0, derp.NotFound("test", "unused")
+}
+
+func (c *filterStore) Iterator(exp.Expression, ...option.Option) (data.Iterator, error) {
+	return nil, derp.NotFound("test", "unused")
+}
+
+func (c *filterStore) Load(exp.Expression, data.Object, ...option.Option) error {
+	return derp.NotFound("test", "unused")
+}
+
+func (c *filterStore) Save(data.Object, string) error   { return derp.NotFound("test", "unused") }
+func (c *filterStore) Delete(data.Object, string) error { return derp.NotFound("test", "unused") }
+func (c *filterStore) HardDelete(exp.Expression) error  { return derp.NotFound("test", "unused") }
+
+func matchesFilter(criteria exp.Expression, record model.Filter) bool {
+
+	return criteria.Match(func(predicate exp.Predicate) bool {
+
+		switch predicate.Field {
+
+		case "userId":
+			value, ok := predicate.Value.(primitive.ObjectID)
+			return ok && (predicate.Operator == exp.OperatorEqual) && (value == record.UserID)
+
+		case "contexts":
+			value, ok := predicate.Value.(string)
+			return ok && (predicate.Operator == exp.OperatorEqual) && slices.Contains(record.Contexts, value)
+
+		case "expireDate":
+			// All test records never expire
+			return (predicate.Operator == exp.OperatorEqual) && (predicate.Value == 0)
+
+		case "deleteDate":
+			// All test records are live; the notDeleted() guard always passes.
+			return predicate.Operator == exp.OperatorEqual
+
+		default:
+			return false
+		}
+	})
+}
+
+type filterSession struct {
+	store data.Collection
+}
+
+func (s filterSession) Collection(string) data.Collection { return s.store }
+func (s filterSession) Context() context.Context          { return context.Background() }
+func (s filterSession) Close()                            {}
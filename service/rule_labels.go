@@ -19,6 +19,14 @@ func (service *Rule) LabelDocuments(session data.Session, userID primitive.Objec
 
 	now := time.Now().Unix()
 
+	// Compile the viewer's CONTENT Rules once for every chunk.
+	// RULE: display fails OPEN (same posture as the rules query below)
+	contentMatcher, err := service.contentMatcher(session, userID)
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, location, "Loading content rules for document labels; skipping content rules"))
+	}
+
 	for chunkStart := 0; chunkStart < len(documents); chunkStart += labelChunkSize {
 
 		chunk := documents[chunkStart:min(chunkStart+labelChunkSize, len(documents))]
@@ -30,6 +38,7 @@ func (service *Rule) LabelDocuments(session data.Session, userID primitive.Objec
 
 		for index := range chunk {
 			perDocument[index] = append(model.ActorMatchKeys(chunk[index].ID()), model.DocumentMatchKeys(chunk[index])...)
+			perDocument[index] = append(perDocument[index], contentMatcher.MatchKeys(chunk[index])...)
 			keys = append(keys, perDocument[index]...)
 		}
 
@@ -4,6 +4,7 @@ import (
 	"iter"
 
 	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/multikey"
 	"github.com/benpate/data"
 	"github.com/benpate/derp"
 	"github.com/benpate/digit"
@@ -59,11 +60,19 @@ func (service *SearchDomain) GetJSONLD(session data.Session) (mapof.Any, error)
 		return nil, derp.Wrap(err, location, "Loading public key PEM")
 	}
 
-	// Return the result as a JSON-LD document
 	actorID := service.ActivityPubURL()
+
+	// Search actors sign with the domain's Ed25519 key
+	assertionMethod, err := service.domainService.AssertionMethodJSONLD(session, actorID)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Loading Ed25519 key")
+	}
+
+	// Return the result as a JSON-LD document
 	domain := service.domainService.Get()
 	result := map[string]any{
-		vocab.AtContext:                 []any{vocab.ContextTypeActivityStreams, vocab.ContextTypeSecurity, vocab.ContextTypeToot},
+		vocab.AtContext:                 []any{vocab.ContextTypeActivityStreams, vocab.ContextTypeSecurity, vocab.ContextTypeToot, multikey.Context},
 		vocab.PropertyType:              vocab.ActorTypeService,
 		vocab.PropertyID:                service.ActivityPubURL(),
 		vocab.PropertyURL:               service.ActivityPubProfileURL(),
@@ -83,6 +92,8 @@ func (service *SearchDomain) GetJSONLD(session data.Session) (mapof.Any, error)
 			vocab.PropertyOwner:        actorID,
 			vocab.PropertyPublicKeyPEM: publicKeyPEM,
 		},
+
+		"assertionMethod": assertionMethod,
 	}
 
 	return result, nil
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/multikey"
 	"github.com/EmissarySocial/emissary/tools/postcommit"
 	"github.com/benpate/data"
 	"github.com/benpate/data/option"
@@ -391,12 +392,19 @@ func (service *SearchQuery) GetJSONLD(session data.Session, searchQuery *model.S
 	searchQueryID := searchQuery.SearchQueryID
 	actorID := service.ActivityPubURL(searchQueryID)
 
+	// Search actors sign with the domain's Ed25519 key
+	assertionMethod, err := service.domainService.AssertionMethodJSONLD(session, actorID)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Getting Ed25519 key")
+	}
+
 	// Build the actor description
 	summary := `This is an automated search query on the server: ` + service.Hostname() + ` that announces new search results as they are received.  <a href="` + service.ActivityPubProfileURL(searchQuery) + `">View the full collection on ` + service.Hostname() + `</a>.`
 
 	// Return the result as a JSON-LD document
 	result := map[string]any{
-		vocab.AtContext:                 []any{vocab.ContextTypeActivityStreams, vocab.ContextTypeSecurity, vocab.ContextTypeToot},
+		vocab.AtContext:                 []any{vocab.ContextTypeActivityStreams, vocab.ContextTypeSecurity, vocab.ContextTypeToot, multikey.Context},
 		vocab.PropertyType:              vocab.ActorTypeService,
 		vocab.PropertyID:                service.ActivityPubURL(searchQueryID),
 		vocab.PropertyURL:               service.ActivityPubProfileURL(searchQuery),
@@ -415,6 +423,8 @@ func (service *SearchQuery) GetJSONLD(session data.Session, searchQuery *model.S
 			vocab.PropertyOwner:        actorID,
 			vocab.PropertyPublicKeyPEM: publicKeyPEM,
 		},
+
+		"assertionMethod": assertionMethod,
 	}
 
 	return result, nil
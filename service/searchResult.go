@@ -274,6 +274,25 @@ func (service *SearchResult) DeleteByURL(session data.Session, url string) error
 	return service.Delete(session, &searchResult, "deleted from search index")
 }
 
+// DeleteByRelay removes every SearchResult that was forwarded by the provided Relay
+func (service *SearchResult) DeleteByRelay(session data.Session, relayID primitive.ObjectID) error {
+
+	const location = "service.SearchResult.DeleteByRelay"
+
+	// RULE: Local SearchResults never belong to a Relay
+	if relayID.IsZero() {
+		return nil
+	}
+
+	criteria := exp.Equal("relayId", relayID).AndEqual("local", false)
+
+	if err := service.collection(session).HardDelete(criteria); err != nil {
+		return derp.Wrap(err, location, "Deleting SearchResults", relayID)
+	}
+
+	return nil
+}
+
 // Shuffle updates the "shuffle" field for all SearchResults that match the provided tags
 func (service *SearchResult) Shuffle(session data.Session) error {
 
@@ -161,7 +161,7 @@ func (service SendLocator) userActor(userID primitive.ObjectID) (sender.Actor, e
 	// Build an Actor object
 	actor := sender.NewActor(
 		user.ActivityPubURL(),
-		user.ActivityPubPublicKeyURL(),
+		service.encryptionKeyService.KeyID(&encryptionKey),
 		privateKey,
 	)
 
@@ -6,6 +6,7 @@ import (
 
 	"github.com/EmissarySocial/emissary/model"
 	"github.com/benpate/data"
+	"github.com/benpate/data/option"
 	"github.com/benpate/derp"
 	"github.com/benpate/digital-dome/dome"
 	"github.com/benpate/exp"
@@ -45,11 +46,15 @@ func NewSterankoSigninService(factory *Factory, session data.Session) SterankoSi
 	}
 }
 
-// SigninSuccess removes failed signin attempts for the provided username.
+// SigninSuccess removes failed password attempts for the provided username.  Failed
+// second factors are kept until the second factor succeeds, so that knowing the password
+// does not reset the lockout on guessing the second factor.
 func (s SterankoSigninService) SigninSuccess(request *http.Request, username string) {
 
-	if err := s.ClearSigninAttempts(username); err != nil {
-		derp.Report(err)
+	criteria := failureCriteria(username).And(exp.NotEqual("event", model.SigninEventTwoFactorFailure))
+
+	if err := s.collection().HardDelete(criteria); err != nil {
+		derp.Report(derp.Wrap(err, "SterankoSigninService.SigninSuccess", "Clearing failed password attempts for user", username))
 	}
 }
 
@@ -84,7 +89,7 @@ func (s SterankoSigninService) IsSigninLocked(request *http.Request, username st
 	// Count only failures recent enough to still be inside the window; older
 	// attempts age out, which is what makes the lockout temporary.
 	cutoff := time.Now().Add(-signinLockoutWindow).UnixMilli()
-	failureCount, err := s.collection().Count(exp.Equal("username", username).And(exp.GreaterThan("createDate", cutoff)))
+	failureCount, err := s.collection().Count(failureCriteria(username).And(exp.GreaterThan("createDate", cutoff)))
 
 	// RULE: fail closed. If we cannot read the attempt history, deny the signin
 	// rather than fall open to unlimited guessing.
@@ -114,16 +119,71 @@ func (s SterankoSigninService) IsSigninLocked(request *http.Request, username st
 	return true
 }
 
-// ClearSigninAttempts removes every recorded signin attempt for the provided username.
+// ClearSigninAttempts removes every failed signin attempt (passwords and second factors)
+// for the provided username.  The User's second factor history is not removed.
 func (s SterankoSigninService) ClearSigninAttempts(username string) error {
 
-	if err := s.collection().HardDelete(exp.Equal("username", username)); err != nil {
+	if err := s.collection().HardDelete(failureCriteria(username)); err != nil {
 		return derp.Wrap(err, "SterankoSigninService.ClearSigninAttempts", "Clearing signin attempts for user", username)
 	}
 
 	return nil
 }
 
+// TwoFactorFailure logs a failed second factor for the provided username.  It counts
+// toward the same lockout window as a failed password, so that the six-digit codes
+// cannot be guessed by an attacker who already knows the password.
+func (s SterankoSigninService) TwoFactorFailure(request *http.Request, username string) {
+	s.recordFailure(request, username, model.SigninEventTwoFactorFailure)
+}
+
+// PasskeyFailure logs a passwordless signin with a passkey that could not be verified.
+// It counts toward the same lockout window as a failed password.
+func (s SterankoSigninService) PasskeyFailure(request *http.Request, username string) {
+	s.recordFailure(request, username, model.SigninEventPasskeyFailure)
+}
+
+// RecordEvent adds an enrollment, use, or reset of a second factor to the provided
+// username's signin history.
+func (s SterankoSigninService) RecordEvent(request *http.Request, username string, event string) error {
+
+	signinAttempt := model.NewSigninAttempt(username, s.clientIPOf(request), userAgentOf(request))
+	signinAttempt.Event = event
+
+	if err := s.collection().Save(&signinAttempt, event); err != nil {
+		return derp.Wrap(err, "SterankoSigninService.RecordEvent", "Saving signin history", signinAttempt)
+	}
+
+	return nil
+}
+
+// QueryHistory returns the most recent signin attempts and second factor events for the provided username.
+func (s SterankoSigninService) QueryHistory(username string, maxRows int64) ([]model.SigninAttempt, error) {
+
+	result := make([]model.SigninAttempt, 0)
+	criteria := exp.Equal("username", username)
+
+	if err := s.collection().Query(&result, criteria, option.SortDesc("createDate"), option.MaxRows(maxRows)); err != nil {
+		return nil, derp.Wrap(err, "SterankoSigninService.QueryHistory", "Querying signin history", username)
+	}
+
+	return result, nil
+}
+
+// recordFailure saves a failed signin attempt with the provided event, and penalizes the source IP
+func (s SterankoSigninService) recordFailure(request *http.Request, username string, event string) {
+
+	signinAttempt := model.NewSigninAttempt(username, s.clientIPOf(request), userAgentOf(request))
+	signinAttempt.Event = event
+
+	if err := s.collection().Save(&signinAttempt, ""); err != nil {
+		derp.Report(derp.Wrap(err, "SterankoSigninService.recordFailure", "Saving signin attempt", signinAttempt))
+	}
+
+	s.blockIP(request)
+	s.pruneExpiredAttempts(username)
+}
+
 // pruneExpiredAttempts hard-deletes the provided username's signin attempts that
 // have aged out of the lockout window. Errors are reported but not returned: this
 // is opportunistic cleanup on the failure path and must not mask the signin result.
@@ -131,7 +191,7 @@ func (s SterankoSigninService) pruneExpiredAttempts(username string) {
 
 	cutoff := time.Now().Add(-signinLockoutWindow).UnixMilli()
 
-	if err := s.collection().HardDelete(exp.Equal("username", username).And(exp.LessThan("createDate", cutoff))); err != nil {
+	if err := s.collection().HardDelete(failureCriteria(username).And(exp.LessThan("createDate", cutoff))); err != nil {
 		derp.Report(derp.Wrap(err, "SterankoSigninService.pruneExpiredAttempts", "Pruning expired signin attempts", username))
 	}
 }
@@ -167,6 +227,13 @@ func userAgentOf(request *http.Request) string {
 	return request.UserAgent()
 }
 
+// failureCriteria returns the criteria for the provided username's failed signin attempts.
+// Records written before second factors existed have no event, so failures are found
+// by excluding the history events rather than by matching the failure events.
+func failureCriteria(username string) exp.Expression {
+	return exp.Equal("username", username).And(exp.NotIn("event", model.SigninHistoryEvents()))
+}
+
 func (s SterankoSigninService) collection() data.Collection {
 	return s.session.Collection("SigninAttempt")
 }
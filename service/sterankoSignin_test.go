@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"testing"
 	"time"
 
@@ -89,8 +90,8 @@ func (c *signinStore) Iterator(exp.Expression, ...option.Option) (data.Iterator,
 func (c *signinStore) Delete(data.Object, string) error { return nil }
 
 // matchesAttempt evaluates the service's criteria against a single record. It
-// supports Equal on "username" and GreaterThan/LessThan on "createDate"; any
-// other field or operator conservatively counts as "no match".
+// supports Equal on "username", NotEqual/NotIn on "event", and GreaterThan/LessThan
+// on "createDate"; any other field or operator conservatively counts as "no match".
 func matchesAttempt(criteria exp.Expression, record *model.SigninAttempt) bool {
 
 	return criteria.Match(func(predicate exp.Predicate) bool {
@@ -100,6 +101,18 @@ func matchesAttempt(criteria exp.Expression, record *model.SigninAttempt) bool {
 			value, ok := predicate.Value.(string)
 			return ok && predicate.Operator == exp.OperatorEqual && record.Username == value
 
+		case "event":
+			switch predicate.Operator {
+			case exp.OperatorNotEqual:
+				value, ok := predicate.Value.(string)
+				return ok && record.Event != value
+			case exp.OperatorNotIn:
+				values, ok := predicate.Value.([]string)
+				return ok && !slices.Contains(values, record.Event)
+			default:
+				return false
+			}
+
 		case "createDate":
 			value, ok := predicate.Value.(int64)
 			if !ok {
@@ -186,6 +199,13 @@ func attemptAt(username string, age time.Duration) *model.SigninAttempt {
 	return &attempt
 }
 
+// eventAt builds a stored SigninAttempt like attemptAt, but with the provided event.
+func eventAt(username string, event string, age time.Duration) *model.SigninAttempt {
+	attempt := attemptAt(username, age)
+	attempt.Event = event
+	return attempt
+}
+
 func signinRequest() *http.Request {
 	request := httptest.NewRequest(http.MethodPost, "/signin", nil)
 	request.RemoteAddr = "5.6.7.8:9999"
@@ -342,3 +362,86 @@ func TestSigninFailure_ToleratesNilRequest(t *testing.T) {
 	require.Len(t, store.records, 1)
 	require.Equal(t, "", store.records[0].IPAddress)
 }
+
+func TestSigninLocked_LegacyAttemptsCount(t *testing.T) {
+
+	store := &signinStore{}
+	// Attempts recorded before events existed are still password failures.
+	for range signinLockoutThreshold + 1 {
+		store.records = append(store.records, eventAt("target@example.com", "", time.Minute))
+	}
+
+	service, testDome := newSigninService(store)
+	t.Cleanup(testDome.Close)
+
+	require.True(t, service.IsSigninLocked(signinRequest(), "target@example.com"))
+}
+
+func TestSigninLocked_HistoryDoesNotCount(t *testing.T) {
+
+	store := &signinStore{}
+	for range signinLockoutThreshold + 5 {
+		store.records = append(store.records, eventAt("target@example.com", model.SigninEventTwoFactorUse, time.Minute))
+	}
+
+	service, _ := newSigninService(store)
+
+	require.False(t, service.IsSigninLocked(signinRequest(), "target@example.com"))
+}
+
+func TestTwoFactorFailure_CountsTowardLockout(t *testing.T) {
+
+	store := &signinStore{}
+	service, testDome := newSigninService(store)
+	t.Cleanup(testDome.Close)
+
+	for range signinLockoutThreshold + 1 {
+		service.TwoFactorFailure(nil, "target@example.com")
+	}
+
+	require.Equal(t, model.SigninEventTwoFactorFailure, store.records[0].Event)
+	require.True(t, service.IsSigninLocked(nil, "target@example.com"))
+
+	// A correct password does not reset the second factor lockout
+	service.SigninSuccess(nil, "target@example.com")
+	require.True(t, service.IsSigninLocked(nil, "target@example.com"))
+}
+
+func TestPasskeyFailure_CountsTowardLockout(t *testing.T) {
+
+	store := &signinStore{}
+	service, testDome := newSigninService(store)
+	t.Cleanup(testDome.Close)
+
+	for range signinLockoutThreshold {
+		service.PasskeyFailure(nil, "target@example.com")
+	}
+
+	require.Equal(t, model.SigninEventPasskeyFailure, store.records[0].Event)
+	require.True(t, service.IsSigninLocked(nil, "target@example.com"))
+}
+
+func TestClearSigninAttempts_KeepsHistory(t *testing.T) {
+
+	store := &signinStore{}
+	store.records = append(store.records, attemptAt("target@example.com", time.Minute))
+	store.records = append(store.records, eventAt("target@example.com", model.SigninEventTwoFactorFailure, time.Minute))
+	store.records = append(store.records, eventAt("target@example.com", model.SigninEventTwoFactorEnroll, time.Minute))
+
+	service, _ := newSigninService(store)
+
+	require.Nil(t, service.ClearSigninAttempts("target@example.com"))
+	require.Len(t, store.records, 1)
+	require.Equal(t, model.SigninEventTwoFactorEnroll, store.records[0].Event)
+}
+
+func TestRecordEvent(t *testing.T) {
+
+	store := &signinStore{}
+	service, _ := newSigninService(store)
+
+	require.Nil(t, service.RecordEvent(signinRequest(), "target@example.com", model.SigninEventRecoveryCodeUse))
+	require.Len(t, store.records, 1)
+	require.Equal(t, model.SigninEventRecoveryCodeUse, store.records[0].Event)
+	require.Equal(t, "5.6.7.8", store.records[0].IPAddress)
+}
@@ -53,6 +53,7 @@ type Stream struct {
 	notificationService *Notification
 	outboxService       *Outbox
 	permissionService   *Permission
+	pollVoteService     *PollVote
 	searchTagService    *SearchTag
 	templateService     *Template
 	followerService     *Follower
@@ -93,6 +94,7 @@ func (service *Stream) Refresh(factory *Factory) {
 	service.notificationService = factory.Notification()
 	service.outboxService = factory.Outbox()
 	service.permissionService = factory.Permission()
+	service.pollVoteService = factory.PollVote()
 	service.ruleService = factory.Rule()
 	service.searchTagService = factory.SearchTag()
 	service.templateService = factory.Template()
@@ -396,6 +398,10 @@ func (service *Stream) Save(session data.Session, stream *model.Stream, note str
 		log.Debug().Strs("rewrites", rewrites).Str("streamId", stream.StreamID.Hex()).Msg("Stream values normalized during save")
 	}
 
+	// RULE: Polls are closed automatically when they expire, so the close task
+	// must be (re)scheduled whenever the end date changes.
+	pollRescheduled := stream.Poll.MarkScheduled()
+
 	// RULE: calculate Parent IDs
 	service.calcParentIDs(session, stream)
 
@@ -407,12 +413,21 @@ func (service *Stream) Save(session data.Session, stream *model.Stream, note str
 		return derp.Wrap(err, location, "Saving Stream", stream, note)
 	}
 
+	// Notify SSE clients that this Stream (and its parent) have been updated
+	publishChange(session, service.queue, service.host, realtime.TopicUpdated, stream.StreamID)
+	publishChange(session, service.queue, service.host, realtime.TopicChildUpdated, stream.ParentID)
+
 	// Send SSE notifications to `InReplyTo` streams (if possible)
 	service.NotifyInReplyTo(session, stream.InReplyTo)
 
+	// RULE: Reschedule the Poll's close task if its end date has changed
+	if pollRescheduled {
+		service.pollVoteService.ScheduleClose(session, stream)
+	}
+
 	// Send stream:create and stream:update Webhooks
 	eventName := iif(wasNew, model.WebhookEventStreamCreate, model.WebhookEventStreamUpdate)
-	service.webhookService.Send(stream, eventName)
+	service.webhookService.Send(session, stream, eventName)
 
 	return nil
 }
@@ -442,10 +457,10 @@ func (service *Stream) Delete(session data.Session, stream *model.Stream, note s
 	}
 
 	// Send Webhooks (if configured)
-	service.webhookService.Send(stream, model.WebhookEventStreamDelete)
+	service.webhookService.Send(session, stream, model.WebhookEventStreamDelete)
 
 	if stream.IsPublished() {
-		service.webhookService.Send(stream, model.WebhookEventStreamPublishUndo)
+		service.webhookService.Send(session, stream, model.WebhookEventStreamPublishUndo)
 
 		if err := service.sendSyndicationMessages(session, stream, nil, nil, stream.Syndication.Values); err != nil {
 			derp.Report(derp.Wrap(err, location, "Sending syndication messages", stream))
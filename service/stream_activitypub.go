@@ -2,6 +2,7 @@ package service
 
 import (
 	"crypto"
+	"crypto/ed25519"
 	"iter"
 	"time"
 
@@ -154,6 +155,11 @@ func (service *Stream) JSONLD(session data.Session, stream *model.Stream) mapof.
 		}
 	}
 
+	// Streams that contain a Poll are published as a "Question", regardless of the Template's social role
+	if stream.Poll.NotZero() {
+		stream.Poll.ApplyJSONLD(result)
+	}
+
 	return result
 }
 
@@ -180,10 +186,6 @@ func (service *Stream) ActivityPubURL(streamID primitive.ObjectID) string {
 	return service.host + "/" + streamID.Hex()
 }
 
-func (service *Stream) PublicKeyID(streamID primitive.ObjectID) string {
-	return service.ActivityPubURL(streamID) + "#main-key"
-}
-
 func (service *Stream) PrivateKey(session data.Session, streamID primitive.ObjectID) (crypto.PrivateKey, error) {
 
 	const location = "service.Stream.PrivateKey"
@@ -206,6 +208,45 @@ func (service *Stream) PrivateKey(session data.Session, streamID primitive.Objec
 
 }
 
+// SigningKey returns the key ID and RSA private key that currently sign requests for the provided streamID.
+// Both values come from the same EncryptionKey, so the ID always matches the key that made the signature.
+func (service *Stream) SigningKey(session data.Session, streamID primitive.ObjectID) (string, crypto.PrivateKey, error) {
+
+	const location = "service.Stream.SigningKey"
+
+	encryptionKey := model.NewEncryptionKey()
+	if err := service.keyService.LoadByParentID(session, model.EncryptionKeyTypeStream, streamID, &encryptionKey); err != nil {
+		return "", nil, derp.Wrap(err, location, "Loading encryption key", streamID)
+	}
+
+	privateKey, err := service.keyService.GetPrivateKey(&encryptionKey)
+
+	if err != nil {
+		return "", nil, derp.Wrap(err, location, "Extracting private key", encryptionKey.EncryptionKeyID)
+	}
+
+	return service.keyService.KeyID(&encryptionKey), privateKey, nil
+}
+
+// Ed25519SigningKey returns the key ID and Ed25519 private key that currently sign RFC 9421 requests for the provided streamID
+func (service *Stream) Ed25519SigningKey(session data.Session, streamID primitive.ObjectID) (string, ed25519.PrivateKey, error) {
+
+	const location = "service.Stream.Ed25519SigningKey"
+
+	encryptionKey := model.NewEncryptionKey()
+	if err := service.keyService.LoadByParentID(session, model.EncryptionKeyTypeStream, streamID, &encryptionKey); err != nil {
+		return "", nil, derp.Wrap(err, location, "Loading encryption key", streamID)
+	}
+
+	privateKey, err := service.keyService.GetEd25519PrivateKey(&encryptionKey)
+
+	if err != nil {
+		return "", nil, derp.Wrap(err, location, "Extracting private key", encryptionKey.EncryptionKeyID)
+	}
+
+	return service.keyService.Ed25519KeyID(&encryptionKey), privateKey, nil
+}
+
 // ActivityPubActor returns an ActivityPub Actor object ** WHICH INCLUDES ENCRYPTION KEYS **
 // for the provided Stream.
 func (service *Stream) ActivityPubActor(session data.Session, streamID primitive.ObjectID) (outbox.Actor, error) {
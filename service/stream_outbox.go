@@ -0,0 +1,218 @@
+package service
+
+import (
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/form"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+	"github.com/benpate/rosetta/slice"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/******************************************
+ * Outbox Posts
+ *
+ * These methods are shared by the web editor, the Mastodon API,
+ * and the ActivityPub C2S outbox so that every client produces
+ * the same published Stream records.
+ ******************************************/
+
+// NewOutboxPost returns a new Stream in the User's outbox.  It uses the Template that the
+// User has chosen for the provided social role (Note or Article), and falls back to the
+// default "outbox-message" Template.  The Stream is placed in the User's chosen outbox
+// folder, or at the top of their outbox if they have not chosen one.
+func (service *Stream) NewOutboxPost(session data.Session, user *model.User, socialRole string) (model.Stream, error) {
+
+	const location = "service.Stream.NewOutboxPost"
+
+	// Choose the Template for this kind of post
+	templateID := user.NoteTemplate
+
+	if socialRole == vocab.ObjectTypeArticle {
+		templateID = firstOf(user.ArticleTemplate, templateID)
+	}
+
+	templateID = firstOf(templateID, "outbox-message")
+
+	template, err := service.templateService.Load(templateID)
+
+	if err != nil {
+		return model.Stream{}, derp.Wrap(err, location, "Loading template", templateID)
+	}
+
+	// Place the new Stream in the User's outbox
+	stream := model.NewStream()
+
+	if !service.setOutboxFolderLocation(session, &template, &stream, user) {
+		if err := service.SetLocationOutbox(&template, &stream, user.UserID); err != nil {
+			return model.Stream{}, derp.Wrap(err, location, "Setting outbox location", templateID)
+		}
+	}
+
+	stream.AttributedTo = user.PersonLink()
+	stream.SocialRole = socialRole
+
+	return stream, nil
+}
+
+// setOutboxFolderLocation places a new Stream inside the User's chosen outbox folder, and
+// returns TRUE if successful.  A missing folder, or one that can't contain the Template, is
+// reported and the caller falls back to the top of the outbox, so that posting never fails
+// because of a stale setting.
+func (service *Stream) setOutboxFolderLocation(session data.Session, template *model.Template, stream *model.Stream, user *model.User) bool {
+
+	const location = "service.Stream.setOutboxFolderLocation"
+
+	if user.OutboxFolderID.IsZero() {
+		return false
+	}
+
+	folder := model.NewStream()
+
+	if err := service.LoadByID(session, user.OutboxFolderID, &folder); err != nil {
+		derp.Report(derp.Wrap(err, location, "Loading outbox folder. Using the top of the outbox instead.", user.OutboxFolderID))
+		return false
+	}
+
+	// RULE: The folder must be at the top of this User's outbox
+	if folder.ParentID != user.UserID {
+		derp.Report(derp.Forbidden(location, "Outbox folder does not belong to this User. Using the top of the outbox instead.", user.UserID, user.OutboxFolderID))
+		return false
+	}
+
+	if err := service.SetLocationChild(template, stream, &folder); err != nil {
+		derp.Report(derp.Wrap(err, location, "Setting outbox folder location. Using the top of the outbox instead.", user.OutboxFolderID))
+		return false
+	}
+
+	return true
+}
+
+// ListOutboxFolders returns the Streams at the top of a User's outbox that can contain other
+// Streams, as form options.  The first option ("") is the top of the outbox itself.
+func (service *Stream) ListOutboxFolders(session data.Session, userID primitive.ObjectID) ([]form.LookupCode, error) {
+
+	const location = "service.Stream.ListOutboxFolders"
+
+	result := []form.LookupCode{{Value: "", Label: "Top of my Outbox"}}
+
+	// Find the outbox Templates that can contain other Streams
+	containers := service.templateService.List(func(template *model.Template) bool {
+		return template.CanBeContainedBy("outbox") && len(service.templateService.ListByContainer(template.TemplateRole)) > 0
+	})
+
+	if len(containers) == 0 {
+		return result, nil
+	}
+
+	templateIDs := slice.Map(containers, func(container form.LookupCode) string {
+		return container.Value
+	})
+
+	// Find this User's outbox Streams that use those Templates
+	criteria := exp.Equal("parentId", userID).AndIn("templateId", templateIDs)
+	folders, err := service.Query(session, criteria, option.SortAsc("rank"))
+
+	if err != nil {
+		return result, derp.Wrap(err, location, "Querying outbox folders", userID)
+	}
+
+	for _, folder := range folders {
+		result = append(result, form.LookupCode{
+			Value: folder.StreamID.Hex(),
+			Label: folder.Label,
+		})
+	}
+
+	return result, nil
+}
+
+// SetOutboxPostDocument copies the values from an ActivityStreams Note or Article into an
+// outbox Stream.  Content warnings on Notes (the "summary" property) map to the Stream label,
+// matching the Mastodon API's "spoiler_text".
+func (service *Stream) SetOutboxPostDocument(stream *model.Stream, document streams.Document) {
+
+	switch document.Type() {
+
+	case vocab.ObjectTypeArticle:
+		stream.Label = document.Name()
+		stream.Summary = document.Summary()
+
+	default:
+		stream.Label = document.Summary()
+	}
+
+	stream.InReplyTo = document.InReplyTo().ID()
+	stream.Content = service.contentService.New(model.ContentFormatHTML, document.Content())
+}
+
+// OutboxPostState returns the State that new outbox posts are published into, as declared by
+// the "save-and-publish" step of the Stream's Template.  This keeps posts from client apps in
+// the same State as posts from the web editor.
+func (service *Stream) OutboxPostState(stream *model.Stream) (string, error) {
+
+	const location = "service.Stream.OutboxPostState"
+
+	template, err := service.templateService.Load(stream.TemplateID)
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Loading template", stream.TemplateID)
+	}
+
+	return template.PublishState(), nil
+}
+
+// PublishOutboxPost saves a Stream in the User's outbox and publishes it to the User's
+// followers.  New Streams send a "Create" activity, and existing Streams send an "Update"
+// when republish is TRUE.
+func (service *Stream) PublishOutboxPost(session data.Session, user *model.User, stream *model.Stream, stateID string, republish bool) error {
+
+	const location = "service.Stream.PublishOutboxPost"
+
+	// New Streams must be saved first so that their permissions are calculated
+	if stream.IsNew() {
+		if err := service.Save(session, stream, "Created"); err != nil {
+			return derp.Wrap(err, location, "Saving stream", stream)
+		}
+	}
+
+	// Guarantee this Stream has a context collection.
+	if err := service.CalcContext(session, stream); err != nil {
+		return derp.Wrap(err, location, "Calculating context for stream", stream)
+	}
+
+	// If this Stream is a reply, record it in the local parent's Replies collection.
+	if err := service.AddReply(session, stream.InReplyTo, stream.ActivityPubURL()); err != nil {
+		return derp.Wrap(err, location, "Adding reply to parent's collection", stream)
+	}
+
+	// Publish the Stream to the User's outbox
+	if err := service.Publish(session, user, stream, stateID, true, republish); err != nil {
+		return derp.Wrap(err, location, "Publishing stream", stream)
+	}
+
+	return nil
+}
+
+// DeleteOutboxPost removes a Stream from the User's outbox.  It sends the "Delete"
+// activities to the User's followers before deleting the Stream from the database.
+func (service *Stream) DeleteOutboxPost(session data.Session, user *model.User, stream *model.Stream, note string) error {
+
+	const location = "service.Stream.DeleteOutboxPost"
+
+	if stream.IsPublished() {
+		if err := service.UnPublish(session, user, stream, stream.StateID, true); err != nil {
+			return derp.Wrap(err, location, "Unpublishing stream", stream)
+		}
+	}
+
+	if err := service.Delete(session, stream, note); err != nil {
+		return derp.Wrap(err, location, "Deleting stream", stream)
+	}
+
+	return nil
+}
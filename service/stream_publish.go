@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/realtime"
 	"github.com/benpate/data"
 	"github.com/benpate/derp"
 	"github.com/benpate/hannibal"
@@ -13,6 +14,7 @@ import (
 	"github.com/benpate/hannibal/vocab"
 	"github.com/benpate/rosetta/mapof"
 	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 /******************************************
@@ -24,6 +26,18 @@ func (service *Stream) Publish(session data.Session, user *model.User, stream *m
 
 	const location = "service.Stream.Publish"
 
+	// RULE: Publishing now replaces any pending schedule.  Scheduled Streams
+	// have never been delivered, so they are treated as unpublished.
+	if stream.IsScheduled() {
+
+		if err := service.dequeuePublishScheduled(stream); err != nil {
+			return derp.Wrap(err, location, "Removing scheduled task", stream.StreamID)
+		}
+
+		stream.Schedule = model.StreamSchedule{}
+		stream.PublishDate = math.MaxInt64
+	}
+
 	wasPublished := stream.IsPublished()
 
 	// RULE: IF this stream is not yet published, then set the publish date
@@ -53,8 +67,13 @@ func (service *Stream) Publish(session data.Session, user *model.User, stream *m
 		}
 	}
 
+	// Send public posts to Mastodon streaming clients
+	if outbox && stream.IsPublic() {
+		service.publish_streaming(session, stream, iif(wasPublished, realtime.StreamingEventStatusUpdate, realtime.StreamingEventUpdate))
+	}
+
 	// Send stream:publish Webhooks
-	service.webhookService.Send(stream, model.WebhookEventStreamPublish)
+	service.webhookService.Send(session, stream, model.WebhookEventStreamPublish)
 
 	// Send syndication messages to all targets
 	switch {
@@ -76,6 +95,31 @@ func (service *Stream) Publish(session data.Session, user *model.User, stream *m
 	return nil
 }
 
+// publish_streaming sends a public Stream to the "public" stream, and to the "hashtag" stream
+// of each of its hashtags.  Updates carry the Status JSON, and deletes carry the Status ID.
+// Streaming is best-effort, so errors are reported but never returned.
+func (service *Stream) publish_streaming(session data.Session, stream *model.Stream, event string) {
+
+	payload := stream.StreamID.Hex()
+
+	if event != realtime.StreamingEventDelete {
+
+		var err error
+		payload, err = streamingPayload(stream.Toot())
+
+		if err != nil {
+			derp.Report(derp.Wrap(err, "service.Stream.publish_streaming", "Encoding status", stream.StreamID))
+			return
+		}
+	}
+
+	publishStreamingEvent(session, service.queue, service.host, primitive.NilObjectID, []string{realtime.StreamPublic}, event, payload)
+
+	for _, hashtag := range stream.Hashtags {
+		publishStreamingEvent(session, service.queue, service.host, primitive.NilObjectID, realtime.HashtagStream(hashtag), event, payload)
+	}
+}
+
 func (service *Stream) publish_outbox(session data.Session, user *model.User, stream *model.Stream, wasPublished bool) error {
 
 	const location = "service.Stream.publish_outbox"
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"iter"
+	"math"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/queries"
+	"github.com/EmissarySocial/emissary/tools/postcommit"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/turbine/queue"
+	"github.com/benpate/uri"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/******************************************
+ * Scheduled Publishing
+ *
+ * Scheduled Streams are saved with a future PublishDate, which
+ * keeps them out of every published listing.  A delayed
+ * "PublishScheduledStream" task publishes them (with ActivityPub
+ * delivery and webhooks) when the PublishDate arrives.
+ ******************************************/
+
+// SchedulePublish saves a Stream that will be published at a future date.  The remaining
+// arguments are the same as Publish, and are used when the Stream is published.
+// Dates that have already passed publish the Stream immediately.
+func (service *Stream) SchedulePublish(session data.Session, user *model.User, stream *model.Stream, stateID string, outbox bool, republish bool, publishDate int64) error {
+
+	const location = "service.Stream.SchedulePublish"
+
+	// If the date has already arrived, then publish the Stream right now.
+	if publishDate <= time.Now().Unix() {
+		return service.publishNow(session, user, stream, stateID, outbox, republish)
+	}
+
+	// RULE: Streams that are already public cannot be pulled back into the future
+	if stream.IsPublished() {
+		return derp.Validation("Published streams cannot be scheduled", stream.StreamID)
+	}
+
+	// Record the schedule in the Stream
+	stream.PublishDate = publishDate
+	stream.UnPublishDate = math.MaxInt64
+	stream.Schedule = model.NewStreamSchedule(user.UserID, stateID, outbox, republish)
+	stream.SetAttributedTo(user.PersonLink())
+
+	if err := service.Save(session, stream, "Scheduled"); err != nil {
+		return derp.Wrap(err, location, "Saving stream", stream.StreamID)
+	}
+
+	// Queue a task to publish the Stream when it is due
+	if err := service.queuePublishScheduled(session, stream); err != nil {
+		return derp.Wrap(err, location, "Scheduling task", stream.StreamID)
+	}
+
+	return nil
+}
+
+// Reschedule moves a scheduled Stream to a new publish date, keeping the
+// options that were chosen when it was first scheduled.
+func (service *Stream) Reschedule(session data.Session, stream *model.Stream, publishDate int64) error {
+
+	const location = "service.Stream.Reschedule"
+
+	if !stream.IsScheduled() {
+		return derp.NotFound(location, "Stream is not scheduled", stream.StreamID)
+	}
+
+	if publishDate <= time.Now().Unix() {
+		return derp.Validation("Publish date must be in the future", publishDate)
+	}
+
+	stream.PublishDate = publishDate
+
+	if err := service.Save(session, stream, "Rescheduled"); err != nil {
+		return derp.Wrap(err, location, "Saving stream", stream.StreamID)
+	}
+
+	if err := service.queuePublishScheduled(session, stream); err != nil {
+		return derp.Wrap(err, location, "Scheduling task", stream.StreamID)
+	}
+
+	return nil
+}
+
+// Unschedule cancels a pending schedule, leaving the Stream unpublished.
+func (service *Stream) Unschedule(session data.Session, stream *model.Stream, note string) error {
+
+	const location = "service.Stream.Unschedule"
+
+	if !stream.IsScheduled() {
+		return nil
+	}
+
+	if err := service.dequeuePublishScheduled(stream); err != nil {
+		return derp.Wrap(err, location, "Removing scheduled task", stream.StreamID)
+	}
+
+	stream.Schedule = model.StreamSchedule{}
+	stream.PublishDate = math.MaxInt64
+
+	if err := service.Save(session, stream, note); err != nil {
+		return derp.Wrap(err, location, "Saving stream", stream.StreamID)
+	}
+
+	return nil
+}
+
+// PublishScheduled publishes a scheduled Stream whose PublishDate has arrived.
+// Streams that are no longer scheduled (or not yet due) are ignored.
+func (service *Stream) PublishScheduled(session data.Session, stream *model.Stream) error {
+
+	const location = "service.Stream.PublishScheduled"
+
+	if !stream.IsScheduled() {
+		return nil
+	}
+
+	// RULE: Rescheduled Streams already have a task queued for their new date
+	if stream.PublishDate > time.Now().Unix() {
+		return nil
+	}
+
+	// Load the User who scheduled this Stream
+	user := model.NewUser()
+
+	if err := service.userService.LoadByID(session, stream.Schedule.UserID, &user); err != nil {
+		return derp.Wrap(err, location, "Loading user", stream.Schedule.UserID)
+	}
+
+	// Claim the Stream before publishing it.  The delayed task and the hourly sweep
+	// can both reach a due Stream, so only the caller that clears the schedule continues.
+	claimed, err := queries.ClaimScheduledStream(session.Context(), service.collection(session), stream.StreamID, stream.PublishDate)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Claiming stream", stream.StreamID)
+	}
+
+	if !claimed {
+		return nil
+	}
+
+	// Clear the schedule and reset the PublishDate so that Publish treats
+	// this as the first publication (sending "Create" activities).
+	schedule := stream.Schedule
+	stream.Schedule = model.StreamSchedule{}
+	stream.PublishDate = math.MaxInt64
+
+	if err := service.publishNow(session, &user, stream, schedule.StateID, schedule.Outbox, schedule.Republish); err != nil {
+		return derp.Wrap(err, location, "Publishing stream", stream.StreamID)
+	}
+
+	return nil
+}
+
+// QueryScheduledByUser returns the Streams that the designated User has scheduled
+// to publish in the future, newest first.
+func (service *Stream) QueryScheduledByUser(session data.Session, userID primitive.ObjectID, criteria exp.Expression, options ...option.Option) ([]model.Stream, error) {
+
+	criteria = criteria.
+		AndEqual("schedule.userId", userID).
+		AndGreaterThan("publishDate", time.Now().Unix())
+
+	options = append(options, option.SortDesc("createDate"))
+
+	return service.Query(session, criteria, options...)
+}
+
+// RangeScheduledDue returns all scheduled Streams whose PublishDate has passed.
+// This is a safety net in case a "PublishScheduledStream" task was lost.
+func (service *Stream) RangeScheduledDue(session data.Session) (iter.Seq[model.Stream], error) {
+
+	criteria := exp.GreaterThan("schedule.userId", primitive.NilObjectID).
+		AndLessOrEqual("publishDate", time.Now().Unix())
+
+	return service.Range(session, criteria)
+}
+
+// publishNow publishes a Stream immediately, using the outbox rules when requested.
+func (service *Stream) publishNow(session data.Session, user *model.User, stream *model.Stream, stateID string, outbox bool, republish bool) error {
+
+	if outbox {
+		return service.PublishOutboxPost(session, user, stream, stateID, republish)
+	}
+
+	return service.Publish(session, user, stream, stateID, false, republish)
+}
+
+// queuePublishScheduled queues (or replaces) the task that publishes a scheduled Stream.
+func (service *Stream) queuePublishScheduled(session data.Session, stream *model.Stream) error {
+
+	if err := service.dequeuePublishScheduled(stream); err != nil {
+		return derp.Wrap(err, "service.Stream.queuePublishScheduled", "Removing existing task", stream.StreamID)
+	}
+
+	postcommit.Publish(
+		session,
+		service.queue,
+		"PublishScheduledStream",
+		mapof.Any{
+			"hostname": uri.Hostname(service.host),
+			"streamId": stream.StreamID.Hex(),
+		},
+		queue.WithSignature(publishScheduledSignature(stream)),
+		queue.WithDelaySeconds(int(max(stream.PublishDate-time.Now().Unix(), 0))),
+	)
+
+	return nil
+}
+
+// dequeuePublishScheduled removes the pending "PublishScheduledStream" task for a Stream (if present)
+func (service *Stream) dequeuePublishScheduled(stream *model.Stream) error {
+	return service.queue.Delete(publishScheduledSignature(stream))
+}
+
+// publishScheduledSignature returns the unique queue signature for a Stream's scheduled publish task
+func publishScheduledSignature(stream *model.Stream) string {
+	return "PUBLISH:" + stream.StreamID.Hex()
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/realtime"
 	"github.com/benpate/data"
 	"github.com/benpate/derp"
 	"github.com/rs/zerolog/log"
@@ -34,8 +35,13 @@ func (service *Stream) UnPublish(session data.Session, user *model.User, stream
 			return derp.Wrap(err, location, "Unpublishing from parent Stream's outbox", stream)
 		}
 
+		// Remove public posts from Mastodon streaming clients
+		if stream.IsPublished() && stream.IsPublic() {
+			service.publish_streaming(session, stream, realtime.StreamingEventDelete)
+		}
+
 		// Send stream:publish:undo Webhooks
-		service.webhookService.Send(stream, model.WebhookEventStreamPublishUndo)
+		service.webhookService.Send(session, stream, model.WebhookEventStreamPublishUndo)
 
 		// Send syndication:undo messages to all targets
 		if err := service.sendSyndicationMessages(session, stream, nil, nil, stream.Syndication.Values); err != nil {
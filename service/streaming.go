@@ -0,0 +1,46 @@
+package service
+
+import (
+	"encoding/json"
+
+	"github.com/EmissarySocial/emissary/realtime"
+	"github.com/EmissarySocial/emissary/tools/postcommit"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/mapof"
+	"github.com/benpate/turbine/queue"
+	"github.com/benpate/uri"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/******************************************
+ * Mastodon Streaming API
+ ******************************************/
+
+// publishStreamingEvent sends a best-effort event to the Mastodon streaming API clients that
+// are subscribed to a stream.  User streams are published under the User's ID, and domain-wide
+// streams (public, hashtag) under primitive.NilObjectID.  Like Notification.publishSSE, this
+// rides the post-commit spool so that clients never see a record before it has been committed.
+func publishStreamingEvent(session data.Session, q *queue.Queue, host string, objectID primitive.ObjectID, stream []string, event string, payload string) {
+	postcommit.Publish(session, q, "PublishRealtimeMessage", mapof.Any{
+		"hostname": uri.Hostname(host),
+		"objectId": objectID.Hex(),
+		"topic":    realtime.TopicStreaming,
+		"stream":   stream,
+		"event":    event,
+		"data":     payload,
+	}, queue.WithInline())
+}
+
+// streamingPayload encodes a Mastodon entity (Status, Notification) as the payload of a
+// streaming event.
+func streamingPayload(value any) (string, error) {
+
+	result, err := json.Marshal(value)
+
+	if err != nil {
+		return "", derp.Wrap(err, "service.streamingPayload", "Encoding streaming payload")
+	}
+
+	return string(result), nil
+}
@@ -37,6 +37,8 @@ import (
 type User struct {
 	activityService   *ActivityStream
 	attachmentService *Attachment
+	bookmarkService   *Bookmark
+	filterService     *Filter
 	connectionService *Connection
 	emailService      *DomainEmail
 	domainService     *Domain
@@ -47,6 +49,8 @@ type User struct {
 	newsFeedService   *NewsFeed
 	outboxService     *Outbox
 	outbox2Service    *Outbox2
+	passkeyService    *Passkey
+	pollVoteService   *PollVote
 	responseService   *Response
 	ruleService       *Rule
 	searchTagService  *SearchTag
@@ -55,7 +59,6 @@ type User struct {
 	templateService   *Template
 	webhookService    *Webhook
 	queue             *queue.Queue
-	sseUpdateChannel  chan<- realtime.Message
 	host              string
 }
 
@@ -73,6 +76,8 @@ func (service *User) Refresh(factory *Factory) {
 
 	service.activityService = factory.ActivityStream()
 	service.attachmentService = factory.Attachment()
+	service.bookmarkService = factory.Bookmark()
+	service.filterService = factory.Filter()
 	service.connectionService = factory.Connection()
 	service.domainService = factory.Domain()
 	service.emailService = factory.Email()
@@ -83,13 +88,14 @@ func (service *User) Refresh(factory *Factory) {
 	service.keyService = factory.EncryptionKey()
 	service.outboxService = factory.Outbox()
 	service.outbox2Service = factory.Outbox2()
+	service.passkeyService = factory.Passkey()
+	service.pollVoteService = factory.PollVote()
 	service.responseService = factory.Response()
 	service.ruleService = factory.Rule()
 	service.steranko = factory.Steranko
 	service.streamService = factory.Stream()
 	service.templateService = factory.Template()
 	service.webhookService = factory.Webhook()
-	service.sseUpdateChannel = factory.SSEUpdateChannel()
 	service.queue = factory.Queue()
 
 	service.host = factory.Host()
@@ -158,6 +164,14 @@ func (service *User) Query(session data.Session, criteria exp.Expression, option
 	return result, err
 }
 
+// QuerySummary returns an slice containing UserSummaries for all of the Users who match the provided criteria
+func (service *User) QuerySummary(session data.Session, criteria exp.Expression, options ...option.Option) ([]model.UserSummary, error) {
+	result := make([]model.UserSummary, 0)
+	options = append(options, option.Fields(model.UserSummaryFields()...))
+	err := service.collection(session).Query(&result, notDeleted(criteria), options...)
+	return result, err
+}
+
 // Load retrieves an User from the database
 func (service *User) Load(session data.Session, criteria exp.Expression, result *model.User, options ...option.Option) error {
 	if err := service.collection(session).Load(notDeleted(criteria), result, options...); err != nil {
@@ -300,12 +314,20 @@ func (service *User) Save(session data.Session, user *model.User, note string) e
 		}
 	}
 
+	// RULE: When a User stops approving followers manually, approve any follow requests
+	// that are still waiting.  (IsLocked is part of the actor document, so it changes the fingerprint)
+	if profileChanged && !user.IsLocked {
+		if err := service.followerService.AcceptAllFollowRequests(session, user.UserID); err != nil {
+			return derp.Wrap(err, location, "Accepting pending follow requests", user)
+		}
+	}
+
 	// Send Webhooks (if configured)
 	eventName := iif(isNew, model.WebhookEventUserCreate, model.WebhookEventUserUpdate)
-	service.webhookService.Send(user, eventName)
+	service.webhookService.Send(session, user, eventName)
 
 	// Notify SSE clients that this User has been updated
-	service.sseUpdateChannel <- realtime.NewMessage_Updated(user.UserID)
+	publishChange(session, service.queue, service.host, realtime.TopicUpdated, user.UserID)
 
 	// Success!
 	return nil
@@ -316,6 +338,16 @@ func (service *User) Delete(session data.Session, user *model.User, note string)
 
 	const location = "service.User.Delete"
 
+	// Delete related Bookmarks
+	if err := service.bookmarkService.DeleteByUserID(session, user.UserID, "Deleted with owner"); err != nil {
+		return derp.Wrap(err, location, "Deleting User's bookmarks", user, note)
+	}
+
+	// Delete related Filters
+	if err := service.filterService.DeleteByUserID(session, user.UserID, "Deleted with owner"); err != nil {
+		return derp.Wrap(err, location, "Deleting User's filters", user, note)
+	}
+
 	// Delete related Folders
 	if err := service.folderService.DeleteByUserID(session, user.UserID, "Deleted with owner"); err != nil {
 		return derp.Wrap(err, location, "Deleting User's folders", user, note)
@@ -348,6 +380,16 @@ func (service *User) Delete(session data.Session, user *model.User, note string)
 		return derp.Wrap(err, location, "Deleting User's outbox messages", user, note)
 	}
 
+	// Delete related Passkeys
+	if err := service.passkeyService.DeleteByUserID(session, user.UserID, "Deleted with owner"); err != nil {
+		return derp.Wrap(err, location, "Deleting User's passkeys", user, note)
+	}
+
+	// Delete related PollVotes
+	if err := service.pollVoteService.DeleteByUserID(session, user.UserID, "Deleted with owner"); err != nil {
+		return derp.Wrap(err, location, "Deleting User's poll votes", user, note)
+	}
+
 	// Delete related Responses
 	if err := service.responseService.DeleteByUserID(session, user.UserID, "Deleted with owner"); err != nil {
 		return derp.Wrap(err, location, "Deleting User's responses", user, note)
@@ -369,7 +411,7 @@ func (service *User) Delete(session data.Session, user *model.User, note string)
 	}
 
 	// Send user:delete webhooks
-	service.webhookService.Send(user, model.WebhookEventUserDelete)
+	service.webhookService.Send(session, user, model.WebhookEventUserDelete)
 
 	// Farewell, sweet prince
 	return nil
@@ -638,6 +680,7 @@ func (service *User) ValidateUsername(session data.Session, userID primitive.Obj
 		"application",
 		"guest",
 		"identity",
+		"inbox",
 		"me",
 		"owner",
 		"root",
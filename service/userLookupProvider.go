@@ -0,0 +1,38 @@
+package service
+
+import (
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/form"
+)
+
+// UserLookupProvider is a read-only lookup of every User on this domain
+type UserLookupProvider struct {
+	userService *User
+	session     data.Session
+}
+
+func NewUserLookupProvider(session data.Session, userService *User) UserLookupProvider {
+	return UserLookupProvider{
+		userService: userService,
+		session:     session,
+	}
+}
+
+func (service UserLookupProvider) Get() []form.LookupCode {
+	users, err := service.userService.QuerySummary(service.session, exp.All(), option.SortAsc("displayName"))
+
+	if err != nil {
+		derp.Report(derp.Wrap(err, "service.UserLookupProvider.Get", "Retrieving users"))
+	}
+
+	result := make([]form.LookupCode, 0, len(users))
+
+	for _, user := range users {
+		result = append(result, user.LookupCode())
+	}
+
+	return result
+}
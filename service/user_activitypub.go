@@ -2,6 +2,7 @@ package service
 
 import (
 	"crypto"
+	"crypto/ed25519"
 	"iter"
 	"net/url"
 	"strings"
@@ -70,11 +71,6 @@ func (service *User) ActivityPubURL(userID primitive.ObjectID) string {
 	return service.host + "/@" + userID.Hex()
 }
 
-// PublicKeyID returns the key ID ("#main-key" fragment URL) for the provided userID
-func (service *User) PublicKeyID(userID primitive.ObjectID) string {
-	return service.ActivityPubURL(userID) + "#main-key"
-}
-
 // PrivateKey returns the signing key for the provided userID
 func (service *User) PrivateKey(session data.Session, userID primitive.ObjectID) (crypto.PrivateKey, error) {
 
@@ -96,6 +92,45 @@ func (service *User) PrivateKey(session data.Session, userID primitive.ObjectID)
 	return privateKey, nil
 }
 
+// SigningKey returns the key ID and RSA private key that currently sign requests for the provided userID.
+// Both values come from the same EncryptionKey, so the ID always matches the key that made the signature.
+func (service *User) SigningKey(session data.Session, userID primitive.ObjectID) (string, crypto.PrivateKey, error) {
+
+	const location = "service.User.SigningKey"
+
+	encryptionKey := model.NewEncryptionKey()
+	if err := service.keyService.LoadByParentID(session, model.EncryptionKeyTypeUser, userID, &encryptionKey); err != nil {
+		return "", nil, derp.Wrap(err, location, "Loading encryption key", userID)
+	}
+
+	privateKey, err := service.keyService.GetPrivateKey(&encryptionKey)
+
+	if err != nil {
+		return "", nil, derp.Wrap(err, location, "Extracting private key", encryptionKey.EncryptionKeyID)
+	}
+
+	return service.keyService.KeyID(&encryptionKey), privateKey, nil
+}
+
+// Ed25519SigningKey returns the key ID and Ed25519 private key that currently sign RFC 9421 requests for the provided userID
+func (service *User) Ed25519SigningKey(session data.Session, userID primitive.ObjectID) (string, ed25519.PrivateKey, error) {
+
+	const location = "service.User.Ed25519SigningKey"
+
+	encryptionKey := model.NewEncryptionKey()
+	if err := service.keyService.LoadByParentID(session, model.EncryptionKeyTypeUser, userID, &encryptionKey); err != nil {
+		return "", nil, derp.Wrap(err, location, "Loading encryption key", userID)
+	}
+
+	privateKey, err := service.keyService.GetEd25519PrivateKey(&encryptionKey)
+
+	if err != nil {
+		return "", nil, derp.Wrap(err, location, "Extracting private key", encryptionKey.EncryptionKeyID)
+	}
+
+	return service.keyService.Ed25519KeyID(&encryptionKey), privateKey, nil
+}
+
 // ActivityPubActor returns an ActivityPub Actor object ** WHICH INCLUDES ENCRYPTION KEYS **
 // for the provided User.
 func (service *User) ActivityPubActor(session data.Session, userID primitive.ObjectID) (outbox.Actor, error) {
@@ -142,21 +177,25 @@ func (service *User) ActivityPubProfile(session data.Session, user *model.User)
 
 	const location = "service.User.ActivityPubProfile"
 
-	// Load the User's encryption key
-	encryptionKey := model.NewEncryptionKey()
+	// Load the User's public key(s)
+	publicKey, err := service.keyService.PublicKeyJSONLD(session, model.EncryptionKeyTypeUser, user.UserID, user.ActivityPubURL())
 
-	if err := service.keyService.LoadByParentID(session, model.EncryptionKeyTypeUser, user.UserID, &encryptionKey); err != nil {
+	if err != nil {
 		return nil, derp.Wrap(err, location, "Loading encryption key", user.UserID)
 	}
 
-	// Combine the profile and the public key
-	result := user.GetJSONLD()
-	result[vocab.PropertyPublicKey] = mapof.Any{
-		vocab.PropertyID:           user.ActivityPubPublicKeyURL(),
-		vocab.PropertyOwner:        user.ActivityPubURL(),
-		vocab.PropertyPublicKeyPEM: encryptionKey.PublicPEM,
+	// Load the User's Ed25519 key(s) for RFC 9421 signatures
+	assertionMethod, err := service.keyService.AssertionMethodJSONLD(session, model.EncryptionKeyTypeUser, user.UserID, user.ActivityPubURL())
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Loading Ed25519 key", user.UserID)
 	}
 
+	// Combine the profile and the public keys
+	result := user.GetJSONLD()
+	result[vocab.PropertyPublicKey] = publicKey
+	result["assertionMethod"] = assertionMethod
+
 	// If the domain allows it, append MLS messaging values as well.
 	domain := service.domainService.Get()
 
@@ -175,7 +214,41 @@ func (service *User) ActivityPubProfile(session data.Session, user *model.User)
 // so re-sends of the same profile state are idempotent for receivers that dedup by id.
 func (service *User) sendProfileUpdate(session data.Session, user *model.User) error {
 
-	const location = "service.User.sendProfileUpdate"
+	// Derive the activity's fragment id from the profile fingerprint
+	fragment := user.ProfileFingerprint
+	if len(fragment) > 16 {
+		fragment = fragment[:16]
+	}
+
+	return service.sendActorUpdate(session, user, fragment)
+}
+
+// RotateKey replaces the User's signing key with a newly generated key, then federates the new
+// publicKey to the User's followers via an Update of the actor.  The previous key continues to
+// be published (and verify) during its grace period.
+func (service *User) RotateKey(session data.Session, user *model.User) error {
+
+	const location = "service.User.RotateKey"
+
+	newKey, err := service.keyService.Rotate(session, model.EncryptionKeyTypeUser, user.UserID)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Rotating encryption key", user.UserID)
+	}
+
+	// The profile fingerprint does not include the publicKey, so use the new key's ID instead
+	if err := service.sendActorUpdate(session, user, "key-"+newKey.EncryptionKeyID.Hex()); err != nil {
+		return derp.Wrap(err, location, "Sending profile update", user.UserID)
+	}
+
+	return nil
+}
+
+// sendActorUpdate wraps the User's complete actor document in an ActivityPub Update, using
+// the provided fragment for its id, and hands it to the Outbox2 sender pipeline.
+func (service *User) sendActorUpdate(session data.Session, user *model.User, fragment string) error {
+
+	const location = "service.User.sendActorUpdate"
 
 	// Assemble the complete actor document (profile + publicKey + MLS)
 	object, err := service.ActivityPubProfile(session, user)
@@ -184,12 +257,6 @@ func (service *User) sendProfileUpdate(session data.Session, user *model.User) e
 		return derp.Wrap(err, location, "Assembling actor document", user.UserID)
 	}
 
-	// Derive the activity's fragment id from the profile fingerprint
-	fragment := user.ProfileFingerprint
-	if len(fragment) > 16 {
-		fragment = fragment[:16]
-	}
-
 	// Build the Update activity, addressed to this User's followers
 	activity := mapof.Any{
 		vocab.AtContext:      vocab.ContextTypeActivityStreams,
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"slices"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/mapof"
+)
+
+/******************************************
+ * Relationships (Mastodon API)
+ ******************************************/
+
+// Relationship calculates how the User is connected to the account at the provided URL,
+// using the User's Following, Follower, and Rule records.
+func (service *User) Relationship(session data.Session, user *model.User, actorID string) (model.Relationship, error) {
+
+	const location = "service.User.Relationship"
+
+	result := model.NewRelationship(actorID)
+	result.Note = user.AccountNote(actorID)
+	result.Endorsed = user.IsEndorsed(actorID)
+
+	// Does the User follow this account?
+	following := model.NewFollowing()
+
+	if err := service.followingService.LoadByURL(session, user.UserID, actorID, &following); err == nil {
+		result.Requested = following.IsPending()
+		result.Following = !result.Requested
+	} else if !derp.IsNotFound(err) {
+		return result, derp.Wrap(err, location, "Loading following", actorID)
+	}
+
+	// Does this account follow the User?
+	follower := model.NewFollower()
+
+	if err := service.followerService.LoadByActor(session, user.UserID, actorID, &follower); err == nil {
+		result.RequestedBy = follower.IsRequested()
+		result.FollowedBy = !result.RequestedBy
+	} else if !derp.IsNotFound(err) {
+		return result, derp.Wrap(err, location, "Loading follower", actorID)
+	}
+
+	// Does the User block or mute this account?
+	now := time.Now().Unix()
+	domainKeys := model.DomainMatchKeys(actorID)
+	actorKeys := slices.DeleteFunc(model.ActorMatchKeys(actorID), func(key string) bool {
+		return slices.Contains(domainKeys, key)
+	})
+
+	actorDisposition, err := service.ruleService.DispositionForKeys(session, user.UserID, actorKeys, now)
+
+	if err != nil {
+		return result, derp.Wrap(err, location, "Checking actor rules", actorID)
+	}
+
+	domainDisposition, err := service.ruleService.DispositionForKeys(session, user.UserID, domainKeys, now)
+
+	if err != nil {
+		return result, derp.Wrap(err, location, "Checking domain rules", actorID)
+	}
+
+	result.Blocking = actorDisposition.IsBlocked()
+	result.Muting = actorDisposition.IsMuted()
+	result.DomainBlocking = domainDisposition.IsBlocked()
+
+	return result, nil
+}
+
+// FamiliarFollowers returns the accounts that the User follows who also follow the account at
+// the provided URL.  Emissary only knows about follow relationships that touch this server,
+// so this includes 1) local Users who follow the account, and 2) when the account is a local
+// User, the remote actors who follow them.
+func (service *User) FamiliarFollowers(session data.Session, user *model.User, actorID string) ([]model.PersonLink, error) {
+
+	const location = "service.User.FamiliarFollowers"
+
+	result := make([]model.PersonLink, 0)
+
+	// Collect everyone that the User follows
+	followings, err := service.followingService.RangeByUserID(session, user.UserID)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Loading following", user.UserID)
+	}
+
+	familiar := mapof.NewBool()
+
+	for following := range followings {
+		if !following.IsPending() {
+			familiar[following.ProfileURL] = true
+		}
+	}
+
+	if len(familiar) == 0 {
+		return result, nil
+	}
+
+	// Local Users who follow this account (and whom the User follows)
+	followers, err := service.followingService.RangeByActorID(session, actorID)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Loading local followers", actorID)
+	}
+
+	for follower := range followers {
+
+		if follower.UserID == user.UserID {
+			continue
+		}
+
+		localUser := model.NewUser()
+
+		if err := service.LoadByID(session, follower.UserID, &localUser); err != nil {
+			continue
+		}
+
+		if familiar[localUser.ActivityPubURL()] {
+			result = append(result, localUser.PersonLink())
+			delete(familiar, localUser.ActivityPubURL())
+		}
+	}
+
+	// Remote actors who follow this account (only possible when it is a local User)
+	target := model.NewUser()
+
+	if err := service.LoadByProfileURL(session, actorID, &target); err != nil {
+
+		if derp.IsNotFound(err) {
+			return result, nil
+		}
+
+		return nil, derp.Wrap(err, location, "Loading user", actorID)
+	}
+
+	for follower := range service.followerService.RangeActiveByUserID(session, target.UserID) {
+		if familiar[follower.Actor.ProfileURL] {
+			result = append(result, follower.Actor)
+			delete(familiar, follower.Actor.ProfileURL)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"encoding/base32"
+	"strings"
+	"time"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/random"
+	"github.com/EmissarySocial/emissary/tools/totp"
+	"github.com/benpate/data"
+	"github.com/benpate/derp"
+)
+
+/******************************************
+ * Two-Factor Authentication
+ ******************************************/
+
+// BeginTwoFactor creates a new pending secret for the User to scan into their
+// authenticator app.  An existing pending secret is reused, so that reloading the
+// enrollment page does not invalidate a QR code that was already scanned.
+func (service *User) BeginTwoFactor(session data.Session, user *model.User) error {
+
+	const location = "service.User.BeginTwoFactor"
+
+	if user.TwoFactor.IsPending() {
+		return nil
+	}
+
+	secret, err := totp.GenerateSecret()
+
+	if err != nil {
+		return derp.Wrap(err, location, "Generating secret")
+	}
+
+	user.TwoFactor.PendingSecret = secret
+
+	if err := service.Save(session, user, "Began two-factor enrollment"); err != nil {
+		return derp.Wrap(err, location, "Saving user", user.UserID)
+	}
+
+	return nil
+}
+
+// EnrollTwoFactor confirms the User's pending secret with a code from their authenticator
+// app.  It returns a new set of recovery codes, which must be shown to the User exactly once.
+func (service *User) EnrollTwoFactor(session data.Session, user *model.User, code string) ([]string, error) {
+
+	const location = "service.User.EnrollTwoFactor"
+
+	// RULE: The User must have started an enrollment
+	if !user.TwoFactor.IsPending() {
+		return nil, derp.BadRequest(location, "Two-factor enrollment has not been started")
+	}
+
+	// RULE: The code must match the pending secret
+	if !user.TwoFactor.Activate(code, time.Now()) {
+		return nil, derp.Validation("Code is incorrect. Check the time on your device and try again.")
+	}
+
+	// New enrollments forget every previously remembered device
+	deviceKey, err := random.GenerateString(32)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Generating device key")
+	}
+
+	user.TwoFactor.DeviceKey = deviceKey
+
+	recoveryCodes, err := service.setRecoveryCodes(user)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Generating recovery codes")
+	}
+
+	if err := service.Save(session, user, "Enrolled two-factor authentication"); err != nil {
+		return nil, derp.Wrap(err, location, "Saving user", user.UserID)
+	}
+
+	return recoveryCodes, nil
+}
+
+// ResetRecoveryCodes replaces all of the User's recovery codes, and returns the new ones.
+func (service *User) ResetRecoveryCodes(session data.Session, user *model.User) ([]string, error) {
+
+	const location = "service.User.ResetRecoveryCodes"
+
+	// RULE: Recovery codes only exist alongside an active second factor
+	if !user.TwoFactor.IsActive() {
+		return nil, derp.BadRequest(location, "Two-factor authentication is not active")
+	}
+
+	recoveryCodes, err := service.setRecoveryCodes(user)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Generating recovery codes")
+	}
+
+	if err := service.Save(session, user, "Reset recovery codes"); err != nil {
+		return nil, derp.Wrap(err, location, "Saving user", user.UserID)
+	}
+
+	return recoveryCodes, nil
+}
+
+// RemoveTwoFactor removes the User's second factor, recovery codes, and remembered devices.
+func (service *User) RemoveTwoFactor(session data.Session, user *model.User) error {
+
+	const location = "service.User.RemoveTwoFactor"
+
+	user.TwoFactor = model.NewTwoFactor()
+
+	if err := service.Save(session, user, "Removed two-factor authentication"); err != nil {
+		return derp.Wrap(err, location, "Saving user", user.UserID)
+	}
+
+	return nil
+}
+
+// VerifyTwoFactor checks a code from the User's authenticator app, or one of their
+// recovery codes.  It returns the signin history event that describes which factor
+// was used, and FALSE if the code is not valid.
+func (service *User) VerifyTwoFactor(session data.Session, user *model.User, code string) (string, bool, error) {
+
+	const location = "service.User.VerifyTwoFactor"
+
+	var event string
+
+	switch {
+
+	case user.TwoFactor.ValidateCode(code, time.Now()):
+		event = model.SigninEventTwoFactorUse
+
+	case user.TwoFactor.UseRecoveryCode(code):
+		event = model.SigninEventRecoveryCodeUse
+
+	default:
+		return "", false, nil
+	}
+
+	// Save the used time step / recovery code so that it cannot be replayed
+	if err := service.Save(session, user, "Verified two-factor authentication"); err != nil {
+		return "", false, derp.Wrap(err, location, "Saving user", user.UserID)
+	}
+
+	return event, true, nil
+}
+
+// setRecoveryCodes generates a new set of recovery codes for the User, and returns
+// the plaintext values.  Only hashes are stored in the database.
+func (service *User) setRecoveryCodes(user *model.User) ([]string, error) {
+
+	const location = "service.User.setRecoveryCodes"
+
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	result := make([]string, model.TwoFactorRecoveryCodeCount)
+
+	for index := range result {
+
+		value, err := random.GenerateBytes(10)
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Generating random bytes")
+		}
+
+		code := strings.ToLower(encoding.EncodeToString(value))
+		result[index] = code[:5] + "-" + code[5:10]
+	}
+
+	user.TwoFactor.SetRecoveryCodes(result)
+	return result, nil
+}
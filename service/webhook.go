@@ -1,25 +1,46 @@
 package service
 
 import (
-	"context"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
 	"time"
 
 	"github.com/EmissarySocial/emissary/model"
+	"github.com/EmissarySocial/emissary/tools/hmac"
+	"github.com/EmissarySocial/emissary/tools/postcommit"
+	"github.com/EmissarySocial/emissary/tools/random"
 	"github.com/benpate/data"
 	"github.com/benpate/data/option"
 	"github.com/benpate/derp"
 	"github.com/benpate/exp"
-	"github.com/benpate/remote"
+	"github.com/benpate/rosetta/mapof"
 	"github.com/benpate/rosetta/schema"
 	"github.com/benpate/turbine/queue"
+	"github.com/benpate/uri"
 	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// webhookTimeout caps how long a single webhook delivery may run.
+const webhookTimeout = 30 * time.Second
+
+// webhookSecretLength is the number of characters in a newly generated webhook signing secret
+const webhookSecretLength = 32
+
+// webhookResponseMaxLength caps how much of a TargetURL's error response is copied into the delivery log
+const webhookResponseMaxLength = 256
+
 // Webhook service sends outbound webhooks
 type Webhook struct {
-	queue      *queue.Queue
-	newSession func(time.Duration) (data.Session, context.CancelFunc, error)
+	deliveryService *WebhookDelivery
+	queue           *queue.Queue
+	hostname        string
+	httpClient      *http.Client
 }
 
 // NewWebhook returns a new instance of the Webhook service
@@ -32,8 +53,13 @@ func NewWebhook() Webhook {
  ******************************************/
 
 func (service *Webhook) Refresh(factory *Factory) {
+	service.deliveryService = factory.WebhookDelivery()
 	service.queue = factory.Queue()
-	service.newSession = factory.Session
+	service.hostname = factory.Hostname()
+
+	// Webhooks follow the same network policy as Web Push: a production instance may only
+	// deliver to public addresses, while a local/dev instance may talk to itself.
+	service.httpClient = webhookHTTPClient(uri.IsLocalHostname(service.hostname))
 }
 
 /******************************************
@@ -82,6 +108,17 @@ func (service *Webhook) Save(session data.Session, webhook *model.Webhook, note
 
 	const location = "service.Webhook.Save"
 
+	// RULE: Every Webhook must have a secret to sign its payloads
+	if webhook.Secret == "" {
+		secret, err := random.GenerateString(webhookSecretLength)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Generating Webhook secret")
+		}
+
+		webhook.Secret = secret
+	}
+
 	// Validate the value (using the global webhook schema) before saving
 	if _, err := service.Schema().Validate(webhook); err != nil {
 		return derp.Wrap(err, location, "Validating Webhook using WebhookSchema", webhook)
@@ -99,9 +136,16 @@ func (service *Webhook) Save(session data.Session, webhook *model.Webhook, note
 // Delete removes an Webhook from the database (virtual delete)
 func (service *Webhook) Delete(session data.Session, webhook *model.Webhook, note string) error {
 
+	const location = "service.Webhook.Delete"
+
 	// Delete this Webhook
 	if err := service.collection(session).Delete(webhook, note); err != nil {
-		return derp.Wrap(err, "service.Webhook.Delete", "Deleting Webhook", webhook, note)
+		return derp.Wrap(err, location, "Deleting Webhook", webhook, note)
+	}
+
+	// Delete the delivery log for this Webhook
+	if err := service.deliveryService.DeleteByWebhook(session, webhook.WebhookID, note); err != nil {
+		return derp.Wrap(err, location, "Deleting WebhookDeliveries", webhook, note)
 	}
 
 	// Bueno!!
@@ -203,55 +247,215 @@ func (service *Webhook) QueryByEvent(session data.Session, event string) ([]mode
  * Send Webhooks
  ******************************************/
 
-// Send delivers the webhook to all the external webhook URLs that are listening to the given event
-func (service *Webhook) Send(getter model.WebhookDataGetter, events ...string) {
+// Send queues the webhook for delivery to all the external webhook URLs that are listening to the given events,
+// and to the User (or domain) that the event belongs to.
+// Each delivery is logged in a WebhookDelivery record, and sent (post-commit) by the "SendWebhook" task.
+func (service *Webhook) Send(session data.Session, getter model.WebhookDataGetter, events ...string) {
 
 	const location = "service.Webhook.Send"
 
-	if len(events) == 0 {
-		return
-	}
+	for _, event := range events {
 
-	go func() {
-
-		// Create a new (thread-safe) database session
-		session, cancel, err := service.newSession(time.Minute)
+		webhooks, err := service.QueryByEvent(session, event)
 
 		if err != nil {
-			derp.Report(derp.Wrap(err, location, "Connecting to database"))
-			return
+			derp.Report(derp.Wrap(err, location, "Querying webhooks", event))
+			continue
+		}
+
+		// RULE: Only send to webhooks that are listening to this User (or to the entire domain)
+		userID := getter.WebhookUserID()
+		webhooks = slices.DeleteFunc(webhooks, func(webhook model.Webhook) bool {
+			return !webhook.MatchesUser(userID)
+		})
+
+		if len(webhooks) == 0 {
+			continue
 		}
 
-		defer cancel()
+		// Calculate the data to send
+		payload := getter.GetWebhookData()
+		payload["event"] = event
 
-		for _, event := range events {
+		body, err := json.Marshal(payload)
 
-			webhooks, err := service.QueryByEvent(session, event)
+		if err != nil {
+			derp.Report(derp.Wrap(err, location, "Marshalling webhook payload", event))
+			continue
+		}
 
-			if err != nil {
-				derp.Report(derp.Wrap(err, location, "Querying webhooks", event))
-				continue
-			}
+		for _, webhook := range webhooks {
 
-			if len(webhooks) == 0 {
-				continue
+			delivery := model.NewWebhookDelivery()
+			delivery.WebhookID = webhook.WebhookID
+			delivery.Event = event
+			delivery.TargetURL = webhook.TargetURL
+			delivery.Body = string(body)
+
+			if err := service.queueDelivery(session, &delivery, "Created"); err != nil {
+				derp.Report(derp.Wrap(err, location, "Queuing webhook delivery", webhook.WebhookID, event))
 			}
+		}
+	}
+}
+
+// Replay creates a new WebhookDelivery that re-sends the payload of an existing delivery
+func (service *Webhook) Replay(session data.Session, original *model.WebhookDelivery) (model.WebhookDelivery, error) {
+
+	const location = "service.Webhook.Replay"
+
+	delivery := model.NewWebhookDelivery()
+	delivery.WebhookID = original.WebhookID
+	delivery.Event = original.Event
+	delivery.TargetURL = original.TargetURL
+	delivery.Body = original.Body
+	delivery.ReplayOf = original.WebhookDeliveryID
+
+	if err := service.queueDelivery(session, &delivery, "Replayed"); err != nil {
+		return delivery, derp.Wrap(err, location, "Queuing replayed delivery", original.WebhookDeliveryID)
+	}
+
+	return delivery, nil
+}
+
+// queueDelivery saves a new WebhookDelivery and (post-commit) queues the task that sends it
+func (service *Webhook) queueDelivery(session data.Session, delivery *model.WebhookDelivery, note string) error {
+
+	const location = "service.Webhook.queueDelivery"
+
+	if err := service.deliveryService.Save(session, delivery, note); err != nil {
+		return derp.Wrap(err, location, "Saving WebhookDelivery", delivery)
+	}
+
+	service.QueueAttempt(session, delivery, 0)
+	return nil
+}
+
+// QueueAttempt (post-commit) queues the "SendWebhook" task for a WebhookDelivery after the provided delay
+func (service *Webhook) QueueAttempt(session data.Session, delivery *model.WebhookDelivery, delaySeconds int) {
+
+	postcommit.Publish(
+		session,
+		service.queue,
+		"SendWebhook",
+		mapof.Any{
+			"hostname":          service.hostname,
+			"webhookDeliveryId": delivery.WebhookDeliveryID.Hex(),
+		},
+		queue.WithDelaySeconds(delaySeconds),
+	)
+}
 
-			// Calculate the data to send
-			data := getter.GetWebhookData()
-			data["event"] = event
+// Deliver makes a single, signed attempt to send a WebhookDelivery to its Webhook's TargetURL.
+// The outcome of the attempt is appended to the delivery log and saved, whether or not it succeeds.
+// An error is returned only if the attempt itself failed, or if the delivery log could not be saved.
+func (service *Webhook) Deliver(session data.Session, delivery *model.WebhookDelivery) error {
 
-			for _, webhook := range webhooks {
+	const location = "service.Webhook.Deliver"
 
-				txn := remote.Post(webhook.TargetURL).JSON(data)
+	// Load the Webhook so that we sign with its CURRENT secret and send to its CURRENT URL
+	webhook := model.NewWebhook()
 
-				if err := txn.Send(); err != nil {
-					derp.Report(derp.Wrap(err, location, "Sending webhook", webhook, data))
-					continue
-				}
+	if err := service.LoadByID(session, delivery.WebhookID, &webhook); err != nil {
 
-				log.Trace().Str("event", event).Msg("Webhook sent to " + webhook.TargetURL)
+		// If the Webhook has been removed, then there is nothing left to deliver to.
+		if derp.IsNotFound(err) {
+			delivery.StateID = model.WebhookDeliveryStateFailure
+
+			if err := service.deliveryService.Save(session, delivery, "Webhook removed"); err != nil {
+				return derp.Wrap(err, location, "Saving WebhookDelivery", delivery)
 			}
 		}
+
+		return derp.Wrap(err, location, "Loading Webhook", delivery.WebhookID)
+	}
+
+	delivery.TargetURL = webhook.TargetURL
+
+	// Send the request and record the outcome
+	attempt := service.send(&webhook, delivery)
+	delivery.AddAttempt(attempt)
+
+	if err := service.deliveryService.Save(session, delivery, "Attempt "+strconv.Itoa(delivery.AttemptCount())); err != nil {
+		return derp.Wrap(err, location, "Saving WebhookDelivery", delivery)
+	}
+
+	if !attempt.IsSuccess() {
+		return derp.Internal(location, "Webhook delivery failed", webhook.TargetURL, attempt.StatusCode, attempt.Error)
+	}
+
+	log.Trace().Str("event", delivery.Event).Msg("Webhook sent to " + webhook.TargetURL)
+	return nil
+}
+
+// send makes a single HTTP request to the Webhook's TargetURL, and reports what happened.
+func (service *Webhook) send(webhook *model.Webhook, delivery *model.WebhookDelivery) model.WebhookAttempt {
+
+	startTime := time.Now()
+
+	result := model.WebhookAttempt{
+		AttemptDate: startTime.Unix(),
+	}
+
+	request, err := http.NewRequest(http.MethodPost, webhook.TargetURL, bytes.NewBufferString(delivery.Body))
+
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	timestamp := strconv.FormatInt(startTime.Unix(), 10)
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "Emissary Webhooks (https://"+service.hostname+")")
+	request.Header.Set("X-Emissary-Event", delivery.Event)
+	request.Header.Set("X-Emissary-Delivery", delivery.WebhookDeliveryID.Hex())
+	request.Header.Set("X-Emissary-Timestamp", timestamp)
+	request.Header.Set("X-Emissary-Signature", webhookSignature(webhook.Secret, timestamp, delivery.Body))
+
+	// Fall back to a guarded client if the service was never refreshed (fail closed, not open).
+	client := service.httpClient
+	if client == nil {
+		client = webhookHTTPClient(false)
+	}
+
+	response, err := client.Do(request)
+	result.Duration = time.Since(startTime).Milliseconds()
+
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	defer func() {
+		if err := response.Body.Close(); err != nil {
+			derp.Report(derp.Wrap(err, "service.Webhook.send", "Closing response body", webhook.TargetURL))
+		}
 	}()
+
+	result.StatusCode = response.StatusCode
+
+	// Keep a short excerpt of any error response, so that admins can see WHY it was rejected
+	if !result.IsSuccess() {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, webhookResponseMaxLength))
+		result.Error = response.Status + " " + string(body)
+	}
+
+	return result
+}
+
+// webhookSignature returns the value of the X-Emissary-Signature header.  Receivers verify a
+// payload by computing HMAC-SHA256(secret, timestamp + "." + body) and comparing it to this value.
+// Including the timestamp lets receivers reject old payloads that are replayed by an attacker.
+func webhookSignature(secret string, timestamp string, body string) string {
+	signature, _ := hmac.Sign("sha256", secret, []byte(timestamp+"."+body))
+	return "sha256=" + hex.EncodeToString(signature)
+}
+
+// webhookHTTPClient returns the HTTP client used to deliver webhooks.  It uses the same
+// SSRF-hardened dialer as Web Push, so that a webhook cannot be pointed at an internal host.
+func webhookHTTPClient(allowPrivateIPs bool) *http.Client {
+	result := webPushHTTPClient(allowPrivateIPs)
+	result.Timeout = webhookTimeout
+	return result
 }
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"iter"
+
+	"github.com/EmissarySocial/emissary/model"
+	"github.com/benpate/data"
+	"github.com/benpate/data/option"
+	"github.com/benpate/derp"
+	"github.com/benpate/exp"
+	"github.com/benpate/rosetta/sliceof"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WebhookDelivery manages the delivery log of every payload sent to a Webhook
+type WebhookDelivery struct{}
+
+// NewWebhookDelivery returns a fully initialized WebhookDelivery service
+func NewWebhookDelivery() WebhookDelivery {
+	return WebhookDelivery{}
+}
+
+/******************************************
+ * Lifecycle Methods
+ ******************************************/
+
+// Refresh updates any stateful data that is cached inside this service.
+func (service *WebhookDelivery) Refresh(factory *Factory) {
+	// Nothing to refresh.
+}
+
+// Close stops any background processes controlled by this service
+func (service *WebhookDelivery) Close() {
+	// Nothin to do here.
+}
+
+/******************************************
+ * Common Data Methods
+ ******************************************/
+
+func (service *WebhookDelivery) collection(session data.Session) data.Collection {
+	return session.Collection("WebhookDelivery")
+}
+
+// Count returns the number of WebhookDeliveries that match the provided criteria
+func (service *WebhookDelivery) Count(session data.Session, criteria exp.Expression) (int64, error) {
+	return service.collection(session).Count(notDeleted(criteria))
+}
+
+// Query returns a slice of WebhookDeliveries that match the provided criteria
+func (service *WebhookDelivery) Query(session data.Session, criteria exp.Expression, options ...option.Option) (sliceof.Object[model.WebhookDelivery], error) {
+	result := make([]model.WebhookDelivery, 0)
+	err := service.collection(session).Query(&result, notDeleted(criteria), options...)
+	return result, err
+}
+
+// List returns an iterator of WebhookDeliveries that match the provided criteria
+func (service *WebhookDelivery) List(session data.Session, criteria exp.Expression, options ...option.Option) (data.Iterator, error) {
+	return service.collection(session).Iterator(notDeleted(criteria), options...)
+}
+
+// Range returns a Go 1.23 RangeFunc over the WebhookDeliveries that match the provided criteria
+func (service *WebhookDelivery) Range(session data.Session, criteria exp.Expression, options ...option.Option) (iter.Seq[model.WebhookDelivery], error) {
+
+	iter, err := service.List(session, criteria, options...)
+
+	if err != nil {
+		return nil, derp.Wrap(err, "service.WebhookDelivery.Range", "Creating iterator", criteria)
+	}
+
+	return RangeFunc(iter, model.NewWebhookDelivery), nil
+}
+
+// Load retrieves a WebhookDelivery from the database
+func (service *WebhookDelivery) Load(session data.Session, criteria exp.Expression, delivery *model.WebhookDelivery) error {
+
+	if err := service.collection(session).Load(notDeleted(criteria), delivery); err != nil {
+		return derp.Wrap(err, "service.WebhookDelivery.Load", "Loading WebhookDelivery", criteria)
+	}
+
+	return nil
+}
+
+// Save adds/updates a WebhookDelivery in the database
+func (service *WebhookDelivery) Save(session data.Session, delivery *model.WebhookDelivery, note string) error {
+
+	const location = "service.WebhookDelivery.Save"
+
+	// RULE: WebhookID is required
+	if delivery.WebhookID.IsZero() {
+		return derp.Validation("WebhookID is required", delivery)
+	}
+
+	if err := service.collection(session).Save(delivery, note); err != nil {
+		return derp.Wrap(err, location, "Saving WebhookDelivery", delivery, note)
+	}
+
+	return nil
+}
+
+// Delete removes a WebhookDelivery from the database (hard delete)
+func (service *WebhookDelivery) Delete(session data.Session, delivery *model.WebhookDelivery, note string) error {
+
+	const location = "service.WebhookDelivery.Delete"
+
+	// Hard delete, never virtual: a delivery log is a record of something that already happened,
+	// so there is nothing to restore it to.
+	if err := service.collection(session).HardDelete(exp.Equal("_id", delivery.WebhookDeliveryID)); err != nil {
+		return derp.Wrap(err, location, "Deleting WebhookDelivery", delivery, note)
+	}
+
+	return nil
+}
+
+/******************************************
+ * Custom Queries
+ ******************************************/
+
+// LoadByID loads a single WebhookDelivery that belongs to the provided Webhook
+func (service *WebhookDelivery) LoadByID(session data.Session, webhookID primitive.ObjectID, deliveryID primitive.ObjectID, delivery *model.WebhookDelivery) error {
+
+	criteria := exp.Equal("_id", deliveryID).AndEqual("webhookId", webhookID)
+
+	if err := service.Load(session, criteria, delivery); err != nil {
+		return derp.Wrap(err, "service.WebhookDelivery.LoadByID", "Loading WebhookDelivery", webhookID, deliveryID)
+	}
+
+	return nil
+}
+
+// LoadByToken loads a single WebhookDelivery using a string representation of its ID
+func (service *WebhookDelivery) LoadByToken(session data.Session, webhookID primitive.ObjectID, token string, delivery *model.WebhookDelivery) error {
+
+	deliveryID, err := primitive.ObjectIDFromHex(token)
+
+	if err != nil {
+		return derp.BadRequest("service.WebhookDelivery.LoadByToken", "Invalid WebhookDeliveryID", token)
+	}
+
+	return service.LoadByID(session, webhookID, deliveryID, delivery)
+}
+
+// LoadPending loads a WebhookDelivery that is still waiting to be delivered.
+func (service *WebhookDelivery) LoadPending(session data.Session, deliveryID primitive.ObjectID, delivery *model.WebhookDelivery) error {
+
+	criteria := exp.Equal("_id", deliveryID).AndEqual("stateId", model.WebhookDeliveryStatePending)
+
+	if err := service.Load(session, criteria, delivery); err != nil {
+		return derp.Wrap(err, "service.WebhookDelivery.LoadPending", "Loading pending WebhookDelivery", deliveryID)
+	}
+
+	return nil
+}
+
+// QueryByWebhook returns the most recent WebhookDeliveries for the provided Webhook, newest first
+func (service *WebhookDelivery) QueryByWebhook(session data.Session, webhookID primitive.ObjectID, maxRows int) (sliceof.Object[model.WebhookDelivery], error) {
+	criteria := exp.Equal("webhookId", webhookID)
+	return service.Query(session, criteria, option.SortDesc("createDate"), option.MaxRows(int64(maxRows)))
+}
+
+// DeleteByWebhook removes the entire delivery log for the provided Webhook
+func (service *WebhookDelivery) DeleteByWebhook(session data.Session, webhookID primitive.ObjectID, note string) error {
+
+	const location = "service.WebhookDelivery.DeleteByWebhook"
+
+	if err := service.collection(session).HardDelete(exp.Equal("webhookId", webhookID)); err != nil {
+		return derp.Wrap(err, location, "Deleting WebhookDeliveries", webhookID, note)
+	}
+
+	return nil
+}
+
+// PurgeBefore removes all WebhookDeliveries that were created before the provided cutoff (in Unix MILLISECONDS)
+func (service *WebhookDelivery) PurgeBefore(session data.Session, cutoffMillis int64) error {
+
+	const location = "service.WebhookDelivery.PurgeBefore"
+
+	criteria := exp.LessThan("createDate", cutoffMillis)
+
+	if err := service.collection(session).HardDelete(criteria); err != nil {
+		return derp.Wrap(err, location, "Purging old WebhookDeliveries", cutoffMillis)
+	}
+
+	return nil
+}
@@ -31,15 +31,15 @@ func Actor(document streams.Document) map[string]any {
 		vocab.PropertyFeatured:   document.Featured().String(),
 		vocab.PropertyFollowers:  document.Followers().String(),
 		vocab.PropertyFollowing:  document.Following().String(),
-		vocab.PropertyPublicKey:  document.PublicKey().Value(),
 	}
 
 	// Cryptography
-	if publicKey := document.PublicKey(); publicKey.NotNil() {
-		result[vocab.PropertyPublicKey] = map[string]any{
-			vocab.PropertyID:           publicKey.ID(),
-			vocab.PropertyPublicKeyPEM: publicKey.PublicKeyPEM(),
-		}
+	if publicKey := PublicKey(document.Get(vocab.PropertyPublicKey)); publicKey != nil {
+		result[vocab.PropertyPublicKey] = publicKey
+	}
+
+	if assertionMethod := document.Get("assertionMethod"); assertionMethod.NotNil() {
+		result["assertionMethod"] = AssertionMethod(assertionMethod)
 	}
 
 	// Migration Data
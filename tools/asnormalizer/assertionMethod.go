@@ -0,0 +1,30 @@
+package asnormalizer
+
+import (
+	"github.com/EmissarySocial/emissary/tools/multikey"
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+)
+
+// AssertionMethod normalizes the Multikey entries (FEP-521a) from an Actor's
+// "assertionMethod" property.  Other key types are discarded.
+func AssertionMethod(document streams.Document) []map[string]any {
+
+	result := make([]map[string]any, 0, document.Len())
+
+	for key := range document.Range() {
+
+		if key.Type() != multikey.Type {
+			continue
+		}
+
+		result = append(result, map[string]any{
+			vocab.PropertyID:     key.ID(),
+			vocab.PropertyType:   multikey.Type,
+			"controller":         key.Get("controller").String(),
+			"publicKeyMultibase": key.Get("publicKeyMultibase").String(),
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,39 @@
+package asnormalizer
+
+import (
+	"github.com/benpate/hannibal/streams"
+	"github.com/benpate/hannibal/vocab"
+)
+
+// PublicKey normalizes every key from an Actor's "publicKey" property.  Actors publish
+// retired keys alongside the current one during a key rotation, so all of them are kept
+// in order for signatures made with an older key to verify.  A single key is returned
+// as a map (the common case) and multiple keys are returned as a slice.
+func PublicKey(document streams.Document) any {
+
+	result := make([]map[string]any, 0, document.Len())
+
+	for key := range document.Range() {
+
+		if key.ID() == "" {
+			continue
+		}
+
+		result = append(result, map[string]any{
+			vocab.PropertyID:           key.ID(),
+			vocab.PropertyOwner:        key.Get(vocab.PropertyOwner).String(),
+			vocab.PropertyPublicKeyPEM: key.PublicKeyPEM(),
+		})
+	}
+
+	switch len(result) {
+
+	case 0:
+		return nil
+
+	case 1:
+		return result[0]
+	}
+
+	return result
+}
@@ -0,0 +1,214 @@
+// Package blurhash encodes images into BlurHash strings: compact placeholders that
+// Mastodon clients (and other Fediverse software) display while the real image loads.
+// https://github.com/woltapp/blurhash/blob/master/Algorithm.md
+package blurhash
+
+import (
+	"image"
+	"math"
+	"strings"
+
+	"github.com/benpate/derp"
+)
+
+// maxSamples is the largest number of pixels sampled along each axis.  A BlurHash only
+// holds a handful of frequencies, so larger images are sampled on an evenly spaced grid
+// rather than reading every pixel.
+const maxSamples = 64
+
+// characters is the base83 alphabet used by BlurHash
+const characters = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// Encode returns the BlurHash for an image, using the given number of horizontal
+// and vertical components (each between 1 and 9).  Mastodon uses 4 x 3.
+func Encode(img image.Image, xComponents int, yComponents int) (string, error) {
+
+	const location = "blurhash.Encode"
+
+	if (xComponents < 1) || (xComponents > 9) || (yComponents < 1) || (yComponents > 9) {
+		return "", derp.Internal(location, "Components must be between 1 and 9", xComponents, yComponents)
+	}
+
+	bounds := img.Bounds()
+
+	if bounds.Empty() {
+		return "", derp.BadRequest(location, "Image is empty")
+	}
+
+	pixels := samplePixels(img)
+
+	// Calculate each component (the DC component is at index 0)
+	factors := make([][3]float64, 0, xComponents*yComponents)
+
+	for y := 0; y < yComponents; y++ {
+		for x := 0; x < xComponents; x++ {
+			factors = append(factors, multiplyBasisFunction(pixels, x, y))
+		}
+	}
+
+	dc := factors[0]
+	ac := factors[1:]
+
+	result := strings.Builder{}
+
+	// Size flag
+	result.WriteString(encode83((xComponents-1)+(yComponents-1)*9, 1))
+
+	// Quantized maximum AC value
+	maximumValue := 1.0
+
+	if len(ac) > 0 {
+
+		actualMaximum := 0.0
+
+		for _, factor := range ac {
+			actualMaximum = math.Max(actualMaximum, math.Abs(factor[0]))
+			actualMaximum = math.Max(actualMaximum, math.Abs(factor[1]))
+			actualMaximum = math.Max(actualMaximum, math.Abs(factor[2]))
+		}
+
+		quantizedMaximum := int(math.Max(0, math.Min(82, math.Floor(actualMaximum*166-0.5))))
+		maximumValue = float64(quantizedMaximum+1) / 166
+		result.WriteString(encode83(quantizedMaximum, 1))
+
+	} else {
+		result.WriteString(encode83(0, 1))
+	}
+
+	// Average color
+	result.WriteString(encode83(encodeDC(dc), 4))
+
+	// Remaining components
+	for _, factor := range ac {
+		result.WriteString(encode83(encodeAC(factor, maximumValue), 2))
+	}
+
+	return result.String(), nil
+}
+
+// samplePixels returns the image's colors in linear RGB, sampled on a grid of
+// at most maxSamples x maxSamples pixels
+func samplePixels(img image.Image) [][][3]float64 {
+
+	bounds := img.Bounds()
+	width := min(bounds.Dx(), maxSamples)
+	height := min(bounds.Dy(), maxSamples)
+
+	result := make([][][3]float64, height)
+
+	for y := 0; y < height; y++ {
+
+		result[y] = make([][3]float64, width)
+		sourceY := bounds.Min.Y + (y * bounds.Dy() / height)
+
+		for x := 0; x < width; x++ {
+
+			sourceX := bounds.Min.X + (x * bounds.Dx() / width)
+			r, g, b, _ := img.At(sourceX, sourceY).RGBA()
+
+			result[y][x] = [3]float64{
+				sRGBToLinear(int(r >> 8)),
+				sRGBToLinear(int(g >> 8)),
+				sRGBToLinear(int(b >> 8)),
+			}
+		}
+	}
+
+	return result
+}
+
+// multiplyBasisFunction calculates a single component of the BlurHash
+func multiplyBasisFunction(pixels [][][3]float64, xComponent int, yComponent int) [3]float64 {
+
+	height := len(pixels)
+	width := len(pixels[0])
+
+	normalization := 2.0
+	if (xComponent == 0) && (yComponent == 0) {
+		normalization = 1.0
+	}
+
+	result := [3]float64{}
+
+	for y := 0; y < height; y++ {
+
+		basisY := math.Cos(math.Pi * float64(yComponent) * float64(y) / float64(height))
+
+		for x := 0; x < width; x++ {
+
+			basis := basisY * math.Cos(math.Pi*float64(xComponent)*float64(x)/float64(width))
+
+			result[0] += basis * pixels[y][x][0]
+			result[1] += basis * pixels[y][x][1]
+			result[2] += basis * pixels[y][x][2]
+		}
+	}
+
+	scale := normalization / float64(width*height)
+
+	result[0] *= scale
+	result[1] *= scale
+	result[2] *= scale
+
+	return result
+}
+
+// encodeDC encodes the average color of the image
+func encodeDC(value [3]float64) int {
+	r := linearToSRGB(value[0])
+	g := linearToSRGB(value[1])
+	b := linearToSRGB(value[2])
+	return (r << 16) + (g << 8) + b
+}
+
+// encodeAC encodes a single (non-average) component of the image
+func encodeAC(value [3]float64, maximumValue float64) int {
+
+	quantize := func(component float64) int {
+		return int(math.Max(0, math.Min(18, math.Floor(signPow(component/maximumValue, 0.5)*9+9.5))))
+	}
+
+	return quantize(value[0])*19*19 + quantize(value[1])*19 + quantize(value[2])
+}
+
+// encode83 encodes a value as a fixed-length base83 string
+func encode83(value int, length int) string {
+
+	result := make([]byte, length)
+
+	for index := length - 1; index >= 0; index-- {
+		result[index] = characters[value%83]
+		value = value / 83
+	}
+
+	return string(result)
+}
+
+// sRGBToLinear converts an 8-bit sRGB channel into linear RGB (0.0 - 1.0)
+func sRGBToLinear(value int) float64 {
+
+	v := float64(value) / 255
+
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB converts a linear RGB channel (0.0 - 1.0) into 8-bit sRGB
+func linearToSRGB(value float64) int {
+
+	v := math.Max(0, math.Min(1, value))
+
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+// signPow raises the absolute value to a power, keeping the original sign
+func signPow(value float64, exponent float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(value), exponent), value)
+}
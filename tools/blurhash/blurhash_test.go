@@ -0,0 +1,67 @@
+package blurhash
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode_SolidColor(t *testing.T) {
+
+	img := image.NewRGBA(image.Rect(0, 0, 32, 24))
+
+	for y := 0; y < 24; y++ {
+		for x := 0; x < 32; x++ {
+			img.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+
+	// Size flag for 4 x 3 components, followed by the average color (pure red)
+	result, err := Encode(img, 4, 3)
+	require.Nil(t, err)
+	require.Equal(t, 28, len(result))
+	require.Equal(t, "L", result[:1])
+	require.Equal(t, "TI:j", result[2:6])
+}
+
+func TestEncode_Gradient(t *testing.T) {
+
+	// Larger than maxSamples, so the image is sampled on a grid
+	img := image.NewRGBA(image.Rect(0, 0, 300, 200))
+
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 300; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 255 / 300), G: uint8(y * 255 / 200), B: 128, A: 255})
+		}
+	}
+
+	result, err := Encode(img, 4, 3)
+	require.Nil(t, err)
+	require.Equal(t, 28, len(result))
+	require.Equal(t, "L", result[:1])
+	require.NotEqual(t, strings.Repeat("fQ", 11), result[6:]) // "fQ" is an AC component of zero
+}
+
+func TestEncode_Errors(t *testing.T) {
+
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+
+	_, err := Encode(img, 0, 3)
+	require.NotNil(t, err)
+
+	_, err = Encode(img, 4, 10)
+	require.NotNil(t, err)
+
+	_, err = Encode(image.NewRGBA(image.Rect(0, 0, 0, 0)), 4, 3)
+	require.NotNil(t, err)
+}
+
+func TestEncode83(t *testing.T) {
+	require.Equal(t, "0", encode83(0, 1))
+	require.Equal(t, "~", encode83(82, 1))
+	require.Equal(t, "10", encode83(83, 2))
+	require.Equal(t, "TI:j", encode83(0xFF0000, 4))
+}
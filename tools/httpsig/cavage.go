@@ -0,0 +1,71 @@
+package httpsig
+
+import (
+	"crypto"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/benpate/derp"
+)
+
+// SignCavage adds a draft-cavage-http-signatures-12 "Signature" header to an outbound request,
+// covering the request target, host, date, and (for requests with a body) digest.  This is
+// the scheme that Mastodon and most other fediverse servers have verified for years, so it is
+// the fallback whenever a remote server rejects an RFC 9421 signature.
+func SignCavage(request *http.Request, body []byte, keyID string, privateKey crypto.Signer, now time.Time) error {
+
+	const location = "httpsig.SignCavage"
+
+	algorithm := algorithmForKey(privateKey)
+
+	if algorithm == "" {
+		return derp.Internal(location, "Unsupported signing key", keyID)
+	}
+
+	host := request.Host
+
+	if host == "" {
+		host = request.URL.Host
+	}
+
+	request.Header.Set("Date", now.UTC().Format(http.TimeFormat))
+
+	headers := []string{"(request-target)", "host", "date"}
+	values := []string{
+		strings.ToLower(request.Method) + " " + request.URL.RequestURI(),
+		host,
+		request.Header.Get("Date"),
+	}
+
+	if len(body) > 0 {
+		request.Header.Set(HeaderDigest, Digest(body))
+		headers = append(headers, "digest")
+		values = append(values, request.Header.Get(HeaderDigest))
+	}
+
+	// Build the signing string
+	lines := make([]string, len(headers))
+
+	for index := range headers {
+		lines[index] = headers[index] + ": " + values[index]
+	}
+
+	signature, err := signMessage(privateKey, []byte(strings.Join(lines, "\n")))
+
+	if err != nil {
+		return derp.Wrap(err, location, "Signing request", keyID)
+	}
+
+	// Older servers only recognize "rsa-sha256", so RSA keys keep using that name
+	cavageAlgorithm := "hs2019"
+
+	if algorithm == AlgorithmRSAPKCS1SHA256 {
+		cavageAlgorithm = "rsa-sha256"
+	}
+
+	request.Header.Set(HeaderSignature, `keyId="`+keyID+`",algorithm="`+cavageAlgorithm+`",headers="`+strings.Join(headers, " ")+`",signature="`+base64.StdEncoding.EncodeToString(signature)+`"`)
+
+	return nil
+}
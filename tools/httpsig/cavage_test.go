@@ -0,0 +1,64 @@
+package httpsig
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignCavage(t *testing.T) {
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+
+	body := []byte(`{"type":"Follow"}`)
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	request, err := http.NewRequest(http.MethodPost, "https://remote.example/users/bob/inbox?x=1", nil)
+	require.Nil(t, err)
+	require.Nil(t, SignCavage(request, body, "https://example.com/@alice#main-key", privateKey, now))
+
+	require.Equal(t, "Tue, 02 Jan 2024 03:04:05 GMT", request.Header.Get("Date"))
+	require.Equal(t, Digest(body), request.Header.Get(HeaderDigest))
+
+	// Parse the Signature header
+	header := request.Header.Get(HeaderSignature)
+	matches := regexp.MustCompile(`^keyId="([^"]+)",algorithm="([^"]+)",headers="([^"]+)",signature="([^"]+)"$`).FindStringSubmatch(header)
+	require.Equal(t, 5, len(matches), header)
+	require.Equal(t, "https://example.com/@alice#main-key", matches[1])
+	require.Equal(t, "rsa-sha256", matches[2])
+	require.Equal(t, "(request-target) host date digest", matches[3])
+
+	// Verify the signature against the expected signing string
+	signingString := "(request-target): post /users/bob/inbox?x=1\n" +
+		"host: remote.example\n" +
+		"date: Tue, 02 Jan 2024 03:04:05 GMT\n" +
+		"digest: " + Digest(body)
+
+	signature, err := base64.StdEncoding.DecodeString(matches[4])
+	require.Nil(t, err)
+
+	digest := sha256.Sum256([]byte(signingString))
+	require.Nil(t, rsa.VerifyPKCS1v15(&privateKey.PublicKey, crypto.SHA256, digest[:], signature))
+}
+
+func TestSignCavage_NoBody(t *testing.T) {
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+
+	request, err := http.NewRequest(http.MethodGet, "https://remote.example/users/bob", nil)
+	require.Nil(t, err)
+	require.Nil(t, SignCavage(request, nil, "key", privateKey, time.Now()))
+
+	require.Equal(t, "", request.Header.Get(HeaderDigest))
+	require.Contains(t, request.Header.Get(HeaderSignature), `headers="(request-target) host date"`)
+}
@@ -0,0 +1,75 @@
+package httpsig
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+
+	"github.com/benpate/derp"
+)
+
+// ContentDigest returns an RFC 9530 "Content-Digest" header value for the provided body
+func ContentDigest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "sha-256=:" + base64.StdEncoding.EncodeToString(sum[:]) + ":"
+}
+
+// Digest returns an RFC 3230 "Digest" header value for the provided body (used by draft-cavage)
+func Digest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// VerifyContentDigest confirms that an RFC 9530 "Content-Digest" header matches the provided body.
+// Every supported digest in the header must match, and at least one supported digest is required.
+func VerifyContentDigest(header string, body []byte) error {
+
+	const location = "httpsig.VerifyContentDigest"
+
+	members, err := parseDictionary(header)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Parsing Content-Digest header", header)
+	}
+
+	verified := false
+
+	for _, member := range members {
+
+		expected, ok := member.Item.Value.([]byte)
+
+		if member.IsInnerList || !ok {
+			return derp.BadRequest(location, "Content-Digest values must be byte sequences", header)
+		}
+
+		var actual []byte
+
+		switch member.Key {
+
+		case "sha-256":
+			sum := sha256.Sum256(body)
+			actual = sum[:]
+
+		case "sha-512":
+			sum := sha512.Sum512(body)
+			actual = sum[:]
+
+		default:
+			// Ignore algorithms that we do not support
+			continue
+		}
+
+		if subtle.ConstantTimeCompare(expected, actual) != 1 {
+			return derp.BadRequest(location, "Content-Digest does not match the request body", member.Key)
+		}
+
+		verified = true
+	}
+
+	if !verified {
+		return derp.BadRequest(location, "Content-Digest does not include a supported algorithm", header)
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package httpsig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentDigest(t *testing.T) {
+
+	// Example from RFC 9530, Appendix B
+	body := []byte(`{"hello": "world"}`)
+	require.Equal(t, "sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:", ContentDigest(body))
+	require.Equal(t, "SHA-256=X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=", Digest(body))
+}
+
+func TestVerifyContentDigest(t *testing.T) {
+
+	body := []byte(`{"hello": "world"}`)
+
+	// Single digest
+	require.Nil(t, VerifyContentDigest(ContentDigest(body), body))
+
+	// Multiple digests, including one we do not support
+	require.Nil(t, VerifyContentDigest("unixsum=:AAAA:, "+ContentDigest(body), body))
+
+	// Wrong body
+	require.NotNil(t, VerifyContentDigest(ContentDigest(body), []byte(`{}`)))
+
+	// No supported digests
+	require.NotNil(t, VerifyContentDigest("unixsum=:AAAA:", body))
+
+	// Missing header
+	require.NotNil(t, VerifyContentDigest("", body))
+
+	// Malformed header
+	require.NotNil(t, VerifyContentDigest("sha-256=X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=", body))
+}
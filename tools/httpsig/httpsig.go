@@ -0,0 +1,65 @@
+// Package httpsig signs and verifies HTTP requests using RFC 9421 HTTP Message Signatures,
+// and signs requests using the older draft-cavage HTTP Signatures that most of the
+// fediverse still expects.  Servers that support both are reached by "double-knocking":
+// sign with RFC 9421 first, and retry with draft-cavage if the server responds 401.
+package httpsig
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"net/http"
+)
+
+// Scheme names the kind of HTTP signature that a request is signed with
+type Scheme string
+
+// SchemeRFC9421 identifies RFC 9421 HTTP Message Signatures
+const SchemeRFC9421 Scheme = "rfc9421"
+
+// SchemeCavage identifies draft-cavage-http-signatures-12
+const SchemeCavage Scheme = "cavage"
+
+// AlgorithmEd25519 is the RFC 9421 algorithm name for EdDSA using curve edwards25519
+const AlgorithmEd25519 = "ed25519"
+
+// AlgorithmRSAPKCS1SHA256 is the RFC 9421 algorithm name for RSASSA-PKCS1-v1_5 using SHA-256
+const AlgorithmRSAPKCS1SHA256 = "rsa-v1_5-sha256"
+
+// HeaderSignature contains the signature(s) for a request (both schemes use this header)
+const HeaderSignature = "Signature"
+
+// HeaderSignatureInput contains the RFC 9421 signature parameters for each signature
+const HeaderSignatureInput = "Signature-Input"
+
+// HeaderContentDigest contains the RFC 9530 digest of the request body
+const HeaderContentDigest = "Content-Digest"
+
+// HeaderDigest contains the RFC 3230 digest of the request body (used by draft-cavage)
+const HeaderDigest = "Digest"
+
+// defaultLabel is the label used for signatures created by this package
+const defaultLabel = "sig1"
+
+// PublicKeyFinder returns the public key for a signature's keyid
+type PublicKeyFinder func(keyID string) (crypto.PublicKey, error)
+
+// IsRFC9421 returns TRUE if the request includes an RFC 9421 signature
+func IsRFC9421(request *http.Request) bool {
+	return request.Header.Get(HeaderSignatureInput) != ""
+}
+
+// algorithmForKey returns the RFC 9421 algorithm name for a private or public key,
+// or an empty string if the key type is not supported.
+func algorithmForKey(key any) string {
+
+	switch key.(type) {
+	case ed25519.PrivateKey, ed25519.PublicKey:
+		return AlgorithmEd25519
+
+	case *rsa.PrivateKey, *rsa.PublicKey:
+		return AlgorithmRSAPKCS1SHA256
+	}
+
+	return ""
+}
@@ -0,0 +1,374 @@
+package httpsig
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/benpate/derp"
+)
+
+// MaxAge is the oldest "created" timestamp that Verify will accept
+const MaxAge = 12 * time.Hour
+
+// ClockSkew is how far into the future a "created" timestamp may be, to allow for clock drift
+const ClockSkew = time.Hour
+
+// Sign adds RFC 9421 "Signature-Input" and "Signature" headers to an outbound request.
+// Requests with a body also receive a "Content-Digest" header, which is covered by the
+// signature.  The signing key must be an ed25519.PrivateKey or an *rsa.PrivateKey.
+func Sign(request *http.Request, body []byte, keyID string, privateKey crypto.Signer, created time.Time) error {
+
+	const location = "httpsig.Sign"
+
+	algorithm := algorithmForKey(privateKey)
+
+	if algorithm == "" {
+		return derp.Internal(location, "Unsupported signing key", keyID)
+	}
+
+	// Cover the request line, plus the body (via its digest) if there is one
+	components := []sfItem{
+		{Value: "@method"},
+		{Value: "@target-uri"},
+	}
+
+	if len(body) > 0 {
+		request.Header.Set(HeaderContentDigest, ContentDigest(body))
+		components = append(components, sfItem{Value: "content-digest"})
+	}
+
+	params := []sfParam{
+		{Key: "created", Value: created.Unix()},
+		{Key: "keyid", Value: keyID},
+		{Key: "alg", Value: algorithm},
+	}
+
+	// Calculate the signature base
+	base, err := signatureBase(request, "", components, params)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Creating signature base")
+	}
+
+	signature, err := signMessage(privateKey, []byte(base))
+
+	if err != nil {
+		return derp.Wrap(err, location, "Signing request", keyID)
+	}
+
+	request.Header.Set(HeaderSignatureInput, defaultLabel+"="+serializeInnerList(components, params))
+	request.Header.Set(HeaderSignature, defaultLabel+"="+serializeBareItem(signature))
+
+	return nil
+}
+
+// Verify validates the RFC 9421 signature on an inbound request, and returns the keyid that signed it.
+// `host` is the scheme and authority of this server (e.g. "https://example.com"), used to reconstruct
+// the target URI of server requests.  Signatures must cover the method and target URI, and must also
+// cover the "Content-Digest" header (which is verified against `body`) when the request has a body.
+func Verify(request *http.Request, body []byte, host string, finder PublicKeyFinder, now time.Time) (string, error) {
+
+	const location = "httpsig.Verify"
+
+	// Find the first signature that has both an input and a value
+	label, input, signature, err := findSignature(request)
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Reading signature headers")
+	}
+
+	keyID, _ := input.Item.Param("keyid")
+	keyIDString, _ := keyID.(string)
+
+	if keyIDString == "" {
+		return "", derp.BadRequest(location, "Signature must include a keyid", label)
+	}
+
+	// Validate the signature lifetime
+	created, _ := input.Item.Param("created")
+	createdUnix, isInteger := created.(int64)
+
+	if !isInteger {
+		return "", derp.BadRequest(location, "Signature must include a created timestamp", label)
+	}
+
+	createdTime := time.Unix(createdUnix, 0)
+
+	if createdTime.Before(now.Add(-MaxAge)) {
+		return "", derp.BadRequest(location, "Signature is too old", label, createdUnix)
+	}
+
+	if createdTime.After(now.Add(ClockSkew)) {
+		return "", derp.BadRequest(location, "Signature is created in the future", label, createdUnix)
+	}
+
+	if expires, exists := input.Item.Param("expires"); exists {
+		if expiresUnix, isInteger := expires.(int64); !isInteger || time.Unix(expiresUnix, 0).Before(now) {
+			return "", derp.BadRequest(location, "Signature has expired", label)
+		}
+	}
+
+	// Validate the covered components
+	covered := make(map[string]bool, len(input.InnerList))
+
+	for _, component := range input.InnerList {
+		if name, isString := component.Value.(string); isString {
+			covered[name] = true
+		}
+	}
+
+	if !covered["@method"] || !covered["@target-uri"] {
+		return "", derp.BadRequest(location, "Signature must cover @method and @target-uri", label)
+	}
+
+	if len(body) > 0 {
+
+		if !covered["content-digest"] {
+			return "", derp.BadRequest(location, "Signature must cover the Content-Digest of the request body", label)
+		}
+
+		if err := VerifyContentDigest(request.Header.Get(HeaderContentDigest), body); err != nil {
+			return "", derp.Wrap(err, location, "Verifying Content-Digest")
+		}
+	}
+
+	// Locate the public key
+	publicKey, err := finder(keyIDString)
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Loading public key", keyIDString)
+	}
+
+	// RULE: If the signer names an algorithm, it must match the key
+	if algorithm, exists := input.Item.Param("alg"); exists {
+		if algorithm != algorithmForKey(publicKey) {
+			return "", derp.BadRequest(location, "Signature algorithm does not match key", label, algorithm)
+		}
+	}
+
+	// Verify the signature
+	base, err := signatureBase(request, host, input.InnerList, input.Item.Params)
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Creating signature base", label)
+	}
+
+	if err := verifyMessage(publicKey, []byte(base), signature); err != nil {
+		return "", derp.Wrap(err, location, "Invalid signature", label, keyIDString)
+	}
+
+	return keyIDString, nil
+}
+
+// KeyID returns the keyid of the first RFC 9421 signature on a request, without verifying it.
+// It returns an empty string if the request has no readable RFC 9421 signature.
+func KeyID(request *http.Request) string {
+
+	_, input, _, err := findSignature(request)
+
+	if err != nil {
+		return ""
+	}
+
+	keyID, _ := input.Item.Param("keyid")
+	result, _ := keyID.(string)
+	return result
+}
+
+// findSignature returns the first labeled signature that appears in both
+// the "Signature-Input" and the "Signature" headers.
+func findSignature(request *http.Request) (string, sfMember, []byte, error) {
+
+	const location = "httpsig.findSignature"
+
+	inputs, err := parseDictionary(strings.Join(request.Header.Values(HeaderSignatureInput), ", "))
+
+	if err != nil {
+		return "", sfMember{}, nil, derp.Wrap(err, location, "Parsing Signature-Input header")
+	}
+
+	signatures, err := parseDictionary(strings.Join(request.Header.Values(HeaderSignature), ", "))
+
+	if err != nil {
+		return "", sfMember{}, nil, derp.Wrap(err, location, "Parsing Signature header")
+	}
+
+	for _, input := range inputs {
+
+		if !input.IsInnerList {
+			continue
+		}
+
+		for _, signature := range signatures {
+
+			if signature.Key != input.Key {
+				continue
+			}
+
+			if value, isBytes := signature.Item.Value.([]byte); isBytes && !signature.IsInnerList {
+				return input.Key, input, value, nil
+			}
+		}
+	}
+
+	return "", sfMember{}, nil, derp.BadRequest(location, "Request does not include an RFC 9421 signature")
+}
+
+// signatureBase creates the RFC 9421 signature base for a request
+func signatureBase(request *http.Request, host string, components []sfItem, params []sfParam) (string, error) {
+
+	const location = "httpsig.signatureBase"
+
+	var builder strings.Builder
+
+	for _, component := range components {
+
+		name, isString := component.Value.(string)
+
+		if !isString {
+			return "", derp.BadRequest(location, "Component identifiers must be strings", component.Value)
+		}
+
+		// Component parameters (like ;sf, ;key, ;req, or ;name) change how values are
+		// derived, and are not needed for ActivityPub, so they are not supported.
+		if len(component.Params) > 0 {
+			return "", derp.BadRequest(location, "Component parameters are not supported", name)
+		}
+
+		value, err := componentValue(request, host, name)
+
+		if err != nil {
+			return "", derp.Wrap(err, location, "Reading component value", name)
+		}
+
+		builder.WriteString(serializeBareItem(name))
+		builder.WriteString(": ")
+		builder.WriteString(value)
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString(`"@signature-params": `)
+	builder.WriteString(serializeInnerList(components, params))
+
+	return builder.String(), nil
+}
+
+// componentValue returns the value of a single covered component
+func componentValue(request *http.Request, host string, name string) (string, error) {
+
+	const location = "httpsig.componentValue"
+
+	target := targetURL(request, host)
+
+	switch name {
+
+	case "@method":
+		return request.Method, nil
+
+	case "@target-uri":
+		return target.String(), nil
+
+	case "@authority":
+		return strings.ToLower(target.Host), nil
+
+	case "@scheme":
+		return strings.ToLower(target.Scheme), nil
+
+	case "@request-target":
+		return target.RequestURI(), nil
+
+	case "@path":
+		return target.EscapedPath(), nil
+
+	case "@query":
+		return "?" + target.RawQuery, nil
+	}
+
+	if strings.HasPrefix(name, "@") {
+		return "", derp.BadRequest(location, "Unsupported derived component", name)
+	}
+
+	// Header names must be lowercase in the signature base
+	if name != strings.ToLower(name) {
+		return "", derp.BadRequest(location, "Header names must be lowercase", name)
+	}
+
+	values := request.Header.Values(name)
+
+	// The "host" header is not stored in the header map by net/http
+	if name == "host" && len(values) == 0 {
+		values = []string{target.Host}
+	}
+
+	if len(values) == 0 {
+		return "", derp.BadRequest(location, "Covered header is missing", name)
+	}
+
+	for index := range values {
+		values[index] = strings.TrimSpace(values[index])
+	}
+
+	return strings.Join(values, ", "), nil
+}
+
+// targetURL returns the absolute URL of a request.  Outbound requests already have one, and
+// inbound (server) requests are resolved against the provided host.
+func targetURL(request *http.Request, host string) *url.URL {
+
+	if request.URL.IsAbs() {
+		return request.URL
+	}
+
+	result := *request.URL
+
+	if base, err := url.Parse(host); err == nil {
+		result.Scheme = base.Scheme
+		result.Host = base.Host
+	}
+
+	if request.Host != "" {
+		result.Host = request.Host
+	}
+
+	return &result
+}
+
+// signMessage signs the signature base with the provided private key
+func signMessage(privateKey crypto.Signer, message []byte) ([]byte, error) {
+
+	if _, isRSA := privateKey.(*rsa.PrivateKey); isRSA {
+		digest := sha256.Sum256(message)
+		return privateKey.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+
+	// Ed25519 signs the complete message
+	return privateKey.Sign(rand.Reader, message, crypto.Hash(0))
+}
+
+// verifyMessage verifies a signature over the signature base with the provided public key
+func verifyMessage(publicKey crypto.PublicKey, message []byte, signature []byte) error {
+
+	const location = "httpsig.verifyMessage"
+
+	switch typed := publicKey.(type) {
+
+	case ed25519.PublicKey:
+		if !ed25519.Verify(typed, message, signature) {
+			return derp.BadRequest(location, "Ed25519 signature does not match")
+		}
+		return nil
+
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(message)
+		return rsa.VerifyPKCS1v15(typed, crypto.SHA256, digest[:], signature)
+	}
+
+	return derp.BadRequest(location, "Unsupported public key type")
+}
@@ -0,0 +1,63 @@
+package httpsig
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// SchemeCache remembers which signature scheme each remote host accepts, so that
+// hosts that reject RFC 9421 are not "double-knocked" on every request.  Entries
+// expire so that hosts which add RFC 9421 support later are eventually noticed.
+// It is safe for concurrent use.
+type SchemeCache struct {
+	mutex sync.RWMutex
+	ttl   time.Duration
+	hosts map[string]schemeCacheEntry
+}
+
+type schemeCacheEntry struct {
+	scheme  Scheme
+	expires time.Time
+}
+
+// NewSchemeCache returns a SchemeCache whose entries last for `ttl`
+func NewSchemeCache(ttl time.Duration) *SchemeCache {
+	return &SchemeCache{
+		ttl:   ttl,
+		hosts: make(map[string]schemeCacheEntry),
+	}
+}
+
+// Get returns the scheme to try first for a host.  Hosts that have not been
+// seen (or whose entries have expired) are offered RFC 9421.
+func (cache *SchemeCache) Get(host string, now time.Time) Scheme {
+
+	cache.mutex.RLock()
+	defer cache.mutex.RUnlock()
+
+	if entry, exists := cache.hosts[strings.ToLower(host)]; exists && now.Before(entry.expires) {
+		return entry.scheme
+	}
+
+	return SchemeRFC9421
+}
+
+// Set records the scheme that a host accepted
+func (cache *SchemeCache) Set(host string, scheme Scheme, now time.Time) {
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	// Opportunistically remove expired entries so that the cache cannot grow without bound
+	for key, entry := range cache.hosts {
+		if !now.Before(entry.expires) {
+			delete(cache.hosts, key)
+		}
+	}
+
+	cache.hosts[strings.ToLower(host)] = schemeCacheEntry{
+		scheme:  scheme,
+		expires: now.Add(cache.ttl),
+	}
+}
@@ -0,0 +1,30 @@
+package httpsig
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemeCache(t *testing.T) {
+
+	now := time.Now()
+	cache := NewSchemeCache(time.Hour)
+
+	// Unknown hosts are offered RFC 9421
+	require.Equal(t, SchemeRFC9421, cache.Get("mastodon.example", now))
+
+	// Known hosts use the scheme that they accepted (host names are case-insensitive)
+	cache.Set("Mastodon.Example", SchemeCavage, now)
+	require.Equal(t, SchemeCavage, cache.Get("mastodon.example", now))
+	require.Equal(t, SchemeRFC9421, cache.Get("other.example", now))
+
+	// Entries expire, so RFC 9421 is offered again
+	require.Equal(t, SchemeCavage, cache.Get("mastodon.example", now.Add(59*time.Minute)))
+	require.Equal(t, SchemeRFC9421, cache.Get("mastodon.example", now.Add(time.Hour)))
+
+	// Expired entries are removed when new entries are added
+	cache.Set("other.example", SchemeRFC9421, now.Add(2*time.Hour))
+	require.Equal(t, 1, len(cache.hosts))
+}
@@ -0,0 +1,500 @@
+package httpsig
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/benpate/derp"
+)
+
+// This file implements the small subset of RFC 8941 Structured Field Values that
+// HTTP Message Signatures need: Dictionaries whose members are Items or Inner Lists,
+// with Parameters.  Decimals are not supported because no signature field uses them.
+
+// sfToken is an RFC 8941 Token, which is serialized without quotes
+type sfToken string
+
+// sfParam is a single key/value parameter attached to an Item or Inner List
+type sfParam struct {
+	Key   string
+	Value any
+}
+
+// sfItem is a bare value (string, sfToken, int64, bool, or []byte) plus its parameters
+type sfItem struct {
+	Value  any
+	Params []sfParam
+}
+
+// sfMember is a single Dictionary member, which is either an Item or an Inner List.
+// For Inner Lists, Item holds only the parameters of the list itself.
+type sfMember struct {
+	Key         string
+	Item        sfItem
+	InnerList   []sfItem
+	IsInnerList bool
+}
+
+// Param returns the value of the named parameter, if it exists
+func (item sfItem) Param(key string) (any, bool) {
+
+	for _, param := range item.Params {
+		if param.Key == key {
+			return param.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// parseDictionary parses an RFC 8941 Dictionary
+func parseDictionary(value string) ([]sfMember, error) {
+
+	const location = "httpsig.parseDictionary"
+
+	parser := sfParser{input: value}
+	result := make([]sfMember, 0)
+
+	parser.skipSpaces()
+
+	for !parser.done() {
+
+		key, err := parser.parseKey()
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Parsing dictionary key", value)
+		}
+
+		member := sfMember{Key: key}
+
+		if parser.peek() == '=' {
+
+			parser.position++
+
+			if parser.peek() == '(' {
+				member.IsInnerList = true
+				member.InnerList, member.Item.Params, err = parser.parseInnerList()
+			} else {
+				member.Item, err = parser.parseItem()
+			}
+
+			if err != nil {
+				return nil, derp.Wrap(err, location, "Parsing dictionary value", key)
+			}
+
+		} else {
+
+			// Bare keys are boolean TRUE, and may still have parameters
+			member.Item.Value = true
+			member.Item.Params, err = parser.parseParams()
+
+			if err != nil {
+				return nil, derp.Wrap(err, location, "Parsing dictionary parameters", key)
+			}
+		}
+
+		result = append(result, member)
+
+		parser.skipOWS()
+
+		if parser.done() {
+			break
+		}
+
+		if parser.peek() != ',' {
+			return nil, derp.BadRequest(location, "Expected comma between dictionary members", value)
+		}
+
+		parser.position++
+		parser.skipOWS()
+
+		if parser.done() {
+			return nil, derp.BadRequest(location, "Trailing comma in dictionary", value)
+		}
+	}
+
+	return result, nil
+}
+
+// serializeInnerList serializes an Inner List and its parameters
+func serializeInnerList(items []sfItem, params []sfParam) string {
+
+	var builder strings.Builder
+
+	builder.WriteString("(")
+
+	for index, item := range items {
+
+		if index > 0 {
+			builder.WriteString(" ")
+		}
+
+		builder.WriteString(serializeBareItem(item.Value))
+		builder.WriteString(serializeParams(item.Params))
+	}
+
+	builder.WriteString(")")
+	builder.WriteString(serializeParams(params))
+
+	return builder.String()
+}
+
+// serializeParams serializes a list of parameters
+func serializeParams(params []sfParam) string {
+
+	var builder strings.Builder
+
+	for _, param := range params {
+
+		builder.WriteString(";")
+		builder.WriteString(param.Key)
+
+		if value, isBool := param.Value.(bool); isBool && value {
+			continue
+		}
+
+		builder.WriteString("=")
+		builder.WriteString(serializeBareItem(param.Value))
+	}
+
+	return builder.String()
+}
+
+// serializeBareItem serializes a single value
+func serializeBareItem(value any) string {
+
+	switch typed := value.(type) {
+
+	case string:
+		escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(typed)
+		return `"` + escaped + `"`
+
+	case sfToken:
+		return string(typed)
+
+	case int64:
+		return strconv.FormatInt(typed, 10)
+
+	case bool:
+		if typed {
+			return "?1"
+		}
+		return "?0"
+
+	case []byte:
+		return ":" + base64.StdEncoding.EncodeToString(typed) + ":"
+	}
+
+	return ""
+}
+
+// sfParser reads structured field values from an input string
+type sfParser struct {
+	input    string
+	position int
+}
+
+func (parser *sfParser) done() bool {
+	return parser.position >= len(parser.input)
+}
+
+func (parser *sfParser) peek() byte {
+
+	if parser.done() {
+		return 0
+	}
+
+	return parser.input[parser.position]
+}
+
+func (parser *sfParser) skipSpaces() {
+	for parser.peek() == ' ' {
+		parser.position++
+	}
+}
+
+func (parser *sfParser) skipOWS() {
+	for parser.peek() == ' ' || parser.peek() == '\t' {
+		parser.position++
+	}
+}
+
+// parseInnerList parses a parenthesized list of Items, followed by its parameters
+func (parser *sfParser) parseInnerList() ([]sfItem, []sfParam, error) {
+
+	const location = "httpsig.sfParser.parseInnerList"
+
+	// Skip the opening parenthesis
+	parser.position++
+	result := make([]sfItem, 0)
+
+	for !parser.done() {
+
+		parser.skipSpaces()
+
+		if parser.peek() == ')' {
+			parser.position++
+			params, err := parser.parseParams()
+			return result, params, err
+		}
+
+		item, err := parser.parseItem()
+
+		if err != nil {
+			return nil, nil, derp.Wrap(err, location, "Parsing inner list item")
+		}
+
+		result = append(result, item)
+
+		if next := parser.peek(); next != ' ' && next != ')' {
+			return nil, nil, derp.BadRequest(location, "Expected space or closing parenthesis", parser.input)
+		}
+	}
+
+	return nil, nil, derp.BadRequest(location, "Inner list is not closed", parser.input)
+}
+
+// parseItem parses a bare value followed by its parameters
+func (parser *sfParser) parseItem() (sfItem, error) {
+
+	value, err := parser.parseBareItem()
+
+	if err != nil {
+		return sfItem{}, err
+	}
+
+	params, err := parser.parseParams()
+
+	if err != nil {
+		return sfItem{}, err
+	}
+
+	return sfItem{Value: value, Params: params}, nil
+}
+
+// parseParams parses zero or more ";key=value" parameters
+func (parser *sfParser) parseParams() ([]sfParam, error) {
+
+	result := make([]sfParam, 0)
+
+	for parser.peek() == ';' {
+
+		parser.position++
+		parser.skipSpaces()
+
+		key, err := parser.parseKey()
+
+		if err != nil {
+			return nil, err
+		}
+
+		param := sfParam{Key: key, Value: true}
+
+		if parser.peek() == '=' {
+			parser.position++
+
+			if param.Value, err = parser.parseBareItem(); err != nil {
+				return nil, err
+			}
+		}
+
+		result = append(result, param)
+	}
+
+	return result, nil
+}
+
+// parseKey parses a lowercase dictionary or parameter key
+func (parser *sfParser) parseKey() (string, error) {
+
+	const location = "httpsig.sfParser.parseKey"
+
+	start := parser.position
+
+	if first := parser.peek(); !isLowerAlpha(first) && first != '*' {
+		return "", derp.BadRequest(location, "Keys must begin with a lowercase letter or '*'", parser.input)
+	}
+
+	for !parser.done() {
+		character := parser.peek()
+		if !isLowerAlpha(character) && !isDigit(character) && !strings.ContainsRune("_-.*", rune(character)) {
+			break
+		}
+		parser.position++
+	}
+
+	return parser.input[start:parser.position], nil
+}
+
+// parseBareItem parses a single String, Token, Integer, Boolean, or Byte Sequence
+func (parser *sfParser) parseBareItem() (any, error) {
+
+	const location = "httpsig.sfParser.parseBareItem"
+
+	switch character := parser.peek(); {
+
+	case character == '"':
+		return parser.parseString()
+
+	case character == ':':
+		return parser.parseByteSequence()
+
+	case character == '?':
+		return parser.parseBoolean()
+
+	case character == '-' || isDigit(character):
+		return parser.parseInteger()
+
+	case isAlpha(character) || character == '*':
+		return parser.parseToken(), nil
+	}
+
+	return nil, derp.BadRequest(location, "Unrecognized value", parser.input)
+}
+
+func (parser *sfParser) parseString() (string, error) {
+
+	const location = "httpsig.sfParser.parseString"
+
+	// Skip the opening quote
+	parser.position++
+
+	var builder strings.Builder
+
+	for !parser.done() {
+
+		character := parser.input[parser.position]
+		parser.position++
+
+		switch character {
+
+		case '\\':
+			if parser.done() {
+				return "", derp.BadRequest(location, "Unterminated escape sequence", parser.input)
+			}
+
+			escaped := parser.input[parser.position]
+			parser.position++
+
+			if escaped != '"' && escaped != '\\' {
+				return "", derp.BadRequest(location, "Invalid escape sequence", parser.input)
+			}
+
+			builder.WriteByte(escaped)
+
+		case '"':
+			return builder.String(), nil
+
+		default:
+			if character < 0x20 || character > 0x7e {
+				return "", derp.BadRequest(location, "Invalid character in string", parser.input)
+			}
+
+			builder.WriteByte(character)
+		}
+	}
+
+	return "", derp.BadRequest(location, "Unterminated string", parser.input)
+}
+
+func (parser *sfParser) parseByteSequence() ([]byte, error) {
+
+	const location = "httpsig.sfParser.parseByteSequence"
+
+	// Skip the opening colon
+	parser.position++
+
+	end := strings.IndexByte(parser.input[parser.position:], ':')
+
+	if end < 0 {
+		return nil, derp.BadRequest(location, "Unterminated byte sequence", parser.input)
+	}
+
+	encoded := parser.input[parser.position : parser.position+end]
+	parser.position += end + 1
+
+	result, err := base64.StdEncoding.DecodeString(encoded)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Invalid base64 value", encoded)
+	}
+
+	return result, nil
+}
+
+func (parser *sfParser) parseBoolean() (bool, error) {
+
+	const location = "httpsig.sfParser.parseBoolean"
+
+	// Skip the question mark
+	parser.position++
+
+	switch parser.peek() {
+
+	case '1':
+		parser.position++
+		return true, nil
+
+	case '0':
+		parser.position++
+		return false, nil
+	}
+
+	return false, derp.BadRequest(location, "Invalid boolean value", parser.input)
+}
+
+func (parser *sfParser) parseInteger() (int64, error) {
+
+	const location = "httpsig.sfParser.parseInteger"
+
+	start := parser.position
+
+	if parser.peek() == '-' {
+		parser.position++
+	}
+
+	for isDigit(parser.peek()) {
+		parser.position++
+	}
+
+	if parser.peek() == '.' {
+		return 0, derp.BadRequest(location, "Decimal values are not supported", parser.input)
+	}
+
+	result, err := strconv.ParseInt(parser.input[start:parser.position], 10, 64)
+
+	if err != nil {
+		return 0, derp.Wrap(err, location, "Invalid integer value", parser.input)
+	}
+
+	return result, nil
+}
+
+func (parser *sfParser) parseToken() sfToken {
+
+	start := parser.position
+
+	for !parser.done() {
+		character := parser.peek()
+		if isAlpha(character) || isDigit(character) || strings.ContainsRune("!#$%&'*+-.^_`|~:/", rune(character)) {
+			parser.position++
+			continue
+		}
+		break
+	}
+
+	return sfToken(parser.input[start:parser.position])
+}
+
+func isLowerAlpha(character byte) bool {
+	return character >= 'a' && character <= 'z'
+}
+
+func isAlpha(character byte) bool {
+	return isLowerAlpha(character) || (character >= 'A' && character <= 'Z')
+}
+
+func isDigit(character byte) bool {
+	return character >= '0' && character <= '9'
+}
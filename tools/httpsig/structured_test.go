@@ -0,0 +1,77 @@
+package httpsig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDictionary(t *testing.T) {
+
+	value := `sig1=("@method" "@target-uri" "content-digest");created=1618884473;keyid="https://example.com/@alice#main-key";alg="ed25519", sig2=:AQID:, flag, token=abc;x`
+
+	members, err := parseDictionary(value)
+	require.Nil(t, err)
+	require.Equal(t, 4, len(members))
+
+	// Inner list with parameters
+	require.Equal(t, "sig1", members[0].Key)
+	require.True(t, members[0].IsInnerList)
+	require.Equal(t, 3, len(members[0].InnerList))
+	require.Equal(t, "@target-uri", members[0].InnerList[1].Value)
+
+	created, exists := members[0].Item.Param("created")
+	require.True(t, exists)
+	require.Equal(t, int64(1618884473), created)
+
+	keyID, _ := members[0].Item.Param("keyid")
+	require.Equal(t, "https://example.com/@alice#main-key", keyID)
+
+	// Byte sequence
+	require.Equal(t, []byte{1, 2, 3}, members[1].Item.Value)
+
+	// Bare key
+	require.Equal(t, true, members[2].Item.Value)
+
+	// Token with a boolean parameter
+	require.Equal(t, sfToken("abc"), members[3].Item.Value)
+	x, _ := members[3].Item.Param("x")
+	require.Equal(t, true, x)
+}
+
+func TestParseDictionary_Invalid(t *testing.T) {
+
+	invalid := []string{
+		`Sig1=("@method")`,
+		`sig1=("@method"`,
+		`sig1=("@method");created=1.5`,
+		`sig1="unterminated`,
+		`sig1=:AQID`,
+		`sig1=?2`,
+		`sig1=1 sig2=2`,
+		`sig1=1,`,
+	}
+
+	for _, value := range invalid {
+		_, err := parseDictionary(value)
+		require.NotNil(t, err, value)
+	}
+}
+
+func TestSerializeInnerList(t *testing.T) {
+
+	items := []sfItem{{Value: "@method"}, {Value: `say "hi"`}}
+	params := []sfParam{
+		{Key: "created", Value: int64(123)},
+		{Key: "keyid", Value: "key"},
+		{Key: "flag", Value: true},
+	}
+
+	value := serializeInnerList(items, params)
+	require.Equal(t, `("@method" "say \"hi\"");created=123;keyid="key";flag`, value)
+
+	// Round trip
+	members, err := parseDictionary("sig=" + value)
+	require.Nil(t, err)
+	require.Equal(t, value, serializeInnerList(members[0].InnerList, members[0].Item.Params))
+}
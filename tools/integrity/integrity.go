@@ -0,0 +1,308 @@
+// Package integrity creates and verifies Object Integrity Proofs (FEP-8b32), which
+// are W3C Data Integrity proofs that use the "eddsa-jcs-2022" cryptosuite.  A proof
+// lets anyone verify that an activity was created by its actor, no matter which
+// server it was received from.
+package integrity
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/json"
+	"maps"
+	"time"
+
+	"github.com/EmissarySocial/emissary/tools/jcs"
+	"github.com/EmissarySocial/emissary/tools/multikey"
+	"github.com/benpate/derp"
+)
+
+// Context is the JSON-LD context that defines the Data Integrity vocabulary
+const Context = "https://w3id.org/security/data-integrity/v1"
+
+// ProofType is the "type" of every Data Integrity proof
+const ProofType = "DataIntegrityProof"
+
+// Cryptosuite is the only cryptosuite that this package supports
+const Cryptosuite = "eddsa-jcs-2022"
+
+// ProofPurpose is the purpose of proofs that assert authorship of a document
+const ProofPurpose = "assertionMethod"
+
+// PropertyProof is the name of the property that contains a document's proof
+const PropertyProof = "proof"
+
+// PublicKeyFinder returns the Ed25519 public key for a proof's verificationMethod
+type PublicKeyFinder func(verificationMethod string) (ed25519.PublicKey, error)
+
+// HasProof returns TRUE if the document includes a proof property
+func HasProof(document map[string]any) bool {
+	_, exists := document[PropertyProof]
+	return exists
+}
+
+// Sign returns a copy of the document with an "eddsa-jcs-2022" proof that is signed by the
+// private key.  The document's @context is extended to include the Data Integrity context.
+// The original document is not modified.
+func Sign(document map[string]any, verificationMethod string, privateKey ed25519.PrivateKey, created time.Time) (map[string]any, error) {
+
+	const location = "integrity.Sign"
+
+	if HasProof(document) {
+		return nil, derp.BadRequest(location, "Document already has a proof")
+	}
+
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return nil, derp.Internal(location, "Invalid Ed25519 private key", verificationMethod)
+	}
+
+	result, err := normalize(document)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Normalizing document", verificationMethod)
+	}
+
+	result["@context"] = withContext(result["@context"])
+
+	proof := map[string]any{
+		"@context":           result["@context"],
+		"type":               ProofType,
+		"cryptosuite":        Cryptosuite,
+		"verificationMethod": verificationMethod,
+		"proofPurpose":       ProofPurpose,
+		"created":            created.UTC().Format(time.RFC3339),
+	}
+
+	hash, err := hashData(result, proof)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Hashing document", verificationMethod)
+	}
+
+	proof["proofValue"] = multikey.EncodeMultibase(ed25519.Sign(privateKey, hash))
+	result[PropertyProof] = proof
+
+	return result, nil
+}
+
+// Verify validates the "eddsa-jcs-2022" proof on a document, and returns the
+// verificationMethod (key ID) that signed it.  Callers are responsible for confirming
+// that the key belongs to the document's actor.
+func Verify(document map[string]any, finder PublicKeyFinder) (string, error) {
+
+	const location = "integrity.Verify"
+
+	document, err := normalize(document)
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Normalizing document")
+	}
+
+	proof, err := findProof(document)
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Reading proof")
+	}
+
+	verificationMethod, _ := proof["verificationMethod"].(string)
+
+	if verificationMethod == "" {
+		return "", derp.BadRequest(location, "Proof must include a verificationMethod")
+	}
+
+	if purpose, _ := proof["proofPurpose"].(string); purpose != ProofPurpose {
+		return "", derp.BadRequest(location, "Unsupported proof purpose", purpose)
+	}
+
+	proofValue, _ := proof["proofValue"].(string)
+	signature, err := multikey.DecodeMultibase(proofValue)
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Decoding proofValue", verificationMethod)
+	}
+
+	if len(signature) != ed25519.SignatureSize {
+		return "", derp.BadRequest(location, "Signature has the wrong length", verificationMethod)
+	}
+
+	// Separate the proof from the document that it signs
+	unsecured := document
+	delete(unsecured, PropertyProof)
+
+	proofConfig := maps.Clone(proof)
+	delete(proofConfig, "proofValue")
+
+	// RULE: If the proof has a @context, then the document's @context must start with it
+	if proofContext, exists := proofConfig["@context"]; exists {
+
+		if !hasContextPrefix(unsecured["@context"], proofContext) {
+			return "", derp.BadRequest(location, "Proof @context does not match the document", verificationMethod)
+		}
+
+		unsecured["@context"] = proofContext
+	}
+
+	hash, err := hashData(unsecured, proofConfig)
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Hashing document", verificationMethod)
+	}
+
+	publicKey, err := finder(verificationMethod)
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Loading public key", verificationMethod)
+	}
+
+	if !ed25519.Verify(publicKey, hash, signature) {
+		return "", derp.Unauthorized(location, "Invalid proof signature", verificationMethod)
+	}
+
+	return verificationMethod, nil
+}
+
+// findProof returns the first "eddsa-jcs-2022" proof in the document
+func findProof(document map[string]any) (map[string]any, error) {
+
+	const location = "integrity.findProof"
+
+	var proofs []any
+
+	switch typed := document[PropertyProof].(type) {
+
+	case nil:
+		return nil, derp.BadRequest(location, "Document does not have a proof")
+
+	case []any:
+		proofs = typed
+
+	default:
+		proofs = []any{typed}
+	}
+
+	for _, value := range proofs {
+
+		proof, isMap := value.(map[string]any)
+
+		if !isMap {
+			continue
+		}
+
+		if (proof["type"] == ProofType) && (proof["cryptosuite"] == Cryptosuite) {
+			return proof, nil
+		}
+	}
+
+	return nil, derp.BadRequest(location, "Document does not have an "+Cryptosuite+" proof")
+}
+
+// hashData returns the value that is signed: the SHA-256 hash of the canonical proof
+// configuration, followed by the SHA-256 hash of the canonical document.
+func hashData(document map[string]any, proofConfig map[string]any) ([]byte, error) {
+
+	const location = "integrity.hashData"
+
+	canonicalProof, err := jcs.Marshal(proofConfig)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Canonicalizing proof configuration")
+	}
+
+	canonicalDocument, err := jcs.Marshal(document)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Canonicalizing document")
+	}
+
+	proofHash := sha256.Sum256(canonicalProof)
+	documentHash := sha256.Sum256(canonicalDocument)
+
+	return append(proofHash[:], documentHash[:]...), nil
+}
+
+// normalize returns a deep copy of the document that contains only the types that
+// encoding/json produces, so that documents built in Go are handled the same way as
+// documents received from the network.
+func normalize(document map[string]any) (map[string]any, error) {
+
+	const location = "integrity.normalize"
+
+	encoded, err := json.Marshal(document)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Encoding document")
+	}
+
+	result := make(map[string]any)
+
+	if err := json.Unmarshal(encoded, &result); err != nil {
+		return nil, derp.Wrap(err, location, "Decoding document")
+	}
+
+	return result, nil
+}
+
+// withContext returns a @context value that includes the Data Integrity context
+func withContext(value any) any {
+
+	contexts := contextSlice(value)
+
+	for _, context := range contexts {
+		if context == Context {
+			return value
+		}
+	}
+
+	result := make([]any, 0, len(contexts)+1)
+	result = append(result, contexts...)
+	result = append(result, Context)
+
+	return result
+}
+
+// hasContextPrefix returns TRUE if the document's @context starts with every value in the proof's @context
+func hasContextPrefix(documentContext any, proofContext any) bool {
+
+	documentContexts := contextSlice(documentContext)
+	proofContexts := contextSlice(proofContext)
+
+	if len(proofContexts) > len(documentContexts) {
+		return false
+	}
+
+	for index, context := range proofContexts {
+
+		expected, err := jcs.Marshal(context)
+
+		if err != nil {
+			return false
+		}
+
+		actual, err := jcs.Marshal(documentContexts[index])
+
+		if err != nil {
+			return false
+		}
+
+		if string(expected) != string(actual) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// contextSlice returns a @context value as a slice
+func contextSlice(value any) []any {
+
+	switch typed := value.(type) {
+
+	case nil:
+		return []any{}
+
+	case []any:
+		return typed
+
+	default:
+		return []any{typed}
+	}
+}
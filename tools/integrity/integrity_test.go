@@ -0,0 +1,144 @@
+package integrity
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/benpate/derp"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey, PublicKeyFinder) {
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+
+	finder := func(verificationMethod string) (ed25519.PublicKey, error) {
+		if verificationMethod == "https://example.com/@alice#ed25519-key" {
+			return publicKey, nil
+		}
+		return nil, derp.NotFound("test", "Unknown key", verificationMethod)
+	}
+
+	return publicKey, privateKey, finder
+}
+
+func testActivity() map[string]any {
+	return map[string]any{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id":       "https://example.com/@alice/pub/outbox/1",
+		"type":     "Create",
+		"actor":    "https://example.com/@alice",
+		"object": map[string]any{
+			"type":    "Note",
+			"content": "Hello <b>world</b> & all",
+			"count":   3,
+		},
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+
+	_, privateKey, finder := testKey(t)
+	activity := testActivity()
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	signed, err := Sign(activity, "https://example.com/@alice#ed25519-key", privateKey, created)
+	require.Nil(t, err)
+
+	// The original document is not modified
+	require.False(t, HasProof(activity))
+	require.Equal(t, "https://www.w3.org/ns/activitystreams", activity["@context"])
+
+	// The signed document includes the Data Integrity context and a proof
+	require.Equal(t, []any{"https://www.w3.org/ns/activitystreams", Context}, signed["@context"])
+
+	proof := signed[PropertyProof].(map[string]any)
+	require.Equal(t, ProofType, proof["type"])
+	require.Equal(t, Cryptosuite, proof["cryptosuite"])
+	require.Equal(t, ProofPurpose, proof["proofPurpose"])
+	require.Equal(t, "2024-01-02T03:04:05Z", proof["created"])
+
+	// The proof survives a round trip through JSON
+	encoded, err := json.Marshal(signed)
+	require.Nil(t, err)
+
+	received := map[string]any{}
+	require.Nil(t, json.Unmarshal(encoded, &received))
+
+	keyID, err := Verify(received, finder)
+	require.Nil(t, err)
+	require.Equal(t, "https://example.com/@alice#ed25519-key", keyID)
+
+	// Verification does not modify the document
+	require.True(t, HasProof(received))
+}
+
+func TestVerify_Tampered(t *testing.T) {
+
+	_, privateKey, finder := testKey(t)
+
+	signed, err := Sign(testActivity(), "https://example.com/@alice#ed25519-key", privateKey, time.Now())
+	require.Nil(t, err)
+
+	signed["object"].(map[string]any)["content"] = "Goodbye"
+
+	_, err = Verify(signed, finder)
+	require.NotNil(t, err)
+}
+
+func TestVerify_WrongKey(t *testing.T) {
+
+	_, privateKey, _ := testKey(t)
+	_, _, finder := testKey(t)
+
+	signed, err := Sign(testActivity(), "https://example.com/@alice#ed25519-key", privateKey, time.Now())
+	require.Nil(t, err)
+
+	_, err = Verify(signed, finder)
+	require.NotNil(t, err)
+}
+
+func TestVerify_ContextMismatch(t *testing.T) {
+
+	_, privateKey, finder := testKey(t)
+
+	signed, err := Sign(testActivity(), "https://example.com/@alice#ed25519-key", privateKey, time.Now())
+	require.Nil(t, err)
+
+	signed["@context"] = []any{"https://example.com/other-context", Context}
+
+	_, err = Verify(signed, finder)
+	require.NotNil(t, err)
+}
+
+func TestVerify_ProofSet(t *testing.T) {
+
+	_, privateKey, finder := testKey(t)
+
+	signed, err := Sign(testActivity(), "https://example.com/@alice#ed25519-key", privateKey, time.Now())
+	require.Nil(t, err)
+
+	// Proofs with other cryptosuites are skipped
+	signed[PropertyProof] = []any{
+		map[string]any{"type": ProofType, "cryptosuite": "ecdsa-rdfc-2019"},
+		signed[PropertyProof],
+	}
+
+	_, err = Verify(signed, finder)
+	require.Nil(t, err)
+}
+
+func TestSign_AlreadySigned(t *testing.T) {
+
+	_, privateKey, _ := testKey(t)
+
+	signed, err := Sign(testActivity(), "https://example.com/@alice#ed25519-key", privateKey, time.Now())
+	require.Nil(t, err)
+
+	_, err = Sign(signed, "https://example.com/@alice#ed25519-key", privateKey, time.Now())
+	require.NotNil(t, err)
+}
@@ -0,0 +1,260 @@
+// Package jcs implements the JSON Canonicalization Scheme (RFC 8785), which
+// serializes JSON values into a single, byte-for-byte reproducible form so that
+// they can be hashed and signed.
+package jcs
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/benpate/derp"
+)
+
+// Marshal returns the canonical JSON encoding of a value.  The value is first
+// encoded with encoding/json, so any type that encoding/json supports is allowed.
+func Marshal(value any) ([]byte, error) {
+
+	const location = "jcs.Marshal"
+
+	encoded, err := json.Marshal(value)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Encoding value")
+	}
+
+	return Canonicalize(encoded)
+}
+
+// Canonicalize re-serializes a JSON document into its canonical form
+func Canonicalize(data []byte) ([]byte, error) {
+
+	const location = "jcs.Canonicalize"
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+
+	if err := decoder.Decode(&value); err != nil {
+		return nil, derp.Wrap(err, location, "Decoding JSON")
+	}
+
+	// Reject trailing data after the first value
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, derp.BadRequest(location, "Unexpected data after JSON value")
+	}
+
+	var buffer bytes.Buffer
+
+	if err := writeValue(&buffer, value); err != nil {
+		return nil, derp.Wrap(err, location, "Canonicalizing JSON")
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// writeValue writes a decoded JSON value to the buffer
+func writeValue(buffer *bytes.Buffer, value any) error {
+
+	switch typed := value.(type) {
+
+	case nil:
+		buffer.WriteString("null")
+
+	case bool:
+		buffer.WriteString(strconv.FormatBool(typed))
+
+	case json.Number:
+		number, err := formatNumber(typed)
+
+		if err != nil {
+			return err
+		}
+
+		buffer.WriteString(number)
+
+	case string:
+		writeString(buffer, typed)
+
+	case []any:
+		buffer.WriteByte('[')
+
+		for index, item := range typed {
+
+			if index > 0 {
+				buffer.WriteByte(',')
+			}
+
+			if err := writeValue(buffer, item); err != nil {
+				return err
+			}
+		}
+
+		buffer.WriteByte(']')
+
+	case map[string]any:
+
+		// Properties are sorted by their UTF-16 code units (RFC 8785 Section 3.2.3)
+		keys := make([]string, 0, len(typed))
+
+		for key := range typed {
+			keys = append(keys, key)
+		}
+
+		slices.SortFunc(keys, func(a string, b string) int {
+			return slices.Compare(utf16.Encode([]rune(a)), utf16.Encode([]rune(b)))
+		})
+
+		buffer.WriteByte('{')
+
+		for index, key := range keys {
+
+			if index > 0 {
+				buffer.WriteByte(',')
+			}
+
+			writeString(buffer, key)
+			buffer.WriteByte(':')
+
+			if err := writeValue(buffer, typed[key]); err != nil {
+				return err
+			}
+		}
+
+		buffer.WriteByte('}')
+
+	default:
+		return derp.Internal("jcs.writeValue", "Unsupported JSON value", value)
+	}
+
+	return nil
+}
+
+// writeString writes a JSON string, escaping only the characters that RFC 8785 requires
+func writeString(buffer *bytes.Buffer, value string) {
+
+	const hex = "0123456789abcdef"
+
+	buffer.WriteByte('"')
+
+	for _, character := range value {
+
+		switch character {
+
+		case '"':
+			buffer.WriteString(`\"`)
+
+		case '\\':
+			buffer.WriteString(`\\`)
+
+		case '\b':
+			buffer.WriteString(`\b`)
+
+		case '\f':
+			buffer.WriteString(`\f`)
+
+		case '\n':
+			buffer.WriteString(`\n`)
+
+		case '\r':
+			buffer.WriteString(`\r`)
+
+		case '\t':
+			buffer.WriteString(`\t`)
+
+		default:
+			if character < 0x20 {
+				buffer.WriteString(`\u00`)
+				buffer.WriteByte(hex[character>>4])
+				buffer.WriteByte(hex[character&0xF])
+			} else {
+				buffer.WriteRune(character)
+			}
+		}
+	}
+
+	buffer.WriteByte('"')
+}
+
+// formatNumber serializes a number the way ECMAScript's Number.prototype.toString does
+// (RFC 8785 Section 3.2.2.3), which is the shortest representation that round-trips.
+func formatNumber(number json.Number) (string, error) {
+
+	const location = "jcs.formatNumber"
+
+	value, err := strconv.ParseFloat(string(number), 64)
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Number is not a valid IEEE 754 double", string(number))
+	}
+
+	if math.IsInf(value, 0) || math.IsNaN(value) {
+		return "", derp.BadRequest(location, "Number is out of range", string(number))
+	}
+
+	// Includes negative zero
+	if value == 0 {
+		return "0", nil
+	}
+
+	var result strings.Builder
+
+	if value < 0 {
+		result.WriteByte('-')
+		value = -value
+	}
+
+	// The shortest round-trip digits, and the exponent "n" so that value = 0.digits * 10^n
+	scientific := strconv.FormatFloat(value, 'e', -1, 64)
+	mantissa, exponentString, _ := strings.Cut(scientific, "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	exponent, _ := strconv.Atoi(exponentString)
+
+	k := len(digits)
+	n := exponent + 1
+
+	switch {
+
+	// Integers up to 21 digits: digits followed by zeros
+	case (k <= n) && (n <= 21):
+		result.WriteString(digits)
+		result.WriteString(strings.Repeat("0", n-k))
+
+	// Decimal point within the digits
+	case (0 < n) && (n <= 21):
+		result.WriteString(digits[:n])
+		result.WriteByte('.')
+		result.WriteString(digits[n:])
+
+	// Small numbers: leading zeros after the decimal point
+	case (-6 < n) && (n <= 0):
+		result.WriteString("0.")
+		result.WriteString(strings.Repeat("0", -n))
+		result.WriteString(digits)
+
+	// Everything else uses exponential notation
+	default:
+		result.WriteByte(digits[0])
+
+		if k > 1 {
+			result.WriteByte('.')
+			result.WriteString(digits[1:])
+		}
+
+		result.WriteByte('e')
+
+		if n-1 >= 0 {
+			result.WriteByte('+')
+		}
+
+		result.WriteString(strconv.Itoa(n - 1))
+	}
+
+	return result.String(), nil
+}
@@ -0,0 +1,105 @@
+package jcs
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Example from RFC 8785 Section 3.2.2
+func TestCanonicalize(t *testing.T) {
+
+	input := `{
+		"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+		"string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+		"literals": [null, true, false]
+	}`
+
+	expected := `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`
+
+	result, err := Canonicalize([]byte(input))
+	require.Nil(t, err)
+	require.Equal(t, expected, string(result))
+}
+
+// Example from RFC 8785 Section 3.2.3
+func TestCanonicalize_Sorting(t *testing.T) {
+
+	input := `{
+		"\u20ac": "Euro Sign",
+		"\r": "Carriage Return",
+		"\ufb33": "Hebrew Letter Dalet With Dagesh",
+		"1": "One",
+		"\ud83d\ude00": "Emoji: Grinning Face",
+		"\u0080": "Control",
+		"\u00f6": "Latin Small Letter O With Diaeresis"
+	}`
+
+	expected := "{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"\u00f6\":\"Latin Small Letter O With Diaeresis\",\"\u20ac\":\"Euro Sign\",\"\U0001f600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}"
+
+	result, err := Canonicalize([]byte(input))
+	require.Nil(t, err)
+	require.Equal(t, expected, string(result))
+}
+
+// Examples from RFC 8785 Appendix B
+func TestFormatNumber(t *testing.T) {
+
+	values := map[uint64]string{
+		0x0000000000000000: "0",
+		0x8000000000000000: "0",
+		0x0000000000000001: "5e-324",
+		0x8000000000000001: "-5e-324",
+		0x7fefffffffffffff: "1.7976931348623157e+308",
+		0xffefffffffffffff: "-1.7976931348623157e+308",
+		0x4340000000000000: "9007199254740992",
+		0xc340000000000000: "-9007199254740992",
+		0x4430000000000000: "295147905179352830000",
+		0x44b52d02c7e14af5: "9.999999999999997e+22",
+		0x44b52d02c7e14af6: "1e+23",
+		0x444b1ae4d6e2ef4f: "999999999999999900000",
+		0x444b1ae4d6e2ef50: "1e+21",
+		0x3eb0c6f7a0b5ed8c: "9.999999999999997e-7",
+		0x3eb0c6f7a0b5ed8d: "0.000001",
+		0x41b3de4355555553: "333333333.3333332",
+		0x41b3de4355555554: "333333333.33333325",
+		0x41b3de4355555555: "333333333.3333333",
+	}
+
+	for bits, expected := range values {
+
+		// Round trip through encoding/json, the same way that Marshal does
+		encoded, err := json.Marshal(math.Float64frombits(bits))
+		require.Nil(t, err)
+
+		result, err := formatNumber(json.Number(encoded))
+		require.Nil(t, err)
+		require.Equal(t, expected, result, "%016x", bits)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+
+	value := map[string]any{
+		"b":    []string{"<tag>", "&"},
+		"a":    1,
+		"html": "\u2028",
+	}
+
+	result, err := Marshal(value)
+	require.Nil(t, err)
+
+	// Unlike encoding/json, HTML characters and line separators are not escaped
+	require.Equal(t, `{"a":1,"b":["<tag>","&"],"html":"`+"\u2028"+`"}`, string(result))
+}
+
+func TestCanonicalize_Invalid(t *testing.T) {
+
+	_, err := Canonicalize([]byte(`{"a":1} {"b":2}`))
+	require.NotNil(t, err)
+
+	_, err = Canonicalize([]byte(`{"a":1e400}`))
+	require.NotNil(t, err)
+}
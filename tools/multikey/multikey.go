@@ -0,0 +1,144 @@
+// Package multikey encodes and decodes Ed25519 public keys in the Multikey format
+// (FEP-521a / W3C Controlled Identifiers): a multibase string that starts with "z"
+// (base58btc) and wraps the raw key in the Ed25519 multicodec prefix (0xed01).
+package multikey
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"math/big"
+	"strings"
+
+	"github.com/benpate/derp"
+)
+
+// Type is the JSON-LD type of a Multikey verification method
+const Type = "Multikey"
+
+// Context is the JSON-LD context that defines the Multikey vocabulary
+const Context = "https://w3id.org/security/multikey/v1"
+
+// multibaseBase58 is the multibase prefix for base58btc encoding
+const multibaseBase58 = "z"
+
+// ed25519Prefix is the multicodec prefix (varint-encoded) for an Ed25519 public key
+var ed25519Prefix = []byte{0xed, 0x01}
+
+// base58Alphabet is the Bitcoin base58 alphabet that multibase uses for "base58btc"
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// EncodeEd25519 returns the "publicKeyMultibase" value for an Ed25519 public key
+func EncodeEd25519(publicKey ed25519.PublicKey) string {
+	value := append(append([]byte{}, ed25519Prefix...), publicKey...)
+	return EncodeMultibase(value)
+}
+
+// DecodeEd25519 parses a "publicKeyMultibase" value into an Ed25519 public key
+func DecodeEd25519(value string) (ed25519.PublicKey, error) {
+
+	const location = "multikey.DecodeEd25519"
+
+	decoded, err := DecodeMultibase(value)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Decoding Multikey", value)
+	}
+
+	publicKey, found := bytes.CutPrefix(decoded, ed25519Prefix)
+
+	if !found {
+		return nil, derp.BadRequest(location, "Multikey is not an Ed25519 key", value)
+	}
+
+	if len(publicKey) != ed25519.PublicKeySize {
+		return nil, derp.BadRequest(location, "Ed25519 key has the wrong length", value)
+	}
+
+	return ed25519.PublicKey(publicKey), nil
+}
+
+// EncodeMultibase returns a multibase (base58btc) string for a byte slice.  This is the
+// format of Multikey values, and of the "proofValue" of Data Integrity proofs.
+func EncodeMultibase(value []byte) string {
+	return multibaseBase58 + encodeBase58(value)
+}
+
+// DecodeMultibase parses a multibase (base58btc) string into a byte slice
+func DecodeMultibase(value string) ([]byte, error) {
+
+	const location = "multikey.DecodeMultibase"
+
+	encoded, found := strings.CutPrefix(value, multibaseBase58)
+
+	if !found {
+		return nil, derp.BadRequest(location, "Value must be base58btc encoded", value)
+	}
+
+	decoded, err := decodeBase58(encoded)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Decoding base58btc value", value)
+	}
+
+	return decoded, nil
+}
+
+// encodeBase58 encodes a byte slice using the base58btc alphabet.  Leading zero
+// bytes are encoded as leading "1" characters.
+func encodeBase58(value []byte) string {
+
+	number := new(big.Int).SetBytes(value)
+	radix := big.NewInt(58)
+	modulus := new(big.Int)
+	result := make([]byte, 0, len(value)*138/100+1)
+
+	for number.Sign() > 0 {
+		number.DivMod(number, radix, modulus)
+		result = append(result, base58Alphabet[modulus.Int64()])
+	}
+
+	for _, b := range value {
+		if b != 0 {
+			break
+		}
+		result = append(result, base58Alphabet[0])
+	}
+
+	// Digits were generated least-significant first, so reverse them
+	for left, right := 0, len(result)-1; left < right; left, right = left+1, right-1 {
+		result[left], result[right] = result[right], result[left]
+	}
+
+	return string(result)
+}
+
+// decodeBase58 decodes a base58btc string into a byte slice
+func decodeBase58(value string) ([]byte, error) {
+
+	const location = "multikey.decodeBase58"
+
+	number := new(big.Int)
+	radix := big.NewInt(58)
+
+	for _, character := range value {
+
+		digit := strings.IndexRune(base58Alphabet, character)
+
+		if digit < 0 {
+			return nil, derp.BadRequest(location, "Invalid base58 character", string(character))
+		}
+
+		number.Mul(number, radix)
+		number.Add(number, big.NewInt(int64(digit)))
+	}
+
+	decoded := number.Bytes()
+
+	// Restore leading zero bytes
+	zeros := 0
+	for zeros < len(value) && value[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+
+	return append(make([]byte, zeros), decoded...), nil
+}
@@ -0,0 +1,69 @@
+package multikey
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBase58(t *testing.T) {
+
+	// Test vectors from the base58 IETF draft (draft-msporny-base58)
+	require.Equal(t, "2NEpo7TZRRrLZSi2U", encodeBase58([]byte("Hello World!")))
+	require.Equal(t, "11233QC4", encodeBase58([]byte{0x00, 0x00, 0x28, 0x7f, 0xb4, 0xcd}))
+
+	decoded, err := decodeBase58("11233QC4")
+	require.Nil(t, err)
+	require.Equal(t, []byte{0x00, 0x00, 0x28, 0x7f, 0xb4, 0xcd}, decoded)
+
+	_, err = decodeBase58("0OIl")
+	require.NotNil(t, err)
+}
+
+func TestEd25519_RoundTrip(t *testing.T) {
+
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+
+	encoded := EncodeEd25519(publicKey)
+
+	// Every Ed25519 Multikey starts with the same prefix
+	require.True(t, strings.HasPrefix(encoded, "z6Mk"))
+
+	decoded, err := DecodeEd25519(encoded)
+	require.Nil(t, err)
+	require.Equal(t, publicKey, decoded)
+}
+
+func TestEd25519_Invalid(t *testing.T) {
+
+	// Not base58btc
+	_, err := DecodeEd25519("uAAAA")
+	require.NotNil(t, err)
+
+	// Not an Ed25519 key
+	_, err = DecodeEd25519("z" + encodeBase58([]byte{0xe7, 0x01, 0x01, 0x02}))
+	require.NotNil(t, err)
+
+	// Wrong length
+	_, err = DecodeEd25519("z" + encodeBase58([]byte{0xed, 0x01, 0x01, 0x02}))
+	require.NotNil(t, err)
+}
+
+func TestMultibase_RoundTrip(t *testing.T) {
+
+	value := []byte{0x00, 0x01, 0x02, 0xff}
+
+	encoded := EncodeMultibase(value)
+	require.True(t, strings.HasPrefix(encoded, "z1"))
+
+	decoded, err := DecodeMultibase(encoded)
+	require.Nil(t, err)
+	require.Equal(t, value, decoded)
+
+	_, err = DecodeMultibase("uAAAA")
+	require.NotNil(t, err)
+}
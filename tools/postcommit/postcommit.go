@@ -24,10 +24,12 @@ type contextKey struct{}
 
 // Tasks is a per-transaction spool of queue.Task values.  WithTransaction places it into
 // the transaction's context, Publish fills it, and it is flushed to the queue only after
-// the transaction commits.
+// the transaction commits.  It also spools the signatures of queued tasks to delete, which
+// are removed just before the new tasks are published.
 type Tasks struct {
-	mutex sync.Mutex
-	tasks []queue.Task
+	mutex   sync.Mutex
+	tasks   []queue.Task
+	deletes []string
 }
 
 // NewTasks returns a fully initialized (empty) task spool.
@@ -65,6 +67,23 @@ func (t *Tasks) Reset() {
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
 	t.tasks = nil
+	t.deletes = nil
+}
+
+// AddDelete appends the signature of a queued task to delete.  Safe for concurrent use.
+func (t *Tasks) AddDelete(signature string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.deletes = append(t.deletes, signature)
+}
+
+// DrainDeletes returns all spooled signatures in FIFO order and clears them from the spool.
+func (t *Tasks) DrainDeletes() []string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	result := t.deletes
+	t.deletes = nil
+	return result
 }
 
 // Drain returns all spooled tasks in FIFO order and clears the spool.
@@ -114,6 +133,33 @@ func Publish(session data.Session, q *queue.Queue, name string, args mapof.Any,
 	}
 }
 
+// Delete removes the queued task with the provided signature.  If the session is part of an
+// open transaction, the deletion is spooled and applied only after the transaction commits,
+// so that a rolled-back transaction leaves the existing task in place.  Otherwise, the task
+// is deleted immediately.  Delete errors are reported, never returned.
+func Delete(session data.Session, q *queue.Queue, signature string) {
+
+	const location = "tools.postcommit.Delete"
+
+	if session == nil {
+		derp.Report(derp.Internal(location, "Nil session passed to Delete (this should never happen)", signature))
+
+	} else if spool := From(session.Context()); spool != nil {
+		// Transactional context: spool for post-commit deletion.
+		spool.AddDelete(signature)
+		return
+	}
+
+	if q == nil {
+		return
+	}
+
+	// Non-transactional context: delete immediately.
+	if err := q.Delete(signature); err != nil {
+		derp.Report(derp.Wrap(err, location, "Deleting task", signature))
+	}
+}
+
 // WithTransaction wraps server.WithTransaction with the post-commit spool lifecycle: a
 // fresh spool rides the transaction's context, is reset on every callback attempt, is
 // dropped on rollback, and is published FIFO to the queue after a successful commit.
@@ -136,8 +182,15 @@ func WithTransaction(ctx context.Context, server data.Server, q *queue.Queue, ca
 		return result, err
 	}
 
-	// COMMIT: publish the spool in FIFO order.
+	// COMMIT: apply spooled deletions first, so that a task can be replaced by
+	// a new one with the same signature, then publish the spool in FIFO order.
 	if q != nil {
+		for _, signature := range spool.DrainDeletes() {
+			if deleteError := q.Delete(signature); deleteError != nil {
+				derp.Report(derp.Wrap(deleteError, location, "Deleting post-commit task", signature))
+			}
+		}
+
 		for _, task := range spool.Drain() {
 			if publishError := q.Publish(task); publishError != nil {
 				derp.Report(derp.Wrap(publishError, location, "Publishing post-commit task", task.Name))
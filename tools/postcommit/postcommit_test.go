@@ -129,6 +129,15 @@ func TestTasks_Reset(t *testing.T) {
 	require.Empty(t, spool.Drain())
 }
 
+func TestTasks_ResetDeletes(t *testing.T) {
+
+	spool := postcommit.NewTasks()
+	spool.AddDelete("SIGNATURE")
+	spool.Reset()
+
+	require.Empty(t, spool.DrainDeletes())
+}
+
 func TestTasks_ConcurrentAdd(t *testing.T) {
 
 	spool := postcommit.NewTasks()
@@ -191,6 +200,25 @@ func TestPublish_NilQueueOutsideTransaction(t *testing.T) {
 	postcommit.Publish(session, nil, "dropped-task", mapof.Any{})
 }
 
+func TestDelete_SpoolsInsideTransaction(t *testing.T) {
+
+	spool := postcommit.NewTasks()
+	session := testSession{ctx: postcommit.WithContext(context.Background(), spool)}
+
+	postcommit.Delete(session, nil, "SIGNATURE")
+
+	// The deletion sits in the spool awaiting the commit
+	require.Equal(t, []string{"SIGNATURE"}, spool.DrainDeletes())
+	require.Empty(t, spool.DrainDeletes())
+}
+
+func TestDelete_NilQueueOutsideTransaction(t *testing.T) {
+
+	// Must not panic (preserves the no-op-safe behavior of nil-queue call sites)
+	session := testSession{ctx: context.Background()}
+	postcommit.Delete(session, nil, "SIGNATURE")
+}
+
 /******************************************
  * WithTransaction
  ******************************************/
@@ -0,0 +1,124 @@
+// Package totp implements the Time-based One-Time Password algorithm (RFC 6238)
+// using the defaults that every authenticator app supports: HMAC-SHA1, six digits,
+// and a thirty second time step.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/benpate/derp"
+)
+
+// Period is the number of seconds that each code is valid for
+const Period = 30
+
+// Digits is the number of digits in each code
+const Digits = 6
+
+// Skew is the number of time steps before and after the current time that are
+// still accepted, to allow for clock drift between the server and the device.
+const Skew = 1
+
+// secretSize is the number of random bytes in a new secret (160 bits, per RFC 4226)
+const secretSize = 20
+
+// encoding is the unpadded base32 alphabet that authenticator apps expect
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a new, random, base32 encoded secret
+func GenerateSecret() (string, error) {
+
+	secret := make([]byte, secretSize)
+
+	if _, err := rand.Read(secret); err != nil {
+		return "", derp.Wrap(err, "totp.GenerateSecret", "Generating random bytes")
+	}
+
+	return encoding.EncodeToString(secret), nil
+}
+
+// Counter returns the time step that contains the provided time
+func Counter(now time.Time) int64 {
+	return now.Unix() / Period
+}
+
+// Code returns the one-time code for a base32 encoded secret at the provided time step
+func Code(secret string, counter int64) (string, error) {
+
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+
+	if err != nil {
+		return "", derp.Wrap(err, "totp.Code", "Decoding secret")
+	}
+
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation (RFC 4226, section 5.3)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", Digits, value%1_000_000), nil
+}
+
+// Validate returns the time step that matches the provided code, and TRUE if the code
+// is valid at the provided time.  Codes from time steps at or before lastCounter are
+// rejected, so that each code can only be used once.
+func Validate(secret string, code string, now time.Time, lastCounter int64) (int64, bool) {
+
+	code = strings.TrimSpace(code)
+
+	if len(code) != Digits {
+		return 0, false
+	}
+
+	current := Counter(now)
+
+	for counter := current - Skew; counter <= current+Skew; counter++ {
+
+		// RULE: Do not allow codes to be replayed
+		if counter <= lastCounter {
+			continue
+		}
+
+		expected, err := Code(secret, counter)
+
+		if err != nil {
+			return 0, false
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return counter, true
+		}
+	}
+
+	return 0, false
+}
+
+// URL returns the "otpauth://" URL that authenticator apps read from a QR code
+func URL(issuer string, account string, secret string) string {
+
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(Digits))
+	query.Set("period", fmt.Sprint(Period))
+
+	label := url.PathEscape(issuer + ":" + account)
+
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
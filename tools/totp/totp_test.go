@@ -0,0 +1,81 @@
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA1 secret from RFC 6238, Appendix B
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode_RFC6238(t *testing.T) {
+
+	// Test vectors from RFC 6238, truncated to six digits
+	table := []struct {
+		unix     int64
+		expected string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, row := range table {
+		code, err := Code(rfcSecret, Counter(time.Unix(row.unix, 0)))
+		require.Nil(t, err)
+		require.Equal(t, row.expected, code, row.unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+
+	now := time.Unix(59, 0)
+
+	counter, ok := Validate(rfcSecret, "287082", now, 0)
+	require.True(t, ok)
+	require.Equal(t, int64(1), counter)
+
+	// Clock drift of one step is allowed
+	_, ok = Validate(rfcSecret, "287082", now.Add(Period*time.Second), 0)
+	require.True(t, ok)
+
+	// Wrong codes are rejected
+	_, ok = Validate(rfcSecret, "123456", now, 0)
+	require.False(t, ok)
+
+	_, ok = Validate(rfcSecret, "", now, 0)
+	require.False(t, ok)
+}
+
+func TestValidate_Replay(t *testing.T) {
+
+	now := time.Unix(59, 0)
+
+	counter, ok := Validate(rfcSecret, "287082", now, 0)
+	require.True(t, ok)
+
+	// The same code cannot be used twice
+	_, ok = Validate(rfcSecret, "287082", now, counter)
+	require.False(t, ok)
+}
+
+func TestGenerateSecret(t *testing.T) {
+
+	secret, err := GenerateSecret()
+	require.Nil(t, err)
+	require.Len(t, secret, 32)
+
+	other, err := GenerateSecret()
+	require.Nil(t, err)
+	require.NotEqual(t, secret, other)
+}
+
+func TestURL(t *testing.T) {
+	result := URL("Example Site", "alice@example.com", "ABCDEFGH")
+	require.Equal(t, "otpauth://totp/Example%20Site:alice@example.com?algorithm=SHA1&digits=6&issuer=Example+Site&period=30&secret=ABCDEFGH", result)
+}
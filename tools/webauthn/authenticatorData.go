@@ -0,0 +1,80 @@
+package webauthn
+
+import (
+	"encoding/binary"
+
+	"github.com/benpate/derp"
+)
+
+// Authenticator data flags (https://www.w3.org/TR/webauthn-3/#authdata-flags)
+const (
+	FlagUserPresent       byte = 0x01
+	FlagUserVerified      byte = 0x04
+	FlagBackupEligible    byte = 0x08
+	FlagBackupState       byte = 0x10
+	FlagAttestedData      byte = 0x40
+	FlagExtensionIncluded byte = 0x80
+)
+
+// authenticatorData is the parsed form of the authenticator data that is signed by every
+// registration and assertion.
+type authenticatorData struct {
+	RPIDHash     []byte // SHA-256 hash of the Relying Party ID that the authenticator used
+	Flags        byte   // Bit flags (user present, user verified, etc)
+	SignCount    uint32 // Signature counter, or zero if the authenticator does not keep one
+	CredentialID []byte // Credential ID (registration only)
+	PublicKey    []byte // COSE encoded credential public key (registration only)
+}
+
+// parseAuthenticatorData decodes the binary authenticator data structure
+func parseAuthenticatorData(data []byte) (authenticatorData, error) {
+
+	const location = "webauthn.parseAuthenticatorData"
+
+	// rpIdHash (32) + flags (1) + signCount (4)
+	if len(data) < 37 {
+		return authenticatorData{}, derp.BadRequest(location, "Authenticator data is too short")
+	}
+
+	result := authenticatorData{
+		RPIDHash:  data[:32],
+		Flags:     data[32],
+		SignCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+
+	if !result.HasFlag(FlagAttestedData) {
+		return result, nil
+	}
+
+	// aaguid (16) + credentialIdLength (2)
+	rest := data[37:]
+
+	if len(rest) < 18 {
+		return authenticatorData{}, derp.BadRequest(location, "Attested credential data is too short")
+	}
+
+	length := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+
+	if (length == 0) || (length > 1023) || (length > len(rest)) {
+		return authenticatorData{}, derp.BadRequest(location, "Invalid credential ID length", length)
+	}
+
+	result.CredentialID = rest[:length]
+	rest = rest[length:]
+
+	// The public key is a CBOR value of unknown length.  Decode it to find where it ends.
+	_, remainder, err := decodeCBOR(rest)
+
+	if err != nil {
+		return authenticatorData{}, derp.Wrap(err, location, "Decoding credential public key")
+	}
+
+	result.PublicKey = rest[:len(rest)-len(remainder)]
+	return result, nil
+}
+
+// HasFlag returns TRUE if the provided flag is set
+func (data authenticatorData) HasFlag(flag byte) bool {
+	return data.Flags&flag == flag
+}
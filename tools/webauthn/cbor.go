@@ -0,0 +1,211 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/benpate/derp"
+)
+
+// maxCBORDepth limits how deeply nested a CBOR value can be, so that hostile input
+// cannot exhaust the stack.
+const maxCBORDepth = 16
+
+// decodeCBOR decodes the first CBOR (RFC 8949) value in the provided bytes, and returns
+// the value along with any bytes that follow it.  Only the subset of CBOR that WebAuthn
+// uses is supported: integers, byte and text strings, arrays, maps, tags, and simple values.
+// Indefinite-length items are rejected, because authenticators must not use them.
+//
+// Values are returned as int64, []byte, string, []any, map[any]any, bool, float64, or nil.
+func decodeCBOR(data []byte) (any, []byte, error) {
+	return decodeCBORValue(data, 0)
+}
+
+func decodeCBORValue(data []byte, depth int) (any, []byte, error) {
+
+	const location = "webauthn.decodeCBOR"
+
+	if depth > maxCBORDepth {
+		return nil, nil, derp.BadRequest(location, "CBOR value is nested too deeply")
+	}
+
+	if len(data) == 0 {
+		return nil, nil, derp.BadRequest(location, "Unexpected end of CBOR data")
+	}
+
+	majorType := data[0] >> 5
+	info := data[0] & 0x1f
+
+	// Simple values and floats use the additional info directly
+	if majorType == 7 {
+		return decodeCBORSimple(data, info)
+	}
+
+	argument, rest, err := decodeCBORArgument(data, info)
+
+	if err != nil {
+		return nil, nil, derp.Wrap(err, location, "Reading CBOR argument")
+	}
+
+	switch majorType {
+
+	// Unsigned integer
+	case 0:
+		if argument > math.MaxInt64 {
+			return nil, nil, derp.BadRequest(location, "CBOR integer is out of range")
+		}
+		return int64(argument), rest, nil
+
+	// Negative integer
+	case 1:
+		if argument > math.MaxInt64 {
+			return nil, nil, derp.BadRequest(location, "CBOR integer is out of range")
+		}
+		return -1 - int64(argument), rest, nil
+
+	// Byte string
+	case 2:
+		if argument > uint64(len(rest)) {
+			return nil, nil, derp.BadRequest(location, "CBOR byte string is truncated")
+		}
+		return rest[:argument], rest[argument:], nil
+
+	// Text string
+	case 3:
+		if argument > uint64(len(rest)) {
+			return nil, nil, derp.BadRequest(location, "CBOR text string is truncated")
+		}
+		return string(rest[:argument]), rest[argument:], nil
+
+	// Array
+	case 4:
+		if argument > uint64(len(rest)) {
+			return nil, nil, derp.BadRequest(location, "CBOR array is truncated")
+		}
+
+		result := make([]any, argument)
+
+		for index := range result {
+			result[index], rest, err = decodeCBORValue(rest, depth+1)
+
+			if err != nil {
+				return nil, nil, derp.Wrap(err, location, "Reading array item")
+			}
+		}
+
+		return result, rest, nil
+
+	// Map
+	case 5:
+		if argument > uint64(len(rest)) {
+			return nil, nil, derp.BadRequest(location, "CBOR map is truncated")
+		}
+
+		result := make(map[any]any, argument)
+
+		for range argument {
+
+			var key any
+			var value any
+
+			key, rest, err = decodeCBORValue(rest, depth+1)
+
+			if err != nil {
+				return nil, nil, derp.Wrap(err, location, "Reading map key")
+			}
+
+			// RULE: Map keys must be comparable.  WebAuthn only uses integer and text keys.
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, nil, derp.BadRequest(location, "CBOR map key must be an integer or text")
+			}
+
+			value, rest, err = decodeCBORValue(rest, depth+1)
+
+			if err != nil {
+				return nil, nil, derp.Wrap(err, location, "Reading map value")
+			}
+
+			result[key] = value
+		}
+
+		return result, rest, nil
+
+	// Tag (ignored, the tagged value is returned as-is)
+	case 6:
+		return decodeCBORValue(rest, depth+1)
+	}
+
+	return nil, nil, derp.BadRequest(location, "Unsupported CBOR type", majorType)
+}
+
+// decodeCBORArgument reads the argument that follows the initial byte of a CBOR item
+func decodeCBORArgument(data []byte, info byte) (uint64, []byte, error) {
+
+	const location = "webauthn.decodeCBORArgument"
+
+	switch {
+
+	case info < 24:
+		return uint64(info), data[1:], nil
+
+	case info == 24:
+		if len(data) < 2 {
+			return 0, nil, derp.BadRequest(location, "CBOR argument is truncated")
+		}
+		return uint64(data[1]), data[2:], nil
+
+	case info == 25:
+		if len(data) < 3 {
+			return 0, nil, derp.BadRequest(location, "CBOR argument is truncated")
+		}
+		return uint64(binary.BigEndian.Uint16(data[1:])), data[3:], nil
+
+	case info == 26:
+		if len(data) < 5 {
+			return 0, nil, derp.BadRequest(location, "CBOR argument is truncated")
+		}
+		return uint64(binary.BigEndian.Uint32(data[1:])), data[5:], nil
+
+	case info == 27:
+		if len(data) < 9 {
+			return 0, nil, derp.BadRequest(location, "CBOR argument is truncated")
+		}
+		return binary.BigEndian.Uint64(data[1:]), data[9:], nil
+	}
+
+	return 0, nil, derp.BadRequest(location, "Indefinite-length CBOR items are not supported")
+}
+
+// decodeCBORSimple decodes the simple values (false, true, null, undefined) and floats
+func decodeCBORSimple(data []byte, info byte) (any, []byte, error) {
+
+	const location = "webauthn.decodeCBORSimple"
+
+	switch info {
+
+	case 20:
+		return false, data[1:], nil
+
+	case 21:
+		return true, data[1:], nil
+
+	case 22, 23:
+		return nil, data[1:], nil
+
+	case 26:
+		if len(data) < 5 {
+			return nil, nil, derp.BadRequest(location, "CBOR float is truncated")
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(data[1:]))), data[5:], nil
+
+	case 27:
+		if len(data) < 9 {
+			return nil, nil, derp.BadRequest(location, "CBOR float is truncated")
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(data[1:])), data[9:], nil
+	}
+
+	return nil, nil, derp.BadRequest(location, "Unsupported CBOR simple value", info)
+}
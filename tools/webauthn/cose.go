@@ -0,0 +1,142 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/benpate/derp"
+)
+
+// COSE algorithm identifiers (https://www.iana.org/assignments/cose/cose.xhtml)
+const (
+	AlgorithmES256 = -7   // ECDSA with SHA-256 on the P-256 curve
+	AlgorithmEdDSA = -8   // EdDSA on the Ed25519 curve
+	AlgorithmRS256 = -257 // RSASSA-PKCS1-v1_5 with SHA-256
+)
+
+// SupportedAlgorithms lists the COSE algorithms that this package can verify, in order of preference
+var SupportedAlgorithms = []int{AlgorithmES256, AlgorithmEdDSA, AlgorithmRS256}
+
+// COSE key parameters (RFC 9052, RFC 9053)
+const (
+	coseKeyType   = 1
+	coseAlgorithm = 3
+	coseCurve     = -1 // also "n" for RSA keys
+	coseX         = -2 // also "e" for RSA keys
+	coseY         = -3
+
+	coseKeyTypeOKP = 1
+	coseKeyTypeEC2 = 2
+	coseKeyTypeRSA = 3
+
+	coseCurveP256    = 1
+	coseCurveEd25519 = 6
+)
+
+// publicKey is a credential public key, decoded from its COSE representation
+type publicKey struct {
+	Algorithm int64
+	Key       crypto.PublicKey
+}
+
+// parsePublicKey decodes a COSE_Key into a public key that can verify signatures
+func parsePublicKey(coseKey []byte) (publicKey, error) {
+
+	const location = "webauthn.parsePublicKey"
+
+	value, _, err := decodeCBOR(coseKey)
+
+	if err != nil {
+		return publicKey{}, derp.Wrap(err, location, "Decoding COSE key")
+	}
+
+	params, ok := value.(map[any]any)
+
+	if !ok {
+		return publicKey{}, derp.BadRequest(location, "COSE key must be a map")
+	}
+
+	keyType, _ := params[int64(coseKeyType)].(int64)
+	algorithm, _ := params[int64(coseAlgorithm)].(int64)
+
+	switch {
+
+	case keyType == coseKeyTypeEC2 && algorithm == AlgorithmES256:
+
+		curve, _ := params[int64(coseCurve)].(int64)
+		x, _ := params[int64(coseX)].([]byte)
+		y, _ := params[int64(coseY)].([]byte)
+
+		if curve != coseCurveP256 || len(x) != 32 || len(y) != 32 {
+			return publicKey{}, derp.BadRequest(location, "Invalid P-256 key")
+		}
+
+		// Parsing the uncompressed point also verifies that it is on the curve
+		key, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), append(append([]byte{4}, x...), y...))
+
+		if err != nil {
+			return publicKey{}, derp.Wrap(err, location, "Invalid P-256 point")
+		}
+
+		return publicKey{Algorithm: algorithm, Key: key}, nil
+
+	case keyType == coseKeyTypeOKP && algorithm == AlgorithmEdDSA:
+
+		curve, _ := params[int64(coseCurve)].(int64)
+		x, _ := params[int64(coseX)].([]byte)
+
+		if curve != coseCurveEd25519 || len(x) != ed25519.PublicKeySize {
+			return publicKey{}, derp.BadRequest(location, "Invalid Ed25519 key")
+		}
+
+		return publicKey{Algorithm: algorithm, Key: ed25519.PublicKey(x)}, nil
+
+	case keyType == coseKeyTypeRSA && algorithm == AlgorithmRS256:
+
+		n, _ := params[int64(coseCurve)].([]byte)
+		e, _ := params[int64(coseX)].([]byte)
+
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return publicKey{}, derp.BadRequest(location, "Invalid RSA key")
+		}
+
+		exponent := int(new(big.Int).SetBytes(e).Int64())
+
+		return publicKey{Algorithm: algorithm, Key: &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent}}, nil
+	}
+
+	return publicKey{}, derp.BadRequest(location, "Unsupported COSE key", keyType, algorithm)
+}
+
+// Verify returns an error if the signature is not valid for the provided data
+func (key publicKey) Verify(data []byte, signature []byte) error {
+
+	const location = "webauthn.publicKey.Verify"
+
+	switch typed := key.Key.(type) {
+
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		if ecdsa.VerifyASN1(typed, digest[:], signature) {
+			return nil
+		}
+
+	case ed25519.PublicKey:
+		if ed25519.Verify(typed, data, signature) {
+			return nil
+		}
+
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(data)
+		if rsa.VerifyPKCS1v15(typed, crypto.SHA256, digest[:], signature) == nil {
+			return nil
+		}
+	}
+
+	return derp.Unauthorized(location, "Invalid signature")
+}
@@ -0,0 +1,115 @@
+package webauthn
+
+import (
+	"encoding/json"
+
+	"github.com/benpate/derp"
+)
+
+// credentialJSON is the JSON form of a PublicKeyCredential, as returned by
+// PublicKeyCredential.toJSON().  Binary values are base64url encoded.
+type credentialJSON struct {
+	ID       string `json:"id"`
+	RawID    string `json:"rawId"`
+	Type     string `json:"type"`
+	Response struct {
+		ClientDataJSON    string   `json:"clientDataJSON"`
+		AttestationObject string   `json:"attestationObject"`
+		Transports        []string `json:"transports"`
+		AuthenticatorData string   `json:"authenticatorData"`
+		Signature         string   `json:"signature"`
+		UserHandle        string   `json:"userHandle"`
+	} `json:"response"`
+}
+
+// Registration is the response from an authenticator during a registration ceremony.
+// Values are the raw bytes, decoded from the browser's base64url encoding.
+type Registration struct {
+	ClientDataJSON    []byte
+	AttestationObject []byte
+	Transports        []string
+}
+
+// ParseRegistration decodes the JSON form of a newly created PublicKeyCredential
+func ParseRegistration(value json.RawMessage) (Registration, error) {
+
+	const location = "webauthn.ParseRegistration"
+
+	credential, err := parseCredentialJSON(value)
+
+	if err != nil {
+		return Registration{}, derp.Wrap(err, location, "Parsing credential")
+	}
+
+	result := Registration{
+		Transports: credential.Response.Transports,
+	}
+
+	if result.ClientDataJSON, err = DecodeID(credential.Response.ClientDataJSON); err != nil {
+		return Registration{}, derp.Wrap(err, location, "Decoding clientDataJSON", derp.WithBadRequest())
+	}
+
+	if result.AttestationObject, err = DecodeID(credential.Response.AttestationObject); err != nil {
+		return Registration{}, derp.Wrap(err, location, "Decoding attestationObject", derp.WithBadRequest())
+	}
+
+	return result, nil
+}
+
+// ParseAssertion decodes the JSON form of a PublicKeyCredential that was returned to sign in
+func ParseAssertion(value json.RawMessage) (Assertion, error) {
+
+	const location = "webauthn.ParseAssertion"
+
+	credential, err := parseCredentialJSON(value)
+
+	if err != nil {
+		return Assertion{}, derp.Wrap(err, location, "Parsing credential")
+	}
+
+	result := Assertion{}
+
+	if result.CredentialID, err = DecodeID(credential.RawID); err != nil {
+		return Assertion{}, derp.Wrap(err, location, "Decoding rawId", derp.WithBadRequest())
+	}
+
+	if result.ClientDataJSON, err = DecodeID(credential.Response.ClientDataJSON); err != nil {
+		return Assertion{}, derp.Wrap(err, location, "Decoding clientDataJSON", derp.WithBadRequest())
+	}
+
+	if result.AuthenticatorData, err = DecodeID(credential.Response.AuthenticatorData); err != nil {
+		return Assertion{}, derp.Wrap(err, location, "Decoding authenticatorData", derp.WithBadRequest())
+	}
+
+	if result.Signature, err = DecodeID(credential.Response.Signature); err != nil {
+		return Assertion{}, derp.Wrap(err, location, "Decoding signature", derp.WithBadRequest())
+	}
+
+	if result.UserHandle, err = DecodeID(credential.Response.UserHandle); err != nil {
+		return Assertion{}, derp.Wrap(err, location, "Decoding userHandle", derp.WithBadRequest())
+	}
+
+	return result, nil
+}
+
+// parseCredentialJSON decodes and checks the common fields of a PublicKeyCredential
+func parseCredentialJSON(value json.RawMessage) (credentialJSON, error) {
+
+	const location = "webauthn.parseCredentialJSON"
+
+	result := credentialJSON{}
+
+	if err := json.Unmarshal(value, &result); err != nil {
+		return credentialJSON{}, derp.Wrap(err, location, "Decoding JSON", derp.WithBadRequest())
+	}
+
+	if result.Type != "public-key" {
+		return credentialJSON{}, derp.BadRequest(location, "Credential type must be public-key", result.Type)
+	}
+
+	if result.ID != result.RawID {
+		return credentialJSON{}, derp.BadRequest(location, "Credential id and rawId must match")
+	}
+
+	return result, nil
+}
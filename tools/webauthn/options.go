@@ -0,0 +1,118 @@
+package webauthn
+
+// TimeoutMilliseconds is how long the browser waits for the User to respond to their authenticator
+const TimeoutMilliseconds = 120_000
+
+// CreationOptions is the JSON form of the PublicKeyCredentialCreationOptions that the
+// browser passes to navigator.credentials.create().  Binary values are base64url encoded,
+// matching PublicKeyCredential.parseCreationOptionsFromJSON().
+type CreationOptions struct {
+	Challenge              string                 `json:"challenge"`
+	RP                     rpEntity               `json:"rp"`
+	User                   userEntity             `json:"user"`
+	PubKeyCredParams       []credentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int                    `json:"timeout"`
+	ExcludeCredentials     []credentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection authenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+// RequestOptions is the JSON form of the PublicKeyCredentialRequestOptions that the
+// browser passes to navigator.credentials.get().  Binary values are base64url encoded,
+// matching PublicKeyCredential.parseRequestOptionsFromJSON().
+type RequestOptions struct {
+	Challenge        string                 `json:"challenge"`
+	RPID             string                 `json:"rpId"`
+	Timeout          int                    `json:"timeout"`
+	AllowCredentials []credentialDescriptor `json:"allowCredentials"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+type rpEntity struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type userEntity struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type credentialParameter struct {
+	Type      string `json:"type"`
+	Algorithm int    `json:"alg"`
+}
+
+type credentialDescriptor struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+type authenticatorSelection struct {
+	ResidentKey      string `json:"residentKey"`
+	UserVerification string `json:"userVerification"`
+}
+
+// CreationOptions returns the options for registering a new credential.  The userHandle
+// is an opaque identifier that authenticators return during passwordless signin, and
+// existing credentials are excluded so that an authenticator is not registered twice.
+func (rp RelyingParty) CreationOptions(challenge string, userHandle []byte, name string, displayName string, existing []Credential) CreationOptions {
+
+	result := CreationOptions{
+		Challenge: challenge,
+		RP: rpEntity{
+			ID:   rp.ID,
+			Name: rp.Name,
+		},
+		User: userEntity{
+			ID:          EncodeID(userHandle),
+			Name:        name,
+			DisplayName: displayName,
+		},
+		PubKeyCredParams:   make([]credentialParameter, len(SupportedAlgorithms)),
+		Timeout:            TimeoutMilliseconds,
+		ExcludeCredentials: descriptors(existing),
+		AuthenticatorSelection: authenticatorSelection{
+			ResidentKey:      "required",
+			UserVerification: "required",
+		},
+		Attestation: "none",
+	}
+
+	for index, algorithm := range SupportedAlgorithms {
+		result.PubKeyCredParams[index] = credentialParameter{Type: "public-key", Algorithm: algorithm}
+	}
+
+	return result
+}
+
+// RequestOptions returns the options for signing in with an existing credential.  If no
+// credentials are provided, then the browser offers every passkey that it has for this site.
+func (rp RelyingParty) RequestOptions(challenge string, allowed []Credential, userVerification string) RequestOptions {
+
+	return RequestOptions{
+		Challenge:        challenge,
+		RPID:             rp.ID,
+		Timeout:          TimeoutMilliseconds,
+		AllowCredentials: descriptors(allowed),
+		UserVerification: userVerification,
+	}
+}
+
+// descriptors returns the JSON descriptors for a list of credentials
+func descriptors(credentials []Credential) []credentialDescriptor {
+
+	result := make([]credentialDescriptor, len(credentials))
+
+	for index, credential := range credentials {
+		result[index] = credentialDescriptor{
+			Type:       "public-key",
+			ID:         EncodeID(credential.ID),
+			Transports: credential.Transports,
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,243 @@
+// Package webauthn implements the server side of the Web Authentication (WebAuthn)
+// registration and authentication ceremonies, which let people sign in with passkeys.
+//
+// Attestation statements are not verified: like most websites, we trust the browser
+// about which kind of authenticator was used, and rely only on the credential's public
+// key.  Supported algorithms are ES256, EdDSA, and RS256.
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/benpate/derp"
+)
+
+// challengeSize is the number of random bytes in each challenge
+const challengeSize = 32
+
+// encoding is the unpadded base64url alphabet that WebAuthn uses in JSON
+var encoding = base64.RawURLEncoding
+
+// RelyingParty identifies the website that credentials are registered with
+type RelyingParty struct {
+	ID     string // Domain name that credentials are scoped to (e.g. "example.com")
+	Name   string // Human-friendly name shown by the authenticator
+	Origin string // Origin that all ceremonies must come from (e.g. "https://example.com")
+}
+
+// Credential is a public key credential that was registered by an authenticator
+type Credential struct {
+	ID             []byte   // Credential ID chosen by the authenticator
+	PublicKey      []byte   // COSE encoded public key
+	SignCount      uint32   // Most recent signature counter
+	Transports     []string // Hints for how the browser can reach the authenticator (usb, nfc, internal, etc)
+	BackupEligible bool     // TRUE if the credential can be synced between devices (a "passkey")
+}
+
+// Assertion is the response from an authenticator during an authentication ceremony.
+// All values are the raw bytes, decoded from the browser's base64url encoding.
+type Assertion struct {
+	CredentialID      []byte
+	ClientDataJSON    []byte
+	AuthenticatorData []byte
+	Signature         []byte
+	UserHandle        []byte
+}
+
+// clientData is the JSON object that the browser signs for each ceremony
+type clientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+// NewChallenge returns a new random, base64url encoded challenge
+func NewChallenge() (string, error) {
+
+	challenge := make([]byte, challengeSize)
+
+	if _, err := rand.Read(challenge); err != nil {
+		return "", derp.Wrap(err, "webauthn.NewChallenge", "Generating random bytes")
+	}
+
+	return encoding.EncodeToString(challenge), nil
+}
+
+// EncodeID returns the base64url encoding of a credential ID or user handle
+func EncodeID(value []byte) string {
+	return encoding.EncodeToString(value)
+}
+
+// DecodeID decodes a base64url encoded credential ID or user handle
+func DecodeID(value string) ([]byte, error) {
+
+	result, err := encoding.DecodeString(value)
+
+	if err != nil {
+		return nil, derp.Wrap(err, "webauthn.DecodeID", "Decoding value", value)
+	}
+
+	return result, nil
+}
+
+// VerifyRegistration validates a new credential that was created in response to the
+// provided challenge, and returns the credential to be stored.
+func (rp RelyingParty) VerifyRegistration(challenge string, clientDataJSON []byte, attestationObject []byte, requireUserVerification bool) (Credential, error) {
+
+	const location = "webauthn.VerifyRegistration"
+
+	if err := rp.verifyClientData(clientDataJSON, "webauthn.create", challenge); err != nil {
+		return Credential{}, derp.Wrap(err, location, "Invalid client data")
+	}
+
+	// Decode the attestation object.  Only the authenticator data is used (see package comment).
+	value, _, err := decodeCBOR(attestationObject)
+
+	if err != nil {
+		return Credential{}, derp.Wrap(err, location, "Decoding attestation object")
+	}
+
+	attestation, ok := value.(map[any]any)
+
+	if !ok {
+		return Credential{}, derp.BadRequest(location, "Attestation object must be a map")
+	}
+
+	rawAuthData, ok := attestation["authData"].([]byte)
+
+	if !ok {
+		return Credential{}, derp.BadRequest(location, "Attestation object is missing authenticator data")
+	}
+
+	authData, err := rp.verifyAuthenticatorData(rawAuthData, requireUserVerification)
+
+	if err != nil {
+		return Credential{}, derp.Wrap(err, location, "Invalid authenticator data")
+	}
+
+	if !authData.HasFlag(FlagAttestedData) {
+		return Credential{}, derp.BadRequest(location, "Authenticator data does not include a credential")
+	}
+
+	// RULE: The public key must use an algorithm that we can verify later
+	if _, err := parsePublicKey(authData.PublicKey); err != nil {
+		return Credential{}, derp.Wrap(err, location, "Invalid credential public key")
+	}
+
+	result := Credential{
+		ID:             bytes.Clone(authData.CredentialID),
+		PublicKey:      bytes.Clone(authData.PublicKey),
+		SignCount:      authData.SignCount,
+		BackupEligible: authData.HasFlag(FlagBackupEligible),
+	}
+
+	return result, nil
+}
+
+// VerifyAssertion validates an authenticator's response to the provided challenge, and
+// returns the credential's new signature counter.
+func (rp RelyingParty) VerifyAssertion(challenge string, credential Credential, assertion Assertion, requireUserVerification bool) (uint32, error) {
+
+	const location = "webauthn.VerifyAssertion"
+
+	// RULE: The assertion must come from the expected credential
+	if subtle.ConstantTimeCompare(credential.ID, assertion.CredentialID) != 1 {
+		return 0, derp.Unauthorized(location, "Credential ID does not match")
+	}
+
+	if err := rp.verifyClientData(assertion.ClientDataJSON, "webauthn.get", challenge); err != nil {
+		return 0, derp.Wrap(err, location, "Invalid client data")
+	}
+
+	authData, err := rp.verifyAuthenticatorData(assertion.AuthenticatorData, requireUserVerification)
+
+	if err != nil {
+		return 0, derp.Wrap(err, location, "Invalid authenticator data")
+	}
+
+	key, err := parsePublicKey(credential.PublicKey)
+
+	if err != nil {
+		return 0, derp.Wrap(err, location, "Invalid credential public key")
+	}
+
+	// The signature covers the authenticator data followed by the hash of the client data
+	clientDataHash := sha256.Sum256(assertion.ClientDataJSON)
+	signedData := append(bytes.Clone(assertion.AuthenticatorData), clientDataHash[:]...)
+
+	if err := key.Verify(signedData, assertion.Signature); err != nil {
+		return 0, derp.Wrap(err, location, "Invalid signature")
+	}
+
+	// RULE: If the authenticator keeps a counter, then it must always increase.  A counter
+	// that goes backwards means that the credential may have been cloned.
+	if (authData.SignCount != 0 || credential.SignCount != 0) && (authData.SignCount <= credential.SignCount) {
+		return 0, derp.Unauthorized(location, "Signature counter did not increase. The authenticator may have been cloned.")
+	}
+
+	return authData.SignCount, nil
+}
+
+// verifyClientData validates the type, challenge, and origin of the browser's client data
+func (rp RelyingParty) verifyClientData(clientDataJSON []byte, ceremony string, challenge string) error {
+
+	const location = "webauthn.verifyClientData"
+
+	data := clientData{}
+
+	if err := json.Unmarshal(clientDataJSON, &data); err != nil {
+		return derp.Wrap(err, location, "Decoding client data", derp.WithBadRequest())
+	}
+
+	if data.Type != ceremony {
+		return derp.BadRequest(location, "Unexpected ceremony type", data.Type)
+	}
+
+	if (challenge == "") || (subtle.ConstantTimeCompare([]byte(data.Challenge), []byte(challenge)) != 1) {
+		return derp.Unauthorized(location, "Challenge does not match")
+	}
+
+	if data.Origin != rp.Origin {
+		return derp.Unauthorized(location, "Origin does not match", data.Origin)
+	}
+
+	if data.CrossOrigin {
+		return derp.Unauthorized(location, "Cross-origin ceremonies are not allowed")
+	}
+
+	return nil
+}
+
+// verifyAuthenticatorData validates the Relying Party ID hash and user flags
+func (rp RelyingParty) verifyAuthenticatorData(rawAuthData []byte, requireUserVerification bool) (authenticatorData, error) {
+
+	const location = "webauthn.verifyAuthenticatorData"
+
+	authData, err := parseAuthenticatorData(rawAuthData)
+
+	if err != nil {
+		return authenticatorData{}, derp.Wrap(err, location, "Parsing authenticator data")
+	}
+
+	rpIDHash := sha256.Sum256([]byte(rp.ID))
+
+	if subtle.ConstantTimeCompare(authData.RPIDHash, rpIDHash[:]) != 1 {
+		return authenticatorData{}, derp.Unauthorized(location, "Relying Party ID does not match")
+	}
+
+	if !authData.HasFlag(FlagUserPresent) {
+		return authenticatorData{}, derp.Unauthorized(location, "User was not present")
+	}
+
+	if requireUserVerification && !authData.HasFlag(FlagUserVerified) {
+		return authenticatorData{}, derp.Unauthorized(location, "User was not verified")
+	}
+
+	return authData, nil
+}
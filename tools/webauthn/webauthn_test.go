@@ -0,0 +1,410 @@
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testRP = RelyingParty{
+	ID:     "example.com",
+	Name:   "Example",
+	Origin: "https://example.com",
+}
+
+/******************************************
+ * Test Authenticator
+ ******************************************/
+
+// testAuthenticator simulates a browser + authenticator that holds a single credential
+type testAuthenticator struct {
+	credentialID []byte
+	ecdsaKey     *ecdsa.PrivateKey
+	ed25519Key   ed25519.PrivateKey
+	signCount    uint32
+	noCounter    bool
+	flags        byte
+}
+
+func newTestAuthenticator(t *testing.T) *testAuthenticator {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+
+	return &testAuthenticator{
+		credentialID: []byte("credential-id-1234"),
+		ecdsaKey:     key,
+		flags:        FlagUserPresent | FlagUserVerified | FlagBackupEligible,
+	}
+}
+
+func newTestEd25519Authenticator(t *testing.T) *testAuthenticator {
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+
+	return &testAuthenticator{
+		credentialID: []byte("credential-id-ed25519"),
+		ed25519Key:   key,
+		flags:        FlagUserPresent | FlagUserVerified,
+	}
+}
+
+func (a *testAuthenticator) coseKey(t *testing.T) []byte {
+
+	if a.ed25519Key != nil {
+		return cborMap(
+			cborInt(coseKeyType), cborInt(coseKeyTypeOKP),
+			cborInt(coseAlgorithm), cborInt(AlgorithmEdDSA),
+			cborInt(coseCurve), cborInt(coseCurveEd25519),
+			cborInt(coseX), cborBytes(a.ed25519Key.Public().(ed25519.PublicKey)),
+		)
+	}
+
+	point, err := a.ecdsaKey.PublicKey.Bytes()
+	require.Nil(t, err)
+
+	return cborMap(
+		cborInt(coseKeyType), cborInt(coseKeyTypeEC2),
+		cborInt(coseAlgorithm), cborInt(AlgorithmES256),
+		cborInt(coseCurve), cborInt(coseCurveP256),
+		cborInt(coseX), cborBytes(point[1:33]),
+		cborInt(coseY), cborBytes(point[33:65]),
+	)
+}
+
+func (a *testAuthenticator) authData(t *testing.T, rpID string, attested bool) []byte {
+
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	result := append([]byte{}, rpIDHash[:]...)
+
+	flags := a.flags
+
+	if attested {
+		flags = flags | FlagAttestedData
+	}
+
+	result = append(result, flags)
+	result = binary.BigEndian.AppendUint32(result, a.signCount)
+
+	if attested {
+		result = append(result, make([]byte, 16)...) // aaguid
+		result = binary.BigEndian.AppendUint16(result, uint16(len(a.credentialID)))
+		result = append(result, a.credentialID...)
+		result = append(result, a.coseKey(t)...)
+	}
+
+	return result
+}
+
+func (a *testAuthenticator) create(t *testing.T, challenge string, origin string) ([]byte, []byte) {
+
+	clientDataJSON := testClientData(t, "webauthn.create", challenge, origin)
+
+	attestationObject := cborMap(
+		cborText("fmt"), cborText("none"),
+		cborText("attStmt"), cborMap(),
+		cborText("authData"), cborBytes(a.authData(t, testRP.ID, true)),
+	)
+
+	return clientDataJSON, attestationObject
+}
+
+func (a *testAuthenticator) get(t *testing.T, challenge string, origin string) Assertion {
+
+	if !a.noCounter {
+		a.signCount++
+	}
+
+	clientDataJSON := testClientData(t, "webauthn.get", challenge, origin)
+	authData := a.authData(t, testRP.ID, false)
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signedData := append(append([]byte{}, authData...), clientDataHash[:]...)
+
+	var signature []byte
+
+	if a.ed25519Key != nil {
+		signature = ed25519.Sign(a.ed25519Key, signedData)
+	} else {
+		digest := sha256.Sum256(signedData)
+		var err error
+		signature, err = ecdsa.SignASN1(rand.Reader, a.ecdsaKey, digest[:])
+		require.Nil(t, err)
+	}
+
+	return Assertion{
+		CredentialID:      a.credentialID,
+		ClientDataJSON:    clientDataJSON,
+		AuthenticatorData: authData,
+		Signature:         signature,
+	}
+}
+
+func testClientData(t *testing.T, ceremony string, challenge string, origin string) []byte {
+
+	result, err := json.Marshal(clientData{
+		Type:      ceremony,
+		Challenge: challenge,
+		Origin:    origin,
+	})
+
+	require.Nil(t, err)
+	return result
+}
+
+/******************************************
+ * Tests
+ ******************************************/
+
+func TestRegistration(t *testing.T) {
+
+	authenticator := newTestAuthenticator(t)
+	challenge, err := NewChallenge()
+	require.Nil(t, err)
+
+	clientDataJSON, attestationObject := authenticator.create(t, challenge, testRP.Origin)
+
+	credential, err := testRP.VerifyRegistration(challenge, clientDataJSON, attestationObject, true)
+	require.Nil(t, err)
+	require.Equal(t, authenticator.credentialID, credential.ID)
+	require.Equal(t, authenticator.coseKey(t), credential.PublicKey)
+	require.True(t, credential.BackupEligible)
+}
+
+func TestRegistration_WrongChallenge(t *testing.T) {
+
+	authenticator := newTestAuthenticator(t)
+	clientDataJSON, attestationObject := authenticator.create(t, "some-other-challenge", testRP.Origin)
+
+	_, err := testRP.VerifyRegistration("expected-challenge", clientDataJSON, attestationObject, true)
+	require.NotNil(t, err)
+
+	// An empty challenge never matches
+	clientDataJSON, attestationObject = authenticator.create(t, "", testRP.Origin)
+	_, err = testRP.VerifyRegistration("", clientDataJSON, attestationObject, true)
+	require.NotNil(t, err)
+}
+
+func TestRegistration_WrongOrigin(t *testing.T) {
+
+	authenticator := newTestAuthenticator(t)
+	clientDataJSON, attestationObject := authenticator.create(t, "challenge", "https://evil.example")
+
+	_, err := testRP.VerifyRegistration("challenge", clientDataJSON, attestationObject, true)
+	require.NotNil(t, err)
+}
+
+func TestRegistration_WrongRPID(t *testing.T) {
+
+	authenticator := newTestAuthenticator(t)
+	clientDataJSON, attestationObject := authenticator.create(t, "challenge", testRP.Origin)
+
+	otherRP := testRP
+	otherRP.ID = "other.example"
+
+	_, err := otherRP.VerifyRegistration("challenge", clientDataJSON, attestationObject, true)
+	require.NotNil(t, err)
+}
+
+func TestRegistration_UserVerification(t *testing.T) {
+
+	authenticator := newTestAuthenticator(t)
+	authenticator.flags = FlagUserPresent
+
+	clientDataJSON, attestationObject := authenticator.create(t, "challenge", testRP.Origin)
+
+	_, err := testRP.VerifyRegistration("challenge", clientDataJSON, attestationObject, true)
+	require.NotNil(t, err)
+
+	_, err = testRP.VerifyRegistration("challenge", clientDataJSON, attestationObject, false)
+	require.Nil(t, err)
+}
+
+func TestAssertion(t *testing.T) {
+
+	for _, authenticator := range []*testAuthenticator{newTestAuthenticator(t), newTestEd25519Authenticator(t)} {
+
+		clientDataJSON, attestationObject := authenticator.create(t, "register", testRP.Origin)
+		credential, err := testRP.VerifyRegistration("register", clientDataJSON, attestationObject, true)
+		require.Nil(t, err)
+
+		assertion := authenticator.get(t, "signin", testRP.Origin)
+		signCount, err := testRP.VerifyAssertion("signin", credential, assertion, true)
+		require.Nil(t, err)
+		require.Equal(t, uint32(1), signCount)
+	}
+}
+
+func TestAssertion_Invalid(t *testing.T) {
+
+	authenticator := newTestAuthenticator(t)
+	clientDataJSON, attestationObject := authenticator.create(t, "register", testRP.Origin)
+	credential, err := testRP.VerifyRegistration("register", clientDataJSON, attestationObject, true)
+	require.Nil(t, err)
+
+	// Wrong challenge
+	assertion := authenticator.get(t, "signin", testRP.Origin)
+	_, err = testRP.VerifyAssertion("other", credential, assertion, true)
+	require.NotNil(t, err)
+
+	// Wrong ceremony type (a registration response replayed as a signin)
+	assertion = authenticator.get(t, "signin", testRP.Origin)
+	assertion.ClientDataJSON = testClientData(t, "webauthn.create", "signin", testRP.Origin)
+	_, err = testRP.VerifyAssertion("signin", credential, assertion, true)
+	require.NotNil(t, err)
+
+	// Tampered signature
+	assertion = authenticator.get(t, "signin", testRP.Origin)
+	assertion.Signature[len(assertion.Signature)-1] ^= 0xff
+	_, err = testRP.VerifyAssertion("signin", credential, assertion, true)
+	require.NotNil(t, err)
+
+	// Wrong credential
+	assertion = authenticator.get(t, "signin", testRP.Origin)
+	assertion.CredentialID = []byte("some-other-credential")
+	_, err = testRP.VerifyAssertion("signin", credential, assertion, true)
+	require.NotNil(t, err)
+}
+
+func TestAssertion_SignCount(t *testing.T) {
+
+	authenticator := newTestAuthenticator(t)
+	clientDataJSON, attestationObject := authenticator.create(t, "register", testRP.Origin)
+	credential, err := testRP.VerifyRegistration("register", clientDataJSON, attestationObject, true)
+	require.Nil(t, err)
+
+	assertion := authenticator.get(t, "signin", testRP.Origin)
+	credential.SignCount, err = testRP.VerifyAssertion("signin", credential, assertion, true)
+	require.Nil(t, err)
+
+	// A replayed (or cloned) counter is rejected
+	_, err = testRP.VerifyAssertion("signin", credential, assertion, true)
+	require.NotNil(t, err)
+
+	// Authenticators that do not keep a counter always send zero
+	authenticator = newTestEd25519Authenticator(t)
+	authenticator.noCounter = true
+	clientDataJSON, attestationObject = authenticator.create(t, "register", testRP.Origin)
+	credential, err = testRP.VerifyRegistration("register", clientDataJSON, attestationObject, true)
+	require.Nil(t, err)
+
+	for range 2 {
+		assertion = authenticator.get(t, "signin", testRP.Origin)
+		signCount, err := testRP.VerifyAssertion("signin", credential, assertion, true)
+		require.Nil(t, err)
+		require.Zero(t, signCount)
+	}
+}
+
+func TestCreationOptions(t *testing.T) {
+
+	existing := []Credential{{ID: []byte{1, 2, 3}, Transports: []string{"usb"}}}
+	options := testRP.CreationOptions("challenge", []byte{4, 5, 6}, "alice", "Alice", existing)
+
+	require.Equal(t, "challenge", options.Challenge)
+	require.Equal(t, "example.com", options.RP.ID)
+	require.Equal(t, "BAUG", options.User.ID)
+	require.Equal(t, "AQID", options.ExcludeCredentials[0].ID)
+	require.Equal(t, "required", options.AuthenticatorSelection.ResidentKey)
+	require.Len(t, options.PubKeyCredParams, len(SupportedAlgorithms))
+}
+
+/******************************************
+ * CBOR Encoding Helpers
+ ******************************************/
+
+func cborHeader(majorType byte, length uint64) []byte {
+
+	switch {
+	case length < 24:
+		return []byte{majorType<<5 | byte(length)}
+	case length < 256:
+		return []byte{majorType<<5 | 24, byte(length)}
+	default:
+		return binary.BigEndian.AppendUint16([]byte{majorType<<5 | 25}, uint16(length))
+	}
+}
+
+func cborInt(value int) []byte {
+	if value < 0 {
+		return cborHeader(1, uint64(-1-value))
+	}
+	return cborHeader(0, uint64(value))
+}
+
+func cborBytes(value []byte) []byte {
+	return append(cborHeader(2, uint64(len(value))), value...)
+}
+
+func cborText(value string) []byte {
+	return append(cborHeader(3, uint64(len(value))), value...)
+}
+
+func cborMap(pairs ...[]byte) []byte {
+
+	result := cborHeader(5, uint64(len(pairs)/2))
+
+	for _, item := range pairs {
+		result = append(result, item...)
+	}
+
+	return result
+}
+
+func TestParseAssertion(t *testing.T) {
+
+	value := []byte(`{
+		"id":"AQID",
+		"rawId":"AQID",
+		"type":"public-key",
+		"response":{
+			"clientDataJSON":"e30",
+			"authenticatorData":"BAUG",
+			"signature":"BwgJ",
+			"userHandle":""
+		}
+	}`)
+
+	assertion, err := ParseAssertion(value)
+	require.Nil(t, err)
+	require.Equal(t, []byte{1, 2, 3}, assertion.CredentialID)
+	require.Equal(t, []byte("{}"), assertion.ClientDataJSON)
+	require.Equal(t, []byte{4, 5, 6}, assertion.AuthenticatorData)
+	require.Equal(t, []byte{7, 8, 9}, assertion.Signature)
+	require.Empty(t, assertion.UserHandle)
+
+	// Other credential types are rejected
+	_, err = ParseAssertion([]byte(`{"id":"AQID","rawId":"AQID","type":"password"}`))
+	require.NotNil(t, err)
+
+	// Invalid base64url is rejected
+	_, err = ParseAssertion([]byte(`{"id":"AQID","rawId":"AQID","type":"public-key","response":{"clientDataJSON":"!!"}}`))
+	require.NotNil(t, err)
+}
+
+func TestParseRegistration(t *testing.T) {
+
+	value := []byte(`{
+		"id":"AQID",
+		"rawId":"AQID",
+		"type":"public-key",
+		"response":{
+			"clientDataJSON":"e30",
+			"attestationObject":"oA",
+			"transports":["usb","nfc"]
+		}
+	}`)
+
+	registration, err := ParseRegistration(value)
+	require.Nil(t, err)
+	require.Equal(t, []byte("{}"), registration.ClientDataJSON)
+	require.Equal(t, []byte{0xa0}, registration.AttestationObject)
+	require.Equal(t, []string{"usb", "nfc"}, registration.Transports)
+}